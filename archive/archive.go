@@ -0,0 +1,360 @@
+// Package archive 提供目录树的 tar.gz 和 zip 打包与解包，支持包含/排除规则、进度回调，解包时防止路径穿越。
+package archive
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// ErrUnsafePath 归档中的路径或符号链接指向解包目录之外
+var ErrUnsafePath = errors.New("archive: unsafe path")
+
+// ProgressFunc 进度回调，name 为当前条目，done 和 total 为累计字节数；
+// 打包和解包 zip 时为文件原始大小，解包 tar.gz 时为已读取的归档字节数
+type ProgressFunc func(name string, done, total int64)
+
+// options 打包解包选项
+type options struct {
+	include  []string
+	exclude  []string
+	progress ProgressFunc
+	prefix   string
+}
+
+// Option 选项
+type Option func(*options)
+
+// WithInclude 只处理匹配任一规则的文件，规则为 path.Match 语法，匹配相对路径或文件名
+func WithInclude(patterns ...string) Option {
+	return func(o *options) {
+		o.include = append(o.include, patterns...)
+	}
+}
+
+// WithExclude 跳过匹配任一规则的文件和目录，匹配目录时跳过整个目录
+func WithExclude(patterns ...string) Option {
+	return func(o *options) {
+		o.exclude = append(o.exclude, patterns...)
+	}
+}
+
+// WithProgress 设置进度回调
+func WithProgress(fn ProgressFunc) Option {
+	return func(o *options) {
+		o.progress = fn
+	}
+}
+
+// WithPrefix 打包时为归档内的路径增加目录前缀，如 app-1.0/
+func WithPrefix(prefix string) Option {
+	return func(o *options) {
+		o.prefix = strings.Trim(filepath.ToSlash(prefix), "/")
+	}
+}
+
+func newOptions(opts []Option) *options {
+	o := &options{}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+// matchAny 规则匹配相对路径、文件名或任一上级目录
+func matchAny(patterns []string, rel string) bool {
+	for _, p := range patterns {
+		for name := rel; name != "." && name != ""; name = path.Dir(name) {
+			if ok, _ := path.Match(p, name); ok {
+				return true
+			}
+			if ok, _ := path.Match(p, path.Base(name)); ok {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// excluded 判断条目是否被排除，目录只检查排除规则
+func (o *options) excluded(rel string, dir bool) bool {
+	if matchAny(o.exclude, rel) {
+		return true
+	}
+	return !dir && len(o.include) > 0 && !matchAny(o.include, rel)
+}
+
+// archiveName 返回带前缀的归档内路径
+func (o *options) archiveName(rel string) string {
+	if o.prefix == "" {
+		return rel
+	}
+	if rel == "." {
+		return o.prefix
+	}
+	return o.prefix + "/" + rel
+}
+
+// entry 待打包的条目
+type entry struct {
+	path string // 本地路径
+	rel  string // 相对源目录的路径，斜杠分隔
+	info fs.FileInfo
+	link string // 符号链接目标
+}
+
+// collect 遍历源目录或文件，返回待打包条目和普通文件总大小
+func collect(src string, o *options) ([]entry, int64, error) {
+	root, err := os.Lstat(src)
+	if err != nil {
+		return nil, 0, err
+	}
+	if !root.IsDir() {
+		e := entry{path: src, rel: filepath.Base(src), info: root}
+		if root.Mode()&fs.ModeSymlink != 0 {
+			if e.link, err = os.Readlink(src); err != nil {
+				return nil, 0, err
+			}
+		}
+		return []entry{e}, root.Size(), nil
+	}
+
+	var entries []entry
+	var total int64
+	err = filepath.WalkDir(src, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(src, p)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+		if rel == "." {
+			return nil
+		}
+		if o.excluded(rel, d.IsDir()) {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		// 设置了包含规则时不单独记录目录，由文件路径隐式创建
+		if d.IsDir() && len(o.include) > 0 {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		e := entry{path: p, rel: rel, info: info}
+		switch {
+		case info.Mode()&fs.ModeSymlink != 0:
+			if e.link, err = os.Readlink(p); err != nil {
+				return err
+			}
+		case info.Mode().IsRegular():
+			total += info.Size()
+		case !info.IsDir():
+			return nil // 跳过设备文件、管道等
+		}
+		entries = append(entries, e)
+		return nil
+	})
+	return entries, total, err
+}
+
+// extractor 解包目标目录。普通文件和目录通过 os.Root 创建，越出目标目录的访问由标准库拒绝；
+// 创建文件、目录和链接前逐级检查上级目录，上级路径中存在符号链接时返回 ErrUnsafePath，
+// 避免通过 d -> . 之类的链接串联出字面上位于目录内、实际位于目录外的路径
+type extractor struct {
+	dst  string
+	root *os.Root
+}
+
+func newExtractor(dst string) (*extractor, error) {
+	if err := os.MkdirAll(dst, 0o755); err != nil {
+		return nil, err
+	}
+	root, err := os.OpenRoot(dst)
+	if err != nil {
+		return nil, err
+	}
+	return &extractor{dst: dst, root: root}, nil
+}
+
+func (e *extractor) close() error {
+	return e.root.Close()
+}
+
+// clean 将归档内路径转换为相对目标目录的 slash 路径，绝对路径或以 .. 开头时返回 ErrUnsafePath
+func clean(name string) (string, error) {
+	name = strings.ReplaceAll(name, `\`, "/")
+	if path.IsAbs(name) || filepath.IsAbs(name) || filepath.VolumeName(name) != "" {
+		return "", fmt.Errorf("%w: %s", ErrUnsafePath, name)
+	}
+	rel := path.Clean(name)
+	if rel == ".." || strings.HasPrefix(rel, "../") {
+		return "", fmt.Errorf("%w: %s", ErrUnsafePath, name)
+	}
+	return rel, nil
+}
+
+// full 返回相对路径对应的实际路径
+func (e *extractor) full(rel string) string {
+	return filepath.Join(e.dst, filepath.FromSlash(rel))
+}
+
+// mkdirAll 逐级创建目录，已存在的上级必须是目录而不是符号链接
+func (e *extractor) mkdirAll(rel string, perm fs.FileMode) error {
+	if rel == "." {
+		return nil
+	}
+	cur := ""
+	for _, part := range strings.Split(rel, "/") {
+		cur = path.Join(cur, part)
+		err := e.root.Mkdir(cur, perm)
+		if err == nil {
+			continue
+		}
+		if !errors.Is(err, fs.ErrExist) {
+			return err
+		}
+		info, err := e.root.Lstat(cur)
+		if err != nil {
+			return err
+		}
+		if info.Mode()&fs.ModeSymlink != 0 {
+			return fmt.Errorf("%w: %s is a symlink", ErrUnsafePath, cur)
+		}
+		if !info.IsDir() {
+			return fmt.Errorf("%s: not a directory", cur)
+		}
+	}
+	return nil
+}
+
+// resolve 按未经字面清理的路径依次展开 rel 中已存在的符号链接，返回实际位置相对目标目录的路径，任一步越出目标目录时返回 ErrUnsafePath
+func (e *extractor) resolve(rel string) (string, error) {
+	var parts []string
+	pending := strings.Split(rel, "/")
+	for hops := 0; len(pending) > 0; {
+		part := pending[0]
+		pending = pending[1:]
+		switch part {
+		case "", ".":
+			continue
+		case "..":
+			if len(parts) == 0 {
+				return "", fmt.Errorf("%w: %s", ErrUnsafePath, rel)
+			}
+			parts = parts[:len(parts)-1]
+			continue
+		}
+		cur := path.Join(append(parts, part)...)
+		info, err := e.root.Lstat(cur)
+		if err != nil || info.Mode()&fs.ModeSymlink == 0 {
+			parts = append(parts, part)
+			continue
+		}
+		if hops++; hops > 40 {
+			return "", fmt.Errorf("%w: too many links in %s", ErrUnsafePath, rel)
+		}
+		// parts 中不含符号链接，字面路径即实际路径
+		link, err := os.Readlink(e.full(cur))
+		if err != nil {
+			return "", err
+		}
+		link = filepath.ToSlash(link)
+		if path.IsAbs(link) || filepath.IsAbs(link) {
+			return "", fmt.Errorf("%w: %s -> %s", ErrUnsafePath, cur, link)
+		}
+		pending = append(strings.Split(link, "/"), pending...)
+	}
+	return path.Join(parts...), nil
+}
+
+// writeFile 写出解包的普通文件
+func (e *extractor) writeFile(rel string, r io.Reader, mode fs.FileMode) error {
+	if err := e.mkdirAll(path.Dir(rel), 0o755); err != nil {
+		return err
+	}
+	// 先删除已存在的文件，避免通过同名符号链接写到其他位置
+	e.root.Remove(rel)
+	f, err := e.root.OpenFile(rel, os.O_CREATE|os.O_WRONLY|os.O_TRUNC|os.O_EXCL, mode.Perm())
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(f, r)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
+
+// symlink 创建符号链接，链接目标展开已有的符号链接后必须位于目标目录之内
+func (e *extractor) symlink(rel, link string) error {
+	if err := e.mkdirAll(path.Dir(rel), 0o755); err != nil {
+		return err
+	}
+	slashLink := strings.ReplaceAll(link, `\`, "/")
+	if path.IsAbs(slashLink) || filepath.IsAbs(link) || filepath.VolumeName(link) != "" {
+		return fmt.Errorf("%w: symlink %s -> %s", ErrUnsafePath, rel, link)
+	}
+	// 不能用 path.Join，字面清理 .. 会绕过对其前面符号链接的展开
+	if _, err := e.resolve(path.Dir(rel) + "/" + slashLink); err != nil {
+		return fmt.Errorf("%w: symlink %s -> %s", ErrUnsafePath, rel, link)
+	}
+	e.root.Remove(rel)
+	return os.Symlink(link, e.full(rel))
+}
+
+// link 创建硬链接，源为归档内路径
+func (e *extractor) link(rel, source string) error {
+	if _, err := clean(source); err != nil {
+		return err
+	}
+	src, err := e.resolve(strings.ReplaceAll(source, `\`, "/"))
+	if err != nil {
+		return err
+	}
+	if err := e.mkdirAll(path.Dir(rel), 0o755); err != nil {
+		return err
+	}
+	e.root.Remove(rel)
+	return os.Link(e.full(src), e.full(rel))
+}
+
+// chtimes 设置修改时间，调用前 rel 的上级目录已经过检查
+func (e *extractor) chtimes(rel string, t time.Time) {
+	os.Chtimes(e.full(rel), t, t)
+}
+
+// progressWriter 统计写入字节数并回调进度
+type progressWriter struct {
+	name  string
+	done  *int64
+	total int64
+	fn    ProgressFunc
+}
+
+func (p *progressWriter) Write(b []byte) (int, error) {
+	*p.done += int64(len(b))
+	p.fn(p.name, *p.done, p.total)
+	return len(b), nil
+}
+
+// copyWithProgress 复制数据并回调进度
+func copyWithProgress(w io.Writer, r io.Reader, name string, done *int64, total int64, fn ProgressFunc) error {
+	if fn != nil {
+		w = io.MultiWriter(w, &progressWriter{name: name, done: done, total: total, fn: fn})
+	}
+	_, err := io.Copy(w, r)
+	return err
+}
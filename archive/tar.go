@@ -0,0 +1,161 @@
+package archive
+
+import (
+	"archive/tar"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+)
+
+// TarGz 将目录或文件打包为 tar.gz 文件
+func TarGz(src, dst string, opts ...Option) error {
+	f, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+	err = WriteTarGz(f, src, opts...)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		os.Remove(dst)
+	}
+	return err
+}
+
+// WriteTarGz 将目录或文件打包为 tar.gz 写入w，可直接写入 HTTP 响应
+func WriteTarGz(w io.Writer, src string, opts ...Option) error {
+	o := newOptions(opts)
+	entries, total, err := collect(src, o)
+	if err != nil {
+		return err
+	}
+	gz := gzip.NewWriter(w)
+	tw := tar.NewWriter(gz)
+	var done int64
+	for _, e := range entries {
+		if err := writeTarEntry(tw, e, o, &done, total); err != nil {
+			return fmt.Errorf("add %s: %w", e.rel, err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	return gz.Close()
+}
+
+func writeTarEntry(tw *tar.Writer, e entry, o *options, done *int64, total int64) error {
+	hdr, err := tar.FileInfoHeader(e.info, e.link)
+	if err != nil {
+		return err
+	}
+	hdr.Name = o.archiveName(e.rel)
+	if e.info.IsDir() {
+		hdr.Name += "/"
+	}
+	// 不记录本机用户信息
+	hdr.Uname, hdr.Gname = "", ""
+	if err := tw.WriteHeader(hdr); err != nil {
+		return err
+	}
+	if !e.info.Mode().IsRegular() {
+		return nil
+	}
+	f, err := os.Open(e.path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return copyWithProgress(tw, io.LimitReader(f, hdr.Size), hdr.Name, done, total, o.progress)
+}
+
+// Unpack 解包 tar.gz 或 tar 文件到目标目录
+func Unpack(src, dst string, opts ...Option) error {
+	f, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	st, err := f.Stat()
+	if err != nil {
+		return err
+	}
+	o := newOptions(opts)
+	var r io.Reader = f
+	if o.progress != nil {
+		var done int64
+		r = io.TeeReader(f, &progressWriter{name: src, done: &done, total: st.Size(), fn: o.progress})
+	}
+	return unpackTar(r, dst, o)
+}
+
+// ReadTarGz 从r读取 tar.gz 或 tar 数据解包到目标目录
+func ReadTarGz(r io.Reader, dst string, opts ...Option) error {
+	return unpackTar(r, dst, newOptions(opts))
+}
+
+func unpackTar(r io.Reader, dst string, o *options) error {
+	br := bufio.NewReader(r)
+	var tr *tar.Reader
+	if head, _ := br.Peek(2); bytes.Equal(head, []byte{0x1f, 0x8b}) {
+		gz, err := gzip.NewReader(br)
+		if err != nil {
+			return err
+		}
+		defer gz.Close()
+		tr = tar.NewReader(gz)
+	} else {
+		tr = tar.NewReader(br)
+	}
+	e, err := newExtractor(dst)
+	if err != nil {
+		return err
+	}
+	defer e.close()
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		rel, err := clean(hdr.Name)
+		if err != nil {
+			return err
+		}
+		if o.excluded(rel, hdr.Typeflag == tar.TypeDir) {
+			continue
+		}
+		mode := fs.FileMode(hdr.Mode).Perm()
+		switch hdr.Typeflag {
+		case tar.TypeDir:
+			if err := e.mkdirAll(rel, mode|0o700); err != nil {
+				return err
+			}
+		case tar.TypeReg:
+			if err := e.writeFile(rel, tr, mode); err != nil {
+				return fmt.Errorf("extract %s: %w", hdr.Name, err)
+			}
+		case tar.TypeSymlink:
+			if err := e.symlink(rel, hdr.Linkname); err != nil {
+				return err
+			}
+		case tar.TypeLink:
+			if err := e.link(rel, hdr.Linkname); err != nil {
+				return err
+			}
+		default:
+			// 跳过设备文件、管道等
+			continue
+		}
+		if !hdr.ModTime.IsZero() && hdr.Typeflag != tar.TypeSymlink {
+			e.chtimes(rel, hdr.ModTime)
+		}
+	}
+}
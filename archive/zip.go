@@ -0,0 +1,146 @@
+package archive
+
+import (
+	"archive/zip"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+)
+
+// Zip 将目录或文件打包为 zip 文件
+func Zip(src, dst string, opts ...Option) error {
+	f, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+	err = WriteZip(f, src, opts...)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		os.Remove(dst)
+	}
+	return err
+}
+
+// WriteZip 将目录或文件打包为 zip 写入w
+func WriteZip(w io.Writer, src string, opts ...Option) error {
+	o := newOptions(opts)
+	entries, total, err := collect(src, o)
+	if err != nil {
+		return err
+	}
+	zw := zip.NewWriter(w)
+	var done int64
+	for _, e := range entries {
+		if err := writeZipEntry(zw, e, o, &done, total); err != nil {
+			return fmt.Errorf("add %s: %w", e.rel, err)
+		}
+	}
+	return zw.Close()
+}
+
+func writeZipEntry(zw *zip.Writer, e entry, o *options, done *int64, total int64) error {
+	hdr, err := zip.FileInfoHeader(e.info)
+	if err != nil {
+		return err
+	}
+	hdr.Name = o.archiveName(e.rel)
+	switch {
+	case e.info.IsDir():
+		hdr.Name += "/"
+		hdr.Method = zip.Store
+	case e.link != "":
+		hdr.Method = zip.Store
+	default:
+		hdr.Method = zip.Deflate
+	}
+	w, err := zw.CreateHeader(hdr)
+	if err != nil {
+		return err
+	}
+	if e.link != "" {
+		_, err = io.WriteString(w, e.link)
+		return err
+	}
+	if !e.info.Mode().IsRegular() {
+		return nil
+	}
+	f, err := os.Open(e.path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return copyWithProgress(w, f, hdr.Name, done, total, o.progress)
+}
+
+// Unzip 解包 zip 文件到目标目录
+func Unzip(src, dst string, opts ...Option) error {
+	zr, err := zip.OpenReader(src)
+	if err != nil {
+		return err
+	}
+	defer zr.Close()
+	o := newOptions(opts)
+	e, err := newExtractor(dst)
+	if err != nil {
+		return err
+	}
+	defer e.close()
+
+	var total, done int64
+	for _, f := range zr.File {
+		total += int64(f.UncompressedSize64)
+	}
+	for _, f := range zr.File {
+		if err := unzipEntry(f, e, o, &done, total); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func unzipEntry(f *zip.File, e *extractor, o *options, done *int64, total int64) error {
+	rel, err := clean(f.Name)
+	if err != nil {
+		return err
+	}
+	mode := f.Mode()
+	if o.excluded(rel, mode.IsDir()) {
+		return nil
+	}
+	if mode.IsDir() {
+		return e.mkdirAll(rel, mode.Perm()|0o700)
+	}
+	rc, err := f.Open()
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+	if mode&fs.ModeSymlink != 0 {
+		link, err := io.ReadAll(io.LimitReader(rc, 4096))
+		if err != nil {
+			return err
+		}
+		return e.symlink(rel, string(link))
+	}
+	if !mode.IsRegular() {
+		return nil
+	}
+	perm := mode.Perm()
+	if perm == 0 {
+		perm = 0o644
+	}
+	var r io.Reader = rc
+	if o.progress != nil {
+		r = io.TeeReader(rc, &progressWriter{name: f.Name, done: done, total: total, fn: o.progress})
+	}
+	if err := e.writeFile(rel, r, perm); err != nil {
+		return fmt.Errorf("extract %s: %w", f.Name, err)
+	}
+	if !f.Modified.IsZero() {
+		e.chtimes(rel, f.Modified)
+	}
+	return nil
+}
@@ -0,0 +1,396 @@
+// Package backup 将 MySQL 表结构和数据、Redis 指定前缀的键、ClickHouse 指定分区以及配置目录打包为单个压缩归档（可加密），
+// 并提供校验清单和对应的恢复流程。
+package backup
+
+import (
+	"archive/tar"
+	"bufio"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/ixxmi/tools/cache/redis"
+	"github.com/ixxmi/tools/db/ckgroup"
+	"github.com/ixxmi/tools/encrypt"
+	"gorm.io/gorm"
+)
+
+// 清单条目类型
+const (
+	KindMySQLSchema = "mysql_schema"
+	KindMySQLData   = "mysql_data"
+	KindRedis       = "redis"
+	KindClickHouse  = "clickhouse"
+	KindFile        = "file"
+)
+
+const (
+	manifestName    = "manifest.json"
+	manifestVersion = 1
+)
+
+// ErrChecksum 归档内容与清单不一致
+var ErrChecksum = errors.New("backup: checksum mismatch")
+
+// Config 配置结构，备份时为数据源，恢复时为恢复目标
+type Config struct {
+	Name       string // 备份名称，作为归档文件名前缀，默认 backup
+	Dir        string // 归档输出目录，默认当前目录
+	TempDir    string // 暂存目录，默认系统临时目录
+	Passphrase string // 加密口令，为空时不加密
+	MySQL      *MySQLSource
+	Redis      *RedisSource
+	ClickHouse *ClickHouseSource
+	Paths      []string // 需要备份的配置目录或文件
+}
+
+// MySQLSource MySQL 备份范围
+type MySQLSource struct {
+	DB         *gorm.DB
+	Tables     []string // 备份表结构的表，为空时备份全部表和视图
+	DataTables []string // 同时备份数据的表
+}
+
+// RedisSource Redis 备份范围
+type RedisSource struct {
+	Client   *redis.RedisClient
+	Prefixes []string // 键前缀，为空时备份全部键
+	Count    int64    // SCAN 每批数量，默认 1000
+}
+
+// ClickHouseSource ClickHouse 备份范围，通过 FREEZE 生成分区快照并从本机数据目录读取，需与 ClickHouse 部署在同一主机
+type ClickHouseSource struct {
+	Client     *ckgroup.ClickHouseClient
+	Partitions []Partition
+}
+
+// Partition ClickHouse 表分区
+type Partition struct {
+	Table string   // database.table
+	IDs   []string // 分区ID（system.parts 中的 partition_id），为空时备份整表
+}
+
+// Manifest 归档清单
+type Manifest struct {
+	Version   int       `json:"version"`
+	Name      string    `json:"name"`
+	Host      string    `json:"host"`
+	CreatedAt time.Time `json:"created_at"`
+	Items     []Item    `json:"items"`
+}
+
+// Item 清单条目，每个条目对应归档中的一个文件
+type Item struct {
+	Kind      string      `json:"kind"`
+	Name      string      `json:"name"`                // 表名、键前缀或原始文件路径
+	Path      string      `json:"path"`                // 归档内路径
+	Partition string      `json:"partition,omitempty"` // ClickHouse 分区ID
+	Count     int64       `json:"count,omitempty"`     // 行数或键数
+	Mode      os.FileMode `json:"mode,omitempty"`
+	Size      int64       `json:"size"`
+	SHA256    string      `json:"sha256"`
+}
+
+// Backup 执行备份，返回归档文件路径和清单
+func Backup(ctx context.Context, config Config) (string, *Manifest, error) {
+	if config.Name == "" {
+		config.Name = "backup"
+	}
+	staging, err := os.MkdirTemp(config.TempDir, "backup-")
+	if err != nil {
+		return "", nil, err
+	}
+	defer os.RemoveAll(staging)
+
+	host, _ := os.Hostname()
+	m := &Manifest{Version: manifestVersion, Name: config.Name, Host: host, CreatedAt: time.Now()}
+	if config.MySQL != nil {
+		if err := backupMySQL(ctx, config.MySQL, staging, m); err != nil {
+			return "", nil, fmt.Errorf("backup mysql: %w", err)
+		}
+	}
+	if config.Redis != nil {
+		if err := backupRedis(ctx, config.Redis, staging, m); err != nil {
+			return "", nil, fmt.Errorf("backup redis: %w", err)
+		}
+	}
+	if config.ClickHouse != nil {
+		if err := backupClickHouse(ctx, config.ClickHouse, staging, m); err != nil {
+			return "", nil, fmt.Errorf("backup clickhouse: %w", err)
+		}
+	}
+	for _, p := range config.Paths {
+		if err := backupPath(ctx, p, staging, m); err != nil {
+			return "", nil, fmt.Errorf("backup %s: %w", p, err)
+		}
+	}
+
+	name := fmt.Sprintf("%s-%s.tar.gz", config.Name, m.CreatedAt.Format("20060102-150405"))
+	if config.Passphrase != "" {
+		name += ".enc"
+	}
+	file := filepath.Join(config.Dir, name)
+	if err := writeArchive(file, config.Passphrase, staging, m); err != nil {
+		os.Remove(file)
+		return "", nil, err
+	}
+	return file, m, nil
+}
+
+// Verify 解包归档并按清单校验全部文件
+func Verify(file, passphrase string) (*Manifest, error) {
+	dir, err := os.MkdirTemp("", "backup-verify-")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(dir)
+	return extract(file, passphrase, dir)
+}
+
+// RestoreOptions 恢复选项
+type RestoreOptions struct {
+	Replace  bool   // 覆盖已存在的表、键和分区，否则跳过已存在的内容
+	FileRoot string // 配置文件恢复的根目录，为空时恢复到原路径；设置后清单中越出该目录的路径会被拒绝
+}
+
+// Restore 校验归档后恢复，config 中为nil的目标跳过对应内容，Paths 不使用
+func Restore(ctx context.Context, file string, config Config, options RestoreOptions) (*Manifest, error) {
+	dir, err := os.MkdirTemp(config.TempDir, "backup-restore-")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(dir)
+
+	m, err := extract(file, config.Passphrase, dir)
+	if err != nil {
+		return nil, err
+	}
+	if config.MySQL != nil {
+		if err := restoreMySQL(ctx, config.MySQL.DB, dir, m, options); err != nil {
+			return m, fmt.Errorf("restore mysql: %w", err)
+		}
+	}
+	if config.Redis != nil {
+		if err := restoreRedis(ctx, config.Redis.Client, dir, m, options); err != nil {
+			return m, fmt.Errorf("restore redis: %w", err)
+		}
+	}
+	if config.ClickHouse != nil {
+		if err := restoreClickHouse(ctx, config.ClickHouse.Client, dir, m, options); err != nil {
+			return m, fmt.Errorf("restore clickhouse: %w", err)
+		}
+	}
+	if err := restoreFiles(ctx, dir, m, options); err != nil {
+		return m, fmt.Errorf("restore files: %w", err)
+	}
+	return m, nil
+}
+
+// addItem 计算暂存文件的大小和校验和并加入清单
+func addItem(m *Manifest, staging string, item Item) error {
+	f, err := os.Open(filepath.Join(staging, filepath.FromSlash(item.Path)))
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	h := sha256.New()
+	n, err := io.Copy(h, f)
+	if err != nil {
+		return err
+	}
+	item.Size = n
+	item.SHA256 = hex.EncodeToString(h.Sum(nil))
+	m.Items = append(m.Items, item)
+	return nil
+}
+
+// createStaged 在暂存目录中创建归档内路径对应的文件
+func createStaged(staging, name string) (*os.File, error) {
+	p := filepath.Join(staging, filepath.FromSlash(name))
+	if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+		return nil, err
+	}
+	return os.Create(p)
+}
+
+// writeJSONLines 逐行写入JSON，返回行数
+func writeJSONLines(staging, name string, fn func(enc *json.Encoder) (int64, error)) (int64, error) {
+	f, err := createStaged(staging, name)
+	if err != nil {
+		return 0, err
+	}
+	w := bufio.NewWriter(f)
+	n, err := fn(json.NewEncoder(w))
+	if err == nil {
+		err = w.Flush()
+	}
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	return n, err
+}
+
+// writeArchive 写出 tar.gz 归档，清单为第一个文件
+func writeArchive(file, passphrase, staging string, m *Manifest) error {
+	f, err := os.Create(file)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	bw := bufio.NewWriter(f)
+	var w io.Writer = bw
+	var enc io.WriteCloser
+	if passphrase != "" {
+		if enc, err = encrypt.NewEncryptWriter(bw, passphrase); err != nil {
+			return err
+		}
+		w = enc
+	}
+	gz := gzip.NewWriter(w)
+	tw := tar.NewWriter(gz)
+
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+	hdr := &tar.Header{Name: manifestName, Mode: 0o644, Size: int64(len(data)), ModTime: m.CreatedAt}
+	if err := tw.WriteHeader(hdr); err != nil {
+		return err
+	}
+	if _, err := tw.Write(data); err != nil {
+		return err
+	}
+	for _, item := range m.Items {
+		if err := writeTarFile(tw, staging, item, m.CreatedAt); err != nil {
+			return err
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	if err := gz.Close(); err != nil {
+		return err
+	}
+	if enc != nil {
+		if err := enc.Close(); err != nil {
+			return err
+		}
+	}
+	if err := bw.Flush(); err != nil {
+		return err
+	}
+	return f.Sync()
+}
+
+func writeTarFile(tw *tar.Writer, staging string, item Item, modTime time.Time) error {
+	f, err := os.Open(filepath.Join(staging, filepath.FromSlash(item.Path)))
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	hdr := &tar.Header{Name: item.Path, Mode: 0o644, Size: item.Size, ModTime: modTime}
+	if err := tw.WriteHeader(hdr); err != nil {
+		return err
+	}
+	_, err = io.Copy(tw, f)
+	return err
+}
+
+// extract 解包归档到dir并校验清单
+func extract(file, passphrase, dir string) (*Manifest, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	br := bufio.NewReader(f)
+	var r io.Reader = br
+	head, _ := br.Peek(8)
+	if encrypt.IsEncryptedStream(head) {
+		if passphrase == "" {
+			return nil, errors.New("backup: archive is encrypted but no passphrase given")
+		}
+		if r, err = encrypt.NewDecryptReader(br, passphrase); err != nil {
+			return nil, err
+		}
+	}
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("open archive: %w", err)
+	}
+	defer gz.Close()
+
+	var m *Manifest
+	tr := tar.NewReader(gz)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("read archive: %w", err)
+		}
+		if hdr.Name == manifestName {
+			m = &Manifest{}
+			if err := json.NewDecoder(tr).Decode(m); err != nil {
+				return nil, fmt.Errorf("decode manifest: %w", err)
+			}
+			continue
+		}
+		name := path.Clean(hdr.Name)
+		if hdr.Typeflag != tar.TypeReg || path.IsAbs(name) || name == ".." || strings.HasPrefix(name, "../") {
+			return nil, fmt.Errorf("backup: unexpected entry %q in archive", hdr.Name)
+		}
+		out, err := createStaged(dir, name)
+		if err != nil {
+			return nil, err
+		}
+		_, err = io.Copy(out, tr)
+		if cerr := out.Close(); err == nil {
+			err = cerr
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	if m == nil {
+		return nil, errors.New("backup: manifest not found in archive")
+	}
+	if m.Version > manifestVersion {
+		return nil, fmt.Errorf("backup: unsupported manifest version %d", m.Version)
+	}
+	for _, item := range m.Items {
+		if !filepath.IsLocal(filepath.FromSlash(item.Path)) {
+			return nil, fmt.Errorf("backup: unexpected item path %q in manifest", item.Path)
+		}
+		check := Manifest{}
+		if err := addItem(&check, dir, Item{Path: item.Path}); err != nil {
+			return nil, fmt.Errorf("%w: %s: %v", ErrChecksum, item.Path, err)
+		}
+		if got := check.Items[0]; got.Size != item.Size || got.SHA256 != item.SHA256 {
+			return nil, fmt.Errorf("%w: %s", ErrChecksum, item.Path)
+		}
+	}
+	return m, nil
+}
+
+// itemsOf 返回指定类型的清单条目
+func itemsOf(m *Manifest, kind string) []Item {
+	var items []Item
+	for _, item := range m.Items {
+		if item.Kind == kind {
+			items = append(items, item)
+		}
+	}
+	return items
+}
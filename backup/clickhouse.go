@@ -0,0 +1,253 @@
+package backup
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ixxmi/tools/db/ckgroup"
+)
+
+// 备份时 FREEZE 生成的快照位于各磁盘的 shadow/<name>/ 下，目录结构与表数据目录一致；
+// 恢复时将分区数据复制到表的 detached 目录后执行 ATTACH PARTITION，进程需有 ClickHouse 数据目录的读写权限
+func backupClickHouse(ctx context.Context, src *ClickHouseSource, staging string, m *Manifest) error {
+	c := src.Client
+	disks, err := clickhouseDisks(c)
+	if err != nil {
+		return err
+	}
+	name := fmt.Sprintf("backup_%d", time.Now().UnixNano())
+	defer func() {
+		for _, disk := range disks {
+			os.RemoveAll(filepath.Join(disk, "shadow", name))
+		}
+	}()
+
+	for _, part := range src.Partitions {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		table, err := qualifyTable(c, part.Table)
+		if err != nil {
+			return err
+		}
+		dataPaths, err := tableDataPaths(c, table)
+		if err != nil {
+			return err
+		}
+		if len(part.IDs) == 0 {
+			if err := c.Exec(fmt.Sprintf("ALTER TABLE %s FREEZE WITH NAME '%s'", table, name)); err != nil {
+				return fmt.Errorf("freeze %s: %w", table, err)
+			}
+		}
+		for _, id := range part.IDs {
+			if err := c.Exec(fmt.Sprintf("ALTER TABLE %s FREEZE PARTITION ID '%s' WITH NAME '%s'", table, escapeCH(id), name)); err != nil {
+				return fmt.Errorf("freeze %s partition %s: %w", table, id, err)
+			}
+		}
+		for _, dp := range dataPaths {
+			disk := diskOf(disks, dp)
+			if disk == "" {
+				return fmt.Errorf("no disk found for data path %s", dp)
+			}
+			shadow := filepath.Join(disk, "shadow", name, strings.TrimPrefix(dp, disk))
+			if err := collectParts(ctx, table, shadow, staging, m); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// collectParts 复制快照目录下的数据分片到暂存目录，按分片名称前缀识别分区ID
+func collectParts(ctx context.Context, table, shadow, staging string, m *Manifest) error {
+	parts, err := os.ReadDir(shadow)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	for _, part := range parts {
+		if !part.IsDir() {
+			continue
+		}
+		partition := partitionOf(part.Name())
+		root := filepath.Join(shadow, part.Name())
+		err := filepath.WalkDir(root, func(p string, d os.DirEntry, err error) error {
+			if err != nil || d.IsDir() {
+				return err
+			}
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			rel, err := filepath.Rel(shadow, p)
+			if err != nil {
+				return err
+			}
+			name := "clickhouse/" + table + "/" + filepath.ToSlash(rel)
+			if err := copyFile(p, filepath.Join(staging, filepath.FromSlash(name))); err != nil {
+				return err
+			}
+			return addItem(m, staging, Item{Kind: KindClickHouse, Name: table, Path: name, Partition: partition})
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func restoreClickHouse(ctx context.Context, c *ckgroup.ClickHouseClient, dir string, m *Manifest, options RestoreOptions) error {
+	// 按表和分区分组
+	tables := make(map[string]map[string][]Item)
+	for _, item := range itemsOf(m, KindClickHouse) {
+		if tables[item.Name] == nil {
+			tables[item.Name] = make(map[string][]Item)
+		}
+		tables[item.Name][item.Partition] = append(tables[item.Name][item.Partition], item)
+	}
+	names := make([]string, 0, len(tables))
+	for name := range tables {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, table := range names {
+		dataPaths, err := tableDataPaths(c, table)
+		if err != nil {
+			return err
+		}
+		detached := filepath.Join(dataPaths[0], "detached")
+		for partition, items := range tables[table] {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			exists, err := partitionExists(c, table, partition)
+			if err != nil {
+				return err
+			}
+			if exists && !options.Replace {
+				continue
+			}
+			prefix := "clickhouse/" + table + "/"
+			for _, item := range items {
+				dst := filepath.Join(detached, filepath.FromSlash(strings.TrimPrefix(item.Path, prefix)))
+				if err := copyFile(filepath.Join(dir, filepath.FromSlash(item.Path)), dst); err != nil {
+					return err
+				}
+			}
+			if exists {
+				if err := c.Exec(fmt.Sprintf("ALTER TABLE %s DROP PARTITION ID '%s'", table, escapeCH(partition))); err != nil {
+					return fmt.Errorf("drop %s partition %s: %w", table, partition, err)
+				}
+			}
+			if err := c.Exec(fmt.Sprintf("ALTER TABLE %s ATTACH PARTITION ID '%s'", table, escapeCH(partition))); err != nil {
+				return fmt.Errorf("attach %s partition %s: %w", table, partition, err)
+			}
+		}
+	}
+	return nil
+}
+
+// qualifyTable 补全库名，返回 database.table
+func qualifyTable(c *ckgroup.ClickHouseClient, table string) (string, error) {
+	if strings.Contains(table, ".") {
+		return table, nil
+	}
+	var db string
+	if err := c.QueryRow("SELECT currentDatabase()").Scan(&db); err != nil {
+		return "", err
+	}
+	return db + "." + table, nil
+}
+
+// tableDataPaths 查询表在各磁盘上的数据目录
+func tableDataPaths(c *ckgroup.ClickHouseClient, table string) ([]string, error) {
+	db, name, _ := strings.Cut(table, ".")
+	var paths []string
+	err := c.QueryRow("SELECT data_paths FROM system.tables WHERE database = ? AND name = ?", db, name).Scan(&paths)
+	if err != nil {
+		return nil, fmt.Errorf("query data paths of %s: %w", table, err)
+	}
+	if len(paths) == 0 {
+		return nil, fmt.Errorf("table %s has no local data path", table)
+	}
+	return paths, nil
+}
+
+func partitionExists(c *ckgroup.ClickHouseClient, table, partition string) (bool, error) {
+	db, name, _ := strings.Cut(table, ".")
+	var n uint64
+	err := c.QueryRow("SELECT count() FROM system.parts WHERE database = ? AND table = ? AND partition_id = ? AND active",
+		db, name, partition).Scan(&n)
+	return n > 0, err
+}
+
+func clickhouseDisks(c *ckgroup.ClickHouseClient) ([]string, error) {
+	rows, err := c.Query("SELECT path FROM system.disks")
+	if err != nil {
+		return nil, fmt.Errorf("query disks: %w", err)
+	}
+	defer rows.Close()
+	var disks []string
+	for rows.Next() {
+		var p string
+		if err := rows.Scan(&p); err != nil {
+			return nil, err
+		}
+		disks = append(disks, p)
+	}
+	return disks, rows.Err()
+}
+
+// diskOf 返回包含数据目录的最长磁盘路径
+func diskOf(disks []string, dataPath string) string {
+	var best string
+	for _, d := range disks {
+		if strings.HasPrefix(dataPath, d) && len(d) > len(best) {
+			best = d
+		}
+	}
+	return best
+}
+
+// partitionOf 从分片名称 <partition_id>_<min_block>_<max_block>_<level> 中取分区ID
+func partitionOf(part string) string {
+	id, _, _ := strings.Cut(part, "_")
+	return id
+}
+
+func escapeCH(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(s)
+}
+
+// copyFile 复制文件，自动创建目标目录
+func copyFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	st, err := in.Stat()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(dst), 0o755); err != nil {
+		return err
+	}
+	out, err := os.OpenFile(dst, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, st.Mode().Perm())
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(out, in)
+	if cerr := out.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
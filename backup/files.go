@@ -0,0 +1,137 @@
+package backup
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// backupPath 备份目录或文件，只包含普通文件，归档内路径为 files/<序号>/<相对路径>
+func backupPath(ctx context.Context, root string, staging string, m *Manifest) error {
+	root, err := filepath.Abs(root)
+	if err != nil {
+		return err
+	}
+	index := len(itemsOf(m, KindFile))
+	return filepath.WalkDir(root, func(p string, d os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		name := fmt.Sprintf("files/%d", index)
+		index++
+		if err := copyFile(p, filepath.Join(staging, filepath.FromSlash(name))); err != nil {
+			return err
+		}
+		return addItem(m, staging, Item{Kind: KindFile, Name: p, Path: name, Mode: info.Mode().Perm()})
+	})
+}
+
+// restoreFiles 将文件恢复到原路径，设置 FileRoot 时恢复到 FileRoot 下的同名路径，
+// 此时通过 os.Root 写入，清单中的路径（含 .. 或指向外部的符号链接）不能越出 FileRoot
+func restoreFiles(ctx context.Context, dir string, m *Manifest, options RestoreOptions) error {
+	var root *os.Root
+	if options.FileRoot != "" {
+		if err := os.MkdirAll(options.FileRoot, 0o755); err != nil {
+			return err
+		}
+		var err error
+		if root, err = os.OpenRoot(options.FileRoot); err != nil {
+			return err
+		}
+		defer root.Close()
+	}
+	for _, item := range itemsOf(m, KindFile) {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		src := filepath.Join(dir, filepath.FromSlash(item.Path))
+		if root != nil {
+			if err := restoreInRoot(root, item, src, options.Replace); err != nil {
+				return err
+			}
+			continue
+		}
+		dst := filepath.FromSlash(item.Name)
+		if !options.Replace {
+			if _, err := os.Stat(dst); err == nil {
+				continue
+			}
+		}
+		if err := copyFile(src, dst); err != nil {
+			return err
+		}
+		if item.Mode != 0 {
+			if err := os.Chmod(dst, item.Mode); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// restoreInRoot 将 src 恢复到 root 下与 item.Name 同名的相对路径
+func restoreInRoot(root *os.Root, item Item, src string, replace bool) error {
+	name := filepath.FromSlash(item.Name)
+	name = strings.TrimLeft(name[len(filepath.VolumeName(name)):], `/\`)
+	if !filepath.IsLocal(name) {
+		return fmt.Errorf("backup: file %q escapes the restore root", item.Name)
+	}
+	if !replace {
+		if _, err := root.Stat(name); err == nil {
+			return nil
+		}
+	}
+	if err := mkdirAllInRoot(root, filepath.Dir(name)); err != nil {
+		return err
+	}
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	perm := item.Mode
+	if perm == 0 {
+		perm = 0o644
+	}
+	out, err := root.OpenFile(name, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, perm)
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(out, in)
+	if err == nil && item.Mode != 0 {
+		err = out.Chmod(item.Mode)
+	}
+	if cerr := out.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
+
+// mkdirAllInRoot 在 root 下逐级创建目录，经过指向 root 外部的符号链接时由 os.Root 拒绝
+func mkdirAllInRoot(root *os.Root, dir string) error {
+	if dir == "." {
+		return nil
+	}
+	if err := mkdirAllInRoot(root, filepath.Dir(dir)); err != nil {
+		return err
+	}
+	if err := root.Mkdir(dir, 0o755); err != nil && !errors.Is(err, fs.ErrExist) {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,262 @@
+package backup
+
+import (
+	"bufio"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+const mysqlInsertBatch = 500
+
+// mysqlHeader 数据文件首行，记录列名
+type mysqlHeader struct {
+	Columns []string `json:"columns"`
+}
+
+func backupMySQL(ctx context.Context, src *MySQLSource, staging string, m *Manifest) error {
+	db := src.DB.WithContext(ctx)
+	var tables, views []string
+	rows, err := db.Raw("SHOW FULL TABLES").Rows()
+	if err != nil {
+		return err
+	}
+	for rows.Next() {
+		var name, typ string
+		if err := rows.Scan(&name, &typ); err != nil {
+			rows.Close()
+			return err
+		}
+		if len(src.Tables) > 0 && !contains(src.Tables, name) {
+			continue
+		}
+		if typ == "VIEW" {
+			views = append(views, name)
+		} else {
+			tables = append(tables, name)
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	// 视图依赖表，放在表之后恢复
+	for _, name := range append(tables, views...) {
+		stmt, err := showCreate(db, name)
+		if err != nil {
+			return fmt.Errorf("show create table %s: %w", name, err)
+		}
+		p := "mysql/schema/" + name + ".sql"
+		f, err := createStaged(staging, p)
+		if err != nil {
+			return err
+		}
+		_, err = f.WriteString(stmt + ";\n")
+		if cerr := f.Close(); err == nil {
+			err = cerr
+		}
+		if err != nil {
+			return err
+		}
+		if err := addItem(m, staging, Item{Kind: KindMySQLSchema, Name: name, Path: p}); err != nil {
+			return err
+		}
+	}
+
+	for _, name := range src.DataTables {
+		p := "mysql/data/" + name + ".jsonl"
+		n, err := writeJSONLines(staging, p, func(enc *json.Encoder) (int64, error) {
+			return dumpTable(db, name, enc)
+		})
+		if err != nil {
+			return fmt.Errorf("dump table %s: %w", name, err)
+		}
+		if err := addItem(m, staging, Item{Kind: KindMySQLData, Name: name, Path: p, Count: n}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// showCreate 读取 SHOW CREATE TABLE 的建表语句，表返回2列，视图返回4列
+func showCreate(db *gorm.DB, name string) (string, error) {
+	rows, err := db.Raw("SHOW CREATE TABLE " + quoteMySQL(name)).Rows()
+	if err != nil {
+		return "", err
+	}
+	defer rows.Close()
+	cols, err := rows.Columns()
+	if err != nil {
+		return "", err
+	}
+	if !rows.Next() {
+		if err := rows.Err(); err != nil {
+			return "", err
+		}
+		return "", sql.ErrNoRows
+	}
+	values := make([]sql.NullString, len(cols))
+	dest := make([]interface{}, len(cols))
+	for i := range values {
+		dest[i] = &values[i]
+	}
+	if err := rows.Scan(dest...); err != nil {
+		return "", err
+	}
+	if len(values) < 2 {
+		return "", fmt.Errorf("unexpected result with %d columns", len(values))
+	}
+	return values[1].String, nil
+}
+
+// dumpTable 按文本协议读取全部行，值保存为原始字节，NULL 保存为 null
+func dumpTable(db *gorm.DB, table string, enc *json.Encoder) (int64, error) {
+	rows, err := db.Raw("SELECT * FROM " + quoteMySQL(table)).Rows()
+	if err != nil {
+		return 0, err
+	}
+	defer rows.Close()
+	cols, err := rows.Columns()
+	if err != nil {
+		return 0, err
+	}
+	if err := enc.Encode(mysqlHeader{Columns: cols}); err != nil {
+		return 0, err
+	}
+	raw := make([]sql.RawBytes, len(cols))
+	dest := make([]interface{}, len(cols))
+	for i := range raw {
+		dest[i] = &raw[i]
+	}
+	var n int64
+	for rows.Next() {
+		if err := rows.Scan(dest...); err != nil {
+			return n, err
+		}
+		values := make([][]byte, len(cols))
+		for i, v := range raw {
+			if v != nil {
+				values[i] = append([]byte{}, v...)
+			}
+		}
+		if err := enc.Encode(values); err != nil {
+			return n, err
+		}
+		n++
+	}
+	return n, rows.Err()
+}
+
+func restoreMySQL(ctx context.Context, db *gorm.DB, dir string, m *Manifest, options RestoreOptions) error {
+	db = db.WithContext(ctx)
+	for _, item := range itemsOf(m, KindMySQLSchema) {
+		exists := db.Migrator().HasTable(item.Name)
+		if exists && !options.Replace {
+			continue
+		}
+		stmt, err := os.ReadFile(filepath.Join(dir, filepath.FromSlash(item.Path)))
+		if err != nil {
+			return err
+		}
+		if exists {
+			drop := "DROP TABLE IF EXISTS "
+			if strings.Contains(string(stmt), " VIEW ") {
+				drop = "DROP VIEW IF EXISTS "
+			}
+			if err := db.Exec(drop + quoteMySQL(item.Name)).Error; err != nil {
+				return fmt.Errorf("drop %s: %w", item.Name, err)
+			}
+		}
+		if err := db.Exec(strings.TrimSuffix(strings.TrimSpace(string(stmt)), ";")).Error; err != nil {
+			return fmt.Errorf("create %s: %w", item.Name, err)
+		}
+	}
+	for _, item := range itemsOf(m, KindMySQLData) {
+		if err := loadTable(db, item.Name, filepath.Join(dir, filepath.FromSlash(item.Path)), options.Replace); err != nil {
+			return fmt.Errorf("load table %s: %w", item.Name, err)
+		}
+	}
+	return nil
+}
+
+// loadTable 分批插入数据文件中的行，覆盖模式下使用 REPLACE，否则使用 INSERT IGNORE 跳过已存在的行
+func loadTable(db *gorm.DB, table, file string, replace bool) error {
+	f, err := os.Open(file)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	dec := json.NewDecoder(bufio.NewReader(f))
+	var header mysqlHeader
+	if err := dec.Decode(&header); err != nil {
+		return err
+	}
+	if len(header.Columns) == 0 {
+		return nil
+	}
+	cols := make([]string, len(header.Columns))
+	for i, c := range header.Columns {
+		cols[i] = quoteMySQL(c)
+	}
+	verb := "INSERT IGNORE INTO "
+	if replace {
+		verb = "REPLACE INTO "
+	}
+	prefix := verb + quoteMySQL(table) + " (" + strings.Join(cols, ", ") + ") VALUES "
+	placeholder := "(" + strings.TrimSuffix(strings.Repeat("?, ", len(cols)), ", ") + ")"
+
+	var args []interface{}
+	count := 0
+	flush := func() error {
+		if count == 0 {
+			return nil
+		}
+		query := prefix + strings.TrimSuffix(strings.Repeat(placeholder+", ", count), ", ")
+		err := db.Exec(query, args...).Error
+		args, count = args[:0], 0
+		return err
+	}
+	for dec.More() {
+		var values [][]byte
+		if err := dec.Decode(&values); err != nil {
+			return err
+		}
+		if len(values) != len(cols) {
+			return fmt.Errorf("row has %d values, expected %d", len(values), len(cols))
+		}
+		for _, v := range values {
+			if v == nil {
+				args = append(args, nil)
+			} else {
+				args = append(args, v)
+			}
+		}
+		count++
+		if count == mysqlInsertBatch {
+			if err := flush(); err != nil {
+				return err
+			}
+		}
+	}
+	return flush()
+}
+
+func quoteMySQL(name string) string {
+	return "`" + strings.ReplaceAll(name, "`", "``") + "`"
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,148 @@
+package backup
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ixxmi/tools/cache/redis"
+	goredis "github.com/redis/go-redis/v9"
+)
+
+// redisEntry Redis 键的 DUMP 序列化值，TTL 为毫秒，0 表示不过期
+type redisEntry struct {
+	Key   string `json:"key"`
+	TTL   int64  `json:"ttl"`
+	Value []byte `json:"value"`
+}
+
+func backupRedis(ctx context.Context, src *RedisSource, staging string, m *Manifest) error {
+	count := src.Count
+	if count <= 0 {
+		count = 1000
+	}
+	prefixes := src.Prefixes
+	if len(prefixes) == 0 {
+		prefixes = []string{""}
+	}
+	for i, prefix := range prefixes {
+		p := fmt.Sprintf("redis/%d.jsonl", i)
+		n, err := writeJSONLines(staging, p, func(enc *json.Encoder) (int64, error) {
+			// 每个前缀重新获取客户端，自动重连后使用新的连接
+			return dumpPrefix(ctx, src.Client.Client(), prefix, count, enc)
+		})
+		if err != nil {
+			return fmt.Errorf("dump prefix %q: %w", prefix, err)
+		}
+		if err := addItem(m, staging, Item{Kind: KindRedis, Name: prefix, Path: p, Count: n}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// dumpPrefix 扫描前缀下的全部键，集群模式下逐个主节点扫描
+func dumpPrefix(ctx context.Context, client goredis.UniversalClient, prefix string, count int64, enc *json.Encoder) (int64, error) {
+	pattern := escapeGlob(prefix) + "*"
+	var n int64
+	scan := func(ctx context.Context, c goredis.Cmdable) error {
+		var cursor uint64
+		for {
+			keys, next, err := c.Scan(ctx, cursor, pattern, count).Result()
+			if err != nil {
+				return err
+			}
+			for _, key := range keys {
+				value, err := c.Dump(ctx, key).Result()
+				if errors.Is(err, goredis.Nil) {
+					continue // 扫描后已删除
+				}
+				if err != nil {
+					return fmt.Errorf("dump %s: %w", key, err)
+				}
+				ttl, err := c.PTTL(ctx, key).Result()
+				if err != nil {
+					return fmt.Errorf("pttl %s: %w", key, err)
+				}
+				entry := redisEntry{Key: key, Value: []byte(value)}
+				if ttl > 0 {
+					entry.TTL = ttl.Milliseconds()
+				}
+				if err := enc.Encode(entry); err != nil {
+					return err
+				}
+				n++
+			}
+			cursor = next
+			if cursor == 0 {
+				return nil
+			}
+		}
+	}
+	if cluster, ok := client.(*goredis.ClusterClient); ok {
+		// ForEachMaster 并发执行，这里串行化写入
+		var mu sync.Mutex
+		err := cluster.ForEachMaster(ctx, func(ctx context.Context, c *goredis.Client) error {
+			mu.Lock()
+			defer mu.Unlock()
+			return scan(ctx, c)
+		})
+		return n, err
+	}
+	return n, scan(ctx, client)
+}
+
+func restoreRedis(ctx context.Context, rc *redis.RedisClient, dir string, m *Manifest, options RestoreOptions) error {
+	for _, item := range itemsOf(m, KindRedis) {
+		f, err := os.Open(filepath.Join(dir, filepath.FromSlash(item.Path)))
+		if err != nil {
+			return err
+		}
+		dec := json.NewDecoder(bufio.NewReader(f))
+		for dec.More() {
+			var entry redisEntry
+			if err = dec.Decode(&entry); err != nil {
+				break
+			}
+			ttl := time.Duration(entry.TTL) * time.Millisecond
+			client := rc.Client()
+			if options.Replace {
+				err = client.RestoreReplace(ctx, entry.Key, ttl, string(entry.Value)).Err()
+			} else {
+				err = client.Restore(ctx, entry.Key, ttl, string(entry.Value)).Err()
+				if err != nil && strings.HasPrefix(err.Error(), "BUSYKEY") {
+					err = nil
+				}
+			}
+			if err != nil {
+				err = fmt.Errorf("restore %s: %w", entry.Key, err)
+				break
+			}
+		}
+		f.Close()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// escapeGlob 转义前缀中的 glob 特殊字符
+func escapeGlob(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		switch r {
+		case '*', '?', '[', ']', '\\':
+			b.WriteByte('\\')
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
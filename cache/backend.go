@@ -0,0 +1,149 @@
+package cache
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/bradfitz/gomemcache/memcache"
+	"github.com/ixxmi/tools/cache/memory"
+	"github.com/ixxmi/tools/cache/redis"
+	goredis "github.com/redis/go-redis/v9"
+)
+
+// redisBackend 基于 cache/redis 客户端，支持单节点和集群，经由 RedisClient 方法执行命令以应用键前缀和调用超时
+type redisBackend struct {
+	rc    *redis.RedisClient
+	owned bool // 客户端由 New 创建时为 true，close 时关闭
+}
+
+func (b *redisBackend) get(ctx context.Context, key string) ([]byte, error) {
+	value, err := b.rc.GetBytesCtx(ctx, key)
+	if errors.Is(err, goredis.Nil) {
+		return nil, ErrMiss
+	}
+	return value, err
+}
+
+func (b *redisBackend) set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	return b.rc.SetCtx(ctx, key, value, ttl)
+}
+
+func (b *redisBackend) del(ctx context.Context, keys ...string) error {
+	// 集群模式下多键 DEL 要求同一槽位，逐个删除
+	for _, key := range keys {
+		if err := b.rc.DelCtx(ctx, key); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// close 只关闭 New 创建的客户端，调用方传入的客户端由调用方管理
+func (b *redisBackend) close() error {
+	if !b.owned {
+		return nil
+	}
+	return b.rc.Close()
+}
+
+// memcachedBackend memcached 后端
+type memcachedBackend struct {
+	client *memcache.Client
+}
+
+func newMemcachedBackend(servers []string, timeout time.Duration) *memcachedBackend {
+	if timeout <= 0 {
+		timeout = time.Second
+	}
+	client := memcache.New(servers...)
+	client.Timeout = timeout
+	return &memcachedBackend{client: client}
+}
+
+// memcachedKey memcached 键最长250字节且不能包含空白和控制字符，不合法的键使用 SHA1
+func memcachedKey(key string) string {
+	if len(key) <= 250 && !strings.ContainsFunc(key, func(r rune) bool { return r <= ' ' || r == 0x7f }) {
+		return key
+	}
+	sum := sha1.Sum([]byte(key))
+	return "sha1:" + hex.EncodeToString(sum[:])
+}
+
+// memcachedExpiration 转换为秒，超过30天时 memcached 按 Unix 时间戳处理
+func memcachedExpiration(ttl time.Duration) int32 {
+	const maxRelative = 30 * 24 * time.Hour
+	if ttl > maxRelative {
+		return int32(time.Now().Add(ttl).Unix())
+	}
+	sec := int32((ttl + time.Second - 1) / time.Second)
+	if sec < 1 {
+		sec = 1
+	}
+	return sec
+}
+
+func (b *memcachedBackend) get(ctx context.Context, key string) ([]byte, error) {
+	item, err := b.client.Get(memcachedKey(key))
+	if errors.Is(err, memcache.ErrCacheMiss) {
+		return nil, ErrMiss
+	}
+	if err != nil {
+		return nil, err
+	}
+	return item.Value, nil
+}
+
+func (b *memcachedBackend) set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	return b.client.Set(&memcache.Item{Key: memcachedKey(key), Value: value, Expiration: memcachedExpiration(ttl)})
+}
+
+func (b *memcachedBackend) del(ctx context.Context, keys ...string) error {
+	for _, key := range keys {
+		if err := b.client.Delete(memcachedKey(key)); err != nil && !errors.Is(err, memcache.ErrCacheMiss) {
+			return err
+		}
+	}
+	return nil
+}
+
+func (b *memcachedBackend) close() error {
+	return b.client.Close()
+}
+
+// memoryBackend 进程内缓存，基于 cache/memory 的 LRU 实现，与 Tiered 的本地缓存淘汰规则一致
+type memoryBackend struct {
+	cache *memory.Cache
+}
+
+func newMemoryBackend(config Config) *memoryBackend {
+	return &memoryBackend{cache: memory.New(memory.Config{
+		MaxEntries:      config.MaxEntries,
+		MaxBytes:        config.MaxBytes,
+		CleanupInterval: config.CleanupInterval,
+	})}
+}
+
+func (b *memoryBackend) get(ctx context.Context, key string) ([]byte, error) {
+	value, err := memory.GetAs[[]byte](b.cache, key)
+	if errors.Is(err, memory.ErrMiss) {
+		return nil, ErrMiss
+	}
+	return value, err
+}
+
+func (b *memoryBackend) set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	memory.SetAs(b.cache, key, value, ttl)
+	return nil
+}
+
+func (b *memoryBackend) del(ctx context.Context, keys ...string) error {
+	return b.cache.Del(keys...)
+}
+
+func (b *memoryBackend) close() error {
+	return b.cache.Close()
+}
@@ -0,0 +1,176 @@
+// Package cache 定义与后端无关的缓存接口，支持 Redis、memcached 和进程内缓存，
+// 通过配置选择后端，业务代码切换部署环境时无需修改。
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ixxmi/tools/cache/redis"
+	"golang.org/x/sync/singleflight"
+)
+
+// ErrMiss 键不存在或已过期
+var ErrMiss = errors.New("cache: miss")
+
+// 后端类型
+const (
+	TypeRedis     = "redis"
+	TypeMemcached = "memcached"
+	TypeMemory    = "memory"
+)
+
+// LoadFunc 缓存未命中时加载数据
+type LoadFunc func(ctx context.Context) ([]byte, error)
+
+// Cache 缓存接口，ttl 为0时使用默认过期时间
+type Cache interface {
+	// Get 读取缓存，不存在时返回 ErrMiss
+	Get(ctx context.Context, key string) ([]byte, error)
+	// Set 写入缓存
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+	// Delete 删除缓存，键不存在不报错
+	Delete(ctx context.Context, keys ...string) error
+	// GetOrLoad 读取缓存，未命中时调用 load 加载并写入，同一进程内相同键的并发加载只执行一次
+	GetOrLoad(ctx context.Context, key string, ttl time.Duration, load LoadFunc) ([]byte, error)
+	// Close 释放后端资源
+	Close() error
+}
+
+// Config 缓存配置
+type Config struct {
+	Type       string        // redis / memcached / memory，默认 memory
+	Prefix     string        // 键前缀，多个服务共用后端时用于隔离
+	DefaultTTL time.Duration // 默认过期时间，默认10分钟
+
+	Redis       redis.Config       // Type 为 redis 且 RedisClient 为空时按此新建客户端，Close 时关闭
+	RedisClient *redis.RedisClient // 复用已有客户端，Close 时不关闭
+
+	MemcachedServers []string      // Type 为 memcached 时使用
+	Timeout          time.Duration // memcached 读写超时，默认1秒
+
+	MaxEntries      int           // Type 为 memory 时的最大条目数，默认100000，超出时淘汰最久未使用的条目
+	MaxBytes        int64         // Type 为 memory 时的最大占用字节数，0表示不限制
+	CleanupInterval time.Duration // 过期条目清理周期，默认1分钟
+}
+
+// backend 后端需要实现的基本操作，ttl 已处理默认值
+type backend interface {
+	get(ctx context.Context, key string) ([]byte, error)
+	set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+	del(ctx context.Context, keys ...string) error
+	close() error
+}
+
+// New 按配置创建缓存
+func New(config Config) (Cache, error) {
+	var b backend
+	switch config.Type {
+	case TypeRedis:
+		if config.RedisClient != nil {
+			b = &redisBackend{rc: config.RedisClient}
+			break
+		}
+		// 按配置新建的客户端归缓存所有，Close 时一并关闭，也不替换全局 redis.RC
+		rc, err := redis.NewClient(config.Redis)
+		if err != nil {
+			return nil, err
+		}
+		b = &redisBackend{rc: rc, owned: true}
+	case TypeMemcached:
+		if len(config.MemcachedServers) == 0 {
+			return nil, errors.New("cache: memcached servers required")
+		}
+		b = newMemcachedBackend(config.MemcachedServers, config.Timeout)
+	case TypeMemory, "":
+		b = newMemoryBackend(config)
+	default:
+		return nil, fmt.Errorf("cache: unsupported type %q", config.Type)
+	}
+	return wrap(b, config), nil
+}
+
+// NewRedis 使用已有 Redis 客户端创建缓存
+func NewRedis(rc *redis.RedisClient, config Config) Cache {
+	return wrap(&redisBackend{rc: rc}, config)
+}
+
+// NewMemcached 创建 memcached 缓存
+func NewMemcached(servers []string, config Config) Cache {
+	return wrap(newMemcachedBackend(servers, config.Timeout), config)
+}
+
+// NewMemory 创建进程内缓存
+func NewMemory(config Config) Cache {
+	return wrap(newMemoryBackend(config), config)
+}
+
+func wrap(b backend, config Config) *cache {
+	if config.DefaultTTL <= 0 {
+		config.DefaultTTL = 10 * time.Minute
+	}
+	return &cache{backend: b, prefix: config.Prefix, ttl: config.DefaultTTL}
+}
+
+// cache 为后端补充键前缀、默认过期时间和合并加载
+type cache struct {
+	backend backend
+	prefix  string
+	ttl     time.Duration
+	group   singleflight.Group
+}
+
+func (c *cache) Get(ctx context.Context, key string) ([]byte, error) {
+	return c.backend.get(ctx, c.prefix+key)
+}
+
+func (c *cache) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	if ttl <= 0 {
+		ttl = c.ttl
+	}
+	return c.backend.set(ctx, c.prefix+key, value, ttl)
+}
+
+func (c *cache) Delete(ctx context.Context, keys ...string) error {
+	if len(keys) == 0 {
+		return nil
+	}
+	full := make([]string, len(keys))
+	for i, k := range keys {
+		full[i] = c.prefix + k
+	}
+	return c.backend.del(ctx, full...)
+}
+
+func (c *cache) GetOrLoad(ctx context.Context, key string, ttl time.Duration, load LoadFunc) ([]byte, error) {
+	value, err := c.Get(ctx, key)
+	if err == nil {
+		return value, nil
+	}
+	if !errors.Is(err, ErrMiss) {
+		return nil, err
+	}
+	v, err, _ := c.group.Do(key, func() (interface{}, error) {
+		// 等待期间其他请求可能已写入
+		if value, err := c.Get(ctx, key); err == nil {
+			return value, nil
+		}
+		value, err := load(ctx)
+		if err != nil {
+			return nil, err
+		}
+		// 写缓存失败不影响本次结果
+		_ = c.Set(ctx, key, value, ttl)
+		return value, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return v.([]byte), nil
+}
+
+func (c *cache) Close() error {
+	return c.backend.close()
+}
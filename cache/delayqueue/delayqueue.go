@@ -0,0 +1,266 @@
+// Package delayqueue 提供基于 Redis 有序集合的延迟任务队列，到期任务由 Lua 脚本原子认领，失败按指数退避重试，
+// 超过重试次数后转入死信集合。
+package delayqueue
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/ixxmi/tools/cache/redis"
+	goredis "github.com/redis/go-redis/v9"
+)
+
+// ErrNotFound 任务不存在或已不在对应集合中
+var ErrNotFound = errors.New("delayqueue: task not found")
+
+// Config 配置结构
+type Config struct {
+	Name         string        // 队列名称，同时作为键前缀，默认 delayqueue
+	Concurrency  int           // 并发处理数，默认1
+	BatchSize    int           // 每次认领的最大任务数，默认10
+	PollInterval time.Duration // 没有到期任务时的轮询间隔，默认1s
+	MaxRetries   int           // 最大重试次数，默认3，小于0不重试
+	RetryBackoff time.Duration // 首次重试等待时间，之后按指数增长，默认10s
+	RetryMaxWait time.Duration // 重试等待时间上限，默认10min
+	Timeout      time.Duration // 单个任务执行超时时间，默认5min，超时未确认的任务会被重新认领
+}
+
+// Task 延迟任务
+type Task struct {
+	ID        string
+	Payload   []byte
+	RunAt     time.Time // 计划执行时间
+	Attempts  int       // 已执行次数
+	LastError string    // 最近一次执行的错误
+	FailedAt  time.Time // 转入死信集合的时间，仅死信任务有值
+}
+
+// Bind 将任务负载解析到dest
+func (t *Task) Bind(dest interface{}) error {
+	if err := json.Unmarshal(t.Payload, dest); err != nil {
+		return fmt.Errorf("failed to decode task payload: %w", err)
+	}
+	return nil
+}
+
+// cancelScript 仅当任务仍在等待中时删除
+var cancelScript = goredis.NewScript(`
+if redis.call('ZREM', KEYS[1], ARGV[1]) == 0 then
+	return 0
+end
+redis.call('DEL', ARGV[2] .. 'task:' .. ARGV[1])
+return 1
+`)
+
+// requeueScript 将死信任务重置执行次数后放回等待集合
+var requeueScript = goredis.NewScript(`
+if redis.call('ZREM', KEYS[1], ARGV[1]) == 0 then
+	return 0
+end
+local key = ARGV[3] .. 'task:' .. ARGV[1]
+redis.call('HSET', key, 'attempts', 0, 'run_at', ARGV[2])
+redis.call('HDEL', key, 'failed_at')
+redis.call('ZADD', KEYS[2], ARGV[2], ARGV[1])
+return 1
+`)
+
+// Queue 延迟任务队列
+type Queue struct {
+	rc     *redis.RedisClient
+	config Config
+	keys   keys
+}
+
+// New 创建延迟任务队列
+func New(rc *redis.RedisClient, config Config) *Queue {
+	if config.Name == "" {
+		config.Name = "delayqueue"
+	}
+	if config.Concurrency <= 0 {
+		config.Concurrency = 1
+	}
+	if config.BatchSize <= 0 {
+		config.BatchSize = 10
+	}
+	if config.PollInterval <= 0 {
+		config.PollInterval = time.Second
+	}
+	if config.MaxRetries == 0 {
+		config.MaxRetries = 3
+	}
+	if config.RetryBackoff <= 0 {
+		config.RetryBackoff = 10 * time.Second
+	}
+	if config.RetryMaxWait <= 0 {
+		config.RetryMaxWait = 10 * time.Minute
+	}
+	if config.Timeout <= 0 {
+		config.Timeout = 5 * time.Minute
+	}
+	return &Queue{
+		rc:     rc,
+		config: config,
+		keys:   newKeys(config.Name),
+	}
+}
+
+// Enqueue 添加任务，在 runAt 到达后执行，runAt 早于当前时间时立即可执行。
+// payload 按JSON编码（[]byte 原样保存），返回任务ID
+func (q *Queue) Enqueue(ctx context.Context, payload interface{}, runAt time.Time) (string, error) {
+	var data []byte
+	switch v := payload.(type) {
+	case []byte:
+		data = v
+	case nil:
+	default:
+		var err error
+		if data, err = json.Marshal(v); err != nil {
+			return "", fmt.Errorf("failed to marshal task payload: %w", err)
+		}
+	}
+
+	id := newID()
+	at := runAt.UnixMilli()
+	_, err := q.rc.Client().TxPipelined(ctx, func(pipe goredis.Pipeliner) error {
+		pipe.HSet(ctx, q.keys.task(id), map[string]interface{}{
+			"payload":  data,
+			"run_at":   at,
+			"attempts": 0,
+		})
+		pipe.ZAdd(ctx, q.keys.pending(), goredis.Z{Score: float64(at), Member: id})
+		return nil
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to enqueue task: %w", err)
+	}
+	return id, nil
+}
+
+// EnqueueIn 添加任务，在 delay 之后执行
+func (q *Queue) EnqueueIn(ctx context.Context, payload interface{}, delay time.Duration) (string, error) {
+	return q.Enqueue(ctx, payload, time.Now().Add(delay))
+}
+
+// Cancel 取消尚未开始执行的任务，任务不存在或已被认领时返回 ErrNotFound
+func (q *Queue) Cancel(ctx context.Context, id string) error {
+	n, err := cancelScript.Run(ctx, q.rc.Client(), []string{q.keys.pending()}, id, q.keys.prefix).Int()
+	if err != nil {
+		return fmt.Errorf("failed to cancel task %s: %w", id, err)
+	}
+	if n == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// Get 读取任务详情
+func (q *Queue) Get(ctx context.Context, id string) (*Task, error) {
+	fields, err := q.rc.Client().HGetAll(ctx, q.keys.task(id)).Result()
+	if err != nil {
+		return nil, err
+	}
+	if len(fields) == 0 {
+		return nil, ErrNotFound
+	}
+	return parseTask(id, fields), nil
+}
+
+// Len 等待执行（含重试等待中）的任务数
+func (q *Queue) Len(ctx context.Context) (int64, error) {
+	return q.rc.Client().ZCard(ctx, q.keys.pending()).Result()
+}
+
+// Dead 按转入时间倒序分页查询死信任务
+func (q *Queue) Dead(ctx context.Context, offset, limit int64) ([]*Task, error) {
+	if limit <= 0 {
+		limit = 20
+	}
+	ids, err := q.rc.Client().ZRevRange(ctx, q.keys.dead(), offset, offset+limit-1).Result()
+	if err != nil {
+		return nil, err
+	}
+	return q.loadTasks(ctx, ids)
+}
+
+// Requeue 将死信任务重置执行次数后立即放回队列，任务不在死信集合中时返回 ErrNotFound
+func (q *Queue) Requeue(ctx context.Context, id string) error {
+	n, err := requeueScript.Run(ctx, q.rc.Client(),
+		[]string{q.keys.dead(), q.keys.pending()},
+		id, time.Now().UnixMilli(), q.keys.prefix,
+	).Int()
+	if err != nil {
+		return fmt.Errorf("failed to requeue task %s: %w", id, err)
+	}
+	if n == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// loadTasks 批量读取任务详情，已被删除的任务不出现在结果中
+func (q *Queue) loadTasks(ctx context.Context, ids []string) ([]*Task, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+	cmds := make([]*goredis.MapStringStringCmd, len(ids))
+	_, err := q.rc.Client().Pipelined(ctx, func(pipe goredis.Pipeliner) error {
+		for i, id := range ids {
+			cmds[i] = pipe.HGetAll(ctx, q.keys.task(id))
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	tasks := make([]*Task, 0, len(ids))
+	for i, cmd := range cmds {
+		if fields := cmd.Val(); len(fields) > 0 {
+			tasks = append(tasks, parseTask(ids[i], fields))
+		}
+	}
+	return tasks, nil
+}
+
+// parseTask 从哈希字段解析任务
+func parseTask(id string, fields map[string]string) *Task {
+	task := &Task{
+		ID:        id,
+		Payload:   []byte(fields["payload"]),
+		LastError: fields["error"],
+	}
+	task.Attempts, _ = strconv.Atoi(fields["attempts"])
+	if ms, err := strconv.ParseInt(fields["run_at"], 10, 64); err == nil {
+		task.RunAt = time.UnixMilli(ms)
+	}
+	if ms, err := strconv.ParseInt(fields["failed_at"], 10, 64); err == nil {
+		task.FailedAt = time.UnixMilli(ms)
+	}
+	return task
+}
+
+// keys Redis 键，使用 {name} 哈希标签保证集群模式下落在同一槽位以支持事务和脚本
+type keys struct {
+	prefix string
+}
+
+func newKeys(name string) keys {
+	return keys{prefix: "{" + name + "}:"}
+}
+
+func (k keys) task(id string) string { return k.prefix + "task:" + id }
+func (k keys) pending() string       { return k.prefix + "pending" }
+func (k keys) processing() string    { return k.prefix + "processing" }
+func (k keys) dead() string          { return k.prefix + "dead" }
+
+// newID 生成随机任务ID
+func newID() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,233 @@
+package delayqueue
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	goredis "github.com/redis/go-redis/v9"
+)
+
+// processingGrace 任务超时后再等待多久才视为处理协程已退出并重新认领
+const processingGrace = 30 * time.Second
+
+// claimScript 原子认领到期任务：移出等待集合、记入执行中集合并累加执行次数
+var claimScript = goredis.NewScript(`
+local ids = redis.call('ZRANGEBYSCORE', KEYS[1], '-inf', ARGV[1], 'LIMIT', 0, ARGV[3])
+for _, id in ipairs(ids) do
+	redis.call('ZREM', KEYS[1], id)
+	redis.call('ZADD', KEYS[2], ARGV[2], id)
+	redis.call('HINCRBY', ARGV[4] .. 'task:' .. id, 'attempts', 1)
+end
+return ids
+`)
+
+// recoverScript 将执行超时未确认的任务放回等待集合
+var recoverScript = goredis.NewScript(`
+local ids = redis.call('ZRANGEBYSCORE', KEYS[1], '-inf', ARGV[1], 'LIMIT', 0, ARGV[2])
+for _, id in ipairs(ids) do
+	redis.call('ZREM', KEYS[1], id)
+	redis.call('ZADD', KEYS[2], ARGV[1], id)
+end
+return #ids
+`)
+
+// Handler 任务处理函数，返回错误时按重试策略重新调度
+type Handler func(ctx context.Context, task *Task) error
+
+// Worker 轮询到期任务并并发处理
+type Worker struct {
+	q       *Queue
+	handler Handler
+	sem     chan struct{}
+	wg      sync.WaitGroup
+	OnError func(task *Task, err error) // 处理失败或轮询失败回调，task 为 nil 表示与具体任务无关，默认输出日志
+}
+
+// NewWorker 创建任务处理者
+func NewWorker(q *Queue, handler Handler) *Worker {
+	return &Worker{
+		q:       q,
+		handler: handler,
+		sem:     make(chan struct{}, q.config.Concurrency),
+	}
+}
+
+// Run 持续认领并处理到期任务，直到 ctx 被取消；返回前等待处理中的任务完成
+func (w *Worker) Run(ctx context.Context) error {
+	defer w.wg.Wait()
+	for ctx.Err() == nil {
+		if err := w.recoverStale(ctx); err != nil && ctx.Err() == nil {
+			w.reportError(nil, fmt.Errorf("failed to recover stale tasks: %w", err))
+		}
+
+		// 只认领空闲处理槽位数量的任务，处理跟不上时留在 Redis 中供其他实例认领
+		free := cap(w.sem) - len(w.sem)
+		limit := max(min(w.q.config.BatchSize, free), 1)
+		tasks, err := w.claim(ctx, limit)
+		if err != nil {
+			if ctx.Err() != nil {
+				break
+			}
+			w.reportError(nil, fmt.Errorf("failed to claim tasks: %w", err))
+			sleep(ctx, w.q.config.PollInterval)
+			continue
+		}
+		for _, task := range tasks {
+			w.dispatch(ctx, task)
+		}
+		if len(tasks) < limit {
+			sleep(ctx, w.q.config.PollInterval)
+		}
+	}
+	return nil
+}
+
+// claim 认领最多 limit 个到期任务
+func (w *Worker) claim(ctx context.Context, limit int) ([]*Task, error) {
+	now := time.Now()
+	deadline := now.Add(w.q.config.Timeout + processingGrace).UnixMilli()
+	ids, err := claimScript.Run(ctx, w.q.rc.Client(),
+		[]string{w.q.keys.pending(), w.q.keys.processing()},
+		now.UnixMilli(), deadline, limit, w.q.keys.prefix,
+	).StringSlice()
+	if err != nil || len(ids) == 0 {
+		return nil, err
+	}
+	tasks, err := w.q.loadTasks(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	if len(tasks) < len(ids) {
+		// 任务详情已被删除，清理执行中记录
+		found := make(map[string]bool, len(tasks))
+		for _, t := range tasks {
+			found[t.ID] = true
+		}
+		for _, id := range ids {
+			if !found[id] {
+				w.q.rc.Client().ZRem(ctx, w.q.keys.processing(), id)
+			}
+		}
+	}
+	return tasks, nil
+}
+
+// dispatch 等待空闲槽位后处理任务。已认领的任务即使 ctx 被取消也会执行，
+// 以免在超时回收前一直滞留在执行中集合
+func (w *Worker) dispatch(ctx context.Context, task *Task) {
+	w.sem <- struct{}{}
+	w.wg.Add(1)
+	go func() {
+		defer func() {
+			<-w.sem
+			w.wg.Done()
+		}()
+		w.process(ctx, task)
+	}()
+}
+
+// process 执行任务并根据结果确认、重试或转入死信
+func (w *Worker) process(ctx context.Context, task *Task) {
+	// 停止时让执行中的任务继续完成，仅受任务超时限制
+	bg := context.WithoutCancel(ctx)
+	taskCtx, cancel := context.WithTimeout(bg, w.q.config.Timeout)
+	err := run(taskCtx, w.handler, task)
+	cancel()
+
+	if err == nil {
+		if err := w.complete(bg, task); err != nil {
+			w.reportError(task, fmt.Errorf("failed to ack task: %w", err))
+		}
+		return
+	}
+
+	w.reportError(task, err)
+	if err := w.fail(bg, task, err); err != nil {
+		w.reportError(task, fmt.Errorf("failed to record task failure: %w", err))
+	}
+}
+
+// run 调用处理函数并捕获panic
+func run(ctx context.Context, handler Handler, task *Task) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("task panic: %v", r)
+		}
+	}()
+	return handler(ctx, task)
+}
+
+// complete 确认任务完成并删除
+func (w *Worker) complete(ctx context.Context, task *Task) error {
+	_, err := w.q.rc.Client().TxPipelined(ctx, func(pipe goredis.Pipeliner) error {
+		pipe.ZRem(ctx, w.q.keys.processing(), task.ID)
+		pipe.Del(ctx, w.q.keys.task(task.ID))
+		return nil
+	})
+	return err
+}
+
+// fail 记录失败，未超过重试次数时按退避时间重新调度，否则转入死信集合
+func (w *Worker) fail(ctx context.Context, task *Task, cause error) error {
+	task.LastError = cause.Error()
+	now := time.Now()
+	_, err := w.q.rc.Client().TxPipelined(ctx, func(pipe goredis.Pipeliner) error {
+		pipe.ZRem(ctx, w.q.keys.processing(), task.ID)
+		if task.Attempts <= w.q.config.MaxRetries {
+			runAt := now.Add(w.backoff(task.Attempts)).UnixMilli()
+			pipe.HSet(ctx, w.q.keys.task(task.ID), "error", task.LastError, "run_at", runAt)
+			pipe.ZAdd(ctx, w.q.keys.pending(), goredis.Z{Score: float64(runAt), Member: task.ID})
+			return nil
+		}
+		pipe.HSet(ctx, w.q.keys.task(task.ID), "error", task.LastError, "failed_at", now.UnixMilli())
+		pipe.ZAdd(ctx, w.q.keys.dead(), goredis.Z{Score: float64(now.UnixMilli()), Member: task.ID})
+		return nil
+	})
+	return err
+}
+
+// recoverStale 回收执行超时的任务
+func (w *Worker) recoverStale(ctx context.Context) error {
+	return recoverScript.Run(ctx, w.q.rc.Client(),
+		[]string{w.q.keys.processing(), w.q.keys.pending()},
+		time.Now().UnixMilli(), 100,
+	).Err()
+}
+
+// backoff 计算第attempts次失败后的重试等待时间
+func (w *Worker) backoff(attempts int) time.Duration {
+	if attempts < 1 {
+		attempts = 1
+	}
+	wait := w.q.config.RetryBackoff << uint(attempts-1)
+	if wait <= 0 || wait > w.q.config.RetryMaxWait {
+		wait = w.q.config.RetryMaxWait
+	}
+	return wait
+}
+
+// reportError 报告错误，未设置 OnError 时写入标准日志
+func (w *Worker) reportError(task *Task, err error) {
+	if w.OnError != nil {
+		w.OnError(task, err)
+		return
+	}
+	if task != nil {
+		log.Printf("延迟任务 %s(%s) 第%d次执行失败: %v", w.q.config.Name, task.ID, task.Attempts, err)
+		return
+	}
+	log.Printf("延迟队列 %s 错误: %v", w.q.config.Name, err)
+}
+
+// sleep 等待d或ctx取消
+func sleep(ctx context.Context, d time.Duration) {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+	case <-t.C:
+	}
+}
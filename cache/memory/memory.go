@@ -0,0 +1,397 @@
+// Package memory 进程内 LRU 缓存，支持按条目设置过期时间、限制条目数和占用字节数。
+//
+// Cache 的 Set/Get/GetMap/GetMaps/Del/Exists/Keys 与 cache/redis.RedisClient 签名一致，
+// 只依赖这些方法的调用方可以在本地缓存和 Redis 之间切换；键不存在时返回 ErrMiss（Redis 为 redis.Nil）。
+// 需要保存任意类型且不经过序列化时使用泛型的 SetAs/GetAs。
+package memory
+
+import (
+	"container/list"
+	"encoding"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// ErrMiss 键不存在或已过期
+var ErrMiss = errors.New("memory: key not found")
+
+// Config 缓存配置
+type Config struct {
+	MaxEntries      int                                       // 最大条目数，默认100000，超出时淘汰最久未使用的条目
+	MaxBytes        int64                                     // 最大占用字节数（键与值长度之和），0表示不限制
+	DefaultTTL      time.Duration                             // Set 的过期时间为0时使用，0表示永不过期
+	CleanupInterval time.Duration                             // 过期条目清理周期，默认1分钟，小于0时只在访问时惰性删除
+	SizeFunc        func(key string, value interface{}) int64 // 计算 SetAs 写入的非字符串值占用的字节数，默认按64字节估算
+}
+
+// Stats 缓存统计
+type Stats struct {
+	Hits      uint64 // 命中次数
+	Misses    uint64 // 未命中次数（包括已过期）
+	Evictions uint64 // 因容量限制被淘汰的条目数
+	Expired   uint64 // 过期被删除的条目数
+	Entries   int    // 当前条目数
+	Bytes     int64  // 当前占用字节数
+}
+
+// HitRate 命中率，无访问时返回0
+func (s Stats) HitRate() float64 {
+	total := s.Hits + s.Misses
+	if total == 0 {
+		return 0
+	}
+	return float64(s.Hits) / float64(total)
+}
+
+type entry struct {
+	key      string
+	value    interface{} // Set 写入时为 []byte，SetAs 写入时为原始值
+	size     int64
+	expireAt time.Time // 零值表示永不过期
+}
+
+func (e *entry) expired(now time.Time) bool {
+	return !e.expireAt.IsZero() && now.After(e.expireAt)
+}
+
+// Cache 并发安全的 LRU 缓存
+type Cache struct {
+	config Config
+
+	mu    sync.Mutex
+	ll    *list.List // 头部为最近使用
+	items map[string]*list.Element
+	bytes int64
+
+	hits, misses, evictions, expired atomic.Uint64
+
+	stop chan struct{}
+	once sync.Once
+}
+
+// New 创建缓存，CleanupInterval 大于0时启动后台清理，不再使用时需调用 Close
+func New(config Config) *Cache {
+	if config.MaxEntries <= 0 {
+		config.MaxEntries = 100000
+	}
+	if config.CleanupInterval == 0 {
+		config.CleanupInterval = time.Minute
+	}
+	c := &Cache{
+		config: config,
+		ll:     list.New(),
+		items:  make(map[string]*list.Element),
+		stop:   make(chan struct{}),
+	}
+	if config.CleanupInterval > 0 {
+		go c.janitor(config.CleanupInterval)
+	}
+	return c
+}
+
+func (c *Cache) janitor(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-c.stop:
+			return
+		case now := <-ticker.C:
+			c.mu.Lock()
+			for el := c.ll.Back(); el != nil; {
+				prev := el.Prev()
+				if el.Value.(*entry).expired(now) {
+					c.removeElement(el)
+					c.expired.Add(1)
+				}
+				el = prev
+			}
+			c.mu.Unlock()
+		}
+	}
+}
+
+// Close 停止后台清理，缓存仍可继续使用
+func (c *Cache) Close() error {
+	c.once.Do(func() { close(c.stop) })
+	return nil
+}
+
+// Set 设置键值，expiration 为0时使用 DefaultTTL。
+// 与 Redis 一致，字符串、[]byte、数字、布尔值和 encoding.BinaryMarshaler 按原样保存，其他类型保存为 JSON
+func (c *Cache) Set(key string, value interface{}, expiration time.Duration) error {
+	b, err := encode(value)
+	if err != nil {
+		return err
+	}
+	c.set(key, b, int64(len(b)), expiration)
+	return nil
+}
+
+// Get 获取值
+func (c *Cache) Get(key string) (string, error) {
+	v, ok := c.get(key)
+	if !ok {
+		return "", ErrMiss
+	}
+	b, err := encode(v)
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
+
+// GetMap 获取以 JSON 对象保存的值
+func (c *Cache) GetMap(key string) (map[string]interface{}, error) {
+	return GetAs[map[string]interface{}](c, key)
+}
+
+// GetMaps 获取以 JSON 数组保存的值
+func (c *Cache) GetMaps(key string) ([]map[string]interface{}, error) {
+	return GetAs[[]map[string]interface{}](c, key)
+}
+
+// Del 删除键，键不存在不报错
+func (c *Cache) Del(keys ...string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, key := range keys {
+		if el, ok := c.items[key]; ok {
+			c.removeElement(el)
+		}
+	}
+	return nil
+}
+
+// Exists 判断键是否存在，不影响淘汰顺序和命中统计
+func (c *Cache) Exists(key string) (bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	el, ok := c.items[key]
+	return ok && !el.Value.(*entry).expired(time.Now()), nil
+}
+
+// Keys 获取匹配的键列表，pattern 语法同 path.Match（支持 * ? [...]，与 Redis KEYS 基本一致）
+func (c *Cache) Keys(pattern string) ([]string, error) {
+	if _, err := path.Match(pattern, ""); err != nil {
+		return nil, fmt.Errorf("memory: invalid pattern %q: %w", pattern, err)
+	}
+	now := time.Now()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	keys := make([]string, 0)
+	for key, el := range c.items {
+		if el.Value.(*entry).expired(now) {
+			continue
+		}
+		if ok, _ := path.Match(pattern, key); ok {
+			keys = append(keys, key)
+		}
+	}
+	return keys, nil
+}
+
+// TTL 返回键的剩余过期时间，永不过期时返回-1，键不存在时返回 ErrMiss
+func (c *Cache) TTL(key string) (time.Duration, error) {
+	now := time.Now()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	el, ok := c.items[key]
+	if !ok || el.Value.(*entry).expired(now) {
+		return 0, ErrMiss
+	}
+	if e := el.Value.(*entry); !e.expireAt.IsZero() {
+		return e.expireAt.Sub(now), nil
+	}
+	return -1, nil
+}
+
+// Len 当前条目数，包括尚未清理的过期条目
+func (c *Cache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.ll.Len()
+}
+
+// Clear 清空缓存，统计计数不清零
+func (c *Cache) Clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.ll.Init()
+	c.items = make(map[string]*list.Element)
+	c.bytes = 0
+}
+
+// Stats 获取统计信息
+func (c *Cache) Stats() Stats {
+	c.mu.Lock()
+	entries, bytes := c.ll.Len(), c.bytes
+	c.mu.Unlock()
+	return Stats{
+		Hits:      c.hits.Load(),
+		Misses:    c.misses.Load(),
+		Evictions: c.evictions.Load(),
+		Expired:   c.expired.Load(),
+		Entries:   entries,
+		Bytes:     bytes,
+	}
+}
+
+// SetAs 保存任意类型的值，不做序列化，GetAs 取出的是同一个值，调用方不应再修改
+func SetAs[T any](c *Cache, key string, value T, expiration time.Duration) {
+	var size int64
+	switch v := any(value).(type) {
+	case string:
+		size = int64(len(v))
+	case []byte:
+		size = int64(len(v))
+	default:
+		size = 64
+		if c.config.SizeFunc != nil {
+			size = c.config.SizeFunc(key, value)
+		}
+	}
+	c.set(key, value, size, expiration)
+}
+
+// GetAs 获取指定类型的值。SetAs 写入的同类型值直接返回，Set 写入的值按 JSON 解析（T 为 string、[]byte 时直接转换），
+// 键不存在时返回 ErrMiss
+func GetAs[T any](c *Cache, key string) (T, error) {
+	var zero T
+	v, ok := c.get(key)
+	if !ok {
+		return zero, ErrMiss
+	}
+	if t, ok := v.(T); ok {
+		return t, nil
+	}
+	b, err := encode(v)
+	if err != nil {
+		return zero, err
+	}
+	switch p := any(&zero).(type) {
+	case *string:
+		*p = string(b)
+		return zero, nil
+	case *[]byte:
+		*p = b
+		return zero, nil
+	}
+	if err := json.Unmarshal(b, &zero); err != nil {
+		return zero, fmt.Errorf("memory: failed to decode %s as %T: %w", key, zero, err)
+	}
+	return zero, nil
+}
+
+func (c *Cache) get(key string) (interface{}, bool) {
+	c.mu.Lock()
+	el, ok := c.items[key]
+	if !ok {
+		c.mu.Unlock()
+		c.misses.Add(1)
+		return nil, false
+	}
+	e := el.Value.(*entry)
+	if e.expired(time.Now()) {
+		c.removeElement(el)
+		c.mu.Unlock()
+		c.expired.Add(1)
+		c.misses.Add(1)
+		return nil, false
+	}
+	c.ll.MoveToFront(el)
+	c.mu.Unlock()
+	c.hits.Add(1)
+	return e.value, true
+}
+
+func (c *Cache) set(key string, value interface{}, size int64, expiration time.Duration) {
+	if expiration <= 0 {
+		expiration = c.config.DefaultTTL
+	}
+	e := &entry{key: key, value: value, size: int64(len(key)) + size}
+	if expiration > 0 {
+		e.expireAt = time.Now().Add(expiration)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if el, ok := c.items[key]; ok {
+		c.bytes -= el.Value.(*entry).size
+		el.Value = e
+		c.ll.MoveToFront(el)
+	} else {
+		c.items[key] = c.ll.PushFront(e)
+	}
+	c.bytes += e.size
+
+	// 超出限制时从尾部淘汰，刚写入的条目即使单独超过 MaxBytes 也保留
+	for c.ll.Len() > 1 && (c.ll.Len() > c.config.MaxEntries || c.config.MaxBytes > 0 && c.bytes > c.config.MaxBytes) {
+		c.removeElement(c.ll.Back())
+		c.evictions.Add(1)
+	}
+}
+
+func (c *Cache) removeElement(el *list.Element) {
+	e := c.ll.Remove(el).(*entry)
+	delete(c.items, e.key)
+	c.bytes -= e.size
+}
+
+// encode 按 go-redis 写入参数的规则转为字节，无法直接转换的类型使用 JSON
+func encode(value interface{}) ([]byte, error) {
+	switch v := value.(type) {
+	case nil:
+		return []byte{}, nil
+	case []byte:
+		return v, nil
+	case string:
+		return []byte(v), nil
+	case int:
+		return strconv.AppendInt(nil, int64(v), 10), nil
+	case int8:
+		return strconv.AppendInt(nil, int64(v), 10), nil
+	case int16:
+		return strconv.AppendInt(nil, int64(v), 10), nil
+	case int32:
+		return strconv.AppendInt(nil, int64(v), 10), nil
+	case int64:
+		return strconv.AppendInt(nil, v, 10), nil
+	case uint:
+		return strconv.AppendUint(nil, uint64(v), 10), nil
+	case uint8:
+		return strconv.AppendUint(nil, uint64(v), 10), nil
+	case uint16:
+		return strconv.AppendUint(nil, uint64(v), 10), nil
+	case uint32:
+		return strconv.AppendUint(nil, uint64(v), 10), nil
+	case uint64:
+		return strconv.AppendUint(nil, v, 10), nil
+	case float32:
+		return strconv.AppendFloat(nil, float64(v), 'f', -1, 32), nil
+	case float64:
+		return strconv.AppendFloat(nil, v, 'f', -1, 64), nil
+	case bool:
+		if v {
+			return []byte("1"), nil
+		}
+		return []byte("0"), nil
+	case time.Time:
+		return v.AppendFormat(nil, time.RFC3339Nano), nil
+	case time.Duration:
+		return strconv.AppendInt(nil, v.Nanoseconds(), 10), nil
+	case encoding.BinaryMarshaler:
+		return v.MarshalBinary()
+	}
+	b, err := json.Marshal(value)
+	if err != nil {
+		return nil, fmt.Errorf("memory: failed to marshal %T: %w", value, err)
+	}
+	return b, nil
+}
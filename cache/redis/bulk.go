@@ -0,0 +1,126 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	goredis "github.com/redis/go-redis/v9"
+)
+
+// MGet 批量获取，返回存在的键及其值，不存在的键不出现在结果中。
+// 集群模式下按槽位分组，每组一条 MGET，通过流水线按节点批量发送
+func (r *RedisClient) MGet(keys ...string) (map[string]string, error) {
+	return r.MGetCtx(ctx, keys...)
+}
+
+// MGetCtx 批量获取，见 MGet
+func (r *RedisClient) MGetCtx(ctx context.Context, keys ...string) (map[string]string, error) {
+	result := make(map[string]string, len(keys))
+	if len(keys) == 0 {
+		return result, nil
+	}
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+
+	groups := r.groupBySlot(keys)
+	pipe := r.Client().Pipeline()
+	cmds := make([]*goredis.SliceCmd, len(groups))
+	for i, g := range groups {
+		cmds[i] = pipe.MGet(ctx, r.keys(g)...)
+	}
+	if _, err := pipe.Exec(ctx); err != nil && !errors.Is(err, goredis.Nil) {
+		return nil, err
+	}
+	for i, cmd := range cmds {
+		for j, v := range cmd.Val() {
+			if s, ok := v.(string); ok {
+				result[groups[i][j]] = s
+			}
+		}
+	}
+	return result, nil
+}
+
+// MSet 批量设置，expiration 为0时永不过期。集群模式下按槽位分组；设置了过期时间时每个键一条 SET，
+// 均通过流水线发送，一个节点一次往返。批量写入不是原子的，出错时部分键可能已写入
+func (r *RedisClient) MSet(pairs map[string]interface{}, expiration time.Duration) error {
+	return r.MSetCtx(ctx, pairs, expiration)
+}
+
+// MSetCtx 批量设置，见 MSet
+func (r *RedisClient) MSetCtx(ctx context.Context, pairs map[string]interface{}, expiration time.Duration) error {
+	if len(pairs) == 0 {
+		return nil
+	}
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+
+	pipe := r.Client().Pipeline()
+	if expiration > 0 {
+		for k, v := range pairs {
+			pipe.Set(ctx, r.key(k), v, expiration)
+		}
+	} else {
+		keys := make([]string, 0, len(pairs))
+		for k := range pairs {
+			keys = append(keys, k)
+		}
+		for _, g := range r.groupBySlot(keys) {
+			values := make([]interface{}, 0, len(g)*2)
+			for _, k := range g {
+				values = append(values, r.key(k), pairs[k])
+			}
+			pipe.MSet(ctx, values...)
+		}
+	}
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+// groupBySlot 按加前缀后的键所在槽位分组，非集群模式下整体为一组
+func (r *RedisClient) groupBySlot(keys []string) [][]string {
+	if !r.isCluster {
+		return [][]string{keys}
+	}
+	index := make(map[int]int)
+	var groups [][]string
+	for _, k := range keys {
+		slot := keySlot(r.key(k))
+		i, ok := index[slot]
+		if !ok {
+			i = len(groups)
+			index[slot] = i
+			groups = append(groups, nil)
+		}
+		groups[i] = append(groups[i], k)
+	}
+	return groups
+}
+
+// keySlot 计算键的集群槽位，键包含非空的 {hashtag} 时只计算花括号内的部分
+func keySlot(key string) int {
+	if start := strings.IndexByte(key, '{'); start >= 0 {
+		if end := strings.IndexByte(key[start+1:], '}'); end > 0 {
+			key = key[start+1 : start+1+end]
+		}
+	}
+	return int(crc16(key) % clusterSlots)
+}
+
+// crc16 Redis Cluster 使用的 CRC16-XMODEM
+func crc16(s string) uint16 {
+	var crc uint16
+	for i := 0; i < len(s); i++ {
+		crc ^= uint16(s[i]) << 8
+		for j := 0; j < 8; j++ {
+			if crc&0x8000 != 0 {
+				crc = crc<<1 ^ 0x1021
+			} else {
+				crc <<= 1
+			}
+		}
+	}
+	return crc
+}
@@ -0,0 +1,191 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	goredis "github.com/redis/go-redis/v9"
+)
+
+// clusterSlots Redis Cluster 的槽位总数
+const clusterSlots = 16384
+
+// connState 底层 go-redis 客户端，由 RedisClient 及其副本共享，自动重连时整体替换
+type connState struct {
+	build func() goredis.UniversalClient
+
+	mu         sync.RWMutex
+	current    goredis.UniversalClient
+	hooks      []goredis.Hook
+	listeners  map[uint64]func()
+	nextID     uint64
+	closed     bool
+	reconnects atomic.Uint64
+
+	stop chan struct{}
+	once sync.Once
+}
+
+func newConnState(build func() goredis.UniversalClient) *connState {
+	return &connState{build: build, current: build(), stop: make(chan struct{})}
+}
+
+func (c *connState) client() goredis.UniversalClient {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.current
+}
+
+func (c *connState) addHook(hook goredis.Hook) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.hooks = append(c.hooks, hook)
+	c.current.AddHook(hook)
+}
+
+// onReconnect 注册重连回调，返回取消注册的函数
+func (c *connState) onReconnect(fn func()) func() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.listeners == nil {
+		c.listeners = make(map[uint64]func())
+	}
+	c.nextID++
+	id := c.nextID
+	c.listeners[id] = fn
+	return func() {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		delete(c.listeners, id)
+	}
+}
+
+// reconnect 新建客户端并重新注册钩子，替换后关闭旧客户端并通知重连回调，旧客户端上执行中的命令会返回错误
+func (c *connState) reconnect() {
+	next := c.build()
+	c.mu.Lock()
+	if c.closed {
+		c.mu.Unlock()
+		next.Close()
+		return
+	}
+	for _, hook := range c.hooks {
+		next.AddHook(hook)
+	}
+	old := c.current
+	c.current = next
+	listeners := make([]func(), 0, len(c.listeners))
+	for _, fn := range c.listeners {
+		listeners = append(listeners, fn)
+	}
+	c.mu.Unlock()
+	c.reconnects.Add(1)
+	old.Close()
+	for _, fn := range listeners {
+		fn()
+	}
+}
+
+// monitor 定期 Ping，连续失败 threshold 次后重建客户端
+func (c *connState) monitor(interval time.Duration, threshold int) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	failures := 0
+	for {
+		select {
+		case <-c.stop:
+			return
+		case <-ticker.C:
+		}
+		pctx, cancel := context.WithTimeout(context.Background(), interval)
+		err := c.client().Ping(pctx).Err()
+		cancel()
+		if err == nil {
+			failures = 0
+			continue
+		}
+		failures++
+		log.Printf("Redis Ping 失败(%d/%d): %v", failures, threshold, err)
+		if failures >= threshold {
+			log.Println("Redis 连续 Ping 失败，重建客户端")
+			c.reconnect()
+			failures = 0
+		}
+	}
+}
+
+func (c *connState) close() error {
+	var err error
+	c.once.Do(func() {
+		close(c.stop)
+		c.mu.Lock()
+		c.closed = true
+		c.mu.Unlock()
+		err = c.current.Close()
+	})
+	return err
+}
+
+// Status Redis 连接状态
+type Status struct {
+	Latency      time.Duration // Ping 耗时
+	Cluster      bool          // 是否为集群模式
+	TotalConns   uint32        // 连接池中的连接数（集群模式为所有节点之和）
+	IdleConns    uint32        // 空闲连接数
+	StaleConns   uint32        // 因过期被关闭的连接数
+	Hits         uint32        // 从连接池取到空闲连接的次数
+	Misses       uint32        // 连接池没有空闲连接需新建的次数
+	Timeouts     uint32        // 等待连接池超时的次数
+	SlotsCovered int           // 集群模式下已分配节点的槽位数，正常为16384
+	Reconnects   uint64        // 自动重连次数
+}
+
+// Health 检查连接状态，Ping 失败或集群槽位未完全覆盖时返回错误，Status 中仍包含已获取的信息
+func (r *RedisClient) Health(ctx context.Context) (Status, error) {
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+	client := r.Client()
+	status := Status{Cluster: r.isCluster, Reconnects: r.conn.reconnects.Load()}
+	if stats := client.PoolStats(); stats != nil {
+		status.TotalConns = stats.TotalConns
+		status.IdleConns = stats.IdleConns
+		status.StaleConns = stats.StaleConns
+		status.Hits = stats.Hits
+		status.Misses = stats.Misses
+		status.Timeouts = stats.Timeouts
+	}
+
+	start := time.Now()
+	if err := client.Ping(ctx).Err(); err != nil {
+		return status, fmt.Errorf("redis ping failed: %w", err)
+	}
+	status.Latency = time.Since(start)
+
+	if r.isCluster {
+		slots, err := client.ClusterSlots(ctx).Result()
+		if err != nil {
+			return status, fmt.Errorf("failed to get cluster slots: %w", err)
+		}
+		for _, s := range slots {
+			if len(s.Nodes) > 0 {
+				status.SlotsCovered += int(s.End - s.Start + 1)
+			}
+		}
+		if status.SlotsCovered < clusterSlots {
+			return status, fmt.Errorf("redis cluster slots not fully covered: %d/%d", status.SlotsCovered, clusterSlots)
+		}
+	}
+	return status, nil
+}
+
+// Close 停止后台检查并关闭所有连接，WithTimeout 返回的副本共享连接，也将不可用
+func (r *RedisClient) Close() error {
+	if r.conn == nil {
+		return nil
+	}
+	return r.conn.close()
+}
@@ -0,0 +1,141 @@
+package redis
+
+import (
+	"context"
+	"time"
+
+	goredis "github.com/redis/go-redis/v9"
+)
+
+// GeoLocation 地理位置，Longitude/Latitude 为经纬度，Dist 为查询结果中与中心点的距离
+type GeoLocation = goredis.GeoLocation
+
+// GeoSearchQuery GEOSEARCH 查询条件，以 Member 或 Longitude/Latitude 为中心，按 Radius 或 BoxWidth/BoxHeight 范围查询
+type GeoSearchQuery = goredis.GeoSearchQuery
+
+// GeoAdd 添加或更新成员位置
+func (r *RedisClient) GeoAdd(key string, locations ...*GeoLocation) error {
+	return r.GeoAddCtx(ctx, key, locations...)
+}
+
+// GeoAddCtx 添加或更新成员位置
+func (r *RedisClient) GeoAddCtx(ctx context.Context, key string, locations ...*GeoLocation) error {
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+	return r.Client().GeoAdd(ctx, r.key(key), locations...).Err()
+}
+
+// GeoSearch 查询范围内的成员名称
+func (r *RedisClient) GeoSearch(key string, query *GeoSearchQuery) ([]string, error) {
+	return r.GeoSearchCtx(ctx, key, query)
+}
+
+// GeoSearchCtx 查询范围内的成员名称
+func (r *RedisClient) GeoSearchCtx(ctx context.Context, key string, query *GeoSearchQuery) ([]string, error) {
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+	return r.Client().GeoSearch(ctx, r.key(key), query).Result()
+}
+
+// GeoSearchLocation 查询范围内的成员，结果包含经纬度和与中心点的距离（单位同查询条件）
+func (r *RedisClient) GeoSearchLocation(key string, query *GeoSearchQuery) ([]GeoLocation, error) {
+	return r.GeoSearchLocationCtx(ctx, key, query)
+}
+
+// GeoSearchLocationCtx 查询范围内的成员，结果包含经纬度和与中心点的距离
+func (r *RedisClient) GeoSearchLocationCtx(ctx context.Context, key string, query *GeoSearchQuery) ([]GeoLocation, error) {
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+	return r.Client().GeoSearchLocation(ctx, r.key(key), &goredis.GeoSearchLocationQuery{
+		GeoSearchQuery: *query,
+		WithCoord:      true,
+		WithDist:       true,
+	}).Result()
+}
+
+// GeoRadius 查询以经纬度为中心、radius 范围内的成员，按距离由近到远排序，unit 为 m/km/ft/mi（默认 km），count 为0时不限制数量
+func (r *RedisClient) GeoRadius(key string, longitude, latitude, radius float64, unit string, count int) ([]GeoLocation, error) {
+	return r.GeoSearchLocationCtx(ctx, key, &GeoSearchQuery{
+		Longitude:  longitude,
+		Latitude:   latitude,
+		Radius:     radius,
+		RadiusUnit: unit,
+		Sort:       "ASC",
+		Count:      count,
+	})
+}
+
+// GeoDist 计算两个成员的距离，unit 为 m/km/ft/mi（默认 km），成员不存在时返回 redis.Nil
+func (r *RedisClient) GeoDist(key, member1, member2, unit string) (float64, error) {
+	return r.GeoDistCtx(ctx, key, member1, member2, unit)
+}
+
+// GeoDistCtx 计算两个成员的距离
+func (r *RedisClient) GeoDistCtx(ctx context.Context, key, member1, member2, unit string) (float64, error) {
+	if unit == "" {
+		unit = "km"
+	}
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+	return r.Client().GeoDist(ctx, r.key(key), member1, member2, unit).Result()
+}
+
+// GeoPos 获取成员经纬度，不存在的成员对应 nil
+func (r *RedisClient) GeoPos(key string, members ...string) ([]*goredis.GeoPos, error) {
+	return r.GeoPosCtx(ctx, key, members...)
+}
+
+// GeoPosCtx 获取成员经纬度，不存在的成员对应 nil
+func (r *RedisClient) GeoPosCtx(ctx context.Context, key string, members ...string) ([]*goredis.GeoPos, error) {
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+	return r.Client().GeoPos(ctx, r.key(key), members...).Result()
+}
+
+// PFAdd 向 HyperLogLog 添加元素，用于 UV 等基数统计
+func (r *RedisClient) PFAdd(key string, elements ...interface{}) error {
+	return r.PFAddCtx(ctx, key, elements...)
+}
+
+// PFAddCtx 向 HyperLogLog 添加元素
+func (r *RedisClient) PFAddCtx(ctx context.Context, key string, elements ...interface{}) error {
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+	return r.Client().PFAdd(ctx, r.key(key), elements...).Err()
+}
+
+// PFCount 统计 HyperLogLog 的近似基数（标准误差0.81%），多个键时返回并集的基数；集群模式下多个键需位于同一槽位
+func (r *RedisClient) PFCount(keys ...string) (int64, error) {
+	return r.PFCountCtx(ctx, keys...)
+}
+
+// PFCountCtx 统计 HyperLogLog 的近似基数
+func (r *RedisClient) PFCountCtx(ctx context.Context, keys ...string) (int64, error) {
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+	return r.Client().PFCount(ctx, r.keys(keys)...).Result()
+}
+
+// PFMerge 合并多个 HyperLogLog 到 dest，如将每日 UV 合并为每周 UV；集群模式下所有键需位于同一槽位
+func (r *RedisClient) PFMerge(dest string, keys ...string) error {
+	return r.PFMergeCtx(ctx, dest, keys...)
+}
+
+// PFMergeCtx 合并多个 HyperLogLog 到 dest
+func (r *RedisClient) PFMergeCtx(ctx context.Context, dest string, keys ...string) error {
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+	return r.Client().PFMerge(ctx, r.key(dest), r.keys(keys)...).Err()
+}
+
+// PFAddExpire 添加元素并设置过期时间，适合按天分键的 UV 统计
+func (r *RedisClient) PFAddExpire(key string, expiration time.Duration, elements ...interface{}) error {
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+	pipe := r.Client().TxPipeline()
+	key = r.key(key)
+	pipe.PFAdd(ctx, key, elements...)
+	pipe.Expire(ctx, key, expiration)
+	_, err := pipe.Exec(ctx)
+	return err
+}
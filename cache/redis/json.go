@@ -0,0 +1,77 @@
+package redis
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+)
+
+// SetJSON 将 v 序列化为 JSON 后写入，长度超过 Config.CompressThreshold 时使用 gzip 压缩
+func (r *RedisClient) SetJSON(key string, v interface{}, expiration time.Duration) error {
+	return r.SetJSONCtx(ctx, key, v, expiration)
+}
+
+// SetJSONCtx 将 v 序列化为 JSON 后写入，见 SetJSON
+func (r *RedisClient) SetJSONCtx(ctx context.Context, key string, v interface{}, expiration time.Duration) error {
+	data, err := r.encodeJSON(key, v)
+	if err != nil {
+		return err
+	}
+	return r.SetCtx(ctx, key, data, expiration)
+}
+
+// GetJSON 读取 JSON 值并解析到 dest，自动识别 gzip 压缩的值；键不存在时返回 redis.Nil，解析失败时返回错误
+func (r *RedisClient) GetJSON(key string, dest interface{}) error {
+	return r.GetJSONCtx(ctx, key, dest)
+}
+
+// GetJSONCtx 读取 JSON 值并解析到 dest，见 GetJSON
+func (r *RedisClient) GetJSONCtx(ctx context.Context, key string, dest interface{}) error {
+	cctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+	data, err := r.Client().Get(cctx, r.key(key)).Bytes()
+	if err != nil {
+		return err
+	}
+	return decodeJSON(key, data, dest)
+}
+
+// encodeJSON 序列化为 JSON，超过压缩阈值时使用 gzip 压缩
+func (r *RedisClient) encodeJSON(key string, v interface{}) ([]byte, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal %s: %w", key, err)
+	}
+	if r.compressThreshold > 0 && len(data) > r.compressThreshold {
+		var buf bytes.Buffer
+		zw := gzip.NewWriter(&buf)
+		zw.Write(data)
+		if err := zw.Close(); err != nil {
+			return nil, fmt.Errorf("failed to compress %s: %w", key, err)
+		}
+		data = buf.Bytes()
+	}
+	return data, nil
+}
+
+// decodeJSON 解析 encodeJSON 写入的值
+func decodeJSON(key string, data []byte, dest interface{}) error {
+	// JSON 文本不会以 gzip 魔数开头，可据此区分压缩值
+	if len(data) >= 2 && data[0] == 0x1f && data[1] == 0x8b {
+		zr, err := gzip.NewReader(bytes.NewReader(data))
+		if err != nil {
+			return fmt.Errorf("failed to decompress %s: %w", key, err)
+		}
+		if data, err = io.ReadAll(zr); err != nil {
+			return fmt.Errorf("failed to decompress %s: %w", key, err)
+		}
+	}
+	if err := json.Unmarshal(data, dest); err != nil {
+		return fmt.Errorf("failed to decode %s: %w", key, err)
+	}
+	return nil
+}
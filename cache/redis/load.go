@@ -0,0 +1,69 @@
+package redis
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"time"
+
+	goredis "github.com/redis/go-redis/v9"
+)
+
+// ErrNotFound 由 GetOrLoad 的 loader 返回，表示数据不存在；结果会按 NegativeTTL 缓存，期间不再调用 loader
+var ErrNotFound = errors.New("redis: not found")
+
+// negativeValue 不存在标记，JSON 和 gzip 数据都不会以0字节开头
+var negativeValue = []byte("\x00notfound")
+
+// GetOrLoad 读取 JSON 缓存并解析到 dest，未命中时调用 loader 加载并以 ttl 写入。
+// 同一进程内相同键的并发加载只执行一次；loader 返回 ErrNotFound 时缓存不存在标记（时长为 Config.NegativeTTL，默认 ttl 的1/10），
+// 过期前的调用直接返回 ErrNotFound
+func (r *RedisClient) GetOrLoad(key string, ttl time.Duration, loader func() (interface{}, error), dest interface{}) error {
+	return r.GetOrLoadCtx(ctx, key, ttl, loader, dest)
+}
+
+// GetOrLoadCtx 读取缓存，未命中时加载，见 GetOrLoad
+func (r *RedisClient) GetOrLoadCtx(ctx context.Context, key string, ttl time.Duration, loader func() (interface{}, error), dest interface{}) error {
+	data, err := r.GetBytesCtx(ctx, key)
+	if errors.Is(err, goredis.Nil) {
+		v, lerr, _ := r.group.Do(r.key(key), func() (interface{}, error) {
+			// 等待期间其他请求可能已写入
+			if data, err := r.GetBytesCtx(ctx, key); err == nil {
+				return data, nil
+			}
+			v, err := loader()
+			if errors.Is(err, ErrNotFound) {
+				// 写缓存失败不影响本次结果
+				_ = r.SetCtx(ctx, key, negativeValue, r.negativeTTL(ttl))
+				return negativeValue, nil
+			}
+			if err != nil {
+				return nil, err
+			}
+			data, err := r.encodeJSON(key, v)
+			if err != nil {
+				return nil, err
+			}
+			_ = r.SetCtx(ctx, key, data, ttl)
+			return data, nil
+		})
+		if lerr != nil {
+			return lerr
+		}
+		data, err = v.([]byte), nil
+	}
+	if err != nil {
+		return err
+	}
+	if bytes.Equal(data, negativeValue) {
+		return ErrNotFound
+	}
+	return decodeJSON(key, data, dest)
+}
+
+func (r *RedisClient) negativeTTL(ttl time.Duration) time.Duration {
+	if r.negTTL > 0 {
+		return r.negTTL
+	}
+	return max(ttl/10, time.Second)
+}
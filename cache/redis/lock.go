@@ -0,0 +1,200 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/ixxmi/tools/utils/randx"
+	goredis "github.com/redis/go-redis/v9"
+)
+
+var (
+	// ErrLockNotAcquired 锁已被其他持有者占用
+	ErrLockNotAcquired = errors.New("redis: lock not acquired")
+	// ErrLockNotHeld 锁已过期或已被其他持有者占用
+	ErrLockNotHeld = errors.New("redis: lock not held")
+)
+
+var (
+	// lockScript 占用锁成功时递增并返回栅栏令牌，失败返回0
+	lockScript = goredis.NewScript(`
+if redis.call("SET", KEYS[1], ARGV[1], "NX", "PX", ARGV[2]) then
+	return redis.call("INCR", KEYS[2])
+end
+return 0`)
+	// lockRefreshScript 仅当锁仍属于自己时续期
+	lockRefreshScript = goredis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("PEXPIRE", KEYS[1], ARGV[2])
+end
+return 0`)
+	// unlockScript 仅当锁仍属于自己时删除
+	unlockScript = goredis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0`)
+)
+
+// LockOption 加锁选项
+type LockOption func(*Lock)
+
+// WithoutRenewal 不自动续期，锁在 ttl 后过期，适合"每个周期最多执行一次"的定时任务
+func WithoutRenewal() LockOption {
+	return func(l *Lock) {
+		l.renew = false
+	}
+}
+
+// Lock 分布式锁。锁键为 lock:{key}，栅栏令牌计数器为 lock:{key}:fence，哈希标签保证集群模式下位于同一槽位。
+// 默认启动看门狗每 ttl/3 续期一次，进程崩溃时锁在 ttl 后自动释放
+type Lock struct {
+	rc    *RedisClient
+	key   string
+	fence string
+	value string
+	ttl   time.Duration
+	token int64
+	renew bool
+
+	once sync.Once
+	stop chan struct{}
+	lost chan struct{}
+	done chan struct{}
+}
+
+// Lock 尝试获取锁，已被占用时返回 ErrLockNotAcquired
+func (r *RedisClient) Lock(key string, ttl time.Duration, opts ...LockOption) (*Lock, error) {
+	return r.LockCtx(ctx, key, ttl, opts...)
+}
+
+// LockCtx 尝试获取锁，已被占用时返回 ErrLockNotAcquired
+func (r *RedisClient) LockCtx(ctx context.Context, key string, ttl time.Duration, opts ...LockOption) (*Lock, error) {
+	if ttl <= 0 {
+		return nil, fmt.Errorf("redis: lock ttl must be positive")
+	}
+	l := &Lock{
+		rc:    r,
+		key:   r.key("lock:{" + key + "}"),
+		fence: r.key("lock:{" + key + "}:fence"),
+		value: randx.SecureTokenHex(16),
+		ttl:   ttl,
+		renew: true,
+		stop:  make(chan struct{}),
+		lost:  make(chan struct{}),
+		done:  make(chan struct{}),
+	}
+	for _, opt := range opts {
+		opt(l)
+	}
+
+	cctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+	token, err := lockScript.Run(cctx, r.Client(), []string{l.key, l.fence}, l.value, ttl.Milliseconds()).Int64()
+	if err != nil {
+		return nil, fmt.Errorf("failed to acquire lock %s: %w", key, err)
+	}
+	if token == 0 {
+		return nil, ErrLockNotAcquired
+	}
+	l.token = token
+	if l.renew {
+		go l.watchdog()
+	} else {
+		close(l.done)
+	}
+	return l, nil
+}
+
+// TryLockWithRetry 获取锁，被占用时每隔 delay（附加最多 delay/2 的随机抖动）重试，最多重试 retries 次
+func (r *RedisClient) TryLockWithRetry(ctx context.Context, key string, ttl time.Duration, retries int, delay time.Duration, opts ...LockOption) (*Lock, error) {
+	for i := 0; ; i++ {
+		l, err := r.LockCtx(ctx, key, ttl, opts...)
+		if !errors.Is(err, ErrLockNotAcquired) || i >= retries {
+			return l, err
+		}
+		wait := delay
+		if delay > 1 {
+			wait += time.Duration(randx.Intn(int(delay / 2)))
+		}
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(wait):
+		}
+	}
+}
+
+// Token 栅栏令牌，同一个 key 每次加锁成功后严格递增。
+// 写入外部存储时携带令牌并拒绝比已见过的更小的令牌，可避免锁过期后旧持有者的延迟写入
+func (l *Lock) Token() int64 {
+	return l.token
+}
+
+// Lost 看门狗续期失败（锁已过期或被他人占用）时关闭，持有者应停止受保护的操作
+func (l *Lock) Lost() <-chan struct{} {
+	return l.lost
+}
+
+// Refresh 手动续期 ttl，锁已不属于自己时返回 ErrLockNotHeld
+func (l *Lock) Refresh(ctx context.Context) error {
+	ctx, cancel := l.rc.withTimeout(ctx)
+	defer cancel()
+	n, err := lockRefreshScript.Run(ctx, l.rc.Client(), []string{l.key}, l.value, l.ttl.Milliseconds()).Int()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return ErrLockNotHeld
+	}
+	return nil
+}
+
+// Unlock 停止续期并释放锁，锁已过期或被他人占用时返回 ErrLockNotHeld
+func (l *Lock) Unlock() error {
+	return l.UnlockCtx(ctx)
+}
+
+// UnlockCtx 停止续期并释放锁，锁已过期或被他人占用时返回 ErrLockNotHeld
+func (l *Lock) UnlockCtx(ctx context.Context) error {
+	l.once.Do(func() { close(l.stop) })
+	<-l.done
+	ctx, cancel := l.rc.withTimeout(ctx)
+	defer cancel()
+	n, err := unlockScript.Run(ctx, l.rc.Client(), []string{l.key}, l.value).Int()
+	if err != nil {
+		return fmt.Errorf("failed to release lock: %w", err)
+	}
+	if n == 0 {
+		return ErrLockNotHeld
+	}
+	return nil
+}
+
+// watchdog 每 ttl/3 续期一次，锁丢失时关闭 lost；网络错误时继续重试直到锁过期
+func (l *Lock) watchdog() {
+	defer close(l.done)
+	ticker := time.NewTicker(max(l.ttl/3, time.Millisecond))
+	defer ticker.Stop()
+	deadline := time.Now().Add(l.ttl)
+	for {
+		select {
+		case <-l.stop:
+			return
+		case <-ticker.C:
+		}
+		rctx, cancel := context.WithTimeout(context.Background(), l.ttl/3+time.Millisecond)
+		err := l.Refresh(rctx)
+		cancel()
+		switch {
+		case err == nil:
+			deadline = time.Now().Add(l.ttl)
+		case errors.Is(err, ErrLockNotHeld) || time.Now().After(deadline):
+			close(l.lost)
+			return
+		}
+	}
+}
@@ -2,164 +2,415 @@ package redis
 
 import (
 	"context"
-	"encoding/json"
+	"crypto/tls"
+	"crypto/x509"
 	"fmt"
 	"log"
+	"os"
+	"strings"
 	"time"
 
+	"github.com/ixxmi/tools/utils"
+
 	goredis "github.com/redis/go-redis/v9"
+	"golang.org/x/sync/singleflight"
 )
 
 var (
-	ctx = context.Background()
-	RC  = RedisClient{} // 全局 Redis 客户端实例
+	ctx = context.Background() // 不带 ctx 的方法使用的上下文
+	RC  = RedisClient{}        // 全局 Redis 客户端实例
 )
 
+// Config Redis 配置，可通过 config.Loader 从配置文件、环境变量加载
 type Config struct {
-	Addrs     []string
-	Password  string
-	DB        int
-	IsCluster bool
+	Addrs     []string `env:"REDIS_ADDRS" default:"127.0.0.1:6379"`
+	Username  string   `env:"REDIS_USERNAME"` // ACL 用户名，Redis 6 以上使用
+	Password  string   `env:"REDIS_PASSWORD"`
+	DB        int      `env:"REDIS_DB"`
+	IsCluster bool     `env:"REDIS_CLUSTER"`
+	KeyPrefix string   `env:"REDIS_KEY_PREFIX"` // 键前缀，如 svc 时 user:1 实际为 svc:user:1，见 WithPrefix
+
+	DialTimeout  time.Duration // 建立连接超时，默认5秒
+	ReadTimeout  time.Duration // 读超时，默认3秒
+	WriteTimeout time.Duration // 写超时，默认与读超时相同
+	Timeout      time.Duration // 每次调用的默认超时，不带 ctx 的方法和 ctx 未设置截止时间时生效，0表示不限制
+
+	PoolSize     int `env:"REDIS_POOL_SIZE"` // 每个节点的最大连接数，默认为 CPU 数的10倍
+	MinIdleConns int // 每个节点保持的最小空闲连接数
+
+	TLS                bool        `env:"REDIS_TLS"`          // 启用 TLS，设置了证书文件时自动启用
+	CertFile           string      `env:"REDIS_TLS_CERT"`     // 客户端证书，双向认证时使用
+	KeyFile            string      `env:"REDIS_TLS_KEY"`      // 客户端私钥
+	CAFile             string      `env:"REDIS_TLS_CA"`       // 服务端 CA 证书，为空时使用系统根证书
+	InsecureSkipVerify bool        `env:"REDIS_TLS_INSECURE"` // 跳过服务端证书校验
+	ServerName         string      // 校验证书使用的主机名，默认取连接地址
+	TLSConfig          *tls.Config // 直接指定 TLS 配置，优先于上面的 TLS 字段
+
+	CompressThreshold int           // SetJSON 写入的 JSON 超过该字节数时使用 gzip 压缩，0表示不压缩
+	NegativeTTL       time.Duration // GetOrLoad 缓存不存在结果的时长，默认为 ttl 的1/10（至少1秒）
+
+	HealthCheckInterval time.Duration // 后台 Ping 的间隔，0表示不检查
+	ReconnectThreshold  int           // 连续 Ping 失败多少次后重建客户端，默认3
+}
+
+// tlsConfig 根据配置构建 TLS 配置，未启用时返回 nil
+func (cfg Config) tlsConfig() (*tls.Config, error) {
+	if cfg.TLSConfig != nil {
+		return cfg.TLSConfig, nil
+	}
+	if !cfg.TLS && cfg.CertFile == "" && cfg.CAFile == "" {
+		return nil, nil
+	}
+	tc := &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		ServerName:         cfg.ServerName,
+		InsecureSkipVerify: cfg.InsecureSkipVerify,
+	}
+	if cfg.CertFile != "" || cfg.KeyFile != "" {
+		cert, err := tls.LoadX509KeyPair(cfg.CertFile, cfg.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load redis client certificate: %w", err)
+		}
+		tc.Certificates = []tls.Certificate{cert}
+	}
+	if cfg.CAFile != "" {
+		pem, err := os.ReadFile(cfg.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read redis CA file: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no valid certificate in redis CA file %s", cfg.CAFile)
+		}
+		tc.RootCAs = pool
+	}
+	return tc, nil
 }
 
 type RedisClient struct {
-	clusterClient *goredis.ClusterClient
-	singleClient  *goredis.Client
-	isCluster     bool
+	conn      *connState
+	isCluster bool
+	timeout   time.Duration
+
+	compressThreshold int
+	scripts           *scriptRegistry
+	prefix            string
+	group             *singleflight.Group
+	negTTL            time.Duration
 }
 
-// NewRedis 创建 Redis 客户端
+// NewRedis 创建 Redis 客户端，并设置为全局 RC
 func NewRedis(cfg Config) (*RedisClient, error) {
-	client := &RedisClient{isCluster: cfg.IsCluster}
+	client, err := NewClient(cfg)
+	if err != nil {
+		return nil, err
+	}
+	RC = *client
+	return client, nil
+}
+
+// NewClient 创建 Redis 客户端，不修改全局 RC，供需要独立客户端的组件使用，由调用方负责 Close
+func NewClient(cfg Config) (*RedisClient, error) {
+	client := &RedisClient{
+		isCluster:         cfg.IsCluster,
+		timeout:           cfg.Timeout,
+		compressThreshold: cfg.CompressThreshold,
+		prefix:            strings.TrimSuffix(cfg.KeyPrefix, ":"),
+		group:             &singleflight.Group{},
+		negTTL:            cfg.NegativeTTL,
+		scripts:           &scriptRegistry{scripts: make(map[string]*goredis.Script)},
+	}
+
+	if len(cfg.Addrs) == 0 {
+		return nil, fmt.Errorf("redis: addrs required")
+	}
+	tlsConfig, err := cfg.tlsConfig()
+	if err != nil {
+		return nil, err
+	}
 
 	if cfg.IsCluster {
-		client.clusterClient = goredis.NewClusterClient(&goredis.ClusterOptions{
-			Addrs:    cfg.Addrs,
-			Password: cfg.Password,
-		})
-		if err := client.clusterClient.Ping(ctx).Err(); err != nil {
+		opts := &goredis.ClusterOptions{
+			Addrs:        cfg.Addrs,
+			Username:     cfg.Username,
+			Password:     cfg.Password,
+			DialTimeout:  cfg.DialTimeout,
+			ReadTimeout:  cfg.ReadTimeout,
+			WriteTimeout: cfg.WriteTimeout,
+			PoolSize:     cfg.PoolSize,
+			MinIdleConns: cfg.MinIdleConns,
+			TLSConfig:    tlsConfig,
+		}
+		client.conn = newConnState(func() goredis.UniversalClient { return goredis.NewClusterClient(opts) })
+		if err := client.conn.client().Ping(ctx).Err(); err != nil {
+			client.conn.close()
 			return nil, fmt.Errorf("连接 Redis Cluster 失败: %v", err)
 		}
 	} else {
-		client.singleClient = goredis.NewClient(&goredis.Options{
-			Addr:     cfg.Addrs[0],
-			Password: cfg.Password,
-			DB:       cfg.DB,
-		})
-		if err := client.singleClient.Ping(ctx).Err(); err != nil {
+		opts := &goredis.Options{
+			Addr:         cfg.Addrs[0],
+			Username:     cfg.Username,
+			Password:     cfg.Password,
+			DB:           cfg.DB,
+			DialTimeout:  cfg.DialTimeout,
+			ReadTimeout:  cfg.ReadTimeout,
+			WriteTimeout: cfg.WriteTimeout,
+			PoolSize:     cfg.PoolSize,
+			MinIdleConns: cfg.MinIdleConns,
+			TLSConfig:    tlsConfig,
+		}
+		client.conn = newConnState(func() goredis.UniversalClient { return goredis.NewClient(opts) })
+		if err := client.conn.client().Ping(ctx).Err(); err != nil {
+			client.conn.close()
 			return nil, fmt.Errorf("连接 Redis 单节点失败: %v", err)
 		}
 	}
+	if cfg.HealthCheckInterval > 0 {
+		threshold := cfg.ReconnectThreshold
+		if threshold <= 0 {
+			threshold = 3
+		}
+		go client.conn.monitor(cfg.HealthCheckInterval, threshold)
+	}
 
-	RC = *client
 	log.Println("Redis 客户端连接成功")
 	return client, nil
 }
 
+// WithTimeout 返回使用指定调用超时的客户端副本，与原客户端共享连接，如 RC.WithTimeout(time.Second).Get(key)
+func (r *RedisClient) WithTimeout(timeout time.Duration) *RedisClient {
+	c := *r
+	c.timeout = timeout
+	return &c
+}
+
+// WithPrefix 返回为所有键追加前缀的客户端副本，与原客户端共享连接。前缀与键之间使用 utils.JoinRedisKey 以冒号连接，
+// 已有前缀时继续追加，如 RC.WithPrefix("svc").WithPrefix("cache") 的键为 svc:cache:key。
+// 前缀作用于 RedisClient 的方法（包括 Keys/Scan 的匹配模式和返回值、脚本的 KEYS），不作用于 Client() 返回的原始客户端
+func (r *RedisClient) WithPrefix(prefix string) *RedisClient {
+	c := *r
+	if prefix = strings.TrimSuffix(prefix, ":"); prefix != "" {
+		if c.prefix != "" {
+			prefix = utils.JoinRedisKey(c.prefix, prefix)
+		}
+		c.prefix = prefix
+	}
+	return &c
+}
+
+// key 追加键前缀
+func (r *RedisClient) key(key string) string {
+	if r.prefix == "" {
+		return key
+	}
+	return utils.JoinRedisKey(r.prefix, key)
+}
+
+// keys 追加键前缀，返回新切片
+func (r *RedisClient) keys(keys []string) []string {
+	if r.prefix == "" {
+		return keys
+	}
+	full := make([]string, len(keys))
+	for i, k := range keys {
+		full[i] = r.key(k)
+	}
+	return full
+}
+
+// stripKey 去掉键前缀
+func (r *RedisClient) stripKey(key string) string {
+	if r.prefix == "" {
+		return key
+	}
+	return strings.TrimPrefix(key, r.prefix+":")
+}
+
+// withTimeout ctx 没有截止时间且设置了调用超时时附加超时
+func (r *RedisClient) withTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	if r.timeout <= 0 {
+		return ctx, func() {}
+	}
+	if _, ok := ctx.Deadline(); ok {
+		return ctx, func() {}
+	}
+	return context.WithTimeout(ctx, r.timeout)
+}
+
 // Set 设置键值
 func (r *RedisClient) Set(key string, value interface{}, expiration time.Duration) error {
-	if r.isCluster {
-		return r.clusterClient.Set(ctx, key, value, expiration).Err()
-	}
-	return r.singleClient.Set(ctx, key, value, expiration).Err()
+	return r.SetCtx(ctx, key, value, expiration)
+}
+
+// SetCtx 设置键值
+func (r *RedisClient) SetCtx(ctx context.Context, key string, value interface{}, expiration time.Duration) error {
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+	return r.Client().Set(ctx, r.key(key), value, expiration).Err()
 }
 
 // Get 获取值
 func (r *RedisClient) Get(key string) (string, error) {
-	if r.isCluster {
-		return r.clusterClient.Get(ctx, key).Result()
-	}
-	return r.singleClient.Get(ctx, key).Result()
+	return r.GetCtx(ctx, key)
+}
+
+// GetCtx 获取值
+func (r *RedisClient) GetCtx(ctx context.Context, key string) (string, error) {
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+	return r.Client().Get(ctx, r.key(key)).Result()
+}
+
+// GetBytes 获取原始字节值
+func (r *RedisClient) GetBytes(key string) ([]byte, error) {
+	return r.GetBytesCtx(ctx, key)
+}
+
+// GetBytesCtx 获取原始字节值
+func (r *RedisClient) GetBytesCtx(ctx context.Context, key string) ([]byte, error) {
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+	return r.Client().Get(ctx, r.key(key)).Bytes()
 }
 
-// Get 获取MAP值
+// GetMap 获取MAP值，值不是 JSON 对象时返回错误
 func (r *RedisClient) GetMap(key string) (map[string]interface{}, error) {
-	if r.isCluster {
-		result, err := r.clusterClient.Get(ctx, key).Result()
-		if err != nil {
-			return nil, err
-		}
-		data := map[string]interface{}{}
-		json.Unmarshal([]byte(result), &data)
-		return data, nil
-	}
-	result, err := r.singleClient.Get(ctx, key).Result()
-	if err != nil {
+	return r.GetMapCtx(ctx, key)
+}
+
+// GetMapCtx 获取MAP值
+func (r *RedisClient) GetMapCtx(ctx context.Context, key string) (map[string]interface{}, error) {
+	data := map[string]interface{}{}
+	if err := r.GetJSONCtx(ctx, key, &data); err != nil {
 		return nil, err
 	}
-	data := map[string]interface{}{}
-	json.Unmarshal([]byte(result), &data)
 	return data, nil
 }
 
-// Get 获取MAP数组值
+// GetMaps 获取MAP数组值，值不是 JSON 数组时返回错误
 func (r *RedisClient) GetMaps(key string) ([]map[string]interface{}, error) {
-	if r.isCluster {
-		result, err := r.clusterClient.Get(ctx, key).Result()
-		if err != nil {
-			return nil, err
-		}
-		data := []map[string]interface{}{}
-		json.Unmarshal([]byte(result), &data)
-		return data, nil
-	}
-	result, err := r.singleClient.Get(ctx, key).Result()
-	if err != nil {
+	return r.GetMapsCtx(ctx, key)
+}
+
+// GetMapsCtx 获取MAP数组值
+func (r *RedisClient) GetMapsCtx(ctx context.Context, key string) ([]map[string]interface{}, error) {
+	data := []map[string]interface{}{}
+	if err := r.GetJSONCtx(ctx, key, &data); err != nil {
 		return nil, err
 	}
-	data := []map[string]interface{}{}
-	json.Unmarshal([]byte(result), &data)
 	return data, nil
 }
 
 // Del 删除键
 func (r *RedisClient) Del(keys ...string) error {
-	if r.isCluster {
-		return r.clusterClient.Del(ctx, keys...).Err()
-	}
-	return r.singleClient.Del(ctx, keys...).Err()
+	return r.DelCtx(ctx, keys...)
+}
+
+// DelCtx 删除键
+func (r *RedisClient) DelCtx(ctx context.Context, keys ...string) error {
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+	return r.Client().Del(ctx, r.keys(keys)...).Err()
 }
 
 // Exists 判断键是否存在
 func (r *RedisClient) Exists(key string) (bool, error) {
-	var n int64
-	var err error
-	if r.isCluster {
-		n, err = r.clusterClient.Exists(ctx, key).Result()
-	} else {
-		n, err = r.singleClient.Exists(ctx, key).Result()
-	}
+	return r.ExistsCtx(ctx, key)
+}
+
+// ExistsCtx 判断键是否存在
+func (r *RedisClient) ExistsCtx(ctx context.Context, key string) (bool, error) {
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+	n, err := r.Client().Exists(ctx, r.key(key)).Result()
 	return n > 0, err
 }
 
 // HSet 设置哈希字段
 func (r *RedisClient) HSet(key string, values ...interface{}) error {
-	if r.isCluster {
-		return r.clusterClient.HSet(ctx, key, values...).Err()
-	}
-	return r.singleClient.HSet(ctx, key, values...).Err()
+	return r.HSetCtx(ctx, key, values...)
+}
+
+// HSetCtx 设置哈希字段
+func (r *RedisClient) HSetCtx(ctx context.Context, key string, values ...interface{}) error {
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+	return r.Client().HSet(ctx, r.key(key), values...).Err()
 }
 
 // HGet 获取哈希字段
 func (r *RedisClient) HGet(key, field string) (string, error) {
-	if r.isCluster {
-		return r.clusterClient.HGet(ctx, key, field).Result()
-	}
-	return r.singleClient.HGet(ctx, key, field).Result()
+	return r.HGetCtx(ctx, key, field)
+}
+
+// HGetCtx 获取哈希字段
+func (r *RedisClient) HGetCtx(ctx context.Context, key, field string) (string, error) {
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+	return r.Client().HGet(ctx, r.key(key), field).Result()
 }
 
 // HDel 删除哈希字段
 func (r *RedisClient) HDel(key string, fields ...string) error {
-	if r.isCluster {
-		return r.clusterClient.HDel(ctx, key, fields...).Err()
-	}
-	return r.singleClient.HDel(ctx, key, fields...).Err()
+	return r.HDelCtx(ctx, key, fields...)
+}
+
+// HDelCtx 删除哈希字段
+func (r *RedisClient) HDelCtx(ctx context.Context, key string, fields ...string) error {
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+	return r.Client().HDel(ctx, r.key(key), fields...).Err()
 }
 
-// Keys 获取匹配的 key 列表（仅支持单节点）
+// Keys 获取匹配的 key 列表（仅支持单节点，KEYS 会阻塞 Redis，大数据量时使用 Scan）
 func (r *RedisClient) Keys(pattern string) ([]string, error) {
+	return r.KeysCtx(ctx, pattern)
+}
+
+// KeysCtx 获取匹配的 key 列表（仅支持单节点）
+func (r *RedisClient) KeysCtx(ctx context.Context, pattern string) ([]string, error) {
 	if r.isCluster {
 		return nil, fmt.Errorf("Keys 命令不支持 Redis Cluster")
 	}
-	return r.singleClient.Keys(ctx, pattern).Result()
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+	keys, err := r.Client().Keys(ctx, r.key(pattern)).Result()
+	for i := range keys {
+		keys[i] = r.stripKey(keys[i])
+	}
+	return keys, err
+}
+
+// AddHook 注册 go-redis 钩子，如 tracing.NewRedisHook()，自动重连后仍然生效
+func (r *RedisClient) AddHook(hook goredis.Hook) {
+	r.conn.addHook(hook)
+}
+
+// OnReconnect 注册自动重连回调，在新客户端替换旧客户端并关闭旧客户端后调用，用于重建 Pub/Sub 订阅等
+// 与连接绑定的状态；返回的函数用于取消注册
+func (r *RedisClient) OnReconnect(fn func()) (cancel func()) {
+	return r.conn.onReconnect(fn)
+}
+
+// Client 返回底层 go-redis 客户端，单节点和集群模式均实现 UniversalClient，用于未封装的命令。
+// 自动重连后旧客户端会被关闭，调用方不应长期持有返回值，应在每次操作时重新获取
+func (r *RedisClient) Client() goredis.UniversalClient {
+	return r.conn.client()
+}
+
+// cluster 集群客户端，仅在 isCluster 时调用
+func (r *RedisClient) cluster() *goredis.ClusterClient {
+	return r.conn.client().(*goredis.ClusterClient)
+}
+
+// single 单节点客户端，仅在非集群时调用
+func (r *RedisClient) single() *goredis.Client {
+	return r.conn.client().(*goredis.Client)
+}
+
+// Ping 检查连接是否可用
+func (r *RedisClient) Ping(ctx context.Context) error {
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+	return r.Client().Ping(ctx).Err()
 }
@@ -0,0 +1,87 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	goredis "github.com/redis/go-redis/v9"
+)
+
+// ErrStopScan 在 Scan 回调中返回以提前结束遍历，Scan 返回 nil
+var ErrStopScan = errors.New("redis: stop scan")
+
+// Scan 使用 SCAN 遍历匹配 pattern 的键，count 为每批的提示数量（默认100）。
+// 集群模式下遍历所有主节点，回调串行执行无需加锁；与 SCAN 语义一致，同一个键可能被回调多次。
+// 回调返回错误时停止遍历并返回该错误
+func (r *RedisClient) Scan(pattern string, count int64, fn func(key string) error) error {
+	return r.ScanCtx(ctx, pattern, count, fn)
+}
+
+// ScanCtx 使用 SCAN 遍历匹配 pattern 的键，见 Scan
+func (r *RedisClient) ScanCtx(ctx context.Context, pattern string, count int64, fn func(key string) error) error {
+	if count <= 0 {
+		count = 100
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var mu sync.Mutex
+	var stopErr error
+	call := func(key string) error {
+		mu.Lock()
+		defer mu.Unlock()
+		if stopErr != nil {
+			return stopErr
+		}
+		if err := fn(r.stripKey(key)); err != nil {
+			stopErr = err
+			cancel()
+			return err
+		}
+		return nil
+	}
+
+	var err error
+	if r.isCluster {
+		err = r.cluster().ForEachMaster(ctx, func(ctx context.Context, node *goredis.Client) error {
+			return scanNode(ctx, node, r.key(pattern), count, call)
+		})
+	} else {
+		err = scanNode(ctx, r.single(), r.key(pattern), count, call)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if stopErr != nil {
+		if errors.Is(stopErr, ErrStopScan) {
+			return nil
+		}
+		return stopErr
+	}
+	return err
+}
+
+func scanNode(ctx context.Context, node *goredis.Client, pattern string, count int64, fn func(key string) error) error {
+	iter := node.Scan(ctx, 0, pattern, count).Iterator()
+	for iter.Next(ctx) {
+		if err := fn(iter.Val()); err != nil {
+			return err
+		}
+	}
+	return iter.Err()
+}
+
+// ScanKeys 使用 SCAN 收集所有匹配的键（已去重），集群模式下同样可用，可替代 Keys
+func (r *RedisClient) ScanKeys(ctx context.Context, pattern string) ([]string, error) {
+	seen := make(map[string]struct{})
+	keys := make([]string, 0)
+	err := r.ScanCtx(ctx, pattern, 0, func(key string) error {
+		if _, ok := seen[key]; !ok {
+			seen[key] = struct{}{}
+			keys = append(keys, key)
+		}
+		return nil
+	})
+	return keys, err
+}
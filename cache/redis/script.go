@@ -0,0 +1,99 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	goredis "github.com/redis/go-redis/v9"
+)
+
+// ScriptResult 脚本执行结果，可用 Int64()/Text()/Bool()/StringSlice()/Slice() 等方法取值，或用 DecodeScript 解析到任意类型
+type ScriptResult = goredis.Cmd
+
+// scriptRegistry 已注册的脚本，由 RedisClient 及其 WithTimeout 副本共享
+type scriptRegistry struct {
+	mu      sync.RWMutex
+	scripts map[string]*goredis.Script
+}
+
+// RegisterScript 注册 Lua 脚本并预加载到所有节点，同名脚本会被替换
+func (r *RedisClient) RegisterScript(name, src string) error {
+	return r.RegisterScriptCtx(ctx, name, src)
+}
+
+// RegisterScriptCtx 注册 Lua 脚本并预加载到所有节点，见 RegisterScript
+func (r *RedisClient) RegisterScriptCtx(ctx context.Context, name, src string) error {
+	if r.scripts == nil {
+		return fmt.Errorf("redis: client not initialized")
+	}
+	script := goredis.NewScript(src)
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+	var err error
+	if r.isCluster {
+		err = r.cluster().ForEachShard(ctx, func(ctx context.Context, node *goredis.Client) error {
+			return script.Load(ctx, node).Err()
+		})
+	} else {
+		err = script.Load(ctx, r.single()).Err()
+	}
+	if err != nil {
+		return fmt.Errorf("failed to load script %s: %w", name, err)
+	}
+
+	r.scripts.mu.Lock()
+	r.scripts.scripts[name] = script
+	r.scripts.mu.Unlock()
+	return nil
+}
+
+// RunScript 执行已注册的脚本。优先使用 EVALSHA，节点上没有脚本缓存（如集群故障切换、SCRIPT FLUSH 后）
+// 返回 NOSCRIPT 时自动改用 EVAL 重新加载。集群模式下 keys 需位于同一槽位
+func (r *RedisClient) RunScript(name string, keys []string, args ...interface{}) *ScriptResult {
+	return r.RunScriptCtx(ctx, name, keys, args...)
+}
+
+// RunScriptCtx 执行已注册的脚本，见 RunScript
+func (r *RedisClient) RunScriptCtx(ctx context.Context, name string, keys []string, args ...interface{}) *ScriptResult {
+	var script *goredis.Script
+	if r.scripts != nil {
+		r.scripts.mu.RLock()
+		script = r.scripts.scripts[name]
+		r.scripts.mu.RUnlock()
+	}
+	if script == nil {
+		return goredis.NewCmdResult(nil, fmt.Errorf("redis: script %s not registered", name))
+	}
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+	return script.Run(ctx, r.Client(), r.keys(keys), args...)
+}
+
+// DecodeScript 将脚本结果解析到 dest：脚本返回的字符串在 dest 不是 *string 时按 JSON（cjson.encode）解析，
+// 其他结果（整数、数组等）按 JSON 语义转换，如整数数组可解析到 *[]int。脚本返回 nil 时返回 redis.Nil
+func DecodeScript(res *ScriptResult, dest interface{}) error {
+	v, err := res.Result()
+	if err != nil {
+		return err
+	}
+	if s, ok := v.(string); ok {
+		if p, ok := dest.(*string); ok {
+			*p = s
+			return nil
+		}
+		if err := json.Unmarshal([]byte(s), dest); err != nil {
+			return fmt.Errorf("failed to decode script result: %w", err)
+		}
+		return nil
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("failed to decode script result: %w", err)
+	}
+	if err := json.Unmarshal(b, dest); err != nil {
+		return fmt.Errorf("failed to decode script result: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,286 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	goredis "github.com/redis/go-redis/v9"
+)
+
+// ErrMaxDeliveries 消息投递次数超过 StreamConsumerConfig.MaxDeliveries
+var ErrMaxDeliveries = errors.New("redis: stream message exceeded max deliveries")
+
+// StreamMessage 流消息
+type StreamMessage = goredis.XMessage
+
+// XAdd 向流追加消息，maxLen 大于0时按近似长度裁剪旧消息，返回消息 ID
+func (r *RedisClient) XAdd(ctx context.Context, stream string, values map[string]interface{}, maxLen int64) (string, error) {
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+	args := &goredis.XAddArgs{Stream: r.key(stream), Values: values}
+	if maxLen > 0 {
+		args.MaxLen = maxLen
+		args.Approx = true
+	}
+	return r.Client().XAdd(ctx, args).Result()
+}
+
+// XGroupCreate 创建消费组，start 为 $ 时只消费之后的新消息，为 0 时从头消费；流不存在时自动创建，组已存在不报错
+func (r *RedisClient) XGroupCreate(ctx context.Context, stream, group, start string) error {
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+	err := r.Client().XGroupCreateMkStream(ctx, r.key(stream), group, start).Err()
+	if err != nil && !strings.HasPrefix(err.Error(), "BUSYGROUP") {
+		return err
+	}
+	return nil
+}
+
+// XReadGroup 以消费组方式读取分配给 consumer 的新消息，block 为0时一直阻塞，小于0时不阻塞；
+// 超时没有消息时返回空切片。阻塞读取不受 Config.Timeout 限制
+func (r *RedisClient) XReadGroup(ctx context.Context, stream, group, consumer string, count int64, block time.Duration) ([]StreamMessage, error) {
+	streams, err := r.Client().XReadGroup(ctx, &goredis.XReadGroupArgs{
+		Group:    group,
+		Consumer: consumer,
+		Streams:  []string{r.key(stream), ">"},
+		Count:    count,
+		Block:    block,
+	}).Result()
+	if errors.Is(err, goredis.Nil) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var msgs []StreamMessage
+	for _, s := range streams {
+		msgs = append(msgs, s.Messages...)
+	}
+	return msgs, nil
+}
+
+// XAck 确认消息已处理
+func (r *RedisClient) XAck(ctx context.Context, stream, group string, ids ...string) error {
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+	return r.Client().XAck(ctx, r.key(stream), group, ids...).Err()
+}
+
+// StreamHandler 消息处理函数，返回 nil 时确认消息，返回错误时消息保持待确认，空闲超过 MinIdle 后被重新投递
+type StreamHandler func(ctx context.Context, msg StreamMessage) error
+
+// StreamConsumerConfig 流消费者配置
+type StreamConsumerConfig struct {
+	Stream          string        // 流名称
+	Group           string        // 消费组名称
+	Consumer        string        // 消费者名称，同一组内需唯一，默认 主机名-进程号
+	StartID         string        // 创建消费组时的起始位置，默认 $ 只消费新消息，0 表示从头消费
+	Concurrency     int           // 并发处理数，默认1，处理中的消息达到上限时暂停读取
+	BatchSize       int64         // 每次读取的最大消息数，默认10
+	Block           time.Duration // 没有新消息时的阻塞时间，默认5秒
+	MinIdle         time.Duration // 待确认消息空闲超过该时间后由本消费者认领重新处理，默认1分钟
+	ReclaimInterval time.Duration // 检查待确认消息的周期，默认30秒
+	MaxDeliveries   int64         // 最大投递次数，超过后转存到 DeadStream 并确认，0表示不限制
+	DeadStream      string        // 超过最大投递次数的消息转存的流，为空时直接确认丢弃
+	DeadMaxLen      int64         // DeadStream 的近似最大长度，0表示不裁剪
+}
+
+// StreamConsumer 基于消费组的流消费者，负责创建消费组、并发处理、确认、回收超时的待确认消息和死信转存
+type StreamConsumer struct {
+	rc      *RedisClient
+	config  StreamConsumerConfig
+	handler StreamHandler
+	sem     chan struct{}
+	wg      sync.WaitGroup
+	OnError func(msg *StreamMessage, err error) // 处理失败或读取失败回调，msg 为 nil 表示与具体消息无关，默认输出日志
+}
+
+// NewStreamConsumer 创建流消费者
+func NewStreamConsumer(rc *RedisClient, config StreamConsumerConfig, handler StreamHandler) *StreamConsumer {
+	if config.Consumer == "" {
+		host, _ := os.Hostname()
+		config.Consumer = host + "-" + strconv.Itoa(os.Getpid())
+	}
+	if config.StartID == "" {
+		config.StartID = "$"
+	}
+	if config.Concurrency <= 0 {
+		config.Concurrency = 1
+	}
+	if config.BatchSize <= 0 {
+		config.BatchSize = 10
+	}
+	if config.Block <= 0 {
+		config.Block = 5 * time.Second
+	}
+	if config.MinIdle <= 0 {
+		config.MinIdle = time.Minute
+	}
+	if config.ReclaimInterval <= 0 {
+		config.ReclaimInterval = 30 * time.Second
+	}
+	return &StreamConsumer{
+		rc:      rc,
+		config:  config,
+		handler: handler,
+		sem:     make(chan struct{}, config.Concurrency),
+	}
+}
+
+// Run 创建消费组并持续消费，直到 ctx 被取消；返回前等待处理中的消息完成
+func (c *StreamConsumer) Run(ctx context.Context) error {
+	if err := c.rc.XGroupCreate(ctx, c.config.Stream, c.config.Group, c.config.StartID); err != nil {
+		return fmt.Errorf("failed to create consumer group %s: %w", c.config.Group, err)
+	}
+	defer c.wg.Wait()
+
+	var lastReclaim time.Time
+	for ctx.Err() == nil {
+		if time.Since(lastReclaim) >= c.config.ReclaimInterval {
+			lastReclaim = time.Now()
+			if err := c.reclaim(ctx); err != nil && ctx.Err() == nil {
+				c.reportError(nil, fmt.Errorf("failed to reclaim pending messages: %w", err))
+			}
+		}
+
+		// 只读取空闲处理槽位数量的消息，处理跟不上时不再从 Redis 拉取
+		count := min(c.config.BatchSize, int64(cap(c.sem)-len(c.sem)))
+		if count <= 0 {
+			count = 1
+		}
+		block := min(c.config.Block, c.config.ReclaimInterval)
+		msgs, err := c.rc.XReadGroup(ctx, c.config.Stream, c.config.Group, c.config.Consumer, count, block)
+		if err != nil {
+			if ctx.Err() != nil {
+				break
+			}
+			c.reportError(nil, fmt.Errorf("failed to read stream %s: %w", c.config.Stream, err))
+			select {
+			case <-ctx.Done():
+			case <-time.After(time.Second):
+			}
+			continue
+		}
+		for _, msg := range msgs {
+			if !c.dispatch(ctx, msg) {
+				break
+			}
+		}
+	}
+	return nil
+}
+
+// dispatch 等待空闲槽位后处理消息，ctx 取消时返回 false，未处理的消息保持待确认
+func (c *StreamConsumer) dispatch(ctx context.Context, msg StreamMessage) bool {
+	select {
+	case c.sem <- struct{}{}:
+	case <-ctx.Done():
+		return false
+	}
+	c.wg.Add(1)
+	go func() {
+		defer func() {
+			<-c.sem
+			c.wg.Done()
+		}()
+		if err := c.handler(ctx, msg); err != nil {
+			c.reportError(&msg, err)
+			return
+		}
+		// ctx 已取消时仍需确认已处理完成的消息
+		if err := c.rc.XAck(context.WithoutCancel(ctx), c.config.Stream, c.config.Group, msg.ID); err != nil {
+			c.reportError(&msg, fmt.Errorf("failed to ack message: %w", err))
+		}
+	}()
+	return true
+}
+
+// reclaim 转存超过最大投递次数的消息，并认领空闲超过 MinIdle 的待确认消息重新处理
+func (c *StreamConsumer) reclaim(ctx context.Context) error {
+	if c.config.MaxDeliveries > 0 {
+		pending, err := c.rc.Client().XPendingExt(ctx, &goredis.XPendingExtArgs{
+			Stream: c.rc.key(c.config.Stream),
+			Group:  c.config.Group,
+			Idle:   c.config.MinIdle,
+			Start:  "-",
+			End:    "+",
+			Count:  100,
+		}).Result()
+		if err != nil {
+			return err
+		}
+		for _, p := range pending {
+			if p.RetryCount >= c.config.MaxDeliveries {
+				if err := c.dead(ctx, p.ID); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	start := "0-0"
+	for {
+		msgs, next, err := c.rc.Client().XAutoClaim(ctx, &goredis.XAutoClaimArgs{
+			Stream:   c.rc.key(c.config.Stream),
+			Group:    c.config.Group,
+			Consumer: c.config.Consumer,
+			MinIdle:  c.config.MinIdle,
+			Start:    start,
+			Count:    c.config.BatchSize,
+		}).Result()
+		if err != nil {
+			return err
+		}
+		for _, msg := range msgs {
+			if !c.dispatch(ctx, msg) {
+				return nil
+			}
+		}
+		if next == "0-0" || len(msgs) == 0 {
+			return nil
+		}
+		start = next
+	}
+}
+
+// dead 将消息转存到死信流并确认
+func (c *StreamConsumer) dead(ctx context.Context, id string) error {
+	msgs, err := c.rc.Client().XRangeN(ctx, c.rc.key(c.config.Stream), id, id, 1).Result()
+	if err != nil {
+		return err
+	}
+	if len(msgs) > 0 {
+		msg := msgs[0]
+		if c.config.DeadStream != "" {
+			values := make(map[string]interface{}, len(msg.Values)+1)
+			for k, v := range msg.Values {
+				values[k] = v
+			}
+			values["_source_id"] = msg.ID
+			if _, err := c.rc.XAdd(ctx, c.config.DeadStream, values, c.config.DeadMaxLen); err != nil {
+				return fmt.Errorf("failed to move message %s to %s: %w", id, c.config.DeadStream, err)
+			}
+		}
+		c.reportError(&msg, ErrMaxDeliveries)
+	}
+	return c.rc.XAck(ctx, c.config.Stream, c.config.Group, id)
+}
+
+func (c *StreamConsumer) reportError(msg *StreamMessage, err error) {
+	if c.OnError != nil {
+		c.OnError(msg, err)
+		return
+	}
+	if msg != nil {
+		log.Printf("redis stream %s: message %s: %v", c.config.Stream, msg.ID, err)
+		return
+	}
+	log.Printf("redis stream %s: %v", c.config.Stream, err)
+}
@@ -0,0 +1,334 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	goredis "github.com/redis/go-redis/v9"
+)
+
+// Z 有序集合成员
+type Z = goredis.Z
+
+// Incr 自增1，返回自增后的值
+func (r *RedisClient) Incr(key string) (int64, error) {
+	return r.IncrCtx(ctx, key)
+}
+
+// IncrCtx 自增1，返回自增后的值
+func (r *RedisClient) IncrCtx(ctx context.Context, key string) (int64, error) {
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+	return r.Client().Incr(ctx, r.key(key)).Result()
+}
+
+// IncrBy 增加 value，返回增加后的值
+func (r *RedisClient) IncrBy(key string, value int64) (int64, error) {
+	return r.IncrByCtx(ctx, key, value)
+}
+
+// IncrByCtx 增加 value，返回增加后的值
+func (r *RedisClient) IncrByCtx(ctx context.Context, key string, value int64) (int64, error) {
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+	return r.Client().IncrBy(ctx, r.key(key), value).Result()
+}
+
+// Decr 自减1，返回自减后的值
+func (r *RedisClient) Decr(key string) (int64, error) {
+	return r.DecrCtx(ctx, key)
+}
+
+// DecrCtx 自减1，返回自减后的值
+func (r *RedisClient) DecrCtx(ctx context.Context, key string) (int64, error) {
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+	return r.Client().Decr(ctx, r.key(key)).Result()
+}
+
+// DecrBy 减少 value，返回减少后的值
+func (r *RedisClient) DecrBy(key string, value int64) (int64, error) {
+	return r.DecrByCtx(ctx, key, value)
+}
+
+// DecrByCtx 减少 value，返回减少后的值
+func (r *RedisClient) DecrByCtx(ctx context.Context, key string, value int64) (int64, error) {
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+	return r.Client().DecrBy(ctx, r.key(key), value).Result()
+}
+
+// SetNX 键不存在时设置，返回是否设置成功
+func (r *RedisClient) SetNX(key string, value interface{}, expiration time.Duration) (bool, error) {
+	return r.SetNXCtx(ctx, key, value, expiration)
+}
+
+// SetNXCtx 键不存在时设置，返回是否设置成功
+func (r *RedisClient) SetNXCtx(ctx context.Context, key string, value interface{}, expiration time.Duration) (bool, error) {
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+	return r.Client().SetNX(ctx, r.key(key), value, expiration).Result()
+}
+
+// GetSet 设置新值并返回旧值，键不存在时返回 redis.Nil
+func (r *RedisClient) GetSet(key string, value interface{}) (string, error) {
+	return r.GetSetCtx(ctx, key, value)
+}
+
+// GetSetCtx 设置新值并返回旧值，键不存在时返回 redis.Nil
+func (r *RedisClient) GetSetCtx(ctx context.Context, key string, value interface{}) (string, error) {
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+	return r.Client().GetSet(ctx, r.key(key), value).Result()
+}
+
+// Expire 设置过期时间，键不存在时返回 false
+func (r *RedisClient) Expire(key string, expiration time.Duration) (bool, error) {
+	return r.ExpireCtx(ctx, key, expiration)
+}
+
+// ExpireCtx 设置过期时间，键不存在时返回 false
+func (r *RedisClient) ExpireCtx(ctx context.Context, key string, expiration time.Duration) (bool, error) {
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+	return r.Client().Expire(ctx, r.key(key), expiration).Result()
+}
+
+// TTL 获取剩余过期时间，永不过期时返回 time.Duration(-1)，键不存在时返回 time.Duration(-2)，与 go-redis 一致
+func (r *RedisClient) TTL(key string) (time.Duration, error) {
+	return r.TTLCtx(ctx, key)
+}
+
+// TTLCtx 获取剩余过期时间，永不过期时返回 time.Duration(-1)，键不存在时返回 time.Duration(-2)，与 go-redis 一致
+func (r *RedisClient) TTLCtx(ctx context.Context, key string) (time.Duration, error) {
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+	return r.Client().TTL(ctx, r.key(key)).Result()
+}
+
+// LPush 从列表头部插入，返回插入后的列表长度
+func (r *RedisClient) LPush(key string, values ...interface{}) (int64, error) {
+	return r.LPushCtx(ctx, key, values...)
+}
+
+// LPushCtx 从列表头部插入，返回插入后的列表长度
+func (r *RedisClient) LPushCtx(ctx context.Context, key string, values ...interface{}) (int64, error) {
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+	return r.Client().LPush(ctx, r.key(key), values...).Result()
+}
+
+// RPush 从列表尾部插入，返回插入后的列表长度
+func (r *RedisClient) RPush(key string, values ...interface{}) (int64, error) {
+	return r.RPushCtx(ctx, key, values...)
+}
+
+// RPushCtx 从列表尾部插入，返回插入后的列表长度
+func (r *RedisClient) RPushCtx(ctx context.Context, key string, values ...interface{}) (int64, error) {
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+	return r.Client().RPush(ctx, r.key(key), values...).Result()
+}
+
+// LPop 从列表头部弹出，列表为空时返回 redis.Nil
+func (r *RedisClient) LPop(key string) (string, error) {
+	return r.LPopCtx(ctx, key)
+}
+
+// LPopCtx 从列表头部弹出，列表为空时返回 redis.Nil
+func (r *RedisClient) LPopCtx(ctx context.Context, key string) (string, error) {
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+	return r.Client().LPop(ctx, r.key(key)).Result()
+}
+
+// RPop 从列表尾部弹出，列表为空时返回 redis.Nil
+func (r *RedisClient) RPop(key string) (string, error) {
+	return r.RPopCtx(ctx, key)
+}
+
+// RPopCtx 从列表尾部弹出，列表为空时返回 redis.Nil
+func (r *RedisClient) RPopCtx(ctx context.Context, key string) (string, error) {
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+	return r.Client().RPop(ctx, r.key(key)).Result()
+}
+
+// LRange 获取列表 [start, stop] 范围的元素，-1 表示最后一个
+func (r *RedisClient) LRange(key string, start, stop int64) ([]string, error) {
+	return r.LRangeCtx(ctx, key, start, stop)
+}
+
+// LRangeCtx 获取列表 [start, stop] 范围的元素，-1 表示最后一个
+func (r *RedisClient) LRangeCtx(ctx context.Context, key string, start, stop int64) ([]string, error) {
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+	return r.Client().LRange(ctx, r.key(key), start, stop).Result()
+}
+
+// LLen 获取列表长度
+func (r *RedisClient) LLen(key string) (int64, error) {
+	return r.LLenCtx(ctx, key)
+}
+
+// LLenCtx 获取列表长度
+func (r *RedisClient) LLenCtx(ctx context.Context, key string) (int64, error) {
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+	return r.Client().LLen(ctx, r.key(key)).Result()
+}
+
+// SAdd 向集合添加成员
+func (r *RedisClient) SAdd(key string, members ...interface{}) error {
+	return r.SAddCtx(ctx, key, members...)
+}
+
+// SAddCtx 向集合添加成员
+func (r *RedisClient) SAddCtx(ctx context.Context, key string, members ...interface{}) error {
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+	return r.Client().SAdd(ctx, r.key(key), members...).Err()
+}
+
+// SRem 从集合删除成员
+func (r *RedisClient) SRem(key string, members ...interface{}) error {
+	return r.SRemCtx(ctx, key, members...)
+}
+
+// SRemCtx 从集合删除成员
+func (r *RedisClient) SRemCtx(ctx context.Context, key string, members ...interface{}) error {
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+	return r.Client().SRem(ctx, r.key(key), members...).Err()
+}
+
+// SMembers 获取集合全部成员
+func (r *RedisClient) SMembers(key string) ([]string, error) {
+	return r.SMembersCtx(ctx, key)
+}
+
+// SMembersCtx 获取集合全部成员
+func (r *RedisClient) SMembersCtx(ctx context.Context, key string) ([]string, error) {
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+	return r.Client().SMembers(ctx, r.key(key)).Result()
+}
+
+// SIsMember 判断是否为集合成员
+func (r *RedisClient) SIsMember(key string, member interface{}) (bool, error) {
+	return r.SIsMemberCtx(ctx, key, member)
+}
+
+// SIsMemberCtx 判断是否为集合成员
+func (r *RedisClient) SIsMemberCtx(ctx context.Context, key string, member interface{}) (bool, error) {
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+	return r.Client().SIsMember(ctx, r.key(key), member).Result()
+}
+
+// SCard 获取集合成员数
+func (r *RedisClient) SCard(key string) (int64, error) {
+	return r.SCardCtx(ctx, key)
+}
+
+// SCardCtx 获取集合成员数
+func (r *RedisClient) SCardCtx(ctx context.Context, key string) (int64, error) {
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+	return r.Client().SCard(ctx, r.key(key)).Result()
+}
+
+// ZAdd 向有序集合添加成员，成员已存在时更新分数
+func (r *RedisClient) ZAdd(key string, members ...Z) error {
+	return r.ZAddCtx(ctx, key, members...)
+}
+
+// ZAddCtx 向有序集合添加成员，成员已存在时更新分数
+func (r *RedisClient) ZAddCtx(ctx context.Context, key string, members ...Z) error {
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+	return r.Client().ZAdd(ctx, r.key(key), members...).Err()
+}
+
+// ZRem 从有序集合删除成员
+func (r *RedisClient) ZRem(key string, members ...interface{}) error {
+	return r.ZRemCtx(ctx, key, members...)
+}
+
+// ZRemCtx 从有序集合删除成员
+func (r *RedisClient) ZRemCtx(ctx context.Context, key string, members ...interface{}) error {
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+	return r.Client().ZRem(ctx, r.key(key), members...).Err()
+}
+
+// ZIncrBy 增加成员分数，返回新分数
+func (r *RedisClient) ZIncrBy(key string, increment float64, member string) (float64, error) {
+	return r.ZIncrByCtx(ctx, key, increment, member)
+}
+
+// ZIncrByCtx 增加成员分数，返回新分数
+func (r *RedisClient) ZIncrByCtx(ctx context.Context, key string, increment float64, member string) (float64, error) {
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+	return r.Client().ZIncrBy(ctx, r.key(key), increment, member).Result()
+}
+
+// ZScore 获取成员分数，成员不存在时返回 redis.Nil
+func (r *RedisClient) ZScore(key string, member string) (float64, error) {
+	return r.ZScoreCtx(ctx, key, member)
+}
+
+// ZScoreCtx 获取成员分数，成员不存在时返回 redis.Nil
+func (r *RedisClient) ZScoreCtx(ctx context.Context, key string, member string) (float64, error) {
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+	return r.Client().ZScore(ctx, r.key(key), member).Result()
+}
+
+// ZRangeByScore 按分数升序获取 [min, max] 范围的成员，min/max 支持 -inf、+inf 和 ( 开区间前缀；count 为0时不限制数量
+func (r *RedisClient) ZRangeByScore(key string, min, max string, offset, count int64) ([]string, error) {
+	return r.ZRangeByScoreCtx(ctx, key, min, max, offset, count)
+}
+
+// ZRangeByScoreCtx 按分数升序获取 [min, max] 范围的成员，min/max 支持 -inf、+inf 和 ( 开区间前缀；count 为0时不限制数量
+func (r *RedisClient) ZRangeByScoreCtx(ctx context.Context, key string, min, max string, offset, count int64) ([]string, error) {
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+	opt := &goredis.ZRangeBy{Min: min, Max: max}
+	if count > 0 {
+		opt.Offset, opt.Count = offset, count
+	}
+	return r.Client().ZRangeByScore(ctx, r.key(key), opt).Result()
+}
+
+// ZCard 获取有序集合成员数
+func (r *RedisClient) ZCard(key string) (int64, error) {
+	return r.ZCardCtx(ctx, key)
+}
+
+// ZCardCtx 获取有序集合成员数
+func (r *RedisClient) ZCardCtx(ctx context.Context, key string) (int64, error) {
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+	return r.Client().ZCard(ctx, r.key(key)).Result()
+}
+
+// BLPop 阻塞地从多个列表头部弹出第一个可用元素，返回所在的键和值；timeout 为0时一直阻塞，超时返回 redis.Nil。
+// 集群模式下多个键需位于同一槽位（使用哈希标签）。阻塞等待不受 Config.Timeout 限制
+func (r *RedisClient) BLPop(timeout time.Duration, keys ...string) (string, string, error) {
+	return r.BLPopCtx(ctx, timeout, keys...)
+}
+
+// BLPopCtx 阻塞地从多个列表头部弹出第一个可用元素，见 BLPop
+func (r *RedisClient) BLPopCtx(ctx context.Context, timeout time.Duration, keys ...string) (string, string, error) {
+	result, err := r.Client().BLPop(ctx, timeout, r.keys(keys)...).Result()
+	if err != nil {
+		return "", "", err
+	}
+	if len(result) != 2 {
+		return "", "", errors.New("redis: unexpected BLPOP reply")
+	}
+	return r.stripKey(result[0]), result[1], nil
+}
@@ -0,0 +1,266 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/ixxmi/tools/cache/memory"
+	"github.com/ixxmi/tools/cache/redis"
+	"github.com/ixxmi/tools/utils"
+	goredis "github.com/redis/go-redis/v9"
+	"golang.org/x/sync/singleflight"
+)
+
+// TieredConfig 两级缓存配置
+type TieredConfig struct {
+	Prefix     string        // Redis 键前缀，追加在 RedisClient 的 KeyPrefix 之后，与 cache.New 的 Prefix 一致
+	DefaultTTL time.Duration // Redis 中的默认过期时间，默认10分钟
+	LocalTTL   time.Duration // 本地缓存的过期时间，默认1分钟；失效消息丢失时（如 Redis 重连期间）本地数据最多陈旧这么久
+	MaxEntries int           // 本地缓存最大条目数，默认100000
+	MaxBytes   int64         // 本地缓存最大字节数，0表示不限制
+	Channel    string        // 失效通知的 Pub/Sub 频道，默认 cache:invalidate，共享同一份数据的实例需相同
+}
+
+// invalidation 失效通知，Source 用于忽略本实例发出的消息
+type invalidation struct {
+	Source string   `json:"source"`
+	Keys   []string `json:"keys"`
+}
+
+// Tiered 两级缓存：读取时先查本地内存，未命中再查 Redis 并回填本地；
+// 写入和删除同时作用于 Redis 和本地，并通过 Redis Pub/Sub 通知其他实例删除本地副本
+type Tiered struct {
+	rc       *redis.RedisClient
+	local    *memory.Cache
+	config   TieredConfig
+	id       string
+	pubsub   *goredis.PubSub // 仅由 listen 协程在启动后访问
+	group    singleflight.Group
+	resub    chan struct{}
+	unwatch  func()
+	done     chan struct{}
+	closing  chan struct{}
+	closeErr error
+	once     sync.Once
+}
+
+var _ Cache = (*Tiered)(nil)
+
+// NewTiered 创建两级缓存并订阅失效通知
+func NewTiered(rc *redis.RedisClient, config TieredConfig) (*Tiered, error) {
+	if config.DefaultTTL <= 0 {
+		config.DefaultTTL = 10 * time.Minute
+	}
+	if config.LocalTTL <= 0 {
+		config.LocalTTL = time.Minute
+	}
+	if config.Channel == "" {
+		config.Channel = "cache:invalidate"
+	}
+	t := &Tiered{
+		rc: rc,
+		local: memory.New(memory.Config{
+			MaxEntries: config.MaxEntries,
+			MaxBytes:   config.MaxBytes,
+			DefaultTTL: config.LocalTTL,
+		}),
+		config:  config,
+		id:      utils.NewUUIDv4(),
+		resub:   make(chan struct{}, 1),
+		done:    make(chan struct{}),
+		closing: make(chan struct{}),
+	}
+
+	pubsub, err := t.subscribe(context.Background())
+	if err != nil {
+		t.local.Close()
+		return nil, err
+	}
+	t.pubsub = pubsub
+	// 自动重连会关闭旧客户端及其订阅，重连后重新订阅
+	t.unwatch = rc.OnReconnect(func() {
+		select {
+		case t.resub <- struct{}{}:
+		default:
+		}
+	})
+	go t.listen()
+	return t, nil
+}
+
+// subscribe 订阅失效通知频道，等待订阅确认，保证返回后不会漏掉其他实例的通知
+func (t *Tiered) subscribe(ctx context.Context) (*goredis.PubSub, error) {
+	pubsub := t.rc.Client().Subscribe(ctx, t.config.Channel)
+	if _, err := pubsub.Receive(ctx); err != nil {
+		pubsub.Close()
+		return nil, fmt.Errorf("failed to subscribe %s: %w", t.config.Channel, err)
+	}
+	return pubsub, nil
+}
+
+// resubscribe 在新客户端上重新订阅，失败时按 LocalTTL 间隔重试；断开期间可能漏掉通知，成功后清空本地缓存
+func (t *Tiered) resubscribe() bool {
+	t.pubsub.Close()
+	for {
+		pubsub, err := t.subscribe(context.Background())
+		if err == nil {
+			t.pubsub = pubsub
+			t.local.Clear()
+			return true
+		}
+		log.Printf("cache: %v", err)
+		select {
+		case <-t.closing:
+			t.pubsub = nil
+			return false
+		case <-time.After(t.config.LocalTTL):
+		}
+	}
+}
+
+// listen 处理其他实例的失效通知，退出时关闭订阅
+func (t *Tiered) listen() {
+	defer close(t.done)
+	defer func() {
+		if t.pubsub != nil {
+			t.closeErr = t.pubsub.Close()
+		}
+	}()
+	ch := t.pubsub.Channel()
+	for {
+		select {
+		case <-t.closing:
+			return
+		case <-t.resub:
+			if !t.resubscribe() {
+				return
+			}
+			ch = t.pubsub.Channel()
+		case msg, ok := <-ch:
+			if !ok {
+				return
+			}
+			var inv invalidation
+			if err := json.Unmarshal([]byte(msg.Payload), &inv); err != nil {
+				log.Printf("cache: invalid invalidation message: %v", err)
+				continue
+			}
+			if inv.Source == t.id {
+				continue
+			}
+			t.local.Del(inv.Keys...)
+		}
+	}
+}
+
+// publish 通知其他实例删除本地副本，发送失败时其他实例的本地副本在 LocalTTL 后过期
+func (t *Tiered) publish(ctx context.Context, keys []string) error {
+	payload, err := json.Marshal(invalidation{Source: t.id, Keys: keys})
+	if err != nil {
+		return err
+	}
+	if err := t.rc.Client().Publish(ctx, t.config.Channel, payload).Err(); err != nil {
+		return fmt.Errorf("failed to publish invalidation: %w", err)
+	}
+	return nil
+}
+
+// Get 读取缓存，本地未命中时读取 Redis 并回填本地，不存在时返回 ErrMiss
+func (t *Tiered) Get(ctx context.Context, key string) ([]byte, error) {
+	key = t.config.Prefix + key
+	if value, err := memory.GetAs[[]byte](t.local, key); err == nil {
+		return value, nil
+	}
+	value, err := t.rc.GetBytesCtx(ctx, key)
+	if errors.Is(err, goredis.Nil) {
+		return nil, ErrMiss
+	}
+	if err != nil {
+		return nil, err
+	}
+	memory.SetAs(t.local, key, value, 0)
+	return value, nil
+}
+
+// Set 写入 Redis 和本地缓存，并通知其他实例删除旧的本地副本
+func (t *Tiered) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	if ttl <= 0 {
+		ttl = t.config.DefaultTTL
+	}
+	key = t.config.Prefix + key
+	if err := t.rc.SetCtx(ctx, key, value, ttl); err != nil {
+		return err
+	}
+	memory.SetAs(t.local, key, value, min(ttl, t.config.LocalTTL))
+	return t.publish(ctx, []string{key})
+}
+
+// Delete 删除 Redis 和本地缓存，并通知其他实例
+func (t *Tiered) Delete(ctx context.Context, keys ...string) error {
+	if len(keys) == 0 {
+		return nil
+	}
+	full := make([]string, len(keys))
+	for i, k := range keys {
+		full[i] = t.config.Prefix + k
+	}
+	t.local.Del(full...)
+	// 集群模式下多键 DEL 要求同一槽位，逐个删除
+	for _, key := range full {
+		if err := t.rc.DelCtx(ctx, key); err != nil {
+			return err
+		}
+	}
+	return t.publish(ctx, full)
+}
+
+// GetOrLoad 读取缓存，未命中时调用 load 加载并写入，同一进程内相同键的并发加载只执行一次
+func (t *Tiered) GetOrLoad(ctx context.Context, key string, ttl time.Duration, load LoadFunc) ([]byte, error) {
+	value, err := t.Get(ctx, key)
+	if err == nil {
+		return value, nil
+	}
+	if !errors.Is(err, ErrMiss) {
+		return nil, err
+	}
+	v, err, _ := t.group.Do(key, func() (interface{}, error) {
+		if value, err := t.Get(ctx, key); err == nil {
+			return value, nil
+		}
+		value, err := load(ctx)
+		if err != nil {
+			return nil, err
+		}
+		// 写缓存失败不影响本次结果
+		_ = t.Set(ctx, key, value, ttl)
+		return value, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return v.([]byte), nil
+}
+
+// Stats 本地缓存统计
+func (t *Tiered) Stats() memory.Stats {
+	return t.local.Stats()
+}
+
+// Close 取消订阅并释放本地缓存，Redis 客户端由调用方管理
+func (t *Tiered) Close() error {
+	var err error
+	t.once.Do(func() {
+		t.unwatch()
+		close(t.closing)
+		<-t.done
+		err = t.closeErr
+		t.local.Close()
+		t.local.Clear()
+	})
+	return err
+}
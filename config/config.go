@@ -0,0 +1,524 @@
+// Package config 提供分层配置加载：默认值 < 配置文件 < 环境变量 < 命令行参数，
+// 合并后通过 utils.Bind 绑定到结构体，并支持配置文件变更通知。
+//
+// 结构体字段可以通过标签声明默认值和环境变量，如
+//
+//	Addr string `json:"addr" env:"REDIS_ADDR" default:"127.0.0.1:6379"`
+//
+// 切片的默认值和环境变量以逗号分隔。绑定后依次执行 validate 标签校验（见 utils/validate）、
+// 结构体的 Validate() error 方法和 WithValidator 注册的校验函数。
+package config
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ixxmi/tools/utils"
+	"github.com/ixxmi/tools/utils/validate"
+)
+
+// Loader 配置加载器
+type Loader struct {
+	files      []string
+	envPrefix  string
+	flags      *flag.FlagSet
+	defaults   map[string]interface{}
+	required   []string
+	validators []func(interface{}) error
+
+	mu          sync.RWMutex
+	data        map[string]interface{}
+	subscribers []func(*Loader)
+}
+
+// Option 是用于配置 Loader 的函数类型
+type Option func(*Loader)
+
+// WithFiles 设置配置文件，按顺序合并，后面的覆盖前面的；支持 .yaml/.yml/.json/.toml
+func WithFiles(files ...string) Option {
+	return func(l *Loader) {
+		l.files = append(l.files, files...)
+	}
+}
+
+// WithEnvPrefix 设置环境变量前缀，如 APP 时 redis.addrs 对应 APP_REDIS_ADDRS
+func WithEnvPrefix(prefix string) Option {
+	return func(l *Loader) {
+		l.envPrefix = strings.ToUpper(strings.TrimSuffix(prefix, "_"))
+	}
+}
+
+// WithFlags 设置命令行参数集合，仅显式传入的参数参与合并，参数名即配置键，如 -redis.addrs
+func WithFlags(fs *flag.FlagSet) Option {
+	return func(l *Loader) {
+		l.flags = fs
+	}
+}
+
+// WithDefaults 设置默认值，键为点分隔路径
+func WithDefaults(defaults map[string]interface{}) Option {
+	return func(l *Loader) {
+		for k, v := range defaults {
+			l.defaults[k] = v
+		}
+	}
+}
+
+// WithRequired 设置必填配置键
+func WithRequired(keys ...string) Option {
+	return func(l *Loader) {
+		l.required = append(l.required, keys...)
+	}
+}
+
+// WithValidator 添加校验函数，参数为绑定后的结构体指针，返回错误时 Load 失败
+func WithValidator(fn func(dest interface{}) error) Option {
+	return func(l *Loader) {
+		l.validators = append(l.validators, fn)
+	}
+}
+
+// Validator 配置结构体实现该接口时，Load 绑定后调用 Validate 校验字段间的约束
+type Validator interface {
+	Validate() error
+}
+
+// New 创建一个新的 Loader 实例
+func New(opts ...Option) *Loader {
+	l := &Loader{
+		defaults: make(map[string]interface{}),
+		data:     make(map[string]interface{}),
+	}
+	for _, opt := range opts {
+		opt(l)
+	}
+	return l
+}
+
+// SetDefault 设置单个默认值
+func (l *Loader) SetDefault(key string, value interface{}) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.defaults[key] = value
+}
+
+// Load 加载并合并所有配置源，绑定到dest（结构体指针）
+func (l *Loader) Load(dest interface{}) error {
+	t := reflect.TypeOf(dest)
+	if t == nil || t.Kind() != reflect.Ptr || t.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("config dest must be a pointer to struct")
+	}
+	fields := structFields(t.Elem())
+
+	data, err := l.merge(fields)
+	if err != nil {
+		return err
+	}
+	if err := checkRequired(data, l.required, fields); err != nil {
+		return err
+	}
+
+	// 先绑定到副本，校验通过后再写回 dest，校验失败时 dest 和已加载的配置保持不变
+	tmp := reflect.New(t.Elem())
+	tmp.Elem().Set(reflect.ValueOf(dest).Elem())
+	if err := utils.Bind(data, tmp.Interface()); err != nil {
+		return err
+	}
+	if err := l.validate(tmp.Interface()); err != nil {
+		return err
+	}
+	reflect.ValueOf(dest).Elem().Set(tmp.Elem())
+
+	l.mu.Lock()
+	l.data = data
+	l.mu.Unlock()
+	return nil
+}
+
+// validate 执行 validate 标签校验、Validate 方法和 WithValidator 注册的校验函数
+func (l *Loader) validate(dest interface{}) error {
+	if err := validate.Struct(dest); err != nil {
+		return fmt.Errorf("invalid config: %w", err)
+	}
+	if v, ok := dest.(Validator); ok {
+		if err := v.Validate(); err != nil {
+			return fmt.Errorf("invalid config: %w", err)
+		}
+	}
+	for _, fn := range l.validators {
+		if err := fn(dest); err != nil {
+			return fmt.Errorf("invalid config: %w", err)
+		}
+	}
+	return nil
+}
+
+// Unmarshal 将已加载配置中key对应的部分绑定到dest，key为空时绑定全部配置
+func (l *Loader) Unmarshal(key string, dest interface{}) error {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	var src interface{} = l.data
+	if key != "" {
+		v, ok := getPath(l.data, key)
+		if !ok {
+			return fmt.Errorf("config key %s not found", key)
+		}
+		src = v
+	}
+	if t := reflect.TypeOf(dest); t != nil && t.Kind() == reflect.Ptr && t.Elem().Kind() == reflect.Struct {
+		if m, ok := src.(map[string]interface{}); ok {
+			src = normalize(copyMap(m), structFields(t.Elem()))
+		}
+	}
+	return utils.Bind(src, dest)
+}
+
+// Get 获取已加载配置中key对应的值
+func (l *Loader) Get(key string) (interface{}, bool) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return getPath(l.data, key)
+}
+
+// GetString 获取字符串配置
+func (l *Loader) GetString(key string) string {
+	v, _ := l.Get(key)
+	switch val := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return val
+	case []interface{}, map[string]interface{}:
+		return fmt.Sprint(val)
+	}
+	return utils.Str(v)
+}
+
+// GetInt 获取整数配置
+func (l *Loader) GetInt(key string) int {
+	v, _ := l.Get(key)
+	return utils.Int(v)
+}
+
+// GetBool 获取布尔配置
+func (l *Loader) GetBool(key string) bool {
+	v, _ := l.Get(key)
+	if s, ok := v.(string); ok {
+		b, _ := strconv.ParseBool(s)
+		return b
+	}
+	b, _ := v.(bool)
+	return b
+}
+
+// AllSettings 返回合并后的配置副本
+func (l *Loader) AllSettings() map[string]interface{} {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return copyMap(l.data)
+}
+
+// merge 按优先级合并各配置源
+func (l *Loader) merge(fields []field) (map[string]interface{}, error) {
+	data := make(map[string]interface{})
+
+	// 1. 默认值，default 标签优先级低于 WithDefaults
+	for _, f := range fields {
+		if v, ok := f.tag.Lookup("default"); ok {
+			setPath(data, f.path, v)
+		}
+	}
+	l.mu.RLock()
+	for k, v := range l.defaults {
+		setPath(data, k, v)
+	}
+	l.mu.RUnlock()
+
+	// 2. 配置文件
+	for _, file := range l.files {
+		m, err := ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+		mergeMap(data, m)
+	}
+
+	// 3. 环境变量，env 标签指定的变量名不加前缀，未指定时按前缀和路径推导
+	for _, f := range fields {
+		name := f.tag.Get("env")
+		if name == "" && l.envPrefix != "" {
+			name = l.envPrefix + "_" + strings.ToUpper(strings.ReplaceAll(f.path, ".", "_"))
+		}
+		if name == "" || name == "-" {
+			continue
+		}
+		if v, ok := os.LookupEnv(name); ok {
+			setPath(data, f.path, v)
+		}
+	}
+
+	// 4. 命令行参数
+	if l.flags != nil {
+		l.flags.Visit(func(fl *flag.Flag) {
+			setPath(data, fl.Name, fl.Value.String())
+		})
+	}
+
+	return normalize(data, fields), nil
+}
+
+// checkRequired 校验必填项，包括WithRequired指定的键和 required:"true" 标签的字段
+func checkRequired(data map[string]interface{}, keys []string, fields []field) error {
+	var missing []string
+	for _, f := range fields {
+		if f.tag.Get("required") == "true" {
+			keys = append(keys, f.path)
+		}
+	}
+	for _, key := range keys {
+		v, ok := getPath(data, key)
+		if !ok || v == nil || v == "" {
+			missing = append(missing, key)
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("missing required config: %s", strings.Join(missing, ", "))
+	}
+	return nil
+}
+
+// --- 结构体字段 ---
+
+// field 结构体叶子字段
+type field struct {
+	path string
+	typ  reflect.Type
+	tag  reflect.StructTag
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// structFields 递归获取结构体的叶子字段路径，路径使用json标签名，无标签时使用小写字段名
+func structFields(t reflect.Type) []field {
+	var fields []field
+	var walk func(t reflect.Type, prefix string, depth int)
+	walk = func(t reflect.Type, prefix string, depth int) {
+		if depth > 10 {
+			return
+		}
+		for i := 0; i < t.NumField(); i++ {
+			sf := t.Field(i)
+			if !sf.IsExported() {
+				continue
+			}
+			name := strings.ToLower(sf.Name)
+			if tag := sf.Tag.Get("json"); tag != "" {
+				tagName := strings.Split(tag, ",")[0]
+				if tagName == "-" {
+					continue
+				}
+				if tagName != "" {
+					name = tagName
+				}
+			}
+			path := name
+			if prefix != "" {
+				path = prefix + "." + name
+			}
+
+			ft := sf.Type
+			if ft.Kind() == reflect.Ptr {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct && ft != timeType {
+				if sf.Anonymous && sf.Tag.Get("json") == "" {
+					walk(ft, prefix, depth+1)
+				} else {
+					walk(ft, path, depth+1)
+				}
+				continue
+			}
+			fields = append(fields, field{path: path, typ: sf.Type, tag: sf.Tag})
+		}
+	}
+	walk(t, "", 0)
+	return fields
+}
+
+// normalize 将字符串形式的值（来自环境变量、命令行参数或配置文件）转换为字段类型，便于JSON绑定
+func normalize(data map[string]interface{}, fields []field) map[string]interface{} {
+	for _, f := range fields {
+		v, ok := getPath(data, f.path)
+		if !ok {
+			continue
+		}
+		if nv, ok := convertValue(v, f.typ); ok {
+			setPath(data, f.path, nv)
+		}
+	}
+	return data
+}
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// convertValue 将值转换为目标类型可绑定的形式
+func convertValue(v interface{}, t reflect.Type) (interface{}, bool) {
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t == durationType {
+		if s, ok := v.(string); ok {
+			d, err := time.ParseDuration(s)
+			if err != nil {
+				return nil, false
+			}
+			return int64(d), true
+		}
+		return nil, false
+	}
+
+	s, ok := v.(string)
+	if !ok {
+		return nil, false
+	}
+	switch t.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseInt(strings.TrimSpace(s), 10, 64)
+		if err != nil {
+			return nil, false
+		}
+		return n, true
+	case reflect.Float32, reflect.Float64:
+		n, err := strconv.ParseFloat(strings.TrimSpace(s), 64)
+		if err != nil {
+			return nil, false
+		}
+		return n, true
+	case reflect.Bool:
+		b, err := strconv.ParseBool(strings.TrimSpace(s))
+		if err != nil {
+			return nil, false
+		}
+		return b, true
+	case reflect.Slice:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return nil, false
+		}
+		var items []interface{}
+		for _, part := range strings.Split(s, ",") {
+			part = strings.TrimSpace(part)
+			if part == "" {
+				continue
+			}
+			if item, ok := convertValue(part, t.Elem()); ok {
+				items = append(items, item)
+			} else {
+				items = append(items, part)
+			}
+		}
+		return items, true
+	}
+	return nil, false
+}
+
+// --- 路径操作 ---
+
+// lookupKey 大小写不敏感地查找map中的键，与encoding/json的字段匹配规则一致
+func lookupKey(m map[string]interface{}, key string) (string, bool) {
+	if _, ok := m[key]; ok {
+		return key, true
+	}
+	for k := range m {
+		if strings.EqualFold(k, key) {
+			return k, true
+		}
+	}
+	return key, false
+}
+
+// getPath 按点分隔路径获取值
+func getPath(data map[string]interface{}, path string) (interface{}, bool) {
+	var cur interface{} = data
+	for _, part := range strings.Split(path, ".") {
+		m, ok := toStringMap(cur)
+		if !ok {
+			return nil, false
+		}
+		k, ok := lookupKey(m, part)
+		if !ok {
+			return nil, false
+		}
+		cur = m[k]
+	}
+	return cur, true
+}
+
+// setPath 按点分隔路径设置值，中间节点不存在时自动创建
+func setPath(data map[string]interface{}, path string, value interface{}) {
+	parts := strings.Split(path, ".")
+	cur := data
+	for i, part := range parts {
+		k, _ := lookupKey(cur, part)
+		if i == len(parts)-1 {
+			cur[k] = value
+			return
+		}
+		next, ok := toStringMap(cur[k])
+		if !ok {
+			next = make(map[string]interface{})
+		}
+		cur[k] = next
+		cur = next
+	}
+}
+
+// mergeMap 深度合并src到dst
+func mergeMap(dst, src map[string]interface{}) {
+	for k, v := range src {
+		key, _ := lookupKey(dst, k)
+		sm, sok := toStringMap(v)
+		dm, dok := toStringMap(dst[key])
+		if sok && dok {
+			mergeMap(dm, sm)
+			dst[key] = dm
+			continue
+		}
+		dst[key] = v
+	}
+}
+
+// copyMap 深拷贝map
+func copyMap(m map[string]interface{}) map[string]interface{} {
+	out := make(map[string]interface{}, len(m))
+	for k, v := range m {
+		if sub, ok := toStringMap(v); ok {
+			out[k] = copyMap(sub)
+		} else {
+			out[k] = v
+		}
+	}
+	return out
+}
+
+// toStringMap 将map[interface{}]interface{}等形式统一为map[string]interface{}
+func toStringMap(v interface{}) (map[string]interface{}, bool) {
+	switch m := v.(type) {
+	case map[string]interface{}:
+		return m, true
+	case map[interface{}]interface{}:
+		out := make(map[string]interface{}, len(m))
+		for k, val := range m {
+			out[fmt.Sprint(k)] = val
+		}
+		return out, true
+	}
+	return nil, false
+}
@@ -0,0 +1,27 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/ixxmi/tools/utils/codec"
+)
+
+// ReadFile 读取配置文件为map，按扩展名识别格式
+func ReadFile(filename string) (map[string]interface{}, error) {
+	content, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file %s: %v", filename, err)
+	}
+	m, err := Decode(content, filepath.Ext(filename))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse config file %s: %v", filename, err)
+	}
+	return m, nil
+}
+
+// Decode 按格式解析配置内容，format 为 yaml/yml/json/toml（可带前导点）
+func Decode(content []byte, format string) (map[string]interface{}, error) {
+	return codec.ToMap(content, format)
+}
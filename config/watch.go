@@ -0,0 +1,114 @@
+package config
+
+import (
+	"context"
+	"log"
+	"os"
+	"reflect"
+	"time"
+)
+
+// OnChange 注册配置变更回调，配置文件变化并重新加载成功后调用
+func (l *Loader) OnChange(fn func(*Loader)) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.subscribers = append(l.subscribers, fn)
+}
+
+// fileState 文件状态，用于判断文件是否变化
+type fileState struct {
+	modTime time.Time
+	size    int64
+}
+
+// statFiles 获取所有配置文件的状态
+func (l *Loader) statFiles() map[string]fileState {
+	states := make(map[string]fileState, len(l.files))
+	for _, file := range l.files {
+		if fi, err := os.Stat(file); err == nil {
+			states[file] = fileState{modTime: fi.ModTime(), size: fi.Size()}
+		}
+	}
+	return states
+}
+
+// Watch 按interval轮询配置文件，发生变化时重新加载并通知订阅者，直到ctx被取消
+// dest 仅用于推导结构体类型，重新加载时绑定到新实例，不会修改调用方持有的结构体；订阅者通过 Unmarshal 读取新配置。
+// 需要类型化的当前配置和按路径订阅时使用 Watcher，两者共用同一套轮询和重新加载逻辑
+func (l *Loader) Watch(ctx context.Context, interval time.Duration, dest interface{}) {
+	l.watch(ctx, interval, func() error { return l.Reload(dest) }, func(err error) {
+		log.Printf("重新加载配置失败: %v", err)
+	})
+}
+
+// watch 按interval轮询配置文件，变化时调用 reload，失败时交给 onError
+func (l *Loader) watch(ctx context.Context, interval time.Duration, reload func() error, onError func(error)) {
+	if interval <= 0 {
+		interval = 5 * time.Second
+	}
+	last := l.statFiles()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		cur := l.statFiles()
+		if !changed(last, cur) {
+			continue
+		}
+		last = cur
+
+		if err := reload(); err != nil {
+			onError(err)
+		}
+	}
+}
+
+// Reload 重新加载配置并通知订阅者，dest 用于推导字段类型和必填校验
+func (l *Loader) Reload(dest interface{}) error {
+	_, err := l.reload(dest)
+	return err
+}
+
+// reload 将配置加载到与 dest 同类型的新实例，成功后通知 OnChange 订阅者并返回新实例
+func (l *Loader) reload(dest interface{}) (interface{}, error) {
+	cfg := newOf(dest)
+	if err := l.Load(cfg); err != nil {
+		return nil, err
+	}
+
+	l.mu.RLock()
+	subscribers := append([]func(*Loader){}, l.subscribers...)
+	l.mu.RUnlock()
+	for _, fn := range subscribers {
+		fn(l)
+	}
+	return cfg, nil
+}
+
+// changed 判断文件状态是否变化
+func changed(a, b map[string]fileState) bool {
+	if len(a) != len(b) {
+		return true
+	}
+	for k, v := range a {
+		if bv, ok := b[k]; !ok || !bv.modTime.Equal(v.modTime) || bv.size != v.size {
+			return true
+		}
+	}
+	return false
+}
+
+// newOf 创建与dest同类型的新实例
+func newOf(dest interface{}) interface{} {
+	t := reflect.TypeOf(dest)
+	if t == nil || t.Kind() != reflect.Ptr {
+		return dest
+	}
+	return reflect.New(t.Elem()).Interface()
+}
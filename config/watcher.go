@@ -0,0 +1,183 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/ixxmi/tools/utils"
+)
+
+// Event 配置变更事件
+type Event[T any] struct {
+	Old     *T
+	New     *T
+	Changes []utils.Change // 新旧配置的差异，路径使用 json 标签名，见 utils.DeepDiff
+}
+
+// Changed 判断 path（如 redis、redis.addrs）及其子路径是否有变化
+func (e Event[T]) Changed(path string) bool {
+	for _, c := range e.Changes {
+		if c.Path == path || strings.HasPrefix(c.Path, path+".") || strings.HasPrefix(c.Path, path+"[") {
+			return true
+		}
+	}
+	return false
+}
+
+// subscriber 订阅者，path 为空时订阅全部变更
+type subscriber[T any] struct {
+	id   uint64
+	path string
+	fn   func(Event[T])
+}
+
+// Watcher 持有类型化的当前配置，配置文件变化时重新加载，校验通过后原子替换并通知订阅者。
+// 加载失败时保留旧配置，典型用法：
+//
+//	w, err := config.NewWatcher[AppConfig](loader, 5*time.Second)
+//	w.Subscribe("log.level", func(e config.Event[AppConfig]) {
+//		if lv, err := logger.ParseLevel(e.New.Log.Level); err == nil {
+//			logger.SetLevel(lv)
+//		}
+//	})
+//	go w.Run(ctx)
+type Watcher[T any] struct {
+	loader   *Loader
+	interval time.Duration
+	current  atomic.Pointer[T]
+	onError  func(error)
+
+	mu     sync.Mutex // 保护 subs 并串行化重新加载
+	subs   []subscriber[T]
+	nextID uint64
+}
+
+// NewWatcher 立即加载一次配置，失败时返回错误。interval 为轮询配置文件的间隔，默认5秒
+func NewWatcher[T any](loader *Loader, interval time.Duration) (*Watcher[T], error) {
+	if interval <= 0 {
+		interval = 5 * time.Second
+	}
+	w := &Watcher[T]{loader: loader, interval: interval, onError: func(err error) {
+		log.Printf("重新加载配置失败: %v", err)
+	}}
+	cfg := new(T)
+	if err := loader.Load(cfg); err != nil {
+		return nil, err
+	}
+	w.current.Store(cfg)
+	return w, nil
+}
+
+// OnError 设置重新加载失败时的回调，默认输出到标准日志
+func (w *Watcher[T]) OnError(fn func(error)) {
+	w.mu.Lock()
+	w.onError = fn
+	w.mu.Unlock()
+}
+
+// Current 当前配置，返回的结构体不应被修改
+func (w *Watcher[T]) Current() *T {
+	return w.current.Load()
+}
+
+// Subscribe 订阅 path 及其子路径的变更，path 为空时订阅全部变更，返回取消订阅函数。
+// 回调在重新加载的 goroutine 中按注册顺序同步执行，回调中不能再调用 Subscribe 或取消订阅
+func (w *Watcher[T]) Subscribe(path string, fn func(Event[T])) (cancel func()) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.nextID++
+	id := w.nextID
+	w.subs = append(w.subs, subscriber[T]{id: id, path: path, fn: fn})
+	return func() {
+		w.mu.Lock()
+		defer w.mu.Unlock()
+		for i, s := range w.subs {
+			if s.id == id {
+				w.subs = append(w.subs[:i:i], w.subs[i+1:]...)
+				return
+			}
+		}
+	}
+}
+
+// Reload 重新加载配置，有变化时替换当前配置并通知订阅者；与 Loader.Reload 共用加载逻辑，Loader.OnChange 的订阅者同样会收到通知
+func (w *Watcher[T]) Reload() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	v, err := w.loader.reload((*T)(nil))
+	if err != nil {
+		return err
+	}
+	cfg := v.(*T)
+	old := w.current.Load()
+	changes, err := utils.DeepDiff(old, cfg)
+	if err != nil {
+		return fmt.Errorf("diff config: %w", err)
+	}
+	if len(changes) == 0 {
+		return nil
+	}
+	w.current.Store(cfg)
+	event := Event[T]{Old: old, New: cfg, Changes: changes}
+	for _, s := range w.subs {
+		if s.path == "" || event.Changed(s.path) {
+			s.fn(event)
+		}
+	}
+	return nil
+}
+
+// Run 轮询配置文件，变化时调用 Reload，直到 ctx 被取消
+func (w *Watcher[T]) Run(ctx context.Context) {
+	w.loader.watch(ctx, w.interval, w.Reload, w.reportError)
+}
+
+// reportError 通知重新加载失败
+func (w *Watcher[T]) reportError(err error) {
+	w.mu.Lock()
+	onError := w.onError
+	w.mu.Unlock()
+	if onError != nil {
+		onError(err)
+	}
+}
+
+// Hot 根据配置构建的资源（如 Redis、ClickHouse 客户端），配置变化时重建并原子替换
+type Hot[C any] struct {
+	p atomic.Pointer[C]
+}
+
+// Get 当前资源
+func (h *Hot[C]) Get() *C {
+	return h.p.Load()
+}
+
+// Attach 用当前配置构建资源，之后 path 下的配置变化时重新构建并替换；
+// 重建失败时保留旧资源并调用 Watcher 的 OnError 回调，替换成功后旧资源交给 release 释放（可为 nil）
+func Attach[T, C any](w *Watcher[T], path string, build func(*T) (*C, error), release func(*C)) (*Hot[C], error) {
+	c, err := build(w.Current())
+	if err != nil {
+		return nil, err
+	}
+	h := &Hot[C]{}
+	h.p.Store(c)
+	w.Subscribe(path, func(e Event[T]) {
+		c, err := build(e.New)
+		if err != nil {
+			// 回调在 Reload 持有 w.mu 时执行，直接读取 onError
+			if w.onError != nil {
+				w.onError(fmt.Errorf("rebuild %s: %w", path, err))
+			}
+			return
+		}
+		if old := h.p.Swap(c); old != nil && release != nil {
+			release(old)
+		}
+	})
+	return h, nil
+}
@@ -0,0 +1,237 @@
+package ckgroup
+
+import (
+	"context"
+	"errors"
+	"log"
+	"reflect"
+	"sync"
+	"time"
+)
+
+// ErrInserterClosed 异步写入器已关闭
+var ErrInserterClosed = errors.New("ckgroup: async inserter closed")
+
+// AsyncInserterConfig 异步写入配置
+type AsyncInserterConfig struct {
+	MaxRows         int           // 单表缓冲行数达到该值时写入，默认为客户端的 BatchSize
+	MaxBytes        int           // 单表缓冲的估算字节数达到该值时写入，默认4MB
+	FlushInterval   time.Duration // 定时写入间隔，默认1秒
+	MaxBufferedRows int           // 所有表缓冲和写入中的总行数上限，达到后 Add 阻塞直到写入完成，默认 MaxRows 的10倍
+	// SizeFunc 估算一行占用的字节数，默认按字段类型递归估算
+	SizeFunc func(row interface{}) int
+}
+
+// AsyncInserter 异步批量写入器。Add 将行加入对应表的缓冲区，后台协程按行数、字节数或时间间隔调用 BatchInsert 写入，
+// 写入失败的行不重试，通过 OnError 报告
+type AsyncInserter struct {
+	c       *ClickHouseClient
+	config  AsyncInserterConfig
+	mu      sync.Mutex
+	cond    *sync.Cond
+	buffers map[string]*tableBuffer
+	total   int
+	closed  bool
+	flushCh chan string
+	stop    chan struct{}
+	wg      sync.WaitGroup
+	OnError func(table string, rows int, err error) // 写入失败回调，默认输出日志
+}
+
+// tableBuffer 单表缓冲区
+type tableBuffer struct {
+	rows    []interface{}
+	bytes   int
+	pending bool // 已通知后台协程写入
+}
+
+// NewAsyncInserter 创建异步写入器并启动后台写入协程，使用完毕后需调用 Close
+func (c *ClickHouseClient) NewAsyncInserter(config AsyncInserterConfig) *AsyncInserter {
+	if config.MaxRows <= 0 {
+		config.MaxRows = c.batchSize
+	}
+	if config.MaxBytes <= 0 {
+		config.MaxBytes = 4 << 20
+	}
+	if config.FlushInterval <= 0 {
+		config.FlushInterval = time.Second
+	}
+	if config.MaxBufferedRows <= 0 {
+		config.MaxBufferedRows = config.MaxRows * 10
+	}
+	if config.SizeFunc == nil {
+		config.SizeFunc = func(row interface{}) int {
+			return estimateSize(reflect.ValueOf(row))
+		}
+	}
+	a := &AsyncInserter{
+		c:       c,
+		config:  config,
+		buffers: make(map[string]*tableBuffer),
+		flushCh: make(chan string, 64),
+		stop:    make(chan struct{}),
+	}
+	a.cond = sync.NewCond(&a.mu)
+	a.wg.Add(1)
+	go a.loop()
+	return a
+}
+
+// Add 将一行（结构体或结构体指针，同一张表的行类型需一致）加入缓冲区，缓冲总行数达到上限时阻塞等待写入完成
+func (a *AsyncInserter) Add(table string, row interface{}) error {
+	size := a.config.SizeFunc(row)
+
+	a.mu.Lock()
+	for a.total >= a.config.MaxBufferedRows && !a.closed {
+		a.cond.Wait()
+	}
+	if a.closed {
+		a.mu.Unlock()
+		return ErrInserterClosed
+	}
+	buf := a.buffers[table]
+	if buf == nil {
+		buf = &tableBuffer{}
+		a.buffers[table] = buf
+	}
+	buf.rows = append(buf.rows, row)
+	buf.bytes += size
+	a.total++
+	notify := !buf.pending && (len(buf.rows) >= a.config.MaxRows || buf.bytes >= a.config.MaxBytes)
+	if notify {
+		buf.pending = true
+	}
+	a.mu.Unlock()
+
+	if notify {
+		select {
+		case a.flushCh <- table:
+		default:
+			// 通知队列已满时由定时写入处理
+		}
+	}
+	return nil
+}
+
+// Flush 立即写入所有缓冲的行
+func (a *AsyncInserter) Flush() {
+	a.mu.Lock()
+	tables := make([]string, 0, len(a.buffers))
+	for table := range a.buffers {
+		tables = append(tables, table)
+	}
+	a.mu.Unlock()
+	for _, table := range tables {
+		a.flushTable(table)
+	}
+}
+
+// Close 停止接收新行，写入剩余的缓冲数据后返回
+func (a *AsyncInserter) Close() error {
+	a.mu.Lock()
+	if a.closed {
+		a.mu.Unlock()
+		return nil
+	}
+	a.closed = true
+	a.cond.Broadcast()
+	a.mu.Unlock()
+
+	close(a.stop)
+	a.wg.Wait()
+	a.Flush()
+	return nil
+}
+
+// loop 后台写入协程
+func (a *AsyncInserter) loop() {
+	defer a.wg.Done()
+	ticker := time.NewTicker(a.config.FlushInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-a.stop:
+			return
+		case table := <-a.flushCh:
+			a.flushTable(table)
+		case <-ticker.C:
+			a.Flush()
+		}
+	}
+}
+
+// flushTable 取出单表缓冲并写入，写入完成后释放缓冲配额
+func (a *AsyncInserter) flushTable(table string) {
+	a.mu.Lock()
+	buf := a.buffers[table]
+	if buf == nil || len(buf.rows) == 0 {
+		if buf != nil {
+			buf.pending = false
+		}
+		a.mu.Unlock()
+		return
+	}
+	rows := buf.rows
+	a.buffers[table] = &tableBuffer{rows: make([]interface{}, 0, len(rows))}
+	a.mu.Unlock()
+
+	if err := a.c.BatchInsertCtx(context.Background(), table, rows); err != nil {
+		a.reportError(table, len(rows), err)
+	}
+
+	a.mu.Lock()
+	a.total -= len(rows)
+	a.cond.Broadcast()
+	a.mu.Unlock()
+}
+
+func (a *AsyncInserter) reportError(table string, rows int, err error) {
+	if a.OnError != nil {
+		a.OnError(table, rows, err)
+		return
+	}
+	log.Printf("ClickHouse 异步写入 %s 失败，丢弃 %d 行: %v", table, rows, err)
+}
+
+// estimateSize 按字段类型递归估算值占用的字节数
+func estimateSize(v reflect.Value) int {
+	if !v.IsValid() {
+		return 0
+	}
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if v.IsNil() {
+			return 8
+		}
+		return estimateSize(v.Elem())
+	case reflect.String:
+		return v.Len()
+	case reflect.Slice, reflect.Array:
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			return v.Len()
+		}
+		n := 0
+		for i := 0; i < v.Len(); i++ {
+			n += estimateSize(v.Index(i))
+		}
+		return n
+	case reflect.Map:
+		n := 0
+		iter := v.MapRange()
+		for iter.Next() {
+			n += estimateSize(iter.Key()) + estimateSize(iter.Value())
+		}
+		return n
+	case reflect.Struct:
+		if v.Type() == timeType {
+			return 8
+		}
+		n := 0
+		for i := 0; i < v.NumField(); i++ {
+			n += estimateSize(v.Field(i))
+		}
+		return n
+	default:
+		return int(v.Type().Size())
+	}
+}
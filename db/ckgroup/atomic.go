@@ -0,0 +1,147 @@
+package ckgroup
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ClickHouse/clickhouse-go/v2/lib/driver"
+)
+
+// AtomicOption ExecAtomicBatch 选项
+type AtomicOption func(*atomicOptions)
+
+type atomicOptions struct {
+	replace bool
+}
+
+// WithReplacePartitions 用暂存表中的分区整体替换目标表的同名分区（REPLACE PARTITION），
+// 用于按分区重跑数据；默认追加（ATTACH PARTITION）
+func WithReplacePartitions() AtomicOption {
+	return func(o *atomicOptions) {
+		o.replace = true
+	}
+}
+
+// ExecAtomicBatch 先将 data 分批写入与目标表结构、分区键和排序键相同的暂存表，全部批次成功后再按分区挂载到目标表，
+// 任一批次失败时目标表不可见任何数据。每个分区的挂载是原子的，跨多个分区时挂载中途失败会使已挂载的分区可见，
+// 错误中列出已挂载的分区。目标表必须是 MergeTree 系列的本地表，不支持 Distributed 表；
+// 暂存表建在同一节点上，Replicated 目标表挂载后由副本同步。暂存表在结束时删除
+func (c *ClickHouseClient) ExecAtomicBatch(ctx context.Context, tableName string, data interface{}, opts ...AtomicOption) error {
+	var o atomicOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	dataValue := reflect.ValueOf(data)
+	if dataValue.Kind() != reflect.Slice {
+		return fmt.Errorf("data must be a slice")
+	}
+	if dataValue.Len() == 0 {
+		return nil
+	}
+	columns, err := c.analyzeStructure(dataValue.Index(0).Interface())
+	if err != nil {
+		return fmt.Errorf("failed to analyze data structure: %w", err)
+	}
+
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+	ctx, done := c.instrument(ctx, "insert", "ATOMIC INSERT INTO "+tableName)
+	// 暂存表、写入和挂载必须在同一节点上，不换节点重试
+	err = c.pool.do(ctx, false, func(r *replica) error {
+		return c.atomicInsert(ctx, r, tableName, dataValue, columns, o)
+	})
+	done(err)
+	return err
+}
+
+// atomicInsert 在节点 r 上创建暂存表、写入数据并挂载分区
+func (c *ClickHouseClient) atomicInsert(ctx context.Context, r *replica, tableName string, dataValue reflect.Value, columns []string, o atomicOptions) error {
+	database, table := splitTableName(tableName)
+	query, args := tableQuery("SELECT engine, partition_key, sorting_key, primary_key, sampling_key FROM system.tables", database, table)
+	var engine, partitionKey, sortingKey, primaryKey, samplingKey string
+	if err := r.conn.QueryRow(ctx, query, args...).Scan(&engine, &partitionKey, &sortingKey, &primaryKey, &samplingKey); err != nil {
+		return fmt.Errorf("failed to describe %s: %w", tableName, err)
+	}
+	if !strings.Contains(engine, "MergeTree") {
+		return fmt.Errorf("atomic batch requires a MergeTree table, %s is %s", tableName, engine)
+	}
+
+	stagingTable := table + "_staging_" + strconv.FormatInt(time.Now().UnixNano(), 36)
+	staging := stagingTable
+	if database != "" {
+		staging = database + "." + stagingTable
+	}
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("CREATE TABLE %s AS %s ENGINE = MergeTree", staging, tableName))
+	if partitionKey != "" {
+		sb.WriteString(" PARTITION BY " + partitionKey)
+	}
+	sb.WriteString(" ORDER BY " + keyTuple(sortingKey))
+	if primaryKey != "" && primaryKey != sortingKey {
+		sb.WriteString(" PRIMARY KEY " + keyTuple(primaryKey))
+	}
+	if samplingKey != "" {
+		sb.WriteString(" SAMPLE BY " + samplingKey)
+	}
+	if err := r.conn.Exec(ctx, sb.String()); err != nil {
+		return fmt.Errorf("failed to create staging table %s: %w", staging, err)
+	}
+	defer func() {
+		// 调用方取消后仍需清理暂存表
+		cleanup, cancel := context.WithTimeout(context.WithoutCancel(ctx), 30*time.Second)
+		defer cancel()
+		_ = r.conn.Exec(cleanup, "DROP TABLE IF EXISTS "+staging)
+	}()
+
+	insert := fmt.Sprintf("INSERT INTO %s (%s)", staging, strings.Join(columns, ", "))
+	err := c.insertSlice(dataValue, columns, func() (driver.Batch, error) {
+		return r.conn.PrepareBatch(ctx, insert)
+	})
+	if err != nil {
+		return err
+	}
+
+	query, args = tableQuery("SELECT DISTINCT partition_id FROM system.parts", database, stagingTable)
+	rows, err := r.conn.Query(ctx, query+" AND active ORDER BY partition_id", args...)
+	if err != nil {
+		return fmt.Errorf("failed to list partitions of %s: %w", staging, err)
+	}
+	var partitions []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return err
+		}
+		partitions = append(partitions, id)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	action := "ATTACH"
+	if o.replace {
+		action = "REPLACE"
+	}
+	for i, id := range partitions {
+		stmt := fmt.Sprintf("ALTER TABLE %s %s PARTITION ID %s FROM %s", tableName, action, quoteString(id), staging)
+		if err := r.conn.Exec(ctx, stmt); err != nil {
+			return fmt.Errorf("failed to %s partition %s (attached: %v): %w",
+				strings.ToLower(action), id, partitions[:i], err)
+		}
+	}
+	return nil
+}
+
+// keyTuple 将 system.tables 中逗号分隔的键表达式转换为 ORDER BY 可用的形式
+func keyTuple(key string) string {
+	if key == "" {
+		return "tuple()"
+	}
+	return "(" + key + ")"
+}
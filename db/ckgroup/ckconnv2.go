@@ -3,6 +3,9 @@ package ckgroup
 import (
 	"context"
 	"database/sql"
+	sqldriver "database/sql/driver"
+	"encoding"
+	"errors"
 	"fmt"
 	"github.com/ClickHouse/clickhouse-go/v2"
 	"github.com/ClickHouse/clickhouse-go/v2/lib/driver"
@@ -13,108 +16,299 @@ import (
 
 var CKCONN ClickHouseClient
 
+// Column 列定义
 type Column struct {
-	Name string
-	Type string
+	Name    string
+	Type    string
+	Comment string // 列注释，建表时写入，DescribeTable 读取
 }
 
 // ClickHouseClient ClickHouse客户端
 type ClickHouseClient struct {
-	conn      driver.Conn
-	db        *sql.DB
-	batchSize int
+	pool          *replicaPool // 写入节点，未设置 ReadHosts 时也用于查询
+	readPool      *replicaPool // 只读副本，未设置 ReadHosts 时为 nil
+	batchSize     int
+	timeout       time.Duration
+	slowThreshold time.Duration
+	onQuery       func(QueryStats)
+	cluster       string
+	engine        string
+	zkPath        string
+	replica       string
+	settings      clickhouse.Settings
+	queryID       string
+	quotaKey      string
+	config        Config
 }
 
-// Config 配置结构
+// DefaultCluster 未设置 Config.Cluster 且不是单节点部署时使用的集群名称
+const DefaultCluster = "bms_cluster"
+
+// Config 配置结构，可通过 config.Loader 从配置文件、环境变量加载
 type Config struct {
-	Hosts     string
-	Database  string
-	Username  string
-	Password  string
-	BatchSize int
-	Debug     bool
+	Hosts     string `env:"CLICKHOUSE_HOSTS" default:"127.0.0.1:9000"` // 逗号分隔，设置了 ReadHosts 时只用于写入和 DDL
+	Database  string `env:"CLICKHOUSE_DATABASE" default:"default"`
+	Username  string `env:"CLICKHOUSE_USERNAME" default:"default"`
+	Password  string `env:"CLICKHOUSE_PASSWORD"`
+	BatchSize int    `env:"CLICKHOUSE_BATCH_SIZE"`
+	Debug     bool   `env:"CLICKHOUSE_DEBUG"`
+	// ReadHosts 逗号分隔的只读副本，设置后查询在这些节点间轮询，BatchInsert、Exec 等写入和 DDL 使用 Hosts；
+	// 为空时读写都使用 Hosts
+	ReadHosts string `env:"CLICKHOUSE_READ_HOSTS"`
+	// Timeout 每次调用的默认超时，不带 ctx 的方法和 ctx 未设置截止时间时生效，0表示不限制
+	Timeout time.Duration `env:"CLICKHOUSE_TIMEOUT"`
+	// Cluster 集群名称，建库建表时附加 ON CLUSTER，默认 DefaultCluster（bms_cluster），与早期版本的固定集群名保持一致
+	Cluster string `env:"CLICKHOUSE_CLUSTER" default:"bms_cluster"`
+	// SingleNode 单节点部署，不附加 ON CLUSTER，默认引擎为 MergeTree，不能创建分布式表；设置后忽略 Cluster
+	SingleNode bool `env:"CLICKHOUSE_SINGLE_NODE"`
+	// Engine CreateTable 使用的表引擎，默认集群部署为 ReplicatedMergeTree，SingleNode 为 MergeTree；
+	// 包含括号时原样使用，如 ReplacingMergeTree(version)
+	Engine string `env:"CLICKHOUSE_ENGINE"`
+	// ZooKeeperPath Replicated* 引擎的 ZooKeeper 路径模板，{database}、{table} 由客户端替换，
+	// 其余宏如 {shard} 由服务端替换，默认 /clickhouse/tables/{database}/{shard}/{table}
+	ZooKeeperPath string `env:"CLICKHOUSE_ZOOKEEPER_PATH"`
+	// ReplicaMacro Replicated* 引擎的副本名，默认 {replica}
+	ReplicaMacro string `env:"CLICKHOUSE_REPLICA_MACRO"`
+	// FailureThreshold 节点连续连接失败达到该次数后熔断，不再参与轮询，默认3
+	FailureThreshold int `env:"CLICKHOUSE_FAILURE_THRESHOLD"`
+	// BreakerCooldown 熔断时长，到期后允许一次尝试，默认30秒
+	BreakerCooldown time.Duration `env:"CLICKHOUSE_BREAKER_COOLDOWN"`
+	// ProbeInterval 探测熔断节点的周期，探测成功后立即恢复，默认10秒，仅多节点时生效
+	ProbeInterval time.Duration `env:"CLICKHOUSE_PROBE_INTERVAL"`
+	// SlowThreshold 慢查询阈值，耗时超过该值的调用通过 logger 输出警告日志，0表示不记录
+	SlowThreshold time.Duration `env:"CLICKHOUSE_SLOW_THRESHOLD"`
+	// OnQuery 每次调用结束后的回调，可用于上报耗时、读写行数等指标
+	OnQuery func(QueryStats) `json:"-"`
+	// Protocol 连接协议 native/http，默认 native（9000端口），只能访问 8123 端口时使用 http
+	Protocol string `env:"CLICKHOUSE_PROTOCOL" default:"native"`
+	// Compression 压缩方式 none/lz4/lz4hc/zstd/gzip/deflate/br，默认 native 为 lz4，http 为 gzip
+	Compression string `env:"CLICKHOUSE_COMPRESSION"`
+	// MaxExecutionTime 服务端单个查询的最长执行时间（秒），默认60
+	MaxExecutionTime int `env:"CLICKHOUSE_MAX_EXECUTION_TIME"`
+	// DialTimeout 建立连接超时，默认30秒
+	DialTimeout time.Duration `env:"CLICKHOUSE_DIAL_TIMEOUT"`
+	// MaxOpenConns 每个节点的最大连接数，默认10
+	MaxOpenConns int `env:"CLICKHOUSE_MAX_OPEN_CONNS"`
+	// MaxIdleConns 每个节点的最大空闲连接数，默认5
+	MaxIdleConns int `env:"CLICKHOUSE_MAX_IDLE_CONNS"`
+	// ConnMaxLifetime 连接最长存活时间，默认1小时
+	ConnMaxLifetime time.Duration `env:"CLICKHOUSE_CONN_MAX_LIFETIME"`
 }
 
 // NewClickHouseClient 创建新的ClickHouse客户端
 func NewClickHouseClient(config Config) (*ClickHouseClient, error) {
-	// 使用原生连接
-	addr := strings.Split(config.Hosts, ",")
-	conn, err := clickhouse.Open(&clickhouse.Options{
-		Addr: addr,
-		Auth: clickhouse.Auth{
-			Database: config.Database,
-			Username: config.Username,
-			Password: config.Password,
-		},
-		Debug: config.Debug,
-		Debugf: func(format string, v ...interface{}) {
-			if config.Debug {
-				fmt.Printf("[ClickHouse Debug] "+format+"\n", v...)
-			}
-		},
-		Settings: clickhouse.Settings{
-			"max_execution_time": 60,
-		},
-		Compression: &clickhouse.Compression{
-			Method: clickhouse.CompressionLZ4,
-		},
-		DialTimeout:      time.Second * 30,
-		MaxOpenConns:     10,
-		MaxIdleConns:     5,
-		ConnMaxLifetime:  time.Hour,
-		ConnOpenStrategy: clickhouse.ConnOpenInOrder,
-	})
-
-	if err != nil {
-		return nil, fmt.Errorf("failed to connect to ClickHouse: %w", err)
+	// 每个节点单独建立连接，查询在健康节点间轮询，连接失败的幂等查询换节点重试
+	if err := config.setConnDefaults(); err != nil {
+		return nil, err
 	}
-
-	// 测试连接
-	if err := conn.Ping(context.Background()); err != nil {
-		return nil, fmt.Errorf("failed to ping ClickHouse: %w", err)
+	pool, readPool, err := openPools(config)
+	if err != nil {
+		return nil, err
 	}
 
-	// 创建标准数据库连接用于查询
-	db := clickhouse.OpenDB(&clickhouse.Options{
-		Addr: addr,
-		Auth: clickhouse.Auth{
-			Database: config.Database,
-			Username: config.Username,
-			Password: config.Password,
-		},
-	})
-
 	batchSize := config.BatchSize
 	if batchSize <= 0 {
 		batchSize = 1000
 	}
+	switch {
+	case config.SingleNode:
+		config.Cluster = ""
+	case config.Cluster == "":
+		config.Cluster = DefaultCluster
+	}
+	engine := config.Engine
+	if engine == "" {
+		engine = "MergeTree"
+		if config.Cluster != "" {
+			engine = "ReplicatedMergeTree"
+		}
+	}
+	zkPath := config.ZooKeeperPath
+	if zkPath == "" {
+		zkPath = "/clickhouse/tables/{database}/{shard}/{table}"
+	}
+	replica := config.ReplicaMacro
+	if replica == "" {
+		replica = "{replica}"
+	}
 	ckconn := ClickHouseClient{
-		conn:      conn,
-		db:        db,
+		pool:      pool,
+		readPool:  readPool,
 		batchSize: batchSize,
+		timeout:   config.Timeout,
+
+		slowThreshold: config.SlowThreshold,
+		onQuery:       config.OnQuery,
+		cluster:       config.Cluster,
+		engine:        engine,
+		zkPath:        zkPath,
+		replica:       replica,
+		config:        config,
 	}
 	CKCONN = ckconn
 
 	return &ckconn, nil
 }
 
+// setConnDefaults 填充连接相关配置的默认值并校验协议和压缩方式
+func (config *Config) setConnDefaults() error {
+	switch config.Protocol = strings.ToLower(config.Protocol); config.Protocol {
+	case "":
+		config.Protocol = "native"
+	case "native", "http":
+	default:
+		return fmt.Errorf("unknown clickhouse protocol %q", config.Protocol)
+	}
+	if config.Compression == "" {
+		config.Compression = "lz4"
+		if config.Protocol == "http" {
+			config.Compression = "gzip"
+		}
+	}
+	if _, ok := compressionMethods[strings.ToLower(config.Compression)]; !ok {
+		return fmt.Errorf("unknown clickhouse compression %q", config.Compression)
+	}
+	if config.MaxExecutionTime <= 0 {
+		config.MaxExecutionTime = 60
+	}
+	if config.DialTimeout <= 0 {
+		config.DialTimeout = 30 * time.Second
+	}
+	if config.MaxOpenConns <= 0 {
+		config.MaxOpenConns = 10
+	}
+	if config.MaxIdleConns <= 0 {
+		config.MaxIdleConns = 5
+	}
+	if config.ConnMaxLifetime <= 0 {
+		config.ConnMaxLifetime = time.Hour
+	}
+	return nil
+}
+
+// WithTimeout 返回使用指定调用超时的客户端副本，与原客户端共享连接，如 CKCONN.WithTimeout(time.Minute).Query(sql)
+func (c *ClickHouseClient) WithTimeout(timeout time.Duration) *ClickHouseClient {
+	cc := *c
+	cc.timeout = timeout
+	return &cc
+}
+
+// withTimeout ctx 没有截止时间且设置了调用超时时附加超时
+func (c *ClickHouseClient) withTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	if c.timeout <= 0 {
+		return ctx, func() {}
+	}
+	if _, ok := ctx.Deadline(); ok {
+		return ctx, func() {}
+	}
+	return context.WithTimeout(ctx, c.timeout)
+}
+
+// queryContext 为返回结果集的查询附加调用超时。结果集在方法返回后才被读取，不能在返回前取消，
+// 因此在超时到达时才释放计时器
+func (c *ClickHouseClient) queryContext(ctx context.Context) context.Context {
+	if c.timeout <= 0 {
+		return ctx
+	}
+	if _, ok := ctx.Deadline(); ok {
+		return ctx
+	}
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	context.AfterFunc(ctx, cancel)
+	return ctx
+}
+
+// Ping 检查连接是否可用，多节点时任一节点可用即成功；设置了 ReadHosts 时写入节点和只读副本都需可用
+func (c *ClickHouseClient) Ping(ctx context.Context) error {
+	ping := func(r *replica) error {
+		return r.conn.Ping(ctx)
+	}
+	if err := c.pool.do(ctx, true, ping); err != nil {
+		return err
+	}
+	if c.readPool != nil {
+		return c.readPool.do(ctx, true, ping)
+	}
+	return nil
+}
+
+// Hosts 返回各节点的健康状态，只读副本排在写入节点之后
+func (c *ClickHouseClient) Hosts() []HostStatus {
+	list := c.pool.status("write")
+	if c.readPool != nil {
+		list = append(list, c.readPool.status("read")...)
+	}
+	return list
+}
+
 // Close 关闭连接
 func (c *ClickHouseClient) Close() error {
-	var err1, err2 error
-	if c.conn != nil {
-		err1 = c.conn.Close()
+	err := c.pool.close()
+	if c.readPool != nil {
+		err = errors.Join(err, c.readPool.close())
 	}
-	if c.db != nil {
-		err2 = c.db.Close()
+	return err
+}
+
+// reader 返回查询使用的节点池
+func (c *ClickHouseClient) reader() *replicaPool {
+	if c.readPool != nil {
+		return c.readPool
+	}
+	return c.pool
+}
+
+// query 执行查询，查询是只读的，连接失败时换节点重试
+func (c *ClickHouseClient) query(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	query, args, err := bindNamed(query, args)
+	if err != nil {
+		return nil, err
+	}
+	ctx, done := c.instrument(ctx, "query", query)
+	var rows *sql.Rows
+	err = c.reader().do(ctx, true, func(r *replica) error {
+		var err error
+		rows, err = r.db.QueryContext(ctx, query, args...)
+		return err
+	})
+	done(err)
+	return rows, err
+}
+
+// queryRow 在健康节点上执行单行查询，错误在 Scan 时返回，不重试
+func (c *ClickHouseClient) queryRow(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	ctx, done := c.instrument(ctx, "query", query)
+	row := c.reader().pick(nil).db.QueryRowContext(ctx, query, args...)
+	done(row.Err())
+	return row
+}
+
+// scanRow 执行查询并扫描第一行，没有结果时返回 sql.ErrNoRows
+func (c *ClickHouseClient) scanRow(ctx context.Context, query string, args []interface{}, dest ...interface{}) error {
+	rows, err := c.query(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	if !rows.Next() {
+		if err := rows.Err(); err != nil {
+			return err
+		}
+		return sql.ErrNoRows
 	}
-	if err1 != nil {
-		return err1
+	if err := rows.Scan(dest...); err != nil {
+		return err
 	}
-	return err2
+	return rows.Close()
 }
 
 // BatchInsert 批量插入数据，支持nested结构
 func (c *ClickHouseClient) BatchInsert(tableName string, data interface{}) error {
+	return c.BatchInsertCtx(context.Background(), tableName, data)
+}
+
+// BatchInsertCtx 批量插入数据，见 BatchInsert。调用超时作用于整个插入过程，ctx 取消后未发送的批次不再写入
+func (c *ClickHouseClient) BatchInsertCtx(ctx context.Context, tableName string, data interface{}) error {
 	dataValue := reflect.ValueOf(data)
 	if dataValue.Kind() != reflect.Slice {
 		return fmt.Errorf("data must be a slice")
@@ -124,6 +318,8 @@ func (c *ClickHouseClient) BatchInsert(tableName string, data interface{}) error
 	if dataLen == 0 {
 		return nil
 	}
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
 
 	// 获取第一个元素来分析结构
 	firstElem := dataValue.Index(0).Interface()
@@ -132,14 +328,21 @@ func (c *ClickHouseClient) BatchInsert(tableName string, data interface{}) error
 		return fmt.Errorf("failed to analyze data structure: %w", err)
 	}
 
-	// 分批处理数据
+	return c.insertSlice(dataValue, columns, func() (driver.Batch, error) {
+		return c.prepareBatch(ctx, tableName, columns)
+	})
+}
+
+// insertSlice 将结构体切片按 batchSize 分批追加到 prepare 创建的批次并发送
+func (c *ClickHouseClient) insertSlice(dataValue reflect.Value, columns []string, prepare func() (driver.Batch, error)) error {
+	dataLen := dataValue.Len()
 	for i := 0; i < dataLen; i += c.batchSize {
 		end := i + c.batchSize
 		if end > dataLen {
 			end = dataLen
 		}
 
-		batch, err := c.prepareBatch(tableName, columns)
+		batch, err := prepare()
 		if err != nil {
 			return fmt.Errorf("failed to prepare batch: %w", err)
 		}
@@ -149,10 +352,12 @@ func (c *ClickHouseClient) BatchInsert(tableName string, data interface{}) error
 			item := dataValue.Index(j).Interface()
 			values, err := c.extractValues(item, columns)
 			if err != nil {
+				batch.Abort()
 				return fmt.Errorf("failed to extract values from item %d: %w", j, err)
 			}
 
 			if err := batch.Append(values...); err != nil {
+				batch.Abort()
 				return fmt.Errorf("failed to append data to batch: %w", err)
 			}
 		}
@@ -167,26 +372,52 @@ func (c *ClickHouseClient) BatchInsert(tableName string, data interface{}) error
 }
 
 // prepareBatch 准备批次
-func (c *ClickHouseClient) prepareBatch(tableName string, columns []string) (driver.Batch, error) {
+func (c *ClickHouseClient) prepareBatch(ctx context.Context, tableName string, columns []string) (driver.Batch, error) {
 	sql := fmt.Sprintf("INSERT INTO %s (%s)", tableName, strings.Join(columns, ", "))
-	return c.conn.PrepareBatch(context.Background(), sql)
+	// 数据在 Send 时才发送，准备阶段连接失败可以换节点重试
+	ctx, done := c.instrument(ctx, "insert", sql)
+	var batch driver.Batch
+	err := c.pool.do(ctx, true, func(r *replica) error {
+		var err error
+		batch, err = r.conn.PrepareBatch(ctx, sql)
+		return err
+	})
+	if err != nil {
+		done(err)
+		return nil, err
+	}
+	return &instrumentedBatch{Batch: batch, done: done}, nil
+}
+
+// fieldColumn 结构体字段与插入列的对应关系
+type fieldColumn struct {
+	name  string
+	field int // 结构体字段下标
+	sub   int // Nested 展开时元素结构体的字段下标，否则为 -1
 }
 
-// analyzeStructure 分析数据结构
+// analyzeStructure 分析数据结构，返回插入的列名
 func (c *ClickHouseClient) analyzeStructure(sample interface{}) ([]string, error) {
-	v := reflect.ValueOf(sample)
 	t := reflect.TypeOf(sample)
-
-	if t.Kind() == reflect.Ptr {
-		v = v.Elem()
+	if t != nil && t.Kind() == reflect.Ptr {
 		t = t.Elem()
 	}
-
-	if t.Kind() != reflect.Struct {
+	if t == nil || t.Kind() != reflect.Struct {
 		return nil, fmt.Errorf("data must be struct or pointer to struct")
 	}
 
-	var columns []string
+	fields := c.fieldColumns(t)
+	columns := make([]string, len(fields))
+	for i, f := range fields {
+		columns[i] = f.name
+	}
+	return columns, nil
+}
+
+// fieldColumns 按字段顺序生成列。结构体切片字段按 Nested 列展开为 name.field 的并行数组列，
+// 标签带 tuple 选项（如 `db:"items,tuple"`）时作为单个 Array(Tuple) 列写入
+func (c *ClickHouseClient) fieldColumns(t reflect.Type) []fieldColumn {
+	var columns []fieldColumn
 	for i := 0; i < t.NumField(); i++ {
 		field := t.Field(i)
 
@@ -201,10 +432,53 @@ func (c *ClickHouseClient) analyzeStructure(sample interface{}) ([]string, error
 			continue
 		}
 
-		columns = append(columns, columnName)
+		if elem, ok := nestedElem(field.Type); ok && !hasTagOption(field, "tuple") {
+			for j := 0; j < elem.NumField(); j++ {
+				sub := elem.Field(j)
+				if !sub.IsExported() {
+					continue
+				}
+				if subName := c.getColumnName(sub); subName != "-" {
+					columns = append(columns, fieldColumn{name: columnName + "." + subName, field: i, sub: j})
+				}
+			}
+			continue
+		}
+		columns = append(columns, fieldColumn{name: columnName, field: i, sub: -1})
 	}
+	return columns
+}
 
-	return columns, nil
+// nestedElem 字段类型为结构体（或结构体指针）切片时返回元素结构体类型
+func nestedElem(t reflect.Type) (reflect.Type, bool) {
+	if t.Kind() != reflect.Slice && t.Kind() != reflect.Array {
+		return nil, false
+	}
+	elem := t.Elem()
+	if elem.Kind() == reflect.Ptr {
+		elem = elem.Elem()
+	}
+	return elem, isRecordStruct(elem)
+}
+
+var (
+	valuerType        = reflect.TypeOf((*sqldriver.Valuer)(nil)).Elem()
+	stringerType      = reflect.TypeOf((*fmt.Stringer)(nil)).Elem()
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+)
+
+// isRecordStruct 是否为按字段展开的普通结构体。time.Time 以及实现了 driver.Valuer、fmt.Stringer、
+// encoding.TextMarshaler 的类型（如 decimal.Decimal、sql.NullString）作为单个值写入
+func isRecordStruct(t reflect.Type) bool {
+	if t.Kind() != reflect.Struct || t == timeType {
+		return false
+	}
+	for _, it := range []reflect.Type{valuerType, stringerType, textMarshalerType} {
+		if t.Implements(it) || reflect.PointerTo(t).Implements(it) {
+			return false
+		}
+	}
+	return true
 }
 
 // getColumnName 获取列名
@@ -218,45 +492,82 @@ func (c *ClickHouseClient) getColumnName(field reflect.StructField) string {
 	return strings.ToLower(field.Name)
 }
 
-// extractValues 提取值
+// hasTagOption db 标签是否包含指定选项
+func hasTagOption(field reflect.StructField, option string) bool {
+	opts := strings.Split(field.Tag.Get("db"), ",")
+	for _, opt := range opts[1:] {
+		if strings.TrimSpace(opt) == option {
+			return true
+		}
+	}
+	return false
+}
+
+// extractValues 按 columns 提取一行的值
 func (c *ClickHouseClient) extractValues(item interface{}, columns []string) ([]interface{}, error) {
 	v := reflect.ValueOf(item)
-	t := reflect.TypeOf(item)
-
-	if t.Kind() == reflect.Ptr {
+	if v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return nil, fmt.Errorf("nil item")
+		}
 		v = v.Elem()
-		t = t.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("item must be struct or pointer to struct")
 	}
 
-	var values []interface{}
-	columnIndex := 0
-
-	for i := 0; i < t.NumField(); i++ {
-		field := t.Field(i)
-		fieldValue := v.Field(i)
-
-		if !field.IsExported() {
-			continue
-		}
-
-		columnName := c.getColumnName(field)
-		if columnName == "-" {
-			continue
+	fields := c.fieldColumns(v.Type())
+	if len(fields) != len(columns) {
+		return nil, fmt.Errorf("item type %s does not match columns", v.Type())
+	}
+	values := make([]interface{}, len(fields))
+	for i, f := range fields {
+		if f.sub < 0 {
+			fv := v.Field(f.field)
+			if fv.Kind() == reflect.Ptr && fv.IsNil() {
+				// nil 指针写入 NULL，设置了 default 选项时写入默认值，用于非 Nullable 列
+				if def, ok := tagDefault(v.Type().Field(f.field)); ok {
+					dv, err := parseDefault(def, fv.Type().Elem())
+					if err != nil {
+						return nil, fmt.Errorf("field %s: %w", v.Type().Field(f.field).Name, err)
+					}
+					values[i] = dv.Interface()
+					continue
+				}
+			}
+			values[i] = c.convertValue(fv)
+		} else {
+			values[i] = nestedColumn(v.Field(f.field), f.sub)
 		}
+	}
+	return values, nil
+}
 
-		if columnIndex >= len(columns) {
-			break
+// nestedColumn 提取结构体切片中每个元素的第 sub 个字段，组成 Nested 子列的类型化数组
+func nestedColumn(list reflect.Value, sub int) interface{} {
+	elemType := list.Type().Elem()
+	isPtr := elemType.Kind() == reflect.Ptr
+	if isPtr {
+		elemType = elemType.Elem()
+	}
+	fieldType := elemType.Field(sub).Type
+	result := reflect.MakeSlice(reflect.SliceOf(fieldType), list.Len(), list.Len())
+	for i := 0; i < list.Len(); i++ {
+		elem := list.Index(i)
+		if isPtr {
+			if elem.IsNil() {
+				// 空元素按零值写入，保持各子列长度一致
+				continue
+			}
+			elem = elem.Elem()
 		}
-
-		value := c.convertValue(fieldValue)
-		values = append(values, value)
-		columnIndex++
+		result.Index(i).Set(elem.Field(sub))
 	}
-
-	return values, nil
+	return result.Interface()
 }
 
-// convertValue 转换值
+// convertValue 转换值：基础类型、Map 和 LowCardinality 列的值原样写入；
+// 结构体按字段顺序转换为 Tuple；结构体切片转换为 Array(Tuple)；指针解引用，nil 写入 NULL
 func (c *ClickHouseClient) convertValue(fieldValue reflect.Value) interface{} {
 	if !fieldValue.IsValid() {
 		return nil
@@ -264,36 +575,28 @@ func (c *ClickHouseClient) convertValue(fieldValue reflect.Value) interface{} {
 
 	switch fieldValue.Kind() {
 	case reflect.Slice, reflect.Array:
-		// 处理数组/切片类型，包括nested结构
-		if fieldValue.Len() == 0 {
-			return []interface{}{}
-		}
-
-		elemType := fieldValue.Type().Elem()
-		if elemType.Kind() == reflect.Struct {
-			// 处理nested结构体数组
-			var result []map[string]interface{}
-			for i := 0; i < fieldValue.Len(); i++ {
-				elem := fieldValue.Index(i)
-				elemMap := make(map[string]interface{})
-				elemType := elem.Type()
-
-				for j := 0; j < elem.NumField(); j++ {
-					if !elemType.Field(j).IsExported() {
-						continue
-					}
-					fieldName := c.getColumnName(elemType.Field(j))
-					if fieldName != "-" {
-						elemMap[fieldName] = c.convertValue(elem.Field(j))
-					}
-				}
-				result = append(result, elemMap)
+		if _, ok := nestedElem(fieldValue.Type()); ok {
+			result := make([]interface{}, fieldValue.Len())
+			for i := range result {
+				result[i] = c.convertValue(fieldValue.Index(i))
 			}
 			return result
-		} else {
-			// 基础类型数组
+		}
+		// 基础类型数组原样写入，保留元素类型
+		return fieldValue.Interface()
+	case reflect.Struct:
+		if !isRecordStruct(fieldValue.Type()) {
 			return fieldValue.Interface()
 		}
+		var tuple []interface{}
+		t := fieldValue.Type()
+		for i := 0; i < t.NumField(); i++ {
+			if !t.Field(i).IsExported() || c.getColumnName(t.Field(i)) == "-" {
+				continue
+			}
+			tuple = append(tuple, c.convertValue(fieldValue.Field(i)))
+		}
+		return tuple
 	case reflect.Ptr:
 		if fieldValue.IsNil() {
 			return nil
@@ -304,18 +607,33 @@ func (c *ClickHouseClient) convertValue(fieldValue reflect.Value) interface{} {
 	}
 }
 
-// Query 执行查询
+// Query 执行查询，args 为位置参数（? 占位符）或单个 Params 命名参数
 func (c *ClickHouseClient) Query(query string, args ...interface{}) (*sql.Rows, error) {
-	return c.db.QueryContext(context.Background(), query, args...)
+	return c.QueryCtx(context.Background(), query, args...)
+}
+
+// QueryCtx 执行查询，ctx 取消或超时后查询被中止，读取结果集返回错误
+func (c *ClickHouseClient) QueryCtx(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	return c.query(c.queryContext(ctx), query, args...)
 }
 
 // QueryRow 执行单行查询
 func (c *ClickHouseClient) QueryRow(query string, args ...interface{}) *sql.Row {
-	return c.db.QueryRowContext(context.Background(), query, args...)
+	return c.QueryRowCtx(context.Background(), query, args...)
+}
+
+// QueryRowCtx 执行单行查询，不支持 Params 命名参数，需要时使用 QueryCtx
+func (c *ClickHouseClient) QueryRowCtx(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	return c.queryRow(c.queryContext(ctx), query, args...)
 }
 
 // QueryToStruct 查询并映射到结构体切片
 func (c *ClickHouseClient) QueryToStruct(dest interface{}, query string, args ...interface{}) error {
+	return c.QueryToStructCtx(context.Background(), dest, query, args...)
+}
+
+// QueryToStructCtx 查询并映射到结构体切片，见 QueryToStruct
+func (c *ClickHouseClient) QueryToStructCtx(ctx context.Context, dest interface{}, query string, args ...interface{}) error {
 	destValue := reflect.ValueOf(dest)
 	if destValue.Kind() != reflect.Ptr || destValue.Elem().Kind() != reflect.Slice {
 		return fmt.Errorf("dest must be a pointer to slice")
@@ -336,18 +654,20 @@ func (c *ClickHouseClient) QueryToStruct(dest interface{}, query string, args ..
 		return fmt.Errorf("slice elements must be structs")
 	}
 
-	rows, err := c.Query(query, args...)
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+	rows, err := c.query(ctx, query, args...)
 	if err != nil {
 		return err
 	}
 	defer rows.Close()
 
-	columns, err := rows.Columns()
+	r, err := c.newRows(rows)
 	if err != nil {
 		return err
 	}
 
-	for rows.Next() {
+	for r.Next() {
 		// 创建新的结构体实例
 		var newElem reflect.Value
 		if isPtr {
@@ -356,40 +676,23 @@ func (c *ClickHouseClient) QueryToStruct(dest interface{}, query string, args ..
 			newElem = reflect.New(actualElemType).Elem()
 		}
 
-		// 准备扫描目标
-		scanDest := make([]interface{}, len(columns))
 		structValue := newElem
 		if isPtr {
 			structValue = newElem.Elem()
 		}
-
-		for i, col := range columns {
-			field := c.findStructField(structValue, col)
-			if field.IsValid() && field.CanSet() {
-				scanDest[i] = field.Addr().Interface()
-			} else {
-				var dummy interface{}
-				scanDest[i] = &dummy
-			}
-		}
-
-		if err := rows.Scan(scanDest...); err != nil {
+		if err := r.scanStruct(structValue); err != nil {
 			return err
 		}
 
 		// 添加到切片
-		if isPtr {
-			sliceValue.Set(reflect.Append(sliceValue, newElem))
-		} else {
-			sliceValue.Set(reflect.Append(sliceValue, newElem))
-		}
+		sliceValue.Set(reflect.Append(sliceValue, newElem))
 	}
 
-	return rows.Err()
+	return r.Err()
 }
 
 // findStructField 查找结构体字段
-func (c *ClickHouseClient) findStructField(structValue reflect.Value, columnName string) reflect.Value {
+func (c *ClickHouseClient) findStructField(structValue reflect.Value, columnName string) (reflect.Value, reflect.StructField) {
 	structType := structValue.Type()
 
 	for i := 0; i < structType.NumField(); i++ {
@@ -400,30 +703,55 @@ func (c *ClickHouseClient) findStructField(structValue reflect.Value, columnName
 
 		fieldColumnName := c.getColumnName(field)
 		if fieldColumnName == columnName {
-			return structValue.Field(i)
+			return structValue.Field(i), field
 		}
 	}
 
-	return reflect.Value{}
+	return reflect.Value{}, reflect.StructField{}
 }
 
 // Exec 执行SQL语句
 func (c *ClickHouseClient) Exec(query string, args ...interface{}) error {
-	return c.conn.Exec(context.Background(), query, args...)
+	return c.ExecCtx(context.Background(), query, args...)
+}
+
+// ExecCtx 执行SQL语句
+func (c *ClickHouseClient) ExecCtx(ctx context.Context, query string, args ...interface{}) error {
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+	query, args, err := bindNamed(query, args)
+	if err != nil {
+		return err
+	}
+	ctx, done := c.instrument(ctx, "exec", query)
+	// 写入和 DDL 不一定幂等，不重试
+	err = c.pool.do(ctx, false, func(r *replica) error {
+		return r.conn.Exec(ctx, query, args...)
+	})
+	done(err)
+	return err
 }
 
 // Count 获取表记录数
 func (c *ClickHouseClient) Count(tableName string, where string, args ...interface{}) (int64, error) {
+	return c.CountCtx(context.Background(), tableName, where, args...)
+}
+
+// CountCtx 获取表记录数
+func (c *ClickHouseClient) CountCtx(ctx context.Context, tableName string, where string, args ...interface{}) (int64, error) {
 	query := fmt.Sprintf("SELECT COUNT(*) FROM %s", tableName)
 	if where != "" {
 		query += " WHERE " + where
 	}
 
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
 	var count int64
-	err := c.QueryRow(query, args...).Scan(&count)
+	err := c.scanRow(ctx, query, args, &count)
 	return count, err
 }
 
+// CreateTable 创建按月分区的本地表，表必须包含 created_at 列；非 SingleNode 部署时在集群所有节点上创建
 func (c *ClickHouseClient) CreateTable(database, table, order, desc string, cols []Column) error {
 	hasCreatedAt := false
 	for _, col := range cols {
@@ -436,19 +764,14 @@ func (c *ClickHouseClient) CreateTable(database, table, order, desc string, cols
 		return fmt.Errorf("created_at column is required for table %s.%s", database, table)
 	}
 
-	if err := c.Exec(fmt.Sprintf("CREATE DATABASE IF NOT EXISTS %s ON CLUSTER bms_cluster", database)); err != nil {
+	if err := c.Exec(fmt.Sprintf("CREATE DATABASE IF NOT EXISTS %s%s", database, c.onCluster())); err != nil {
 		return err
 	}
 
 	var sb strings.Builder
-	sb.WriteString(fmt.Sprintf("CREATE TABLE IF NOT EXISTS %s.%s ON CLUSTER bms_cluster (\n", database, table))
-	for i, col := range cols {
-		sb.WriteString(fmt.Sprintf("  %s %s", col.Name, col.Type))
-		if i < len(cols)-1 {
-			sb.WriteString(",\n")
-		}
-	}
-	sb.WriteString(fmt.Sprintf("\n)\nENGINE = ReplicatedMergeTree('/clickhouse/tables/%s/{shard}/%s', '{replica}')\n", database, table))
+	sb.WriteString(fmt.Sprintf("CREATE TABLE IF NOT EXISTS %s.%s%s (\n", database, table, c.onCluster()))
+	writeColumns(&sb, cols)
+	sb.WriteString(fmt.Sprintf("\n)\nENGINE = %s\n", c.engineClause(database, table)))
 	sb.WriteString("PARTITION BY toYYYYMM(created_at)\n")
 	sb.WriteString(fmt.Sprintf("ORDER BY (%s, intHash64(created_at))\n", order))
 	sb.WriteString("SAMPLE BY intHash64(created_at)\n")
@@ -457,27 +780,58 @@ func (c *ClickHouseClient) CreateTable(database, table, order, desc string, cols
 
 	return c.Exec(sb.String())
 }
+
+// CreateDistributedTable 在集群上创建 localTable 的分布式表 localTable_distributed，SingleNode 部署时返回错误
 func (c *ClickHouseClient) CreateDistributedTable(distDB, localTable, desc string, cols []Column) error {
 	if len(cols) == 0 {
 		return fmt.Errorf("columns must be provided")
 	}
+	if c.cluster == "" {
+		return fmt.Errorf("distributed table %s.%s requires a cluster, not available in single-node mode", distDB, localTable)
+	}
 
-	if err := c.Exec(fmt.Sprintf("CREATE DATABASE IF NOT EXISTS %s ON CLUSTER bms_cluster", distDB)); err != nil {
+	if err := c.Exec(fmt.Sprintf("CREATE DATABASE IF NOT EXISTS %s%s", distDB, c.onCluster())); err != nil {
 		return err
 	}
 
 	var sb strings.Builder
-	sb.WriteString(fmt.Sprintf("CREATE TABLE IF NOT EXISTS %s.%s ON CLUSTER bms_cluster (\n", distDB, localTable+"_distributed"))
+	sb.WriteString(fmt.Sprintf("CREATE TABLE IF NOT EXISTS %s.%s%s (\n", distDB, localTable+"_distributed", c.onCluster()))
+	writeColumns(&sb, cols)
+	sb.WriteString(fmt.Sprintf("\n)\nENGINE = Distributed('%s', '%s', '%s')\n", c.cluster, distDB, localTable))
+	sb.WriteString(fmt.Sprintf("COMMENT '%s';", desc))
+
+	return c.Exec(sb.String())
+}
 
+// onCluster 设置了集群时返回 ON CLUSTER 子句
+func (c *ClickHouseClient) onCluster() string {
+	if c.cluster == "" {
+		return ""
+	}
+	return " ON CLUSTER " + c.cluster
+}
+
+// engineClause 生成表引擎子句，Replicated* 引擎附加 ZooKeeper 路径和副本名
+func (c *ClickHouseClient) engineClause(database, table string) string {
+	if strings.Contains(c.engine, "(") {
+		return c.engine
+	}
+	if strings.HasPrefix(c.engine, "Replicated") {
+		path := strings.NewReplacer("{database}", database, "{table}", table).Replace(c.zkPath)
+		return fmt.Sprintf("%s('%s', '%s')", c.engine, path, c.replica)
+	}
+	return c.engine + "()"
+}
+
+// writeColumns 写入列定义
+func writeColumns(sb *strings.Builder, cols []Column) {
 	for i, col := range cols {
 		sb.WriteString(fmt.Sprintf("  %s %s", col.Name, col.Type))
+		if col.Comment != "" {
+			sb.WriteString(" COMMENT " + quoteString(col.Comment))
+		}
 		if i < len(cols)-1 {
 			sb.WriteString(",\n")
 		}
 	}
-
-	sb.WriteString(fmt.Sprintf("\n)\nENGINE = Distributed('bms_cluster', '%s', '%s')\n", distDB, localTable))
-	sb.WriteString(fmt.Sprintf("COMMENT '%s';", desc))
-
-	return c.Exec(sb.String())
 }
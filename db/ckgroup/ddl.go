@@ -0,0 +1,106 @@
+package ckgroup
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// ErrNotConfirmed 破坏性操作未通过 WithConfirm 确认
+var ErrNotConfirmed = errors.New("ckgroup: destructive operation requires WithConfirm")
+
+// DDLOption DDL 操作选项
+type DDLOption func(*ddlOptions)
+
+type ddlOptions struct {
+	dryRun    bool
+	confirm   bool
+	partition string
+	final     bool
+}
+
+// WithDryRun 只生成语句，不执行
+func WithDryRun() DDLOption {
+	return func(o *ddlOptions) {
+		o.dryRun = true
+	}
+}
+
+// WithConfirm 确认执行删除数据的操作（DropTable、TruncateTable、AlterTTL），未确认时返回 ErrNotConfirmed
+func WithConfirm() DDLOption {
+	return func(o *ddlOptions) {
+		o.confirm = true
+	}
+}
+
+// WithPartition OptimizeTable 只合并指定分区，如 '202401' 或 tuple()
+func WithPartition(partition string) DDLOption {
+	return func(o *ddlOptions) {
+		o.partition = partition
+	}
+}
+
+// WithFinal OptimizeTable 强制合并为一个数据片段，即使只有一个片段也会重写
+func WithFinal() DDLOption {
+	return func(o *ddlOptions) {
+		o.final = true
+	}
+}
+
+// DropTable 删除表，非 SingleNode 部署时在集群所有节点上执行；需要 WithConfirm，返回执行（或 WithDryRun 时将要执行）的语句
+func (c *ClickHouseClient) DropTable(ctx context.Context, table string, opts ...DDLOption) (string, error) {
+	stmt := fmt.Sprintf("DROP TABLE IF EXISTS %s%s", table, c.onCluster())
+	return c.runDDL(ctx, stmt, true, opts)
+}
+
+// TruncateTable 清空表数据，需要 WithConfirm
+func (c *ClickHouseClient) TruncateTable(ctx context.Context, table string, opts ...DDLOption) (string, error) {
+	stmt := fmt.Sprintf("TRUNCATE TABLE IF EXISTS %s%s", table, c.onCluster())
+	return c.runDDL(ctx, stmt, true, opts)
+}
+
+// AlterTTL 修改表的 TTL 表达式，如 "created_at + INTERVAL 90 DAY"，ttl 为空时移除 TTL。
+// 过期数据会在合并时被删除，修改需要 WithConfirm
+func (c *ClickHouseClient) AlterTTL(ctx context.Context, table, ttl string, opts ...DDLOption) (string, error) {
+	stmt := fmt.Sprintf("ALTER TABLE %s%s MODIFY TTL %s", table, c.onCluster(), ttl)
+	if ttl == "" {
+		stmt = fmt.Sprintf("ALTER TABLE %s%s REMOVE TTL", table, c.onCluster())
+	}
+	return c.runDDL(ctx, stmt, ttl != "", opts)
+}
+
+// OptimizeTable 触发表的数据片段合并，可通过 WithPartition、WithFinal 指定分区和强制合并
+func (c *ClickHouseClient) OptimizeTable(ctx context.Context, table string, opts ...DDLOption) (string, error) {
+	o := applyDDLOptions(opts)
+	stmt := fmt.Sprintf("OPTIMIZE TABLE %s%s", table, c.onCluster())
+	if o.partition != "" {
+		stmt += " PARTITION " + o.partition
+	}
+	if o.final {
+		stmt += " FINAL"
+	}
+	return c.runDDL(ctx, stmt, false, opts)
+}
+
+// runDDL 检查确认并执行语句
+func (c *ClickHouseClient) runDDL(ctx context.Context, stmt string, destructive bool, opts []DDLOption) (string, error) {
+	o := applyDDLOptions(opts)
+	if o.dryRun {
+		return stmt, nil
+	}
+	if destructive && !o.confirm {
+		return stmt, fmt.Errorf("%w: %s", ErrNotConfirmed, stmt)
+	}
+	if err := c.ExecCtx(ctx, stmt); err != nil {
+		return stmt, err
+	}
+	return stmt, nil
+}
+
+func applyDDLOptions(opts []DDLOption) ddlOptions {
+	var o ddlOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
@@ -0,0 +1,344 @@
+package ckgroup
+
+import (
+	"context"
+	"database/sql"
+	sqldriver "database/sql/driver"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	"github.com/ClickHouse/clickhouse-go/v2"
+	"github.com/ClickHouse/clickhouse-go/v2/lib/driver"
+)
+
+// HostStatus 单个节点的健康状态
+type HostStatus struct {
+	Addr      string
+	Role      string    // write 写入节点，read 只读副本
+	Healthy   bool      // 熔断器关闭，节点参与轮询
+	Failures  int       // 连续连接失败次数
+	LastError string    // 最近一次连接失败的错误
+	OpenUntil time.Time // 熔断到期时间，到期前只有探测成功才会恢复
+}
+
+// replica 单个节点的连接
+type replica struct {
+	addr string
+	conn driver.Conn
+	db   *sql.DB
+
+	mu        sync.Mutex
+	failures  int
+	lastError string
+	openUntil time.Time
+}
+
+// replicaPool 多节点连接池，按轮询选择健康节点，连续连接失败达到阈值的节点熔断并由后台协程定期探测恢复
+type replicaPool struct {
+	replicas  []*replica
+	next      atomic.Uint32
+	threshold int
+	cooldown  time.Duration
+	stop      chan struct{}
+	once      sync.Once
+}
+
+// openPools 建立写入节点池，设置了 ReadHosts 时另外建立只读副本池
+func openPools(config Config) (*replicaPool, *replicaPool, error) {
+	addrs := splitHosts(config.Hosts)
+	if len(addrs) == 0 {
+		return nil, nil, fmt.Errorf("clickhouse hosts must be provided")
+	}
+	pool, err := openPool(config, addrs)
+	if err != nil {
+		return nil, nil, err
+	}
+	readAddrs := splitHosts(config.ReadHosts)
+	if len(readAddrs) == 0 {
+		return pool, nil, nil
+	}
+	readPool, err := openPool(config, readAddrs)
+	if err != nil {
+		pool.close()
+		return nil, nil, fmt.Errorf("failed to open read replicas: %w", err)
+	}
+	return pool, readPool, nil
+}
+
+// splitHosts 拆分逗号分隔的地址列表
+func splitHosts(hosts string) []string {
+	var addrs []string
+	for _, addr := range strings.Split(hosts, ",") {
+		if addr = strings.TrimSpace(addr); addr != "" {
+			addrs = append(addrs, addr)
+		}
+	}
+	return addrs
+}
+
+// openPool 为每个地址建立连接，至少一个节点可用时成功
+func openPool(config Config, addrs []string) (*replicaPool, error) {
+	p := &replicaPool{
+		threshold: config.FailureThreshold,
+		cooldown:  config.BreakerCooldown,
+		stop:      make(chan struct{}),
+	}
+	if p.threshold <= 0 {
+		p.threshold = 3
+	}
+	if p.cooldown <= 0 {
+		p.cooldown = 30 * time.Second
+	}
+
+	var errs []error
+	for _, addr := range addrs {
+		r, err := openReplica(config, addr)
+		if err != nil {
+			p.close()
+			return nil, fmt.Errorf("failed to connect to ClickHouse %s: %w", addr, err)
+		}
+		p.replicas = append(p.replicas, r)
+		// 启动时不可用的节点直接熔断，由探测协程恢复
+		if err := r.conn.Ping(context.Background()); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", addr, err))
+			r.failures, r.lastError = p.threshold, err.Error()
+			r.openUntil = time.Now().Add(p.cooldown)
+		}
+	}
+	if len(errs) == len(p.replicas) {
+		p.close()
+		return nil, fmt.Errorf("failed to ping ClickHouse: %w", errors.Join(errs...))
+	}
+
+	if len(p.replicas) > 1 {
+		interval := config.ProbeInterval
+		if interval <= 0 {
+			interval = 10 * time.Second
+		}
+		go p.probe(interval)
+	}
+	return p, nil
+}
+
+// compressionMethods 压缩方式名称
+var compressionMethods = map[string]clickhouse.CompressionMethod{
+	"none":    clickhouse.CompressionNone,
+	"lz4":     clickhouse.CompressionLZ4,
+	"lz4hc":   clickhouse.CompressionLZ4HC,
+	"zstd":    clickhouse.CompressionZSTD,
+	"gzip":    clickhouse.CompressionGZIP,
+	"deflate": clickhouse.CompressionDeflate,
+	"br":      clickhouse.CompressionBrotli,
+}
+
+// openReplica 建立单个节点的原生连接和标准库连接，config 已由 setConnDefaults 填充默认值
+func openReplica(config Config, addr string) (*replica, error) {
+	protocol := clickhouse.Native
+	if config.Protocol == "http" {
+		protocol = clickhouse.HTTP
+	}
+	options := func() *clickhouse.Options {
+		return &clickhouse.Options{
+			Protocol: protocol,
+			Addr:     []string{addr},
+			Auth: clickhouse.Auth{
+				Database: config.Database,
+				Username: config.Username,
+				Password: config.Password,
+			},
+			Debug: config.Debug,
+			Debugf: func(format string, v ...interface{}) {
+				if config.Debug {
+					fmt.Printf("[ClickHouse Debug] "+format+"\n", v...)
+				}
+			},
+			Settings: clickhouse.Settings{
+				"max_execution_time": config.MaxExecutionTime,
+			},
+			Compression: &clickhouse.Compression{
+				Method: compressionMethods[strings.ToLower(config.Compression)],
+			},
+			DialTimeout:     config.DialTimeout,
+			MaxOpenConns:    config.MaxOpenConns,
+			MaxIdleConns:    config.MaxIdleConns,
+			ConnMaxLifetime: config.ConnMaxLifetime,
+		}
+	}
+	conn, err := clickhouse.Open(options())
+	if err != nil {
+		return nil, err
+	}
+	// 标准数据库连接用于查询
+	db := clickhouse.OpenDB(options())
+	db.SetMaxOpenConns(config.MaxOpenConns)
+	db.SetMaxIdleConns(config.MaxIdleConns)
+	db.SetConnMaxLifetime(config.ConnMaxLifetime)
+	return &replica{addr: addr, conn: conn, db: db}, nil
+}
+
+// healthy 熔断器关闭或熔断已到期（半开，允许一次尝试）
+func (r *replica) healthy(now time.Time, threshold int) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.failures < threshold || now.After(r.openUntil)
+}
+
+// pick 轮询选择未尝试过的健康节点，没有健康节点时选择未尝试过的任意节点
+func (p *replicaPool) pick(tried []*replica) *replica {
+	n := len(p.replicas)
+	start := int(p.next.Add(1))
+	now := time.Now()
+	var fallback *replica
+	for i := 0; i < n; i++ {
+		r := p.replicas[(start+i)%n]
+		if contains(tried, r) {
+			continue
+		}
+		if r.healthy(now, p.threshold) {
+			return r
+		}
+		if fallback == nil {
+			fallback = r
+		}
+	}
+	if fallback != nil {
+		return fallback
+	}
+	return p.replicas[start%n]
+}
+
+// do 在选中的节点上执行 fn。retry 为 true 时（只用于幂等操作）连接类错误换下一个节点重试，每个节点最多尝试一次
+func (p *replicaPool) do(ctx context.Context, retry bool, fn func(r *replica) error) error {
+	attempts := 1
+	if retry {
+		attempts = len(p.replicas)
+	}
+	var tried []*replica
+	var err error
+	for i := 0; i < attempts; i++ {
+		r := p.pick(tried)
+		tried = append(tried, r)
+		err = fn(r)
+		p.report(r, err)
+		if err == nil || !isConnError(err) || ctx.Err() != nil {
+			return err
+		}
+	}
+	return err
+}
+
+// report 记录执行结果，成功时重置失败计数，连续连接失败达到阈值时熔断
+func (p *replicaPool) report(r *replica, err error) {
+	if err != nil && !isConnError(err) {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err == nil {
+		r.failures = 0
+		return
+	}
+	r.failures++
+	r.lastError = err.Error()
+	if r.failures >= p.threshold {
+		r.openUntil = time.Now().Add(p.cooldown)
+	}
+}
+
+// probe 定期探测熔断的节点，恢复后重新参与轮询
+func (p *replicaPool) probe(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-p.stop:
+			return
+		case <-ticker.C:
+		}
+		for _, r := range p.replicas {
+			r.mu.Lock()
+			broken := r.failures >= p.threshold
+			r.mu.Unlock()
+			if !broken {
+				continue
+			}
+			ctx, cancel := context.WithTimeout(context.Background(), interval)
+			err := r.conn.Ping(ctx)
+			cancel()
+			if err == nil {
+				p.report(r, nil)
+			}
+		}
+	}
+}
+
+// status 返回各节点健康状态
+func (p *replicaPool) status(role string) []HostStatus {
+	now := time.Now()
+	list := make([]HostStatus, len(p.replicas))
+	for i, r := range p.replicas {
+		healthy := r.healthy(now, p.threshold)
+		r.mu.Lock()
+		list[i] = HostStatus{
+			Addr:      r.addr,
+			Role:      role,
+			Healthy:   healthy,
+			Failures:  r.failures,
+			LastError: r.lastError,
+			OpenUntil: r.openUntil,
+		}
+		r.mu.Unlock()
+	}
+	return list
+}
+
+// close 停止探测并关闭所有连接
+func (p *replicaPool) close() error {
+	p.once.Do(func() { close(p.stop) })
+	var errs []error
+	for _, r := range p.replicas {
+		if err := r.conn.Close(); err != nil {
+			errs = append(errs, err)
+		}
+		if err := r.db.Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// isConnError 是否为连接类错误（节点不可达、连接断开），服务端返回的查询错误和 ctx 取消不算
+func isConnError(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	var exception *clickhouse.Exception
+	if errors.As(err, &exception) {
+		return false
+	}
+	var netErr net.Error
+	return errors.Is(err, sqldriver.ErrBadConn) ||
+		errors.Is(err, io.EOF) ||
+		errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, syscall.ECONNREFUSED) ||
+		errors.Is(err, syscall.ECONNRESET) ||
+		errors.Is(err, syscall.EPIPE) ||
+		errors.Is(err, clickhouse.ErrAcquireConnTimeout) ||
+		errors.As(err, &netErr)
+}
+
+func contains(list []*replica, r *replica) bool {
+	for _, x := range list {
+		if x == r {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,117 @@
+package ckgroup
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/ClickHouse/clickhouse-go/v2"
+	"github.com/ClickHouse/clickhouse-go/v2/lib/driver"
+	"github.com/ixxmi/tools/logger"
+	"github.com/ixxmi/tools/tracing"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// QueryStats 单次调用的统计信息，读写行数和内存来自服务端推送的进度和 ProfileEvents。
+// Query/QueryRow 返回结果集后即结束统计，之后读取结果集的时间和行数不计入
+type QueryStats struct {
+	Operation    string // query/exec/insert
+	Query        string
+	Duration     time.Duration
+	ReadRows     uint64
+	ReadBytes    uint64
+	WrittenRows  uint64
+	WrittenBytes uint64
+	MemoryUsage  int64 // 服务端内存峰值，服务端未推送时为0
+	Err          error
+}
+
+// statsCollector 收集服务端推送的进度，回调可能在读取协程中并发调用
+type statsCollector struct {
+	mu    sync.Mutex
+	stats QueryStats
+}
+
+func (s *statsCollector) progress(p *clickhouse.Progress) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.stats.ReadRows += p.Rows
+	s.stats.ReadBytes += p.Bytes
+	s.stats.WrittenRows += p.WroteRows
+	s.stats.WrittenBytes += p.WroteBytes
+}
+
+func (s *statsCollector) profileEvents(events []clickhouse.ProfileEvent) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, e := range events {
+		if e.Name == "MemoryTrackerPeakUsage" || e.Name == "MemoryTrackerUsage" {
+			s.stats.MemoryUsage = max(s.stats.MemoryUsage, e.Value)
+		}
+	}
+}
+
+// instrument 开始一次调用的追踪和统计，返回的 done 在调用结束时执行：结束 Span、调用 Config.OnQuery、
+// 超过 Config.SlowThreshold 时通过 logger 输出慢查询日志
+func (c *ClickHouseClient) instrument(ctx context.Context, operation, query string) (context.Context, func(err error)) {
+	ctx = c.queryOptions(ctx)
+	ctx, span := tracing.StartDBSpan(ctx, "clickhouse", operation, query)
+	collector := &statsCollector{}
+	if c.onQuery != nil || c.slowThreshold > 0 {
+		ctx = clickhouse.Context(ctx,
+			clickhouse.WithProgress(collector.progress),
+			clickhouse.WithProfileEvents(collector.profileEvents),
+		)
+	}
+	start := time.Now()
+	return ctx, func(err error) {
+		collector.mu.Lock()
+		stats := collector.stats
+		collector.mu.Unlock()
+		stats.Operation, stats.Query, stats.Err = operation, query, err
+		stats.Duration = time.Since(start)
+
+		span.SetAttributes(
+			attribute.Int64("db.clickhouse.read_rows", int64(stats.ReadRows)),
+			attribute.Int64("db.clickhouse.written_rows", int64(stats.WrittenRows)),
+		)
+		tracing.RecordError(span, err)
+		span.End()
+
+		if c.onQuery != nil {
+			c.onQuery(stats)
+		}
+		if c.slowThreshold > 0 && stats.Duration >= c.slowThreshold {
+			logger.Warnf("ClickHouse 慢查询 %s 耗时 %s，读取 %d 行，写入 %d 行，内存 %d 字节: %s",
+				operation, stats.Duration, stats.ReadRows, stats.WrittenRows, stats.MemoryUsage, truncateQuery(query))
+		}
+	}
+}
+
+// truncateQuery 截断过长的语句，避免批量 SQL 撑大日志
+func truncateQuery(query string) string {
+	const maxLen = 1024
+	if len(query) > maxLen {
+		return query[:maxLen] + "..."
+	}
+	return query
+}
+
+// instrumentedBatch 在 Send 或 Abort 时结束统计的批次
+type instrumentedBatch struct {
+	driver.Batch
+	once sync.Once
+	done func(err error)
+}
+
+func (b *instrumentedBatch) Send() error {
+	err := b.Batch.Send()
+	b.once.Do(func() { b.done(err) })
+	return err
+}
+
+func (b *instrumentedBatch) Abort() error {
+	err := b.Batch.Abort()
+	b.once.Do(func() { b.done(context.Canceled) })
+	return err
+}
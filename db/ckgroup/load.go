@@ -0,0 +1,361 @@
+package ckgroup
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// Format 导入文件格式
+type Format int
+
+const (
+	// FormatCSV 逗号分隔，默认首行为表头
+	FormatCSV Format = iota
+	// FormatTSV 制表符分隔，转义规则同 ClickHouse TabSeparated，默认首行为表头
+	FormatTSV
+	// FormatNDJSON 每行一个 JSON 对象
+	FormatNDJSON
+)
+
+// String 返回格式名称
+func (f Format) String() string {
+	switch f {
+	case FormatCSV:
+		return "CSV"
+	case FormatTSV:
+		return "TSV"
+	case FormatNDJSON:
+		return "NDJSON"
+	}
+	return fmt.Sprintf("Format(%d)", int(f))
+}
+
+// maxLoadErrors LoadReport 中最多保留的错误明细数
+const maxLoadErrors = 100
+
+// LoadOptions 文件导入选项
+type LoadOptions struct {
+	Columns    []string                // 列名；CSV/TSV 无表头时必填，NDJSON 为空时按表结构自动匹配
+	NoHeader   bool                    // CSV/TSV 首行不是表头
+	Delimiter  rune                    // CSV 分隔符，默认 ','
+	BatchSize  int                     // 每批行数，默认使用客户端的 BatchSize
+	MaxErrors  int                     // 允许跳过的坏行数，超过后中止导入；小于 0 表示不限制
+	OnProgress func(report LoadReport) // 每批写入后回调
+}
+
+// LoadError 导入中被跳过的行
+type LoadError struct {
+	Line int64  // 行号，从 1 开始
+	Err  string // 错误原因
+}
+
+// LoadReport 导入结果汇总
+type LoadReport struct {
+	Rows     int64         // 已写入行数
+	Skipped  int64         // 跳过的坏行数
+	Errors   []LoadError   // 坏行明细，最多保留 100 条
+	Duration time.Duration // 耗时
+}
+
+// errBadRow 可跳过的单行解析错误
+type errBadRow struct {
+	err error
+}
+
+func (e errBadRow) Error() string { return e.err.Error() }
+
+// rowReader 逐行读取文件，返回 io.EOF 表示结束
+type rowReader interface {
+	next() (row map[string]interface{}, line int64, err error)
+}
+
+// LoadFile 将文件流式分批写入表中，坏行按 MaxErrors 跳过，返回导入汇总
+func (c *ClickHouseClient) LoadFile(tableName string, r io.Reader, format Format, opts LoadOptions) (*LoadReport, error) {
+	return c.LoadFileCtx(context.Background(), tableName, r, format, opts)
+}
+
+// LoadFileCtx 将文件流式分批写入表中，坏行按 MaxErrors 跳过，返回导入汇总。
+// 出错时已写入的批次不会回滚，report 中记录了已写入的行数
+func (c *ClickHouseClient) LoadFileCtx(ctx context.Context, tableName string, r io.Reader, format Format, opts LoadOptions) (*LoadReport, error) {
+	start := time.Now()
+	report := &LoadReport{}
+	defer func() { report.Duration = time.Since(start) }()
+
+	batchSize := opts.BatchSize
+	if batchSize <= 0 {
+		batchSize = c.batchSize
+	}
+
+	var (
+		columns = opts.Columns
+		types   map[string]string
+		reader  rowReader
+	)
+	switch format {
+	case FormatCSV, FormatTSV:
+		schema, err := c.tableColumns(ctx, tableName, true)
+		if err != nil {
+			return report, fmt.Errorf("failed to load columns of %s: %w", tableName, err)
+		}
+		types = make(map[string]string, len(schema))
+		for _, col := range schema {
+			types[col.Name] = col.Type
+		}
+		tr := newTextReader(r, format, opts.Delimiter)
+		if !opts.NoHeader {
+			header, err := tr.header()
+			if err != nil {
+				return report, fmt.Errorf("failed to read header: %w", err)
+			}
+			if len(columns) == 0 {
+				columns = header
+			}
+		}
+		if len(columns) == 0 {
+			return report, errors.New("columns are required when file has no header")
+		}
+		for _, col := range columns {
+			if _, ok := types[col]; !ok {
+				return report, fmt.Errorf("column %s not found in %s", col, tableName)
+			}
+		}
+		tr.columns, tr.types = columns, types
+		reader = tr
+	case FormatNDJSON:
+		reader = &jsonReader{r: bufio.NewReader(r)}
+	default:
+		return report, fmt.Errorf("unsupported format: %s", format)
+	}
+
+	skip := func(line int64, err error) error {
+		report.Skipped++
+		if len(report.Errors) < maxLoadErrors {
+			report.Errors = append(report.Errors, LoadError{Line: line, Err: err.Error()})
+		}
+		if opts.MaxErrors >= 0 && report.Skipped > int64(opts.MaxErrors) {
+			return fmt.Errorf("too many bad rows (%d), last at line %d: %w", report.Skipped, line, err)
+		}
+		return nil
+	}
+
+	rows := make([]map[string]interface{}, 0, batchSize)
+	lines := make([]int64, 0, batchSize)
+	flush := func() error {
+		if len(rows) == 0 {
+			return nil
+		}
+		if types == nil {
+			var err error
+			if columns, types, err = c.mapColumns(ctx, tableName, rows, columns); err != nil {
+				return err
+			}
+		}
+		batch, err := c.prepareBatch(ctx, tableName, columns)
+		if err != nil {
+			return fmt.Errorf("failed to prepare batch: %w", err)
+		}
+		n, err := appendMaps(batch, rows, columns, types, func(i int, err error) error {
+			return skip(lines[i], err)
+		})
+		if err != nil {
+			batch.Abort()
+			return err
+		}
+		if n > 0 {
+			if err := batch.Send(); err != nil {
+				return fmt.Errorf("failed to send batch ending at line %d: %w", lines[len(lines)-1], err)
+			}
+		} else {
+			batch.Abort()
+		}
+		report.Rows += int64(n)
+		rows, lines = rows[:0], lines[:0]
+		if opts.OnProgress != nil {
+			report.Duration = time.Since(start)
+			opts.OnProgress(*report)
+		}
+		return nil
+	}
+
+	for {
+		if err := ctx.Err(); err != nil {
+			return report, err
+		}
+		row, line, err := reader.next()
+		if err == io.EOF {
+			break
+		}
+		var bad errBadRow
+		if errors.As(err, &bad) {
+			if err := skip(line, bad.err); err != nil {
+				return report, err
+			}
+			continue
+		}
+		if err != nil {
+			return report, fmt.Errorf("failed to read line %d: %w", line, err)
+		}
+		rows = append(rows, row)
+		lines = append(lines, line)
+		if len(rows) >= batchSize {
+			if err := flush(); err != nil {
+				return report, err
+			}
+		}
+	}
+	if err := flush(); err != nil {
+		return report, err
+	}
+	return report, nil
+}
+
+// textReader 读取 CSV/TSV，按列类型转换字段
+type textReader struct {
+	csv     *csv.Reader
+	tsv     *bufio.Reader
+	line    int64
+	columns []string
+	types   map[string]string
+}
+
+func newTextReader(r io.Reader, format Format, delimiter rune) *textReader {
+	if format == FormatTSV {
+		return &textReader{tsv: bufio.NewReader(r)}
+	}
+	cr := csv.NewReader(r)
+	if delimiter != 0 {
+		cr.Comma = delimiter
+	}
+	cr.FieldsPerRecord = -1
+	cr.ReuseRecord = true
+	return &textReader{csv: cr}
+}
+
+// header 读取表头
+func (t *textReader) header() ([]string, error) {
+	fields, err := t.fields()
+	if err != nil {
+		return nil, err
+	}
+	return append([]string(nil), fields...), nil
+}
+
+// fields 读取一行原始字段
+func (t *textReader) fields() ([]string, error) {
+	if t.csv != nil {
+		fields, err := t.csv.Read()
+		var perr *csv.ParseError
+		if errors.As(err, &perr) {
+			t.line = int64(perr.StartLine)
+			return nil, errBadRow{err: perr.Err}
+		}
+		if err != nil {
+			return nil, err
+		}
+		line, _ := t.csv.FieldPos(0)
+		t.line = int64(line)
+		return fields, nil
+	}
+
+	s, err := t.tsv.ReadString('\n')
+	if err != nil && (err != io.EOF || s == "") {
+		return nil, err
+	}
+	t.line++
+	s = strings.TrimSuffix(strings.TrimSuffix(s, "\n"), "\r")
+	fields := strings.Split(s, "\t")
+	for i, f := range fields {
+		fields[i] = unescapeTSV(f)
+	}
+	return fields, nil
+}
+
+func (t *textReader) next() (map[string]interface{}, int64, error) {
+	fields, err := t.fields()
+	if err != nil {
+		return nil, t.line, err
+	}
+	if len(fields) != len(t.columns) {
+		return nil, t.line, errBadRow{err: fmt.Errorf("expected %d fields, got %d", len(t.columns), len(fields))}
+	}
+	row := make(map[string]interface{}, len(fields))
+	for i, col := range t.columns {
+		v, err := coerceText(fields[i], t.types[col])
+		if err != nil {
+			return nil, t.line, errBadRow{err: fmt.Errorf("column %s: %w", col, err)}
+		}
+		row[col] = v
+	}
+	return row, t.line, nil
+}
+
+// unescapeTSV 还原 TabSeparated 转义，\N 保持原样由 coerceText 识别为 NULL
+func unescapeTSV(s string) string {
+	if s == `\N` || !strings.Contains(s, `\`) {
+		return s
+	}
+	var b strings.Builder
+	b.Grow(len(s))
+	for i := 0; i < len(s); i++ {
+		if s[i] != '\\' || i == len(s)-1 {
+			b.WriteByte(s[i])
+			continue
+		}
+		i++
+		switch s[i] {
+		case 't':
+			b.WriteByte('\t')
+		case 'n':
+			b.WriteByte('\n')
+		case 'r':
+			b.WriteByte('\r')
+		case '0':
+			b.WriteByte(0)
+		case 'b':
+			b.WriteByte('\b')
+		case 'f':
+			b.WriteByte('\f')
+		default:
+			b.WriteByte(s[i])
+		}
+	}
+	return b.String()
+}
+
+// jsonReader 读取 NDJSON，数字保留为 json.Number 由 coerceValue 按列类型转换
+type jsonReader struct {
+	r    *bufio.Reader
+	line int64
+}
+
+func (j *jsonReader) next() (map[string]interface{}, int64, error) {
+	for {
+		b, err := j.r.ReadBytes('\n')
+		if err != nil && (err != io.EOF || len(b) == 0) {
+			return nil, j.line, err
+		}
+		j.line++
+		b = bytes.TrimSpace(b)
+		if len(b) == 0 {
+			if err == io.EOF {
+				return nil, j.line, io.EOF
+			}
+			continue
+		}
+		dec := json.NewDecoder(bytes.NewReader(b))
+		dec.UseNumber()
+		row := map[string]interface{}{}
+		if err := dec.Decode(&row); err != nil {
+			return nil, j.line, errBadRow{err: err}
+		}
+		return row, j.line, nil
+	}
+}
@@ -0,0 +1,340 @@
+package ckgroup
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/big"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ClickHouse/clickhouse-go/v2/lib/driver"
+)
+
+// QueryToMaps 查询并转换为 map 切片，键为列名。值按列类型转换：
+// Nullable 为 NULL 时为 nil，否则为基础类型的值；DateTime/Date 为 time.Time；
+// Decimal 为 float64；Int128/Int256 等大整数为十进制字符串；UUID、IPv4/IPv6 为字符串；Array 为 []interface{}；Map(String, T) 为 map[string]interface{}
+func (c *ClickHouseClient) QueryToMaps(query string, args ...interface{}) ([]map[string]interface{}, error) {
+	return c.QueryToMapsCtx(context.Background(), query, args...)
+}
+
+// QueryToMapsCtx 查询并转换为 map 切片，见 QueryToMaps
+func (c *ClickHouseClient) QueryToMapsCtx(ctx context.Context, query string, args ...interface{}) ([]map[string]interface{}, error) {
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+	rows, err := c.query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	r, err := c.newRows(rows)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]map[string]interface{}, 0)
+	for r.Next() {
+		row, err := r.ScanMap()
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, row)
+	}
+	return result, r.Err()
+}
+
+// SelectToMaps 执行构造的查询并转换为 map 切片，见 QueryToMaps
+func (c *ClickHouseClient) SelectToMaps(ctx context.Context, q *Query) ([]map[string]interface{}, error) {
+	sql, args := q.Build()
+	return c.QueryToMapsCtx(ctx, sql, args...)
+}
+
+// BatchInsertMaps 批量插入 map 形式的行，如 utils.BJsonToListMap 的结果。
+// columns 为空时从表结构读取可写入的列，只写入至少一行中出现的列，其余列使用默认值；行中缺少的列写入 nil。
+// 数值按表结构转换为对应的整数、浮点类型，JSON 解析出的 float64 和 json.Number 可直接写入整数列
+func (c *ClickHouseClient) BatchInsertMaps(tableName string, rows []map[string]interface{}, columns ...string) error {
+	return c.BatchInsertMapsCtx(context.Background(), tableName, rows, columns...)
+}
+
+// BatchInsertMapsCtx 批量插入 map 形式的行，见 BatchInsertMaps
+func (c *ClickHouseClient) BatchInsertMapsCtx(ctx context.Context, tableName string, rows []map[string]interface{}, columns ...string) error {
+	if len(rows) == 0 {
+		return nil
+	}
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+
+	columns, types, err := c.mapColumns(ctx, tableName, rows, columns)
+	if err != nil {
+		return err
+	}
+
+	for i := 0; i < len(rows); i += c.batchSize {
+		end := min(i+c.batchSize, len(rows))
+		batch, err := c.prepareBatch(ctx, tableName, columns)
+		if err != nil {
+			return fmt.Errorf("failed to prepare batch: %w", err)
+		}
+		_, err = appendMaps(batch, rows[i:end], columns, types, func(j int, err error) error {
+			return fmt.Errorf("row %d: %w", i+j, err)
+		})
+		if err != nil {
+			batch.Abort()
+			return err
+		}
+		if err := batch.Send(); err != nil {
+			return fmt.Errorf("failed to send batch %d-%d: %w", i, end-1, err)
+		}
+	}
+	return nil
+}
+
+// mapColumns 读取表结构，columns 为空时取表中至少在一行出现的可写入列，返回列名和列类型
+func (c *ClickHouseClient) mapColumns(ctx context.Context, tableName string, rows []map[string]interface{}, columns []string) ([]string, map[string]string, error) {
+	schema, err := c.tableColumns(ctx, tableName, true)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to load columns of %s: %w", tableName, err)
+	}
+	types := make(map[string]string, len(schema))
+	for _, col := range schema {
+		types[col.Name] = col.Type
+	}
+	if len(columns) == 0 {
+		for _, col := range schema {
+			for _, row := range rows {
+				if _, ok := row[col.Name]; ok {
+					columns = append(columns, col.Name)
+					break
+				}
+			}
+		}
+		if len(columns) == 0 {
+			return nil, nil, fmt.Errorf("no columns of %s found in rows", tableName)
+		}
+	}
+	return columns, types, nil
+}
+
+// appendMaps 将行按列转换后追加到批次，返回追加的行数。
+// 单行转换失败时调用 onBad，onBad 返回 nil 时跳过该行继续，否则停止并返回该错误
+func appendMaps(batch driver.Batch, rows []map[string]interface{}, columns []string, types map[string]string, onBad func(i int, err error) error) (int, error) {
+	appended := 0
+	for i, row := range rows {
+		values := make([]interface{}, len(columns))
+		var err error
+		for k, col := range columns {
+			if values[k], err = coerceValue(row[col], types[col]); err != nil {
+				err = fmt.Errorf("column %s: %w", col, err)
+				break
+			}
+		}
+		if err != nil {
+			if err := onBad(i, err); err != nil {
+				return appended, err
+			}
+			continue
+		}
+		if err := batch.Append(values...); err != nil {
+			return appended, fmt.Errorf("failed to append data to batch: %w", err)
+		}
+		appended++
+	}
+	return appended, nil
+}
+
+// splitTableName 拆分 db.table，未指定数据库时 database 为空
+func splitTableName(name string) (database, table string) {
+	if i := strings.IndexByte(name, '.'); i >= 0 {
+		return name[:i], name[i+1:]
+	}
+	return "", name
+}
+
+// baseType 去掉 Nullable、LowCardinality 包装
+func baseType(chType string) string {
+	for _, wrapper := range []string{"Nullable(", "LowCardinality("} {
+		for strings.HasPrefix(chType, wrapper) && strings.HasSuffix(chType, ")") {
+			chType = chType[len(wrapper) : len(chType)-1]
+		}
+	}
+	return chType
+}
+
+// coerceValue 将 JSON 等来源的通用数值转换为列类型对应的 Go 类型，其他值原样返回
+func coerceValue(v interface{}, chType string) (interface{}, error) {
+	if v == nil {
+		return nil, nil
+	}
+	var f float64
+	switch n := v.(type) {
+	case float64:
+		f = n
+	case float32:
+		f = float64(n)
+	case int:
+		f = float64(n)
+	case int64:
+		f = float64(n)
+	case json.Number:
+		var err error
+		if f, err = n.Float64(); err != nil {
+			return nil, err
+		}
+		// 大整数按字符串解析以免精度损失
+		if i, err := strconv.ParseInt(n.String(), 10, 64); err == nil {
+			return convertInt(i, baseType(chType), v), nil
+		}
+	default:
+		return v, nil
+	}
+
+	t := baseType(chType)
+	switch t {
+	case "Float32":
+		return float32(f), nil
+	case "Float64":
+		return f, nil
+	}
+	if strings.HasPrefix(t, "Int") || strings.HasPrefix(t, "UInt") || t == "Bool" {
+		if f != math.Trunc(f) {
+			return nil, fmt.Errorf("%v is not an integer", v)
+		}
+		return convertInt(int64(f), t, v), nil
+	}
+	return v, nil
+}
+
+// coerceText 将 CSV/TSV 等文本格式的字段转换为列类型对应的值，\N 表示 NULL，
+// Nullable 的非字符串列空字段也视为 NULL
+func coerceText(s, chType string) (interface{}, error) {
+	if s == `\N` {
+		return nil, nil
+	}
+	t := baseType(chType)
+	if s == "" && strings.HasPrefix(chType, "Nullable(") && t != "String" {
+		return nil, nil
+	}
+	switch {
+	case t == "Bool":
+		return strconv.ParseBool(s)
+	case t == "Float32" || t == "Float64":
+		f, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return nil, err
+		}
+		return coerceValue(f, t)
+	case strings.HasPrefix(t, "UInt"):
+		n, err := strconv.ParseUint(s, 10, 64)
+		if err != nil {
+			return nil, err
+		}
+		return convertUint(n, t), nil
+	case strings.HasPrefix(t, "Int"):
+		n, err := strconv.ParseInt(s, 10, 64)
+		if err != nil {
+			return nil, err
+		}
+		return convertInt(n, t, s), nil
+	}
+	return s, nil
+}
+
+// convertUint 转换为无符号整数列对应的 Go 类型，UInt64 超出 int64 范围时也不丢失精度
+func convertUint(n uint64, t string) interface{} {
+	if t == "UInt64" {
+		return n
+	}
+	return convertInt(int64(n), t, n)
+}
+
+// convertInt 转换为整数列对应的 Go 类型，不是整数列时返回 orig
+func convertInt(i int64, t string, orig interface{}) interface{} {
+	switch t {
+	case "Int8":
+		return int8(i)
+	case "Int16":
+		return int16(i)
+	case "Int32":
+		return int32(i)
+	case "Int64":
+		return i
+	case "UInt8":
+		return uint8(i)
+	case "Bool":
+		return i != 0
+	case "UInt16":
+		return uint16(i)
+	case "UInt32":
+		return uint32(i)
+	case "UInt64":
+		return uint64(i)
+	case "Float32":
+		return float32(i)
+	case "Float64":
+		return float64(i)
+	}
+	return orig
+}
+
+var (
+	timeType   = reflect.TypeOf(time.Time{})
+	bigIntType = reflect.TypeOf(big.Int{})
+)
+
+// normalizeValue 将驱动扫描出的值转换为通用类型
+func normalizeValue(v reflect.Value) interface{} {
+	if !v.IsValid() {
+		return nil
+	}
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if v.IsNil() {
+			return nil
+		}
+		return normalizeValue(v.Elem())
+	case reflect.Struct:
+		switch v.Type() {
+		case timeType:
+			return v.Interface()
+		case bigIntType:
+			n := v.Interface().(big.Int)
+			return n.String()
+		}
+		// shopspring/decimal 等 Decimal 类型
+		if d, ok := v.Interface().(interface{ Float64() (float64, bool) }); ok {
+			f, _ := d.Float64()
+			return f
+		}
+		return v.Interface()
+	case reflect.Slice, reflect.Array:
+		// UUID、IPv4/IPv6 等字节数组类型
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			if s, ok := v.Interface().(fmt.Stringer); ok {
+				return s.String()
+			}
+		}
+		if v.Kind() == reflect.Slice && v.IsNil() {
+			return []interface{}{}
+		}
+		list := make([]interface{}, v.Len())
+		for i := range list {
+			list[i] = normalizeValue(v.Index(i))
+		}
+		return list
+	case reflect.Map:
+		if v.Type().Key().Kind() != reflect.String {
+			return v.Interface()
+		}
+		m := make(map[string]interface{}, v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			m[iter.Key().String()] = normalizeValue(iter.Value())
+		}
+		return m
+	default:
+		return v.Interface()
+	}
+}
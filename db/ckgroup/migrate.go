@@ -0,0 +1,198 @@
+package ckgroup
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// TableSpec Migrate 的目标表定义
+type TableSpec struct {
+	Database string // 数据库名
+	Table    string // 表名
+	Order    string // 表不存在时创建使用的排序键，见 CreateTable
+	Comment  string // 表不存在时创建使用的表注释
+	DryRun   bool   // 只返回将要执行的语句，不执行
+}
+
+// Migrate 根据结构体字段同步表结构，返回执行的语句。表不存在时按 CreateTable 创建（结构体需包含 created_at 列）；
+// 表已存在时为缺少的列执行 ADD COLUMN，类型不一致的列执行 MODIFY COLUMN，注释不一致的列执行 COMMENT COLUMN。
+// 不删除表中多余的列。列名规则与 BatchInsert 相同，类型按 Go 类型推断，可用 ch 标签指定，如 `ch:"LowCardinality(String)"`，
+// 列注释使用 comment 标签。类型为推断时忽略 LowCardinality 包装的差异
+func (c *ClickHouseClient) Migrate(sample interface{}, spec TableSpec) ([]string, error) {
+	return c.MigrateCtx(context.Background(), sample, spec)
+}
+
+// MigrateCtx 根据结构体字段同步表结构，见 Migrate
+func (c *ClickHouseClient) MigrateCtx(ctx context.Context, sample interface{}, spec TableSpec) ([]string, error) {
+	fields, err := c.structColumns(sample)
+	if err != nil {
+		return nil, err
+	}
+	tableName := spec.Database + "." + spec.Table
+
+	existing, err := c.ColumnsCtx(ctx, tableName)
+	if errors.Is(err, ErrTableNotFound) {
+		cols := make([]Column, len(fields))
+		for i, f := range fields {
+			cols[i] = f.Column
+		}
+		if spec.DryRun {
+			return []string{"CREATE TABLE " + tableName}, nil
+		}
+		if err := c.CreateTable(spec.Database, spec.Table, spec.Order, spec.Comment, cols); err != nil {
+			return nil, fmt.Errorf("failed to create table %s: %w", tableName, err)
+		}
+		return []string{"CREATE TABLE " + tableName}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	current := make(map[string]Column, len(existing))
+	for _, col := range existing {
+		current[col.Name] = col
+	}
+	prefix := fmt.Sprintf("ALTER TABLE %s%s ", tableName, c.onCluster())
+	var stmts []string
+	for _, f := range fields {
+		col, ok := current[f.Name]
+		switch {
+		case !ok:
+			stmt := prefix + fmt.Sprintf("ADD COLUMN IF NOT EXISTS %s %s", f.Name, f.Type)
+			if f.Comment != "" {
+				stmt += " COMMENT " + quoteString(f.Comment)
+			}
+			stmts = append(stmts, stmt)
+			continue
+		case f.explicit && col.Type != f.Type,
+			!f.explicit && stripLowCardinality(col.Type) != stripLowCardinality(f.Type):
+			stmts = append(stmts, prefix+fmt.Sprintf("MODIFY COLUMN %s %s", f.Name, f.Type))
+		}
+		if f.Comment != "" && col.Comment != f.Comment {
+			stmts = append(stmts, prefix+fmt.Sprintf("COMMENT COLUMN %s %s", f.Name, quoteString(f.Comment)))
+		}
+	}
+
+	if spec.DryRun {
+		return stmts, nil
+	}
+	for i, stmt := range stmts {
+		if err := c.ExecCtx(ctx, stmt); err != nil {
+			return stmts[:i], fmt.Errorf("failed to migrate %s: %s: %w", tableName, stmt, err)
+		}
+	}
+	return stmts, nil
+}
+
+// ColumnsCtx 按定义顺序读取表的列，表不存在时返回 ErrTableNotFound
+func (c *ClickHouseClient) ColumnsCtx(ctx context.Context, tableName string) ([]Column, error) {
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+	return c.tableColumns(ctx, tableName, false)
+}
+
+// structColumn 结构体字段对应的列
+type structColumn struct {
+	Column
+	explicit bool // 类型由 ch 标签指定
+}
+
+// structColumns 解析结构体字段对应的列定义
+func (c *ClickHouseClient) structColumns(sample interface{}) ([]structColumn, error) {
+	t := reflect.TypeOf(sample)
+	if t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("sample must be struct or pointer to struct")
+	}
+
+	var cols []structColumn
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		name := c.getColumnName(field)
+		if name == "-" {
+			continue
+		}
+		col := structColumn{Column: Column{Name: name, Comment: field.Tag.Get("comment")}}
+		if tag := field.Tag.Get("ch"); tag != "" {
+			col.Type, col.explicit = tag, true
+		} else {
+			typ, err := clickHouseType(field.Type)
+			if err != nil {
+				return nil, fmt.Errorf("field %s: %w", field.Name, err)
+			}
+			col.Type = typ
+		}
+		cols = append(cols, col)
+	}
+	return cols, nil
+}
+
+// clickHouseType 推断 Go 类型对应的 ClickHouse 类型
+func clickHouseType(t reflect.Type) (string, error) {
+	if t == timeType {
+		return "DateTime64(3)", nil
+	}
+	switch t.Kind() {
+	case reflect.Ptr:
+		inner, err := clickHouseType(t.Elem())
+		if err != nil {
+			return "", err
+		}
+		return "Nullable(" + inner + ")", nil
+	case reflect.String:
+		return "String", nil
+	case reflect.Bool:
+		return "Bool", nil
+	case reflect.Int:
+		return "Int64", nil
+	case reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return fmt.Sprintf("Int%d", t.Bits()), nil
+	case reflect.Uint:
+		return "UInt64", nil
+	case reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return fmt.Sprintf("UInt%d", t.Bits()), nil
+	case reflect.Float32, reflect.Float64:
+		return fmt.Sprintf("Float%d", t.Bits()), nil
+	case reflect.Slice:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return "String", nil
+		}
+		inner, err := clickHouseType(t.Elem())
+		if err != nil {
+			return "", err
+		}
+		return "Array(" + inner + ")", nil
+	case reflect.Map:
+		key, err := clickHouseType(t.Key())
+		if err != nil {
+			return "", err
+		}
+		value, err := clickHouseType(t.Elem())
+		if err != nil {
+			return "", err
+		}
+		return "Map(" + key + ", " + value + ")", nil
+	}
+	return "", fmt.Errorf("unsupported type %s, use ch tag to specify the column type", t)
+}
+
+// stripLowCardinality 去掉 LowCardinality 包装
+func stripLowCardinality(chType string) string {
+	if strings.HasPrefix(chType, "LowCardinality(") && strings.HasSuffix(chType, ")") {
+		return chType[len("LowCardinality(") : len(chType)-1]
+	}
+	return chType
+}
+
+// quoteString 生成 ClickHouse 字符串字面量
+func quoteString(s string) string {
+	return "'" + strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(s) + "'"
+}
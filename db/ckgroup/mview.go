@@ -0,0 +1,172 @@
+package ckgroup
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// 物化视图目标表引擎
+const (
+	SummingEngine     = "SummingMergeTree"
+	AggregatingEngine = "AggregatingMergeTree"
+)
+
+// MVColumn 物化视图的列，Expr 为基于源表的 SELECT 表达式，为空时与列名相同
+type MVColumn struct {
+	Name    string
+	Type    string
+	Expr    string
+	Comment string
+}
+
+// MVSpec 物化视图定义。目标表按 Dimensions 排序，SummingMergeTree 合并时对 Metrics 求和，
+// AggregatingMergeTree 的 Metrics 应为 AggregateFunction 类型，见 AggState
+type MVSpec struct {
+	Database    string     // 视图和目标表所在数据库
+	Name        string     // 视图名
+	Target      string     // 目标表名，默认为 Name + "_data"
+	Source      string     // 源表，如 db.events
+	Engine      string     // SummingEngine（默认）或 AggregatingEngine
+	Dimensions  []MVColumn // 聚合维度，即 GROUP BY 和 ORDER BY 的列
+	Metrics     []MVColumn // 聚合指标
+	Where       string     // 源表过滤条件
+	PartitionBy string     // 目标表分区表达式
+	TTL         string     // 目标表 TTL 表达式
+	Comment     string     // 目标表注释
+	Backfill    bool       // 创建后将源表已有数据写入目标表，回填期间写入源表的数据会被重复计入
+	DryRun      bool       // 只返回将要执行的语句，不执行
+}
+
+// Sum 定义 SummingMergeTree 的求和指标，如 Sum("amount", "sum(price)", "Float64")
+func Sum(name, expr, chType string) MVColumn {
+	return MVColumn{Name: name, Type: chType, Expr: expr}
+}
+
+// AggState 定义 AggregatingMergeTree 的聚合状态指标，如 AggState("uv", "uniq", "user_id", "UInt64")
+// 生成类型 AggregateFunction(uniq, UInt64) 和表达式 uniqState(user_id)，查询时使用 uniqMerge(uv)
+func AggState(name, fn, arg, argType string) MVColumn {
+	return MVColumn{
+		Name: name,
+		Type: fmt.Sprintf("AggregateFunction(%s, %s)", fn, argType),
+		Expr: fmt.Sprintf("%sState(%s)", fn, arg),
+	}
+}
+
+// CreateMaterializedView 创建目标表和写入目标表的物化视图（TO 形式），返回执行的语句；
+// 非 SingleNode 部署时在集群所有节点上创建，Config.Engine 为 Replicated* 时目标表使用对应的 Replicated 引擎
+func (c *ClickHouseClient) CreateMaterializedView(spec MVSpec) ([]string, error) {
+	return c.CreateMaterializedViewCtx(context.Background(), spec)
+}
+
+// CreateMaterializedViewCtx 创建目标表和物化视图，见 CreateMaterializedView
+func (c *ClickHouseClient) CreateMaterializedViewCtx(ctx context.Context, spec MVSpec) ([]string, error) {
+	stmts, err := c.materializedViewDDL(spec)
+	if err != nil {
+		return nil, err
+	}
+	if spec.DryRun {
+		return stmts, nil
+	}
+	for i, stmt := range stmts {
+		if err := c.ExecCtx(ctx, stmt); err != nil {
+			return stmts[:i], fmt.Errorf("failed to create materialized view %s.%s: %w", spec.Database, spec.Name, err)
+		}
+	}
+	return stmts, nil
+}
+
+// materializedViewDDL 生成建库、目标表、物化视图以及回填语句
+func (c *ClickHouseClient) materializedViewDDL(spec MVSpec) ([]string, error) {
+	if spec.Database == "" || spec.Name == "" || spec.Source == "" {
+		return nil, fmt.Errorf("materialized view requires database, name and source")
+	}
+	if len(spec.Dimensions) == 0 || len(spec.Metrics) == 0 {
+		return nil, fmt.Errorf("materialized view %s requires dimensions and metrics", spec.Name)
+	}
+	if spec.Target == "" {
+		spec.Target = spec.Name + "_data"
+	}
+	if spec.Engine == "" {
+		spec.Engine = SummingEngine
+	}
+	if spec.Engine != SummingEngine && spec.Engine != AggregatingEngine {
+		return nil, fmt.Errorf("unsupported materialized view engine: %s", spec.Engine)
+	}
+
+	cols := make([]Column, 0, len(spec.Dimensions)+len(spec.Metrics))
+	dims := make([]string, len(spec.Dimensions))
+	metrics := make([]string, len(spec.Metrics))
+	selects := make([]string, 0, cap(cols))
+	for i, list := range [][]MVColumn{spec.Dimensions, spec.Metrics} {
+		for j, col := range list {
+			if col.Name == "" || col.Type == "" {
+				return nil, fmt.Errorf("materialized view %s: column name and type are required", spec.Name)
+			}
+			cols = append(cols, Column{Name: col.Name, Type: col.Type, Comment: col.Comment})
+			if expr := col.Expr; expr == "" || expr == col.Name {
+				selects = append(selects, col.Name)
+			} else {
+				selects = append(selects, col.Expr+" AS "+col.Name)
+			}
+			if i == 0 {
+				dims[j] = col.Name
+			} else {
+				metrics[j] = col.Name
+			}
+		}
+	}
+	target := spec.Database + "." + spec.Target
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("CREATE TABLE IF NOT EXISTS %s%s (\n", target, c.onCluster()))
+	writeColumns(&sb, cols)
+	sb.WriteString(fmt.Sprintf("\n)\nENGINE = %s\n", c.mvEngineClause(spec, metrics)))
+	if spec.PartitionBy != "" {
+		sb.WriteString(fmt.Sprintf("PARTITION BY %s\n", spec.PartitionBy))
+	}
+	sb.WriteString(fmt.Sprintf("ORDER BY (%s)", strings.Join(dims, ", ")))
+	if spec.TTL != "" {
+		sb.WriteString(fmt.Sprintf("\nTTL %s", spec.TTL))
+	}
+	if spec.Comment != "" {
+		sb.WriteString("\nCOMMENT " + quoteString(spec.Comment))
+	}
+	createTable := sb.String()
+
+	var sel strings.Builder
+	sel.WriteString(fmt.Sprintf("SELECT\n  %s\nFROM %s", strings.Join(selects, ",\n  "), spec.Source))
+	if spec.Where != "" {
+		sel.WriteString(fmt.Sprintf("\nWHERE %s", spec.Where))
+	}
+	sel.WriteString(fmt.Sprintf("\nGROUP BY %s", strings.Join(dims, ", ")))
+
+	stmts := []string{
+		fmt.Sprintf("CREATE DATABASE IF NOT EXISTS %s%s", spec.Database, c.onCluster()),
+		createTable,
+		fmt.Sprintf("CREATE MATERIALIZED VIEW IF NOT EXISTS %s.%s%s TO %s AS\n%s",
+			spec.Database, spec.Name, c.onCluster(), target, sel.String()),
+	}
+	if spec.Backfill {
+		names := append(append([]string(nil), dims...), metrics...)
+		stmts = append(stmts, fmt.Sprintf("INSERT INTO %s (%s)\n%s",
+			target, strings.Join(names, ", "), sel.String()))
+	}
+	return stmts, nil
+}
+
+// mvEngineClause 生成目标表引擎子句，SummingMergeTree 显式指定求和列，
+// Config.Engine 为 Replicated* 时使用对应的 Replicated 引擎
+func (c *ClickHouseClient) mvEngineClause(spec MVSpec, metrics []string) string {
+	engine := spec.Engine
+	var args []string
+	if strings.HasPrefix(c.engine, "Replicated") {
+		engine = "Replicated" + engine
+		path := strings.NewReplacer("{database}", spec.Database, "{table}", spec.Target).Replace(c.zkPath)
+		args = append(args, quoteString(path), quoteString(c.replica))
+	}
+	if spec.Engine == SummingEngine {
+		args = append(args, "("+strings.Join(metrics, ", ")+")")
+	}
+	return fmt.Sprintf("%s(%s)", engine, strings.Join(args, ", "))
+}
@@ -0,0 +1,80 @@
+package ckgroup
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+)
+
+// Params 命名参数，作为 Query/Exec 等方法的唯一参数传入时，语句中的 {name:Type} 占位符按名称绑定：
+//   - 类型为 Identifier 时值作为标识符（表名、列名）加反引号转义后拼接，如 {table:Identifier}
+//   - 值为切片（[]byte 除外）时展开为逗号分隔的多个参数，用于 IN 子句，如 id IN ({ids:Array(UInt64)})，空切片展开为 NULL
+//   - 其他值作为普通参数由驱动转义
+//
+// 如 c.QueryToMaps("SELECT * FROM {t:Identifier} WHERE host = {host:String} AND id IN ({ids:Array(UInt64)})",
+// ckgroup.Params{"t": "logs", "host": host, "ids": ids})
+type Params map[string]interface{}
+
+// namedPattern 匹配 {name:Type} 占位符，不含冒号的 {shard} 等宏不匹配
+var namedPattern = regexp.MustCompile(`\{(\w+):([^{}]+)\}`)
+
+// bindNamed args 为单个 Params 时将命名占位符改写为位置参数，否则原样返回
+func bindNamed(query string, args []interface{}) (string, []interface{}, error) {
+	if len(args) != 1 {
+		return query, args, nil
+	}
+	params, ok := args[0].(Params)
+	if !ok {
+		return query, args, nil
+	}
+
+	var bound []interface{}
+	var bindErr error
+	query = namedPattern.ReplaceAllStringFunc(query, func(m string) string {
+		sub := namedPattern.FindStringSubmatch(m)
+		name, typ := sub[1], strings.TrimSpace(sub[2])
+		v, ok := params[name]
+		if !ok {
+			if bindErr == nil {
+				bindErr = fmt.Errorf("missing parameter %s", name)
+			}
+			return m
+		}
+		if typ == "Identifier" {
+			s, ok := v.(string)
+			if !ok {
+				if bindErr == nil {
+					bindErr = fmt.Errorf("identifier parameter %s must be a string", name)
+				}
+				return m
+			}
+			return QuoteIdentifier(s)
+		}
+		rv := reflect.ValueOf(v)
+		if rv.Kind() == reflect.Slice && rv.Type().Elem().Kind() != reflect.Uint8 {
+			if rv.Len() == 0 {
+				return "NULL"
+			}
+			for i := 0; i < rv.Len(); i++ {
+				bound = append(bound, rv.Index(i).Interface())
+			}
+			return placeholders(rv.Len())
+		}
+		bound = append(bound, v)
+		return "?"
+	})
+	if bindErr != nil {
+		return "", nil, bindErr
+	}
+	return query, bound, nil
+}
+
+// QuoteIdentifier 使用反引号转义标识符，db.table 形式按点号分别转义
+func QuoteIdentifier(name string) string {
+	parts := strings.Split(name, ".")
+	for i, p := range parts {
+		parts[i] = "`" + strings.NewReplacer(`\`, `\\`, "`", "\\`").Replace(p) + "`"
+	}
+	return strings.Join(parts, ".")
+}
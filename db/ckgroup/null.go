@@ -0,0 +1,101 @@
+package ckgroup
+
+import (
+	"database/sql"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var scannerType = reflect.TypeOf((*sql.Scanner)(nil)).Elem()
+
+// nullHolder Nullable 列扫描到非指针字段时的中间指针
+type nullHolder struct {
+	field reflect.Value
+	ptr   reflect.Value // **T
+	sf    reflect.StructField
+}
+
+// assign 将扫描结果写入字段，NULL 时使用 default 选项或零值
+func (h nullHolder) assign() error {
+	if p := h.ptr.Elem(); !p.IsNil() {
+		h.field.Set(p.Elem())
+		return nil
+	}
+	def, ok := tagDefault(h.sf)
+	if !ok {
+		h.field.Set(reflect.Zero(h.field.Type()))
+		return nil
+	}
+	v, err := parseDefault(def, h.field.Type())
+	if err != nil {
+		return fmt.Errorf("field %s: %w", h.sf.Name, err)
+	}
+	h.field.Set(v)
+	return nil
+}
+
+// acceptsNull 字段能否直接接收 NULL：指针、接口、切片、Map 和实现 sql.Scanner 的类型（如 sql.NullInt64）
+func acceptsNull(field reflect.Value) bool {
+	switch field.Kind() {
+	case reflect.Ptr, reflect.Interface, reflect.Slice, reflect.Map:
+		return true
+	}
+	return field.Addr().Type().Implements(scannerType)
+}
+
+// tagDefault 读取 db 标签的 default 选项，如 `db:"age,default=0"`
+func tagDefault(field reflect.StructField) (string, bool) {
+	opts := strings.Split(field.Tag.Get("db"), ",")
+	for _, opt := range opts[1:] {
+		if v, ok := strings.CutPrefix(strings.TrimSpace(opt), "default="); ok {
+			return v, true
+		}
+	}
+	return "", false
+}
+
+// parseDefault 将 default 选项解析为 t 类型的值，时间支持 RFC3339 和 2006-01-02 15:04:05
+func parseDefault(s string, t reflect.Type) (reflect.Value, error) {
+	v := reflect.New(t).Elem()
+	if t == timeType {
+		tm, err := time.Parse(time.RFC3339, s)
+		if err != nil {
+			tm, err = time.ParseInLocation(time.DateTime, s, time.Local)
+		}
+		if err != nil {
+			return v, fmt.Errorf("invalid default %q: %w", s, err)
+		}
+		v.Set(reflect.ValueOf(tm))
+		return v, nil
+	}
+	var err error
+	switch t.Kind() {
+	case reflect.String:
+		v.SetString(s)
+	case reflect.Bool:
+		var b bool
+		b, err = strconv.ParseBool(s)
+		v.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		var n int64
+		n, err = strconv.ParseInt(s, 10, t.Bits())
+		v.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		var n uint64
+		n, err = strconv.ParseUint(s, 10, t.Bits())
+		v.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		var f float64
+		f, err = strconv.ParseFloat(s, t.Bits())
+		v.SetFloat(f)
+	default:
+		return v, fmt.Errorf("default not supported for type %s", t)
+	}
+	if err != nil {
+		return v, fmt.Errorf("invalid default %q: %w", s, err)
+	}
+	return v, nil
+}
@@ -0,0 +1,218 @@
+package ckgroup
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Query SELECT 语句构造器，条件中的值通过 ? 占位符作为参数传递，由驱动负责转义。
+// 列名、表名和排序表达式原样拼接，不能来自用户输入
+type Query struct {
+	columns    []string
+	from       string
+	where      []string
+	whereArgs  []interface{}
+	groupBy    []string
+	having     []string
+	havingArgs []interface{}
+	orderBy    []string
+	limit      int
+	offset     int
+	final      bool
+	version    string
+	keys       []string
+}
+
+// NewQuery 创建查询构造器，如
+//
+//	ckgroup.NewQuery().Select("host", "count() AS n").From("logs").
+//		Where("created_at >= ?", since).WhereIn("level", levels).
+//		GroupBy("host").OrderBy("n DESC").Limit(10)
+func NewQuery() *Query {
+	return &Query{}
+}
+
+// Select 追加查询列，未设置时为 *
+func (q *Query) Select(columns ...string) *Query {
+	q.columns = append(q.columns, columns...)
+	return q
+}
+
+// From 设置表名，如 db.table
+func (q *Query) From(table string) *Query {
+	q.from = table
+	return q
+}
+
+// Where 追加条件，多个条件以 AND 连接；cond 中的 ? 按顺序对应 args
+func (q *Query) Where(cond string, args ...interface{}) *Query {
+	q.where = append(q.where, cond)
+	q.whereArgs = append(q.whereArgs, args...)
+	return q
+}
+
+// WhereIn 追加 column IN (...) 条件，values 为空切片时条件恒为假
+func (q *Query) WhereIn(column string, values ...interface{}) *Query {
+	if len(values) == 0 {
+		return q.Where("0")
+	}
+	return q.Where(column+" IN ("+placeholders(len(values))+")", values...)
+}
+
+// GroupBy 追加分组列
+func (q *Query) GroupBy(columns ...string) *Query {
+	q.groupBy = append(q.groupBy, columns...)
+	return q
+}
+
+// Having 追加分组后的过滤条件，多个条件以 AND 连接
+func (q *Query) Having(cond string, args ...interface{}) *Query {
+	q.having = append(q.having, cond)
+	q.havingArgs = append(q.havingArgs, args...)
+	return q
+}
+
+// OrderBy 追加排序表达式，如 "created_at DESC"
+func (q *Query) OrderBy(exprs ...string) *Query {
+	q.orderBy = append(q.orderBy, exprs...)
+	return q
+}
+
+// Limit 设置返回行数，0表示不限制
+func (q *Query) Limit(n int) *Query {
+	q.limit = n
+	return q
+}
+
+// Offset 设置跳过的行数，需同时设置 Limit
+func (q *Query) Offset(n int) *Query {
+	q.offset = n
+	return q
+}
+
+// Final 在表名后追加 FINAL，查询时合并 ReplacingMergeTree 等引擎中未合并的重复行
+func (q *Query) Final() *Query {
+	q.final = true
+	return q
+}
+
+// LatestBy 按 keys 对 ReplacingMergeTree 表去重，每个 key 只保留 version 最大的行，
+// Where 等条件作用于去重后的结果。Select 中的列名通过 argMax 取最新值，此时 Select 只能使用列名；
+// 未设置 Select 时按 (keys, version) IN (SELECT keys, max(version) ...) 过滤，version 相同的行会同时返回
+func (q *Query) LatestBy(version string, keys ...string) *Query {
+	q.version = version
+	q.keys = keys
+	return q
+}
+
+// Build 生成 SQL 和按占位符顺序排列的参数
+func (q *Query) Build() (string, []interface{}) {
+	var sb strings.Builder
+	sb.WriteString("SELECT ")
+	if len(q.columns) == 0 {
+		sb.WriteString("*")
+	} else {
+		sb.WriteString(strings.Join(q.columns, ", "))
+	}
+	sb.WriteString(" FROM ")
+	where := q.where
+	switch {
+	case q.version != "" && len(q.columns) > 0:
+		sb.WriteString("(" + q.latestQuery() + ")")
+	case q.version != "":
+		sb.WriteString(q.from)
+		where = append([]string{q.latestCond()}, where...)
+	default:
+		sb.WriteString(q.from)
+		if q.final {
+			sb.WriteString(" FINAL")
+		}
+	}
+	if len(where) > 0 {
+		sb.WriteString(" WHERE ")
+		sb.WriteString(joinConds(where))
+	}
+	if len(q.groupBy) > 0 {
+		sb.WriteString(" GROUP BY ")
+		sb.WriteString(strings.Join(q.groupBy, ", "))
+	}
+	if len(q.having) > 0 {
+		sb.WriteString(" HAVING ")
+		sb.WriteString(joinConds(q.having))
+	}
+	if len(q.orderBy) > 0 {
+		sb.WriteString(" ORDER BY ")
+		sb.WriteString(strings.Join(q.orderBy, ", "))
+	}
+	if q.limit > 0 {
+		sb.WriteString(" LIMIT ")
+		sb.WriteString(strconv.Itoa(q.limit))
+		if q.offset > 0 {
+			sb.WriteString(" OFFSET ")
+			sb.WriteString(strconv.Itoa(q.offset))
+		}
+	}
+
+	args := make([]interface{}, 0, len(q.whereArgs)+len(q.havingArgs))
+	args = append(args, q.whereArgs...)
+	args = append(args, q.havingArgs...)
+	return sb.String(), args
+}
+
+// String 返回生成的 SQL，用于日志
+func (q *Query) String() string {
+	sql, _ := q.Build()
+	return sql
+}
+
+// SelectToStruct 执行构造的查询并映射到结构体切片，见 QueryToStruct
+func (c *ClickHouseClient) SelectToStruct(ctx context.Context, dest interface{}, q *Query) error {
+	sql, args := q.Build()
+	return c.QueryToStructCtx(ctx, dest, sql, args...)
+}
+
+// latestQuery 生成按 keys 分组、以 argMax 取最新值的子查询。
+// 列使用表别名限定，避免与同名的结果别名冲突
+func (q *Query) latestQuery() string {
+	const alias = "__latest"
+	isKey := make(map[string]bool, len(q.keys))
+	for _, k := range q.keys {
+		isKey[k] = true
+	}
+	cols := append([]string(nil), q.keys...)
+	for _, col := range q.columns {
+		if isKey[col] || col == q.version {
+			continue
+		}
+		isKey[col] = true
+		cols = append(cols, fmt.Sprintf("argMax(%s.%s, %s.%s) AS %s", alias, col, alias, q.version, col))
+	}
+	cols = append(cols, fmt.Sprintf("max(%s.%s) AS %s", alias, q.version, q.version))
+	table := q.from + " AS " + alias
+	if q.final {
+		table += " FINAL"
+	}
+	return fmt.Sprintf("SELECT %s FROM %s GROUP BY %s", strings.Join(cols, ", "), table, strings.Join(q.keys, ", "))
+}
+
+// latestCond 生成 (keys, version) IN 子查询条件
+func (q *Query) latestCond() string {
+	keys := strings.Join(q.keys, ", ")
+	return fmt.Sprintf("(%s, %s) IN (SELECT %s, max(%s) FROM %s GROUP BY %s)",
+		keys, q.version, keys, q.version, q.from, keys)
+}
+
+// joinConds 以 AND 连接条件，多个条件时分别加括号以免 OR 改变优先级
+func joinConds(conds []string) string {
+	if len(conds) == 1 {
+		return conds[0]
+	}
+	return "(" + strings.Join(conds, ") AND (") + ")"
+}
+
+// placeholders 生成 n 个以逗号分隔的 ? 占位符
+func placeholders(n int) string {
+	return strings.TrimSuffix(strings.Repeat("?, ", n), ", ")
+}
@@ -0,0 +1,88 @@
+package ckgroup
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+	"time"
+)
+
+// ErrNotReplacing 表引擎不是 ReplacingMergeTree
+var ErrNotReplacing = errors.New("ckgroup: table engine is not ReplacingMergeTree")
+
+// InsertOrReplace 向 (Replicated)ReplacingMergeTree 表写入行，同一排序键的行合并后只保留 versionColumn 最大的一行，
+// 读取最新值时使用 Query.Final 或 Query.LatestBy。rows 为结构体切片时必须包含 versionColumn 对应的字段；
+// 为 []map[string]interface{} 时缺少 versionColumn 的行以当前时间作为版本号，
+// DateTime 类型的版本列写入当前时间，64 位整数写入纳秒时间戳，32 位整数写入秒级时间戳
+func (c *ClickHouseClient) InsertOrReplace(tableName string, rows interface{}, versionColumn string) error {
+	return c.InsertOrReplaceCtx(context.Background(), tableName, rows, versionColumn)
+}
+
+// InsertOrReplaceCtx 向 ReplacingMergeTree 表写入行，见 InsertOrReplace
+func (c *ClickHouseClient) InsertOrReplaceCtx(ctx context.Context, tableName string, rows interface{}, versionColumn string) error {
+	info, err := c.DescribeTable(ctx, tableName)
+	if err != nil {
+		return err
+	}
+	if !strings.Contains(info.Engine, "ReplacingMergeTree") {
+		return fmt.Errorf("%w: %s is %s", ErrNotReplacing, tableName, info.Engine)
+	}
+	i := slices.IndexFunc(info.Columns, func(col Column) bool { return col.Name == versionColumn })
+	if i < 0 {
+		return fmt.Errorf("version column %s not found in %s", versionColumn, tableName)
+	}
+
+	if maps, ok := rows.([]map[string]interface{}); ok {
+		version, err := versionValue(info.Columns[i].Type, time.Now())
+		if err != nil {
+			return err
+		}
+		filled := make([]map[string]interface{}, len(maps))
+		for j, row := range maps {
+			if _, ok := row[versionColumn]; ok {
+				filled[j] = row
+				continue
+			}
+			cp := make(map[string]interface{}, len(row)+1)
+			for k, v := range row {
+				cp[k] = v
+			}
+			cp[versionColumn] = version
+			filled[j] = cp
+		}
+		return c.BatchInsertMapsCtx(ctx, tableName, filled)
+	}
+
+	v := reflect.ValueOf(rows)
+	if v.Kind() != reflect.Slice {
+		return fmt.Errorf("rows must be a slice")
+	}
+	if v.Len() == 0 {
+		return nil
+	}
+	columns, err := c.analyzeStructure(v.Index(0).Interface())
+	if err != nil {
+		return fmt.Errorf("failed to analyze data structure: %w", err)
+	}
+	if !slices.Contains(columns, versionColumn) {
+		return fmt.Errorf("rows must contain version column %s", versionColumn)
+	}
+	return c.BatchInsertCtx(ctx, tableName, rows)
+}
+
+// versionValue 按版本列类型生成基于当前时间的版本号
+func versionValue(chType string, now time.Time) (interface{}, error) {
+	t := baseType(chType)
+	switch {
+	case strings.HasPrefix(t, "DateTime"):
+		return now, nil
+	case t == "UInt64" || t == "Int64":
+		return convertInt(now.UnixNano(), t, now), nil
+	case t == "UInt32" || t == "Int32":
+		return convertInt(now.Unix(), t, now), nil
+	}
+	return nil, fmt.Errorf("unsupported version column type: %s", chType)
+}
@@ -0,0 +1,162 @@
+package ckgroup
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrTableNotFound 表不存在
+var ErrTableNotFound = errors.New("ckgroup: table not found")
+
+// TableInfo 表结构信息
+type TableInfo struct {
+	Database     string
+	Name         string
+	Engine       string // 表引擎，如 ReplicatedMergeTree
+	PartitionKey string // 分区键表达式
+	SortingKey   string // 排序键表达式
+	PrimaryKey   string // 主键表达式
+	Comment      string
+	TotalRows    uint64 // 总行数，引擎不支持时为0
+	TotalBytes   uint64 // 总字节数，引擎不支持时为0
+	Columns      []Column
+}
+
+// ListTables 列出数据库中的表名，database 为空时使用当前数据库
+func (c *ClickHouseClient) ListTables(ctx context.Context, database string) ([]string, error) {
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+	query, args := "SELECT name FROM system.tables WHERE database = currentDatabase() ORDER BY name", []interface{}(nil)
+	if database != "" {
+		query, args = "SELECT name FROM system.tables WHERE database = ? ORDER BY name", []interface{}{database}
+	}
+	rows, err := c.query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var tables []string
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, err
+		}
+		tables = append(tables, name)
+	}
+	return tables, rows.Err()
+}
+
+// DescribeTable 读取表的引擎、分区键、排序键和列定义，tableName 可为 db.table，表不存在时返回 ErrTableNotFound
+func (c *ClickHouseClient) DescribeTable(ctx context.Context, tableName string) (*TableInfo, error) {
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+	database, table := splitTableName(tableName)
+	query, args := tableQuery(`SELECT database, name, engine, partition_key, sorting_key, primary_key, comment,
+	ifNull(total_rows, 0), ifNull(total_bytes, 0) FROM system.tables`, database, table)
+
+	info := &TableInfo{}
+	err := c.scanRow(ctx, query, args, &info.Database, &info.Name, &info.Engine,
+		&info.PartitionKey, &info.SortingKey, &info.PrimaryKey, &info.Comment, &info.TotalRows, &info.TotalBytes)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("%w: %s", ErrTableNotFound, tableName)
+		}
+		return nil, err
+	}
+
+	cols, err := c.tableColumns(ctx, tableName, false)
+	if err != nil {
+		return nil, err
+	}
+	info.Columns = cols
+	return info, nil
+}
+
+// ColumnTypes 返回列名到 ClickHouse 类型的映射
+func (c *ClickHouseClient) ColumnTypes(ctx context.Context, tableName string) (map[string]string, error) {
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+	cols, err := c.tableColumns(ctx, tableName, false)
+	if err != nil {
+		return nil, err
+	}
+	types := make(map[string]string, len(cols))
+	for _, col := range cols {
+		types[col.Name] = col.Type
+	}
+	return types, nil
+}
+
+// ValidateStruct 检查 sample 结构体（BatchInsert 的行类型）的列在表中都存在且可写入
+func (c *ClickHouseClient) ValidateStruct(ctx context.Context, tableName string, sample interface{}) error {
+	columns, err := c.analyzeStructure(sample)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+	cols, err := c.tableColumns(ctx, tableName, true)
+	if err != nil {
+		return err
+	}
+	exists := make(map[string]bool, len(cols))
+	for _, col := range cols {
+		exists[col.Name] = true
+	}
+	var missing []string
+	for _, name := range columns {
+		if !exists[name] {
+			missing = append(missing, name)
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("columns %s not found or not insertable in %s", strings.Join(missing, ", "), tableName)
+	}
+	return nil
+}
+
+// tableColumns 按定义顺序读取列，insertable 为 true 时排除 MATERIALIZED、ALIAS 列；表不存在时返回 ErrTableNotFound
+func (c *ClickHouseClient) tableColumns(ctx context.Context, tableName string, insertable bool) ([]Column, error) {
+	database, table := splitTableName(tableName)
+	query, args := tableQuery("SELECT name, type, comment FROM system.columns", database, table)
+	if insertable {
+		query += " AND default_kind NOT IN ('MATERIALIZED', 'ALIAS')"
+	}
+	query += " ORDER BY position"
+
+	rows, err := c.query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var cols []Column
+	for rows.Next() {
+		var col Column
+		if err := rows.Scan(&col.Name, &col.Type, &col.Comment); err != nil {
+			return nil, err
+		}
+		cols = append(cols, col)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if len(cols) == 0 {
+		return nil, fmt.Errorf("%w: %s", ErrTableNotFound, tableName)
+	}
+	return cols, nil
+}
+
+// tableQuery 为 system.tables/system.columns 查询附加库名和表名条件，database 为空时使用当前数据库
+func tableQuery(query, database, table string) (string, []interface{}) {
+	nameColumn := "table"
+	if strings.HasSuffix(query, "system.tables") {
+		nameColumn = "name"
+	}
+	if database == "" {
+		return query + " WHERE database = currentDatabase() AND " + nameColumn + " = ?", []interface{}{table}
+	}
+	return query + " WHERE database = ? AND " + nameColumn + " = ?", []interface{}{database, table}
+}
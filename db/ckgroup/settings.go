@@ -0,0 +1,77 @@
+package ckgroup
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ClickHouse/clickhouse-go/v2"
+)
+
+// WithSettings 返回附加查询级设置的客户端副本，与原客户端共享连接，多次调用时合并设置。
+// 可用于为报表查询单独限制资源，如
+//
+//	reports := ckgroup.CKCONN.WithSettings(map[string]interface{}{
+//		"max_memory_usage": 10 << 30,
+//		"max_threads":      4,
+//	})
+//
+// ctx 带截止时间时驱动会按剩余时间覆盖 max_execution_time
+func (c *ClickHouseClient) WithSettings(settings map[string]interface{}) *ClickHouseClient {
+	cc := *c
+	cc.settings = make(clickhouse.Settings, len(c.settings)+len(settings))
+	for k, v := range c.settings {
+		cc.settings[k] = v
+	}
+	for k, v := range settings {
+		cc.settings[k] = v
+	}
+	return &cc
+}
+
+// WithQueryID 返回使用指定 query_id 的客户端副本，便于在 system.query_log 中定位或通过 KILL QUERY 终止。
+// 同一时刻服务端不允许两个相同 query_id 的查询，副本应只用于一次调用；分批写入的多个批次依次使用同一 query_id
+func (c *ClickHouseClient) WithQueryID(queryID string) *ClickHouseClient {
+	cc := *c
+	cc.queryID = queryID
+	return &cc
+}
+
+// WithQuotaKey 返回使用指定 quota_key 的客户端副本，服务端配额按 quota_key 区分时可为不同业务分别限流
+func (c *ClickHouseClient) WithQuotaKey(quotaKey string) *ClickHouseClient {
+	cc := *c
+	cc.quotaKey = quotaKey
+	return &cc
+}
+
+// WithUser 返回以指定用户连接的客户端，查询使用该用户的角色、配额和 settings profile。
+// 服务端不支持按查询切换用户，因此会为每个节点建立新的连接，其余配置与原客户端相同，不再使用时需调用 Close
+func (c *ClickHouseClient) WithUser(username, password string) (*ClickHouseClient, error) {
+	config := c.config
+	config.Username, config.Password = username, password
+	pool, readPool, err := openPools(config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect as %s: %w", username, err)
+	}
+	cc := *c
+	cc.config = config
+	cc.pool, cc.readPool = pool, readPool
+	return &cc, nil
+}
+
+// queryOptions 将客户端的查询级设置附加到 ctx
+func (c *ClickHouseClient) queryOptions(ctx context.Context) context.Context {
+	var opts []clickhouse.QueryOption
+	if len(c.settings) > 0 {
+		opts = append(opts, clickhouse.WithSettings(c.settings))
+	}
+	if c.queryID != "" {
+		opts = append(opts, clickhouse.WithQueryID(c.queryID))
+	}
+	if c.quotaKey != "" {
+		opts = append(opts, clickhouse.WithQuotaKey(c.quotaKey))
+	}
+	if len(opts) == 0 {
+		return ctx
+	}
+	return clickhouse.Context(ctx, opts...)
+}
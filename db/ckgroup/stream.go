@@ -0,0 +1,153 @@
+package ckgroup
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"reflect"
+)
+
+// Rows 流式结果集，驱动按数据块逐块读取，适合导出大量数据，使用完毕后需调用 Close
+type Rows struct {
+	c         *ClickHouseClient
+	rows      *sql.Rows
+	columns   []string
+	nullable  []bool
+	scanTypes []reflect.Type
+}
+
+// QueryRows 执行查询并返回流式结果集，ctx 取消后读取中止
+func (c *ClickHouseClient) QueryRows(ctx context.Context, query string, args ...interface{}) (*Rows, error) {
+	rows, err := c.query(c.queryContext(ctx), query, args...)
+	if err != nil {
+		return nil, err
+	}
+	r, err := c.newRows(rows)
+	if err != nil {
+		rows.Close()
+		return nil, err
+	}
+	return r, nil
+}
+
+// QueryStream 逐行读取查询结果并调用 fn，行格式与 QueryToMaps 相同；fn 返回错误时停止读取并返回该错误
+func (c *ClickHouseClient) QueryStream(ctx context.Context, query string, fn func(row map[string]interface{}) error, args ...interface{}) error {
+	rows, err := c.QueryRows(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		row, err := rows.ScanMap()
+		if err != nil {
+			return err
+		}
+		if err := fn(row); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+func (c *ClickHouseClient) newRows(rows *sql.Rows) (*Rows, error) {
+	types, err := rows.ColumnTypes()
+	if err != nil {
+		return nil, err
+	}
+	r := &Rows{
+		c:         c,
+		rows:      rows,
+		columns:   make([]string, len(types)),
+		nullable:  make([]bool, len(types)),
+		scanTypes: make([]reflect.Type, len(types)),
+	}
+	for i, ct := range types {
+		r.columns[i] = ct.Name()
+		r.nullable[i], _ = ct.Nullable()
+		r.scanTypes[i] = ct.ScanType()
+		if r.scanTypes[i] == nil {
+			r.scanTypes[i] = reflect.TypeOf((*interface{})(nil)).Elem()
+		}
+	}
+	return r, nil
+}
+
+// Next 读取下一行，没有更多行或出错时返回 false，错误通过 Err 获取
+func (r *Rows) Next() bool {
+	return r.rows.Next()
+}
+
+// Err 返回读取过程中的错误
+func (r *Rows) Err() error {
+	return r.rows.Err()
+}
+
+// Close 关闭结果集
+func (r *Rows) Close() error {
+	return r.rows.Close()
+}
+
+// Columns 返回列名
+func (r *Rows) Columns() []string {
+	return r.columns
+}
+
+// Scan 按列顺序扫描当前行，同 sql.Rows.Scan
+func (r *Rows) Scan(dest ...interface{}) error {
+	return r.rows.Scan(dest...)
+}
+
+// ScanMap 将当前行转换为 map，值的类型见 QueryToMaps
+func (r *Rows) ScanMap() (map[string]interface{}, error) {
+	dest := make([]interface{}, len(r.scanTypes))
+	for i, t := range r.scanTypes {
+		dest[i] = reflect.New(t).Interface()
+	}
+	if err := r.rows.Scan(dest...); err != nil {
+		return nil, fmt.Errorf("failed to scan row: %w", err)
+	}
+	row := make(map[string]interface{}, len(r.columns))
+	for i, col := range r.columns {
+		row[col] = normalizeValue(reflect.ValueOf(dest[i]).Elem())
+	}
+	return row, nil
+}
+
+// ScanStruct 将当前行映射到 dest 指向的结构体，列与字段的对应规则同 QueryToStruct
+func (r *Rows) ScanStruct(dest interface{}) error {
+	v := reflect.ValueOf(dest)
+	if v.Kind() != reflect.Ptr || v.IsNil() || v.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("dest must be a pointer to struct")
+	}
+	return r.scanStruct(v.Elem())
+}
+
+// scanStruct 扫描当前行到结构体，Nullable 列扫描到非指针字段时先扫描到指针，NULL 时使用 default 选项或零值
+func (r *Rows) scanStruct(structValue reflect.Value) error {
+	scanDest := make([]interface{}, len(r.columns))
+	var holders []nullHolder
+	for i, col := range r.columns {
+		field, sf := r.c.findStructField(structValue, col)
+		switch {
+		case !field.IsValid() || !field.CanSet():
+			var dummy interface{}
+			scanDest[i] = &dummy
+		case r.nullable[i] && !acceptsNull(field):
+			h := nullHolder{field: field, ptr: reflect.New(reflect.PointerTo(field.Type())), sf: sf}
+			holders = append(holders, h)
+			scanDest[i] = h.ptr.Interface()
+		default:
+			scanDest[i] = field.Addr().Interface()
+		}
+	}
+
+	if err := r.rows.Scan(scanDest...); err != nil {
+		return err
+	}
+	for _, h := range holders {
+		if err := h.assign(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,5 @@
+// Package mongo MongoDB 客户端，提供连接管理、FindToMaps/FindToStruct、批量写入和索引创建，
+// 调用超时与 cache/redis、db/ckgroup 保持一致。
+//
+// 驱动依赖记录在 go.mod 中，得益于模块图裁剪，未导入本包的项目不会下载 MongoDB 驱动。
+package mongo
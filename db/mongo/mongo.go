@@ -0,0 +1,392 @@
+package mongo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+	"go.mongodb.org/mongo-driver/v2/x/mongo/driver/connstring"
+)
+
+var MONGOCONN MongoClient
+
+// ErrNotFound 没有匹配的文档
+var ErrNotFound = mongo.ErrNoDocuments
+
+// Config 配置结构，可通过 config.Loader 从配置文件、环境变量加载
+type Config struct {
+	URI      string `env:"MONGO_URI" default:"mongodb://127.0.0.1:27017"`
+	Database string `env:"MONGO_DATABASE"` // 默认数据库，为空时使用 URI 中的数据库
+	// Timeout 每次调用的默认超时，不带 ctx 的方法和 ctx 未设置截止时间时生效，0表示不限制
+	Timeout time.Duration `env:"MONGO_TIMEOUT"`
+	// ConnectTimeout 建立连接超时，默认10秒
+	ConnectTimeout time.Duration `env:"MONGO_CONNECT_TIMEOUT"`
+	// MaxPoolSize 每个节点的最大连接数，默认100
+	MaxPoolSize uint64 `env:"MONGO_MAX_POOL_SIZE"`
+	// MinPoolSize 每个节点保持的最小连接数
+	MinPoolSize uint64 `env:"MONGO_MIN_POOL_SIZE"`
+	// BatchSize BulkInsert 每批写入的文档数，默认1000
+	BatchSize int `env:"MONGO_BATCH_SIZE"`
+}
+
+// MongoClient MongoDB客户端
+type MongoClient struct {
+	client    *mongo.Client
+	db        *mongo.Database
+	timeout   time.Duration
+	batchSize int
+}
+
+// NewMongoClient 创建新的MongoDB客户端
+func NewMongoClient(config Config) (*MongoClient, error) {
+	if config.URI == "" {
+		return nil, fmt.Errorf("mongo uri must be provided")
+	}
+	connectTimeout := config.ConnectTimeout
+	if connectTimeout <= 0 {
+		connectTimeout = 10 * time.Second
+	}
+	opts := options.Client().ApplyURI(config.URI).SetConnectTimeout(connectTimeout)
+	if config.MaxPoolSize > 0 {
+		opts.SetMaxPoolSize(config.MaxPoolSize)
+	}
+	if config.MinPoolSize > 0 {
+		opts.SetMinPoolSize(config.MinPoolSize)
+	}
+
+	client, err := mongo.Connect(opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to mongo: %w", err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), connectTimeout)
+	defer cancel()
+	// 测试连接
+	if err := client.Ping(ctx, nil); err != nil {
+		client.Disconnect(context.Background())
+		return nil, fmt.Errorf("failed to ping mongo: %w", err)
+	}
+
+	database := config.Database
+	if database == "" {
+		cs, err := connstringDatabase(config.URI)
+		if err != nil {
+			client.Disconnect(context.Background())
+			return nil, err
+		}
+		database = cs
+	}
+
+	batchSize := config.BatchSize
+	if batchSize <= 0 {
+		batchSize = 1000
+	}
+	mc := MongoClient{
+		client:    client,
+		db:        client.Database(database),
+		timeout:   config.Timeout,
+		batchSize: batchSize,
+	}
+	MONGOCONN = mc
+
+	return &mc, nil
+}
+
+// Client 返回底层*mongo.Client
+func (c *MongoClient) Client() *mongo.Client {
+	return c.client
+}
+
+// Database 返回默认数据库
+func (c *MongoClient) Database() *mongo.Database {
+	return c.db
+}
+
+// Collection 返回默认数据库中的集合
+func (c *MongoClient) Collection(name string) *mongo.Collection {
+	return c.db.Collection(name)
+}
+
+// WithTimeout 返回使用指定调用超时的客户端副本，与原客户端共享连接
+func (c *MongoClient) WithTimeout(timeout time.Duration) *MongoClient {
+	cc := *c
+	cc.timeout = timeout
+	return &cc
+}
+
+// WithDatabase 返回使用另一个默认数据库的客户端副本，与原客户端共享连接
+func (c *MongoClient) WithDatabase(name string) *MongoClient {
+	cc := *c
+	cc.db = c.client.Database(name)
+	return &cc
+}
+
+// withTimeout ctx 没有截止时间且设置了调用超时时附加超时
+func (c *MongoClient) withTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	if c.timeout <= 0 {
+		return ctx, func() {}
+	}
+	if _, ok := ctx.Deadline(); ok {
+		return ctx, func() {}
+	}
+	return context.WithTimeout(ctx, c.timeout)
+}
+
+// Ping 检查连接是否可用
+func (c *MongoClient) Ping(ctx context.Context) error {
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+	return c.client.Ping(ctx, nil)
+}
+
+// Close 关闭连接
+func (c *MongoClient) Close() error {
+	if c.client == nil {
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	return c.client.Disconnect(ctx)
+}
+
+// FindOptions 查询选项
+type FindOptions struct {
+	Sort       bson.D      // 排序，如 bson.D{{Key: "time", Value: -1}}
+	Projection interface{} // 返回字段，如 bson.M{"_id": 0, "user_id": 1}
+	Limit      int64       // 返回文档数，0表示不限制
+	Skip       int64       // 跳过的文档数
+}
+
+func (o *FindOptions) build() *options.FindOptionsBuilder {
+	opts := options.Find()
+	if o == nil {
+		return opts
+	}
+	if len(o.Sort) > 0 {
+		opts.SetSort(o.Sort)
+	}
+	if o.Projection != nil {
+		opts.SetProjection(o.Projection)
+	}
+	if o.Limit > 0 {
+		opts.SetLimit(o.Limit)
+	}
+	if o.Skip > 0 {
+		opts.SetSkip(o.Skip)
+	}
+	return opts
+}
+
+// FindToMaps 查询并转换为 map 切片，filter 为 nil 时匹配全部文档。
+// ObjectID 转换为十六进制字符串，日期转换为 time.Time，嵌套文档转换为 map[string]interface{}，数组转换为 []interface{}
+func (c *MongoClient) FindToMaps(collection string, filter interface{}, opts *FindOptions) ([]map[string]interface{}, error) {
+	return c.FindToMapsCtx(context.Background(), collection, filter, opts)
+}
+
+// FindToMapsCtx 查询并转换为 map 切片，见 FindToMaps
+func (c *MongoClient) FindToMapsCtx(ctx context.Context, collection string, filter interface{}, opts *FindOptions) ([]map[string]interface{}, error) {
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+	cursor, err := c.Collection(collection).Find(ctx, orAll(filter), opts.build())
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	result := []map[string]interface{}{}
+	for cursor.Next(ctx) {
+		var doc bson.M
+		if err := cursor.Decode(&doc); err != nil {
+			return nil, err
+		}
+		result = append(result, normalizeDoc(doc))
+	}
+	return result, cursor.Err()
+}
+
+// FindToStruct 查询并按 bson 标签映射到结构体切片，如 []utils.SaveDebug
+func (c *MongoClient) FindToStruct(dest interface{}, collection string, filter interface{}, opts *FindOptions) error {
+	return c.FindToStructCtx(context.Background(), dest, collection, filter, opts)
+}
+
+// FindToStructCtx 查询并按 bson 标签映射到结构体切片，见 FindToStruct
+func (c *MongoClient) FindToStructCtx(ctx context.Context, dest interface{}, collection string, filter interface{}, opts *FindOptions) error {
+	destValue := reflect.ValueOf(dest)
+	if destValue.Kind() != reflect.Ptr || destValue.Elem().Kind() != reflect.Slice {
+		return fmt.Errorf("dest must be a pointer to slice")
+	}
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+	cursor, err := c.Collection(collection).Find(ctx, orAll(filter), opts.build())
+	if err != nil {
+		return err
+	}
+	return cursor.All(ctx, dest)
+}
+
+// FindOne 查询单个文档并解码到 dest，没有匹配的文档时返回 ErrNotFound
+func (c *MongoClient) FindOne(ctx context.Context, collection string, filter interface{}, dest interface{}) error {
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+	return c.Collection(collection).FindOne(ctx, orAll(filter)).Decode(dest)
+}
+
+// BulkInsert 按 BatchSize 分批写入文档切片，返回写入成功的文档数。
+// 批次内无序写入，个别文档失败（如唯一索引冲突）不影响同批其余文档，错误中包含失败的文档；批次出错后不再写入后续批次
+func (c *MongoClient) BulkInsert(collection string, docs interface{}) (int, error) {
+	return c.BulkInsertCtx(context.Background(), collection, docs)
+}
+
+// BulkInsertCtx 分批写入文档切片，见 BulkInsert
+func (c *MongoClient) BulkInsertCtx(ctx context.Context, collection string, docs interface{}) (int, error) {
+	docsValue := reflect.ValueOf(docs)
+	if docsValue.Kind() != reflect.Slice {
+		return 0, fmt.Errorf("docs must be a slice")
+	}
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+
+	coll := c.Collection(collection)
+	total := docsValue.Len()
+	inserted := 0
+	for i := 0; i < total; i += c.batchSize {
+		end := min(i+c.batchSize, total)
+		batch := make([]interface{}, 0, end-i)
+		for j := i; j < end; j++ {
+			batch = append(batch, docsValue.Index(j).Interface())
+		}
+		res, err := coll.InsertMany(ctx, batch, options.InsertMany().SetOrdered(false))
+		if err != nil {
+			// 无序写入时除 WriteErrors 中的文档外其余均已写入
+			var bwe mongo.BulkWriteException
+			if errors.As(err, &bwe) {
+				inserted += len(batch) - len(bwe.WriteErrors)
+			}
+			return inserted, fmt.Errorf("failed to insert documents %d-%d: %w", i, end-1, err)
+		}
+		inserted += len(res.InsertedIDs)
+	}
+	return inserted, nil
+}
+
+// Count 获取集合中匹配的文档数，filter 为 nil 时统计全部文档
+func (c *MongoClient) Count(collection string, filter interface{}) (int64, error) {
+	return c.CountCtx(context.Background(), collection, filter)
+}
+
+// CountCtx 获取集合中匹配的文档数
+func (c *MongoClient) CountCtx(ctx context.Context, collection string, filter interface{}) (int64, error) {
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+	return c.Collection(collection).CountDocuments(ctx, orAll(filter))
+}
+
+// Index 索引定义
+type Index struct {
+	Keys   bson.D        // 索引键，1 升序 -1 降序，如 bson.D{{Key: "user_id", Value: 1}, {Key: "time", Value: -1}}
+	Name   string        // 索引名，为空时由服务端按键生成
+	Unique bool          // 唯一索引
+	Sparse bool          // 稀疏索引，不包含缺少该字段的文档
+	TTL    time.Duration // 大于0时为 TTL 索引，文档在索引字段的时间之后 TTL 过期删除，只能用于单个日期字段
+}
+
+// EnsureIndexes 创建索引，已存在的同名同定义索引不会重复创建，返回索引名
+func (c *MongoClient) EnsureIndexes(ctx context.Context, collection string, indexes ...Index) ([]string, error) {
+	if len(indexes) == 0 {
+		return nil, nil
+	}
+	models := make([]mongo.IndexModel, len(indexes))
+	for i, idx := range indexes {
+		if len(idx.Keys) == 0 {
+			return nil, fmt.Errorf("index %d of %s has no keys", i, collection)
+		}
+		opts := options.Index()
+		if idx.Name != "" {
+			opts.SetName(idx.Name)
+		}
+		if idx.Unique {
+			opts.SetUnique(true)
+		}
+		if idx.Sparse {
+			opts.SetSparse(true)
+		}
+		if idx.TTL > 0 {
+			opts.SetExpireAfterSeconds(int32(idx.TTL / time.Second))
+		}
+		models[i] = mongo.IndexModel{Keys: idx.Keys, Options: opts}
+	}
+
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+	names, err := c.Collection(collection).Indexes().CreateMany(ctx, models)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create indexes on %s: %w", collection, err)
+	}
+	return names, nil
+}
+
+// DropIndex 删除索引
+func (c *MongoClient) DropIndex(ctx context.Context, collection, name string) error {
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+	return c.Collection(collection).Indexes().DropOne(ctx, name)
+}
+
+// orAll filter 为 nil 时返回匹配全部文档的条件
+func orAll(filter interface{}) interface{} {
+	if filter == nil {
+		return bson.D{}
+	}
+	return filter
+}
+
+// normalizeDoc 将解码后的文档转换为 Go 基础类型
+func normalizeDoc(doc bson.M) map[string]interface{} {
+	m := make(map[string]interface{}, len(doc))
+	for k, v := range doc {
+		m[k] = normalizeValue(v)
+	}
+	return m
+}
+
+func normalizeValue(v interface{}) interface{} {
+	switch val := v.(type) {
+	case bson.ObjectID:
+		return val.Hex()
+	case bson.DateTime:
+		return val.Time()
+	case bson.M:
+		return normalizeDoc(val)
+	case bson.D:
+		m := make(map[string]interface{}, len(val))
+		for _, e := range val {
+			m[e.Key] = normalizeValue(e.Value)
+		}
+		return m
+	case bson.A:
+		list := make([]interface{}, len(val))
+		for i, e := range val {
+			list[i] = normalizeValue(e)
+		}
+		return list
+	case bson.Decimal128:
+		return val.String()
+	}
+	return v
+}
+
+// connstringDatabase 读取 URI 中的数据库名
+func connstringDatabase(uri string) (string, error) {
+	cs, err := connstring.ParseAndValidate(uri)
+	if err != nil {
+		return "", fmt.Errorf("invalid mongo uri: %w", err)
+	}
+	if cs.Database == "" {
+		return "", fmt.Errorf("mongo database must be provided in Database or URI")
+	}
+	return cs.Database, nil
+}
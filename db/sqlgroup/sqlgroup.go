@@ -0,0 +1,308 @@
+package sqlgroup
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	mysqldriver "github.com/go-sql-driver/mysql"
+)
+
+var SQLCONN SQLClient
+
+// 支持的数据库方言
+const (
+	MySQL    = "mysql"
+	Postgres = "postgres"
+)
+
+// Config 配置结构，可通过 config.Loader 从配置文件、环境变量加载
+type Config struct {
+	// Dialect 数据库方言 mysql/postgres，默认 mysql
+	Dialect string `env:"SQL_DIALECT" default:"mysql"`
+	// DriverName database/sql 驱动名，默认 mysql 为 mysql，postgres 为 pgx。
+	// 本包只内置 MySQL 驱动，使用 PostgreSQL 时需在程序中导入驱动，如 _ "github.com/jackc/pgx/v5/stdlib"
+	DriverName string `env:"SQL_DRIVER_NAME"`
+	// DSN 连接字符串；MySQL 未指定 parseTime 时自动开启，以便时间列扫描为 time.Time
+	DSN string `env:"SQL_DSN"`
+	// BatchSize BatchInsert 每条 INSERT 语句的行数，默认1000，列数较多时按占位符上限自动减少
+	BatchSize int `env:"SQL_BATCH_SIZE"`
+	// Timeout 每次调用的默认超时，不带 ctx 的方法和 ctx 未设置截止时间时生效，0表示不限制
+	Timeout time.Duration `env:"SQL_TIMEOUT"`
+	// MaxOpenConns 最大连接数，默认10
+	MaxOpenConns int `env:"SQL_MAX_OPEN_CONNS"`
+	// MaxIdleConns 最大空闲连接数，默认5
+	MaxIdleConns int `env:"SQL_MAX_IDLE_CONNS"`
+	// ConnMaxLifetime 连接最长存活时间，默认1小时
+	ConnMaxLifetime time.Duration `env:"SQL_CONN_MAX_LIFETIME"`
+}
+
+// SQLClient MySQL/PostgreSQL 客户端，接口与 ckgroup.ClickHouseClient 保持一致。
+// 语句统一使用 ? 占位符，PostgreSQL 下自动转换为 $1、$2，已使用 $n 的语句不含 ? 时原样执行
+type SQLClient struct {
+	db        *sql.DB
+	dialect   string
+	batchSize int
+	timeout   time.Duration
+}
+
+// NewSQLClient 创建新的 MySQL/PostgreSQL 客户端
+func NewSQLClient(config Config) (*SQLClient, error) {
+	if config.DSN == "" {
+		return nil, fmt.Errorf("sql dsn must be provided")
+	}
+	dialect := strings.ToLower(config.Dialect)
+	if dialect == "" {
+		dialect = MySQL
+	}
+	driverName := config.DriverName
+	dsn := config.DSN
+	switch dialect {
+	case MySQL:
+		if driverName == "" {
+			driverName = "mysql"
+		}
+		if !strings.Contains(dsn, "parseTime=") {
+			cfg, err := mysqldriver.ParseDSN(dsn)
+			if err != nil {
+				return nil, fmt.Errorf("invalid mysql dsn: %w", err)
+			}
+			cfg.ParseTime = true
+			dsn = cfg.FormatDSN()
+		}
+	case Postgres:
+		if driverName == "" {
+			driverName = "pgx"
+		}
+	default:
+		return nil, fmt.Errorf("unsupported sql dialect: %s", config.Dialect)
+	}
+
+	db, err := sql.Open(driverName, dsn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s: %w", dialect, err)
+	}
+	maxOpenConns := config.MaxOpenConns
+	if maxOpenConns <= 0 {
+		maxOpenConns = 10
+	}
+	maxIdleConns := config.MaxIdleConns
+	if maxIdleConns <= 0 {
+		maxIdleConns = 5
+	}
+	connMaxLifetime := config.ConnMaxLifetime
+	if connMaxLifetime <= 0 {
+		connMaxLifetime = time.Hour
+	}
+	db.SetMaxOpenConns(maxOpenConns)
+	db.SetMaxIdleConns(maxIdleConns)
+	db.SetConnMaxLifetime(connMaxLifetime)
+
+	// 测试连接
+	if err := db.Ping(); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to ping %s: %w", dialect, err)
+	}
+
+	batchSize := config.BatchSize
+	if batchSize <= 0 {
+		batchSize = 1000
+	}
+	client := SQLClient{
+		db:        db,
+		dialect:   dialect,
+		batchSize: batchSize,
+		timeout:   config.Timeout,
+	}
+	SQLCONN = client
+
+	return &client, nil
+}
+
+// DB 返回底层*sql.DB
+func (c *SQLClient) DB() *sql.DB {
+	return c.db
+}
+
+// Dialect 返回数据库方言
+func (c *SQLClient) Dialect() string {
+	return c.dialect
+}
+
+// WithTimeout 返回使用指定调用超时的客户端副本，与原客户端共享连接
+func (c *SQLClient) WithTimeout(timeout time.Duration) *SQLClient {
+	cc := *c
+	cc.timeout = timeout
+	return &cc
+}
+
+// withTimeout ctx 没有截止时间且设置了调用超时时附加超时
+func (c *SQLClient) withTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	if c.timeout <= 0 {
+		return ctx, func() {}
+	}
+	if _, ok := ctx.Deadline(); ok {
+		return ctx, func() {}
+	}
+	return context.WithTimeout(ctx, c.timeout)
+}
+
+// queryContext 为返回结果集的查询附加调用超时，结果集在方法返回后才被读取，因此在超时到达时才释放计时器
+func (c *SQLClient) queryContext(ctx context.Context) context.Context {
+	if c.timeout <= 0 {
+		return ctx
+	}
+	if _, ok := ctx.Deadline(); ok {
+		return ctx
+	}
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	context.AfterFunc(ctx, cancel)
+	return ctx
+}
+
+// Ping 检查连接是否可用
+func (c *SQLClient) Ping(ctx context.Context) error {
+	return c.db.PingContext(ctx)
+}
+
+// Close 关闭连接
+func (c *SQLClient) Close() error {
+	if c.db == nil {
+		return nil
+	}
+	return c.db.Close()
+}
+
+// Exec 执行SQL语句，返回影响的行数
+func (c *SQLClient) Exec(query string, args ...interface{}) (int64, error) {
+	return c.ExecCtx(context.Background(), query, args...)
+}
+
+// ExecCtx 执行SQL语句，返回影响的行数
+func (c *SQLClient) ExecCtx(ctx context.Context, query string, args ...interface{}) (int64, error) {
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+	res, err := c.db.ExecContext(ctx, c.rebind(query), args...)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
+
+// Query 执行查询
+func (c *SQLClient) Query(query string, args ...interface{}) (*sql.Rows, error) {
+	return c.QueryCtx(context.Background(), query, args...)
+}
+
+// QueryCtx 执行查询
+func (c *SQLClient) QueryCtx(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	return c.db.QueryContext(c.queryContext(ctx), c.rebind(query), args...)
+}
+
+// QueryRow 执行单行查询
+func (c *SQLClient) QueryRow(query string, args ...interface{}) *sql.Row {
+	return c.QueryRowCtx(context.Background(), query, args...)
+}
+
+// QueryRowCtx 执行单行查询
+func (c *SQLClient) QueryRowCtx(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	return c.db.QueryRowContext(c.queryContext(ctx), c.rebind(query), args...)
+}
+
+// Count 获取表记录数
+func (c *SQLClient) Count(tableName string, where string, args ...interface{}) (int64, error) {
+	return c.CountCtx(context.Background(), tableName, where, args...)
+}
+
+// CountCtx 获取表记录数
+func (c *SQLClient) CountCtx(ctx context.Context, tableName string, where string, args ...interface{}) (int64, error) {
+	query := fmt.Sprintf("SELECT COUNT(*) FROM %s", tableName)
+	if where != "" {
+		query += " WHERE " + where
+	}
+
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+	var count int64
+	err := c.db.QueryRowContext(ctx, c.rebind(query), args...).Scan(&count)
+	return count, err
+}
+
+// Transaction 在事务中执行fn，fn返回错误时回滚
+func (c *SQLClient) Transaction(fn func(tx *sql.Tx) error) error {
+	return c.TransactionCtx(context.Background(), fn)
+}
+
+// TransactionCtx 在事务中执行fn，fn返回错误时回滚。事务内的语句直接使用 tx，PostgreSQL 下需自行使用 $n 占位符
+// 或通过 Rebind 转换
+func (c *SQLClient) TransactionCtx(ctx context.Context, fn func(tx *sql.Tx) error) error {
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+	tx, err := c.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	if err := fn(tx); err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+// Rebind 将语句中的 ? 占位符转换为当前方言的形式
+func (c *SQLClient) Rebind(query string) string {
+	return c.rebind(query)
+}
+
+// rebind PostgreSQL 下将引号和注释以外的 ? 依次替换为 $1、$2
+func (c *SQLClient) rebind(query string) string {
+	if c.dialect != Postgres || !strings.Contains(query, "?") {
+		return query
+	}
+	var sb strings.Builder
+	sb.Grow(len(query) + 8)
+	n := 0
+	var quote byte
+	for i := 0; i < len(query); i++ {
+		ch := query[i]
+		switch {
+		case quote != 0:
+			if ch == quote {
+				quote = 0
+			}
+		case ch == '\'' || ch == '"':
+			quote = ch
+		case ch == '-' && i+1 < len(query) && query[i+1] == '-':
+			end := strings.IndexByte(query[i:], '\n')
+			if end < 0 {
+				end = len(query) - i
+			}
+			sb.WriteString(query[i : i+end])
+			i += end - 1
+			continue
+		case ch == '?':
+			n++
+			sb.WriteByte('$')
+			sb.WriteString(strconv.Itoa(n))
+			continue
+		}
+		sb.WriteByte(ch)
+	}
+	return sb.String()
+}
+
+// quoteIdentifier 按方言转义标识符，db.table 形式分别转义
+func (c *SQLClient) quoteIdentifier(name string) string {
+	q := "`"
+	if c.dialect == Postgres {
+		q = `"`
+	}
+	parts := strings.Split(name, ".")
+	for i, p := range parts {
+		parts[i] = q + strings.ReplaceAll(p, q, q+q) + q
+	}
+	return strings.Join(parts, ".")
+}
@@ -0,0 +1,272 @@
+package sqlgroup
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// maxPlaceholders 单条语句的占位符上限，MySQL 和 PostgreSQL 均为 65535
+const maxPlaceholders = 65535
+
+var scannerType = reflect.TypeOf((*sql.Scanner)(nil)).Elem()
+
+// BatchInsert 批量插入结构体切片，列名规则与 ckgroup 相同：db 标签、json 标签、字段名小写，标签为 - 的字段忽略。
+// 按 BatchSize 拼接多行 INSERT 语句，所有语句在同一事务中执行，任一批次失败时全部回滚
+func (c *SQLClient) BatchInsert(tableName string, data interface{}) error {
+	return c.BatchInsertCtx(context.Background(), tableName, data)
+}
+
+// BatchInsertCtx 批量插入结构体切片，见 BatchInsert
+func (c *SQLClient) BatchInsertCtx(ctx context.Context, tableName string, data interface{}) error {
+	dataValue := reflect.ValueOf(data)
+	if dataValue.Kind() != reflect.Slice {
+		return fmt.Errorf("data must be a slice")
+	}
+	dataLen := dataValue.Len()
+	if dataLen == 0 {
+		return nil
+	}
+
+	elemType := dataValue.Type().Elem()
+	if elemType.Kind() == reflect.Ptr {
+		elemType = elemType.Elem()
+	}
+	if elemType.Kind() != reflect.Struct {
+		return fmt.Errorf("data must be a slice of struct or pointer to struct")
+	}
+	fields := structFields(elemType)
+	if len(fields) == 0 {
+		return fmt.Errorf("no exported fields in %s", elemType)
+	}
+	columns := make([]string, len(fields))
+	for i, f := range fields {
+		columns[i] = c.quoteIdentifier(f.name)
+	}
+
+	batchSize := min(c.batchSize, maxPlaceholders/len(fields))
+	prefix := fmt.Sprintf("INSERT INTO %s (%s) VALUES ", c.quoteIdentifier(tableName), strings.Join(columns, ", "))
+	row := "(" + strings.TrimSuffix(strings.Repeat("?, ", len(fields)), ", ") + ")"
+
+	return c.TransactionCtx(ctx, func(tx *sql.Tx) error {
+		for i := 0; i < dataLen; i += batchSize {
+			end := min(i+batchSize, dataLen)
+			rows := make([]string, 0, end-i)
+			args := make([]interface{}, 0, (end-i)*len(fields))
+			for j := i; j < end; j++ {
+				item := reflect.Indirect(dataValue.Index(j))
+				if !item.IsValid() {
+					return fmt.Errorf("item %d is nil", j)
+				}
+				for _, f := range fields {
+					args = append(args, item.FieldByIndex(f.index).Interface())
+				}
+				rows = append(rows, row)
+			}
+			if _, err := tx.ExecContext(ctx, c.rebind(prefix+strings.Join(rows, ", ")), args...); err != nil {
+				return fmt.Errorf("failed to insert rows %d-%d: %w", i, end-1, err)
+			}
+		}
+		return nil
+	})
+}
+
+// QueryToStruct 查询并映射到结构体切片，列按 BatchInsert 的列名规则匹配字段，没有对应字段的列忽略，
+// NULL 扫描到非指针字段时为零值
+func (c *SQLClient) QueryToStruct(dest interface{}, query string, args ...interface{}) error {
+	return c.QueryToStructCtx(context.Background(), dest, query, args...)
+}
+
+// QueryToStructCtx 查询并映射到结构体切片，见 QueryToStruct
+func (c *SQLClient) QueryToStructCtx(ctx context.Context, dest interface{}, query string, args ...interface{}) error {
+	destValue := reflect.ValueOf(dest)
+	if destValue.Kind() != reflect.Ptr || destValue.Elem().Kind() != reflect.Slice {
+		return fmt.Errorf("dest must be a pointer to slice")
+	}
+	sliceValue := destValue.Elem()
+	elemType := sliceValue.Type().Elem()
+	isPtr := elemType.Kind() == reflect.Ptr
+	if isPtr {
+		elemType = elemType.Elem()
+	}
+	if elemType.Kind() != reflect.Struct {
+		return fmt.Errorf("slice elements must be structs")
+	}
+
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+	rows, err := c.db.QueryContext(ctx, c.rebind(query), args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return err
+	}
+	byName := make(map[string][]int)
+	for _, f := range structFields(elemType) {
+		byName[f.name] = f.index
+	}
+
+	for rows.Next() {
+		elem := reflect.New(elemType)
+		structValue := elem.Elem()
+		scanDest := make([]interface{}, len(columns))
+		var holders [][2]reflect.Value
+		for i, col := range columns {
+			index, ok := byName[col]
+			if !ok {
+				var dummy interface{}
+				scanDest[i] = &dummy
+				continue
+			}
+			field := structValue.FieldByIndex(index)
+			if acceptsNull(field) {
+				scanDest[i] = field.Addr().Interface()
+				continue
+			}
+			// 通过 **T 接收 NULL
+			ptr := reflect.New(reflect.PointerTo(field.Type()))
+			holders = append(holders, [2]reflect.Value{field, ptr})
+			scanDest[i] = ptr.Interface()
+		}
+		if err := rows.Scan(scanDest...); err != nil {
+			return err
+		}
+		for _, h := range holders {
+			if p := h[1].Elem(); !p.IsNil() {
+				h[0].Set(p.Elem())
+			}
+		}
+
+		if isPtr {
+			sliceValue.Set(reflect.Append(sliceValue, elem))
+		} else {
+			sliceValue.Set(reflect.Append(sliceValue, structValue))
+		}
+	}
+	return rows.Err()
+}
+
+// QueryToMaps 查询并转换为map切片，文本协议返回的 []byte 按列类型转换为整数、浮点数或字符串，二进制列保留 []byte
+func (c *SQLClient) QueryToMaps(query string, args ...interface{}) ([]map[string]interface{}, error) {
+	return c.QueryToMapsCtx(context.Background(), query, args...)
+}
+
+// QueryToMapsCtx 查询并转换为map切片，见 QueryToMaps
+func (c *SQLClient) QueryToMapsCtx(ctx context.Context, query string, args ...interface{}) ([]map[string]interface{}, error) {
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+	rows, err := c.db.QueryContext(ctx, c.rebind(query), args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	types, err := rows.ColumnTypes()
+	if err != nil {
+		return nil, err
+	}
+
+	result := []map[string]interface{}{}
+	for rows.Next() {
+		values := make([]interface{}, len(types))
+		scanDest := make([]interface{}, len(types))
+		for i := range values {
+			scanDest[i] = &values[i]
+		}
+		if err := rows.Scan(scanDest...); err != nil {
+			return nil, err
+		}
+
+		row := make(map[string]interface{}, len(types))
+		for i, t := range types {
+			row[t.Name()] = normalizeValue(values[i], t.DatabaseTypeName())
+		}
+		result = append(result, row)
+	}
+	return result, rows.Err()
+}
+
+// normalizeValue 将 []byte 按数据库类型名转换
+func normalizeValue(v interface{}, dbType string) interface{} {
+	b, ok := v.([]byte)
+	if !ok {
+		return v
+	}
+	s := string(b)
+	dbType = strings.ToUpper(dbType)
+	switch {
+	case strings.Contains(dbType, "BLOB") || strings.Contains(dbType, "BINARY") || dbType == "BYTEA" || dbType == "BIT":
+		return b
+	case strings.HasPrefix(dbType, "UNSIGNED ") && strings.HasSuffix(dbType, "INT"):
+		if n, err := strconv.ParseUint(s, 10, 64); err == nil {
+			return n
+		}
+	case strings.HasSuffix(dbType, "INT") || strings.HasPrefix(dbType, "INT") || dbType == "SERIAL" || dbType == "BIGSERIAL":
+		if n, err := strconv.ParseInt(s, 10, 64); err == nil {
+			return n
+		}
+	case dbType == "FLOAT" || dbType == "DOUBLE" || dbType == "REAL" || strings.HasPrefix(dbType, "FLOAT"):
+		if f, err := strconv.ParseFloat(s, 64); err == nil {
+			return f
+		}
+	}
+	return s
+}
+
+// field 结构体字段与列的对应关系
+type field struct {
+	name  string
+	index []int
+}
+
+// structFields 按字段顺序返回可导出字段对应的列，匿名嵌入的结构体字段展开
+func structFields(t reflect.Type) []field {
+	var fields []field
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		// 嵌入的结构体即使类型未导出，其导出字段也可访问
+		if sf.Anonymous && sf.Type.Kind() == reflect.Struct && sf.Tag.Get("db") == "" {
+			for _, sub := range structFields(sf.Type) {
+				sub.index = append([]int{i}, sub.index...)
+				fields = append(fields, sub)
+			}
+			continue
+		}
+		if !sf.IsExported() {
+			continue
+		}
+		name := columnName(sf)
+		if name == "-" {
+			continue
+		}
+		fields = append(fields, field{name: name, index: []int{i}})
+	}
+	return fields
+}
+
+// columnName 获取字段对应的列名，规则与 ckgroup 相同
+func columnName(sf reflect.StructField) string {
+	if tag := sf.Tag.Get("db"); tag != "" {
+		return strings.Split(tag, ",")[0]
+	}
+	if tag := sf.Tag.Get("json"); tag != "" {
+		return strings.Split(tag, ",")[0]
+	}
+	return strings.ToLower(sf.Name)
+}
+
+// acceptsNull 字段能否直接接收 NULL：指针、接口、切片、Map 和实现 sql.Scanner 的类型（如 sql.NullInt64）
+func acceptsNull(field reflect.Value) bool {
+	switch field.Kind() {
+	case reflect.Ptr, reflect.Interface, reflect.Slice, reflect.Map:
+		return true
+	}
+	return field.Addr().Type().Implements(scannerType)
+}
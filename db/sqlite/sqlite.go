@@ -0,0 +1,301 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	sqlite3 "github.com/mattn/go-sqlite3"
+)
+
+var SQLITECONN SQLiteClient
+
+// Config 配置结构
+type Config struct {
+	Path         string        // 数据库文件路径
+	BusyTimeout  time.Duration // 数据库被锁时的等待时间，默认5秒
+	Synchronous  string        // 同步模式 OFF/NORMAL/FULL/EXTRA，默认FULL保证断电不丢数据
+	MaxOpenConns int           // 打开数据库连接的最大数量，默认1（SQLite写操作串行）
+	MaxRetries   int           // 遇到SQLITE_BUSY/SQLITE_LOCKED时的重试次数，默认3
+	Migrations   []Migration   // 启动时执行的结构迁移
+}
+
+// Migration 结构迁移，按Version从小到大执行，已执行的版本记录在schema_migrations表中
+type Migration struct {
+	Version int
+	Name    string
+	SQL     string              // 迁移语句，可包含多条以分号分隔的语句
+	Up      func(*sql.Tx) error // 自定义迁移逻辑，与SQL同时存在时先执行SQL
+}
+
+// SQLiteClient SQLite客户端
+type SQLiteClient struct {
+	db         *sql.DB
+	path       string
+	maxRetries int
+	migrations []Migration
+}
+
+// NewSQLiteClient 创建新的SQLite客户端，开启WAL模式并执行结构迁移
+func NewSQLiteClient(config Config) (*SQLiteClient, error) {
+	if config.Path == "" {
+		return nil, fmt.Errorf("sqlite path must be provided")
+	}
+	if err := os.MkdirAll(filepath.Dir(config.Path), 0755); err != nil {
+		return nil, fmt.Errorf("failed to create sqlite directory: %w", err)
+	}
+
+	busyTimeout := config.BusyTimeout
+	if busyTimeout <= 0 {
+		busyTimeout = 5 * time.Second
+	}
+	synchronous := strings.ToUpper(config.Synchronous)
+	if synchronous == "" {
+		synchronous = "FULL"
+	}
+	maxOpenConns := config.MaxOpenConns
+	if maxOpenConns <= 0 {
+		maxOpenConns = 1
+	}
+	maxRetries := config.MaxRetries
+	if maxRetries <= 0 {
+		maxRetries = 3
+	}
+
+	params := url.Values{}
+	params.Set("_journal_mode", "WAL")
+	params.Set("_busy_timeout", fmt.Sprintf("%d", busyTimeout.Milliseconds()))
+	params.Set("_synchronous", synchronous)
+	params.Set("_foreign_keys", "on")
+	params.Set("_txlock", "immediate")
+
+	db, err := sql.Open("sqlite3", "file:"+config.Path+"?"+params.Encode())
+	if err != nil {
+		return nil, fmt.Errorf("failed to open sqlite: %w", err)
+	}
+	db.SetMaxOpenConns(maxOpenConns)
+	db.SetMaxIdleConns(maxOpenConns)
+
+	// 测试连接
+	if err := db.Ping(); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to ping sqlite: %w", err)
+	}
+
+	client := SQLiteClient{
+		db:         db,
+		path:       config.Path,
+		maxRetries: maxRetries,
+		migrations: append([]Migration{}, config.Migrations...),
+	}
+	if err := client.Migrate(); err != nil {
+		db.Close()
+		return nil, err
+	}
+	SQLITECONN = client
+
+	return &client, nil
+}
+
+// DB 返回底层*sql.DB
+func (c *SQLiteClient) DB() *sql.DB {
+	return c.db
+}
+
+// Close 关闭连接
+func (c *SQLiteClient) Close() error {
+	if c.db == nil {
+		return nil
+	}
+	return c.db.Close()
+}
+
+// RegisterMigration 注册结构迁移，需在Migrate之前调用
+func (c *SQLiteClient) RegisterMigration(m ...Migration) {
+	c.migrations = append(c.migrations, m...)
+}
+
+// Migrate 执行尚未执行过的结构迁移，每个版本在独立事务中执行
+func (c *SQLiteClient) Migrate() error {
+	migrations := append([]Migration{}, c.migrations...)
+
+	if len(migrations) == 0 {
+		return nil
+	}
+	sort.SliceStable(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+
+	if err := c.Exec(`CREATE TABLE IF NOT EXISTS schema_migrations (
+  version INTEGER PRIMARY KEY,
+  name TEXT NOT NULL,
+  applied_at INTEGER NOT NULL
+)`); err != nil {
+		return fmt.Errorf("failed to create schema_migrations: %w", err)
+	}
+
+	applied := make(map[int]bool)
+	rows, err := c.Query("SELECT version FROM schema_migrations")
+	if err != nil {
+		return fmt.Errorf("failed to load schema_migrations: %w", err)
+	}
+	for rows.Next() {
+		var v int
+		if err := rows.Scan(&v); err != nil {
+			rows.Close()
+			return err
+		}
+		applied[v] = true
+	}
+	rows.Close()
+
+	for _, m := range migrations {
+		if applied[m.Version] {
+			continue
+		}
+		err := c.Transaction(func(tx *sql.Tx) error {
+			if m.SQL != "" {
+				if _, err := tx.Exec(m.SQL); err != nil {
+					return err
+				}
+			}
+			if m.Up != nil {
+				if err := m.Up(tx); err != nil {
+					return err
+				}
+			}
+			_, err := tx.Exec("INSERT INTO schema_migrations (version, name, applied_at) VALUES (?, ?, ?)",
+				m.Version, m.Name, time.Now().Unix())
+			return err
+		})
+		if err != nil {
+			return fmt.Errorf("failed to apply migration %d(%s): %w", m.Version, m.Name, err)
+		}
+		applied[m.Version] = true
+	}
+	return nil
+}
+
+// isBusy 判断是否为数据库忙/被锁错误
+func isBusy(err error) bool {
+	var sqliteErr sqlite3.Error
+	if errors.As(err, &sqliteErr) {
+		return sqliteErr.Code == sqlite3.ErrBusy || sqliteErr.Code == sqlite3.ErrLocked
+	}
+	return false
+}
+
+// withRetry 在数据库忙时按退避时间重试
+func (c *SQLiteClient) withRetry(ctx context.Context, fn func() error) error {
+	var err error
+	for i := 0; i <= c.maxRetries; i++ {
+		if err = fn(); err == nil || !isBusy(err) {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(time.Duration(i+1) * 50 * time.Millisecond):
+		}
+	}
+	return err
+}
+
+// Exec 执行SQL语句
+func (c *SQLiteClient) Exec(query string, args ...interface{}) error {
+	return c.ExecContext(context.Background(), query, args...)
+}
+
+// ExecContext 执行SQL语句
+func (c *SQLiteClient) ExecContext(ctx context.Context, query string, args ...interface{}) error {
+	return c.withRetry(ctx, func() error {
+		_, err := c.db.ExecContext(ctx, query, args...)
+		return err
+	})
+}
+
+// Transaction 在事务中执行fn，fn返回错误时回滚
+func (c *SQLiteClient) Transaction(fn func(tx *sql.Tx) error) error {
+	return c.withRetry(context.Background(), func() error {
+		tx, err := c.db.Begin()
+		if err != nil {
+			return err
+		}
+		if err := fn(tx); err != nil {
+			tx.Rollback()
+			return err
+		}
+		return tx.Commit()
+	})
+}
+
+// Query 执行查询
+func (c *SQLiteClient) Query(query string, args ...interface{}) (*sql.Rows, error) {
+	return c.db.QueryContext(context.Background(), query, args...)
+}
+
+// QueryRow 执行单行查询
+func (c *SQLiteClient) QueryRow(query string, args ...interface{}) *sql.Row {
+	return c.db.QueryRowContext(context.Background(), query, args...)
+}
+
+// QueryToMaps 查询并转换为map切片
+func (c *SQLiteClient) QueryToMaps(query string, args ...interface{}) ([]map[string]interface{}, error) {
+	rows, err := c.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, err
+	}
+
+	result := []map[string]interface{}{}
+	for rows.Next() {
+		values := make([]interface{}, len(columns))
+		scanDest := make([]interface{}, len(columns))
+		for i := range values {
+			scanDest[i] = &values[i]
+		}
+		if err := rows.Scan(scanDest...); err != nil {
+			return nil, err
+		}
+
+		row := make(map[string]interface{}, len(columns))
+		for i, col := range columns {
+			// TEXT类型可能以[]byte返回，统一转换为string
+			if b, ok := values[i].([]byte); ok {
+				row[col] = string(b)
+			} else {
+				row[col] = values[i]
+			}
+		}
+		result = append(result, row)
+	}
+
+	return result, rows.Err()
+}
+
+// Count 获取表记录数
+func (c *SQLiteClient) Count(tableName string, where string, args ...interface{}) (int64, error) {
+	query := fmt.Sprintf("SELECT COUNT(*) FROM %s", tableName)
+	if where != "" {
+		query += " WHERE " + where
+	}
+
+	var count int64
+	err := c.QueryRow(query, args...).Scan(&count)
+	return count, err
+}
+
+// Checkpoint 将WAL日志合并回主数据库文件
+func (c *SQLiteClient) Checkpoint() error {
+	return c.Exec("PRAGMA wal_checkpoint(TRUNCATE)")
+}
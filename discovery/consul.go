@@ -0,0 +1,226 @@
+package discovery
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/consul/api"
+)
+
+// ConsulRegistry 基于 Consul agent 的注册中心，使用TTL检查维持心跳
+type ConsulRegistry struct {
+	client *api.Client
+	ttl    time.Duration
+
+	mu   sync.Mutex
+	regs map[string]context.CancelFunc
+}
+
+// NewConsul 创建 Consul 注册中心客户端，Endpoints 为空时使用 Consul 默认地址
+func NewConsul(config Config) (*ConsulRegistry, error) {
+	if config.TTL <= 0 {
+		config.TTL = 10 * time.Second
+	}
+	cfg := api.DefaultConfig()
+	if len(config.Endpoints) > 0 {
+		cfg.Address = config.Endpoints[0]
+	}
+	if config.Token != "" {
+		cfg.Token = config.Token
+	}
+	if config.DialTimeout > 0 {
+		cfg.Transport.DialContext = (&net.Dialer{
+			Timeout:   config.DialTimeout,
+			KeepAlive: 30 * time.Second,
+		}).DialContext
+	}
+	client, err := api.NewClient(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create consul client: %w", err)
+	}
+	return &ConsulRegistry{
+		client: client,
+		ttl:    config.TTL,
+		regs:   make(map[string]context.CancelFunc),
+	}, nil
+}
+
+// Client 返回底层 Consul 客户端
+func (r *ConsulRegistry) Client() *api.Client {
+	return r.client
+}
+
+func checkID(id string) string {
+	return "service:" + id
+}
+
+// Register 向本地 agent 注册实例，按 TTL/3 的间隔上报心跳，超过一分钟未上报的实例由 Consul 自动注销
+func (r *ConsulRegistry) Register(ctx context.Context, ins *Instance) error {
+	if err := ins.normalize(); err != nil {
+		return err
+	}
+	host, portStr, err := net.SplitHostPort(ins.Addr)
+	if err != nil {
+		return fmt.Errorf("invalid instance addr %s: %w", ins.Addr, err)
+	}
+	port, err := strconv.Atoi(portStr)
+	if err != nil {
+		return fmt.Errorf("invalid instance port %s: %w", portStr, err)
+	}
+	reg := &api.AgentServiceRegistration{
+		ID:      ins.ID,
+		Name:    ins.Name,
+		Address: host,
+		Port:    port,
+		Meta:    ins.Metadata,
+		Check: &api.AgentServiceCheck{
+			CheckID:                        checkID(ins.ID),
+			TTL:                            r.ttl.String(),
+			DeregisterCriticalServiceAfter: time.Minute.String(),
+		},
+	}
+
+	r.mu.Lock()
+	if _, ok := r.regs[ins.ID]; ok {
+		r.mu.Unlock()
+		return fmt.Errorf("instance %s already registered", ins.ID)
+	}
+	kctx, cancel := context.WithCancel(context.Background())
+	r.regs[ins.ID] = cancel
+	r.mu.Unlock()
+
+	opts := api.ServiceRegisterOpts{}.WithContext(ctx)
+	if err := r.client.Agent().ServiceRegisterOpts(reg, opts); err != nil {
+		r.mu.Lock()
+		delete(r.regs, ins.ID)
+		r.mu.Unlock()
+		cancel()
+		return fmt.Errorf("failed to register %s: %w", ins.ID, err)
+	}
+	go r.heartbeat(kctx, reg)
+	return nil
+}
+
+// heartbeat 定时更新TTL检查，检查不存在时（如 agent 重启）重新注册
+func (r *ConsulRegistry) heartbeat(ctx context.Context, reg *api.AgentServiceRegistration) {
+	interval := r.ttl / 3
+	if interval < time.Second {
+		interval = time.Second
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	q := (&api.QueryOptions{}).WithContext(ctx)
+	for {
+		if err := r.client.Agent().UpdateTTLOpts(reg.Check.CheckID, "", api.HealthPassing, q); err != nil && ctx.Err() == nil {
+			_ = r.client.Agent().ServiceRegisterOpts(reg, api.ServiceRegisterOpts{}.WithContext(ctx))
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Deregister 停止心跳并从 agent 注销实例
+func (r *ConsulRegistry) Deregister(ctx context.Context, ins *Instance) error {
+	if err := ins.normalize(); err != nil {
+		return err
+	}
+	r.mu.Lock()
+	if cancel, ok := r.regs[ins.ID]; ok {
+		cancel()
+		delete(r.regs, ins.ID)
+	}
+	r.mu.Unlock()
+	q := (&api.QueryOptions{}).WithContext(ctx)
+	if err := r.client.Agent().ServiceDeregisterOpts(ins.ID, q); err != nil {
+		return fmt.Errorf("failed to deregister %s: %w", ins.ID, err)
+	}
+	return nil
+}
+
+// Resolve 获取服务的健康实例
+func (r *ConsulRegistry) Resolve(ctx context.Context, name string) ([]*Instance, error) {
+	list, _, err := r.query(ctx, name, 0)
+	return list, err
+}
+
+// query 查询健康实例，waitIndex 大于0时为阻塞查询
+func (r *ConsulRegistry) query(ctx context.Context, name string, waitIndex uint64) ([]*Instance, uint64, error) {
+	q := (&api.QueryOptions{WaitIndex: waitIndex}).WithContext(ctx)
+	entries, meta, err := r.client.Health().Service(name, "", true, q)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to query %s: %w", name, err)
+	}
+	list := make([]*Instance, 0, len(entries))
+	for _, e := range entries {
+		host := e.Service.Address
+		if host == "" {
+			host = e.Node.Address
+		}
+		list = append(list, &Instance{
+			ID:       e.Service.ID,
+			Name:     e.Service.Service,
+			Addr:     net.JoinHostPort(host, strconv.Itoa(e.Service.Port)),
+			Metadata: e.Service.Meta,
+		})
+	}
+	return sortInstances(list), meta.LastIndex, nil
+}
+
+// Watch 使用阻塞查询监听服务健康实例变化
+func (r *ConsulRegistry) Watch(ctx context.Context, name string) (<-chan []*Instance, error) {
+	list, index, err := r.query(ctx, name, 0)
+	if err != nil {
+		return nil, err
+	}
+	out := make(chan []*Instance, 1)
+	go func() {
+		defer close(out)
+		if !send(ctx, out, list) {
+			return
+		}
+		for {
+			next, nextIndex, err := r.query(ctx, name, index)
+			if err != nil {
+				select {
+				case <-ctx.Done():
+					return
+				case <-time.After(time.Second):
+				}
+				continue
+			}
+			// 索引回退说明 agent 状态被重置，需从头开始阻塞查询
+			if nextIndex < index {
+				nextIndex = 0
+			}
+			changed := nextIndex != index
+			index = nextIndex
+			if changed && !send(ctx, out, next) {
+				return
+			}
+		}
+	}()
+	return out, nil
+}
+
+// Close 注销全部实例
+func (r *ConsulRegistry) Close() error {
+	r.mu.Lock()
+	regs := r.regs
+	r.regs = make(map[string]context.CancelFunc)
+	r.mu.Unlock()
+	var firstErr error
+	for id, cancel := range regs {
+		cancel()
+		if err := r.client.Agent().ServiceDeregister(id); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
@@ -0,0 +1,142 @@
+// Package discovery 提供服务注册与发现，支持 etcd、Consul 和静态配置三种后端。
+package discovery
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// 后端类型
+const (
+	BackendEtcd   = "etcd"
+	BackendConsul = "consul"
+	BackendStatic = "static"
+)
+
+// Config 配置结构
+type Config struct {
+	Backend     string              // 后端 etcd/consul/static
+	Endpoints   []string            // etcd 地址列表或 Consul agent 地址
+	Username    string              // etcd 用户名
+	Password    string              // etcd 密码
+	Token       string              // Consul ACL token
+	Prefix      string              // etcd 键前缀，默认 /services
+	TTL         time.Duration       // 注册租约/心跳TTL，默认 10s
+	DialTimeout time.Duration       // 连接超时时间，默认 5s
+	Static      map[string][]string // 静态后端的服务名到地址列表的映射
+}
+
+// Instance 服务实例
+type Instance struct {
+	ID       string            `json:"id"`   // 实例ID，为空时使用 名称-地址
+	Name     string            `json:"name"` // 服务名
+	Addr     string            `json:"addr"` // host:port
+	Metadata map[string]string `json:"metadata,omitempty"`
+}
+
+// Registry 服务注册发现接口
+type Registry interface {
+	// Register 注册实例并维持心跳，直到 Deregister 或 Close
+	Register(ctx context.Context, ins *Instance) error
+	// Deregister 注销实例
+	Deregister(ctx context.Context, ins *Instance) error
+	// Resolve 获取服务的健康实例列表
+	Resolve(ctx context.Context, name string) ([]*Instance, error)
+	// Watch 监听服务实例变化，每次变化推送完整列表，ctx 取消后关闭通道
+	Watch(ctx context.Context, name string) (<-chan []*Instance, error)
+	// Close 注销本客户端注册的全部实例并关闭连接
+	Close() error
+}
+
+// New 根据配置创建注册中心客户端
+func New(config Config) (Registry, error) {
+	if config.TTL <= 0 {
+		config.TTL = 10 * time.Second
+	}
+	if config.DialTimeout <= 0 {
+		config.DialTimeout = 5 * time.Second
+	}
+	switch strings.ToLower(config.Backend) {
+	case BackendEtcd:
+		return NewEtcd(config)
+	case BackendConsul:
+		return NewConsul(config)
+	case "", BackendStatic:
+		return NewStatic(config), nil
+	}
+	return nil, fmt.Errorf("unsupported discovery backend %s", config.Backend)
+}
+
+// normalize 补全实例ID并校验
+func (ins *Instance) normalize() error {
+	if ins.Name == "" || ins.Addr == "" {
+		return fmt.Errorf("discovery: instance name and addr must be provided")
+	}
+	if ins.ID == "" {
+		ins.ID = ins.Name + "-" + ins.Addr
+	}
+	return nil
+}
+
+// sortInstances 按ID排序，保证列表稳定
+func sortInstances(list []*Instance) []*Instance {
+	sort.Slice(list, func(i, j int) bool { return list[i].ID < list[j].ID })
+	return list
+}
+
+// Resolver 持续监听服务并缓存实例列表，按轮询方式选取实例
+type Resolver struct {
+	name      string
+	instances atomic.Pointer[[]*Instance]
+	next      atomic.Uint64
+	ready     chan struct{}
+	once      sync.Once
+}
+
+// NewResolver 创建并启动服务解析器，ctx 取消后停止监听
+func NewResolver(ctx context.Context, reg Registry, name string) (*Resolver, error) {
+	ch, err := reg.Watch(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	r := &Resolver{name: name, ready: make(chan struct{})}
+	empty := []*Instance{}
+	r.instances.Store(&empty)
+	go func() {
+		for list := range ch {
+			r.instances.Store(&list)
+			r.once.Do(func() { close(r.ready) })
+		}
+	}()
+	return r, nil
+}
+
+// Wait 等待首次获取到实例列表
+func (r *Resolver) Wait(ctx context.Context) error {
+	select {
+	case <-r.ready:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Instances 返回当前缓存的实例列表
+func (r *Resolver) Instances() []*Instance {
+	return *r.instances.Load()
+}
+
+// Next 轮询选取下一个实例
+func (r *Resolver) Next() (*Instance, error) {
+	list := r.Instances()
+	if len(list) == 0 {
+		return nil, fmt.Errorf("discovery: no available instance for %s", r.name)
+	}
+	n := r.next.Add(1) - 1
+	return list[n%uint64(len(list))], nil
+}
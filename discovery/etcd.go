@@ -0,0 +1,293 @@
+package discovery
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	clientv3 "go.etcd.io/etcd/client/v3"
+)
+
+// etcdRegistration 已注册实例的租约信息
+type etcdRegistration struct {
+	cancel context.CancelFunc
+	lease  clientv3.LeaseID
+	key    string
+}
+
+// EtcdRegistry 基于 etcd 租约的注册中心，实例写入 前缀/服务名/实例ID
+type EtcdRegistry struct {
+	client *clientv3.Client
+	prefix string
+	ttl    time.Duration
+
+	mu   sync.Mutex
+	regs map[string]*etcdRegistration
+}
+
+// NewEtcd 创建 etcd 注册中心客户端
+func NewEtcd(config Config) (*EtcdRegistry, error) {
+	if len(config.Endpoints) == 0 {
+		return nil, fmt.Errorf("etcd endpoints must be provided")
+	}
+	if config.TTL <= 0 {
+		config.TTL = 10 * time.Second
+	}
+	if config.DialTimeout <= 0 {
+		config.DialTimeout = 5 * time.Second
+	}
+	prefix := strings.TrimSuffix(config.Prefix, "/")
+	if prefix == "" {
+		prefix = "/services"
+	}
+	client, err := clientv3.New(clientv3.Config{
+		Endpoints:   config.Endpoints,
+		Username:    config.Username,
+		Password:    config.Password,
+		DialTimeout: config.DialTimeout,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to etcd: %w", err)
+	}
+	return &EtcdRegistry{
+		client: client,
+		prefix: prefix,
+		ttl:    config.TTL,
+		regs:   make(map[string]*etcdRegistration),
+	}, nil
+}
+
+// Client 返回底层 etcd 客户端
+func (r *EtcdRegistry) Client() *clientv3.Client {
+	return r.client
+}
+
+func (r *EtcdRegistry) serviceKey(name string) string {
+	return r.prefix + "/" + name + "/"
+}
+
+// Register 以租约方式写入实例并自动续约，租约丢失后自动重新注册
+func (r *EtcdRegistry) Register(ctx context.Context, ins *Instance) error {
+	if err := ins.normalize(); err != nil {
+		return err
+	}
+	value, err := json.Marshal(ins)
+	if err != nil {
+		return fmt.Errorf("failed to marshal instance: %w", err)
+	}
+	key := r.serviceKey(ins.Name) + ins.ID
+
+	r.mu.Lock()
+	if _, ok := r.regs[ins.ID]; ok {
+		r.mu.Unlock()
+		return fmt.Errorf("instance %s already registered", ins.ID)
+	}
+	kctx, cancel := context.WithCancel(context.Background())
+	reg := &etcdRegistration{cancel: cancel, key: key}
+	r.regs[ins.ID] = reg
+	r.mu.Unlock()
+
+	ch, err := r.grant(ctx, kctx, reg, string(value))
+	if err != nil {
+		r.mu.Lock()
+		delete(r.regs, ins.ID)
+		r.mu.Unlock()
+		cancel()
+		return err
+	}
+	go r.keepAlive(kctx, reg, string(value), ch)
+	return nil
+}
+
+// grant 申请租约、写入实例并开启续约
+func (r *EtcdRegistry) grant(ctx, kctx context.Context, reg *etcdRegistration, value string) (<-chan *clientv3.LeaseKeepAliveResponse, error) {
+	lease, err := r.client.Grant(ctx, int64((r.ttl+time.Second-1)/time.Second))
+	if err != nil {
+		return nil, fmt.Errorf("failed to grant lease: %w", err)
+	}
+	if _, err := r.client.Put(ctx, reg.key, value, clientv3.WithLease(lease.ID)); err != nil {
+		return nil, fmt.Errorf("failed to put %s: %w", reg.key, err)
+	}
+	ch, err := r.client.KeepAlive(kctx, lease.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to keep alive lease: %w", err)
+	}
+	r.mu.Lock()
+	reg.lease = lease.ID
+	r.mu.Unlock()
+	return ch, nil
+}
+
+// keepAlive 消费续约响应，通道关闭说明租约失效，按退避间隔重新注册
+func (r *EtcdRegistry) keepAlive(kctx context.Context, reg *etcdRegistration, value string, ch <-chan *clientv3.LeaseKeepAliveResponse) {
+	backoff := time.Second
+	for {
+		for range ch {
+		}
+		for {
+			if kctx.Err() != nil {
+				return
+			}
+			select {
+			case <-kctx.Done():
+				return
+			case <-time.After(backoff):
+			}
+			ctx, cancel := context.WithTimeout(kctx, r.ttl)
+			next, err := r.grant(ctx, kctx, reg, value)
+			cancel()
+			if err == nil {
+				ch = next
+				backoff = time.Second
+				break
+			}
+			if backoff < r.ttl {
+				backoff *= 2
+			}
+		}
+	}
+}
+
+// Deregister 停止续约并撤销租约，实例键随之删除
+func (r *EtcdRegistry) Deregister(ctx context.Context, ins *Instance) error {
+	if err := ins.normalize(); err != nil {
+		return err
+	}
+	r.mu.Lock()
+	reg, ok := r.regs[ins.ID]
+	delete(r.regs, ins.ID)
+	r.mu.Unlock()
+	if !ok {
+		_, err := r.client.Delete(ctx, r.serviceKey(ins.Name)+ins.ID)
+		return err
+	}
+	return r.revoke(ctx, reg)
+}
+
+func (r *EtcdRegistry) revoke(ctx context.Context, reg *etcdRegistration) error {
+	reg.cancel()
+	r.mu.Lock()
+	lease := reg.lease
+	r.mu.Unlock()
+	if _, err := r.client.Revoke(ctx, lease); err != nil {
+		return fmt.Errorf("failed to revoke lease: %w", err)
+	}
+	return nil
+}
+
+// Resolve 获取服务的全部实例
+func (r *EtcdRegistry) Resolve(ctx context.Context, name string) ([]*Instance, error) {
+	list, _, err := r.list(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	return instanceList(list), nil
+}
+
+// list 读取服务目录，返回实例映射和当前版本号
+func (r *EtcdRegistry) list(ctx context.Context, name string) (map[string]*Instance, int64, error) {
+	resp, err := r.client.Get(ctx, r.serviceKey(name), clientv3.WithPrefix())
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list %s: %w", name, err)
+	}
+	state := make(map[string]*Instance, len(resp.Kvs))
+	for _, kv := range resp.Kvs {
+		var ins Instance
+		if err := json.Unmarshal(kv.Value, &ins); err != nil {
+			continue
+		}
+		state[string(kv.Key)] = &ins
+	}
+	return state, resp.Header.Revision, nil
+}
+
+// Watch 监听服务目录，版本被压缩或连接中断时重新全量拉取
+func (r *EtcdRegistry) Watch(ctx context.Context, name string) (<-chan []*Instance, error) {
+	state, rev, err := r.list(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	out := make(chan []*Instance, 1)
+	go func() {
+		defer close(out)
+		for {
+			if !send(ctx, out, instanceList(state)) {
+				return
+			}
+			wch := r.client.Watch(clientv3.WithRequireLeader(ctx), r.serviceKey(name), clientv3.WithPrefix(), clientv3.WithRev(rev+1))
+			for wr := range wch {
+				if wr.Err() != nil {
+					break
+				}
+				for _, ev := range wr.Events {
+					key := string(ev.Kv.Key)
+					if ev.Type == clientv3.EventTypeDelete {
+						delete(state, key)
+						continue
+					}
+					var ins Instance
+					if err := json.Unmarshal(ev.Kv.Value, &ins); err == nil {
+						state[key] = &ins
+					}
+				}
+				if len(wr.Events) > 0 && !send(ctx, out, instanceList(state)) {
+					return
+				}
+			}
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case <-time.After(time.Second):
+				}
+				if state, rev, err = r.list(ctx, name); err == nil {
+					break
+				}
+			}
+		}
+	}()
+	return out, nil
+}
+
+// Close 注销全部实例并关闭连接
+func (r *EtcdRegistry) Close() error {
+	r.mu.Lock()
+	regs := r.regs
+	r.regs = make(map[string]*etcdRegistration)
+	r.mu.Unlock()
+	ctx, cancel := context.WithTimeout(context.Background(), r.ttl)
+	defer cancel()
+	for _, reg := range regs {
+		_ = r.revoke(ctx, reg)
+	}
+	return r.client.Close()
+}
+
+// instanceList 将实例映射转为有序列表
+func instanceList(state map[string]*Instance) []*Instance {
+	list := make([]*Instance, 0, len(state))
+	for _, ins := range state {
+		list = append(list, ins)
+	}
+	return sortInstances(list)
+}
+
+// send 推送实例列表，通道已满时丢弃旧值只保留最新列表
+func send(ctx context.Context, out chan []*Instance, list []*Instance) bool {
+	for {
+		select {
+		case out <- list:
+			return true
+		case <-ctx.Done():
+			return false
+		default:
+		}
+		select {
+		case <-out:
+		default:
+		}
+	}
+}
@@ -0,0 +1,125 @@
+package discovery
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// StaticRegistry 基于静态配置的注册中心，Register 仅在进程内生效，适用于单机部署或测试
+type StaticRegistry struct {
+	mu       sync.Mutex
+	services map[string]map[string]*Instance
+	watchers map[string][]chan struct{}
+}
+
+// NewStatic 根据 Config.Static 创建静态注册中心
+func NewStatic(config Config) *StaticRegistry {
+	r := &StaticRegistry{
+		services: make(map[string]map[string]*Instance),
+		watchers: make(map[string][]chan struct{}),
+	}
+	for name, addrs := range config.Static {
+		for _, addr := range addrs {
+			ins := &Instance{Name: name, Addr: addr}
+			_ = ins.normalize()
+			r.add(ins)
+		}
+	}
+	return r
+}
+
+func (r *StaticRegistry) add(ins *Instance) {
+	if r.services[ins.Name] == nil {
+		r.services[ins.Name] = make(map[string]*Instance)
+	}
+	r.services[ins.Name][ins.ID] = ins
+}
+
+// notify 通知服务的全部监听者，调用时需持有锁
+func (r *StaticRegistry) notify(name string) {
+	for _, ch := range r.watchers[name] {
+		select {
+		case ch <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// Register 在进程内登记实例
+func (r *StaticRegistry) Register(ctx context.Context, ins *Instance) error {
+	if err := ins.normalize(); err != nil {
+		return err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.add(ins)
+	r.notify(ins.Name)
+	return nil
+}
+
+// Deregister 移除实例
+func (r *StaticRegistry) Deregister(ctx context.Context, ins *Instance) error {
+	if err := ins.normalize(); err != nil {
+		return err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.services[ins.Name][ins.ID]; !ok {
+		return fmt.Errorf("instance %s not registered", ins.ID)
+	}
+	delete(r.services[ins.Name], ins.ID)
+	r.notify(ins.Name)
+	return nil
+}
+
+// Resolve 返回服务的实例列表
+func (r *StaticRegistry) Resolve(ctx context.Context, name string) ([]*Instance, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return instanceList(r.services[name]), nil
+}
+
+// Watch 推送当前实例列表，之后在进程内 Register/Deregister 时推送更新
+func (r *StaticRegistry) Watch(ctx context.Context, name string) (<-chan []*Instance, error) {
+	changed := make(chan struct{}, 1)
+	changed <- struct{}{}
+	r.mu.Lock()
+	r.watchers[name] = append(r.watchers[name], changed)
+	r.mu.Unlock()
+
+	out := make(chan []*Instance, 1)
+	go func() {
+		defer close(out)
+		defer r.unwatch(name, changed)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-changed:
+			}
+			list, _ := r.Resolve(ctx, name)
+			if !send(ctx, out, list) {
+				return
+			}
+		}
+	}()
+	return out, nil
+}
+
+func (r *StaticRegistry) unwatch(name string, ch chan struct{}) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	list := r.watchers[name]
+	for i, c := range list {
+		if c == ch {
+			r.watchers[name] = append(list[:i], list[i+1:]...)
+			break
+		}
+	}
+}
+
+// Close 静态注册中心无需释放资源
+func (r *StaticRegistry) Close() error {
+	return nil
+}
@@ -0,0 +1,35 @@
+package encrypt
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+)
+
+// GenerateSignKey 生成 ed25519 签名密钥对，返回base64编码的公钥和私钥
+func GenerateSignKey() (publicKey, privateKey string, err error) {
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return "", "", err
+	}
+	return base64.StdEncoding.EncodeToString(pub), base64.StdEncoding.EncodeToString(priv), nil
+}
+
+// Sign 使用base64编码的 ed25519 私钥签名，返回签名
+func Sign(privateKey string, data []byte) ([]byte, error) {
+	key, err := base64.StdEncoding.DecodeString(privateKey)
+	if err != nil || len(key) != ed25519.PrivateKeySize {
+		return nil, errors.New("invalid ed25519 private key")
+	}
+	return ed25519.Sign(ed25519.PrivateKey(key), data), nil
+}
+
+// Verify 使用base64编码的 ed25519 公钥验证签名
+func Verify(publicKey string, data, sig []byte) bool {
+	key, err := base64.StdEncoding.DecodeString(publicKey)
+	if err != nil || len(key) != ed25519.PublicKeySize {
+		return false
+	}
+	return ed25519.Verify(ed25519.PublicKey(key), data, sig)
+}
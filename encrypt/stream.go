@@ -0,0 +1,191 @@
+package encrypt
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/pbkdf2"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/ixxmi/tools/utils/randx"
+)
+
+// 流式加密格式：magic(6) + salt(16) + 若干分块，每块为 4 字节长度（最高位标记最后一块）+ AES-256-GCM 密文；
+// 密钥由口令经 PBKDF2-SHA256 派生，nonce 为分块序号，最后一块的标记参与认证以防截断
+const (
+	streamMagic     = "ITENC1"
+	streamSaltSize  = 16
+	streamChunkSize = 64 * 1024
+	streamIter      = 100000
+	streamLastFlag  = 1 << 31
+)
+
+// ErrDecrypt 口令错误或数据被篡改、截断
+var ErrDecrypt = errors.New("encrypt: message authentication failed")
+
+// NewEncryptWriter 返回加密写入器，写入的数据加密后写到w，必须调用 Close 写出最后一块
+func NewEncryptWriter(w io.Writer, passphrase string) (io.WriteCloser, error) {
+	salt := randx.Bytes(streamSaltSize)
+	aead, err := streamCipher(passphrase, salt)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := w.Write(append([]byte(streamMagic), salt...)); err != nil {
+		return nil, err
+	}
+	return &encryptWriter{w: w, aead: aead, buf: make([]byte, 0, streamChunkSize)}, nil
+}
+
+// NewDecryptReader 返回解密读取器，数据被篡改或截断时读取返回 ErrDecrypt
+func NewDecryptReader(r io.Reader, passphrase string) (io.Reader, error) {
+	header := make([]byte, len(streamMagic)+streamSaltSize)
+	if _, err := io.ReadFull(r, header); err != nil {
+		return nil, fmt.Errorf("read header: %w", err)
+	}
+	if string(header[:len(streamMagic)]) != streamMagic {
+		return nil, errors.New("encrypt: not an encrypted stream")
+	}
+	aead, err := streamCipher(passphrase, header[len(streamMagic):])
+	if err != nil {
+		return nil, err
+	}
+	return &decryptReader{r: r, aead: aead}, nil
+}
+
+// IsEncryptedStream 判断数据开头是否为加密流格式
+func IsEncryptedStream(head []byte) bool {
+	return bytes.HasPrefix(head, []byte(streamMagic))
+}
+
+func streamCipher(passphrase string, salt []byte) (cipher.AEAD, error) {
+	if passphrase == "" {
+		return nil, errors.New("encrypt: empty passphrase")
+	}
+	key, err := pbkdf2.Key(sha256.New, passphrase, salt, streamIter, 32)
+	if err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+func streamNonce(aead cipher.AEAD, seq uint64, last bool) []byte {
+	nonce := make([]byte, aead.NonceSize())
+	binary.BigEndian.PutUint64(nonce[len(nonce)-8:], seq)
+	if last {
+		nonce[0] = 1
+	}
+	return nonce
+}
+
+type encryptWriter struct {
+	w      io.Writer
+	aead   cipher.AEAD
+	buf    []byte
+	seq    uint64
+	closed bool
+}
+
+func (e *encryptWriter) Write(p []byte) (int, error) {
+	if e.closed {
+		return 0, errors.New("encrypt: write after close")
+	}
+	n := 0
+	for len(p) > 0 {
+		// 缓冲区满且仍有数据时才写出，保证最后一块在 Close 时写出
+		if len(e.buf) == streamChunkSize {
+			if err := e.flush(false); err != nil {
+				return n, err
+			}
+		}
+		c := copy(e.buf[len(e.buf):streamChunkSize], p)
+		e.buf = e.buf[:len(e.buf)+c]
+		p = p[c:]
+		n += c
+	}
+	return n, nil
+}
+
+func (e *encryptWriter) flush(last bool) error {
+	sealed := e.aead.Seal(nil, streamNonce(e.aead, e.seq, last), e.buf, nil)
+	e.seq++
+	e.buf = e.buf[:0]
+	length := uint32(len(sealed))
+	if last {
+		length |= streamLastFlag
+	}
+	var hdr [4]byte
+	binary.BigEndian.PutUint32(hdr[:], length)
+	if _, err := e.w.Write(hdr[:]); err != nil {
+		return err
+	}
+	_, err := e.w.Write(sealed)
+	return err
+}
+
+// Close 写出最后一块，不关闭底层写入器
+func (e *encryptWriter) Close() error {
+	if e.closed {
+		return nil
+	}
+	e.closed = true
+	return e.flush(true)
+}
+
+type decryptReader struct {
+	r    io.Reader
+	aead cipher.AEAD
+	buf  []byte
+	seq  uint64
+	done bool
+}
+
+func (d *decryptReader) Read(p []byte) (int, error) {
+	for len(d.buf) == 0 {
+		if d.done {
+			return 0, io.EOF
+		}
+		if err := d.next(); err != nil {
+			return 0, err
+		}
+	}
+	n := copy(p, d.buf)
+	d.buf = d.buf[n:]
+	return n, nil
+}
+
+func (d *decryptReader) next() error {
+	var hdr [4]byte
+	if _, err := io.ReadFull(d.r, hdr[:]); err != nil {
+		if err == io.EOF {
+			// 未读到最后一块即结束，数据被截断
+			return ErrDecrypt
+		}
+		return err
+	}
+	length := binary.BigEndian.Uint32(hdr[:])
+	last := length&streamLastFlag != 0
+	length &^= streamLastFlag
+	if length > streamChunkSize+uint32(d.aead.Overhead()) {
+		return ErrDecrypt
+	}
+	sealed := make([]byte, length)
+	if _, err := io.ReadFull(d.r, sealed); err != nil {
+		return ErrDecrypt
+	}
+	plain, err := d.aead.Open(sealed[:0], streamNonce(d.aead, d.seq, last), sealed, nil)
+	if err != nil {
+		return ErrDecrypt
+	}
+	d.seq++
+	d.buf = plain
+	d.done = last
+	return nil
+}
@@ -0,0 +1,336 @@
+// Package fsm 提供有限状态机：定义状态、事件、带守卫条件的转换和进入/退出回调，状态可持久化到 Redis 或 MySQL，
+// 用于告警生命周期（open → acknowledged → resolved）、设备开通流程等场景。
+package fsm
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+)
+
+var (
+	// ErrInvalidTransition 当前状态不能响应该事件
+	ErrInvalidTransition = errors.New("fsm: invalid transition")
+	// ErrRejected 守卫条件或动作拒绝了转换
+	ErrRejected = errors.New("fsm: transition rejected")
+	// ErrConflict 并发修改，状态已被其他请求更新
+	ErrConflict = errors.New("fsm: version conflict")
+	// ErrNotFound 实例不存在
+	ErrNotFound = errors.New("fsm: instance not found")
+)
+
+// Callback 回调函数，守卫和动作返回错误时拒绝转换
+type Callback func(ctx context.Context, e *Event) error
+
+// Event 一次转换的上下文
+type Event struct {
+	Machine string
+	ID      string // 实例ID，如告警ID、设备序列号
+	Name    string // 事件名称
+	From    string
+	To      string
+	Data    interface{} // 触发事件时传入的业务数据
+	Time    time.Time
+}
+
+// stateDef 状态定义
+type stateDef struct {
+	onEnter []Callback
+	onExit  []Callback
+	final   bool
+}
+
+// transition 转换定义
+type transition struct {
+	to     string
+	guards []Callback
+	action []Callback
+}
+
+// StateOption 状态选项
+type StateOption func(*stateDef)
+
+// OnEnter 进入状态后回调，在状态保存之后执行，返回的错误不会回滚状态
+func OnEnter(fn Callback) StateOption {
+	return func(s *stateDef) {
+		s.onEnter = append(s.onEnter, fn)
+	}
+}
+
+// OnExit 离开状态前回调，在状态保存之前执行，返回错误时拒绝转换
+func OnExit(fn Callback) StateOption {
+	return func(s *stateDef) {
+		s.onExit = append(s.onExit, fn)
+	}
+}
+
+// Final 终态，不能再响应任何事件
+func Final() StateOption {
+	return func(s *stateDef) {
+		s.final = true
+	}
+}
+
+// TransitionOption 转换选项
+type TransitionOption func(*transition)
+
+// Guard 守卫条件，返回错误时拒绝转换
+func Guard(fn Callback) TransitionOption {
+	return func(t *transition) {
+		t.guards = append(t.guards, fn)
+	}
+}
+
+// Action 转换动作，在守卫和退出回调之后、状态保存之前执行，返回错误时拒绝转换
+func Action(fn Callback) TransitionOption {
+	return func(t *transition) {
+		t.action = append(t.action, fn)
+	}
+}
+
+// Definition 状态机定义，定义完成后可被多个 Machine 并发使用
+type Definition struct {
+	name        string
+	initial     string
+	states      map[string]*stateDef
+	transitions map[string]map[string]*transition // event -> from -> transition
+	after       []Callback
+}
+
+// NewDefinition 创建状态机定义
+func NewDefinition(name, initial string) *Definition {
+	d := &Definition{
+		name:        name,
+		initial:     initial,
+		states:      make(map[string]*stateDef),
+		transitions: make(map[string]map[string]*transition),
+	}
+	d.State(initial)
+	return d
+}
+
+// State 定义状态，可多次调用追加选项
+func (d *Definition) State(name string, opts ...StateOption) *Definition {
+	s, ok := d.states[name]
+	if !ok {
+		s = &stateDef{}
+		d.states[name] = s
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return d
+}
+
+// Transition 定义事件在 from 中任一状态下转换到 to，未定义的状态自动添加
+func (d *Definition) Transition(event string, from []string, to string, opts ...TransitionOption) *Definition {
+	t := &transition{to: to}
+	for _, opt := range opts {
+		opt(t)
+	}
+	if d.transitions[event] == nil {
+		d.transitions[event] = make(map[string]*transition)
+	}
+	for _, f := range from {
+		d.State(f)
+		d.transitions[event][f] = t
+	}
+	d.State(to)
+	return d
+}
+
+// AfterTransition 任意转换完成后的回调，在进入回调之后执行，可用于审计和通知
+func (d *Definition) AfterTransition(fn Callback) *Definition {
+	d.after = append(d.after, fn)
+	return d
+}
+
+// Name 状态机名称
+func (d *Definition) Name() string {
+	return d.name
+}
+
+// Initial 初始状态
+func (d *Definition) Initial() string {
+	return d.initial
+}
+
+// Validate 检查定义：终态不能有出向转换，除初始状态外的状态必须可达
+func (d *Definition) Validate() error {
+	reachable := map[string]bool{d.initial: true}
+	for event, froms := range d.transitions {
+		for from, t := range froms {
+			if d.states[from].final {
+				return fmt.Errorf("fsm %s: final state %s has transition %s", d.name, from, event)
+			}
+			reachable[t.to] = true
+		}
+	}
+	for name := range d.states {
+		if !reachable[name] {
+			return fmt.Errorf("fsm %s: state %s is unreachable", d.name, name)
+		}
+	}
+	return nil
+}
+
+// Can 判断状态能否响应事件（不检查守卫条件）
+func (d *Definition) Can(state, event string) bool {
+	_, ok := d.transitions[event][state]
+	return ok
+}
+
+// Events 返回状态可响应的事件，按名称排序
+func (d *Definition) Events(state string) []string {
+	var events []string
+	for event, froms := range d.transitions {
+		if _, ok := froms[state]; ok {
+			events = append(events, event)
+		}
+	}
+	sort.Strings(events)
+	return events
+}
+
+// IsFinal 判断是否为终态
+func (d *Definition) IsFinal(state string) bool {
+	s, ok := d.states[state]
+	return ok && s.final
+}
+
+// Record 持久化的实例状态
+type Record struct {
+	Machine   string    `json:"machine"`
+	ID        string    `json:"id"`
+	State     string    `json:"state"`
+	Version   int64     `json:"version"` // 每次转换加1，用于乐观锁
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// HistoryEntry 转换历史
+type HistoryEntry struct {
+	Machine string    `json:"machine"`
+	ID      string    `json:"id"`
+	Event   string    `json:"event"`
+	From    string    `json:"from"`
+	To      string    `json:"to"`
+	Data    string    `json:"data"` // 业务数据的JSON
+	Time    time.Time `json:"time"`
+}
+
+// Store 状态存储
+type Store interface {
+	// Load 读取实例状态，不存在时返回 ErrNotFound
+	Load(ctx context.Context, machine, id string) (*Record, error)
+	// Save 保存新状态并追加历史，存储中的版本必须为 rec.Version-1（新实例为不存在），否则返回 ErrConflict
+	Save(ctx context.Context, rec *Record, history *HistoryEntry) error
+	// History 按时间倒序返回最近的转换历史
+	History(ctx context.Context, machine, id string, limit int) ([]HistoryEntry, error)
+}
+
+// Machine 绑定存储的状态机
+type Machine struct {
+	def   *Definition
+	store Store
+}
+
+// NewMachine 创建状态机，store 为nil时使用内存存储
+func NewMachine(def *Definition, store Store) *Machine {
+	if store == nil {
+		store = NewMemoryStore()
+	}
+	return &Machine{def: def, store: store}
+}
+
+// Definition 返回状态机定义
+func (m *Machine) Definition() *Definition {
+	return m.def
+}
+
+// Current 返回实例当前状态，实例不存在时返回初始状态
+func (m *Machine) Current(ctx context.Context, id string) (string, error) {
+	rec, err := m.load(ctx, id)
+	if err != nil {
+		return "", err
+	}
+	return rec.State, nil
+}
+
+func (m *Machine) load(ctx context.Context, id string) (*Record, error) {
+	rec, err := m.store.Load(ctx, m.def.name, id)
+	if errors.Is(err, ErrNotFound) {
+		return &Record{Machine: m.def.name, ID: id, State: m.def.initial}, nil
+	}
+	return rec, err
+}
+
+// Fire 触发事件。执行顺序：守卫 → 退出回调 → 动作 → 保存 → 进入回调 → 转换后回调；
+// 保存之前的回调返回错误时状态不变，之后的回调错误会返回但状态已更新
+func (m *Machine) Fire(ctx context.Context, id, event string, data interface{}) (*Event, error) {
+	rec, err := m.load(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	t, ok := m.def.transitions[event][rec.State]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s cannot handle %s in state %s", ErrInvalidTransition, m.def.name, event, rec.State)
+	}
+	e := &Event{Machine: m.def.name, ID: id, Name: event, From: rec.State, To: t.to, Data: data, Time: time.Now()}
+	for _, fn := range t.guards {
+		if err := fn(ctx, e); err != nil {
+			return e, fmt.Errorf("%w: %v", ErrRejected, err)
+		}
+	}
+	for _, fn := range m.def.states[e.From].onExit {
+		if err := fn(ctx, e); err != nil {
+			return e, fmt.Errorf("%w: %v", ErrRejected, err)
+		}
+	}
+	for _, fn := range t.action {
+		if err := fn(ctx, e); err != nil {
+			return e, fmt.Errorf("%w: %v", ErrRejected, err)
+		}
+	}
+
+	history := &HistoryEntry{Machine: e.Machine, ID: id, Event: event, From: e.From, To: e.To, Time: e.Time}
+	if data != nil {
+		b, err := json.Marshal(data)
+		if err != nil {
+			return e, fmt.Errorf("fsm: encode event data: %w", err)
+		}
+		history.Data = string(b)
+	}
+	next := &Record{Machine: e.Machine, ID: id, State: e.To, Version: rec.Version + 1, UpdatedAt: e.Time}
+	if err := m.store.Save(ctx, next, history); err != nil {
+		return e, err
+	}
+
+	for _, fn := range m.def.states[e.To].onEnter {
+		if err := fn(ctx, e); err != nil {
+			return e, err
+		}
+	}
+	for _, fn := range m.def.after {
+		if err := fn(ctx, e); err != nil {
+			return e, err
+		}
+	}
+	return e, nil
+}
+
+// Can 判断实例当前状态能否响应事件（不检查守卫条件）
+func (m *Machine) Can(ctx context.Context, id, event string) (bool, error) {
+	state, err := m.Current(ctx, id)
+	if err != nil {
+		return false, err
+	}
+	return m.def.Can(state, event), nil
+}
+
+// History 返回实例最近的转换历史
+func (m *Machine) History(ctx context.Context, id string, limit int) ([]HistoryEntry, error) {
+	return m.store.History(ctx, m.def.name, id, limit)
+}
@@ -0,0 +1,121 @@
+package fsm
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strconv"
+
+	mysqldriver "github.com/go-sql-driver/mysql"
+	"gorm.io/gorm"
+)
+
+// MySQLStore MySQL 存储
+type MySQLStore struct {
+	db           *gorm.DB
+	stateTable   string
+	historyTable string
+}
+
+// NewMySQLStore 创建 MySQL 存储，表名前缀默认 fsm，状态表和历史表不存在时自动创建
+func NewMySQLStore(db *gorm.DB, prefix string) (*MySQLStore, error) {
+	if prefix == "" {
+		prefix = "fsm"
+	}
+	s := &MySQLStore{db: db, stateTable: prefix + "_state", historyTable: prefix + "_history"}
+	err := db.Exec("CREATE TABLE IF NOT EXISTS `" + s.stateTable + "` (" +
+		"`machine` VARCHAR(64) NOT NULL," +
+		"`instance_id` VARCHAR(128) NOT NULL," +
+		"`state` VARCHAR(64) NOT NULL," +
+		"`version` BIGINT NOT NULL DEFAULT 0," +
+		"`updated_at` DATETIME(3) NOT NULL," +
+		"PRIMARY KEY (`machine`, `instance_id`)" +
+		") ENGINE=InnoDB").Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to create state table: %w", err)
+	}
+	err = db.Exec("CREATE TABLE IF NOT EXISTS `" + s.historyTable + "` (" +
+		"`id` BIGINT NOT NULL AUTO_INCREMENT PRIMARY KEY," +
+		"`machine` VARCHAR(64) NOT NULL," +
+		"`instance_id` VARCHAR(128) NOT NULL," +
+		"`event` VARCHAR(64) NOT NULL," +
+		"`from_state` VARCHAR(64) NOT NULL," +
+		"`to_state` VARCHAR(64) NOT NULL," +
+		"`data` TEXT," +
+		"`created_at` DATETIME(3) NOT NULL," +
+		"KEY `idx_instance` (`machine`, `instance_id`, `id`)" +
+		") ENGINE=InnoDB").Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to create history table: %w", err)
+	}
+	return s, nil
+}
+
+// Load 读取实例状态
+func (s *MySQLStore) Load(ctx context.Context, machine, id string) (*Record, error) {
+	rec := &Record{Machine: machine, ID: id}
+	row := s.db.WithContext(ctx).Raw("SELECT state, version, updated_at FROM `"+s.stateTable+
+		"` WHERE machine = ? AND instance_id = ?", machine, id).Row()
+	if err := row.Scan(&rec.State, &rec.Version, &rec.UpdatedAt); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	return rec, nil
+}
+
+// Save 在事务内按版本更新状态并写入历史
+func (s *MySQLStore) Save(ctx context.Context, rec *Record, history *HistoryEntry) error {
+	return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if rec.Version == 1 {
+			err := tx.Exec("INSERT INTO `"+s.stateTable+"` (machine, instance_id, state, version, updated_at) VALUES (?, ?, ?, ?, ?)",
+				rec.Machine, rec.ID, rec.State, rec.Version, rec.UpdatedAt).Error
+			var me *mysqldriver.MySQLError
+			if errors.As(err, &me) && me.Number == 1062 {
+				return ErrConflict
+			}
+			if err != nil {
+				return err
+			}
+		} else {
+			res := tx.Exec("UPDATE `"+s.stateTable+"` SET state = ?, version = ?, updated_at = ? WHERE machine = ? AND instance_id = ? AND version = ?",
+				rec.State, rec.Version, rec.UpdatedAt, rec.Machine, rec.ID, rec.Version-1)
+			if res.Error != nil {
+				return res.Error
+			}
+			if res.RowsAffected == 0 {
+				return ErrConflict
+			}
+		}
+		if history == nil {
+			return nil
+		}
+		return tx.Exec("INSERT INTO `"+s.historyTable+"` (machine, instance_id, event, from_state, to_state, data, created_at) VALUES (?, ?, ?, ?, ?, ?, ?)",
+			history.Machine, history.ID, history.Event, history.From, history.To, history.Data, history.Time).Error
+	})
+}
+
+// History 按时间倒序返回转换历史
+func (s *MySQLStore) History(ctx context.Context, machine, id string, limit int) ([]HistoryEntry, error) {
+	query := "SELECT event, from_state, to_state, COALESCE(data, ''), created_at FROM `" + s.historyTable +
+		"` WHERE machine = ? AND instance_id = ? ORDER BY id DESC"
+	if limit > 0 {
+		query += " LIMIT " + strconv.Itoa(limit)
+	}
+	rows, err := s.db.WithContext(ctx).Raw(query, machine, id).Rows()
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var result []HistoryEntry
+	for rows.Next() {
+		h := HistoryEntry{Machine: machine, ID: id}
+		if err := rows.Scan(&h.Event, &h.From, &h.To, &h.Data, &h.Time); err != nil {
+			return nil, err
+		}
+		result = append(result, h)
+	}
+	return result, rows.Err()
+}
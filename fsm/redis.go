@@ -0,0 +1,118 @@
+package fsm
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/ixxmi/tools/cache/redis"
+	goredis "github.com/redis/go-redis/v9"
+)
+
+// RedisStore Redis 存储，状态保存为哈希，历史保存为列表
+type RedisStore struct {
+	rc         *redis.RedisClient
+	namespace  string
+	maxHistory int
+}
+
+// RedisStoreConfig Redis 存储配置
+type RedisStoreConfig struct {
+	Namespace  string // 键前缀，默认 fsm
+	MaxHistory int    // 每个实例保留的历史条数，默认 100
+}
+
+// NewRedisStore 创建 Redis 存储
+func NewRedisStore(rc *redis.RedisClient, config RedisStoreConfig) *RedisStore {
+	if config.Namespace == "" {
+		config.Namespace = "fsm"
+	}
+	if config.MaxHistory <= 0 {
+		config.MaxHistory = 100
+	}
+	return &RedisStore{rc: rc, namespace: config.Namespace, maxHistory: config.MaxHistory}
+}
+
+// 状态键和历史键使用同一哈希标签，保证集群模式下落在同一槽位
+func (s *RedisStore) stateKey(machine, id string) string {
+	return fmt.Sprintf("%s:{%s:%s}:state", s.namespace, machine, id)
+}
+
+func (s *RedisStore) historyKey(machine, id string) string {
+	return fmt.Sprintf("%s:{%s:%s}:history", s.namespace, machine, id)
+}
+
+// saveScript 版本匹配时更新状态并追加历史
+var saveScript = goredis.NewScript(`
+local v = redis.call('HGET', KEYS[1], 'version') or '0'
+if v ~= ARGV[1] then
+	return 0
+end
+redis.call('HSET', KEYS[1], 'state', ARGV[2], 'version', ARGV[3], 'updated_at', ARGV[4])
+if ARGV[5] ~= '' then
+	redis.call('LPUSH', KEYS[2], ARGV[5])
+	redis.call('LTRIM', KEYS[2], 0, tonumber(ARGV[6]) - 1)
+end
+return 1
+`)
+
+// Load 读取实例状态
+func (s *RedisStore) Load(ctx context.Context, machine, id string) (*Record, error) {
+	m, err := s.rc.Client().HGetAll(ctx, s.stateKey(machine, id)).Result()
+	if err != nil {
+		return nil, err
+	}
+	if len(m) == 0 {
+		return nil, ErrNotFound
+	}
+	rec := &Record{Machine: machine, ID: id, State: m["state"]}
+	rec.Version, _ = strconv.ParseInt(m["version"], 10, 64)
+	if ms, err := strconv.ParseInt(m["updated_at"], 10, 64); err == nil {
+		rec.UpdatedAt = time.UnixMilli(ms)
+	}
+	return rec, nil
+}
+
+// Save 保存新状态并追加历史
+func (s *RedisStore) Save(ctx context.Context, rec *Record, history *HistoryEntry) error {
+	var entry []byte
+	if history != nil {
+		var err error
+		if entry, err = json.Marshal(history); err != nil {
+			return err
+		}
+	}
+	keys := []string{s.stateKey(rec.Machine, rec.ID), s.historyKey(rec.Machine, rec.ID)}
+	n, err := saveScript.Run(ctx, s.rc.Client(), keys, rec.Version-1, rec.State, rec.Version,
+		rec.UpdatedAt.UnixMilli(), string(entry), s.maxHistory).Int()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return ErrConflict
+	}
+	return nil
+}
+
+// History 按时间倒序返回转换历史
+func (s *RedisStore) History(ctx context.Context, machine, id string, limit int) ([]HistoryEntry, error) {
+	stop := int64(-1)
+	if limit > 0 {
+		stop = int64(limit) - 1
+	}
+	items, err := s.rc.Client().LRange(ctx, s.historyKey(machine, id), 0, stop).Result()
+	if err != nil {
+		return nil, err
+	}
+	result := make([]HistoryEntry, 0, len(items))
+	for _, item := range items {
+		var h HistoryEntry
+		if err := json.Unmarshal([]byte(item), &h); err != nil {
+			continue
+		}
+		result = append(result, h)
+	}
+	return result, nil
+}
@@ -0,0 +1,56 @@
+package fsm
+
+import (
+	"context"
+	"sync"
+)
+
+// MemoryStore 内存存储，用于单进程和测试
+type MemoryStore struct {
+	mu      sync.Mutex
+	records map[string]Record
+	history map[string][]HistoryEntry
+}
+
+// NewMemoryStore 创建内存存储
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{records: make(map[string]Record), history: make(map[string][]HistoryEntry)}
+}
+
+// Load 读取实例状态
+func (s *MemoryStore) Load(ctx context.Context, machine, id string) (*Record, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	rec, ok := s.records[machine+"/"+id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return &rec, nil
+}
+
+// Save 保存新状态并追加历史
+func (s *MemoryStore) Save(ctx context.Context, rec *Record, history *HistoryEntry) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	key := rec.Machine + "/" + rec.ID
+	if s.records[key].Version != rec.Version-1 {
+		return ErrConflict
+	}
+	s.records[key] = *rec
+	if history != nil {
+		s.history[key] = append(s.history[key], *history)
+	}
+	return nil
+}
+
+// History 按时间倒序返回转换历史
+func (s *MemoryStore) History(ctx context.Context, machine, id string, limit int) ([]HistoryEntry, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	all := s.history[machine+"/"+id]
+	var result []HistoryEntry
+	for i := len(all) - 1; i >= 0 && (limit <= 0 || len(result) < limit); i-- {
+		result = append(result, all[i])
+	}
+	return result, nil
+}
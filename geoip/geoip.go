@@ -0,0 +1,201 @@
+// Package geoip 提供IP地理位置和ASN查询，支持本地MMDB文件或自定义数据源、结果缓存和数据库文件热加载。
+package geoip
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Info 查询结果
+type Info struct {
+	IP          string  `json:"ip"`
+	CountryCode string  `json:"country_code"`
+	Country     string  `json:"country"`
+	Province    string  `json:"province"`
+	City        string  `json:"city"`
+	Latitude    float64 `json:"latitude"`
+	Longitude   float64 `json:"longitude"`
+	TimeZone    string  `json:"time_zone"`
+	ASN         uint    `json:"asn"`
+	ASOrg       string  `json:"as_org"`
+	Private     bool    `json:"private"` // 内网、回环等保留地址，不查询数据源
+}
+
+// Provider 数据源接口，可替换为第三方库或在线服务
+type Provider interface {
+	Lookup(ip net.IP) (*Info, error)
+	Close() error
+}
+
+// Config 配置结构
+type Config struct {
+	CityDB         string        // City 或 Country MMDB 文件路径
+	ASNDB          string        // ASN MMDB 文件路径，可选
+	Language       string        // 地名语言，默认 zh-CN
+	CacheSize      int           // 缓存条数，默认 10000，小于0时不缓存
+	CacheTTL       time.Duration // 缓存有效期，默认 1h
+	ReloadInterval time.Duration // 检查文件变化的间隔，默认 1min
+}
+
+// Locator IP查询器，并发安全
+type Locator struct {
+	config   Config
+	provider atomic.Pointer[providerHolder]
+	cache    *lruCache
+	mu       sync.Mutex
+	modTimes map[string]time.Time
+}
+
+// providerHolder 包装接口以便原子替换
+type providerHolder struct {
+	Provider
+}
+
+// New 根据配置打开 MMDB 文件创建查询器
+func New(config Config) (*Locator, error) {
+	applyDefaults(&config)
+	p, err := OpenMMDB(config.CityDB, config.ASNDB, config.Language)
+	if err != nil {
+		return nil, err
+	}
+	l := NewWithProvider(p, config)
+	l.modTimes = l.statFiles()
+	return l, nil
+}
+
+// NewWithProvider 使用自定义数据源创建查询器，此时不支持文件热加载
+func NewWithProvider(p Provider, config Config) *Locator {
+	applyDefaults(&config)
+	l := &Locator{config: config}
+	if config.CacheSize > 0 {
+		l.cache = newLRUCache(config.CacheSize, config.CacheTTL)
+	}
+	l.provider.Store(&providerHolder{p})
+	return l
+}
+
+func applyDefaults(config *Config) {
+	if config.Language == "" {
+		config.Language = "zh-CN"
+	}
+	if config.CacheSize == 0 {
+		config.CacheSize = 10000
+	}
+	if config.CacheTTL <= 0 {
+		config.CacheTTL = time.Hour
+	}
+	if config.ReloadInterval <= 0 {
+		config.ReloadInterval = time.Minute
+	}
+}
+
+// Lookup 查询IP，保留地址直接返回 Private 为 true 的结果
+func (l *Locator) Lookup(ip string) (*Info, error) {
+	parsed := net.ParseIP(ip)
+	if parsed == nil {
+		return nil, fmt.Errorf("geoip: invalid ip %s", ip)
+	}
+	key := parsed.String()
+	if isPrivate(parsed) {
+		return &Info{IP: key, Private: true}, nil
+	}
+	if l.cache != nil {
+		if info, ok := l.cache.get(key); ok {
+			return info, nil
+		}
+	}
+	info, err := l.provider.Load().Lookup(parsed)
+	if err != nil {
+		return nil, fmt.Errorf("geoip: lookup %s: %w", ip, err)
+	}
+	if l.cache != nil {
+		l.cache.set(key, info)
+	}
+	return info, nil
+}
+
+// LookupAll 批量查询，查询失败的IP对应位置为nil
+func (l *Locator) LookupAll(ips []string) []*Info {
+	result := make([]*Info, len(ips))
+	for i, ip := range ips {
+		result[i], _ = l.Lookup(ip)
+	}
+	return result
+}
+
+// isPrivate 判断是否为内网、回环、链路本地等不在公网路由的地址
+func isPrivate(ip net.IP) bool {
+	return ip.IsPrivate() || ip.IsLoopback() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() ||
+		ip.IsMulticast() || ip.IsUnspecified()
+}
+
+// Reload 重新打开 MMDB 文件并替换数据源，成功后清空缓存
+func (l *Locator) Reload() error {
+	p, err := OpenMMDB(l.config.CityDB, l.config.ASNDB, l.config.Language)
+	if err != nil {
+		return err
+	}
+	old := l.provider.Swap(&providerHolder{p})
+	if l.cache != nil {
+		l.cache.purge()
+	}
+	l.mu.Lock()
+	l.modTimes = l.statFiles()
+	l.mu.Unlock()
+	if old != nil {
+		// 等待进行中的查询完成后再关闭旧文件
+		time.AfterFunc(10*time.Second, func() { old.Close() })
+	}
+	return nil
+}
+
+// statFiles 记录数据库文件的修改时间
+func (l *Locator) statFiles() map[string]time.Time {
+	m := make(map[string]time.Time)
+	for _, f := range []string{l.config.CityDB, l.config.ASNDB} {
+		if f == "" {
+			continue
+		}
+		if st, err := os.Stat(f); err == nil {
+			m[f] = st.ModTime()
+		}
+	}
+	return m
+}
+
+// Watch 定时检查数据库文件的修改时间，变化后自动重新加载，直到ctx取消；onError 可为nil
+func (l *Locator) Watch(ctx context.Context, onError func(error)) {
+	ticker := time.NewTicker(l.config.ReloadInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		l.mu.Lock()
+		changed := false
+		for f, t := range l.statFiles() {
+			if !t.Equal(l.modTimes[f]) {
+				changed = true
+			}
+		}
+		l.mu.Unlock()
+		if !changed {
+			continue
+		}
+		if err := l.Reload(); err != nil && onError != nil {
+			onError(err)
+		}
+	}
+}
+
+// Close 关闭数据源
+func (l *Locator) Close() error {
+	return l.provider.Load().Close()
+}
@@ -0,0 +1,102 @@
+package geoip
+
+import (
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/oschwald/geoip2-golang"
+)
+
+// MMDBProvider 基于 MaxMind MMDB 文件的查询，CityDB 可使用 City 或 Country 库，ASNDB 可选
+type MMDBProvider struct {
+	city     *geoip2.Reader
+	asn      *geoip2.Reader
+	isCity   bool // CityDB 为 City 库时返回省份、城市和经纬度
+	language string
+}
+
+// OpenMMDB 打开 MMDB 文件，language 为地名语言（如 zh-CN、en），缺失时回退到英文
+func OpenMMDB(cityDB, asnDB, language string) (*MMDBProvider, error) {
+	if cityDB == "" && asnDB == "" {
+		return nil, fmt.Errorf("geoip: no mmdb file configured")
+	}
+	p := &MMDBProvider{language: language}
+	if cityDB != "" {
+		r, err := geoip2.Open(cityDB)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open %s: %w", cityDB, err)
+		}
+		p.city = r
+		p.isCity = strings.Contains(r.Metadata().DatabaseType, "City")
+	}
+	if asnDB != "" {
+		r, err := geoip2.Open(asnDB)
+		if err != nil {
+			p.Close()
+			return nil, fmt.Errorf("failed to open %s: %w", asnDB, err)
+		}
+		p.asn = r
+	}
+	return p, nil
+}
+
+// Lookup 查询IP的地理位置和ASN
+func (p *MMDBProvider) Lookup(ip net.IP) (*Info, error) {
+	info := &Info{IP: ip.String()}
+	if p.city != nil {
+		if p.isCity {
+			rec, err := p.city.City(ip)
+			if err != nil {
+				return nil, err
+			}
+			info.CountryCode = rec.Country.IsoCode
+			info.Country = p.name(rec.Country.Names)
+			if len(rec.Subdivisions) > 0 {
+				info.Province = p.name(rec.Subdivisions[0].Names)
+			}
+			info.City = p.name(rec.City.Names)
+			info.Latitude = rec.Location.Latitude
+			info.Longitude = rec.Location.Longitude
+			info.TimeZone = rec.Location.TimeZone
+		} else {
+			rec, err := p.city.Country(ip)
+			if err != nil {
+				return nil, err
+			}
+			info.CountryCode = rec.Country.IsoCode
+			info.Country = p.name(rec.Country.Names)
+		}
+	}
+	if p.asn != nil {
+		rec, err := p.asn.ASN(ip)
+		if err != nil {
+			return nil, err
+		}
+		info.ASN = rec.AutonomousSystemNumber
+		info.ASOrg = rec.AutonomousSystemOrganization
+	}
+	return info, nil
+}
+
+// name 按语言取地名
+func (p *MMDBProvider) name(names map[string]string) string {
+	if n, ok := names[p.language]; ok {
+		return n
+	}
+	return names["en"]
+}
+
+// Close 关闭数据库文件
+func (p *MMDBProvider) Close() error {
+	var err error
+	if p.city != nil {
+		err = p.city.Close()
+	}
+	if p.asn != nil {
+		if cerr := p.asn.Close(); err == nil {
+			err = cerr
+		}
+	}
+	return err
+}
@@ -0,0 +1,81 @@
+package health
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net"
+	"net/http"
+)
+
+// Pinger 支持连通性检查的客户端，如 redis.RedisClient、ckgroup.ClickHouseClient
+type Pinger interface {
+	Ping(ctx context.Context) error
+}
+
+// PingCheck 调用 Ping 检查连通性
+func PingCheck(p Pinger) CheckFunc {
+	return p.Ping
+}
+
+// DBCheck 检查 database/sql 连接，如 sqlite 或 gorm 的 DB()
+func DBCheck(db *sql.DB) CheckFunc {
+	return db.PingContext
+}
+
+// HTTPCheck 请求依赖服务的URL，状态码小于400视为健康
+func HTTPCheck(url string) CheckFunc {
+	client := &http.Client{
+		// 不跟随重定向，3xx 视为服务可用
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+	return func(ctx context.Context) error {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+		if err != nil {
+			return err
+		}
+		resp, err := client.Do(req)
+		if err != nil {
+			return err
+		}
+		resp.Body.Close()
+		if resp.StatusCode >= 400 {
+			return fmt.Errorf("%s returned status %d", url, resp.StatusCode)
+		}
+		return nil
+	}
+}
+
+// TCPCheck 检查TCP端口是否可连接
+func TCPCheck(addr string) CheckFunc {
+	return func(ctx context.Context) error {
+		var d net.Dialer
+		conn, err := d.DialContext(ctx, "tcp", addr)
+		if err != nil {
+			return err
+		}
+		return conn.Close()
+	}
+}
+
+// DiskCheck 检查path所在分区的剩余空间，剩余字节数低于minFree或剩余比例低于minFreePercent时失败，为0的条件忽略
+func DiskCheck(path string, minFree uint64, minFreePercent float64) CheckFunc {
+	return func(ctx context.Context) error {
+		free, total, err := diskUsage(path)
+		if err != nil {
+			return err
+		}
+		if minFree > 0 && free < minFree {
+			return fmt.Errorf("%s free space %d bytes below %d", path, free, minFree)
+		}
+		if minFreePercent > 0 && total > 0 {
+			percent := float64(free) / float64(total) * 100
+			if percent < minFreePercent {
+				return fmt.Errorf("%s free space %.1f%% below %.1f%%", path, percent, minFreePercent)
+			}
+		}
+		return nil
+	}
+}
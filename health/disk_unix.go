@@ -0,0 +1,14 @@
+//go:build !windows
+
+package health
+
+import "syscall"
+
+// diskUsage 返回分区可用字节数和总字节数
+func diskUsage(path string) (free, total uint64, err error) {
+	var st syscall.Statfs_t
+	if err := syscall.Statfs(path, &st); err != nil {
+		return 0, 0, err
+	}
+	return st.Bavail * uint64(st.Bsize), st.Blocks * uint64(st.Bsize), nil
+}
@@ -0,0 +1,8 @@
+package health
+
+import "errors"
+
+// diskUsage Windows 暂不支持磁盘空间检查
+func diskUsage(path string) (free, total uint64, err error) {
+	return 0, 0, errors.New("disk check is not supported on windows")
+}
@@ -0,0 +1,235 @@
+// Package health 提供健康检查框架，组件注册命名检查项，按需或定时执行并通过HTTP输出就绪/存活状态。
+package health
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+// 检查状态
+const (
+	StatusUp       = "up"       // 全部检查通过
+	StatusDegraded = "degraded" // 非关键检查失败
+	StatusDown     = "down"     // 关键检查失败
+)
+
+// CheckFunc 检查函数，返回nil表示健康
+type CheckFunc func(ctx context.Context) error
+
+// check 注册的检查项
+type check struct {
+	name     string
+	fn       CheckFunc
+	timeout  time.Duration
+	critical bool
+	liveness bool
+}
+
+// Option 检查项选项
+type Option func(*check)
+
+// WithTimeout 设置检查超时时间，默认 5s
+func WithTimeout(d time.Duration) Option {
+	return func(c *check) {
+		c.timeout = d
+	}
+}
+
+// NonCritical 非关键检查，失败时整体状态为 degraded，不影响就绪
+func NonCritical() Option {
+	return func(c *check) {
+		c.critical = false
+	}
+}
+
+// Liveness 同时作为存活检查，失败时存活探针返回失败（进程应被重启），仅用于检查进程自身状态
+func Liveness() Option {
+	return func(c *check) {
+		c.liveness = true
+	}
+}
+
+// Result 单项检查结果
+type Result struct {
+	Status    string        `json:"status"`
+	Error     string        `json:"error,omitempty"`
+	Critical  bool          `json:"critical"`
+	Duration  time.Duration `json:"duration_ns"`
+	CheckedAt time.Time     `json:"checked_at"`
+}
+
+// Report 汇总结果
+type Report struct {
+	Status string            `json:"status"`
+	Checks map[string]Result `json:"checks"`
+	Time   time.Time         `json:"time"`
+}
+
+// Registry 检查项注册表
+type Registry struct {
+	mu     sync.RWMutex
+	checks map[string]*check
+	last   *Report // 定时检查的最近结果
+}
+
+// Default 默认注册表
+var Default = NewRegistry()
+
+// NewRegistry 创建注册表
+func NewRegistry() *Registry {
+	return &Registry{checks: make(map[string]*check)}
+}
+
+// Register 注册检查项，同名检查项会被替换
+func (r *Registry) Register(name string, fn CheckFunc, opts ...Option) {
+	c := &check{name: name, fn: fn, timeout: 5 * time.Second, critical: true}
+	for _, opt := range opts {
+		opt(c)
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.checks[name] = c
+}
+
+// Unregister 移除检查项
+func (r *Registry) Unregister(name string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.checks, name)
+}
+
+// Names 返回已注册的检查项名称
+func (r *Registry) Names() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	names := make([]string, 0, len(r.checks))
+	for name := range r.checks {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Run 并发执行全部检查项
+func (r *Registry) Run(ctx context.Context) *Report {
+	return r.run(ctx, false)
+}
+
+// RunLiveness 只执行存活检查项
+func (r *Registry) RunLiveness(ctx context.Context) *Report {
+	return r.run(ctx, true)
+}
+
+// run 执行检查并汇总
+func (r *Registry) run(ctx context.Context, livenessOnly bool) *Report {
+	r.mu.RLock()
+	checks := make([]*check, 0, len(r.checks))
+	for _, c := range r.checks {
+		if !livenessOnly || c.liveness {
+			checks = append(checks, c)
+		}
+	}
+	r.mu.RUnlock()
+
+	report := &Report{Status: StatusUp, Checks: make(map[string]Result, len(checks)), Time: time.Now()}
+	var (
+		mu sync.Mutex
+		wg sync.WaitGroup
+	)
+	for _, c := range checks {
+		wg.Add(1)
+		go func(c *check) {
+			defer wg.Done()
+			res := runCheck(ctx, c)
+			mu.Lock()
+			report.Checks[c.name] = res
+			mu.Unlock()
+		}(c)
+	}
+	wg.Wait()
+
+	for _, res := range report.Checks {
+		if res.Status == StatusUp {
+			continue
+		}
+		if res.Critical {
+			report.Status = StatusDown
+		} else if report.Status == StatusUp {
+			report.Status = StatusDegraded
+		}
+	}
+	return report
+}
+
+// runCheck 带超时执行单个检查，捕获panic
+func runCheck(ctx context.Context, c *check) (res Result) {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	start := time.Now()
+	res = Result{Status: StatusUp, Critical: c.critical, CheckedAt: start}
+
+	done := make(chan error, 1)
+	go func() {
+		defer func() {
+			if p := recover(); p != nil {
+				done <- fmt.Errorf("check panic: %v", p)
+			}
+		}()
+		done <- c.fn(ctx)
+	}()
+
+	var err error
+	select {
+	case err = <-done:
+	case <-ctx.Done():
+		err = fmt.Errorf("check timed out after %s", c.timeout)
+	}
+	res.Duration = time.Since(start)
+	if err != nil {
+		res.Status = StatusDown
+		res.Error = err.Error()
+	}
+	return res
+}
+
+// Start 按interval定时执行全部检查并缓存结果，HTTP处理器优先返回缓存结果，直到ctx取消
+func (r *Registry) Start(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		interval = 15 * time.Second
+	}
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			report := r.Run(ctx)
+			r.mu.Lock()
+			r.last = report
+			r.mu.Unlock()
+
+			select {
+			case <-ctx.Done():
+				r.mu.Lock()
+				r.last = nil
+				r.mu.Unlock()
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// Last 返回定时检查的最近结果，未启动定时检查时返回nil
+func (r *Registry) Last() *Report {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.last
+}
+
+// Register 在默认注册表中注册检查项
+func Register(name string, fn CheckFunc, opts ...Option) {
+	Default.Register(name, fn, opts...)
+}
@@ -0,0 +1,50 @@
+package health
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+// ReadyHandler 就绪探针，关键检查全部通过时返回200，否则返回503；已启动定时检查时返回缓存结果
+func (r *Registry) ReadyHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		report := r.Last()
+		if report == nil {
+			report = r.Run(req.Context())
+		}
+		writeReport(w, report)
+	})
+}
+
+// LiveHandler 存活探针，只执行标记为 Liveness 的检查，未注册时始终返回200
+func (r *Registry) LiveHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		writeReport(w, r.RunLiveness(req.Context()))
+	})
+}
+
+// Handler 同时提供 /live 和 /ready 两个路径，挂载时使用 http.StripPrefix，其余路径返回完整检查结果
+func (r *Registry) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.Handle("/live", r.LiveHandler())
+	mux.Handle("/ready", r.ReadyHandler())
+	mux.Handle("/", r.ReadyHandler())
+	return mux
+}
+
+// writeReport 输出JSON结果，down 状态返回503
+func writeReport(w http.ResponseWriter, report *Report) {
+	status := http.StatusOK
+	if report.Status == StatusDown {
+		status = http.StatusServiceUnavailable
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(report)
+}
+
+// Handler 返回默认注册表的HTTP处理器
+func Handler() http.Handler {
+	return Default.Handler()
+}
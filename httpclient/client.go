@@ -0,0 +1,194 @@
+// Package httpclient 提供带重试、超时和JSON编解码的HTTP客户端。
+package httpclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ixxmi/tools/utils"
+)
+
+// Config 配置结构
+type Config struct {
+	BaseURL      string            // 请求路径为相对路径时拼接的前缀
+	Timeout      time.Duration     // 单次请求超时时间，默认 30s
+	MaxRetries   int               // 5xx/429/网络超时时的最大重试次数，默认 2，小于0不重试；只对幂等方法生效，POST/PATCH 需通过 SetRetries 显式开启
+	RetryWait    time.Duration     // 首次重试等待时间，之后按指数增长，默认 200ms
+	RetryMaxWait time.Duration     // 重试等待时间上限，默认 5s
+	Headers      map[string]string // 每个请求默认携带的请求头
+	Transport    http.RoundTripper // 自定义传输层，如链路追踪包装
+}
+
+// BeforeHook 请求发送前回调，返回错误时终止请求
+type BeforeHook func(req *http.Request) error
+
+// AfterHook 每次尝试结束后回调，用于日志和指标
+type AfterHook func(req *http.Request, resp *http.Response, err error, elapsed time.Duration)
+
+// Client HTTP客户端
+type Client struct {
+	config Config
+	client *http.Client
+	before []BeforeHook
+	after  []AfterHook
+}
+
+// Default 默认客户端
+var Default = NewClient(Config{})
+
+// NewClient 创建HTTP客户端
+func NewClient(config Config) *Client {
+	if config.Timeout <= 0 {
+		config.Timeout = 30 * time.Second
+	}
+	if config.MaxRetries == 0 {
+		config.MaxRetries = 2
+	}
+	if config.MaxRetries < 0 {
+		config.MaxRetries = 0
+	}
+	if config.RetryWait <= 0 {
+		config.RetryWait = 200 * time.Millisecond
+	}
+	if config.RetryMaxWait <= 0 {
+		config.RetryMaxWait = 5 * time.Second
+	}
+	transport := config.Transport
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	return &Client{
+		config: config,
+		client: &http.Client{Transport: transport},
+	}
+}
+
+// OnBeforeRequest 注册请求发送前回调
+func (c *Client) OnBeforeRequest(hook BeforeHook) *Client {
+	c.before = append(c.before, hook)
+	return c
+}
+
+// OnAfterResponse 注册请求结束后回调
+func (c *Client) OnAfterResponse(hook AfterHook) *Client {
+	c.after = append(c.after, hook)
+	return c
+}
+
+// R 创建绑定ctx的请求构建器
+func (c *Client) R(ctx context.Context) *Request {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	r := &Request{
+		client:  c,
+		ctx:     ctx,
+		header:  make(http.Header),
+		query:   make(map[string][]string),
+		retries: c.config.MaxRetries,
+		timeout: c.config.Timeout,
+	}
+	for k, v := range c.config.Headers {
+		r.header.Set(k, v)
+	}
+	return r
+}
+
+// resolveURL 拼接BaseURL
+func (c *Client) resolveURL(url string) string {
+	if c.config.BaseURL == "" || strings.HasPrefix(url, "http://") || strings.HasPrefix(url, "https://") {
+		return url
+	}
+	return strings.TrimSuffix(c.config.BaseURL, "/") + "/" + strings.TrimPrefix(url, "/")
+}
+
+// shouldRetry 判断是否需要重试
+func shouldRetry(ctx context.Context, resp *http.Response, err error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+	if err != nil {
+		var netErr net.Error
+		if errors.As(err, &netErr) && netErr.Timeout() {
+			return true
+		}
+		if errors.Is(err, context.DeadlineExceeded) {
+			return true
+		}
+		// 连接被拒绝/重置等网络错误
+		var opErr *net.OpError
+		return errors.As(err, &opErr)
+	}
+	return resp.StatusCode >= 500 || resp.StatusCode == http.StatusTooManyRequests
+}
+
+// idempotent 方法是否幂等，重复发送不会产生额外副作用
+func idempotent(method string) bool {
+	switch method {
+	case utils.GET, utils.HEAD, utils.OPTIONS, utils.PUT, utils.DELETE:
+		return true
+	}
+	return false
+}
+
+// retryAfter 解析 429 响应的 Retry-After 头，支持秒数和 HTTP 日期两种格式
+func retryAfter(resp *Response) (time.Duration, bool) {
+	if resp == nil || resp.StatusCode != http.StatusTooManyRequests {
+		return 0, false
+	}
+	value := strings.TrimSpace(resp.Header.Get("Retry-After"))
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if t, err := http.ParseTime(value); err == nil {
+		return max(time.Until(t), 0), true
+	}
+	return 0, false
+}
+
+// backoff 计算第attempt次重试的等待时间（指数退避加随机抖动）
+func (c *Client) backoff(attempt int) time.Duration {
+	wait := c.config.RetryWait << uint(attempt)
+	if wait <= 0 || wait > c.config.RetryMaxWait {
+		wait = c.config.RetryMaxWait
+	}
+	jitter := time.Duration(rand.Int63n(int64(wait)/2 + 1))
+	return wait/2 + jitter
+}
+
+// GetJSON 使用默认客户端发送GET请求并将响应JSON解析到dest
+func GetJSON(ctx context.Context, url string, dest interface{}) error {
+	resp, err := Default.R(ctx).SetResult(dest).Get(url)
+	if err != nil {
+		return err
+	}
+	return resp.Error()
+}
+
+// PostJSON 使用默认客户端发送JSON POST请求并将响应JSON解析到dest（可为nil）
+func PostJSON(ctx context.Context, url string, body interface{}, dest interface{}) error {
+	resp, err := Default.R(ctx).SetJSON(body).SetResult(dest).Post(url)
+	if err != nil {
+		return err
+	}
+	return resp.Error()
+}
+
+// methodAllowed 校验请求方法
+func methodAllowed(method string) error {
+	switch method {
+	case utils.GET, utils.POST, utils.PUT, utils.DELETE, utils.PATCH, utils.HEAD, utils.OPTIONS:
+		return nil
+	}
+	return fmt.Errorf("unsupported http method %s", method)
+}
@@ -0,0 +1,270 @@
+package httpclient
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/ixxmi/tools/utils"
+)
+
+// Request 请求构建器
+type Request struct {
+	client  *Client
+	ctx     context.Context
+	header  http.Header
+	query   url.Values
+	body    []byte
+	err     error
+	result  interface{}
+	retries int
+	// retriesSet 调用过 SetRetries，非幂等方法也按 retries 重试
+	retriesSet bool
+	timeout    time.Duration
+}
+
+// SetHeader 设置请求头
+func (r *Request) SetHeader(key, value string) *Request {
+	r.header.Set(key, value)
+	return r
+}
+
+// SetHeaders 批量设置请求头
+func (r *Request) SetHeaders(headers map[string]string) *Request {
+	for k, v := range headers {
+		r.header.Set(k, v)
+	}
+	return r
+}
+
+// SetBasicAuth 设置Basic认证
+func (r *Request) SetBasicAuth(username, password string) *Request {
+	auth := base64.StdEncoding.EncodeToString([]byte(username + ":" + password))
+	r.header.Set("Authorization", "Basic "+auth)
+	return r
+}
+
+// SetBearerToken 设置Bearer令牌
+func (r *Request) SetBearerToken(token string) *Request {
+	r.header.Set("Authorization", "Bearer "+token)
+	return r
+}
+
+// SetQuery 设置查询参数
+func (r *Request) SetQuery(key string, value interface{}) *Request {
+	r.query.Set(key, utils.Str(value))
+	return r
+}
+
+// SetQueryParams 批量设置查询参数
+func (r *Request) SetQueryParams(params map[string]interface{}) *Request {
+	for k, v := range params {
+		r.query.Set(k, utils.Str(v))
+	}
+	return r
+}
+
+// SetJSON 设置JSON请求体
+func (r *Request) SetJSON(body interface{}) *Request {
+	data, err := json.Marshal(body)
+	if err != nil {
+		r.err = fmt.Errorf("failed to marshal request body: %w", err)
+		return r
+	}
+	r.body = data
+	r.header.Set("Content-Type", "application/json")
+	return r
+}
+
+// SetForm 设置表单请求体
+func (r *Request) SetForm(form map[string]string) *Request {
+	values := url.Values{}
+	for k, v := range form {
+		values.Set(k, v)
+	}
+	r.body = []byte(values.Encode())
+	r.header.Set("Content-Type", "application/x-www-form-urlencoded")
+	return r
+}
+
+// SetBody 设置原始请求体，读取后缓存以便重试
+func (r *Request) SetBody(body io.Reader, contentType string) *Request {
+	data, err := io.ReadAll(body)
+	if err != nil {
+		r.err = fmt.Errorf("failed to read request body: %w", err)
+		return r
+	}
+	r.body = data
+	if contentType != "" {
+		r.header.Set("Content-Type", contentType)
+	}
+	return r
+}
+
+// SetResult 设置响应成功（2xx）时JSON解析的目标
+func (r *Request) SetResult(dest interface{}) *Request {
+	r.result = dest
+	return r
+}
+
+// SetRetries 设置本次请求的最大重试次数，对 POST/PATCH 等非幂等方法同样生效，调用方需确保重复发送是安全的
+func (r *Request) SetRetries(n int) *Request {
+	r.retries = n
+	r.retriesSet = true
+	return r
+}
+
+// SetTimeout 设置本次请求单次尝试的超时时间
+func (r *Request) SetTimeout(d time.Duration) *Request {
+	r.timeout = d
+	return r
+}
+
+// Get 发送GET请求
+func (r *Request) Get(url string) (*Response, error) {
+	return r.Do(utils.GET, url)
+}
+
+// Post 发送POST请求
+func (r *Request) Post(url string) (*Response, error) {
+	return r.Do(utils.POST, url)
+}
+
+// Put 发送PUT请求
+func (r *Request) Put(url string) (*Response, error) {
+	return r.Do(utils.PUT, url)
+}
+
+// Patch 发送PATCH请求
+func (r *Request) Patch(url string) (*Response, error) {
+	return r.Do(utils.PATCH, url)
+}
+
+// Delete 发送DELETE请求
+func (r *Request) Delete(url string) (*Response, error) {
+	return r.Do(utils.DELETE, url)
+}
+
+// Do 发送请求，5xx/429/网络超时时按退避策略重试，429 响应带 Retry-After 时按其等待；
+// 非幂等方法（POST/PATCH）默认不重试，除非通过 SetRetries 显式开启
+func (r *Request) Do(method, rawURL string) (*Response, error) {
+	if r.err != nil {
+		return nil, r.err
+	}
+	method = strings.ToUpper(method)
+	if err := methodAllowed(method); err != nil {
+		return nil, err
+	}
+
+	target := r.client.resolveURL(rawURL)
+	if len(r.query) > 0 {
+		u, err := url.Parse(target)
+		if err != nil {
+			return nil, fmt.Errorf("invalid url %s: %w", target, err)
+		}
+		q := u.Query()
+		for k, vs := range r.query {
+			for _, v := range vs {
+				q.Add(k, v)
+			}
+		}
+		u.RawQuery = q.Encode()
+		target = u.String()
+	}
+
+	retries := max(r.retries, 0)
+	if !r.retriesSet && !idempotent(method) {
+		retries = 0
+	}
+
+	var lastErr error
+	var lastResp *Response
+	for attempt := 0; attempt <= retries; attempt++ {
+		if attempt > 0 {
+			wait, ok := retryAfter(lastResp)
+			if !ok {
+				wait = r.client.backoff(attempt - 1)
+			}
+			select {
+			case <-r.ctx.Done():
+				return nil, r.ctx.Err()
+			case <-time.After(wait):
+			}
+		}
+
+		resp, retry, err := r.attempt(method, target)
+		lastResp = resp
+		if err == nil && !retry {
+			return resp, nil
+		}
+		if err == nil {
+			// 可重试的状态码，最后一次尝试时直接返回响应
+			if attempt == retries {
+				return resp, nil
+			}
+			lastErr = fmt.Errorf("%s %s: status %d", method, target, resp.StatusCode)
+			continue
+		}
+		lastErr = err
+		if !retry {
+			return nil, err
+		}
+	}
+	return nil, lastErr
+}
+
+// attempt 执行一次请求，返回响应、是否可重试和错误
+func (r *Request) attempt(method, target string) (*Response, bool, error) {
+	ctx, cancel := context.WithTimeout(r.ctx, r.timeout)
+	defer cancel()
+
+	var body io.Reader
+	if r.body != nil {
+		body = bytes.NewReader(r.body)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, target, body)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header = r.header.Clone()
+	if req.Header.Get("Accept") == "" {
+		req.Header.Set("Accept", "application/json")
+	}
+
+	for _, hook := range r.client.before {
+		if err := hook(req); err != nil {
+			return nil, false, err
+		}
+	}
+
+	start := time.Now()
+	httpResp, err := r.client.client.Do(req)
+	var resp *Response
+	if err == nil {
+		resp, err = readResponse(httpResp)
+	}
+	elapsed := time.Since(start)
+	for _, hook := range r.client.after {
+		hook(req, httpResp, err, elapsed)
+	}
+
+	if err != nil {
+		return nil, shouldRetry(r.ctx, nil, err), fmt.Errorf("%s %s: %w", method, target, err)
+	}
+	if shouldRetry(r.ctx, httpResp, nil) {
+		return resp, true, nil
+	}
+	if r.result != nil && resp.IsSuccess() && len(resp.Body) > 0 {
+		if err := resp.JSON(r.result); err != nil {
+			return resp, false, err
+		}
+	}
+	return resp, false, nil
+}
@@ -0,0 +1,73 @@
+package httpclient
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// Response 响应，Body已完整读取
+type Response struct {
+	StatusCode int
+	Header     http.Header
+	Body       []byte
+}
+
+// readResponse 读取并关闭响应体
+func readResponse(resp *http.Response) (*Response, error) {
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+	return &Response{
+		StatusCode: resp.StatusCode,
+		Header:     resp.Header,
+		Body:       body,
+	}, nil
+}
+
+// IsSuccess 状态码是否为2xx
+func (r *Response) IsSuccess() bool {
+	return r.StatusCode >= 200 && r.StatusCode < 300
+}
+
+// Error 状态码非2xx时返回错误
+func (r *Response) Error() error {
+	if r.IsSuccess() {
+		return nil
+	}
+	body := r.Body
+	if len(body) > 512 {
+		body = body[:512]
+	}
+	return fmt.Errorf("http status %d: %s", r.StatusCode, string(body))
+}
+
+// String 返回响应体字符串
+func (r *Response) String() string {
+	return string(r.Body)
+}
+
+// JSON 将响应体解析到dest
+func (r *Response) JSON(dest interface{}) error {
+	if err := json.Unmarshal(r.Body, dest); err != nil {
+		return fmt.Errorf("failed to decode response body: %w", err)
+	}
+	return nil
+}
+
+// Map 将响应体解析为map
+func (r *Response) Map() (map[string]interface{}, error) {
+	m := map[string]interface{}{}
+	err := r.JSON(&m)
+	return m, err
+}
+
+// Maps 将响应体解析为map切片
+func (r *Response) Maps() ([]map[string]interface{}, error) {
+	m := []map[string]interface{}{}
+	err := r.JSON(&m)
+	return m, err
+}
@@ -0,0 +1,169 @@
+package i18n
+
+import (
+	"context"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+type contextKey struct{}
+
+// WithLanguage 将语言写入 context
+func WithLanguage(ctx context.Context, lang string) context.Context {
+	return context.WithValue(ctx, contextKey{}, lang)
+}
+
+// FromContext 读取 context 中的语言，未设置时返回空字符串
+func FromContext(ctx context.Context) string {
+	lang, _ := ctx.Value(contextKey{}).(string)
+	return lang
+}
+
+// TC 使用 context 中的语言翻译
+func (b *Bundle) TC(ctx context.Context, key string, args ...interface{}) string {
+	return b.T(FromContext(ctx), key, args...)
+}
+
+// NC 使用 context 中的语言按数量翻译
+func (b *Bundle) NC(ctx context.Context, key string, count int, args ...interface{}) string {
+	return b.N(FromContext(ctx), key, count, args...)
+}
+
+// Match 按 Accept-Language 的权重顺序选择第一个可用的语言，均不可用时返回默认语言
+func (b *Bundle) Match(acceptLanguage string) string {
+	for _, lang := range ParseAcceptLanguage(acceptLanguage) {
+		if lang == "*" {
+			break
+		}
+		b.mu.RLock()
+		for _, l := range b.chain(lang) {
+			if l == b.defLang && l != normalizeLang(lang) {
+				// 回退到默认语言时继续尝试下一个候选
+				break
+			}
+			if _, ok := b.messages[l]; ok {
+				b.mu.RUnlock()
+				return l
+			}
+		}
+		b.mu.RUnlock()
+	}
+	return b.defLang
+}
+
+// MiddlewareOption 中间件选项
+type MiddlewareOption func(*middleware)
+
+type middleware struct {
+	query  string
+	cookie string
+	header string
+	tenant func(*http.Request) string
+}
+
+// WithQueryParam 优先使用查询参数指定的语言，如 ?lang=en
+func WithQueryParam(name string) MiddlewareOption {
+	return func(m *middleware) { m.query = name }
+}
+
+// WithCookie 优先使用 Cookie 指定的语言
+func WithCookie(name string) MiddlewareOption {
+	return func(m *middleware) { m.cookie = name }
+}
+
+// WithHeader 优先使用自定义请求头指定的语言，如 X-Language
+func WithHeader(name string) MiddlewareOption {
+	return func(m *middleware) { m.header = name }
+}
+
+// WithTenantLanguage 按租户配置返回语言，返回空字符串时继续按 Accept-Language 选择
+func WithTenantLanguage(fn func(*http.Request) string) MiddlewareOption {
+	return func(m *middleware) { m.tenant = fn }
+}
+
+// Middleware 选择请求语言并写入 context，顺序为查询参数、Cookie、自定义请求头、租户配置、Accept-Language
+func (b *Bundle) Middleware(opts ...MiddlewareOption) func(http.Handler) http.Handler {
+	m := &middleware{}
+	for _, opt := range opts {
+		opt(m)
+	}
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			lang := m.explicit(r)
+			if lang != "" {
+				lang = b.Match(lang)
+			} else {
+				lang = b.Match(r.Header.Get("Accept-Language"))
+			}
+			w.Header().Set("Content-Language", lang)
+			next.ServeHTTP(w, r.WithContext(WithLanguage(r.Context(), lang)))
+		})
+	}
+}
+
+// explicit 返回请求显式指定的语言
+func (m *middleware) explicit(r *http.Request) string {
+	if m.query != "" {
+		if v := r.URL.Query().Get(m.query); v != "" {
+			return v
+		}
+	}
+	if m.cookie != "" {
+		if c, err := r.Cookie(m.cookie); err == nil && c.Value != "" {
+			return c.Value
+		}
+	}
+	if m.header != "" {
+		if v := r.Header.Get(m.header); v != "" {
+			return v
+		}
+	}
+	if m.tenant != nil {
+		return m.tenant(r)
+	}
+	return ""
+}
+
+// Middleware 使用默认消息目录的中间件
+func Middleware(opts ...MiddlewareOption) func(http.Handler) http.Handler {
+	return Default.Middleware(opts...)
+}
+
+// ParseAcceptLanguage 解析 Accept-Language 请求头，按权重从高到低返回语言标签
+func ParseAcceptLanguage(header string) []string {
+	type item struct {
+		lang string
+		q    float64
+	}
+	var items []item
+	for _, part := range strings.Split(header, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		lang, q := part, 1.0
+		if i := strings.Index(part, ";"); i >= 0 {
+			lang = strings.TrimSpace(part[:i])
+			for _, p := range strings.Split(part[i+1:], ";") {
+				p = strings.TrimSpace(p)
+				if strings.HasPrefix(p, "q=") {
+					if v, err := strconv.ParseFloat(p[2:], 64); err == nil {
+						q = v
+					}
+				}
+			}
+		}
+		if q <= 0 || lang == "" {
+			continue
+		}
+		items = append(items, item{normalizeLang(lang), q})
+	}
+	sort.SliceStable(items, func(i, j int) bool { return items[i].q > items[j].q })
+	langs := make([]string, 0, len(items))
+	for _, it := range items {
+		langs = append(langs, it.lang)
+	}
+	return langs
+}
@@ -0,0 +1,278 @@
+// Package i18n 提供多语言消息目录，支持按语言加载消息文件、复数形式、回退链和按 Accept-Language 选择语言的中间件。
+package i18n
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/ixxmi/tools/config"
+)
+
+// Message 单条消息，Other 为默认形式，其余为复数形式
+type Message struct {
+	Zero  string
+	One   string
+	Two   string
+	Few   string
+	Many  string
+	Other string
+}
+
+// Bundle 消息目录
+type Bundle struct {
+	mu        sync.RWMutex
+	defLang   string
+	messages  map[string]map[string]*Message
+	fallbacks map[string][]string
+}
+
+// NewBundle 创建消息目录，defaultLang 为最终回退语言
+func NewBundle(defaultLang string) *Bundle {
+	return &Bundle{
+		defLang:   normalizeLang(defaultLang),
+		messages:  make(map[string]map[string]*Message),
+		fallbacks: make(map[string][]string),
+	}
+}
+
+// Default 默认消息目录，默认语言为中文
+var Default = NewBundle("zh-CN")
+
+// normalizeLang 统一语言标签格式：小写，下划线替换为连字符
+func normalizeLang(lang string) string {
+	return strings.ToLower(strings.ReplaceAll(strings.TrimSpace(lang), "_", "-"))
+}
+
+// DefaultLanguage 返回默认语言
+func (b *Bundle) DefaultLanguage() string {
+	return b.defLang
+}
+
+// SetFallback 设置语言的回退链，如 SetFallback("zh-TW", "zh-HK", "zh-CN")
+func (b *Bundle) SetFallback(lang string, fallbacks ...string) {
+	list := make([]string, 0, len(fallbacks))
+	for _, f := range fallbacks {
+		list = append(list, normalizeLang(f))
+	}
+	b.mu.Lock()
+	b.fallbacks[normalizeLang(lang)] = list
+	b.mu.Unlock()
+}
+
+// Add 添加单条消息
+func (b *Bundle) Add(lang, key string, msg Message) {
+	lang = normalizeLang(lang)
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.messages[lang] == nil {
+		b.messages[lang] = make(map[string]*Message)
+	}
+	b.messages[lang][key] = &msg
+}
+
+// AddMessages 添加一组消息，值为字符串或复数形式映射（zero/one/two/few/many/other），嵌套映射的键以点号连接
+func (b *Bundle) AddMessages(lang string, messages map[string]interface{}) error {
+	flat := make(map[string]*Message)
+	if err := flatten("", messages, flat); err != nil {
+		return fmt.Errorf("invalid messages for %s: %w", lang, err)
+	}
+	lang = normalizeLang(lang)
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.messages[lang] == nil {
+		b.messages[lang] = make(map[string]*Message)
+	}
+	for k, m := range flat {
+		b.messages[lang][k] = m
+	}
+	return nil
+}
+
+// LoadFile 加载消息文件，文件名（不含扩展名）即语言标签，如 zh-CN.yaml、en.json
+func (b *Bundle) LoadFile(filename string) error {
+	m, err := config.ReadFile(filename)
+	if err != nil {
+		return err
+	}
+	lang := strings.TrimSuffix(filepath.Base(filename), filepath.Ext(filename))
+	return b.AddMessages(lang, m)
+}
+
+// LoadDir 加载目录下全部 yaml/yml/json/toml 消息文件
+func (b *Bundle) LoadDir(dir string) error {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return fmt.Errorf("failed to read i18n dir %s: %w", dir, err)
+	}
+	for _, e := range entries {
+		if e.IsDir() {
+			continue
+		}
+		switch strings.ToLower(filepath.Ext(e.Name())) {
+		case ".yaml", ".yml", ".json", ".toml":
+			if err := b.LoadFile(filepath.Join(dir, e.Name())); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// Languages 返回已加载的语言列表
+func (b *Bundle) Languages() []string {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	langs := make([]string, 0, len(b.messages))
+	for lang := range b.messages {
+		langs = append(langs, lang)
+	}
+	sort.Strings(langs)
+	return langs
+}
+
+// HasLanguage 判断语言是否已加载
+func (b *Bundle) HasLanguage(lang string) bool {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	_, ok := b.messages[normalizeLang(lang)]
+	return ok
+}
+
+// chain 返回语言的查找顺序：自身、逐级去掉子标签、自定义回退链、默认语言
+func (b *Bundle) chain(lang string) []string {
+	lang = normalizeLang(lang)
+	var list []string
+	seen := make(map[string]bool)
+	add := func(l string) {
+		if l != "" && !seen[l] {
+			seen[l] = true
+			list = append(list, l)
+		}
+	}
+	for l := lang; l != ""; {
+		add(l)
+		for _, f := range b.fallbacks[l] {
+			add(f)
+		}
+		i := strings.LastIndex(l, "-")
+		if i < 0 {
+			break
+		}
+		l = l[:i]
+	}
+	add(b.defLang)
+	if i := strings.Index(b.defLang, "-"); i > 0 {
+		add(b.defLang[:i])
+	}
+	return list
+}
+
+// lookup 沿回退链查找消息，返回消息及其所属语言
+func (b *Bundle) lookup(lang, key string) (*Message, string) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	for _, l := range b.chain(lang) {
+		if m, ok := b.messages[l][key]; ok {
+			return m, l
+		}
+	}
+	return nil, ""
+}
+
+// T 翻译消息，args 按 fmt.Sprintf 格式化；未找到时返回 key
+func (b *Bundle) T(lang, key string, args ...interface{}) string {
+	m, _ := b.lookup(lang, key)
+	if m == nil {
+		return key
+	}
+	return format(m.Other, args)
+}
+
+// N 按数量选择复数形式翻译消息，count 同时作为第一个格式化参数
+func (b *Bundle) N(lang, key string, count int, args ...interface{}) string {
+	m, l := b.lookup(lang, key)
+	if m == nil {
+		return key
+	}
+	return format(m.form(pluralCategory(l, count)), append([]interface{}{count}, args...))
+}
+
+// format 格式化消息，不含格式化动词的消息原样返回
+func format(s string, args []interface{}) string {
+	if len(args) == 0 || !strings.Contains(s, "%") {
+		return s
+	}
+	return fmt.Sprintf(s, args...)
+}
+
+// flatten 展开嵌套映射，只包含复数分类键的映射视为复数消息
+func flatten(prefix string, m map[string]interface{}, out map[string]*Message) error {
+	for k, v := range m {
+		key := k
+		if prefix != "" {
+			key = prefix + "." + k
+		}
+		switch val := v.(type) {
+		case string:
+			out[key] = &Message{Other: val}
+		case map[string]interface{}:
+			if msg, ok := pluralMessage(val); ok {
+				out[key] = msg
+				continue
+			}
+			if err := flatten(key, val, out); err != nil {
+				return err
+			}
+		default:
+			return fmt.Errorf("unsupported value for key %s: %T", key, v)
+		}
+	}
+	return nil
+}
+
+func pluralMessage(m map[string]interface{}) (*Message, bool) {
+	if len(m) == 0 {
+		return nil, false
+	}
+	msg := &Message{}
+	for k, v := range m {
+		s, ok := v.(string)
+		if !ok {
+			return nil, false
+		}
+		switch strings.ToLower(k) {
+		case "zero":
+			msg.Zero = s
+		case "one":
+			msg.One = s
+		case "two":
+			msg.Two = s
+		case "few":
+			msg.Few = s
+		case "many":
+			msg.Many = s
+		case "other":
+			msg.Other = s
+		default:
+			return nil, false
+		}
+	}
+	if msg.Other == "" {
+		return nil, false
+	}
+	return msg, true
+}
+
+// T 使用默认消息目录翻译
+func T(lang, key string, args ...interface{}) string {
+	return Default.T(lang, key, args...)
+}
+
+// N 使用默认消息目录按数量翻译
+func N(lang, key string, count int, args ...interface{}) string {
+	return Default.N(lang, key, count, args...)
+}
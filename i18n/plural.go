@@ -0,0 +1,99 @@
+package i18n
+
+import "strings"
+
+// 复数分类
+const (
+	pluralZero  = "zero"
+	pluralOne   = "one"
+	pluralTwo   = "two"
+	pluralFew   = "few"
+	pluralMany  = "many"
+	pluralOther = "other"
+)
+
+// pluralCategory 返回语言在给定数量下的复数分类，覆盖常用语言的整数规则
+func pluralCategory(lang string, n int) string {
+	if n < 0 {
+		n = -n
+	}
+	base := lang
+	if i := strings.Index(lang, "-"); i > 0 {
+		base = lang[:i]
+	}
+	switch base {
+	case "zh", "ja", "ko", "vi", "th", "id", "ms":
+		// 无复数变化，仅在提供 zero 时区分 0
+		if n == 0 {
+			return pluralZero
+		}
+		return pluralOther
+	case "fr", "pt":
+		if n == 0 || n == 1 {
+			return pluralOne
+		}
+		return pluralOther
+	case "ru", "uk", "be":
+		switch {
+		case n%10 == 1 && n%100 != 11:
+			return pluralOne
+		case n%10 >= 2 && n%10 <= 4 && (n%100 < 12 || n%100 > 14):
+			return pluralFew
+		default:
+			return pluralMany
+		}
+	case "pl":
+		switch {
+		case n == 1:
+			return pluralOne
+		case n%10 >= 2 && n%10 <= 4 && (n%100 < 12 || n%100 > 14):
+			return pluralFew
+		default:
+			return pluralMany
+		}
+	case "ar":
+		switch {
+		case n == 0:
+			return pluralZero
+		case n == 1:
+			return pluralOne
+		case n == 2:
+			return pluralTwo
+		case n%100 >= 3 && n%100 <= 10:
+			return pluralFew
+		case n%100 >= 11:
+			return pluralMany
+		default:
+			return pluralOther
+		}
+	default:
+		if n == 0 {
+			return pluralZero
+		}
+		if n == 1 {
+			return pluralOne
+		}
+		return pluralOther
+	}
+}
+
+// form 返回分类对应的文本，缺失时回退到 Other
+func (m *Message) form(category string) string {
+	var s string
+	switch category {
+	case pluralZero:
+		s = m.Zero
+	case pluralOne:
+		s = m.One
+	case pluralTwo:
+		s = m.Two
+	case pluralFew:
+		s = m.Few
+	case pluralMany:
+		s = m.Many
+	}
+	if s == "" {
+		return m.Other
+	}
+	return s
+}
@@ -0,0 +1,318 @@
+package idgen
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/ixxmi/tools/cache/redis"
+	goredis "github.com/redis/go-redis/v9"
+	"go.etcd.io/etcd/api/v3/v3rpc/rpctypes"
+	clientv3 "go.etcd.io/etcd/client/v3"
+)
+
+// WorkerLeaser workerID 租约分配器
+type WorkerLeaser interface {
+	// Acquire 抢占一个空闲的 workerID
+	Acquire(ctx context.Context) (int64, error)
+	// Refresh 续约，租约已被他人占用或已过期时返回 ErrLeaseLost
+	Refresh(ctx context.Context) error
+	// Release 释放租约
+	Release(ctx context.Context) error
+	// TTL 租约有效期
+	TTL() time.Duration
+}
+
+// LeaseConfig 租约配置
+type LeaseConfig struct {
+	Namespace string        // 键前缀，默认 idgen
+	TTL       time.Duration // 租约有效期，默认 30s
+}
+
+func (c *LeaseConfig) defaults() {
+	if c.Namespace == "" {
+		c.Namespace = "idgen"
+	}
+	if c.TTL <= 0 {
+		c.TTL = 30 * time.Second
+	}
+}
+
+// leaseOwner 租约持有者标识
+func leaseOwner() string {
+	host, _ := os.Hostname()
+	return fmt.Sprintf("%s:%d:%d", host, os.Getpid(), time.Now().UnixNano())
+}
+
+// RedisLeaser 基于 Redis SET NX 的 workerID 租约
+type RedisLeaser struct {
+	rc     *redis.RedisClient
+	config LeaseConfig
+	owner  string
+
+	mu  sync.Mutex
+	key string
+}
+
+// NewRedisLeaser 创建 Redis 租约分配器
+func NewRedisLeaser(rc *redis.RedisClient, config LeaseConfig) *RedisLeaser {
+	config.defaults()
+	return &RedisLeaser{rc: rc, config: config, owner: leaseOwner()}
+}
+
+var (
+	// refreshScript 仅当键仍属于自己时续期
+	refreshScript = goredis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("PEXPIRE", KEYS[1], ARGV[2])
+end
+return 0`)
+	// releaseScript 仅当键仍属于自己时删除
+	releaseScript = goredis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0`)
+)
+
+// Acquire 从随机位置开始依次尝试占用 workerID
+func (l *RedisLeaser) Acquire(ctx context.Context) (int64, error) {
+	start := rand.Int63n(MaxWorkerID + 1)
+	for i := int64(0); i <= MaxWorkerID; i++ {
+		id := (start + i) % (MaxWorkerID + 1)
+		key := l.config.Namespace + ":worker:" + strconv.FormatInt(id, 10)
+		ok, err := l.rc.Client().SetNX(ctx, key, l.owner, l.config.TTL).Result()
+		if err != nil {
+			return 0, fmt.Errorf("failed to acquire worker id: %w", err)
+		}
+		if ok {
+			l.mu.Lock()
+			l.key = key
+			l.mu.Unlock()
+			return id, nil
+		}
+	}
+	return 0, ErrNoWorkerID
+}
+
+// Refresh 续约
+func (l *RedisLeaser) Refresh(ctx context.Context) error {
+	l.mu.Lock()
+	key := l.key
+	l.mu.Unlock()
+	if key == "" {
+		return ErrLeaseLost
+	}
+	n, err := refreshScript.Run(ctx, l.rc.Client(), []string{key}, l.owner, l.config.TTL.Milliseconds()).Int()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return ErrLeaseLost
+	}
+	return nil
+}
+
+// Release 释放租约
+func (l *RedisLeaser) Release(ctx context.Context) error {
+	l.mu.Lock()
+	key := l.key
+	l.key = ""
+	l.mu.Unlock()
+	if key == "" {
+		return nil
+	}
+	return releaseScript.Run(ctx, l.rc.Client(), []string{key}, l.owner).Err()
+}
+
+// TTL 租约有效期
+func (l *RedisLeaser) TTL() time.Duration {
+	return l.config.TTL
+}
+
+// EtcdLeaser 基于 etcd 租约和事务的 workerID 分配器
+type EtcdLeaser struct {
+	client *clientv3.Client
+	config LeaseConfig
+	owner  string
+
+	mu    sync.Mutex
+	lease clientv3.LeaseID
+}
+
+// NewEtcdLeaser 创建 etcd 租约分配器
+func NewEtcdLeaser(client *clientv3.Client, config LeaseConfig) *EtcdLeaser {
+	config.defaults()
+	return &EtcdLeaser{client: client, config: config, owner: leaseOwner()}
+}
+
+// Acquire 申请租约后以事务方式占用第一个不存在的 workerID 键
+func (l *EtcdLeaser) Acquire(ctx context.Context) (int64, error) {
+	lease, err := l.client.Grant(ctx, int64((l.config.TTL+time.Second-1)/time.Second))
+	if err != nil {
+		return 0, fmt.Errorf("failed to grant lease: %w", err)
+	}
+	start := rand.Int63n(MaxWorkerID + 1)
+	for i := int64(0); i <= MaxWorkerID; i++ {
+		id := (start + i) % (MaxWorkerID + 1)
+		key := "/" + l.config.Namespace + "/workers/" + strconv.FormatInt(id, 10)
+		resp, err := l.client.Txn(ctx).
+			If(clientv3.Compare(clientv3.CreateRevision(key), "=", 0)).
+			Then(clientv3.OpPut(key, l.owner, clientv3.WithLease(lease.ID))).
+			Commit()
+		if err != nil {
+			_, _ = l.client.Revoke(context.Background(), lease.ID)
+			return 0, fmt.Errorf("failed to acquire worker id: %w", err)
+		}
+		if resp.Succeeded {
+			l.mu.Lock()
+			l.lease = lease.ID
+			l.mu.Unlock()
+			return id, nil
+		}
+	}
+	_, _ = l.client.Revoke(context.Background(), lease.ID)
+	return 0, ErrNoWorkerID
+}
+
+// Refresh 续约
+func (l *EtcdLeaser) Refresh(ctx context.Context) error {
+	l.mu.Lock()
+	lease := l.lease
+	l.mu.Unlock()
+	if lease == clientv3.NoLease {
+		return ErrLeaseLost
+	}
+	if _, err := l.client.KeepAliveOnce(ctx, lease); err != nil {
+		if errors.Is(err, rpctypes.ErrLeaseNotFound) {
+			return ErrLeaseLost
+		}
+		return err
+	}
+	return nil
+}
+
+// Release 撤销租约
+func (l *EtcdLeaser) Release(ctx context.Context) error {
+	l.mu.Lock()
+	lease := l.lease
+	l.lease = clientv3.NoLease
+	l.mu.Unlock()
+	if lease == clientv3.NoLease {
+		return nil
+	}
+	_, err := l.client.Revoke(ctx, lease)
+	return err
+}
+
+// TTL 租约有效期
+func (l *EtcdLeaser) TTL() time.Duration {
+	return l.config.TTL
+}
+
+// LeasedSnowflake 持有 workerID 租约的雪花生成器，租约失效期间拒绝生成ID并自动重新抢占
+type LeasedSnowflake struct {
+	*Snowflake
+	leaser WorkerLeaser
+	cancel context.CancelFunc
+	done   chan struct{}
+
+	mu      sync.RWMutex
+	expires time.Time
+	lost    bool
+}
+
+// NewLeasedSnowflake 抢占 workerID 并创建雪花生成器，后台按 TTL/3 的间隔续约
+func NewLeasedSnowflake(ctx context.Context, leaser WorkerLeaser, opts ...SnowflakeOption) (*LeasedSnowflake, error) {
+	id, err := leaser.Acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+	sf, err := NewSnowflake(id, opts...)
+	if err != nil {
+		_ = leaser.Release(ctx)
+		return nil, err
+	}
+	kctx, cancel := context.WithCancel(context.Background())
+	s := &LeasedSnowflake{
+		Snowflake: sf,
+		leaser:    leaser,
+		cancel:    cancel,
+		done:      make(chan struct{}),
+		expires:   time.Now().Add(leaser.TTL()),
+	}
+	go s.keepAlive(kctx)
+	return s, nil
+}
+
+// keepAlive 定时续约，续约连续失败至租约到期或租约已被占用时标记失效并重新抢占
+func (s *LeasedSnowflake) keepAlive(ctx context.Context) {
+	defer close(s.done)
+	ttl := s.leaser.TTL()
+	ticker := time.NewTicker(ttl / 3)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		s.mu.RLock()
+		lost := s.lost
+		s.mu.RUnlock()
+
+		if lost {
+			id, err := s.leaser.Acquire(ctx)
+			if err != nil {
+				continue
+			}
+			s.Snowflake.setWorkerID(id)
+			s.mu.Lock()
+			s.lost = false
+			s.expires = time.Now().Add(ttl)
+			s.mu.Unlock()
+			continue
+		}
+
+		rctx, cancel := context.WithTimeout(ctx, ttl/3)
+		started := time.Now()
+		err := s.leaser.Refresh(rctx)
+		cancel()
+		s.mu.Lock()
+		switch {
+		case err == nil:
+			s.expires = started.Add(ttl)
+		case errors.Is(err, ErrLeaseLost) || time.Now().After(s.expires):
+			s.lost = true
+		}
+		s.mu.Unlock()
+	}
+}
+
+// Next 实现 Generator 接口
+func (s *LeasedSnowflake) Next(ctx context.Context) (int64, error) {
+	return s.NextID()
+}
+
+// NextID 生成下一个ID，租约失效时返回 ErrLeaseLost
+func (s *LeasedSnowflake) NextID() (int64, error) {
+	s.mu.RLock()
+	valid := !s.lost && time.Now().Before(s.expires)
+	s.mu.RUnlock()
+	if !valid {
+		return 0, ErrLeaseLost
+	}
+	return s.Snowflake.NextID()
+}
+
+// Close 停止续约并释放 workerID
+func (s *LeasedSnowflake) Close(ctx context.Context) error {
+	s.cancel()
+	<-s.done
+	return s.leaser.Release(ctx)
+}
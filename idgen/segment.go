@@ -0,0 +1,182 @@
+package idgen
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// SegmentConfig 号段分配器配置
+type SegmentConfig struct {
+	Table         string        // 号段表名，默认 id_segment
+	Step          int64         // 新业务标识的默认步长，默认 1000
+	PrefetchRatio float64       // 当前号段剩余比例低于该值时异步预取下一号段，默认 0.2
+	Timeout       time.Duration // 取号段超时时间，默认 5s
+}
+
+// segment 号段 [cursor, max]
+type segment struct {
+	cursor int64
+	max    int64
+	step   int64
+}
+
+// segmentBuffer 单个业务标识的双缓冲
+type segmentBuffer struct {
+	mu      sync.Mutex
+	cur     *segment
+	next    *segment
+	loading chan struct{}
+	err     error
+}
+
+// SegmentAllocator 基于 MySQL 号段表的ID分配器，每次从数据库取一段连续ID在内存中分配，多副本之间号段互不重叠
+type SegmentAllocator struct {
+	db      *gorm.DB
+	config  SegmentConfig
+	mu      sync.Mutex
+	buffers map[string]*segmentBuffer
+}
+
+// NewSegmentAllocator 创建号段分配器，号段表不存在时自动创建
+func NewSegmentAllocator(db *gorm.DB, config SegmentConfig) (*SegmentAllocator, error) {
+	if config.Table == "" {
+		config.Table = "id_segment"
+	}
+	if config.Step <= 0 {
+		config.Step = 1000
+	}
+	if config.PrefetchRatio <= 0 || config.PrefetchRatio >= 1 {
+		config.PrefetchRatio = 0.2
+	}
+	if config.Timeout <= 0 {
+		config.Timeout = 5 * time.Second
+	}
+	err := db.Exec("CREATE TABLE IF NOT EXISTS `" + config.Table + "` (" +
+		"`biz_tag` VARCHAR(128) NOT NULL PRIMARY KEY," +
+		"`max_id` BIGINT NOT NULL DEFAULT 0," +
+		"`step` INT NOT NULL DEFAULT 1000," +
+		"`updated_at` DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP" +
+		") ENGINE=InnoDB").Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to create segment table: %w", err)
+	}
+	return &SegmentAllocator{db: db, config: config, buffers: make(map[string]*segmentBuffer)}, nil
+}
+
+// SetStep 修改业务标识的步长，从下次取号段开始生效
+func (a *SegmentAllocator) SetStep(ctx context.Context, tag string, step int64) error {
+	if step <= 0 {
+		return fmt.Errorf("idgen: invalid step %d", step)
+	}
+	return a.db.WithContext(ctx).Exec("INSERT INTO `"+a.config.Table+"` (biz_tag, max_id, step) VALUES (?, 0, ?) "+
+		"ON DUPLICATE KEY UPDATE step = VALUES(step), updated_at = NOW()", tag, step).Error
+}
+
+// fetch 在事务内推进 max_id 并读取新的号段，业务标识不存在时按默认步长初始化
+func (a *SegmentAllocator) fetch(ctx context.Context, tag string) (*segment, error) {
+	var maxID, step int64
+	err := a.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Exec("INSERT INTO `"+a.config.Table+"` (biz_tag, max_id, step) VALUES (?, ?, ?) "+
+			"ON DUPLICATE KEY UPDATE max_id = max_id + step, updated_at = NOW()", tag, a.config.Step, a.config.Step).Error
+		if err != nil {
+			return err
+		}
+		return tx.Raw("SELECT max_id, step FROM `"+a.config.Table+"` WHERE biz_tag = ?", tag).Row().Scan(&maxID, &step)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch segment for %s: %w", tag, err)
+	}
+	return &segment{cursor: maxID - step + 1, max: maxID, step: step}, nil
+}
+
+func (a *SegmentAllocator) buffer(tag string) *segmentBuffer {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	b, ok := a.buffers[tag]
+	if !ok {
+		b = &segmentBuffer{}
+		a.buffers[tag] = b
+	}
+	return b
+}
+
+// load 启动异步取号段，调用时需持有 b.mu
+func (a *SegmentAllocator) load(b *segmentBuffer, tag string) chan struct{} {
+	if b.loading != nil {
+		return b.loading
+	}
+	ch := make(chan struct{})
+	b.loading = ch
+	b.err = nil
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), a.config.Timeout)
+		defer cancel()
+		seg, err := a.fetch(ctx, tag)
+		b.mu.Lock()
+		if err != nil {
+			b.err = err
+		} else {
+			b.next = seg
+		}
+		b.loading = nil
+		b.mu.Unlock()
+		close(ch)
+	}()
+	return ch
+}
+
+// Next 分配业务标识 tag 的下一个ID
+func (a *SegmentAllocator) Next(ctx context.Context, tag string) (int64, error) {
+	b := a.buffer(tag)
+	for {
+		b.mu.Lock()
+		if cur := b.cur; cur != nil && cur.cursor <= cur.max {
+			id := cur.cursor
+			cur.cursor++
+			if b.next == nil && float64(cur.max-cur.cursor+1) < float64(cur.step)*a.config.PrefetchRatio {
+				a.load(b, tag)
+			}
+			b.mu.Unlock()
+			return id, nil
+		}
+		if b.next != nil {
+			b.cur, b.next = b.next, nil
+			b.mu.Unlock()
+			continue
+		}
+		wait := a.load(b, tag)
+		b.mu.Unlock()
+
+		select {
+		case <-wait:
+		case <-ctx.Done():
+			return 0, ctx.Err()
+		}
+		b.mu.Lock()
+		err := b.err
+		if b.next == nil && err != nil {
+			b.err = nil
+			b.mu.Unlock()
+			return 0, err
+		}
+		b.mu.Unlock()
+	}
+}
+
+// Generator 返回绑定业务标识的 Generator
+func (a *SegmentAllocator) Generator(tag string) Generator {
+	return &segmentGenerator{allocator: a, tag: tag}
+}
+
+type segmentGenerator struct {
+	allocator *SegmentAllocator
+	tag       string
+}
+
+func (g *segmentGenerator) Next(ctx context.Context) (int64, error) {
+	return g.allocator.Next(ctx, g.tag)
+}
@@ -0,0 +1,173 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	goredis "github.com/redis/go-redis/v9"
+)
+
+// Stats 任务统计
+type Stats struct {
+	Pending    map[string]int64 // 各类型就绪队列中等待执行的任务数
+	Scheduled  int64            // 延迟执行和等待重试的任务数
+	Processing int64            // 执行中的任务数
+	Failed     int64            // 重试耗尽的失败任务数
+}
+
+// Stats 获取任务统计
+func (m *Manager) Stats(ctx context.Context) (*Stats, error) {
+	types, err := m.rc.Client().SMembers(ctx, m.keys.types()).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get job types: %w", err)
+	}
+
+	pipe := m.rc.Client().Pipeline()
+	pending := make(map[string]*goredis.IntCmd, len(types))
+	for _, t := range types {
+		pending[t] = pipe.LLen(ctx, m.keys.queue(t))
+	}
+	scheduled := pipe.ZCard(ctx, m.keys.scheduled())
+	processing := pipe.ZCard(ctx, m.keys.processing())
+	failed := pipe.ZCard(ctx, m.keys.failed())
+	if _, err := pipe.Exec(ctx); err != nil {
+		return nil, fmt.Errorf("failed to get job stats: %w", err)
+	}
+
+	stats := &Stats{
+		Pending:    make(map[string]int64, len(types)),
+		Scheduled:  scheduled.Val(),
+		Processing: processing.Val(),
+		Failed:     failed.Val(),
+	}
+	for t, cmd := range pending {
+		stats.Pending[t] = cmd.Val()
+	}
+	return stats, nil
+}
+
+// Get 获取任务详情
+func (m *Manager) Get(ctx context.Context, id string) (*Job, error) {
+	return m.getJob(ctx, id)
+}
+
+// Failed 按失败时间倒序分页获取失败任务
+func (m *Manager) Failed(ctx context.Context, offset, limit int64) ([]*Job, error) {
+	if limit <= 0 {
+		limit = 20
+	}
+	ids, err := m.rc.Client().ZRevRange(ctx, m.keys.failed(), offset, offset+limit-1).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list failed jobs: %w", err)
+	}
+	jobs := make([]*Job, 0, len(ids))
+	for _, id := range ids {
+		job, err := m.getJob(ctx, id)
+		if errors.Is(err, ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		jobs = append(jobs, job)
+	}
+	return jobs, nil
+}
+
+// Retry 将失败任务重新放入就绪队列，执行次数清零
+func (m *Manager) Retry(ctx context.Context, id string) error {
+	job, err := m.getJob(ctx, id)
+	if err != nil {
+		return err
+	}
+	removed, err := m.rc.Client().ZRem(ctx, m.keys.failed(), id).Result()
+	if err != nil {
+		return fmt.Errorf("failed to retry job: %w", err)
+	}
+	if removed == 0 {
+		return fmt.Errorf("jobs: job %s is not failed", id)
+	}
+	_, err = m.rc.Client().TxPipelined(ctx, func(pipe goredis.Pipeliner) error {
+		pipe.HSet(ctx, m.keys.job(id), "attempts", 0)
+		pipe.HDel(ctx, m.keys.job(id), "failed_at")
+		pipe.LPush(ctx, m.keys.queue(job.Type), id)
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to retry job: %w", err)
+	}
+	return nil
+}
+
+// RetryAll 重试所有失败任务，返回重试的任务数
+func (m *Manager) RetryAll(ctx context.Context) (int, error) {
+	ids, err := m.rc.Client().ZRange(ctx, m.keys.failed(), 0, -1).Result()
+	if err != nil {
+		return 0, fmt.Errorf("failed to list failed jobs: %w", err)
+	}
+	count := 0
+	for _, id := range ids {
+		err := m.Retry(ctx, id)
+		if errors.Is(err, ErrNotFound) {
+			m.rc.Client().ZRem(ctx, m.keys.failed(), id)
+			continue
+		}
+		if err != nil {
+			return count, err
+		}
+		count++
+	}
+	return count, nil
+}
+
+// Purge 清空指定类型就绪队列中等待执行的任务，返回清理的任务数
+func (m *Manager) Purge(ctx context.Context, jobType string) (int, error) {
+	ids, err := m.rc.Client().LRange(ctx, m.keys.queue(jobType), 0, -1).Result()
+	if err != nil {
+		return 0, fmt.Errorf("failed to list pending jobs: %w", err)
+	}
+	if err := m.deleteJobs(ctx, ids, m.keys.queue(jobType)); err != nil {
+		return 0, err
+	}
+	return len(ids), nil
+}
+
+// PurgeFailed 删除所有失败任务，返回删除的任务数
+func (m *Manager) PurgeFailed(ctx context.Context) (int, error) {
+	ids, err := m.rc.Client().ZRange(ctx, m.keys.failed(), 0, -1).Result()
+	if err != nil {
+		return 0, fmt.Errorf("failed to list failed jobs: %w", err)
+	}
+	if err := m.deleteJobs(ctx, ids, m.keys.failed()); err != nil {
+		return 0, err
+	}
+	return len(ids), nil
+}
+
+// deleteJobs 删除任务详情、唯一键及所在的集合
+func (m *Manager) deleteJobs(ctx context.Context, ids []string, container string) error {
+	pipe := m.rc.Client().Pipeline()
+	uniques := make([]*goredis.StringCmd, len(ids))
+	for i, id := range ids {
+		uniques[i] = pipe.HGet(ctx, m.keys.job(id), "unique")
+	}
+	if _, err := pipe.Exec(ctx); err != nil && !errors.Is(err, goredis.Nil) {
+		return fmt.Errorf("failed to load jobs: %w", err)
+	}
+
+	_, err := m.rc.Client().TxPipelined(ctx, func(pipe goredis.Pipeliner) error {
+		for i, id := range ids {
+			pipe.Del(ctx, m.keys.job(id))
+			if u := uniques[i].Val(); u != "" {
+				pipe.Del(ctx, u)
+			}
+		}
+		pipe.Del(ctx, container)
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to purge jobs: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,274 @@
+// Package jobs 提供基于 Redis 持久化的后台任务框架，支持延迟执行、失败重试和唯一任务。
+package jobs
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/ixxmi/tools/cache/redis"
+	goredis "github.com/redis/go-redis/v9"
+)
+
+// ErrDuplicate 唯一任务已存在
+var ErrDuplicate = errors.New("jobs: duplicate unique job")
+
+// ErrNotFound 任务不存在
+var ErrNotFound = errors.New("jobs: job not found")
+
+// Config 配置结构
+type Config struct {
+	Namespace    string        // 键前缀，默认 jobs，同一 Redis 上的多个应用应使用不同前缀
+	Concurrency  int           // 工作协程数，默认 10
+	PollInterval time.Duration // 队列为空时的轮询间隔和延迟任务调度间隔，默认 1s
+	MaxRetries   int           // 默认最大重试次数，默认 3，小于0不重试
+	RetryBackoff time.Duration // 首次重试等待时间，之后按指数增长，默认 10s
+	RetryMaxWait time.Duration // 重试等待时间上限，默认 10min
+	Timeout      time.Duration // 单个任务执行超时时间，默认 5min，超时未确认的任务会被重新调度
+}
+
+// Job 任务
+type Job struct {
+	ID         string
+	Type       string
+	Payload    []byte
+	Attempts   int       // 已执行次数
+	MaxRetries int       // 最大重试次数
+	CreatedAt  time.Time // 入队时间
+	FailedAt   time.Time // 最终失败时间，仅失败任务有值
+	LastError  string    // 最近一次执行的错误
+	unique     string
+}
+
+// Bind 将任务负载解析到dest
+func (j *Job) Bind(dest interface{}) error {
+	if err := json.Unmarshal(j.Payload, dest); err != nil {
+		return fmt.Errorf("failed to decode job payload: %w", err)
+	}
+	return nil
+}
+
+// Handler 任务处理函数，返回错误时按重试策略重新调度
+type Handler func(ctx context.Context, job *Job) error
+
+// Manager 任务管理器，负责入队、执行和查询
+type Manager struct {
+	rc       *redis.RedisClient
+	config   Config
+	keys     keys
+	mu       sync.RWMutex
+	handlers map[string]Handler
+	types    []string
+	cancel   context.CancelFunc
+	wg       sync.WaitGroup
+	OnError  func(job *Job, err error) // 任务执行失败回调
+}
+
+// NewManager 创建任务管理器
+func NewManager(rc *redis.RedisClient, config Config) *Manager {
+	if config.Namespace == "" {
+		config.Namespace = "jobs"
+	}
+	if config.Concurrency <= 0 {
+		config.Concurrency = 10
+	}
+	if config.PollInterval <= 0 {
+		config.PollInterval = time.Second
+	}
+	if config.MaxRetries == 0 {
+		config.MaxRetries = 3
+	}
+	if config.MaxRetries < 0 {
+		config.MaxRetries = 0
+	}
+	if config.RetryBackoff <= 0 {
+		config.RetryBackoff = 10 * time.Second
+	}
+	if config.RetryMaxWait <= 0 {
+		config.RetryMaxWait = 10 * time.Minute
+	}
+	if config.Timeout <= 0 {
+		config.Timeout = 5 * time.Minute
+	}
+	return &Manager{
+		rc:       rc,
+		config:   config,
+		keys:     newKeys(config.Namespace),
+		handlers: make(map[string]Handler),
+	}
+}
+
+// Register 注册任务类型的处理函数，需在 Start 之前调用
+func (m *Manager) Register(jobType string, handler Handler) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.handlers[jobType]; !ok {
+		m.types = append(m.types, jobType)
+	}
+	m.handlers[jobType] = handler
+}
+
+// enqueueOptions 入队选项
+type enqueueOptions struct {
+	runAt      time.Time
+	maxRetries *int
+	unique     string
+	uniqueTTL  time.Duration
+}
+
+// Option 入队选项函数
+type Option func(*enqueueOptions)
+
+// WithDelay 延迟d后执行
+func WithDelay(d time.Duration) Option {
+	return func(o *enqueueOptions) {
+		o.runAt = time.Now().Add(d)
+	}
+}
+
+// WithRunAt 在指定时间执行
+func WithRunAt(t time.Time) Option {
+	return func(o *enqueueOptions) {
+		o.runAt = t
+	}
+}
+
+// WithMaxRetries 设置该任务的最大重试次数，覆盖 Config.MaxRetries
+func WithMaxRetries(n int) Option {
+	return func(o *enqueueOptions) {
+		o.maxRetries = &n
+	}
+}
+
+// WithUnique 唯一任务，同类型同key的任务在完成、最终失败或ttl过期前只能入队一次
+func WithUnique(key string, ttl time.Duration) Option {
+	return func(o *enqueueOptions) {
+		o.unique = key
+		o.uniqueTTL = ttl
+	}
+}
+
+// Enqueue 入队任务，payload 按JSON编码（[]byte 原样保存），返回任务ID
+func (m *Manager) Enqueue(ctx context.Context, jobType string, payload interface{}, opts ...Option) (string, error) {
+	o := enqueueOptions{}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	var data []byte
+	switch v := payload.(type) {
+	case []byte:
+		data = v
+	case nil:
+	default:
+		var err error
+		if data, err = json.Marshal(v); err != nil {
+			return "", fmt.Errorf("failed to marshal job payload: %w", err)
+		}
+	}
+
+	maxRetries := m.config.MaxRetries
+	if o.maxRetries != nil {
+		maxRetries = *o.maxRetries
+	}
+	id := newID()
+
+	uniqueKey := ""
+	if o.unique != "" {
+		uniqueKey = m.keys.unique(jobType, o.unique)
+		ttl := o.uniqueTTL
+		if ttl <= 0 {
+			ttl = 24 * time.Hour
+		}
+		ok, err := m.rc.Client().SetNX(ctx, uniqueKey, id, ttl).Result()
+		if err != nil {
+			return "", fmt.Errorf("failed to acquire unique key: %w", err)
+		}
+		if !ok {
+			return "", ErrDuplicate
+		}
+	}
+
+	now := time.Now()
+	_, err := m.rc.Client().TxPipelined(ctx, func(pipe goredis.Pipeliner) error {
+		pipe.HSet(ctx, m.keys.job(id), map[string]interface{}{
+			"type":        jobType,
+			"payload":     data,
+			"attempts":    0,
+			"max_retries": maxRetries,
+			"created_at":  now.UnixMilli(),
+			"unique":      uniqueKey,
+		})
+		pipe.SAdd(ctx, m.keys.types(), jobType)
+		if o.runAt.After(now) {
+			pipe.ZAdd(ctx, m.keys.scheduled(), goredis.Z{Score: float64(o.runAt.UnixMilli()), Member: id})
+		} else {
+			pipe.LPush(ctx, m.keys.queue(jobType), id)
+		}
+		return nil
+	})
+	if err != nil {
+		if uniqueKey != "" {
+			m.rc.Client().Del(ctx, uniqueKey)
+		}
+		return "", fmt.Errorf("failed to enqueue job: %w", err)
+	}
+	return id, nil
+}
+
+// getJob 读取任务详情
+func (m *Manager) getJob(ctx context.Context, id string) (*Job, error) {
+	fields, err := m.rc.Client().HGetAll(ctx, m.keys.job(id)).Result()
+	if err != nil {
+		return nil, err
+	}
+	if len(fields) == 0 {
+		return nil, ErrNotFound
+	}
+	job := &Job{
+		ID:        id,
+		Type:      fields["type"],
+		Payload:   []byte(fields["payload"]),
+		LastError: fields["error"],
+		unique:    fields["unique"],
+	}
+	job.Attempts, _ = strconv.Atoi(fields["attempts"])
+	job.MaxRetries, _ = strconv.Atoi(fields["max_retries"])
+	if ms, err := strconv.ParseInt(fields["created_at"], 10, 64); err == nil {
+		job.CreatedAt = time.UnixMilli(ms)
+	}
+	if ms, err := strconv.ParseInt(fields["failed_at"], 10, 64); err == nil {
+		job.FailedAt = time.UnixMilli(ms)
+	}
+	return job, nil
+}
+
+// keys Redis 键，使用 {namespace} 哈希标签保证集群模式下落在同一槽位以支持事务和脚本
+type keys struct {
+	prefix string
+}
+
+func newKeys(namespace string) keys {
+	return keys{prefix: "{" + namespace + "}:"}
+}
+
+func (k keys) job(id string) string              { return k.prefix + "job:" + id }
+func (k keys) queue(jobType string) string       { return k.prefix + "queue:" + jobType }
+func (k keys) unique(jobType, key string) string { return k.prefix + "unique:" + jobType + ":" + key }
+func (k keys) scheduled() string                 { return k.prefix + "scheduled" }
+func (k keys) processing() string                { return k.prefix + "processing" }
+func (k keys) failed() string                    { return k.prefix + "failed" }
+func (k keys) types() string                     { return k.prefix + "types" }
+
+// newID 生成随机任务ID
+func newID() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,295 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	goredis "github.com/redis/go-redis/v9"
+)
+
+// promoteBatch 每次调度转移的到期任务数
+const promoteBatch = 100
+
+// processingGrace 任务超时后再等待多久才视为工作协程已退出并重新调度
+const processingGrace = 30 * time.Second
+
+// dequeueScript 从就绪队列取出任务并记入执行中集合，同时累加执行次数
+var dequeueScript = goredis.NewScript(`
+local id = redis.call('RPOP', KEYS[1])
+if not id then
+	return false
+end
+redis.call('ZADD', KEYS[2], ARGV[1], id)
+redis.call('HINCRBY', ARGV[2] .. 'job:' .. id, 'attempts', 1)
+return id
+`)
+
+// promoteScript 将到期的延迟/重试任务转移到对应类型的就绪队列
+var promoteScript = goredis.NewScript(`
+local ids = redis.call('ZRANGEBYSCORE', KEYS[1], '-inf', ARGV[1], 'LIMIT', 0, ARGV[3])
+for _, id in ipairs(ids) do
+	redis.call('ZREM', KEYS[1], id)
+	local t = redis.call('HGET', ARGV[2] .. 'job:' .. id, 'type')
+	if t then
+		redis.call('LPUSH', ARGV[2] .. 'queue:' .. t, id)
+	end
+end
+return #ids
+`)
+
+// recoverScript 将执行超时未确认的任务重新放回延迟集合
+var recoverScript = goredis.NewScript(`
+local ids = redis.call('ZRANGEBYSCORE', KEYS[1], '-inf', ARGV[1], 'LIMIT', 0, ARGV[2])
+for _, id in ipairs(ids) do
+	redis.call('ZREM', KEYS[1], id)
+	redis.call('ZADD', KEYS[2], ARGV[1], id)
+end
+return #ids
+`)
+
+// Start 启动调度协程和工作协程，ctx 取消或调用 Shutdown 后停止取新任务
+func (m *Manager) Start(ctx context.Context) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.cancel != nil {
+		return fmt.Errorf("jobs: manager already started")
+	}
+	if len(m.types) == 0 {
+		return fmt.Errorf("jobs: no handler registered")
+	}
+
+	ctx, m.cancel = context.WithCancel(ctx)
+	types := append([]string{}, m.types...)
+
+	m.wg.Add(1)
+	go m.schedule(ctx)
+	for i := 0; i < m.config.Concurrency; i++ {
+		m.wg.Add(1)
+		go m.work(ctx, types, i)
+	}
+	return nil
+}
+
+// Shutdown 停止取新任务并等待执行中的任务完成，ctx 超时后直接返回，未完成的任务将在超时后被重新调度
+func (m *Manager) Shutdown(ctx context.Context) error {
+	m.mu.Lock()
+	cancel := m.cancel
+	m.mu.Unlock()
+	if cancel == nil {
+		return nil
+	}
+	cancel()
+
+	done := make(chan struct{})
+	go func() {
+		m.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// schedule 定时转移到期任务并回收超时任务
+func (m *Manager) schedule(ctx context.Context) {
+	defer m.wg.Done()
+	ticker := time.NewTicker(m.config.PollInterval)
+	defer ticker.Stop()
+	for {
+		if err := m.promote(ctx); err != nil && ctx.Err() == nil {
+			m.reportError(nil, fmt.Errorf("failed to promote scheduled jobs: %w", err))
+		}
+		if err := m.recoverStale(ctx); err != nil && ctx.Err() == nil {
+			m.reportError(nil, fmt.Errorf("failed to recover stale jobs: %w", err))
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// promote 转移所有到期任务
+func (m *Manager) promote(ctx context.Context) error {
+	for {
+		n, err := promoteScript.Run(ctx, m.rc.Client(),
+			[]string{m.keys.scheduled()},
+			time.Now().UnixMilli(), m.keys.prefix, promoteBatch,
+		).Int()
+		if err != nil || n < promoteBatch {
+			return err
+		}
+	}
+}
+
+// recoverStale 回收执行超时的任务
+func (m *Manager) recoverStale(ctx context.Context) error {
+	return recoverScript.Run(ctx, m.rc.Client(),
+		[]string{m.keys.processing(), m.keys.scheduled()},
+		time.Now().UnixMilli(), promoteBatch,
+	).Err()
+}
+
+// work 工作协程，按轮询顺序从各类型队列取任务执行
+func (m *Manager) work(ctx context.Context, types []string, offset int) {
+	defer m.wg.Done()
+	for ctx.Err() == nil {
+		job, err := m.dequeue(ctx, types, offset)
+		offset++
+		if err != nil {
+			if ctx.Err() == nil {
+				m.reportError(nil, fmt.Errorf("failed to dequeue job: %w", err))
+			}
+			sleep(ctx, m.config.PollInterval)
+			continue
+		}
+		if job == nil {
+			sleep(ctx, m.config.PollInterval)
+			continue
+		}
+		m.process(ctx, job)
+	}
+}
+
+// dequeue 依次尝试各类型队列，全部为空时返回nil
+func (m *Manager) dequeue(ctx context.Context, types []string, offset int) (*Job, error) {
+	deadline := time.Now().Add(m.config.Timeout + processingGrace).UnixMilli()
+	for i := range types {
+		jobType := types[(offset+i)%len(types)]
+		id, err := dequeueScript.Run(ctx, m.rc.Client(),
+			[]string{m.keys.queue(jobType), m.keys.processing()},
+			deadline, m.keys.prefix,
+		).Text()
+		if errors.Is(err, goredis.Nil) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		job, err := m.getJob(ctx, id)
+		if errors.Is(err, ErrNotFound) {
+			// 任务已被清理
+			m.rc.Client().ZRem(ctx, m.keys.processing(), id)
+			continue
+		}
+		return job, err
+	}
+	return nil, nil
+}
+
+// process 执行任务并根据结果确认、重试或标记失败
+func (m *Manager) process(ctx context.Context, job *Job) {
+	m.mu.RLock()
+	handler := m.handlers[job.Type]
+	m.mu.RUnlock()
+
+	// 停止时让执行中的任务继续完成，仅受任务超时限制
+	bg := context.WithoutCancel(ctx)
+	jobCtx, cancel := context.WithTimeout(bg, m.config.Timeout)
+	err := run(jobCtx, handler, job)
+	cancel()
+
+	if err == nil {
+		err = m.complete(bg, job)
+		if err != nil {
+			m.reportError(job, fmt.Errorf("failed to ack job: %w", err))
+		}
+		return
+	}
+
+	m.reportError(job, err)
+	if err := m.fail(bg, job, err); err != nil {
+		m.reportError(job, fmt.Errorf("failed to record job failure: %w", err))
+	}
+}
+
+// run 调用处理函数并捕获panic
+func run(ctx context.Context, handler Handler, job *Job) (err error) {
+	if handler == nil {
+		return fmt.Errorf("no handler for job type %s", job.Type)
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("job panic: %v", r)
+		}
+	}()
+	return handler(ctx, job)
+}
+
+// complete 确认任务完成并删除
+func (m *Manager) complete(ctx context.Context, job *Job) error {
+	_, err := m.rc.Client().TxPipelined(ctx, func(pipe goredis.Pipeliner) error {
+		pipe.ZRem(ctx, m.keys.processing(), job.ID)
+		pipe.Del(ctx, m.keys.job(job.ID))
+		if job.unique != "" {
+			pipe.Del(ctx, job.unique)
+		}
+		return nil
+	})
+	return err
+}
+
+// fail 记录失败，未超过重试次数时按退避时间重新调度，否则移入失败集合
+func (m *Manager) fail(ctx context.Context, job *Job, cause error) error {
+	job.LastError = cause.Error()
+	now := time.Now()
+	_, err := m.rc.Client().TxPipelined(ctx, func(pipe goredis.Pipeliner) error {
+		pipe.ZRem(ctx, m.keys.processing(), job.ID)
+		if job.Attempts <= job.MaxRetries {
+			runAt := now.Add(m.backoff(job.Attempts))
+			pipe.HSet(ctx, m.keys.job(job.ID), "error", job.LastError)
+			pipe.ZAdd(ctx, m.keys.scheduled(), goredis.Z{Score: float64(runAt.UnixMilli()), Member: job.ID})
+			return nil
+		}
+		pipe.HSet(ctx, m.keys.job(job.ID), "error", job.LastError, "failed_at", now.UnixMilli())
+		pipe.ZAdd(ctx, m.keys.failed(), goredis.Z{Score: float64(now.UnixMilli()), Member: job.ID})
+		if job.unique != "" {
+			pipe.Del(ctx, job.unique)
+		}
+		return nil
+	})
+	return err
+}
+
+// backoff 计算第attempts次失败后的重试等待时间
+func (m *Manager) backoff(attempts int) time.Duration {
+	if attempts < 1 {
+		attempts = 1
+	}
+	wait := m.config.RetryBackoff << uint(attempts-1)
+	if wait <= 0 || wait > m.config.RetryMaxWait {
+		wait = m.config.RetryMaxWait
+	}
+	return wait
+}
+
+// reportError 报告错误，未设置 OnError 时写入标准日志
+func (m *Manager) reportError(job *Job, err error) {
+	if m.OnError != nil {
+		m.OnError(job, err)
+		return
+	}
+	if job != nil {
+		log.Printf("任务 %s(%s) 第%d次执行失败: %v", job.Type, job.ID, job.Attempts, err)
+		return
+	}
+	log.Printf("任务调度错误: %v", err)
+}
+
+// sleep 等待d或ctx取消
+func sleep(ctx context.Context, d time.Duration) {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+	case <-t.C:
+	}
+}
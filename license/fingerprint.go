@@ -0,0 +1,65 @@
+package license
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net"
+	"os"
+	"sort"
+	"strings"
+)
+
+// machineIDFiles 机器ID文件，系统安装时生成，重启后保持不变
+var machineIDFiles = []string{"/etc/machine-id", "/var/lib/dbus/machine-id"}
+
+// Fingerprint 计算机器指纹：机器ID和物理网卡MAC地址的 SHA-256 摘要
+func Fingerprint() (string, error) {
+	var parts []string
+	for _, file := range machineIDFiles {
+		if data, err := os.ReadFile(file); err == nil {
+			if id := strings.TrimSpace(string(data)); id != "" {
+				parts = append(parts, id)
+				break
+			}
+		}
+	}
+
+	macs, err := hardwareAddrs()
+	if err != nil {
+		return "", err
+	}
+	parts = append(parts, macs...)
+
+	sum := sha256.Sum256([]byte(strings.Join(parts, "|")))
+	return hex.EncodeToString(sum[:16]), nil
+}
+
+// hardwareAddrs 获取物理网卡MAC地址并排序，忽略回环和虚拟网卡
+func hardwareAddrs() ([]string, error) {
+	ifaces, err := net.Interfaces()
+	if err != nil {
+		return nil, err
+	}
+	var macs []string
+	for _, iface := range ifaces {
+		if iface.Flags&net.FlagLoopback != 0 || len(iface.HardwareAddr) == 0 {
+			continue
+		}
+		if isVirtual(iface.Name) {
+			continue
+		}
+		macs = append(macs, iface.HardwareAddr.String())
+	}
+	sort.Strings(macs)
+	return macs, nil
+}
+
+// isVirtual 判断是否为容器或虚拟化产生的网卡，这些网卡的MAC地址会随重启变化
+func isVirtual(name string) bool {
+	for _, prefix := range []string{"docker", "veth", "br-", "virbr", "vnet", "tun", "tap", "cni", "flannel", "cali", "kube", "lxc"} {
+		if strings.HasPrefix(name, prefix) {
+			return true
+		}
+	}
+	return false
+}
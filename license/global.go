@@ -0,0 +1,91 @@
+package license
+
+import (
+	"sync"
+	"time"
+
+	"github.com/ixxmi/tools/utils"
+)
+
+// Config 启动时加载许可证的配置
+type Config struct {
+	Path            string // 许可证文件路径，默认 utils.LicenseLoginFileName
+	PublicKey       string // base64编码的 ed25519 公钥
+	CipherKey       string // SimpleEncrypt 加密key
+	SkipFingerprint bool   // 跳过机器指纹校验，用于开发环境
+}
+
+var (
+	mu      sync.RWMutex
+	current *License
+	lastErr error
+)
+
+// Load 加载并校验许可证，成功后作为全局许可证供 Allowed 等函数使用；失败时清空全局许可证
+func Load(config Config) (*License, error) {
+	if config.Path == "" {
+		config.Path = utils.LicenseLoginFileName
+	}
+	lic, err := load(config)
+
+	mu.Lock()
+	defer mu.Unlock()
+	current, lastErr = lic, err
+	return lic, err
+}
+
+// load 解析并校验许可证
+func load(config Config) (*License, error) {
+	lic, err := ParseFile(config.Path, config.PublicKey, config.CipherKey)
+	if err != nil {
+		return nil, err
+	}
+	fingerprint := ""
+	if !config.SkipFingerprint {
+		if fingerprint, err = Fingerprint(); err != nil {
+			return nil, err
+		}
+	}
+	if err := lic.Validate(time.Now(), fingerprint); err != nil {
+		return nil, err
+	}
+	return lic, nil
+}
+
+// Current 返回当前加载的许可证，未加载或校验失败时返回nil
+func Current() *License {
+	mu.RLock()
+	defer mu.RUnlock()
+	return current
+}
+
+// Err 返回当前许可证不可用的原因，可用时返回nil
+func Err() error {
+	mu.RLock()
+	defer mu.RUnlock()
+	if current == nil {
+		if lastErr == nil {
+			return ErrInvalid
+		}
+		return lastErr
+	}
+	// 指纹已在加载时校验，这里只检查运行期间是否过期
+	return current.Validate(time.Now(), "")
+}
+
+// Valid 当前许可证是否可用（含宽限期）
+func Valid() bool {
+	return Err() == nil
+}
+
+// InGrace 当前许可证是否处于过期宽限期
+func InGrace() bool {
+	lic := Current()
+	return lic != nil && lic.InGrace(time.Now())
+}
+
+// Allowed 当前许可证是否可用且授权了指定模块
+func Allowed(module string) bool {
+	lic := Current()
+	return lic != nil && Valid() && lic.HasModule(module)
+}
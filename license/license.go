@@ -0,0 +1,172 @@
+// Package license 提供许可证的签发、解析校验和模块授权检查。
+//
+// 许可证内容以 JSON 序列化后使用 ed25519 私钥签名，签名和内容再经 encrypt.SimpleEncrypt 加密后写入文件；
+// 运行时使用内置的公钥校验签名，私钥只保存在签发端。
+package license
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/ixxmi/tools/encrypt"
+)
+
+// AllModules 授权全部模块
+const AllModules = "*"
+
+// 校验错误
+var (
+	ErrInvalid     = errors.New("license: invalid license file")
+	ErrSignature   = errors.New("license: signature verification failed")
+	ErrFingerprint = errors.New("license: machine fingerprint mismatch")
+	ErrNotYetValid = errors.New("license: license is not yet valid")
+	ErrExpired     = errors.New("license: license expired")
+)
+
+// License 许可证内容
+type License struct {
+	ID          string            `json:"id"`
+	Customer    string            `json:"customer"`
+	Fingerprint string            `json:"fingerprint"` // 机器指纹，为空时不绑定机器
+	Modules     []string          `json:"modules"`     // 授权模块，包含 * 时授权全部模块
+	IssuedAt    time.Time         `json:"issued_at"`
+	NotBefore   time.Time         `json:"not_before"` // 生效时间，为零值时立即生效
+	ExpiresAt   time.Time         `json:"expires_at"` // 过期时间，为零值时永久有效
+	GraceDays   int               `json:"grace_days"` // 过期后的宽限天数，宽限期内仍可使用但应提示续期
+	Limits      map[string]int64  `json:"limits"`     // 数量限制，如最大设备数
+	Extra       map[string]string `json:"extra"`
+}
+
+// envelope 许可证文件结构
+type envelope struct {
+	Data      string `json:"data"`
+	Signature string `json:"sig"`
+}
+
+// Generate 签发许可证，privateKey 为base64编码的 ed25519 私钥，cipherKey 为 SimpleEncrypt 加密key
+func Generate(lic *License, privateKey, cipherKey string) ([]byte, error) {
+	if lic.IssuedAt.IsZero() {
+		lic.IssuedAt = time.Now()
+	}
+	data, err := json.Marshal(lic)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal license: %w", err)
+	}
+	sig, err := encrypt.Sign(privateKey, data)
+	if err != nil {
+		return nil, err
+	}
+	env, err := json.Marshal(envelope{
+		Data:      base64.StdEncoding.EncodeToString(data),
+		Signature: base64.StdEncoding.EncodeToString(sig),
+	})
+	if err != nil {
+		return nil, err
+	}
+	cipher := &encrypt.SimpleEncrypt{Key: cipherKey}
+	return []byte(cipher.EncodeStringByCheck(string(env))), nil
+}
+
+// GenerateFile 签发许可证并写入文件
+func GenerateFile(filename string, lic *License, privateKey, cipherKey string) error {
+	data, err := Generate(lic, privateKey, cipherKey)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filename, data, 0o644)
+}
+
+// Parse 解密并校验许可证签名，不检查有效期和机器指纹
+func Parse(content []byte, publicKey, cipherKey string) (*License, error) {
+	cipher := &encrypt.SimpleEncrypt{Key: cipherKey}
+	plain := cipher.DecodeStringByCheck(string(bytes.TrimSpace(content)))
+	if plain == "" {
+		return nil, ErrInvalid
+	}
+	var env envelope
+	if err := json.Unmarshal([]byte(plain), &env); err != nil {
+		return nil, ErrInvalid
+	}
+	data, err := base64.StdEncoding.DecodeString(env.Data)
+	if err != nil {
+		return nil, ErrInvalid
+	}
+	sig, err := base64.StdEncoding.DecodeString(env.Signature)
+	if err != nil {
+		return nil, ErrInvalid
+	}
+	if !encrypt.Verify(publicKey, data, sig) {
+		return nil, ErrSignature
+	}
+
+	lic := &License{}
+	if err := json.Unmarshal(data, lic); err != nil {
+		return nil, ErrInvalid
+	}
+	return lic, nil
+}
+
+// ParseFile 读取并解析许可证文件
+func ParseFile(filename, publicKey, cipherKey string) (*License, error) {
+	content, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	return Parse(content, publicKey, cipherKey)
+}
+
+// Validate 校验有效期和机器指纹，fingerprint 为空时跳过指纹校验；处于宽限期时返回nil，可通过 InGrace 判断
+func (l *License) Validate(now time.Time, fingerprint string) error {
+	if l.Fingerprint != "" && fingerprint != "" && l.Fingerprint != fingerprint {
+		return ErrFingerprint
+	}
+	if !l.NotBefore.IsZero() && now.Before(l.NotBefore) {
+		return ErrNotYetValid
+	}
+	if !l.ExpiresAt.IsZero() && now.After(l.GraceEnd()) {
+		return ErrExpired
+	}
+	return nil
+}
+
+// GraceEnd 宽限期结束时间
+func (l *License) GraceEnd() time.Time {
+	return l.ExpiresAt.AddDate(0, 0, l.GraceDays)
+}
+
+// InGrace 是否已过期但处于宽限期内
+func (l *License) InGrace(now time.Time) bool {
+	return !l.ExpiresAt.IsZero() && now.After(l.ExpiresAt) && !now.After(l.GraceEnd())
+}
+
+// Remaining 距离过期的剩余时间，永久有效时返回-1
+func (l *License) Remaining(now time.Time) time.Duration {
+	if l.ExpiresAt.IsZero() {
+		return -1
+	}
+	if d := l.ExpiresAt.Sub(now); d > 0 {
+		return d
+	}
+	return 0
+}
+
+// HasModule 是否授权指定模块
+func (l *License) HasModule(module string) bool {
+	for _, m := range l.Modules {
+		if m == module || m == AllModules {
+			return true
+		}
+	}
+	return false
+}
+
+// Limit 获取数量限制，未设置时返回 0, false
+func (l *License) Limit(name string) (int64, bool) {
+	v, ok := l.Limits[name]
+	return v, ok
+}
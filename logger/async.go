@@ -0,0 +1,233 @@
+package logger
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// OverflowPolicy 异步模式下缓冲区满时的处理方式
+type OverflowPolicy uint8
+
+const (
+	// OverflowBlock 阻塞等待后台写入腾出空间，不丢日志
+	OverflowBlock OverflowPolicy = iota
+	// OverflowDropOldest 丢弃缓冲区中最早的一条日志
+	OverflowDropOldest
+	// OverflowDropNew 丢弃当前这条日志
+	OverflowDropNew
+)
+
+// AsyncConfig 异步写入配置
+type AsyncConfig struct {
+	BufferSize    int            // 缓冲的日志条数，默认4096
+	Overflow      OverflowPolicy // 缓冲区满时的处理方式，默认 OverflowBlock
+	FlushInterval time.Duration  // 定期刷新输出的间隔，默认1秒
+}
+
+// ErrClosed Logger 已关闭
+var ErrClosed = errors.New("logger: closed")
+
+// asyncItem 待写入的日志，flush 不为 nil 时为刷新请求
+type asyncItem struct {
+	data  []byte
+	flush chan error
+}
+
+// asyncWriter 后台写入协程，日志经有界 channel 按顺序写入带缓冲的输出
+type asyncWriter struct {
+	l        *Logger
+	policy   OverflowPolicy
+	ch       chan asyncItem
+	dropped  atomic.Uint64
+	closeMu  sync.RWMutex
+	closed   bool
+	finished chan struct{}
+}
+
+// WithAsync 开启异步写入：日志格式化后放入有界缓冲区，由后台协程写入输出，写入耗时不再阻塞调用方。
+// 程序退出前需调用 Close 或 Flush，否则缓冲区中的日志会丢失；Fatal 级别日志在退出前会自动刷新
+func WithAsync(config AsyncConfig) Option {
+	return func(l *Logger) {
+		l.startAsync(config)
+	}
+}
+
+// SetAsync 为默认 logger 开启异步写入，见 WithAsync
+func SetAsync(config AsyncConfig) {
+	defaultLogger.startAsync(config)
+}
+
+// Flush 等待已提交的日志全部写入默认 logger 的输出
+func Flush() error {
+	return defaultLogger.Flush()
+}
+
+func (l *Logger) startAsync(config AsyncConfig) {
+	if config.BufferSize <= 0 {
+		config.BufferSize = 4096
+	}
+	if config.FlushInterval <= 0 {
+		config.FlushInterval = time.Second
+	}
+	w := &asyncWriter{
+		l:        l,
+		policy:   config.Overflow,
+		ch:       make(chan asyncItem, config.BufferSize),
+		finished: make(chan struct{}),
+	}
+	l.mu.Lock()
+	old := l.async
+	l.async = w
+	l.mu.Unlock()
+	if old != nil {
+		old.close()
+	}
+	go w.run(config.FlushInterval)
+}
+
+// Flush 等待已提交的日志全部写入输出，未开启异步写入时直接返回
+func (l *Logger) Flush() error {
+	l.mu.Lock()
+	w := l.async
+	l.mu.Unlock()
+	if w == nil {
+		return nil
+	}
+	return w.flush()
+}
+
+// Close 刷新并停止异步写入，之后的日志同步写入；输出实现了 io.Closer 时不会关闭，由创建方负责
+func (l *Logger) Close() error {
+	l.mu.Lock()
+	w := l.async
+	l.async = nil
+	l.mu.Unlock()
+	if w == nil {
+		return nil
+	}
+	return w.close()
+}
+
+// Dropped 异步模式下因缓冲区满而丢弃的日志条数
+func (l *Logger) Dropped() uint64 {
+	l.mu.Lock()
+	w := l.async
+	l.mu.Unlock()
+	if w == nil {
+		return 0
+	}
+	return w.dropped.Load()
+}
+
+// write 按溢出策略放入缓冲区，返回 false 表示已关闭
+func (w *asyncWriter) write(data []byte) bool {
+	w.closeMu.RLock()
+	defer w.closeMu.RUnlock()
+	if w.closed {
+		return false
+	}
+	item := asyncItem{data: data}
+	switch w.policy {
+	case OverflowDropNew:
+		select {
+		case w.ch <- item:
+		default:
+			w.dropped.Add(1)
+		}
+	case OverflowDropOldest:
+		for {
+			select {
+			case w.ch <- item:
+				return true
+			default:
+			}
+			select {
+			case old := <-w.ch:
+				// 刷新请求不能丢弃，否则 Flush 调用方会一直阻塞，直接完成它
+				if old.flush != nil {
+					old.flush <- nil
+					continue
+				}
+				w.dropped.Add(1)
+			default:
+			}
+		}
+	default:
+		w.ch <- item
+	}
+	return true
+}
+
+// flush 在队列中插入刷新请求并等待写入协程处理到该位置
+func (w *asyncWriter) flush() error {
+	w.closeMu.RLock()
+	if w.closed {
+		w.closeMu.RUnlock()
+		return ErrClosed
+	}
+	done := make(chan error, 1)
+	w.ch <- asyncItem{flush: done}
+	w.closeMu.RUnlock()
+	return <-done
+}
+
+// close 停止接收日志，等待缓冲区写完并刷新
+func (w *asyncWriter) close() error {
+	w.closeMu.Lock()
+	if w.closed {
+		w.closeMu.Unlock()
+		return ErrClosed
+	}
+	w.closed = true
+	close(w.ch)
+	w.closeMu.Unlock()
+	<-w.finished
+	return nil
+}
+
+// run 后台写入循环，输出在 Logger 上被替换时切换到新的输出
+func (w *asyncWriter) run(interval time.Duration) {
+	defer close(w.finished)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	out := w.l.output()
+	buf := bufio.NewWriterSize(out, 32*1024)
+	flush := func() error {
+		err := buf.Flush()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "写入日志失败: %v\n", err)
+			buf.Reset(out)
+		}
+		return err
+	}
+	for {
+		select {
+		case item, ok := <-w.ch:
+			if !ok {
+				flush()
+				return
+			}
+			if item.flush != nil {
+				item.flush <- flush()
+				continue
+			}
+			if next := w.l.output(); next != out {
+				flush()
+				out = next
+				buf.Reset(out)
+			}
+			if _, err := buf.Write(item.data); err != nil {
+				fmt.Fprintf(os.Stderr, "写入日志失败: %v\n", err)
+				buf.Reset(out)
+			}
+		case <-ticker.C:
+			flush()
+		}
+	}
+}
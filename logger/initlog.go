@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"time"
 )
 
 type logger struct {
@@ -11,13 +12,23 @@ type logger struct {
 	FilePath   string
 	MaxSizeMB  int
 	MaxBackups int
+	MaxAgeDays int            // 旧日志保留天数，0 表示不限制
+	Interval   RotateInterval // 按天或按小时轮转
+	Compress   bool           // 压缩旧日志
 }
 
 // initGlobalLogger 封装了创建和设置全局日志记录器的逻辑
 // 它会配置默认的 logger，使其同时输出到控制台和轮转文件
 func InitGlobalLogger(c logger) (io.Closer, error) {
 	// 1. 设置日志轮转
-	logFile, err := NewRotator(c.FilePath, int64(c.MaxSizeMB)*1024*1024, c.MaxBackups)
+	logFile, err := NewRotatorWithConfig(RotatorConfig{
+		Filename:   c.FilePath,
+		MaxSize:    int64(c.MaxSizeMB) * 1024 * 1024,
+		Interval:   c.Interval,
+		MaxBackups: c.MaxBackups,
+		MaxAge:     time.Duration(c.MaxAgeDays) * 24 * time.Hour,
+		Compress:   c.Compress,
+	})
 	if err != nil {
 		return nil, fmt.Errorf("创建日志轮转文件失败: %v", err)
 	}
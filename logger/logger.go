@@ -2,12 +2,16 @@
 package logger
 
 import (
+	"bytes"
+	"encoding/json"
 	"fmt"
 	"io"
 	"os"
 	"runtime"
+	"sort"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
@@ -43,6 +47,23 @@ func (l Level) String() string {
 	}
 }
 
+// ParseLevel 解析日志级别名称（debug/info/warn/warning/error/fatal，不区分大小写），用于从配置读取级别
+func ParseLevel(s string) (Level, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "debug":
+		return DebugLevel, nil
+	case "info":
+		return InfoLevel, nil
+	case "warn", "warning":
+		return WarnLevel, nil
+	case "error":
+		return ErrorLevel, nil
+	case "fatal":
+		return FatalLevel, nil
+	}
+	return InfoLevel, fmt.Errorf("unknown log level %q", s)
+}
+
 // --- 字段和条目 ---
 
 // Fields 是用于结构化日志的键值对类型
@@ -74,6 +95,18 @@ func (e *Entry) WithFields(fields Fields) *Entry {
 	return e
 }
 
+// Log 以指定级别记录日志，用于按变量级别输出
+func (e *Entry) Log(level Level, args ...interface{}) {
+	e.Level = level
+	e.log(args...)
+}
+
+// Logf 以指定级别格式化并记录日志
+func (e *Entry) Logf(level Level, format string, args ...interface{}) {
+	e.Level = level
+	e.logf(format, args...)
+}
+
 // logf 格式化并记录日志
 func (e *Entry) logf(format string, args ...interface{}) {
 	e.Message = fmt.Sprintf(format, args...)
@@ -113,45 +146,130 @@ func (f *TextFormatter) Format(e *Entry) ([]byte, error) {
 	)), nil
 }
 
-// JSONFormatter 将日志格式化为 JSON
-type JSONFormatter struct{}
+// JSONFormatter 将日志格式化为 JSON，每条日志一行。字段按原类型编码，数字、布尔值和嵌套的 map、切片保持原样，
+// error 编码为 Error() 字符串，无法编码的值（如 chan、func）按 %v 输出为字符串。
+// 与核心字段 time/level/message/file/func 同名的字段以 fields. 前缀输出
+type JSONFormatter struct {
+	// TimestampFormat 时间格式，默认 time.RFC3339
+	TimestampFormat string
+	// FieldOrder 优先输出的键，可包含核心字段和自定义字段；其余核心字段按 time、level、message、file、func 输出，
+	// 自定义字段按键名排序
+	FieldOrder []string
+	// PrettyPrint 缩进输出，便于本地调试
+	PrettyPrint bool
+}
+
+// coreKeys 核心字段的默认顺序
+var coreKeys = []string{"time", "level", "message", "file", "func"}
+
+// jsonBufPool 复用格式化缓冲区
+var jsonBufPool = sync.Pool{New: func() interface{} { return new(bytes.Buffer) }}
 
 // Format 实现 Formatter 接口
 func (f *JSONFormatter) Format(e *Entry) ([]byte, error) {
-	// 使用一个 map 来构建 JSON，以避免复杂的字符串拼接
-	data := make(Fields, len(e.Fields)+5)
-	data["time"] = e.Time.Format(time.RFC3339)
+	timestampFormat := f.TimestampFormat
+	if timestampFormat == "" {
+		timestampFormat = time.RFC3339
+	}
+	data := make(map[string]interface{}, len(e.Fields)+len(coreKeys))
+	for k, v := range e.Fields {
+		data[k] = v
+	}
+	for _, k := range coreKeys {
+		if v, ok := data[k]; ok {
+			data["fields."+k] = v
+		}
+	}
+	data["time"] = e.Time.Format(timestampFormat)
 	data["level"] = e.Level.String()
 	data["message"] = e.Message
 	data["file"] = fmt.Sprintf("%s:%d", e.File, e.Line)
 	data["func"] = e.Func
 
-	for k, v := range e.Fields {
-		// 避免覆盖核心字段
-		if _, ok := data[k]; !ok {
-			data[k] = v
+	keys := make([]string, 0, len(data))
+	seen := make(map[string]bool, len(data))
+	for _, list := range [][]string{f.FieldOrder, coreKeys} {
+		for _, k := range list {
+			if _, ok := data[k]; ok && !seen[k] {
+				seen[k] = true
+				keys = append(keys, k)
+			}
+		}
+	}
+	rest := make([]string, 0, len(data)-len(keys))
+	for k := range data {
+		if !seen[k] {
+			rest = append(rest, k)
+		}
+	}
+	sort.Strings(rest)
+	keys = append(keys, rest...)
+
+	buf := jsonBufPool.Get().(*bytes.Buffer)
+	buf.Reset()
+	defer jsonBufPool.Put(buf)
+	enc := json.NewEncoder(buf)
+	enc.SetEscapeHTML(false)
+
+	buf.WriteByte('{')
+	for i, k := range keys {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		if err := enc.Encode(k); err != nil {
+			return nil, err
+		}
+		buf.Truncate(buf.Len() - 1) // Encode 追加的换行
+		buf.WriteByte(':')
+		if err := encodeJSONValue(enc, buf, data[k]); err != nil {
+			return nil, err
 		}
 	}
+	buf.WriteByte('}')
 
-	// 手动序列化为 JSON 字符串，以避免引入额外的依赖
-	var parts []string
-	for k, v := range data {
-		parts = append(parts, fmt.Sprintf("\"%s\":%q", k, fmt.Sprintf("%v", v)))
+	if f.PrettyPrint {
+		var out bytes.Buffer
+		if err := json.Indent(&out, buf.Bytes(), "", "  "); err != nil {
+			return nil, err
+		}
+		out.WriteByte('\n')
+		return out.Bytes(), nil
 	}
+	buf.WriteByte('\n')
+	return append([]byte(nil), buf.Bytes()...), nil
+}
 
-	return []byte("{" + strings.Join(parts, ",") + "}\n"), nil
+// encodeJSONValue 编码单个字段值，编码失败时回退为 %v 字符串
+func encodeJSONValue(enc *json.Encoder, buf *bytes.Buffer, v interface{}) error {
+	if err, ok := v.(error); ok {
+		v = err.Error()
+	}
+	n := buf.Len()
+	if err := enc.Encode(v); err != nil {
+		buf.Truncate(n)
+		if err := enc.Encode(fmt.Sprintf("%v", v)); err != nil {
+			return err
+		}
+	}
+	buf.Truncate(buf.Len() - 1)
+	return nil
 }
 
 // --- Logger ---
 
 // Logger 是日志记录器的核心结构
 type Logger struct {
-	out       io.Writer
-	level     Level
-	formatter Formatter
-	mu        sync.Mutex
+	out        io.Writer
+	level      atomic.Uint32 // Level，支持运行时并发修改
+	callerSkip atomic.Int32  // 额外跳过的调用层数
+	formatter  Formatter
+	async      *asyncWriter // 开启异步写入时非 nil
+	mu         sync.Mutex
 }
 
+// baseCallDepth 从 Logger.log 到调用日志方法处的层数：log <- Entry.log/logf <- Logger.Info 等 <- 调用方
+const baseCallDepth = 3
+
 // Option 是用于配置 Logger 的函数类型
 type Option func(*Logger)
 
@@ -159,9 +277,9 @@ type Option func(*Logger)
 func New(opts ...Option) *Logger {
 	logger := &Logger{
 		out:       os.Stdout,
-		level:     InfoLevel,
 		formatter: &TextFormatter{},
 	}
+	logger.level.Store(uint32(InfoLevel))
 
 	for _, opt := range opts {
 		opt(logger)
@@ -180,10 +298,32 @@ func WithOutput(out io.Writer) Option {
 // WithLevel 设置日志级别
 func WithLevel(level Level) Option {
 	return func(l *Logger) {
-		l.level = level
+		l.level.Store(uint32(level))
+	}
+}
+
+// WithCallerSkip 设置额外跳过的调用层数，封装 logger 的辅助函数每包一层加 1，使 file:line 指向辅助函数的调用方
+func WithCallerSkip(skip int) Option {
+	return func(l *Logger) {
+		l.callerSkip.Store(int32(skip))
 	}
 }
 
+// SetCallerSkip 修改额外跳过的调用层数，见 WithCallerSkip
+func (l *Logger) SetCallerSkip(skip int) {
+	l.callerSkip.Store(int32(skip))
+}
+
+// SetLevel 修改日志级别，可在运行时并发调用（如配置热更新）
+func (l *Logger) SetLevel(level Level) {
+	l.level.Store(uint32(level))
+}
+
+// GetLevel 当前日志级别
+func (l *Logger) GetLevel() Level {
+	return Level(l.level.Load())
+}
+
 // WithFormatter 设置格式化器
 func WithFormatter(formatter Formatter) Option {
 	return func(l *Logger) {
@@ -193,18 +333,15 @@ func WithFormatter(formatter Formatter) Option {
 
 // log 是内部的日志记录方法
 func (l *Logger) log(entry *Entry) {
-	if entry.Level < l.level {
+	if entry.Level < l.GetLevel() {
 		return
 	}
 
-	l.mu.Lock()
-	defer l.mu.Unlock()
-
 	// 获取调用信息
 	if entry.callDepth == 0 {
-		entry.callDepth = 3
+		entry.callDepth = baseCallDepth
 	}
-	pc, file, line, ok := runtime.Caller(entry.callDepth)
+	pc, file, line, ok := runtime.Caller(entry.callDepth + int(l.callerSkip.Load()))
 	if ok {
 		entry.File = getShortPath(file)
 		entry.Line = line
@@ -212,25 +349,50 @@ func (l *Logger) log(entry *Entry) {
 	}
 
 	entry.Time = time.Now()
+
+	l.mu.Lock()
 	bytes, err := l.formatter.Format(entry)
 	if err != nil {
+		l.mu.Unlock()
 		fmt.Fprintf(os.Stderr, "格式化日志失败: %v\n", err)
 		return
 	}
+	async := l.async
+	if async == nil {
+		l.writeLocked(bytes)
+	}
+	l.mu.Unlock()
 
-	_, err = l.out.Write(bytes)
-	if err != nil {
-		fmt.Fprintf(os.Stderr, "写入日志失败: %v\n", err)
+	// 异步写入时在锁外入队，缓冲区满且策略为阻塞时不影响其他协程格式化；已关闭时改为同步写入
+	if async != nil && !async.write(bytes) {
+		l.mu.Lock()
+		l.writeLocked(bytes)
+		l.mu.Unlock()
 	}
 
 	if entry.Level == FatalLevel {
+		l.Flush()
 		os.Exit(1)
 	}
 }
 
+// writeLocked 同步写入输出，调用方需持有 l.mu
+func (l *Logger) writeLocked(p []byte) {
+	if _, err := l.out.Write(p); err != nil {
+		fmt.Fprintf(os.Stderr, "写入日志失败: %v\n", err)
+	}
+}
+
+// output 当前输出目标
+func (l *Logger) output() io.Writer {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.out
+}
+
 // newEntry 创建一个新的日志条目
 func (l *Logger) newEntry() *Entry {
-	return &Entry{Logger: l, Fields: make(Fields), callDepth: 3}
+	return &Entry{Logger: l, Fields: make(Fields), callDepth: baseCallDepth}
 }
 
 // WithFields 为 Logger 添加结构化字段，返回一个 Entry
@@ -240,12 +402,30 @@ func (l *Logger) WithFields(fields Fields) *Entry {
 
 // --- 日志级别方法 ---
 
+// Log 以指定级别记录日志
+func (l *Logger) Log(level Level, args ...interface{}) {
+	entry := l.newEntry()
+	entry.Level = level
+	entry.log(args...)
+}
+
+// Logf 以指定级别格式化并记录日志，便于封装 logger 的辅助函数按变量级别输出
+func (l *Logger) Logf(level Level, format string, args ...interface{}) {
+	entry := l.newEntry()
+	entry.Level = level
+	entry.logf(format, args...)
+}
+
 func (l *Logger) Debug(args ...interface{}) {
-	l.newEntry().log(args...)
+	entry := l.newEntry()
+	entry.Level = DebugLevel
+	entry.log(args...)
 }
 
 func (l *Logger) Debugf(format string, args ...interface{}) {
-	l.newEntry().logf(format, args...)
+	entry := l.newEntry()
+	entry.Level = DebugLevel
+	entry.logf(format, args...)
 }
 
 func (l *Logger) Info(args ...interface{}) {
@@ -302,16 +482,25 @@ var defaultLogger = New()
 
 // SetLevel 设置默认 logger 的级别
 func SetLevel(level Level) {
-	defaultLogger.level = level
+	defaultLogger.SetLevel(level)
 }
 
 // SetOutput 设置默认 logger 的输出
 func SetOutput(out io.Writer) {
+	defaultLogger.mu.Lock()
+	defer defaultLogger.mu.Unlock()
 	defaultLogger.out = out
 }
 
+// SetCallerSkip 设置默认 logger 额外跳过的调用层数，见 WithCallerSkip
+func SetCallerSkip(skip int) {
+	defaultLogger.SetCallerSkip(skip)
+}
+
 // SetFormatter 设置默认 logger 的格式化器
 func SetFormatter(formatter Formatter) {
+	defaultLogger.mu.Lock()
+	defer defaultLogger.mu.Unlock()
 	defaultLogger.formatter = formatter
 }
 
@@ -320,12 +509,30 @@ func WithFields(fields Fields) *Entry {
 	return defaultLogger.WithFields(fields)
 }
 
+// Log 以指定级别记录日志
+func Log(level Level, args ...interface{}) {
+	entry := defaultLogger.newEntry()
+	entry.Level = level
+	entry.log(args...)
+}
+
+// Logf 以指定级别格式化并记录日志
+func Logf(level Level, format string, args ...interface{}) {
+	entry := defaultLogger.newEntry()
+	entry.Level = level
+	entry.logf(format, args...)
+}
+
 func Debug(args ...interface{}) {
-	defaultLogger.newEntry().log(args...)
+	entry := defaultLogger.newEntry()
+	entry.Level = DebugLevel
+	entry.log(args...)
 }
 
 func Debugf(format string, args ...interface{}) {
-	defaultLogger.newEntry().logf(format, args...)
+	entry := defaultLogger.newEntry()
+	entry.Level = DebugLevel
+	entry.logf(format, args...)
 }
 
 func Info(args ...interface{}) {
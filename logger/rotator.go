@@ -2,20 +2,65 @@
 package logger
 
 import (
+	"compress/gzip"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
 	"sync"
+	"time"
 )
 
-// LogRotator 实现了 io.WriteCloser 接口，用于按大小轮转日志文件。
+// RotateInterval 按时间轮转的周期
+type RotateInterval uint8
+
+const (
+	// RotateNone 不按时间轮转
+	RotateNone RotateInterval = iota
+	// RotateHourly 每小时整点轮转
+	RotateHourly
+	// RotateDaily 每天零点轮转
+	RotateDaily
+)
+
+// compressSuffix 压缩后的旧日志文件后缀
+const compressSuffix = ".gz"
+
+// RotatorConfig 日志轮转配置
+type RotatorConfig struct {
+	// Filename 当前写入的日志文件路径，轮转后始终写入该文件，方便 tail -F 跟踪
+	Filename string
+	// MaxSize 单个文件的最大大小（字节），0 表示不按大小轮转
+	MaxSize int64
+	// Interval 按时间轮转的周期
+	Interval RotateInterval
+	// TimeFormat 旧日志文件名中的时间格式，旧文件命名为 app-<时间>.log，同一时间重复时追加序号 app-<时间>.1.log。
+	// 按时间轮转时时间为文件所属周期的开始时间，默认 RotateDaily 为 2006-01-02，RotateHourly 为 2006-01-02T15；
+	// 只按大小轮转时为轮转时间，默认 2006-01-02T15-04-05.000。
+	// Interval 为 RotateNone 且 TimeFormat 为空时旧文件按序号命名为 app.log.1（最新）到 app.log.n
+	TimeFormat string
+	// MaxBackups 保留的旧日志文件数，0 表示不限制
+	MaxBackups int
+	// MaxAge 旧日志文件按修改时间保留的最长时间，0 表示不限制
+	MaxAge time.Duration
+	// Compress 轮转后在后台将旧日志文件压缩为 .gz
+	Compress bool
+}
+
+// LogRotator 实现了 io.WriteCloser 接口，按大小或时间轮转日志文件。
 type LogRotator struct {
 	mu          sync.Mutex
-	filename    string
-	maxSize     int64 // 以字节为单位
-	maxBackups  int
+	config      RotatorConfig
 	currentSize int64
+	periodEnd   time.Time // 当前文件所属周期的结束时间，到达后轮转
+	periodStart time.Time
 	file        *os.File
+
+	millMu sync.Mutex // 串行化压缩、清理与序号重命名
+	millWg sync.WaitGroup
 }
 
 // New 创建一个新的 LogRotator 实例。
@@ -29,52 +74,127 @@ func NewRotator(filename string, maxSize int64, maxBackups int) (*LogRotator, er
 	if maxBackups < 0 {
 		return nil, fmt.Errorf("maxBackups 不能为负数")
 	}
+	// 兼容旧行为：maxBackups 为 0 时仍保留一个旧文件
+	if maxBackups == 0 {
+		maxBackups = 1
+	}
+	return NewRotatorWithConfig(RotatorConfig{
+		Filename:   filename,
+		MaxSize:    maxSize,
+		MaxBackups: maxBackups,
+	})
+}
 
-	r := &LogRotator{
-		filename:   filename,
-		maxSize:    maxSize,
-		maxBackups: maxBackups,
+// NewRotatorWithConfig 按配置创建 LogRotator，支持按大小、按天或按小时轮转，旧文件压缩和按数量、时间清理
+func NewRotatorWithConfig(config RotatorConfig) (*LogRotator, error) {
+	if config.Filename == "" {
+		return nil, fmt.Errorf("filename 不能为空")
+	}
+	if config.MaxSize < 0 || config.MaxBackups < 0 || config.MaxAge < 0 {
+		return nil, fmt.Errorf("maxSize、maxBackups、maxAge 不能为负数")
 	}
+	if config.MaxSize == 0 && config.Interval == RotateNone {
+		return nil, fmt.Errorf("maxSize 和 interval 至少需要设置一个")
+	}
+	r := &LogRotator{config: defaultTimeFormat(config)}
 
 	// 确保日志目录存在
-	if err := os.MkdirAll(filepath.Dir(filename), 0755); err != nil {
+	if err := os.MkdirAll(filepath.Dir(config.Filename), 0755); err != nil {
 		return nil, err
 	}
 
 	// 打开或创建日志文件
-	err := r.openFile()
-	if err != nil {
+	if err := r.openFile(); err != nil {
 		return nil, err
 	}
 
+	// 清理和压缩上次运行遗留的旧文件
+	r.startMill()
 	return r, nil
 }
 
-// openFile 打开日志文件并获取其当前大小。
+// defaultTimeFormat 按时间轮转且未设置 TimeFormat 时使用周期对应的默认格式
+func defaultTimeFormat(config RotatorConfig) RotatorConfig {
+	if config.TimeFormat == "" {
+		switch config.Interval {
+		case RotateDaily:
+			config.TimeFormat = "2006-01-02"
+		case RotateHourly:
+			config.TimeFormat = "2006-01-02T15"
+		}
+	}
+	return config
+}
+
+// RotatedFiles 按 config 的命名规则列出 config.Filename 轮转出的旧文件，由新到旧排列，压缩后的文件带 .gz 后缀。
+// 用于在写入进程之外读取旧文件（如 utils/tail 断点续读），config 需与写入方 LogRotator 的配置一致
+func RotatedFiles(config RotatorConfig) ([]string, error) {
+	if config.Filename == "" {
+		return nil, fmt.Errorf("filename 不能为空")
+	}
+	r := &LogRotator{config: defaultTimeFormat(config)}
+	files, err := r.backups()
+	if err != nil {
+		return nil, err
+	}
+	paths := make([]string, len(files))
+	for i, f := range files {
+		paths[i] = f.path
+	}
+	return paths, nil
+}
+
+// openFile 打开日志文件并获取其当前大小，已有文件按修改时间确定所属周期。
 func (r *LogRotator) openFile() error {
-	file, err := os.OpenFile(r.filename, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	file, err := os.OpenFile(r.config.Filename, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
 	if err != nil {
 		return err
 	}
 
 	stat, err := file.Stat()
 	if err != nil {
+		file.Close()
 		return err
 	}
 
 	r.file = file
 	r.currentSize = stat.Size()
+	start := time.Now()
+	if stat.Size() > 0 {
+		start = stat.ModTime()
+	}
+	r.periodStart, r.periodEnd = r.period(start)
 	return nil
 }
 
+// period 返回 t 所在轮转周期的开始和结束时间，不按时间轮转时结束时间为零值
+func (r *LogRotator) period(t time.Time) (time.Time, time.Time) {
+	switch r.config.Interval {
+	case RotateHourly:
+		start := time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), 0, 0, 0, t.Location())
+		return start, start.Add(time.Hour)
+	case RotateDaily:
+		start := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+		return start, start.AddDate(0, 0, 1)
+	}
+	return t, time.Time{}
+}
+
 // Write 实现了 io.Writer 接口。
 func (r *LogRotator) Write(p []byte) (n int, err error) {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
+	if r.file == nil {
+		return 0, os.ErrClosed
+	}
+
 	// 检查是否需要轮转
-	if r.currentSize+int64(len(p)) > r.maxSize {
-		if err := r.rotate(); err != nil {
+	now := time.Now()
+	bySize := r.config.MaxSize > 0 && r.currentSize > 0 && r.currentSize+int64(len(p)) > r.config.MaxSize
+	byTime := !r.periodEnd.IsZero() && !now.Before(r.periodEnd)
+	if bySize || byTime {
+		if err := r.rotate(now); err != nil {
 			return 0, err
 		}
 	}
@@ -84,44 +204,278 @@ func (r *LogRotator) Write(p []byte) (n int, err error) {
 	return n, err
 }
 
-// Close 实现了 io.Closer 接口。
-func (r *LogRotator) Close() error {
+// Rotate 立即轮转当前日志文件，可用于响应 SIGHUP 等外部信号
+func (r *LogRotator) Rotate() error {
 	r.mu.Lock()
 	defer r.mu.Unlock()
-	return r.file.Close()
+	if r.file == nil {
+		return os.ErrClosed
+	}
+	return r.rotate(time.Now())
 }
 
-// rotate 执行文件轮转。
-func (r *LogRotator) rotate() error {
+// Close 实现了 io.Closer 接口，等待正在进行的压缩和清理完成。
+func (r *LogRotator) Close() error {
+	r.mu.Lock()
+	file := r.file
+	r.file = nil
+	r.mu.Unlock()
+	if file == nil {
+		return nil
+	}
+	err := file.Close()
+	r.millWg.Wait()
+	return err
+}
+
+// rotate 执行文件轮转，调用方需持有 r.mu。
+func (r *LogRotator) rotate(now time.Time) error {
 	// 1. 关闭当前文件
 	if err := r.file.Close(); err != nil {
 		return err
 	}
 
-	// 2. 重命名备份文件
-	for i := r.maxBackups; i > 0; i-- {
-		oldPath := r.backupFilename(i - 1)
-		newPath := r.backupFilename(i)
+	// 2. 重命名当前日志文件为旧文件，与后台压缩互斥以免序号重命名时文件正在压缩
+	r.millMu.Lock()
+	err := r.renameCurrent(now)
+	r.millMu.Unlock()
+	if err != nil {
+		// 重命名失败时继续写入原文件，避免丢日志
+		if openErr := r.openFile(); openErr != nil {
+			return openErr
+		}
+		return err
+	}
+
+	// 3. 创建一个新的日志文件
+	if err := r.openFile(); err != nil {
+		return err
+	}
+
+	// 4. 后台压缩和清理旧文件
+	r.startMill()
+	return nil
+}
+
+// renameCurrent 将当前日志文件重命名为最新的旧文件
+func (r *LogRotator) renameCurrent(now time.Time) error {
+	if r.config.TimeFormat == "" {
+		backups, err := r.backups()
+		if err != nil {
+			return err
+		}
+		// 由旧到新依次后移序号，app.log.1 始终为最新
+		for i := len(backups) - 1; i >= 0; i-- {
+			b := backups[i]
+			next := r.numberedFilename(b.seq+1, strings.HasSuffix(b.path, compressSuffix))
+			if err := os.Rename(b.path, next); err != nil {
+				return err
+			}
+		}
+		return os.Rename(r.config.Filename, r.numberedFilename(1, false))
+	}
+
+	t := now
+	if r.config.Interval != RotateNone {
+		t = r.periodStart
+	}
+	return os.Rename(r.config.Filename, r.timestampFilename(t))
+}
+
+// numberedFilename 生成按序号命名的旧文件名称。
+func (r *LogRotator) numberedFilename(num int, compressed bool) string {
+	name := r.config.Filename + "." + strconv.Itoa(num)
+	if compressed {
+		name += compressSuffix
+	}
+	return name
+}
+
+// timestampFilename 生成按时间命名的旧文件名称，与已有文件（含压缩后的）重名时追加序号
+func (r *LogRotator) timestampFilename(t time.Time) string {
+	dir, prefix, ext := r.nameParts()
+	stamp := t.Format(r.config.TimeFormat)
+	for seq := 0; ; seq++ {
+		name := prefix + stamp
+		if seq > 0 {
+			name += "." + strconv.Itoa(seq)
+		}
+		path := filepath.Join(dir, name+ext)
+		if !fileExists(path) && !fileExists(path+compressSuffix) {
+			return path
+		}
+	}
+}
+
+// nameParts 拆分日志文件名，app.log 返回目录、"app-" 和 ".log"
+func (r *LogRotator) nameParts() (dir, prefix, ext string) {
+	dir = filepath.Dir(r.config.Filename)
+	base := filepath.Base(r.config.Filename)
+	ext = filepath.Ext(base)
+	return dir, strings.TrimSuffix(base, ext) + "-", ext
+}
+
+// backupFile 旧日志文件
+type backupFile struct {
+	path    string
+	seq     int // 按序号命名时的序号
+	modTime time.Time
+}
+
+// backups 列出当前日志的旧文件，按序号命名时按序号升序，按时间命名时按修改时间由新到旧
+func (r *LogRotator) backups() ([]backupFile, error) {
+	dir := filepath.Dir(r.config.Filename)
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	base := filepath.Base(r.config.Filename)
+	_, prefix, ext := r.nameParts()
 
-		// 检查旧文件是否存在
-		if _, err := os.Stat(oldPath); err == nil {
-			os.Rename(oldPath, newPath)
+	var files []backupFile
+	for _, e := range entries {
+		if !e.Type().IsRegular() {
+			continue
 		}
+		name := e.Name()
+		b := backupFile{path: filepath.Join(dir, name)}
+		trimmed := strings.TrimSuffix(name, compressSuffix)
+		if r.config.TimeFormat == "" {
+			seq, err := strconv.Atoi(strings.TrimPrefix(trimmed, base+"."))
+			if !strings.HasPrefix(trimmed, base+".") || err != nil || seq <= 0 {
+				continue
+			}
+			b.seq = seq
+		} else {
+			if !strings.HasPrefix(trimmed, prefix) || !strings.HasSuffix(trimmed, ext) {
+				continue
+			}
+			if !r.isTimestamp(strings.TrimSuffix(strings.TrimPrefix(trimmed, prefix), ext)) {
+				continue
+			}
+		}
+		info, err := e.Info()
+		if err != nil {
+			continue
+		}
+		b.modTime = info.ModTime()
+		files = append(files, b)
+	}
+
+	if r.config.TimeFormat == "" {
+		sort.Slice(files, func(i, j int) bool { return files[i].seq < files[j].seq })
+	} else {
+		sort.Slice(files, func(i, j int) bool { return files[i].modTime.After(files[j].modTime) })
+	}
+	return files, nil
+}
+
+// isTimestamp 判断 s 是否为 TimeFormat 格式的时间，可带 .序号 后缀，避免误删 app-error.log 等其他文件
+func (r *LogRotator) isTimestamp(s string) bool {
+	if _, err := time.ParseInLocation(r.config.TimeFormat, s, time.Local); err == nil {
+		return true
+	}
+	i := strings.LastIndexByte(s, '.')
+	if i < 0 {
+		return false
+	}
+	if _, err := strconv.Atoi(s[i+1:]); err != nil {
+		return false
 	}
+	_, err := time.ParseInLocation(r.config.TimeFormat, s[:i], time.Local)
+	return err == nil
+}
 
-	// 3. 重命名当前日志文件为第一个备份
-	if err := os.Rename(r.filename, r.backupFilename(0)); err != nil {
+// startMill 在后台执行压缩和清理
+func (r *LogRotator) startMill() {
+	if !r.config.Compress && r.config.MaxBackups == 0 && r.config.MaxAge == 0 {
+		return
+	}
+	r.millWg.Add(1)
+	go func() {
+		defer r.millWg.Done()
+		r.millMu.Lock()
+		defer r.millMu.Unlock()
+		if err := r.mill(); err != nil {
+			fmt.Fprintf(os.Stderr, "清理旧日志文件失败: %v\n", err)
+		}
+	}()
+}
+
+// mill 删除超出数量或超过保留时间的旧文件，再压缩剩余的未压缩旧文件
+func (r *LogRotator) mill() error {
+	files, err := r.backups()
+	if err != nil {
 		return err
 	}
+	cutoff := time.Time{}
+	if r.config.MaxAge > 0 {
+		cutoff = time.Now().Add(-r.config.MaxAge)
+	}
 
-	// 4. 创建一个新的日志文件
-	return r.openFile()
+	var errs []string
+	for i, f := range files {
+		expired := r.config.MaxBackups > 0 && i >= r.config.MaxBackups
+		if !cutoff.IsZero() && f.modTime.Before(cutoff) {
+			expired = true
+		}
+		if expired {
+			if err := os.Remove(f.path); err != nil && !os.IsNotExist(err) {
+				errs = append(errs, err.Error())
+			}
+			continue
+		}
+		if r.config.Compress && !strings.HasSuffix(f.path, compressSuffix) {
+			if err := compressFile(f.path, f.modTime); err != nil {
+				errs = append(errs, err.Error())
+			}
+		}
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("%s", strings.Join(errs, "; "))
+	}
+	return nil
 }
 
-// backupFilename 生成备份文件的名称。
-func (r *LogRotator) backupFilename(num int) string {
-	if num == 0 {
-		return r.filename + ".1"
+// compressFile 将文件压缩为 .gz 并删除原文件，保留原修改时间以便按时间清理
+func compressFile(src string, modTime time.Time) (err error) {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
 	}
-	return fmt.Sprintf("%s.%d", r.filename, num+1)
+	defer in.Close()
+
+	dst := src + compressSuffix
+	out, err := os.OpenFile(dst, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			out.Close()
+			os.Remove(dst)
+		}
+	}()
+
+	gz := gzip.NewWriter(out)
+	gz.Name = filepath.Base(src)
+	gz.ModTime = modTime
+	if _, err = io.Copy(gz, in); err != nil {
+		return fmt.Errorf("failed to compress %s: %w", src, err)
+	}
+	if err = gz.Close(); err != nil {
+		return fmt.Errorf("failed to compress %s: %w", src, err)
+	}
+	if err = out.Close(); err != nil {
+		return fmt.Errorf("failed to compress %s: %w", src, err)
+	}
+	os.Chtimes(dst, modTime, modTime)
+	in.Close()
+	return os.Remove(src)
+}
+
+// fileExists 判断文件是否存在
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
 }
@@ -0,0 +1,219 @@
+package kafka
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/IBM/sarama"
+)
+
+// Handler 消息处理函数，返回错误时按配置重试，重试耗尽后投递到死信队列
+type Handler func(ctx context.Context, msg *Message) error
+
+// RebalanceCallback 分区分配/回收回调，参数为主题到分区列表的映射
+type RebalanceCallback func(claims map[string][]int32)
+
+// Consumer 消费者组消费者
+type Consumer struct {
+	group    sarama.ConsumerGroup
+	topics   []string
+	handler  Handler
+	config   Config
+	dlq      *Producer
+	OnAssign RebalanceCallback // 分区分配后回调
+	OnRevoke RebalanceCallback // 分区回收前回调
+	OnError  func(err error)   // 消费过程中的错误回调
+}
+
+// NewConsumer 创建消费者组消费者
+func NewConsumer(config Config, topics []string, handler Handler) (*Consumer, error) {
+	if config.GroupID == "" {
+		return nil, fmt.Errorf("kafka consumer group id must be provided")
+	}
+	if len(topics) == 0 {
+		return nil, fmt.Errorf("kafka consumer topics must be provided")
+	}
+	if handler == nil {
+		return nil, fmt.Errorf("kafka consumer handler must be provided")
+	}
+	sc, err := newSaramaConfig(config)
+	if err != nil {
+		return nil, err
+	}
+	group, err := sarama.NewConsumerGroup(config.Brokers, config.GroupID, sc)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create kafka consumer group: %w", err)
+	}
+
+	c := &Consumer{
+		group:   group,
+		topics:  topics,
+		handler: handler,
+		config:  config,
+	}
+	if config.DLQTopic != "" {
+		c.dlq, err = NewProducer(config, nil)
+		if err != nil {
+			group.Close()
+			return nil, fmt.Errorf("failed to create dlq producer: %w", err)
+		}
+	}
+	return c, nil
+}
+
+// maxConsumeBackoff 加入消费者组连续失败时的最长等待时间
+const maxConsumeBackoff = 30 * time.Second
+
+// Consume 阻塞消费，直到ctx被取消；重平衡后自动重新加入消费者组，失败时从1秒开始指数退避重试
+func (c *Consumer) Consume(ctx context.Context) error {
+	go func() {
+		for err := range c.group.Errors() {
+			c.reportError(err)
+		}
+	}()
+
+	h := &groupHandler{consumer: c}
+	wait := time.Duration(0)
+	for {
+		err := c.group.Consume(ctx, c.topics, h)
+		if errors.Is(err, sarama.ErrClosedConsumerGroup) || ctx.Err() != nil {
+			return nil
+		}
+		if err == nil {
+			wait = 0
+			continue
+		}
+		c.reportError(err)
+		// 连续失败（如 broker 不可用、认证失败）时退避等待，避免空转刷屏
+		wait = min(max(wait*2, time.Second), maxConsumeBackoff)
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(wait):
+		}
+	}
+}
+
+// Close 关闭消费者
+func (c *Consumer) Close() error {
+	err := c.group.Close()
+	if c.dlq != nil {
+		c.dlq.Close()
+	}
+	return err
+}
+
+// reportError 通知错误回调
+func (c *Consumer) reportError(err error) {
+	if c.OnError != nil && err != nil {
+		c.OnError(err)
+	}
+}
+
+// handle 处理单条消息，失败时重试并投递死信队列
+func (c *Consumer) handle(ctx context.Context, msg *Message) error {
+	retries := defaultInt(c.config.HandleRetry, 3)
+	backoff := defaultDuration(c.config.RetryBackoff, 500*time.Millisecond)
+
+	var err error
+	for i := 0; i <= retries; i++ {
+		if err = c.handler(ctx, msg); err == nil {
+			return nil
+		}
+		if i < retries {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(backoff):
+			}
+		}
+	}
+
+	c.reportError(fmt.Errorf("failed to handle message %s/%d/%d: %w", msg.Topic, msg.Partition, msg.Offset, err))
+	if c.dlq == nil {
+		return nil
+	}
+
+	dead := &Message{
+		Topic:   c.config.DLQTopic,
+		Key:     msg.Key,
+		Value:   msg.Value,
+		Headers: make(map[string]string, len(msg.Headers)+4),
+	}
+	for k, v := range msg.Headers {
+		dead.Headers[k] = v
+	}
+	dead.Headers["x-original-topic"] = msg.Topic
+	dead.Headers["x-original-partition"] = strconv.Itoa(int(msg.Partition))
+	dead.Headers["x-original-offset"] = strconv.FormatInt(msg.Offset, 10)
+	dead.Headers["x-error"] = err.Error()
+	if err := c.dlq.SendSync(dead); err != nil {
+		return fmt.Errorf("failed to send message to dlq %s: %w", c.config.DLQTopic, err)
+	}
+	return nil
+}
+
+// groupHandler 实现 sarama.ConsumerGroupHandler
+type groupHandler struct {
+	consumer *Consumer
+}
+
+// Setup 分区分配后调用
+func (h *groupHandler) Setup(sess sarama.ConsumerGroupSession) error {
+	if h.consumer.OnAssign != nil {
+		h.consumer.OnAssign(sess.Claims())
+	}
+	return nil
+}
+
+// Cleanup 分区回收前调用，提交已标记的位移
+func (h *groupHandler) Cleanup(sess sarama.ConsumerGroupSession) error {
+	if h.consumer.config.CommitStrategy != CommitAuto {
+		sess.Commit()
+	}
+	if h.consumer.OnRevoke != nil {
+		h.consumer.OnRevoke(sess.Claims())
+	}
+	return nil
+}
+
+// ConsumeClaim 消费单个分区
+func (h *groupHandler) ConsumeClaim(sess sarama.ConsumerGroupSession, claim sarama.ConsumerGroupClaim) error {
+	c := h.consumer
+	batch := defaultInt(c.config.CommitBatch, 100)
+	interval := defaultDuration(c.config.CommitInterval, time.Second)
+	pending := 0
+	lastCommit := time.Now()
+
+	for {
+		select {
+		case <-sess.Context().Done():
+			return nil
+		case cm, ok := <-claim.Messages():
+			if !ok {
+				return nil
+			}
+			if err := c.handle(sess.Context(), fromConsumerMessage(cm)); err != nil {
+				// 死信投递失败时不标记位移，停止消费该分区，等待重平衡后重新处理
+				c.reportError(err)
+				return err
+			}
+			sess.MarkMessage(cm, "")
+
+			switch c.config.CommitStrategy {
+			case CommitSync:
+				sess.Commit()
+			case CommitBatch:
+				pending++
+				if pending >= batch || time.Since(lastCommit) >= interval {
+					sess.Commit()
+					pending = 0
+					lastCommit = time.Now()
+				}
+			}
+		}
+	}
+}
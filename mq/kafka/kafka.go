@@ -0,0 +1,203 @@
+package kafka
+
+import (
+	"crypto/tls"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/IBM/sarama"
+)
+
+// Config 配置结构
+type Config struct {
+	Brokers  []string
+	Version  string // Kafka版本，如 "2.8.0"，默认 sarama.DefaultVersion
+	ClientID string
+	Username string // SASL/PLAIN 用户名，为空不启用SASL
+	Password string
+	TLS      *tls.Config
+
+	// 生产者配置
+	Compression   string        // none/gzip/snappy/lz4/zstd，默认 snappy
+	BatchSize     int           // 每批最多消息数，默认 500
+	BatchBytes    int           // 每批最大字节数，默认 1MB
+	Linger        time.Duration // 批次最长等待时间，默认 100ms
+	RequiredAcks  int           // 0:不等待 1:leader确认 -1:全部副本确认，默认 -1
+	ProduceRetry  int           // 发送失败重试次数，默认 3
+	Idempotent    bool          // 启用幂等生产者
+	MaxInFlight   int           // 幂等模式下会强制为1
+	DeliveryQueue int           // 异步发送队列长度，默认 4096
+
+	// 消费者配置
+	GroupID        string
+	InitialOffset  string         // newest/oldest，默认 newest
+	Rebalance      string         // range/roundrobin/sticky，默认 range
+	CommitStrategy CommitStrategy // 位移提交策略，默认 CommitAuto
+	CommitInterval time.Duration  // CommitAuto 时的自动提交间隔，CommitBatch 时的最长提交间隔，默认 1s
+	CommitBatch    int            // CommitBatch 时每处理多少条提交一次，默认 100
+	HandleRetry    int            // 处理失败重试次数，默认 3
+	RetryBackoff   time.Duration  // 处理失败重试间隔，默认 500ms
+	DLQTopic       string         // 死信队列主题，为空时丢弃处理失败的消息
+}
+
+// CommitStrategy 位移提交策略
+type CommitStrategy int
+
+const (
+	CommitAuto  CommitStrategy = iota // 标记后由sarama按间隔自动提交
+	CommitSync                        // 每条消息处理后同步提交
+	CommitBatch                       // 按条数或时间批量提交
+)
+
+// Message 消息
+type Message struct {
+	Topic     string
+	Partition int32
+	Offset    int64
+	Key       []byte
+	Value     []byte
+	Headers   map[string]string
+	Timestamp time.Time
+}
+
+// toProducerMessage 转换为sarama生产者消息
+func (m *Message) toProducerMessage() *sarama.ProducerMessage {
+	msg := &sarama.ProducerMessage{
+		Topic:     m.Topic,
+		Value:     sarama.ByteEncoder(m.Value),
+		Timestamp: m.Timestamp,
+	}
+	if m.Key != nil {
+		msg.Key = sarama.ByteEncoder(m.Key)
+	}
+	for k, v := range m.Headers {
+		msg.Headers = append(msg.Headers, sarama.RecordHeader{Key: []byte(k), Value: []byte(v)})
+	}
+	return msg
+}
+
+// fromConsumerMessage 从sarama消费者消息转换
+func fromConsumerMessage(msg *sarama.ConsumerMessage) *Message {
+	m := &Message{
+		Topic:     msg.Topic,
+		Partition: msg.Partition,
+		Offset:    msg.Offset,
+		Key:       msg.Key,
+		Value:     msg.Value,
+		Headers:   make(map[string]string, len(msg.Headers)),
+		Timestamp: msg.Timestamp,
+	}
+	for _, h := range msg.Headers {
+		if h != nil {
+			m.Headers[string(h.Key)] = string(h.Value)
+		}
+	}
+	return m
+}
+
+// newSaramaConfig 根据Config生成sarama配置
+func newSaramaConfig(config Config) (*sarama.Config, error) {
+	sc := sarama.NewConfig()
+
+	if config.Version != "" {
+		version, err := sarama.ParseKafkaVersion(config.Version)
+		if err != nil {
+			return nil, fmt.Errorf("invalid kafka version %s: %w", config.Version, err)
+		}
+		sc.Version = version
+	}
+	if config.ClientID != "" {
+		sc.ClientID = config.ClientID
+	}
+	if config.Username != "" {
+		sc.Net.SASL.Enable = true
+		sc.Net.SASL.Mechanism = sarama.SASLTypePlaintext
+		sc.Net.SASL.User = config.Username
+		sc.Net.SASL.Password = config.Password
+	}
+	if config.TLS != nil {
+		sc.Net.TLS.Enable = true
+		sc.Net.TLS.Config = config.TLS
+	}
+
+	// 生产者
+	codec, err := parseCompression(config.Compression)
+	if err != nil {
+		return nil, err
+	}
+	sc.Producer.Compression = codec
+	sc.Producer.Flush.Messages = defaultInt(config.BatchSize, 500)
+	sc.Producer.Flush.MaxMessages = sc.Producer.Flush.Messages
+	sc.Producer.Flush.Bytes = defaultInt(config.BatchBytes, 1024*1024)
+	sc.Producer.Flush.Frequency = defaultDuration(config.Linger, 100*time.Millisecond)
+	sc.Producer.RequiredAcks = sarama.WaitForAll
+	if config.RequiredAcks == 0 || config.RequiredAcks == 1 {
+		sc.Producer.RequiredAcks = sarama.RequiredAcks(config.RequiredAcks)
+	}
+	sc.Producer.Retry.Max = defaultInt(config.ProduceRetry, 3)
+	sc.Producer.Return.Successes = true
+	sc.Producer.Return.Errors = true
+	sc.ChannelBufferSize = defaultInt(config.DeliveryQueue, 4096)
+	if config.MaxInFlight > 0 {
+		sc.Net.MaxOpenRequests = config.MaxInFlight
+	}
+	if config.Idempotent {
+		sc.Producer.Idempotent = true
+		sc.Producer.RequiredAcks = sarama.WaitForAll
+		sc.Net.MaxOpenRequests = 1
+	}
+
+	// 消费者
+	sc.Consumer.Return.Errors = true
+	sc.Consumer.Offsets.Initial = sarama.OffsetNewest
+	if strings.EqualFold(config.InitialOffset, "oldest") {
+		sc.Consumer.Offsets.Initial = sarama.OffsetOldest
+	}
+	switch strings.ToLower(config.Rebalance) {
+	case "", "range":
+		sc.Consumer.Group.Rebalance.GroupStrategies = []sarama.BalanceStrategy{sarama.NewBalanceStrategyRange()}
+	case "roundrobin":
+		sc.Consumer.Group.Rebalance.GroupStrategies = []sarama.BalanceStrategy{sarama.NewBalanceStrategyRoundRobin()}
+	case "sticky":
+		sc.Consumer.Group.Rebalance.GroupStrategies = []sarama.BalanceStrategy{sarama.NewBalanceStrategySticky()}
+	default:
+		return nil, fmt.Errorf("unsupported rebalance strategy %s", config.Rebalance)
+	}
+	sc.Consumer.Offsets.AutoCommit.Enable = config.CommitStrategy == CommitAuto
+	sc.Consumer.Offsets.AutoCommit.Interval = defaultDuration(config.CommitInterval, time.Second)
+
+	return sc, nil
+}
+
+// parseCompression 解析压缩方式
+func parseCompression(name string) (sarama.CompressionCodec, error) {
+	switch strings.ToLower(name) {
+	case "", "snappy":
+		return sarama.CompressionSnappy, nil
+	case "none":
+		return sarama.CompressionNone, nil
+	case "gzip":
+		return sarama.CompressionGZIP, nil
+	case "lz4":
+		return sarama.CompressionLZ4, nil
+	case "zstd":
+		return sarama.CompressionZSTD, nil
+	default:
+		return sarama.CompressionNone, fmt.Errorf("unsupported compression %s", name)
+	}
+}
+
+func defaultInt(v, def int) int {
+	if v <= 0 {
+		return def
+	}
+	return v
+}
+
+func defaultDuration(v, def time.Duration) time.Duration {
+	if v <= 0 {
+		return def
+	}
+	return v
+}
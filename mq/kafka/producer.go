@@ -0,0 +1,132 @@
+package kafka
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	"github.com/IBM/sarama"
+)
+
+// DeliveryCallback 投递结果回调，err为nil表示投递成功
+type DeliveryCallback func(msg *Message, err error)
+
+// Producer 批量异步生产者
+type Producer struct {
+	producer sarama.AsyncProducer
+	callback DeliveryCallback
+	wg       sync.WaitGroup
+	mu       sync.RWMutex
+	closed   bool
+}
+
+// delivery 随消息传递的投递上下文
+type delivery struct {
+	msg  *Message
+	done chan error
+}
+
+// NewProducer 创建生产者，callback 为全局投递回调，可为nil
+func NewProducer(config Config, callback DeliveryCallback) (*Producer, error) {
+	sc, err := newSaramaConfig(config)
+	if err != nil {
+		return nil, err
+	}
+	ap, err := sarama.NewAsyncProducer(config.Brokers, sc)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create kafka producer: %w", err)
+	}
+
+	p := &Producer{producer: ap, callback: callback}
+	p.wg.Add(2)
+	go p.handleSuccesses()
+	go p.handleErrors()
+	return p, nil
+}
+
+// handleSuccesses 处理投递成功的消息
+func (p *Producer) handleSuccesses() {
+	defer p.wg.Done()
+	for pm := range p.producer.Successes() {
+		p.deliver(pm, nil)
+	}
+}
+
+// handleErrors 处理投递失败的消息
+func (p *Producer) handleErrors() {
+	defer p.wg.Done()
+	for pe := range p.producer.Errors() {
+		p.deliver(pe.Msg, pe.Err)
+	}
+}
+
+// deliver 回填分区/位移并通知回调
+func (p *Producer) deliver(pm *sarama.ProducerMessage, err error) {
+	d, ok := pm.Metadata.(*delivery)
+	if !ok {
+		return
+	}
+	d.msg.Partition = pm.Partition
+	d.msg.Offset = pm.Offset
+	if p.callback != nil {
+		p.callback(d.msg, err)
+	}
+	if d.done != nil {
+		d.done <- err
+	}
+}
+
+// send 将消息放入发送队列
+func (p *Producer) send(msg *Message, done chan error) error {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	if p.closed {
+		return fmt.Errorf("kafka producer is closed")
+	}
+	pm := msg.toProducerMessage()
+	pm.Metadata = &delivery{msg: msg, done: done}
+	p.producer.Input() <- pm
+	return nil
+}
+
+// Send 异步发送消息，投递结果通过回调通知
+func (p *Producer) Send(msg *Message) error {
+	return p.send(msg, nil)
+}
+
+// SendSync 发送消息并等待投递结果
+func (p *Producer) SendSync(msg *Message) error {
+	done := make(chan error, 1)
+	if err := p.send(msg, done); err != nil {
+		return err
+	}
+	return <-done
+}
+
+// SendJSON 将v序列化为JSON后异步发送
+func (p *Producer) SendJSON(topic string, key string, v interface{}) error {
+	value, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("failed to marshal message: %w", err)
+	}
+	msg := &Message{Topic: topic, Value: value}
+	if key != "" {
+		msg.Key = []byte(key)
+	}
+	return p.Send(msg)
+}
+
+// Close 刷新缓冲区中的消息并关闭生产者
+func (p *Producer) Close() error {
+	p.mu.Lock()
+	if p.closed {
+		p.mu.Unlock()
+		return nil
+	}
+	p.closed = true
+	p.mu.Unlock()
+
+	p.producer.AsyncClose()
+	p.wg.Wait()
+	return nil
+}
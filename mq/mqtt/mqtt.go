@@ -0,0 +1,327 @@
+package mqtt
+
+import (
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	paho "github.com/eclipse/paho.mqtt.golang"
+)
+
+// Config 配置结构
+type Config struct {
+	Brokers        []string // tcp://host:1883, ssl://host:8883, ws://host:8083/mqtt
+	ClientID       string
+	Username       string
+	Password       string
+	TLS            *tls.Config
+	CleanSession   bool
+	KeepAlive      time.Duration // 心跳间隔，默认 30s
+	ConnectTimeout time.Duration // 连接超时，默认 10s
+	Will           *Will         // 遗嘱消息（LWT）
+	OfflineBuffer  int           // 断线期间缓存的待发布消息数量，默认 1000，超出时丢弃最旧的消息
+	PublishTimeout time.Duration // 等待发布完成的超时时间，默认 5s
+}
+
+// Will 遗嘱消息
+type Will struct {
+	Topic    string
+	Payload  []byte
+	QoS      byte
+	Retained bool
+}
+
+// Message 消息
+type Message struct {
+	Topic     string
+	Payload   []byte
+	QoS       byte
+	Retained  bool
+	MessageID uint16
+}
+
+// Handler 消息处理函数
+type Handler func(msg *Message)
+
+// route 订阅路由
+type route struct {
+	filter  string
+	qos     byte
+	handler Handler
+}
+
+// Client MQTT客户端
+type Client struct {
+	client paho.Client
+	config Config
+
+	mu      sync.RWMutex
+	routes  []route
+	pending []*Message
+	dropped int64
+}
+
+// NewClient 创建MQTT客户端并连接
+func NewClient(config Config) (*Client, error) {
+	if len(config.Brokers) == 0 {
+		return nil, fmt.Errorf("mqtt brokers must be provided")
+	}
+	if config.KeepAlive <= 0 {
+		config.KeepAlive = 30 * time.Second
+	}
+	if config.ConnectTimeout <= 0 {
+		config.ConnectTimeout = 10 * time.Second
+	}
+	if config.OfflineBuffer <= 0 {
+		config.OfflineBuffer = 1000
+	}
+	if config.PublishTimeout <= 0 {
+		config.PublishTimeout = 5 * time.Second
+	}
+
+	c := &Client{config: config}
+
+	opts := paho.NewClientOptions()
+	for _, broker := range config.Brokers {
+		opts.AddBroker(broker)
+	}
+	opts.SetClientID(config.ClientID)
+	opts.SetUsername(config.Username)
+	opts.SetPassword(config.Password)
+	if config.TLS != nil {
+		opts.SetTLSConfig(config.TLS)
+	}
+	opts.SetCleanSession(config.CleanSession)
+	opts.SetKeepAlive(config.KeepAlive)
+	opts.SetConnectTimeout(config.ConnectTimeout)
+	opts.SetAutoReconnect(true)
+	opts.SetConnectRetry(true)
+	opts.SetOrderMatters(false)
+	if config.Will != nil {
+		opts.SetBinaryWill(config.Will.Topic, config.Will.Payload, config.Will.QoS, config.Will.Retained)
+	}
+	opts.SetDefaultPublishHandler(func(_ paho.Client, m paho.Message) {
+		c.dispatch(m)
+	})
+	opts.SetOnConnectHandler(func(paho.Client) {
+		log.Println("MQTT 客户端连接成功")
+		c.resubscribe()
+		c.flushPending()
+	})
+	opts.SetConnectionLostHandler(func(_ paho.Client, err error) {
+		log.Printf("MQTT 连接断开: %v", err)
+	})
+
+	c.client = paho.NewClient(opts)
+	token := c.client.Connect()
+	if !token.WaitTimeout(config.ConnectTimeout) {
+		// 开启了连接重试，首次连接超时后在后台继续重连，期间发布的消息进入离线缓存
+		log.Println("MQTT 首次连接超时，后台继续重连")
+		return c, nil
+	}
+	if err := token.Error(); err != nil {
+		return nil, fmt.Errorf("连接 MQTT 失败: %v", err)
+	}
+	return c, nil
+}
+
+// Subscribe 订阅主题过滤器（支持 + 和 # 通配符），消息路由到handler；重连后自动重新订阅
+func (c *Client) Subscribe(filter string, qos byte, handler Handler) error {
+	if handler == nil {
+		return fmt.Errorf("mqtt handler must be provided")
+	}
+	if err := validateFilter(filter); err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	c.routes = append(c.routes, route{filter: filter, qos: qos, handler: handler})
+	c.mu.Unlock()
+
+	if !c.client.IsConnectionOpen() {
+		// 连接恢复后由 resubscribe 统一订阅
+		return nil
+	}
+	return c.waitToken(c.client.Subscribe(filter, qos, nil))
+}
+
+// Unsubscribe 取消订阅并移除对应路由
+func (c *Client) Unsubscribe(filter string) error {
+	c.mu.Lock()
+	routes := c.routes[:0]
+	for _, r := range c.routes {
+		if r.filter != filter {
+			routes = append(routes, r)
+		}
+	}
+	c.routes = routes
+	c.mu.Unlock()
+
+	if !c.client.IsConnectionOpen() {
+		return nil
+	}
+	return c.waitToken(c.client.Unsubscribe(filter))
+}
+
+// resubscribe 连接建立后重新订阅所有路由
+func (c *Client) resubscribe() {
+	c.mu.RLock()
+	filters := make(map[string]byte, len(c.routes))
+	for _, r := range c.routes {
+		if qos, ok := filters[r.filter]; !ok || r.qos > qos {
+			filters[r.filter] = r.qos
+		}
+	}
+	c.mu.RUnlock()
+
+	if len(filters) == 0 {
+		return
+	}
+	if err := c.waitToken(c.client.SubscribeMultiple(filters, nil)); err != nil {
+		log.Printf("MQTT 重新订阅失败: %v", err)
+	}
+}
+
+// dispatch 将消息分发给所有匹配的路由
+func (c *Client) dispatch(m paho.Message) {
+	msg := &Message{
+		Topic:     m.Topic(),
+		Payload:   m.Payload(),
+		QoS:       m.Qos(),
+		Retained:  m.Retained(),
+		MessageID: m.MessageID(),
+	}
+
+	c.mu.RLock()
+	var handlers []Handler
+	for _, r := range c.routes {
+		if MatchTopic(r.filter, msg.Topic) {
+			handlers = append(handlers, r.handler)
+		}
+	}
+	c.mu.RUnlock()
+
+	for _, h := range handlers {
+		h(msg)
+	}
+}
+
+// Publish 发布消息；断线期间消息进入离线缓存，连接恢复后按顺序补发
+func (c *Client) Publish(topic string, qos byte, retained bool, payload []byte) error {
+	msg := &Message{Topic: topic, Payload: payload, QoS: qos, Retained: retained}
+	if !c.client.IsConnectionOpen() {
+		c.buffer(msg)
+		return nil
+	}
+	if err := c.waitToken(c.client.Publish(topic, qos, retained, payload)); err != nil {
+		c.buffer(msg)
+		return err
+	}
+	return nil
+}
+
+// PublishJSON 将v序列化为JSON后发布
+func (c *Client) PublishJSON(topic string, qos byte, v interface{}) error {
+	payload, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("failed to marshal message: %w", err)
+	}
+	return c.Publish(topic, qos, false, payload)
+}
+
+// buffer 放入离线缓存
+func (c *Client) buffer(msg *Message) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if len(c.pending) >= c.config.OfflineBuffer {
+		c.pending = c.pending[1:]
+		c.dropped++
+	}
+	c.pending = append(c.pending, msg)
+}
+
+// flushPending 补发离线缓存中的消息
+func (c *Client) flushPending() {
+	c.mu.Lock()
+	pending := c.pending
+	c.pending = nil
+	c.mu.Unlock()
+
+	for i, msg := range pending {
+		if err := c.waitToken(c.client.Publish(msg.Topic, msg.QoS, msg.Retained, msg.Payload)); err != nil {
+			log.Printf("MQTT 补发离线消息失败: %v", err)
+			c.mu.Lock()
+			c.pending = append(append([]*Message{}, pending[i:]...), c.pending...)
+			c.mu.Unlock()
+			return
+		}
+	}
+}
+
+// Stats 返回离线缓存中的消息数量和因缓存溢出丢弃的消息数量
+func (c *Client) Stats() (pending int, dropped int64) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return len(c.pending), c.dropped
+}
+
+// IsConnected 判断连接是否可用
+func (c *Client) IsConnected() bool {
+	return c.client.IsConnectionOpen()
+}
+
+// Close 断开连接，quiesce 为等待未完成工作的毫秒数
+func (c *Client) Close(quiesce uint) {
+	c.client.Disconnect(quiesce)
+}
+
+// waitToken 等待操作完成
+func (c *Client) waitToken(token paho.Token) error {
+	if !token.WaitTimeout(c.config.PublishTimeout) {
+		return fmt.Errorf("mqtt operation timeout")
+	}
+	return token.Error()
+}
+
+// validateFilter 校验主题过滤器
+func validateFilter(filter string) error {
+	if filter == "" {
+		return fmt.Errorf("mqtt topic filter must be provided")
+	}
+	levels := strings.Split(filter, "/")
+	for i, level := range levels {
+		if strings.Contains(level, "#") && (level != "#" || i != len(levels)-1) {
+			return fmt.Errorf("invalid mqtt topic filter %s: # must be the last level", filter)
+		}
+		if strings.Contains(level, "+") && level != "+" {
+			return fmt.Errorf("invalid mqtt topic filter %s: + must occupy an entire level", filter)
+		}
+	}
+	return nil
+}
+
+// MatchTopic 判断主题是否匹配过滤器，支持 + 单层和 # 多层通配符
+func MatchTopic(filter, topic string) bool {
+	// $开头的系统主题不匹配以通配符开头的过滤器
+	if strings.HasPrefix(topic, "$") && (strings.HasPrefix(filter, "+") || strings.HasPrefix(filter, "#")) {
+		return false
+	}
+	fl := strings.Split(filter, "/")
+	tl := strings.Split(topic, "/")
+	for i, f := range fl {
+		if f == "#" {
+			return true
+		}
+		if i >= len(tl) {
+			return false
+		}
+		if f != "+" && f != tl[i] {
+			return false
+		}
+	}
+	return len(fl) == len(tl)
+}
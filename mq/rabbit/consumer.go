@@ -0,0 +1,99 @@
+package rabbit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+// ErrReject 处理函数返回该错误时消息被拒绝且不重新入队（进入死信交换机，若已配置）
+var ErrReject = errors.New("rabbitmq: reject message")
+
+// Handler 消息处理函数，返回nil时确认消息，返回错误时按Requeue配置拒绝
+type Handler func(ctx context.Context, d amqp.Delivery) error
+
+// ConsumeOptions 消费参数
+type ConsumeOptions struct {
+	Queue     string
+	Consumer  string // 消费者标签，为空时由服务端生成
+	Prefetch  int    // 预取数量，默认 10
+	Requeue   bool   // 处理失败时是否重新入队
+	Exclusive bool
+}
+
+// Consume 阻塞消费队列，连接断开后自动重新订阅，直到ctx被取消或客户端关闭
+func (c *Client) Consume(ctx context.Context, opts ConsumeOptions, handler Handler) error {
+	if opts.Queue == "" {
+		return fmt.Errorf("rabbitmq consume queue must be provided")
+	}
+	if handler == nil {
+		return fmt.Errorf("rabbitmq consume handler must be provided")
+	}
+	if opts.Prefetch <= 0 {
+		opts.Prefetch = 10
+	}
+
+	for {
+		err := c.consumeOnce(ctx, opts, handler)
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-c.closed:
+			return nil
+		default:
+		}
+		if err != nil {
+			log.Printf("RabbitMQ 消费队列 %s 中断: %v", opts.Queue, err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-c.closed:
+			return nil
+		case <-time.After(c.config.ReconnectDelay):
+		}
+	}
+}
+
+// consumeOnce 打开通道并消费，直到通道关闭
+func (c *Client) consumeOnce(ctx context.Context, opts ConsumeOptions, handler Handler) error {
+	ch, err := c.channel()
+	if err != nil {
+		return err
+	}
+	defer ch.Close()
+
+	if err := ch.Qos(opts.Prefetch, 0, false); err != nil {
+		return fmt.Errorf("failed to set qos: %w", err)
+	}
+	deliveries, err := ch.ConsumeWithContext(ctx, opts.Queue, opts.Consumer, false, opts.Exclusive, false, false, nil)
+	if err != nil {
+		return fmt.Errorf("failed to consume queue %s: %w", opts.Queue, err)
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case d, ok := <-deliveries:
+			if !ok {
+				return fmt.Errorf("delivery channel closed")
+			}
+			if err := handler(ctx, d); err != nil {
+				requeue := opts.Requeue && !errors.Is(err, ErrReject)
+				if nackErr := d.Nack(false, requeue); nackErr != nil {
+					return fmt.Errorf("failed to nack message: %w", nackErr)
+				}
+				continue
+			}
+			if err := d.Ack(false); err != nil {
+				return fmt.Errorf("failed to ack message: %w", err)
+			}
+		}
+	}
+}
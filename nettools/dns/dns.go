@@ -0,0 +1,255 @@
+// Package dns 提供DNS诊断查询，支持指定解析服务器、超时、响应时间测量和DNSSEC标志位检查。
+package dns
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/miekg/dns"
+)
+
+// Config 配置结构
+type Config struct {
+	Servers          []string      // 解析服务器，host 或 host:port，为空时读取 /etc/resolv.conf
+	Net              string        // udp/tcp/tcp-tls，默认 udp，UDP 响应被截断时自动改用 TCP 重试
+	Timeout          time.Duration // 单次查询超时时间，默认 3s
+	Retries          int           // 单个服务器失败后的重试次数
+	DNSSEC           bool          // 设置 DO 位请求 DNSSEC 记录，并设置 AD 位要求服务器返回验证结果
+	CheckingDisabled bool          // 设置 CD 位，要求服务器不做 DNSSEC 验证
+	NoRecursion      bool          // 不设置 RD 位，用于直接查询权威服务器
+}
+
+// Record 解析记录
+type Record struct {
+	Name     string `json:"name"`
+	Type     string `json:"type"`
+	TTL      uint32 `json:"ttl"`
+	Value    string `json:"value"`
+	Priority uint16 `json:"priority,omitempty"` // MX 优先级
+}
+
+// Result 查询结果
+type Result struct {
+	Server        string        `json:"server"`
+	Name          string        `json:"name"`
+	Type          string        `json:"type"`
+	Rcode         string        `json:"rcode"`
+	RTT           time.Duration `json:"rtt"`
+	Records       []Record      `json:"records"`
+	Authenticated bool          `json:"authenticated"` // AD 位，服务器已完成 DNSSEC 验证
+	Signed        bool          `json:"signed"`        // 应答包含 RRSIG 记录
+	Truncated     bool          `json:"truncated"`
+	Err           error         `json:"-"`
+}
+
+// Success 查询成功且返回码为 NOERROR
+func (r *Result) Success() bool {
+	return r.Err == nil && r.Rcode == dns.RcodeToString[dns.RcodeSuccess]
+}
+
+// Values 返回记录值列表
+func (r *Result) Values() []string {
+	values := make([]string, 0, len(r.Records))
+	for _, rec := range r.Records {
+		values = append(values, rec.Value)
+	}
+	return values
+}
+
+// Resolver DNS解析器
+type Resolver struct {
+	config  Config
+	servers []string
+}
+
+// NewResolver 创建解析器
+func NewResolver(config Config) (*Resolver, error) {
+	if config.Timeout <= 0 {
+		config.Timeout = 3 * time.Second
+	}
+	if config.Net == "" {
+		config.Net = "udp"
+	}
+	servers := config.Servers
+	if len(servers) == 0 {
+		cc, err := dns.ClientConfigFromFile("/etc/resolv.conf")
+		if err != nil {
+			return nil, fmt.Errorf("no dns servers configured: %w", err)
+		}
+		for _, s := range cc.Servers {
+			servers = append(servers, net.JoinHostPort(s, cc.Port))
+		}
+	}
+	port := "53"
+	if config.Net == "tcp-tls" {
+		port = "853"
+	}
+	r := &Resolver{config: config}
+	for _, s := range servers {
+		if _, _, err := net.SplitHostPort(s); err != nil {
+			s = net.JoinHostPort(strings.Trim(s, "[]"), port)
+		}
+		r.servers = append(r.servers, s)
+	}
+	return r, nil
+}
+
+// Servers 返回解析服务器列表
+func (r *Resolver) Servers() []string {
+	return r.servers
+}
+
+// Lookup 依次向各解析服务器查询，返回第一个得到应答的结果；qtype 如 A、AAAA、CNAME、MX、TXT、PTR、NS
+func (r *Resolver) Lookup(ctx context.Context, name, qtype string) (*Result, error) {
+	var last *Result
+	for _, server := range r.servers {
+		last = r.Query(ctx, server, name, qtype)
+		if last.Err == nil {
+			return last, nil
+		}
+		if ctx.Err() != nil {
+			break
+		}
+	}
+	if last == nil {
+		return nil, fmt.Errorf("no dns servers configured")
+	}
+	return last, last.Err
+}
+
+// LookupAll 并发向全部解析服务器查询，用于对比各服务器的应答和响应时间
+func (r *Resolver) LookupAll(ctx context.Context, name, qtype string) []*Result {
+	results := make([]*Result, len(r.servers))
+	var wg sync.WaitGroup
+	for i, server := range r.servers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i] = r.Query(ctx, server, name, qtype)
+		}()
+	}
+	wg.Wait()
+	return results
+}
+
+// Query 向指定服务器查询，失败时按 Retries 重试，错误记录在 Result.Err 中
+func (r *Resolver) Query(ctx context.Context, server, name, qtype string) *Result {
+	result := &Result{Server: server, Name: dns.Fqdn(name), Type: strings.ToUpper(qtype)}
+	t, ok := dns.StringToType[result.Type]
+	if !ok {
+		result.Err = fmt.Errorf("unsupported dns type %s", qtype)
+		return result
+	}
+
+	msg := new(dns.Msg)
+	msg.SetQuestion(result.Name, t)
+	msg.RecursionDesired = !r.config.NoRecursion
+	msg.CheckingDisabled = r.config.CheckingDisabled
+	if r.config.DNSSEC {
+		msg.AuthenticatedData = true
+		msg.SetEdns0(4096, true)
+	}
+
+	var resp *dns.Msg
+	var err error
+	for attempt := 0; attempt <= r.config.Retries; attempt++ {
+		resp, result.RTT, err = r.exchange(ctx, msg, server, r.config.Net)
+		if err == nil || ctx.Err() != nil {
+			break
+		}
+	}
+	if err != nil {
+		result.Err = fmt.Errorf("query %s %s @%s: %w", result.Type, name, server, err)
+		return result
+	}
+	result.Truncated = resp.Truncated
+	if resp.Truncated && r.config.Net == "udp" {
+		if tcpResp, rtt, err := r.exchange(ctx, msg, server, "tcp"); err == nil {
+			resp, result.RTT = tcpResp, result.RTT+rtt
+		}
+	}
+
+	result.Rcode = dns.RcodeToString[resp.Rcode]
+	result.Authenticated = resp.AuthenticatedData
+	for _, rr := range resp.Answer {
+		if _, ok := rr.(*dns.RRSIG); ok {
+			result.Signed = true
+			continue
+		}
+		result.Records = append(result.Records, toRecord(rr))
+	}
+	return result
+}
+
+func (r *Resolver) exchange(ctx context.Context, msg *dns.Msg, server, network string) (*dns.Msg, time.Duration, error) {
+	client := &dns.Client{Net: network, Timeout: r.config.Timeout}
+	if r.config.DNSSEC {
+		client.UDPSize = 4096
+	}
+	return client.ExchangeContext(ctx, msg, server)
+}
+
+// toRecord 转换应答记录
+func toRecord(rr dns.RR) Record {
+	h := rr.Header()
+	rec := Record{Name: h.Name, Type: dns.TypeToString[h.Rrtype], TTL: h.Ttl}
+	switch v := rr.(type) {
+	case *dns.A:
+		rec.Value = v.A.String()
+	case *dns.AAAA:
+		rec.Value = v.AAAA.String()
+	case *dns.CNAME:
+		rec.Value = v.Target
+	case *dns.MX:
+		rec.Value = v.Mx
+		rec.Priority = v.Preference
+	case *dns.TXT:
+		rec.Value = strings.Join(v.Txt, "")
+	case *dns.PTR:
+		rec.Value = v.Ptr
+	case *dns.NS:
+		rec.Value = v.Ns
+	default:
+		// 其余类型取记录文本中头部之后的部分
+		rec.Value = strings.TrimPrefix(rr.String(), h.String())
+	}
+	return rec
+}
+
+// LookupA 查询 A 记录
+func (r *Resolver) LookupA(ctx context.Context, name string) (*Result, error) {
+	return r.Lookup(ctx, name, "A")
+}
+
+// LookupAAAA 查询 AAAA 记录
+func (r *Resolver) LookupAAAA(ctx context.Context, name string) (*Result, error) {
+	return r.Lookup(ctx, name, "AAAA")
+}
+
+// LookupCNAME 查询 CNAME 记录
+func (r *Resolver) LookupCNAME(ctx context.Context, name string) (*Result, error) {
+	return r.Lookup(ctx, name, "CNAME")
+}
+
+// LookupMX 查询 MX 记录
+func (r *Resolver) LookupMX(ctx context.Context, name string) (*Result, error) {
+	return r.Lookup(ctx, name, "MX")
+}
+
+// LookupTXT 查询 TXT 记录
+func (r *Resolver) LookupTXT(ctx context.Context, name string) (*Result, error) {
+	return r.Lookup(ctx, name, "TXT")
+}
+
+// LookupPTR 反向解析IP地址
+func (r *Resolver) LookupPTR(ctx context.Context, ip string) (*Result, error) {
+	arpa, err := dns.ReverseAddr(ip)
+	if err != nil {
+		return nil, fmt.Errorf("invalid ip %s: %w", ip, err)
+	}
+	return r.Lookup(ctx, arpa, "PTR")
+}
@@ -0,0 +1,194 @@
+// Package ntp 查询NTP服务器，计算本机时钟偏移和层级，并提供偏移阈值告警检查。
+package ntp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/beevik/ntp"
+)
+
+// ErrOffsetExceeded 时钟偏移超过阈值
+var ErrOffsetExceeded = errors.New("ntp: clock offset exceeds threshold")
+
+// Config 配置结构
+type Config struct {
+	Servers    []string      // NTP 服务器，host 或 host:port，默认 ntp.aliyun.com、cn.pool.ntp.org
+	Timeout    time.Duration // 单个服务器查询超时时间，默认 5s
+	MaxOffset  time.Duration // 告警阈值，偏移绝对值超过时 Check 返回 ErrOffsetExceeded，默认 500ms
+	MaxStratum uint8         // 层级大于该值的应答视为不可用，默认 15
+	MinServers int           // 计算偏移所需的最少可用服务器数，默认 1
+}
+
+// ServerResult 单个服务器的查询结果
+type ServerResult struct {
+	Server       string        `json:"server"`
+	Offset       time.Duration `json:"offset"`
+	RTT          time.Duration `json:"rtt"`
+	Stratum      uint8         `json:"stratum"`
+	ReferenceID  string        `json:"reference_id"`
+	RootDistance time.Duration `json:"root_distance"`
+	Leap         string        `json:"leap"`
+	Err          error         `json:"-"`
+}
+
+// Report 一次检查的汇总结果
+type Report struct {
+	Time      time.Time      `json:"time"`
+	Offset    time.Duration  `json:"offset"`  // 可用服务器偏移的中位数
+	Stratum   uint8          `json:"stratum"` // 可用服务器中的最小层级
+	Valid     int            `json:"valid"`   // 可用服务器数
+	Exceeded  bool           `json:"exceeded"`
+	Servers   []ServerResult `json:"servers"`
+	Threshold time.Duration  `json:"threshold"`
+}
+
+// Checker NTP 偏移检查器
+type Checker struct {
+	config Config
+}
+
+// NewChecker 创建检查器
+func NewChecker(config Config) *Checker {
+	if len(config.Servers) == 0 {
+		config.Servers = []string{"ntp.aliyun.com", "cn.pool.ntp.org"}
+	}
+	if config.Timeout <= 0 {
+		config.Timeout = 5 * time.Second
+	}
+	if config.MaxOffset <= 0 {
+		config.MaxOffset = 500 * time.Millisecond
+	}
+	if config.MaxStratum == 0 {
+		config.MaxStratum = 15
+	}
+	if config.MinServers <= 0 {
+		config.MinServers = 1
+	}
+	return &Checker{config: config}
+}
+
+// Query 查询单个服务器
+func (c *Checker) Query(server string) ServerResult {
+	result := ServerResult{Server: server}
+	resp, err := ntp.QueryWithOptions(server, ntp.QueryOptions{Timeout: c.config.Timeout})
+	if err != nil {
+		result.Err = fmt.Errorf("query %s: %w", server, err)
+		return result
+	}
+	result.Offset = resp.ClockOffset
+	result.RTT = resp.RTT
+	result.Stratum = resp.Stratum
+	result.ReferenceID = resp.ReferenceString()
+	result.RootDistance = resp.RootDistance
+	result.Leap = leapString(resp.Leap)
+	if err := resp.Validate(); err != nil {
+		result.Err = fmt.Errorf("invalid response from %s: %w", server, err)
+	} else if resp.Stratum > c.config.MaxStratum {
+		result.Err = fmt.Errorf("%s stratum %d exceeds %d", server, resp.Stratum, c.config.MaxStratum)
+	}
+	return result
+}
+
+// Check 并发查询全部服务器，以可用服务器偏移的中位数作为本机时钟偏移；可用服务器不足时返回错误，偏移超过阈值时返回 ErrOffsetExceeded
+func (c *Checker) Check(ctx context.Context) (*Report, error) {
+	report := &Report{Time: time.Now(), Threshold: c.config.MaxOffset, Servers: make([]ServerResult, len(c.config.Servers))}
+	var wg sync.WaitGroup
+	for i, server := range c.config.Servers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			report.Servers[i] = c.Query(server)
+		}()
+	}
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+
+	var offsets []time.Duration
+	var lastErr error
+	for _, r := range report.Servers {
+		if r.Err != nil {
+			lastErr = r.Err
+			continue
+		}
+		offsets = append(offsets, r.Offset)
+		if report.Valid == 0 || r.Stratum < report.Stratum {
+			report.Stratum = r.Stratum
+		}
+		report.Valid++
+	}
+	if report.Valid < c.config.MinServers {
+		return report, fmt.Errorf("ntp: only %d of %d servers available: %v", report.Valid, len(c.config.Servers), lastErr)
+	}
+
+	sort.Slice(offsets, func(i, j int) bool { return offsets[i] < offsets[j] })
+	mid := len(offsets) / 2
+	if len(offsets)%2 == 0 {
+		report.Offset = (offsets[mid-1] + offsets[mid]) / 2
+	} else {
+		report.Offset = offsets[mid]
+	}
+	if abs(report.Offset) > c.config.MaxOffset {
+		report.Exceeded = true
+		return report, fmt.Errorf("%w: offset %s, threshold %s", ErrOffsetExceeded, report.Offset, c.config.MaxOffset)
+	}
+	return report, nil
+}
+
+// HealthCheck 返回可注册到 health.Registry 的检查函数
+func (c *Checker) HealthCheck() func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		_, err := c.Check(ctx)
+		return err
+	}
+}
+
+// Watch 按间隔定时检查并回调结果，直到ctx取消
+func (c *Checker) Watch(ctx context.Context, interval time.Duration, fn func(*Report, error)) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		report, err := c.Check(ctx)
+		if ctx.Err() != nil {
+			return
+		}
+		fn(report, err)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func abs(d time.Duration) time.Duration {
+	if d < 0 {
+		return -d
+	}
+	return d
+}
+
+func leapString(l ntp.LeapIndicator) string {
+	switch l {
+	case ntp.LeapNoWarning:
+		return "none"
+	case ntp.LeapAddSecond:
+		return "add"
+	case ntp.LeapDelSecond:
+		return "delete"
+	default:
+		return "unsynchronized"
+	}
+}
@@ -0,0 +1,112 @@
+package probe
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// HTTPOptions HTTP(S) 探测参数
+type HTTPOptions struct {
+	Method             string            // 请求方法，默认 GET
+	Headers            map[string]string // 请求头
+	Count              int               // 请求次数，默认 1
+	Interval           time.Duration     // 请求间隔，默认 1s
+	Timeout            time.Duration     // 单次请求超时时间，默认 10s
+	InsecureSkipVerify bool              // 跳过证书校验
+	ExpectStatus       []int             // 期望的状态码，默认小于400视为成功
+}
+
+func (o *HTTPOptions) defaults() {
+	if o.Method == "" {
+		o.Method = http.MethodGet
+	}
+	if o.Count <= 0 {
+		o.Count = 1
+	}
+	if o.Interval <= 0 {
+		o.Interval = time.Second
+	}
+	if o.Timeout <= 0 {
+		o.Timeout = 10 * time.Second
+	}
+}
+
+func (o *HTTPOptions) expected(code int) bool {
+	if len(o.ExpectStatus) == 0 {
+		return code < 400
+	}
+	for _, c := range o.ExpectStatus {
+		if c == code {
+			return true
+		}
+	}
+	return false
+}
+
+// HTTPProbe 请求URL并统计响应时延，每次请求使用新连接，时延包含建连和TLS握手
+func HTTPProbe(ctx context.Context, url string, opts HTTPOptions) *Result {
+	opts.defaults()
+	client := &http.Client{
+		Timeout: opts.Timeout,
+		Transport: &http.Transport{
+			Proxy:             http.ProxyFromEnvironment,
+			DisableKeepAlives: true,
+			TLSClientConfig:   &tls.Config{InsecureSkipVerify: opts.InsecureSkipVerify},
+		},
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+
+	var rtts []time.Duration
+	var lastErr error
+	var status int
+	sent := 0
+	for i := 0; i < opts.Count; i++ {
+		if i > 0 && !sleepContext(ctx, opts.Interval) {
+			break
+		}
+		sent++
+		code, rtt, err := httpOnce(ctx, client, url, &opts)
+		if code != 0 {
+			status = code
+		}
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		rtts = append(rtts, rtt)
+	}
+	r := newResult(url, TypeHTTP, url, sent, rtts)
+	r.StatusCode = uint16(status)
+	if lastErr != nil && !r.Success {
+		r.Error = lastErr.Error()
+	}
+	return r
+}
+
+func httpOnce(ctx context.Context, client *http.Client, url string, opts *HTTPOptions) (int, time.Duration, error) {
+	req, err := http.NewRequestWithContext(ctx, opts.Method, url, nil)
+	if err != nil {
+		return 0, 0, err
+	}
+	for k, v := range opts.Headers {
+		req.Header.Set(k, v)
+	}
+	start := time.Now()
+	resp, err := client.Do(req)
+	if err != nil {
+		return 0, 0, err
+	}
+	_, _ = io.Copy(io.Discard, resp.Body)
+	resp.Body.Close()
+	rtt := time.Since(start)
+	if !opts.expected(resp.StatusCode) {
+		return resp.StatusCode, rtt, fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+	return resp.StatusCode, rtt, nil
+}
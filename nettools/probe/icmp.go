@@ -0,0 +1,71 @@
+package probe
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	probing "github.com/prometheus-community/pro-bing"
+)
+
+// PingOptions ICMP 探测参数
+type PingOptions struct {
+	Count      int           // 发送报文数，默认 4
+	Interval   time.Duration // 发送间隔，默认 1s
+	Timeout    time.Duration // 总超时时间，默认 Count*Interval+2s
+	Size       int           // 报文负载大小，默认 56
+	TTL        int           // 报文TTL，默认 64
+	Privileged bool          // 使用原始套接字（需 root 或 CAP_NET_RAW），否则使用非特权 UDP ICMP
+}
+
+func (o *PingOptions) defaults() {
+	if o.Count <= 0 {
+		o.Count = 4
+	}
+	if o.Interval <= 0 {
+		o.Interval = time.Second
+	}
+	if o.Timeout <= 0 {
+		o.Timeout = time.Duration(o.Count)*o.Interval + 2*time.Second
+	}
+	if o.Size <= 0 {
+		o.Size = 56
+	}
+	if o.TTL <= 0 {
+		o.TTL = 64
+	}
+}
+
+// Ping 对主机执行 ICMP 探测，统计时延、丢包率和抖动
+func Ping(ctx context.Context, host string, opts PingOptions) *Result {
+	opts.defaults()
+	pinger, err := probing.NewPinger(host)
+	if err != nil {
+		r := newResult(host, TypeICMP, host, opts.Count, nil)
+		r.Error = fmt.Sprintf("resolve %s: %v", host, err)
+		return r
+	}
+	pinger.Count = opts.Count
+	pinger.Interval = opts.Interval
+	pinger.Timeout = opts.Timeout
+	pinger.Size = opts.Size
+	pinger.TTL = opts.TTL
+	pinger.SetPrivileged(opts.Privileged)
+
+	if err := pinger.RunWithContext(ctx); err != nil {
+		r := newResult(host, TypeICMP, pinger.IPAddr().String(), opts.Count, nil)
+		r.Error = fmt.Sprintf("ping %s: %v", host, err)
+		return r
+	}
+	stats := pinger.Statistics()
+	// 超时或取消时 PacketsSent 可能小于 Count，按实际发送数计算丢包
+	sent := stats.PacketsSent
+	if sent == 0 {
+		sent = opts.Count
+	}
+	r := newResult(host, TypeICMP, stats.IPAddr.String(), sent, stats.Rtts)
+	if !r.Success {
+		r.Error = "request timeout"
+	}
+	return r
+}
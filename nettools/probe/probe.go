@@ -0,0 +1,115 @@
+// Package probe 提供 ICMP ping、TCP 连接和 HTTP(S) 探测，以及并发多目标探测器，结果可直接写入 ClickHouse。
+package probe
+
+import (
+	"context"
+	"time"
+
+	"github.com/ixxmi/tools/db/ckgroup"
+)
+
+// 探测类型
+const (
+	TypeICMP = "icmp"
+	TypeTCP  = "tcp"
+	TypeHTTP = "http"
+)
+
+// Result 探测结果，db 标签与 ckgroup.BatchInsert 的列名对应，建表见 Columns
+type Result struct {
+	Target     string    `db:"target"`      // 目标名称
+	Type       string    `db:"probe_type"`  // icmp/tcp/http
+	Address    string    `db:"address"`     // 实际探测地址
+	Sent       uint32    `db:"sent"`        // 发送次数
+	Received   uint32    `db:"received"`    // 成功次数
+	Loss       float64   `db:"loss"`        // 丢包率，百分比
+	MinRTT     float64   `db:"rtt_min_ms"`  // 最小时延，毫秒
+	AvgRTT     float64   `db:"rtt_avg_ms"`  // 平均时延，毫秒
+	MaxRTT     float64   `db:"rtt_max_ms"`  // 最大时延，毫秒
+	Jitter     float64   `db:"jitter_ms"`   // 抖动，相邻时延差的平均值，毫秒
+	StatusCode uint16    `db:"status_code"` // HTTP 状态码
+	Success    bool      `db:"success"`
+	Error      string    `db:"error"`
+	CreatedAt  time.Time `db:"created_at"`
+}
+
+// Columns 返回 Result 对应的 ClickHouse 列定义，用于 ckgroup.CreateTable
+func Columns() []ckgroup.Column {
+	return []ckgroup.Column{
+		{Name: "target", Type: "LowCardinality(String)"},
+		{Name: "probe_type", Type: "LowCardinality(String)"},
+		{Name: "address", Type: "String"},
+		{Name: "sent", Type: "UInt32"},
+		{Name: "received", Type: "UInt32"},
+		{Name: "loss", Type: "Float64"},
+		{Name: "rtt_min_ms", Type: "Float64"},
+		{Name: "rtt_avg_ms", Type: "Float64"},
+		{Name: "rtt_max_ms", Type: "Float64"},
+		{Name: "jitter_ms", Type: "Float64"},
+		{Name: "status_code", Type: "UInt16"},
+		{Name: "success", Type: "Bool"},
+		{Name: "error", Type: "String"},
+		{Name: "created_at", Type: "DateTime64(3)"},
+	}
+}
+
+// newResult 根据时延样本计算统计值
+func newResult(target, typ, address string, sent int, rtts []time.Duration) *Result {
+	r := &Result{
+		Target:    target,
+		Type:      typ,
+		Address:   address,
+		Sent:      uint32(sent),
+		Received:  uint32(len(rtts)),
+		CreatedAt: time.Now(),
+	}
+	if sent > 0 {
+		r.Loss = float64(sent-len(rtts)) / float64(sent) * 100
+	}
+	if len(rtts) == 0 {
+		return r
+	}
+	r.Success = true
+	min, max, sum := rtts[0], rtts[0], time.Duration(0)
+	var jitter time.Duration
+	for i, rtt := range rtts {
+		sum += rtt
+		if rtt < min {
+			min = rtt
+		}
+		if rtt > max {
+			max = rtt
+		}
+		if i > 0 {
+			d := rtt - rtts[i-1]
+			if d < 0 {
+				d = -d
+			}
+			jitter += d
+		}
+	}
+	r.MinRTT = ms(min)
+	r.MaxRTT = ms(max)
+	r.AvgRTT = ms(sum / time.Duration(len(rtts)))
+	if len(rtts) > 1 {
+		r.Jitter = ms(jitter / time.Duration(len(rtts)-1))
+	}
+	return r
+}
+
+// ms 转换为毫秒
+func ms(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
+
+// sleepContext 等待指定时间，ctx 取消时提前返回 false
+func sleepContext(ctx context.Context, d time.Duration) bool {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return false
+	case <-t.C:
+		return true
+	}
+}
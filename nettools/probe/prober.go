@@ -0,0 +1,131 @@
+package probe
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Target 探测目标
+type Target struct {
+	Name     string        // 目标名称，写入结果的 target 字段，默认使用 Address
+	Type     string        // icmp/tcp/http
+	Address  string        // icmp 为主机，tcp 为 host:port，http 为 URL
+	Interval time.Duration // 探测间隔，默认 1min
+	ICMP     PingOptions
+	TCP      TCPOptions
+	HTTP     HTTPOptions
+}
+
+// Prober 并发多目标探测器
+type Prober struct {
+	handler     func(*Result)
+	concurrency int
+	mu          sync.Mutex
+	targets     []Target
+}
+
+// NewProber 创建探测器，concurrency 为同时探测的最大目标数（默认 64）
+func NewProber(concurrency int, handler func(*Result)) *Prober {
+	if concurrency <= 0 {
+		concurrency = 64
+	}
+	return &Prober{handler: handler, concurrency: concurrency}
+}
+
+// Add 添加目标，需在 Run 之前调用
+func (p *Prober) Add(targets ...Target) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.targets = append(p.targets, targets...)
+}
+
+// Run 启动探测，每个目标启动后立即探测一次，之后按间隔探测，直到ctx取消
+func (p *Prober) Run(ctx context.Context) {
+	p.mu.Lock()
+	targets := append([]Target{}, p.targets...)
+	p.mu.Unlock()
+
+	sem := make(chan struct{}, p.concurrency)
+	var wg sync.WaitGroup
+	for _, target := range targets {
+		wg.Add(1)
+		go func(target Target) {
+			defer wg.Done()
+			p.loop(ctx, target, sem)
+		}(target)
+	}
+	wg.Wait()
+}
+
+// loop 单个目标的探测循环
+func (p *Prober) loop(ctx context.Context, target Target, sem chan struct{}) {
+	interval := target.Interval
+	if interval <= 0 {
+		interval = time.Minute
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case sem <- struct{}{}:
+			result := Probe(ctx, target)
+			<-sem
+			if ctx.Err() != nil {
+				return
+			}
+			p.handler(result)
+		case <-ctx.Done():
+			return
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Probe 对目标执行一次探测
+func Probe(ctx context.Context, target Target) *Result {
+	var r *Result
+	switch target.Type {
+	case TypeICMP:
+		r = Ping(ctx, target.Address, target.ICMP)
+	case TypeTCP:
+		r = TCPPing(ctx, target.Address, target.TCP)
+	case TypeHTTP:
+		r = HTTPProbe(ctx, target.Address, target.HTTP)
+	default:
+		r = newResult(target.Address, target.Type, target.Address, 0, nil)
+		r.Error = fmt.Sprintf("unsupported probe type %s", target.Type)
+	}
+	if target.Name != "" {
+		r.Target = target.Name
+	}
+	return r
+}
+
+// ProbeAll 并发探测全部目标一次，结果顺序与 targets 一致
+func ProbeAll(ctx context.Context, targets []Target, concurrency int) []*Result {
+	if concurrency <= 0 {
+		concurrency = 64
+	}
+	results := make([]*Result, len(targets))
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	for i, target := range targets {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+			results[i] = Probe(ctx, target)
+		}()
+	}
+	wg.Wait()
+	return results
+}
@@ -0,0 +1,56 @@
+package probe
+
+import (
+	"context"
+	"net"
+	"time"
+)
+
+// TCPOptions TCP 连接探测参数
+type TCPOptions struct {
+	Count    int           // 连接次数，默认 3
+	Interval time.Duration // 连接间隔，默认 500ms
+	Timeout  time.Duration // 单次连接超时时间，默认 3s
+}
+
+func (o *TCPOptions) defaults() {
+	if o.Count <= 0 {
+		o.Count = 3
+	}
+	if o.Interval <= 0 {
+		o.Interval = 500 * time.Millisecond
+	}
+	if o.Timeout <= 0 {
+		o.Timeout = 3 * time.Second
+	}
+}
+
+// TCPPing 多次建立 TCP 连接并统计握手时延，addr 为 host:port
+func TCPPing(ctx context.Context, addr string, opts TCPOptions) *Result {
+	opts.defaults()
+	dialer := &net.Dialer{Timeout: opts.Timeout}
+	var rtts []time.Duration
+	var lastErr error
+	sent := 0
+	address := addr
+	for i := 0; i < opts.Count; i++ {
+		if i > 0 && !sleepContext(ctx, opts.Interval) {
+			break
+		}
+		sent++
+		start := time.Now()
+		conn, err := dialer.DialContext(ctx, "tcp", addr)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		rtts = append(rtts, time.Since(start))
+		address = conn.RemoteAddr().String()
+		conn.Close()
+	}
+	r := newResult(addr, TypeTCP, address, sent, rtts)
+	if lastErr != nil && !r.Success {
+		r.Error = lastErr.Error()
+	}
+	return r
+}
@@ -0,0 +1,289 @@
+// Package email 提供 SMTP 邮件发送，支持 STARTTLS/SSL、HTML 正文和附件。
+package email
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/tls"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"net/textproto"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// 加密方式
+const (
+	TLSNone     = "none"     // 明文
+	TLSStartTLS = "starttls" // 明文连接后升级，默认
+	TLSSSL      = "ssl"      // 隐式 TLS，一般为 465 端口
+)
+
+// Config 配置结构
+type Config struct {
+	Host               string
+	Port               int           // 默认 ssl 为 465，其他为 25
+	Username           string        // 为空时不认证
+	Password           string        // 密码或授权码
+	From               string        // 发件人，如 "告警中心 <noreply@example.com>"
+	TLSMode            string        // none/starttls/ssl，默认 starttls（服务器不支持时回退明文）
+	InsecureSkipVerify bool          // 跳过证书校验
+	Timeout            time.Duration // 连接和发送超时时间，默认 30s
+}
+
+// Attachment 附件
+type Attachment struct {
+	Filename    string
+	ContentType string // 为空时按扩展名推断
+	Data        []byte
+}
+
+// Message 邮件
+type Message struct {
+	To          []string
+	Cc          []string
+	Bcc         []string
+	Subject     string
+	Text        string // 纯文本正文
+	HTML        string // HTML 正文，与 Text 同时设置时客户端优先显示 HTML
+	Attachments []Attachment
+}
+
+// Attach 添加附件
+func (m *Message) Attach(filename string, data []byte) {
+	m.Attachments = append(m.Attachments, Attachment{Filename: filename, Data: data})
+}
+
+// Client SMTP 客户端，每次发送建立新连接
+type Client struct {
+	config Config
+}
+
+// NewClient 创建客户端
+func NewClient(config Config) (*Client, error) {
+	if config.Host == "" {
+		return nil, fmt.Errorf("smtp host must be provided")
+	}
+	if _, err := mail.ParseAddress(config.From); err != nil {
+		return nil, fmt.Errorf("invalid from address %q: %w", config.From, err)
+	}
+	if config.TLSMode == "" {
+		config.TLSMode = TLSStartTLS
+	}
+	if config.Port == 0 {
+		config.Port = 25
+		if config.TLSMode == TLSSSL {
+			config.Port = 465
+		}
+	}
+	if config.Timeout <= 0 {
+		config.Timeout = 30 * time.Second
+	}
+	return &Client{config: config}, nil
+}
+
+// Send 发送邮件
+func (c *Client) Send(ctx context.Context, msg *Message) error {
+	if len(msg.To)+len(msg.Cc)+len(msg.Bcc) == 0 {
+		return fmt.Errorf("email recipients must be provided")
+	}
+	from, _ := mail.ParseAddress(c.config.From)
+	var rcpts []string
+	for _, list := range [][]string{msg.To, msg.Cc, msg.Bcc} {
+		for _, addr := range list {
+			a, err := mail.ParseAddress(addr)
+			if err != nil {
+				return fmt.Errorf("invalid recipient %q: %w", addr, err)
+			}
+			rcpts = append(rcpts, a.Address)
+		}
+	}
+	body, err := c.build(from, msg)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, c.config.Timeout)
+	defer cancel()
+	addr := net.JoinHostPort(c.config.Host, strconv.Itoa(c.config.Port))
+	tlsConfig := &tls.Config{ServerName: c.config.Host, InsecureSkipVerify: c.config.InsecureSkipVerify}
+	var conn net.Conn
+	dialer := &net.Dialer{}
+	if c.config.TLSMode == TLSSSL {
+		conn, err = (&tls.Dialer{NetDialer: dialer, Config: tlsConfig}).DialContext(ctx, "tcp", addr)
+	} else {
+		conn, err = dialer.DialContext(ctx, "tcp", addr)
+	}
+	if err != nil {
+		return fmt.Errorf("connect %s: %w", addr, err)
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+	sc, err := smtp.NewClient(conn, c.config.Host)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	defer sc.Close()
+
+	if c.config.TLSMode == TLSStartTLS {
+		if ok, _ := sc.Extension("STARTTLS"); ok {
+			if err := sc.StartTLS(tlsConfig); err != nil {
+				return fmt.Errorf("starttls: %w", err)
+			}
+		}
+	}
+	if c.config.Username != "" {
+		if err := sc.Auth(smtp.PlainAuth("", c.config.Username, c.config.Password, c.config.Host)); err != nil {
+			return fmt.Errorf("smtp auth: %w", err)
+		}
+	}
+	if err := sc.Mail(from.Address); err != nil {
+		return err
+	}
+	for _, rcpt := range rcpts {
+		if err := sc.Rcpt(rcpt); err != nil {
+			return fmt.Errorf("rcpt %s: %w", rcpt, err)
+		}
+	}
+	w, err := sc.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(body); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	return sc.Quit()
+}
+
+// build 生成 MIME 邮件内容
+func (c *Client) build(from *mail.Address, msg *Message) ([]byte, error) {
+	var buf bytes.Buffer
+	header := func(k, v string) {
+		buf.WriteString(k + ": " + v + "\r\n")
+	}
+	header("From", from.String())
+	if len(msg.To) > 0 {
+		header("To", formatList(msg.To))
+	}
+	if len(msg.Cc) > 0 {
+		header("Cc", formatList(msg.Cc))
+	}
+	header("Subject", mime.BEncoding.Encode("UTF-8", msg.Subject))
+	header("Date", time.Now().Format(time.RFC1123Z))
+	header("Message-ID", messageID(from.Address))
+	header("MIME-Version", "1.0")
+
+	mw := multipart.NewWriter(&buf)
+	header("Content-Type", "multipart/mixed; boundary="+mw.Boundary())
+	buf.WriteString("\r\n")
+
+	// 正文
+	var altBuf bytes.Buffer
+	alt := multipart.NewWriter(&altBuf)
+	if msg.Text != "" || msg.HTML == "" {
+		if err := writePart(alt, "text/plain; charset=UTF-8", []byte(msg.Text)); err != nil {
+			return nil, err
+		}
+	}
+	if msg.HTML != "" {
+		if err := writePart(alt, "text/html; charset=UTF-8", []byte(msg.HTML)); err != nil {
+			return nil, err
+		}
+	}
+	if err := alt.Close(); err != nil {
+		return nil, err
+	}
+	pw, err := mw.CreatePart(textproto.MIMEHeader{"Content-Type": {"multipart/alternative; boundary=" + alt.Boundary()}})
+	if err != nil {
+		return nil, err
+	}
+	if _, err := pw.Write(altBuf.Bytes()); err != nil {
+		return nil, err
+	}
+
+	for _, a := range msg.Attachments {
+		ct := a.ContentType
+		if ct == "" {
+			ct = mime.TypeByExtension(filepath.Ext(a.Filename))
+		}
+		if ct == "" {
+			ct = "application/octet-stream"
+		}
+		name := mime.BEncoding.Encode("UTF-8", a.Filename)
+		h := textproto.MIMEHeader{
+			"Content-Type":              {fmt.Sprintf("%s; name=%q", ct, name)},
+			"Content-Disposition":       {fmt.Sprintf("attachment; filename=%q", name)},
+			"Content-Transfer-Encoding": {"base64"},
+		}
+		pw, err := mw.CreatePart(h)
+		if err != nil {
+			return nil, err
+		}
+		if err := writeBase64(pw, a.Data); err != nil {
+			return nil, err
+		}
+	}
+	if err := mw.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func writePart(w *multipart.Writer, contentType string, data []byte) error {
+	pw, err := w.CreatePart(textproto.MIMEHeader{
+		"Content-Type":              {contentType},
+		"Content-Transfer-Encoding": {"base64"},
+	})
+	if err != nil {
+		return err
+	}
+	return writeBase64(pw, data)
+}
+
+// writeBase64 按每行76字符写出base64编码
+func writeBase64(w io.Writer, data []byte) error {
+	encoded := base64.StdEncoding.EncodeToString(data)
+	for len(encoded) > 76 {
+		if _, err := w.Write([]byte(encoded[:76] + "\r\n")); err != nil {
+			return err
+		}
+		encoded = encoded[76:]
+	}
+	_, err := w.Write([]byte(encoded + "\r\n"))
+	return err
+}
+
+func formatList(addrs []string) string {
+	out := make([]string, 0, len(addrs))
+	for _, addr := range addrs {
+		if a, err := mail.ParseAddress(addr); err == nil {
+			out = append(out, a.String())
+		}
+	}
+	return strings.Join(out, ", ")
+}
+
+func messageID(from string) string {
+	b := make([]byte, 12)
+	rand.Read(b)
+	domain := "localhost"
+	if i := strings.LastIndex(from, "@"); i >= 0 {
+		domain = from[i+1:]
+	}
+	return fmt.Sprintf("<%d.%s@%s>", time.Now().UnixNano(), hex.EncodeToString(b), domain)
+}
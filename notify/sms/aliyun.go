@@ -0,0 +1,246 @@
+package sms
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ixxmi/tools/httpclient"
+)
+
+// aliyunBatchSize 阿里云单次请求手机号上限
+const aliyunBatchSize = 1000
+
+// cst 服务商返回时间使用的北京时间
+var cst = time.FixedZone("CST", 8*3600)
+
+// Aliyun 阿里云短信
+type Aliyun struct {
+	config Config
+	client *httpclient.Client
+}
+
+// NewAliyun 创建阿里云短信服务商
+func NewAliyun(config Config) (*Aliyun, error) {
+	if config.AccessKeyID == "" || config.AccessKeySecret == "" {
+		return nil, fmt.Errorf("aliyun sms access key must be provided")
+	}
+	if config.Region == "" {
+		config.Region = "cn-hangzhou"
+	}
+	if config.Endpoint == "" {
+		config.Endpoint = "https://dysmsapi.aliyuncs.com"
+	}
+	if config.Timeout <= 0 {
+		config.Timeout = 10 * time.Second
+	}
+	return &Aliyun{
+		config: config,
+		// 短信不可重复发送，关闭自动重试
+		client: httpclient.NewClient(httpclient.Config{Timeout: config.Timeout, MaxRetries: -1}),
+	}, nil
+}
+
+// Name 服务商名称
+func (a *Aliyun) Name() string {
+	return ProviderAliyun
+}
+
+// Send 发送模板短信，同一批次的手机号共用一个 BizId
+func (a *Aliyun) Send(ctx context.Context, msg *Message) ([]SendResult, error) {
+	sign, err := msg.validate(a.config.SignName)
+	if err != nil {
+		return nil, err
+	}
+	params := map[string]string{}
+	for _, p := range msg.Params {
+		params[p.Key] = p.Value
+	}
+	templateParam, err := json.Marshal(params)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal template params: %w", err)
+	}
+
+	var results []SendResult
+	for _, phones := range chunk(msg.Phones, aliyunBatchSize) {
+		query := map[string]string{
+			"PhoneNumbers":  strings.Join(phones, ","),
+			"SignName":      sign,
+			"TemplateCode":  msg.Template,
+			"TemplateParam": string(templateParam),
+		}
+		if msg.OutID != "" {
+			query["OutId"] = msg.OutID
+		}
+
+		var resp struct {
+			Code    string
+			Message string
+			BizId   string
+		}
+		if err := a.call(ctx, "SendSms", query, &resp); err != nil {
+			results = append(results, failAll(phones, err)...)
+			return results, err
+		}
+		for _, phone := range phones {
+			results = append(results, SendResult{
+				Phone:     phone,
+				MessageID: resp.BizId,
+				Success:   resp.Code == "OK",
+				Code:      resp.Code,
+				Message:   resp.Message,
+			})
+		}
+	}
+	return results, nil
+}
+
+// Query 查询短信回执，仅支持查询最近30天
+func (a *Aliyun) Query(ctx context.Context, query *StatusQuery) ([]Status, error) {
+	if query.Phone == "" {
+		return nil, fmt.Errorf("sms query phone must be provided")
+	}
+	date := query.Date
+	if date.IsZero() {
+		date = time.Now()
+	}
+	limit := query.Limit
+	if limit <= 0 || limit > 50 {
+		limit = 50
+	}
+	params := map[string]string{
+		"PhoneNumber": query.Phone,
+		"SendDate":    date.In(cst).Format("20060102"),
+		"PageSize":    fmt.Sprint(limit),
+		"CurrentPage": "1",
+	}
+	if query.MessageID != "" {
+		params["BizId"] = query.MessageID
+	}
+
+	var resp struct {
+		Code              string
+		Message           string
+		SmsSendDetailDTOs struct {
+			SmsSendDetailDTO []struct {
+				PhoneNum    string
+				SendStatus  int
+				ErrCode     string
+				SendDate    string
+				ReceiveDate string
+				OutId       string
+			}
+		}
+	}
+	if err := a.call(ctx, "QuerySendDetails", params, &resp); err != nil {
+		return nil, err
+	}
+	if resp.Code != "OK" {
+		return nil, fmt.Errorf("aliyun sms query failed: %s %s", resp.Code, resp.Message)
+	}
+
+	details := resp.SmsSendDetailDTOs.SmsSendDetailDTO
+	statuses := make([]Status, 0, len(details))
+	for _, d := range details {
+		s := Status{
+			Phone:       d.PhoneNum,
+			MessageID:   query.MessageID,
+			Code:        d.ErrCode,
+			Description: d.ErrCode,
+			OutID:       d.OutId,
+		}
+		// SendStatus: 1 等待回执 2 发送失败 3 发送成功
+		switch d.SendStatus {
+		case 2:
+			s.State = StateFailed
+		case 3:
+			s.State = StateSuccess
+		default:
+			s.State = StatePending
+		}
+		s.SendTime, _ = time.ParseInLocation("2006-01-02 15:04:05", d.SendDate, cst)
+		s.ReceiveTime, _ = time.ParseInLocation("2006-01-02 15:04:05", d.ReceiveDate, cst)
+		statuses = append(statuses, s)
+	}
+	return statuses, nil
+}
+
+// call 签名并调用阿里云RPC接口
+func (a *Aliyun) call(ctx context.Context, action string, params map[string]string, dest interface{}) error {
+	query := map[string]string{
+		"AccessKeyId":      a.config.AccessKeyID,
+		"Action":           action,
+		"Format":           "JSON",
+		"RegionId":         a.config.Region,
+		"SignatureMethod":  "HMAC-SHA1",
+		"SignatureNonce":   nonce(),
+		"SignatureVersion": "1.0",
+		"Timestamp":        time.Now().UTC().Format("2006-01-02T15:04:05Z"),
+		"Version":          "2017-05-25",
+	}
+	for k, v := range params {
+		query[k] = v
+	}
+	canonical := canonicalQuery(query)
+	stringToSign := "GET&" + percentEncode("/") + "&" + percentEncode(canonical)
+	mac := hmac.New(sha1.New, []byte(a.config.AccessKeySecret+"&"))
+	mac.Write([]byte(stringToSign))
+	signature := base64.StdEncoding.EncodeToString(mac.Sum(nil))
+
+	target := strings.TrimSuffix(a.config.Endpoint, "/") + "/?Signature=" + percentEncode(signature) + "&" + canonical
+	resp, err := a.client.R(ctx).Get(target)
+	if err != nil {
+		return fmt.Errorf("aliyun sms %s: %w", action, err)
+	}
+	// 鉴权等错误返回4xx，但响应体格式一致，统一按JSON解析
+	if err := resp.JSON(dest); err != nil {
+		if respErr := resp.Error(); respErr != nil {
+			return fmt.Errorf("aliyun sms %s: %w", action, respErr)
+		}
+		return err
+	}
+	if !resp.IsSuccess() {
+		var body struct{ Code, Message string }
+		_ = resp.JSON(&body)
+		return fmt.Errorf("aliyun sms %s: %s %s", action, body.Code, body.Message)
+	}
+	return nil
+}
+
+// canonicalQuery 按参数名排序并编码
+func canonicalQuery(params map[string]string) string {
+	keys := make([]string, 0, len(params))
+	for k := range params {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	parts := make([]string, len(keys))
+	for i, k := range keys {
+		parts[i] = percentEncode(k) + "=" + percentEncode(params[k])
+	}
+	return strings.Join(parts, "&")
+}
+
+// percentEncode 阿里云要求的RFC3986编码
+func percentEncode(s string) string {
+	s = url.QueryEscape(s)
+	s = strings.ReplaceAll(s, "+", "%20")
+	s = strings.ReplaceAll(s, "*", "%2A")
+	return strings.ReplaceAll(s, "%7E", "~")
+}
+
+// nonce 生成随机串
+func nonce() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,166 @@
+// Package sms 提供短信网关抽象，支持阿里云和腾讯云短信的模板发送、批量发送和回执查询。
+package sms
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// 短信服务商
+const (
+	ProviderAliyun  = "aliyun"
+	ProviderTencent = "tencent"
+)
+
+// 发送状态
+const (
+	StatePending = "pending" // 等待回执
+	StateSuccess = "success" // 用户已接收
+	StateFailed  = "failed"  // 发送失败
+)
+
+// Config 配置结构
+type Config struct {
+	Provider        string        // 服务商 aliyun/tencent
+	AccessKeyID     string        // 阿里云 AccessKeyId / 腾讯云 SecretId
+	AccessKeySecret string        // 阿里云 AccessKeySecret / 腾讯云 SecretKey
+	Region          string        // 地域，阿里云默认 cn-hangzhou，腾讯云默认 ap-guangzhou
+	AppID           string        // 腾讯云短信应用 SmsSdkAppId
+	SignName        string        // 默认签名
+	Endpoint        string        // 自定义接入地址，默认使用服务商公网地址
+	Timeout         time.Duration // 请求超时时间，默认 10s
+}
+
+// Param 模板参数
+type Param struct {
+	Key   string
+	Value string
+}
+
+// Message 短信消息
+type Message struct {
+	Phones   []string // 手机号，超过服务商单次上限时自动分批发送
+	SignName string   // 签名，为空时使用 Config.SignName
+	Template string   // 模板编号，阿里云 TemplateCode / 腾讯云 TemplateId
+	Params   []Param  // 模板参数，阿里云按 Key 填充，腾讯云按顺序填充
+	OutID    string   // 外部流水号，回执中原样返回
+}
+
+// SendResult 单个手机号的发送结果
+type SendResult struct {
+	Phone     string
+	MessageID string // 阿里云 BizId / 腾讯云 SerialNo，用于查询回执
+	Success   bool
+	Code      string
+	Message   string
+}
+
+// StatusQuery 回执查询条件
+type StatusQuery struct {
+	Phone     string    // 手机号，必填
+	MessageID string    // 发送时返回的 MessageID，可选
+	Date      time.Time // 发送日期，默认当天
+	Limit     int       // 最大返回条数，默认 50
+}
+
+// Status 短信回执
+type Status struct {
+	Phone       string
+	MessageID   string
+	State       string // StatePending/StateSuccess/StateFailed
+	Code        string
+	Description string
+	SendTime    time.Time
+	ReceiveTime time.Time
+	OutID       string
+}
+
+// Provider 短信服务商接口
+type Provider interface {
+	// Name 服务商名称
+	Name() string
+	// Send 发送模板短信，返回每个手机号的发送结果；请求失败时返回错误
+	Send(ctx context.Context, msg *Message) ([]SendResult, error)
+	// Query 查询短信回执
+	Query(ctx context.Context, query *StatusQuery) ([]Status, error)
+}
+
+// New 根据配置创建短信服务商
+func New(config Config) (Provider, error) {
+	switch strings.ToLower(config.Provider) {
+	case ProviderAliyun:
+		return NewAliyun(config)
+	case ProviderTencent:
+		return NewTencent(config)
+	}
+	return nil, fmt.Errorf("unsupported sms provider %s", config.Provider)
+}
+
+// SendAll 发送多条短信，遇到请求错误时继续发送剩余消息，返回全部结果和第一个错误
+func SendAll(ctx context.Context, p Provider, msgs []*Message) ([]SendResult, error) {
+	var (
+		results  []SendResult
+		firstErr error
+	)
+	for _, msg := range msgs {
+		res, err := p.Send(ctx, msg)
+		results = append(results, res...)
+		if err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return results, firstErr
+}
+
+// Failed 返回发送失败的结果
+func Failed(results []SendResult) []SendResult {
+	var failed []SendResult
+	for _, r := range results {
+		if !r.Success {
+			failed = append(failed, r)
+		}
+	}
+	return failed
+}
+
+// validate 校验消息
+func (m *Message) validate(defaultSign string) (string, error) {
+	if len(m.Phones) == 0 {
+		return "", fmt.Errorf("sms phones must be provided")
+	}
+	if m.Template == "" {
+		return "", fmt.Errorf("sms template must be provided")
+	}
+	sign := m.SignName
+	if sign == "" {
+		sign = defaultSign
+	}
+	if sign == "" {
+		return "", fmt.Errorf("sms sign name must be provided")
+	}
+	return sign, nil
+}
+
+// chunk 按size切分手机号
+func chunk(phones []string, size int) [][]string {
+	var chunks [][]string
+	for len(phones) > size {
+		chunks = append(chunks, phones[:size])
+		phones = phones[size:]
+	}
+	if len(phones) > 0 {
+		chunks = append(chunks, phones)
+	}
+	return chunks
+}
+
+// failAll 请求失败时为一批手机号生成失败结果
+func failAll(phones []string, err error) []SendResult {
+	results := make([]SendResult, len(phones))
+	for i, phone := range phones {
+		results[i] = SendResult{Phone: phone, Code: "RequestError", Message: err.Error()}
+	}
+	return results
+}
@@ -0,0 +1,262 @@
+package sms
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ixxmi/tools/httpclient"
+)
+
+// tencentBatchSize 腾讯云单次请求手机号上限
+const tencentBatchSize = 200
+
+// Tencent 腾讯云短信
+type Tencent struct {
+	config Config
+	host   string
+	client *httpclient.Client
+}
+
+// NewTencent 创建腾讯云短信服务商
+func NewTencent(config Config) (*Tencent, error) {
+	if config.AccessKeyID == "" || config.AccessKeySecret == "" {
+		return nil, fmt.Errorf("tencent sms secret must be provided")
+	}
+	if config.AppID == "" {
+		return nil, fmt.Errorf("tencent sms app id must be provided")
+	}
+	if config.Region == "" {
+		config.Region = "ap-guangzhou"
+	}
+	if config.Endpoint == "" {
+		config.Endpoint = "https://sms.tencentcloudapi.com"
+	}
+	if config.Timeout <= 0 {
+		config.Timeout = 10 * time.Second
+	}
+	u, err := url.Parse(config.Endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("invalid endpoint %s: %w", config.Endpoint, err)
+	}
+	return &Tencent{
+		config: config,
+		host:   u.Host,
+		// 短信不可重复发送，关闭自动重试
+		client: httpclient.NewClient(httpclient.Config{Timeout: config.Timeout, MaxRetries: -1}),
+	}, nil
+}
+
+// Name 服务商名称
+func (t *Tencent) Name() string {
+	return ProviderTencent
+}
+
+// Send 发送模板短信，未带国家码的手机号按中国大陆 +86 处理
+func (t *Tencent) Send(ctx context.Context, msg *Message) ([]SendResult, error) {
+	sign, err := msg.validate(t.config.SignName)
+	if err != nil {
+		return nil, err
+	}
+	params := make([]string, len(msg.Params))
+	for i, p := range msg.Params {
+		params[i] = p.Value
+	}
+
+	var results []SendResult
+	for _, phones := range chunk(msg.Phones, tencentBatchSize) {
+		numbers := make([]string, len(phones))
+		for i, phone := range phones {
+			numbers[i] = e164(phone)
+		}
+		req := map[string]interface{}{
+			"PhoneNumberSet":   numbers,
+			"SmsSdkAppId":      t.config.AppID,
+			"SignName":         sign,
+			"TemplateId":       msg.Template,
+			"TemplateParamSet": params,
+		}
+		if msg.OutID != "" {
+			req["SessionContext"] = msg.OutID
+		}
+
+		var resp struct {
+			SendStatusSet []struct {
+				SerialNo    string
+				PhoneNumber string
+				Code        string
+				Message     string
+			}
+		}
+		if err := t.call(ctx, "SendSms", req, &resp); err != nil {
+			results = append(results, failAll(phones, err)...)
+			return results, err
+		}
+		for _, s := range resp.SendStatusSet {
+			results = append(results, SendResult{
+				Phone:     s.PhoneNumber,
+				MessageID: s.SerialNo,
+				Success:   s.Code == "Ok",
+				Code:      s.Code,
+				Message:   s.Message,
+			})
+		}
+	}
+	return results, nil
+}
+
+// Query 查询短信回执，腾讯云按时间范围拉取，MessageID 不为空时按 SerialNo 过滤
+func (t *Tencent) Query(ctx context.Context, query *StatusQuery) ([]Status, error) {
+	if query.Phone == "" {
+		return nil, fmt.Errorf("sms query phone must be provided")
+	}
+	date := query.Date
+	if date.IsZero() {
+		date = time.Now()
+	}
+	begin := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, cst)
+	limit := query.Limit
+	if limit <= 0 || limit > 100 {
+		limit = 50
+	}
+	req := map[string]interface{}{
+		"PhoneNumber": e164(query.Phone),
+		"SmsSdkAppId": t.config.AppID,
+		"BeginTime":   begin.Unix(),
+		"EndTime":     begin.Add(24*time.Hour - time.Second).Unix(),
+		"Offset":      0,
+		"Limit":       limit,
+	}
+
+	var resp struct {
+		PullSmsSendStatusSet []struct {
+			UserReceiveTime int64
+			PhoneNumber     string
+			SerialNo        string
+			ReportStatus    string
+			Description     string
+			SessionContext  string
+		}
+	}
+	if err := t.call(ctx, "PullSmsSendStatusByPhoneNumber", req, &resp); err != nil {
+		return nil, err
+	}
+
+	statuses := make([]Status, 0, len(resp.PullSmsSendStatusSet))
+	for _, s := range resp.PullSmsSendStatusSet {
+		if query.MessageID != "" && s.SerialNo != query.MessageID {
+			continue
+		}
+		status := Status{
+			Phone:       s.PhoneNumber,
+			MessageID:   s.SerialNo,
+			Code:        s.ReportStatus,
+			Description: s.Description,
+			OutID:       s.SessionContext,
+		}
+		switch s.ReportStatus {
+		case "SUCCESS":
+			status.State = StateSuccess
+		case "FAIL":
+			status.State = StateFailed
+		default:
+			status.State = StatePending
+		}
+		if s.UserReceiveTime > 0 {
+			status.ReceiveTime = time.Unix(s.UserReceiveTime, 0)
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses, nil
+}
+
+// call 使用 TC3-HMAC-SHA256 签名并调用腾讯云API
+func (t *Tencent) call(ctx context.Context, action string, req interface{}, dest interface{}) error {
+	payload, err := json.Marshal(req)
+	if err != nil {
+		return fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	const contentType = "application/json; charset=utf-8"
+	now := time.Now().UTC()
+	timestamp := strconv.FormatInt(now.Unix(), 10)
+	date := now.Format("2006-01-02")
+	scope := date + "/sms/tc3_request"
+
+	canonical := "POST\n/\n\ncontent-type:" + contentType + "\nhost:" + t.host + "\n\ncontent-type;host\n" + sha256Hex(payload)
+	stringToSign := "TC3-HMAC-SHA256\n" + timestamp + "\n" + scope + "\n" + sha256Hex([]byte(canonical))
+	key := hmacSHA256([]byte("TC3"+t.config.AccessKeySecret), date)
+	key = hmacSHA256(key, "sms")
+	key = hmacSHA256(key, "tc3_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+	authorization := "TC3-HMAC-SHA256 Credential=" + t.config.AccessKeyID + "/" + scope +
+		", SignedHeaders=content-type;host, Signature=" + signature
+
+	resp, err := t.client.R(ctx).
+		SetBody(bytes.NewReader(payload), contentType).
+		SetHeaders(map[string]string{
+			"Authorization":  authorization,
+			"X-TC-Action":    action,
+			"X-TC-Timestamp": timestamp,
+			"X-TC-Version":   "2021-01-11",
+			"X-TC-Region":    t.config.Region,
+		}).
+		Post(t.config.Endpoint)
+	if err != nil {
+		return fmt.Errorf("tencent sms %s: %w", action, err)
+	}
+	if err := resp.Error(); err != nil {
+		return fmt.Errorf("tencent sms %s: %w", action, err)
+	}
+
+	var wrapper struct {
+		Response json.RawMessage
+	}
+	if err := resp.JSON(&wrapper); err != nil {
+		return err
+	}
+	var result struct {
+		Error *struct {
+			Code    string
+			Message string
+		}
+		RequestId string
+	}
+	if err := json.Unmarshal(wrapper.Response, &result); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+	if e := result.Error; e != nil {
+		return fmt.Errorf("tencent sms %s: %s %s (request id %s)", action, e.Code, e.Message, result.RequestId)
+	}
+	if err := json.Unmarshal(wrapper.Response, dest); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+	return nil
+}
+
+// e164 补全国家码
+func e164(phone string) string {
+	if strings.HasPrefix(phone, "+") {
+		return phone
+	}
+	return "+86" + phone
+}
+
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
@@ -0,0 +1,111 @@
+package objstore
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"mime"
+	"os"
+	"path/filepath"
+
+	"golang.org/x/sync/errgroup"
+)
+
+// 分片上传参数
+const (
+	// multipartThreshold 超过该大小的文件使用分片上传
+	multipartThreshold = 64 << 20
+	defaultPartSize    = 16 << 20
+	partConcurrency    = 4
+)
+
+// UploadFile 上传本地文件，未指定 Content-Type 时按扩展名推断，大文件使用分片并发上传
+func UploadFile(ctx context.Context, s Store, key, filename string, opts ...PutOption) error {
+	f, err := os.Open(filename)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	st, err := f.Stat()
+	if err != nil {
+		return err
+	}
+	if putOptions(opts).ContentType == "" {
+		if ct := mime.TypeByExtension(filepath.Ext(filename)); ct != "" {
+			opts = append([]PutOption{WithContentType(ct)}, opts...)
+		}
+	}
+	if st.Size() <= multipartThreshold {
+		return s.Put(ctx, key, f, st.Size(), opts...)
+	}
+	return UploadMultipart(ctx, s, key, f, st.Size(), defaultPartSize, opts...)
+}
+
+// UploadMultipart 将 r 按 partSize 切分并发上传，任一分片失败时取消整个上传
+func UploadMultipart(ctx context.Context, s Store, key string, r io.ReaderAt, size, partSize int64, opts ...PutOption) error {
+	if partSize <= 0 {
+		partSize = defaultPartSize
+	}
+	mp, err := s.NewMultipartUpload(ctx, key, opts...)
+	if err != nil {
+		return err
+	}
+	count := int((size + partSize - 1) / partSize)
+	if count == 0 {
+		count = 1
+	}
+	parts := make([]Part, count)
+	g, gctx := errgroup.WithContext(ctx)
+	g.SetLimit(partConcurrency)
+	for i := 0; i < count; i++ {
+		offset := int64(i) * partSize
+		n := partSize
+		if offset+n > size {
+			n = size - offset
+		}
+		g.Go(func() error {
+			p, err := mp.UploadPart(gctx, i+1, io.NewSectionReader(r, offset, n), n)
+			if err != nil {
+				return err
+			}
+			parts[i] = p
+			return nil
+		})
+	}
+	if err := g.Wait(); err != nil {
+		_ = mp.Abort(context.Background())
+		return err
+	}
+	if err := mp.Complete(ctx, parts); err != nil {
+		_ = mp.Abort(context.Background())
+		return err
+	}
+	return nil
+}
+
+// DownloadFile 下载对象到本地文件，先写入临时文件完成后再重命名
+func DownloadFile(ctx context.Context, s Store, key, filename string) error {
+	body, err := s.Get(ctx, key)
+	if err != nil {
+		return err
+	}
+	defer body.Close()
+	if err := os.MkdirAll(filepath.Dir(filename), 0755); err != nil {
+		return err
+	}
+	tmp := filename + ".part"
+	f, err := os.Create(tmp)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(f, body); err != nil {
+		f.Close()
+		os.Remove(tmp)
+		return fmt.Errorf("failed to download %s: %w", key, err)
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	return os.Rename(tmp, filename)
+}
@@ -0,0 +1,188 @@
+package objstore
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+)
+
+// MinIOStore 基于 minio-go 的 S3 兼容存储
+type MinIOStore struct {
+	core   *minio.Core
+	bucket string
+	config Config
+}
+
+// NewMinIO 创建 MinIO/S3 客户端，CreateBucket 为 true 时自动创建桶
+func NewMinIO(config Config) (*MinIOStore, error) {
+	core, err := minio.NewCore(config.Endpoint, &minio.Options{
+		Creds:  credentials.NewStaticV4(config.AccessKeyID, config.SecretAccessKey, config.SessionToken),
+		Secure: config.UseSSL,
+		Region: config.Region,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create minio client: %w", err)
+	}
+	s := &MinIOStore{core: core, bucket: config.Bucket, config: config}
+	if config.CreateBucket {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		exists, err := core.BucketExists(ctx, config.Bucket)
+		if err != nil {
+			return nil, fmt.Errorf("failed to check bucket %s: %w", config.Bucket, err)
+		}
+		if !exists {
+			if err := core.MakeBucket(ctx, config.Bucket, minio.MakeBucketOptions{Region: config.Region}); err != nil {
+				return nil, fmt.Errorf("failed to create bucket %s: %w", config.Bucket, err)
+			}
+		}
+	}
+	return s, nil
+}
+
+// Client 返回底层 minio 客户端
+func (s *MinIOStore) Client() *minio.Client {
+	return s.core.Client
+}
+
+// minioError 将对象不存在错误转换为 ErrNotFound
+func minioError(err error) error {
+	if err == nil {
+		return nil
+	}
+	switch minio.ToErrorResponse(err).Code {
+	case "NoSuchKey", "NotFound":
+		return fmt.Errorf("%w: %v", ErrNotFound, err)
+	}
+	return err
+}
+
+// Put 上传对象，大对象由 minio-go 自动分片
+func (s *MinIOStore) Put(ctx context.Context, key string, r io.Reader, size int64, opts ...PutOption) error {
+	o := putOptions(opts)
+	_, err := s.core.Client.PutObject(ctx, s.bucket, key, r, size, minio.PutObjectOptions{
+		ContentType:  o.ContentType,
+		UserMetadata: o.Metadata,
+		PartSize:     uint64(s.config.PartSize),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to put %s: %w", key, err)
+	}
+	return nil
+}
+
+// Get 下载对象
+func (s *MinIOStore) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	obj, err := s.core.Client.GetObject(ctx, s.bucket, key, minio.GetObjectOptions{})
+	if err != nil {
+		return nil, minioError(err)
+	}
+	// GetObject 延迟发起请求，先 Stat 以便及时返回对象不存在错误
+	if _, err := obj.Stat(); err != nil {
+		obj.Close()
+		return nil, minioError(err)
+	}
+	return obj, nil
+}
+
+// Stat 获取对象信息
+func (s *MinIOStore) Stat(ctx context.Context, key string) (*ObjectInfo, error) {
+	info, err := s.core.Client.StatObject(ctx, s.bucket, key, minio.StatObjectOptions{})
+	if err != nil {
+		return nil, minioError(err)
+	}
+	return minioInfo(info), nil
+}
+
+func minioInfo(info minio.ObjectInfo) *ObjectInfo {
+	return &ObjectInfo{
+		Key:          info.Key,
+		Size:         info.Size,
+		ETag:         info.ETag,
+		ContentType:  info.ContentType,
+		LastModified: info.LastModified,
+		Metadata:     info.UserMetadata,
+	}
+}
+
+// Delete 删除对象
+func (s *MinIOStore) Delete(ctx context.Context, key string) error {
+	if err := s.core.Client.RemoveObject(ctx, s.bucket, key, minio.RemoveObjectOptions{}); err != nil {
+		return fmt.Errorf("failed to delete %s: %w", key, err)
+	}
+	return nil
+}
+
+// List 递归列出前缀下的全部对象
+func (s *MinIOStore) List(ctx context.Context, prefix string) ([]ObjectInfo, error) {
+	var list []ObjectInfo
+	for info := range s.core.Client.ListObjects(ctx, s.bucket, minio.ListObjectsOptions{Prefix: prefix, Recursive: true}) {
+		if info.Err != nil {
+			return nil, fmt.Errorf("failed to list %s: %w", prefix, info.Err)
+		}
+		list = append(list, *minioInfo(info))
+	}
+	return list, nil
+}
+
+// PresignURL 生成预签名URL
+func (s *MinIOStore) PresignURL(ctx context.Context, method, key string, expires time.Duration) (string, error) {
+	method, err := checkMethod(method)
+	if err != nil {
+		return "", err
+	}
+	u, err := s.core.Client.Presign(ctx, method, s.bucket, key, expires, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to presign %s: %w", key, err)
+	}
+	return u.String(), nil
+}
+
+// NewMultipartUpload 初始化分片上传
+func (s *MinIOStore) NewMultipartUpload(ctx context.Context, key string, opts ...PutOption) (Multipart, error) {
+	o := putOptions(opts)
+	putOpts := minio.PutObjectOptions{ContentType: o.ContentType, UserMetadata: o.Metadata}
+	id, err := s.core.NewMultipartUpload(ctx, s.bucket, key, putOpts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to init multipart upload %s: %w", key, err)
+	}
+	return &minioMultipart{store: s, key: key, id: id, opts: putOpts}, nil
+}
+
+type minioMultipart struct {
+	store *MinIOStore
+	key   string
+	id    string
+	opts  minio.PutObjectOptions
+}
+
+func (m *minioMultipart) UploadID() string {
+	return m.id
+}
+
+func (m *minioMultipart) UploadPart(ctx context.Context, number int, r io.Reader, size int64) (Part, error) {
+	p, err := m.store.core.PutObjectPart(ctx, m.store.bucket, m.key, m.id, number, r, size, minio.PutObjectPartOptions{})
+	if err != nil {
+		return Part{}, fmt.Errorf("failed to upload part %d of %s: %w", number, m.key, err)
+	}
+	return Part{Number: p.PartNumber, ETag: p.ETag, Size: p.Size}, nil
+}
+
+func (m *minioMultipart) Complete(ctx context.Context, parts []Part) error {
+	complete := make([]minio.CompletePart, 0, len(parts))
+	for _, p := range parts {
+		complete = append(complete, minio.CompletePart{PartNumber: p.Number, ETag: p.ETag})
+	}
+	if _, err := m.store.core.CompleteMultipartUpload(ctx, m.store.bucket, m.key, m.id, complete, m.opts); err != nil {
+		return fmt.Errorf("failed to complete multipart upload %s: %w", m.key, err)
+	}
+	return nil
+}
+
+func (m *minioMultipart) Abort(ctx context.Context) error {
+	return m.store.core.AbortMultipartUpload(ctx, m.store.bucket, m.key, m.id)
+}
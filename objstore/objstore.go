@@ -0,0 +1,141 @@
+// Package objstore 提供对象存储的统一接口，支持 S3 兼容的 MinIO 和阿里云 OSS。
+package objstore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// 存储类型
+const (
+	ProviderMinIO = "minio"
+	ProviderS3    = "s3"
+	ProviderOSS   = "oss"
+)
+
+// ErrNotFound 对象不存在
+var ErrNotFound = errors.New("objstore: object not found")
+
+// Config 配置结构
+type Config struct {
+	Provider        string // minio/s3/oss
+	Endpoint        string // 服务地址，如 play.min.io:9000、oss-cn-hangzhou.aliyuncs.com
+	AccessKeyID     string
+	SecretAccessKey string
+	SessionToken    string // 临时凭证，可选
+	Bucket          string
+	Region          string
+	UseSSL          bool  // MinIO/S3 是否使用 HTTPS
+	CreateBucket    bool  // 桶不存在时自动创建（仅 MinIO/S3）
+	PartSize        int64 // 分片上传的分片大小，默认 16MB
+}
+
+// ObjectInfo 对象信息
+type ObjectInfo struct {
+	Key          string
+	Size         int64
+	ETag         string
+	ContentType  string
+	LastModified time.Time
+	Metadata     map[string]string
+}
+
+// Part 已上传的分片
+type Part struct {
+	Number int
+	ETag   string
+	Size   int64
+}
+
+// PutOptions 上传选项
+type PutOptions struct {
+	ContentType string
+	Metadata    map[string]string
+}
+
+// PutOption 上传选项函数
+type PutOption func(*PutOptions)
+
+// WithContentType 设置对象的 Content-Type
+func WithContentType(contentType string) PutOption {
+	return func(o *PutOptions) {
+		o.ContentType = contentType
+	}
+}
+
+// WithMetadata 设置对象的自定义元数据
+func WithMetadata(metadata map[string]string) PutOption {
+	return func(o *PutOptions) {
+		o.Metadata = metadata
+	}
+}
+
+func putOptions(opts []PutOption) PutOptions {
+	var o PutOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// Store 对象存储接口
+type Store interface {
+	// Put 上传对象，size 未知时传 -1
+	Put(ctx context.Context, key string, r io.Reader, size int64, opts ...PutOption) error
+	// Get 下载对象，调用方负责关闭返回的 ReadCloser
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	// Stat 获取对象信息，对象不存在时返回 ErrNotFound
+	Stat(ctx context.Context, key string) (*ObjectInfo, error)
+	// Delete 删除对象
+	Delete(ctx context.Context, key string) error
+	// List 列出前缀下的全部对象
+	List(ctx context.Context, prefix string) ([]ObjectInfo, error)
+	// PresignURL 生成预签名URL，method 为 GET 或 PUT
+	PresignURL(ctx context.Context, method, key string, expires time.Duration) (string, error)
+	// NewMultipartUpload 初始化分片上传
+	NewMultipartUpload(ctx context.Context, key string, opts ...PutOption) (Multipart, error)
+}
+
+// Multipart 分片上传
+type Multipart interface {
+	// UploadID 分片上传ID
+	UploadID() string
+	// UploadPart 上传分片，number 从1开始
+	UploadPart(ctx context.Context, number int, r io.Reader, size int64) (Part, error)
+	// Complete 合并分片
+	Complete(ctx context.Context, parts []Part) error
+	// Abort 取消分片上传
+	Abort(ctx context.Context) error
+}
+
+// New 根据配置创建对象存储客户端
+func New(config Config) (Store, error) {
+	if config.Bucket == "" {
+		return nil, fmt.Errorf("objstore: bucket must be provided")
+	}
+	if config.PartSize <= 0 {
+		config.PartSize = 16 << 20
+	}
+	switch strings.ToLower(config.Provider) {
+	case ProviderMinIO, ProviderS3, "":
+		return NewMinIO(config)
+	case ProviderOSS:
+		return NewOSS(config)
+	}
+	return nil, fmt.Errorf("unsupported objstore provider %s", config.Provider)
+}
+
+// checkMethod 校验预签名方法
+func checkMethod(method string) (string, error) {
+	method = strings.ToUpper(method)
+	switch method {
+	case http.MethodGet, http.MethodPut, http.MethodHead, http.MethodDelete:
+		return method, nil
+	}
+	return "", fmt.Errorf("objstore: unsupported presign method %s", method)
+}
@@ -0,0 +1,195 @@
+package objstore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aliyun/aliyun-oss-go-sdk/oss"
+)
+
+// OSSStore 阿里云 OSS 存储
+type OSSStore struct {
+	client *oss.Client
+	bucket *oss.Bucket
+}
+
+// NewOSS 创建阿里云 OSS 客户端
+func NewOSS(config Config) (*OSSStore, error) {
+	var opts []oss.ClientOption
+	if config.SessionToken != "" {
+		opts = append(opts, oss.SecurityToken(config.SessionToken))
+	}
+	if config.Region != "" {
+		opts = append(opts, oss.Region(config.Region))
+	}
+	client, err := oss.New(config.Endpoint, config.AccessKeyID, config.SecretAccessKey, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create oss client: %w", err)
+	}
+	bucket, err := client.Bucket(config.Bucket)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open bucket %s: %w", config.Bucket, err)
+	}
+	return &OSSStore{client: client, bucket: bucket}, nil
+}
+
+// Client 返回底层 OSS 客户端
+func (s *OSSStore) Client() *oss.Client {
+	return s.client
+}
+
+// ossError 将对象不存在错误转换为 ErrNotFound
+func ossError(err error) error {
+	var se oss.ServiceError
+	if errors.As(err, &se) && (se.Code == "NoSuchKey" || se.StatusCode == http.StatusNotFound) {
+		return fmt.Errorf("%w: %v", ErrNotFound, err)
+	}
+	return err
+}
+
+func ossPutOptions(ctx context.Context, opts []PutOption) []oss.Option {
+	o := putOptions(opts)
+	options := []oss.Option{oss.WithContext(ctx)}
+	if o.ContentType != "" {
+		options = append(options, oss.ContentType(o.ContentType))
+	}
+	for k, v := range o.Metadata {
+		options = append(options, oss.Meta(k, v))
+	}
+	return options
+}
+
+// Put 上传对象
+func (s *OSSStore) Put(ctx context.Context, key string, r io.Reader, size int64, opts ...PutOption) error {
+	if err := s.bucket.PutObject(key, r, ossPutOptions(ctx, opts)...); err != nil {
+		return fmt.Errorf("failed to put %s: %w", key, err)
+	}
+	return nil
+}
+
+// Get 下载对象
+func (s *OSSStore) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	body, err := s.bucket.GetObject(key, oss.WithContext(ctx))
+	if err != nil {
+		return nil, ossError(err)
+	}
+	return body, nil
+}
+
+// Stat 获取对象信息
+func (s *OSSStore) Stat(ctx context.Context, key string) (*ObjectInfo, error) {
+	header, err := s.bucket.GetObjectDetailedMeta(key, oss.WithContext(ctx))
+	if err != nil {
+		return nil, ossError(err)
+	}
+	info := &ObjectInfo{
+		Key:         key,
+		ETag:        strings.Trim(header.Get("ETag"), `"`),
+		ContentType: header.Get("Content-Type"),
+		Metadata:    make(map[string]string),
+	}
+	info.Size, _ = strconv.ParseInt(header.Get("Content-Length"), 10, 64)
+	info.LastModified, _ = http.ParseTime(header.Get("Last-Modified"))
+	for k, v := range header {
+		if len(v) > 0 && strings.HasPrefix(strings.ToLower(k), "x-oss-meta-") {
+			info.Metadata[strings.ToLower(k[len("x-oss-meta-"):])] = v[0]
+		}
+	}
+	return info, nil
+}
+
+// Delete 删除对象
+func (s *OSSStore) Delete(ctx context.Context, key string) error {
+	if err := s.bucket.DeleteObject(key, oss.WithContext(ctx)); err != nil {
+		return fmt.Errorf("failed to delete %s: %w", key, err)
+	}
+	return nil
+}
+
+// List 列出前缀下的全部对象
+func (s *OSSStore) List(ctx context.Context, prefix string) ([]ObjectInfo, error) {
+	var list []ObjectInfo
+	token := ""
+	for {
+		opts := []oss.Option{oss.WithContext(ctx), oss.Prefix(prefix), oss.MaxKeys(1000)}
+		if token != "" {
+			opts = append(opts, oss.ContinuationToken(token))
+		}
+		result, err := s.bucket.ListObjectsV2(opts...)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list %s: %w", prefix, err)
+		}
+		for _, obj := range result.Objects {
+			list = append(list, ObjectInfo{
+				Key:          obj.Key,
+				Size:         obj.Size,
+				ETag:         strings.Trim(obj.ETag, `"`),
+				LastModified: obj.LastModified,
+			})
+		}
+		if !result.IsTruncated {
+			return list, nil
+		}
+		token = result.NextContinuationToken
+	}
+}
+
+// PresignURL 生成预签名URL
+func (s *OSSStore) PresignURL(ctx context.Context, method, key string, expires time.Duration) (string, error) {
+	method, err := checkMethod(method)
+	if err != nil {
+		return "", err
+	}
+	u, err := s.bucket.SignURL(key, oss.HTTPMethod(method), int64(expires/time.Second))
+	if err != nil {
+		return "", fmt.Errorf("failed to presign %s: %w", key, err)
+	}
+	return u, nil
+}
+
+// NewMultipartUpload 初始化分片上传
+func (s *OSSStore) NewMultipartUpload(ctx context.Context, key string, opts ...PutOption) (Multipart, error) {
+	imur, err := s.bucket.InitiateMultipartUpload(key, ossPutOptions(ctx, opts)...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to init multipart upload %s: %w", key, err)
+	}
+	return &ossMultipart{bucket: s.bucket, imur: imur}, nil
+}
+
+type ossMultipart struct {
+	bucket *oss.Bucket
+	imur   oss.InitiateMultipartUploadResult
+}
+
+func (m *ossMultipart) UploadID() string {
+	return m.imur.UploadID
+}
+
+func (m *ossMultipart) UploadPart(ctx context.Context, number int, r io.Reader, size int64) (Part, error) {
+	p, err := m.bucket.UploadPart(m.imur, r, size, number, oss.WithContext(ctx))
+	if err != nil {
+		return Part{}, fmt.Errorf("failed to upload part %d of %s: %w", number, m.imur.Key, err)
+	}
+	return Part{Number: p.PartNumber, ETag: p.ETag, Size: size}, nil
+}
+
+func (m *ossMultipart) Complete(ctx context.Context, parts []Part) error {
+	complete := make([]oss.UploadPart, 0, len(parts))
+	for _, p := range parts {
+		complete = append(complete, oss.UploadPart{PartNumber: p.Number, ETag: p.ETag})
+	}
+	if _, err := m.bucket.CompleteMultipartUpload(m.imur, complete, oss.WithContext(ctx)); err != nil {
+		return fmt.Errorf("failed to complete multipart upload %s: %w", m.imur.Key, err)
+	}
+	return nil
+}
+
+func (m *ossMultipart) Abort(ctx context.Context) error {
+	return m.bucket.AbortMultipartUpload(m.imur, oss.WithContext(ctx))
+}
@@ -0,0 +1,25 @@
+//go:build !windows
+
+package procutil
+
+import (
+	"errors"
+	"os"
+	"syscall"
+)
+
+// lockSupported 是否支持操作系统文件锁
+const lockSupported = true
+
+// lockFile 对文件加非阻塞排他锁
+func lockFile(f *os.File) error {
+	err := syscall.Flock(int(f.Fd()), syscall.LOCK_EX|syscall.LOCK_NB)
+	if errors.Is(err, syscall.EWOULDBLOCK) {
+		return ErrAlreadyRunning
+	}
+	return err
+}
+
+func unlockFile(f *os.File) error {
+	return syscall.Flock(int(f.Fd()), syscall.LOCK_UN)
+}
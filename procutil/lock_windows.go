@@ -0,0 +1,14 @@
+package procutil
+
+import "os"
+
+// lockSupported Windows 暂不支持文件锁，单实例判断依赖 PID 文件中的进程是否存活
+const lockSupported = false
+
+func lockFile(f *os.File) error {
+	return nil
+}
+
+func unlockFile(f *os.File) error {
+	return nil
+}
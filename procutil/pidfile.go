@@ -0,0 +1,137 @@
+// Package procutil 提供进程管理辅助：PID 文件与单实例锁、子进程守护重启以及进程资源占用采样。
+package procutil
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/shirou/gopsutil/v4/process"
+)
+
+// ErrAlreadyRunning 已有实例持有锁
+var ErrAlreadyRunning = errors.New("procutil: another instance is running")
+
+// FileLock 基于文件的排他锁，进程退出后由操作系统自动释放
+type FileLock struct {
+	path string
+	file *os.File
+}
+
+// Lock 获取文件排他锁，不阻塞；已被其他进程持有时返回 ErrAlreadyRunning
+func Lock(path string) (*FileLock, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, err
+	}
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	if err := lockFile(f); err != nil {
+		f.Close()
+		return nil, err
+	}
+	return &FileLock{path: path, file: f}, nil
+}
+
+// Path 锁文件路径
+func (l *FileLock) Path() string {
+	return l.path
+}
+
+// Unlock 释放锁并关闭文件，不删除锁文件
+func (l *FileLock) Unlock() error {
+	if l.file == nil {
+		return nil
+	}
+	err := unlockFile(l.file)
+	if cerr := l.file.Close(); err == nil {
+		err = cerr
+	}
+	l.file = nil
+	return err
+}
+
+// PIDFile 持有锁的 PID 文件
+type PIDFile struct {
+	lock *FileLock
+}
+
+// AcquirePIDFile 获取 PID 文件并写入当前进程号；
+// 文件中记录的进程仍在运行时返回包装了 ErrAlreadyRunning 的错误，进程已退出的残留文件会被覆盖
+func AcquirePIDFile(path string) (*PIDFile, error) {
+	lock, err := Lock(path)
+	if err != nil {
+		if errors.Is(err, ErrAlreadyRunning) {
+			if pid, rerr := ReadPIDFile(path); rerr == nil {
+				return nil, fmt.Errorf("%w: pid %d", ErrAlreadyRunning, pid)
+			}
+		}
+		return nil, err
+	}
+	if pid, err := ReadPIDFile(path); err == nil && pid != os.Getpid() && !lockSupported && isRunning(pid, lock.file) {
+		lock.Unlock()
+		return nil, fmt.Errorf("%w: pid %d", ErrAlreadyRunning, pid)
+	}
+	if err := lock.file.Truncate(0); err != nil {
+		lock.Unlock()
+		return nil, err
+	}
+	if _, err := lock.file.WriteAt([]byte(strconv.Itoa(os.Getpid())+"\n"), 0); err != nil {
+		lock.Unlock()
+		return nil, err
+	}
+	if err := lock.file.Sync(); err != nil {
+		lock.Unlock()
+		return nil, err
+	}
+	return &PIDFile{lock: lock}, nil
+}
+
+// isRunning 判断残留 PID 文件中的进程是否仍在运行；进程启动时间晚于文件修改时间说明 PID 已被复用
+func isRunning(pid int, f *os.File) bool {
+	p, err := process.NewProcess(int32(pid))
+	if err != nil {
+		return false
+	}
+	st, err := f.Stat()
+	if err != nil {
+		return true
+	}
+	created, err := p.CreateTime()
+	if err != nil {
+		return true
+	}
+	return !time.UnixMilli(created).After(st.ModTime())
+}
+
+// Path PID 文件路径
+func (p *PIDFile) Path() string {
+	return p.lock.Path()
+}
+
+// Release 删除 PID 文件并释放锁
+func (p *PIDFile) Release() error {
+	err := os.Remove(p.lock.Path())
+	if uerr := p.lock.Unlock(); err == nil {
+		err = uerr
+	}
+	return err
+}
+
+// ReadPIDFile 读取 PID 文件中的进程号
+func ReadPIDFile(path string) (int, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return 0, err
+	}
+	pid, err := strconv.Atoi(string(bytes.TrimSpace(data)))
+	if err != nil || pid <= 0 {
+		return 0, fmt.Errorf("invalid pid file %s", path)
+	}
+	return pid, nil
+}
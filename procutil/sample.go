@@ -0,0 +1,97 @@
+package procutil
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/shirou/gopsutil/v4/process"
+)
+
+// Usage 进程资源占用
+type Usage struct {
+	PID           int       `json:"pid"`
+	Name          string    `json:"name"`
+	CPUPercent    float64   `json:"cpu_percent"` // 距上次采样的CPU占用，多核时可超过100
+	MemoryRSS     uint64    `json:"memory_rss"`
+	MemoryVMS     uint64    `json:"memory_vms"`
+	MemoryPercent float32   `json:"memory_percent"`
+	NumThreads    int32     `json:"num_threads"`
+	NumFDs        int32     `json:"num_fds"`
+	ReadBytes     uint64    `json:"read_bytes"`
+	WriteBytes    uint64    `json:"write_bytes"`
+	StartTime     time.Time `json:"start_time"`
+	Time          time.Time `json:"time"`
+}
+
+// Sampler 进程资源采样器，保存上次采样状态用于计算CPU占用，并发安全
+type Sampler struct {
+	mu    sync.Mutex
+	procs map[int]*sampleState
+}
+
+type sampleState struct {
+	proc    *process.Process
+	created int64
+}
+
+// NewSampler 创建采样器
+func NewSampler() *Sampler {
+	return &Sampler{procs: make(map[int]*sampleState)}
+}
+
+// Sample 采样进程资源占用，首次采样的CPU占用为进程启动以来的平均值；
+// 个别指标无权限读取时保留零值，进程不存在时返回错误
+func (s *Sampler) Sample(ctx context.Context, pid int) (*Usage, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	p, err := process.NewProcessWithContext(ctx, int32(pid))
+	if err != nil {
+		delete(s.procs, pid)
+		return nil, err
+	}
+	created, err := p.CreateTimeWithContext(ctx)
+	if err != nil {
+		delete(s.procs, pid)
+		return nil, err
+	}
+	state, ok := s.procs[pid]
+	// 启动时间不同说明 PID 已被复用，重新计算
+	first := !ok || state.created != created
+	if !first {
+		p = state.proc
+	}
+
+	u := &Usage{PID: pid, StartTime: time.UnixMilli(created), Time: time.Now()}
+	u.Name, _ = p.NameWithContext(ctx)
+	if first {
+		u.CPUPercent, _ = p.CPUPercentWithContext(ctx)
+		// 记录本次CPU时间作为下次采样的基准
+		p.PercentWithContext(ctx, 0)
+	} else {
+		u.CPUPercent, _ = p.PercentWithContext(ctx, 0)
+	}
+	if mem, err := p.MemoryInfoWithContext(ctx); err == nil {
+		u.MemoryRSS = mem.RSS
+		u.MemoryVMS = mem.VMS
+	}
+	u.MemoryPercent, _ = p.MemoryPercentWithContext(ctx)
+	u.NumThreads, _ = p.NumThreadsWithContext(ctx)
+	u.NumFDs, _ = p.NumFDsWithContext(ctx)
+	if io, err := p.IOCountersWithContext(ctx); err == nil {
+		u.ReadBytes = io.ReadBytes
+		u.WriteBytes = io.WriteBytes
+	}
+
+	if first {
+		s.procs[pid] = &sampleState{proc: p, created: created}
+	}
+	return u, nil
+}
+
+// Forget 清除进程的采样状态，进程退出后调用
+func (s *Sampler) Forget(pid int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.procs, pid)
+}
@@ -0,0 +1,332 @@
+package procutil
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"sort"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// 进程状态
+const (
+	StateStarting = "starting"
+	StateRunning  = "running"
+	StateBackoff  = "backoff"
+	StateStopped  = "stopped"
+	StateFatal    = "fatal" // 超过最大重启次数，不再重启
+)
+
+// 事件类型
+const (
+	EventStart  = "start"
+	EventExit   = "exit"
+	EventGiveUp = "give_up"
+)
+
+// ProcessConfig 子进程配置
+type ProcessConfig struct {
+	Name        string
+	Path        string
+	Args        []string
+	Env         []string // 追加到当前进程环境变量之后
+	Dir         string
+	Stdout      io.Writer
+	Stderr      io.Writer
+	MinBackoff  time.Duration // 首次重启等待时间，默认 1s，之后每次翻倍
+	MaxBackoff  time.Duration // 最大重启等待时间，默认 1min
+	ResetAfter  time.Duration // 连续运行超过该时间后重置重启等待时间和计数，默认 1min
+	MaxRestarts int           // 连续重启次数上限，0 表示不限
+	StopSignal  os.Signal     // 停止信号，默认 SIGTERM，不支持时直接 Kill
+	StopTimeout time.Duration // 发送停止信号后等待退出的时间，超时后 Kill，默认 10s
+}
+
+// Event 子进程事件
+type Event struct {
+	Name     string
+	Type     string
+	PID      int
+	ExitCode int
+	Restarts int
+	Err      error
+	Time     time.Time
+}
+
+// Status 子进程状态
+type Status struct {
+	Name      string    `json:"name"`
+	State     string    `json:"state"`
+	PID       int       `json:"pid"`
+	Restarts  int       `json:"restarts"`
+	StartedAt time.Time `json:"started_at"`
+	ExitCode  int       `json:"exit_code"`
+	LastError string    `json:"last_error"`
+}
+
+// SupervisorOption 守护器选项
+type SupervisorOption func(*Supervisor)
+
+// WithEventHandler 设置事件回调，回调在子进程的守护协程中同步执行
+func WithEventHandler(fn func(Event)) SupervisorOption {
+	return func(s *Supervisor) {
+		s.onEvent = fn
+	}
+}
+
+// Supervisor 子进程守护器，子进程异常退出后按指数退避重启
+type Supervisor struct {
+	mu      sync.Mutex
+	procs   map[string]*managed
+	onEvent func(Event)
+	sampler *Sampler
+	running bool
+}
+
+type managed struct {
+	config  ProcessConfig
+	status  Status
+	restart chan struct{}
+}
+
+// NewSupervisor 创建守护器
+func NewSupervisor(opts ...SupervisorOption) *Supervisor {
+	s := &Supervisor{procs: make(map[string]*managed), sampler: NewSampler()}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// Add 添加子进程，需在 Run 之前调用
+func (s *Supervisor) Add(config ProcessConfig) error {
+	if config.Name == "" || config.Path == "" {
+		return errors.New("procutil: process name and path are required")
+	}
+	if config.MinBackoff <= 0 {
+		config.MinBackoff = time.Second
+	}
+	if config.MaxBackoff <= 0 {
+		config.MaxBackoff = time.Minute
+	}
+	if config.ResetAfter <= 0 {
+		config.ResetAfter = time.Minute
+	}
+	if config.StopSignal == nil {
+		config.StopSignal = syscall.SIGTERM
+	}
+	if config.StopTimeout <= 0 {
+		config.StopTimeout = 10 * time.Second
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.running {
+		return errors.New("procutil: supervisor already running")
+	}
+	if _, ok := s.procs[config.Name]; ok {
+		return fmt.Errorf("procutil: process %s already exists", config.Name)
+	}
+	s.procs[config.Name] = &managed{
+		config:  config,
+		status:  Status{Name: config.Name, State: StateStopped},
+		restart: make(chan struct{}, 1),
+	}
+	return nil
+}
+
+// Run 启动全部子进程并守护，直到ctx取消后停止全部子进程并返回
+func (s *Supervisor) Run(ctx context.Context) {
+	s.mu.Lock()
+	s.running = true
+	procs := make([]*managed, 0, len(s.procs))
+	for _, m := range s.procs {
+		procs = append(procs, m)
+	}
+	s.mu.Unlock()
+
+	var wg sync.WaitGroup
+	for _, m := range procs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			s.supervise(ctx, m)
+		}()
+	}
+	wg.Wait()
+
+	s.mu.Lock()
+	s.running = false
+	s.mu.Unlock()
+}
+
+// supervise 单个子进程的守护循环
+func (s *Supervisor) supervise(ctx context.Context, m *managed) {
+	cfg := m.config
+	backoff := cfg.MinBackoff
+	restarts := 0
+	for {
+		s.setState(m, StateStarting, nil)
+		cmd := exec.Command(cfg.Path, cfg.Args...)
+		cmd.Dir = cfg.Dir
+		cmd.Env = append(os.Environ(), cfg.Env...)
+		cmd.Stdout = cfg.Stdout
+		cmd.Stderr = cfg.Stderr
+		started := time.Now()
+		err := cmd.Start()
+		if err == nil {
+			s.mu.Lock()
+			m.status.State = StateRunning
+			m.status.PID = cmd.Process.Pid
+			m.status.StartedAt = started
+			m.status.Restarts = restarts
+			s.mu.Unlock()
+			s.emit(Event{Name: cfg.Name, Type: EventStart, PID: cmd.Process.Pid, Restarts: restarts})
+
+			err = s.wait(ctx, m, cmd)
+			exitCode := cmd.ProcessState.ExitCode()
+			s.sampler.Forget(cmd.Process.Pid)
+			s.mu.Lock()
+			m.status.PID = 0
+			m.status.ExitCode = exitCode
+			s.mu.Unlock()
+			s.emit(Event{Name: cfg.Name, Type: EventExit, PID: cmd.Process.Pid, ExitCode: exitCode, Restarts: restarts, Err: err})
+		}
+		if ctx.Err() != nil {
+			s.setState(m, StateStopped, err)
+			return
+		}
+
+		// 手动重启不计入重启次数
+		select {
+		case <-m.restart:
+			restarts, backoff = 0, cfg.MinBackoff
+			continue
+		default:
+		}
+		if time.Since(started) >= cfg.ResetAfter {
+			restarts, backoff = 0, cfg.MinBackoff
+		}
+		restarts++
+		if cfg.MaxRestarts > 0 && restarts > cfg.MaxRestarts {
+			s.setState(m, StateFatal, err)
+			s.emit(Event{Name: cfg.Name, Type: EventGiveUp, Restarts: restarts - 1, Err: err})
+			select {
+			case <-ctx.Done():
+				return
+			case <-m.restart:
+				restarts, backoff = 0, cfg.MinBackoff
+				continue
+			}
+		}
+		s.setState(m, StateBackoff, err)
+		timer := time.NewTimer(backoff)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			s.setState(m, StateStopped, err)
+			return
+		case <-m.restart:
+			timer.Stop()
+			restarts, backoff = 0, cfg.MinBackoff
+			continue
+		case <-timer.C:
+		}
+		backoff *= 2
+		if backoff > cfg.MaxBackoff {
+			backoff = cfg.MaxBackoff
+		}
+	}
+}
+
+// wait 等待子进程退出，ctx取消或收到重启请求时先发送停止信号，超时后强制结束
+func (s *Supervisor) wait(ctx context.Context, m *managed, cmd *exec.Cmd) error {
+	done := make(chan error, 1)
+	go func() {
+		done <- cmd.Wait()
+	}()
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+	case <-m.restart:
+		// 放回请求，由守护循环识别为手动重启
+		m.restart <- struct{}{}
+	}
+	if err := cmd.Process.Signal(m.config.StopSignal); err != nil {
+		cmd.Process.Kill()
+	}
+	timer := time.NewTimer(m.config.StopTimeout)
+	defer timer.Stop()
+	select {
+	case err := <-done:
+		return err
+	case <-timer.C:
+		cmd.Process.Kill()
+		return <-done
+	}
+}
+
+func (s *Supervisor) setState(m *managed, state string, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	m.status.State = state
+	if err != nil {
+		m.status.LastError = err.Error()
+	}
+}
+
+func (s *Supervisor) emit(e Event) {
+	if s.onEvent == nil {
+		return
+	}
+	e.Time = time.Now()
+	s.onEvent(e)
+}
+
+// Restart 重启子进程，运行中的进程先停止，处于退避或已放弃状态的进程立即启动
+func (s *Supervisor) Restart(name string) error {
+	s.mu.Lock()
+	m, ok := s.procs[name]
+	s.mu.Unlock()
+	if !ok {
+		return fmt.Errorf("procutil: process %s not found", name)
+	}
+	select {
+	case m.restart <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+// Status 返回全部子进程状态，按名称排序
+func (s *Supervisor) Status() []Status {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	result := make([]Status, 0, len(s.procs))
+	for _, m := range s.procs {
+		result = append(result, m.status)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Name < result[j].Name })
+	return result
+}
+
+// Usage 采样全部运行中子进程的资源占用
+func (s *Supervisor) Usage(ctx context.Context) []*Usage {
+	var result []*Usage
+	for _, st := range s.Status() {
+		if st.PID == 0 {
+			continue
+		}
+		u, err := s.sampler.Sample(ctx, st.PID)
+		if err != nil {
+			continue
+		}
+		u.Name = st.Name
+		result = append(result, u)
+	}
+	return result
+}
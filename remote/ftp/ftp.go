@@ -0,0 +1,462 @@
+// Package ftp 提供 FTP/FTPS 客户端，支持目录列表、断点续传的上传下载和目录镜像。
+package ftp
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/jlaffaye/ftp"
+)
+
+// TLS 模式
+const (
+	TLSNone     = ""         // 明文 FTP
+	TLSExplicit = "explicit" // 显式 FTPS（AUTH TLS）
+	TLSImplicit = "implicit" // 隐式 FTPS，默认端口 990
+)
+
+// Config 配置结构
+type Config struct {
+	Host               string
+	Port               int // 默认 21，隐式 FTPS 默认 990
+	User               string
+	Password           string
+	TLSMode            string        // 空/explicit/implicit
+	InsecureSkipVerify bool          // 跳过证书校验，设备自签名证书时使用
+	DisableEPSV        bool          // 禁用 EPSV，部分老旧设备仅支持 PASV
+	Timeout            time.Duration // 连接和读写超时时间，默认 30s
+	Location           *time.Location
+}
+
+// Addr 返回 host:port
+func (c Config) Addr() string {
+	port := c.Port
+	if port == 0 {
+		port = 21
+		if c.TLSMode == TLSImplicit {
+			port = 990
+		}
+	}
+	return net.JoinHostPort(c.Host, strconv.Itoa(port))
+}
+
+// ProgressFunc 进度回调，transferred 为已传输字节数（含续传前已有部分），total 为文件总大小
+type ProgressFunc func(name string, transferred, total int64)
+
+// options 传输选项
+type options struct {
+	progress ProgressFunc
+	resume   bool
+	preserve bool
+}
+
+// Option 传输选项函数
+type Option func(*options)
+
+// WithProgress 设置进度回调
+func WithProgress(fn ProgressFunc) Option {
+	return func(o *options) {
+		o.progress = fn
+	}
+}
+
+// WithResume 目标文件已存在且小于源文件时从断点继续传输，大小相同时跳过
+func WithResume() Option {
+	return func(o *options) {
+		o.resume = true
+	}
+}
+
+// WithPreserve 传输完成后保留源文件的修改时间，上传时需服务端支持 MFMT
+func WithPreserve() Option {
+	return func(o *options) {
+		o.preserve = true
+	}
+}
+
+// Entry 远程文件条目
+type Entry struct {
+	Name  string
+	Path  string
+	Size  int64
+	Time  time.Time
+	IsDir bool
+	Link  string // 符号链接目标
+}
+
+// Client FTP客户端，FTP 控制连接不支持并发命令，所有操作串行执行
+type Client struct {
+	mu     sync.Mutex
+	conn   *ftp.ServerConn
+	config Config
+}
+
+// Dial 连接并登录FTP服务器
+func Dial(config Config) (*Client, error) {
+	if config.Timeout <= 0 {
+		config.Timeout = 30 * time.Second
+	}
+	opts := []ftp.DialOption{
+		ftp.DialWithTimeout(config.Timeout),
+		ftp.DialWithDisabledEPSV(config.DisableEPSV),
+	}
+	if config.Location != nil {
+		opts = append(opts, ftp.DialWithLocation(config.Location))
+	}
+	tlsConfig := &tls.Config{ServerName: config.Host, InsecureSkipVerify: config.InsecureSkipVerify}
+	switch config.TLSMode {
+	case TLSNone:
+	case TLSExplicit:
+		opts = append(opts, ftp.DialWithExplicitTLS(tlsConfig))
+	case TLSImplicit:
+		opts = append(opts, ftp.DialWithTLS(tlsConfig))
+	default:
+		return nil, fmt.Errorf("unsupported ftp tls mode %s", config.TLSMode)
+	}
+
+	addr := config.Addr()
+	conn, err := ftp.Dial(addr, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to %s: %w", addr, err)
+	}
+	user := config.User
+	if user == "" {
+		user = "anonymous"
+	}
+	if err := conn.Login(user, config.Password); err != nil {
+		conn.Quit()
+		return nil, fmt.Errorf("failed to login to %s: %w", addr, err)
+	}
+	return &Client{conn: conn, config: config}, nil
+}
+
+// Conn 返回底层 FTP 连接，使用时需自行保证串行访问
+func (c *Client) Conn() *ftp.ServerConn {
+	return c.conn
+}
+
+// Close 退出登录并关闭连接
+func (c *Client) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.conn.Quit()
+}
+
+// NoOp 发送 NOOP 保持连接活跃
+func (c *Client) NoOp() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.conn.NoOp()
+}
+
+func toEntry(dir string, e *ftp.Entry) *Entry {
+	return &Entry{
+		Name:  e.Name,
+		Path:  path.Join(dir, e.Name),
+		Size:  int64(e.Size),
+		Time:  e.Time,
+		IsDir: e.Type == ftp.EntryTypeFolder,
+		Link:  e.Target,
+	}
+}
+
+// List 列出远程目录，不包含 . 和 ..
+func (c *Client) List(dir string) ([]*Entry, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.list(dir)
+}
+
+func (c *Client) list(dir string) ([]*Entry, error) {
+	entries, err := c.conn.List(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list %s: %w", dir, err)
+	}
+	list := make([]*Entry, 0, len(entries))
+	for _, e := range entries {
+		if e.Name == "." || e.Name == ".." {
+			continue
+		}
+		list = append(list, toEntry(dir, e))
+	}
+	return list, nil
+}
+
+// Stat 获取远程文件信息，服务端不支持 MLST 时通过列出父目录查找
+func (c *Client) Stat(remote string) (*Entry, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.stat(remote)
+}
+
+func (c *Client) stat(remote string) (*Entry, error) {
+	if e, err := c.conn.GetEntry(remote); err == nil {
+		return toEntry(path.Dir(remote), &ftp.Entry{Name: path.Base(remote), Target: e.Target, Type: e.Type, Size: e.Size, Time: e.Time}), nil
+	}
+	entries, err := c.list(path.Dir(remote))
+	if err != nil {
+		return nil, err
+	}
+	for _, e := range entries {
+		if e.Name == path.Base(remote) {
+			return e, nil
+		}
+	}
+	return nil, fmt.Errorf("%s: %w", remote, os.ErrNotExist)
+}
+
+// MkdirAll 递归创建远程目录
+func (c *Client) MkdirAll(dir string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.mkdirAll(dir)
+}
+
+func (c *Client) mkdirAll(dir string) error {
+	dir = path.Clean(dir)
+	if dir == "/" || dir == "." {
+		return nil
+	}
+	if e, err := c.stat(dir); err == nil {
+		if !e.IsDir {
+			return fmt.Errorf("%s exists and is not a directory", dir)
+		}
+		return nil
+	}
+	if err := c.mkdirAll(path.Dir(dir)); err != nil {
+		return err
+	}
+	if err := c.conn.MakeDir(dir); err != nil {
+		// 并发创建或 stat 不可靠时再次确认
+		if e, serr := c.stat(dir); serr == nil && e.IsDir {
+			return nil
+		}
+		return fmt.Errorf("failed to create remote dir %s: %w", dir, err)
+	}
+	return nil
+}
+
+// Remove 删除远程文件
+func (c *Client) Remove(remote string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.conn.Delete(remote)
+}
+
+// RemoveAll 递归删除远程目录
+func (c *Client) RemoveAll(dir string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.conn.RemoveDirRecur(dir)
+}
+
+// Rename 重命名远程文件
+func (c *Client) Rename(from, to string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.conn.Rename(from, to)
+}
+
+// Upload 上传本地文件，远程目录不存在时自动创建
+func (c *Client) Upload(ctx context.Context, local, remote string, opts ...Option) error {
+	o := applyOptions(opts)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	src, err := os.Open(local)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+	info, err := src.Stat()
+	if err != nil {
+		return err
+	}
+
+	if err := c.mkdirAll(path.Dir(remote)); err != nil {
+		return err
+	}
+
+	var offset int64
+	if o.resume {
+		if size, err := c.conn.FileSize(remote); err == nil && size <= info.Size() {
+			offset = size
+		}
+	}
+
+	if offset < info.Size() {
+		if _, err := src.Seek(offset, io.SeekStart); err != nil {
+			return err
+		}
+		reader := &progressReader{ctx: ctx, r: src, name: local, done: offset, total: info.Size(), fn: o.progress}
+		if offset > 0 {
+			err = c.conn.StorFrom(remote, reader, uint64(offset))
+		} else {
+			err = c.conn.Stor(remote, reader)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to upload %s: %w", local, err)
+		}
+	} else if o.progress != nil {
+		o.progress(local, offset, info.Size())
+	}
+
+	if o.preserve && c.conn.IsSetTimeSupported() {
+		if err := c.conn.SetTime(remote, info.ModTime()); err != nil {
+			return fmt.Errorf("failed to set time of %s: %w", remote, err)
+		}
+	}
+	return nil
+}
+
+// Download 下载远程文件，本地目录不存在时自动创建
+func (c *Client) Download(ctx context.Context, remote, local string, opts ...Option) error {
+	o := applyOptions(opts)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	size, err := c.conn.FileSize(remote)
+	if err != nil {
+		return fmt.Errorf("failed to get size of %s: %w", remote, err)
+	}
+	if err := os.MkdirAll(filepath.Dir(local), 0o755); err != nil {
+		return err
+	}
+
+	var offset int64
+	if o.resume {
+		if li, err := os.Stat(local); err == nil && !li.IsDir() && li.Size() <= size {
+			offset = li.Size()
+		}
+	}
+
+	flags := os.O_WRONLY | os.O_CREATE
+	if offset == 0 {
+		flags |= os.O_TRUNC
+	}
+	dst, err := os.OpenFile(local, flags, 0o644)
+	if err != nil {
+		return err
+	}
+	defer dst.Close()
+
+	if offset < size {
+		if _, err := dst.Seek(offset, io.SeekStart); err != nil {
+			return err
+		}
+		resp, err := c.conn.RetrFrom(remote, uint64(offset))
+		if err != nil {
+			return fmt.Errorf("failed to retrieve %s: %w", remote, err)
+		}
+		writer := &progressWriter{ctx: ctx, w: dst, name: remote, done: offset, total: size, fn: o.progress}
+		_, err = io.Copy(writer, resp)
+		if cerr := resp.Close(); err == nil {
+			err = cerr
+		}
+		if err != nil {
+			return fmt.Errorf("failed to download %s: %w", remote, err)
+		}
+	} else if o.progress != nil {
+		o.progress(remote, offset, size)
+	}
+
+	if o.preserve {
+		mtime, err := c.conn.GetTime(remote)
+		if err != nil {
+			// 服务端不支持 MDTM 时不保留修改时间
+			return nil
+		}
+		if err := dst.Close(); err != nil {
+			return err
+		}
+		if err := os.Chtimes(local, mtime, mtime); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ReadFile 读取远程文件内容，适用于设备配置等小文件
+func (c *Client) ReadFile(remote string) ([]byte, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	resp, err := c.conn.Retr(remote)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve %s: %w", remote, err)
+	}
+	data, err := io.ReadAll(resp)
+	if cerr := resp.Close(); err == nil {
+		err = cerr
+	}
+	return data, err
+}
+
+// WriteFile 写入远程文件
+func (c *Client) WriteFile(remote string, data []byte) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.conn.Stor(remote, bytes.NewReader(data))
+}
+
+// applyOptions 合并传输选项
+func applyOptions(opts []Option) options {
+	o := options{}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// progressReader 上传时统计进度并响应ctx取消
+type progressReader struct {
+	ctx   context.Context
+	r     io.Reader
+	name  string
+	done  int64
+	total int64
+	fn    ProgressFunc
+}
+
+func (p *progressReader) Read(b []byte) (int, error) {
+	if err := p.ctx.Err(); err != nil {
+		return 0, err
+	}
+	n, err := p.r.Read(b)
+	p.done += int64(n)
+	if p.fn != nil && n > 0 {
+		p.fn(p.name, p.done, p.total)
+	}
+	return n, err
+}
+
+// progressWriter 下载时统计进度并响应ctx取消
+type progressWriter struct {
+	ctx   context.Context
+	w     io.Writer
+	name  string
+	done  int64
+	total int64
+	fn    ProgressFunc
+}
+
+func (p *progressWriter) Write(b []byte) (int, error) {
+	if err := p.ctx.Err(); err != nil {
+		return 0, err
+	}
+	n, err := p.w.Write(b)
+	p.done += int64(n)
+	if p.fn != nil && n > 0 {
+		p.fn(p.name, p.done, p.total)
+	}
+	return n, err
+}
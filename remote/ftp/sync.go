@@ -0,0 +1,115 @@
+package ftp
+
+import (
+	"context"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"time"
+)
+
+// SyncResult 目录同步结果
+type SyncResult struct {
+	Transferred int   // 传输的文件数
+	Skipped     int   // 大小和修改时间一致而跳过的文件数
+	Bytes       int64 // 传输的文件总大小
+}
+
+// UploadDir 递归上传本地目录，远程已存在且大小和修改时间一致的文件跳过
+func (c *Client) UploadDir(ctx context.Context, localRoot, remoteRoot string, opts ...Option) (*SyncResult, error) {
+	opts = append(opts, WithPreserve())
+	result := &SyncResult{}
+
+	err := filepath.WalkDir(localRoot, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(localRoot, p)
+		if err != nil {
+			return err
+		}
+		remote := path.Join(remoteRoot, filepath.ToSlash(rel))
+
+		if d.IsDir() {
+			return c.MkdirAll(remote)
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+
+		if e, err := c.Stat(remote); err == nil && c.sameFile(info, e) {
+			result.Skipped++
+			return nil
+		}
+		if err := c.Upload(ctx, p, remote, opts...); err != nil {
+			return err
+		}
+		result.Transferred++
+		result.Bytes += info.Size()
+		return nil
+	})
+	return result, err
+}
+
+// DownloadDir 递归镜像远程目录到本地，本地已存在且大小和修改时间一致的文件跳过，用于拉取设备导出的配置目录
+func (c *Client) DownloadDir(ctx context.Context, remoteRoot, localRoot string, opts ...Option) (*SyncResult, error) {
+	opts = append(opts, WithPreserve())
+	result := &SyncResult{}
+	err := c.downloadDir(ctx, remoteRoot, localRoot, result, opts)
+	return result, err
+}
+
+func (c *Client) downloadDir(ctx context.Context, remoteDir, localDir string, result *SyncResult, opts []Option) error {
+	if err := os.MkdirAll(localDir, 0o755); err != nil {
+		return err
+	}
+	entries, err := c.List(remoteDir)
+	if err != nil {
+		return err
+	}
+	for _, e := range entries {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		local := filepath.Join(localDir, e.Name)
+		if e.IsDir {
+			if err := c.downloadDir(ctx, e.Path, local, result, opts); err != nil {
+				return err
+			}
+			continue
+		}
+		if e.Link != "" {
+			continue
+		}
+		if li, err := os.Stat(local); err == nil && c.sameFile(li, e) {
+			result.Skipped++
+			continue
+		}
+		if err := c.Download(ctx, e.Path, local, opts...); err != nil {
+			return err
+		}
+		result.Transferred++
+		result.Bytes += e.Size
+	}
+	return nil
+}
+
+// sameFile 按大小和修改时间判断文件是否一致，LIST 只返回分钟精度时间时按分钟比较
+func (c *Client) sameFile(local os.FileInfo, remote *Entry) bool {
+	if local.IsDir() || remote.IsDir || local.Size() != remote.Size {
+		return false
+	}
+	precision := time.Second
+	if !c.conn.IsTimePreciseInList() {
+		precision = time.Minute
+	}
+	return local.ModTime().Truncate(precision).Equal(remote.Time.Truncate(precision))
+}
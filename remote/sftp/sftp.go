@@ -0,0 +1,297 @@
+// Package sftp 提供基于SSH连接的文件上传下载，支持进度回调、断点续传、权限保留和目录同步。
+package sftp
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+
+	"github.com/ixxmi/tools/remote/ssh"
+	pkgsftp "github.com/pkg/sftp"
+)
+
+// ProgressFunc 进度回调，transferred 为已传输字节数（含续传前已有部分），total 为文件总大小
+type ProgressFunc func(name string, transferred, total int64)
+
+// options 传输选项
+type options struct {
+	progress ProgressFunc
+	resume   bool
+	preserve bool
+}
+
+// Option 传输选项函数
+type Option func(*options)
+
+// WithProgress 设置进度回调
+func WithProgress(fn ProgressFunc) Option {
+	return func(o *options) {
+		o.progress = fn
+	}
+}
+
+// WithResume 目标文件已存在且小于源文件时从断点继续传输，大小相同时跳过
+func WithResume() Option {
+	return func(o *options) {
+		o.resume = true
+	}
+}
+
+// WithPreserve 传输完成后保留源文件的权限和修改时间
+func WithPreserve() Option {
+	return func(o *options) {
+		o.preserve = true
+	}
+}
+
+// Client SFTP客户端
+type Client struct {
+	conn    *ssh.Client
+	client  *pkgsftp.Client
+	ownConn bool
+}
+
+// New 复用已有SSH连接创建SFTP客户端，关闭时不会断开SSH连接
+func New(conn *ssh.Client) (*Client, error) {
+	client, err := pkgsftp.NewClient(conn.Conn(), pkgsftp.UseConcurrentWrites(true))
+	if err != nil {
+		return nil, fmt.Errorf("failed to start sftp on %s: %w", conn.Host(), err)
+	}
+	return &Client{conn: conn, client: client}, nil
+}
+
+// Dial 建立SSH连接并创建SFTP客户端，关闭时一并断开SSH连接
+func Dial(config ssh.Config) (*Client, error) {
+	conn, err := ssh.Dial(config)
+	if err != nil {
+		return nil, err
+	}
+	c, err := New(conn)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	c.ownConn = true
+	return c, nil
+}
+
+// SFTP 返回底层 pkg/sftp 客户端
+func (c *Client) SFTP() *pkgsftp.Client {
+	return c.client
+}
+
+// Close 关闭SFTP会话，通过 Dial 创建时同时关闭SSH连接
+func (c *Client) Close() error {
+	err := c.client.Close()
+	if c.ownConn {
+		if cerr := c.conn.Close(); err == nil {
+			err = cerr
+		}
+	}
+	return err
+}
+
+// Stat 获取远程文件信息
+func (c *Client) Stat(remote string) (os.FileInfo, error) {
+	return c.client.Stat(remote)
+}
+
+// ReadDir 列出远程目录
+func (c *Client) ReadDir(remote string) ([]os.FileInfo, error) {
+	return c.client.ReadDir(remote)
+}
+
+// MkdirAll 递归创建远程目录
+func (c *Client) MkdirAll(remote string) error {
+	return c.client.MkdirAll(remote)
+}
+
+// Remove 删除远程文件或空目录
+func (c *Client) Remove(remote string) error {
+	return c.client.Remove(remote)
+}
+
+// Upload 上传本地文件，远程目录不存在时自动创建
+func (c *Client) Upload(ctx context.Context, local, remote string, opts ...Option) error {
+	o := applyOptions(opts)
+
+	src, err := os.Open(local)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+	info, err := src.Stat()
+	if err != nil {
+		return err
+	}
+
+	if err := c.client.MkdirAll(path.Dir(remote)); err != nil {
+		return fmt.Errorf("failed to create remote dir: %w", err)
+	}
+
+	var offset int64
+	if o.resume {
+		if ri, err := c.client.Stat(remote); err == nil && !ri.IsDir() && ri.Size() <= info.Size() {
+			offset = ri.Size()
+		}
+	}
+
+	flags := os.O_WRONLY | os.O_CREATE
+	if offset == 0 {
+		flags |= os.O_TRUNC
+	}
+	dst, err := c.client.OpenFile(remote, flags)
+	if err != nil {
+		return fmt.Errorf("failed to open remote file %s: %w", remote, err)
+	}
+	defer dst.Close()
+
+	if offset < info.Size() {
+		if _, err := src.Seek(offset, io.SeekStart); err != nil {
+			return err
+		}
+		if _, err := dst.Seek(offset, io.SeekStart); err != nil {
+			return err
+		}
+		reader := &progressReader{ctx: ctx, r: src, name: local, done: offset, total: info.Size(), fn: o.progress}
+		if _, err := dst.ReadFrom(reader); err != nil {
+			return fmt.Errorf("failed to upload %s: %w", local, err)
+		}
+	} else if o.progress != nil {
+		o.progress(local, offset, info.Size())
+	}
+
+	if o.preserve {
+		if err := c.client.Chmod(remote, info.Mode().Perm()); err != nil {
+			return fmt.Errorf("failed to chmod %s: %w", remote, err)
+		}
+		if err := c.client.Chtimes(remote, info.ModTime(), info.ModTime()); err != nil {
+			return fmt.Errorf("failed to chtimes %s: %w", remote, err)
+		}
+	}
+	return nil
+}
+
+// Download 下载远程文件，本地目录不存在时自动创建
+func (c *Client) Download(ctx context.Context, remote, local string, opts ...Option) error {
+	o := applyOptions(opts)
+
+	src, err := c.client.Open(remote)
+	if err != nil {
+		return fmt.Errorf("failed to open remote file %s: %w", remote, err)
+	}
+	defer src.Close()
+	info, err := src.Stat()
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(local), 0o755); err != nil {
+		return err
+	}
+
+	var offset int64
+	if o.resume {
+		if li, err := os.Stat(local); err == nil && !li.IsDir() && li.Size() <= info.Size() {
+			offset = li.Size()
+		}
+	}
+
+	flags := os.O_WRONLY | os.O_CREATE
+	if offset == 0 {
+		flags |= os.O_TRUNC
+	}
+	dst, err := os.OpenFile(local, flags, 0o644)
+	if err != nil {
+		return err
+	}
+	defer dst.Close()
+
+	if offset < info.Size() {
+		if _, err := src.Seek(offset, io.SeekStart); err != nil {
+			return err
+		}
+		if _, err := dst.Seek(offset, io.SeekStart); err != nil {
+			return err
+		}
+		writer := &progressWriter{ctx: ctx, w: dst, name: remote, done: offset, total: info.Size(), fn: o.progress}
+		if _, err := src.WriteTo(writer); err != nil {
+			return fmt.Errorf("failed to download %s: %w", remote, err)
+		}
+	} else if o.progress != nil {
+		o.progress(remote, offset, info.Size())
+	}
+
+	if o.preserve {
+		if err := dst.Chmod(info.Mode().Perm()); err != nil {
+			return err
+		}
+		if err := dst.Close(); err != nil {
+			return err
+		}
+		if err := os.Chtimes(local, info.ModTime(), info.ModTime()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// applyOptions 合并传输选项
+func applyOptions(opts []Option) options {
+	o := options{}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// progressReader 上传时统计进度并响应ctx取消
+type progressReader struct {
+	ctx   context.Context
+	r     io.Reader
+	name  string
+	done  int64
+	total int64
+	fn    ProgressFunc
+}
+
+func (p *progressReader) Read(b []byte) (int, error) {
+	if err := p.ctx.Err(); err != nil {
+		return 0, err
+	}
+	n, err := p.r.Read(b)
+	if n > 0 {
+		p.done += int64(n)
+		if p.fn != nil {
+			p.fn(p.name, p.done, p.total)
+		}
+	}
+	return n, err
+}
+
+// progressWriter 下载时统计进度并响应ctx取消
+type progressWriter struct {
+	ctx   context.Context
+	w     io.Writer
+	name  string
+	done  int64
+	total int64
+	fn    ProgressFunc
+}
+
+func (p *progressWriter) Write(b []byte) (int, error) {
+	if err := p.ctx.Err(); err != nil {
+		return 0, err
+	}
+	n, err := p.w.Write(b)
+	if n > 0 {
+		p.done += int64(n)
+		if p.fn != nil {
+			p.fn(p.name, p.done, p.total)
+		}
+	}
+	return n, err
+}
@@ -0,0 +1,113 @@
+package sftp
+
+import (
+	"context"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+)
+
+// SyncResult 目录同步结果
+type SyncResult struct {
+	Transferred int   // 传输的文件数
+	Skipped     int   // 大小和修改时间一致而跳过的文件数
+	Bytes       int64 // 传输的文件总大小
+}
+
+// UploadDir 递归上传本地目录，远程已存在且大小和修改时间一致的文件跳过，同步时始终保留权限和修改时间
+func (c *Client) UploadDir(ctx context.Context, localRoot, remoteRoot string, opts ...Option) (*SyncResult, error) {
+	opts = append(opts, WithPreserve())
+	result := &SyncResult{}
+
+	err := filepath.WalkDir(localRoot, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(localRoot, p)
+		if err != nil {
+			return err
+		}
+		remote := path.Join(remoteRoot, filepath.ToSlash(rel))
+
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if err := c.client.MkdirAll(remote); err != nil {
+				return err
+			}
+			return c.client.Chmod(remote, info.Mode().Perm())
+		}
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+
+		if ri, err := c.client.Stat(remote); err == nil && sameFile(info, ri) {
+			result.Skipped++
+			return nil
+		}
+		if err := c.Upload(ctx, p, remote, opts...); err != nil {
+			return err
+		}
+		result.Transferred++
+		result.Bytes += info.Size()
+		return nil
+	})
+	return result, err
+}
+
+// DownloadDir 递归下载远程目录，本地已存在且大小和修改时间一致的文件跳过，同步时始终保留权限和修改时间
+func (c *Client) DownloadDir(ctx context.Context, remoteRoot, localRoot string, opts ...Option) (*SyncResult, error) {
+	opts = append(opts, WithPreserve())
+	result := &SyncResult{}
+
+	walker := c.client.Walk(remoteRoot)
+	for walker.Step() {
+		if err := walker.Err(); err != nil {
+			return result, err
+		}
+		if err := ctx.Err(); err != nil {
+			return result, err
+		}
+		rel, err := filepath.Rel(filepath.FromSlash(remoteRoot), filepath.FromSlash(walker.Path()))
+		if err != nil {
+			return result, err
+		}
+		local := filepath.Join(localRoot, rel)
+
+		info := walker.Stat()
+		if info.IsDir() {
+			if err := os.MkdirAll(local, 0o755); err != nil {
+				return result, err
+			}
+			if err := os.Chmod(local, info.Mode().Perm()); err != nil {
+				return result, err
+			}
+			continue
+		}
+		if !info.Mode().IsRegular() {
+			continue
+		}
+
+		if li, err := os.Stat(local); err == nil && sameFile(info, li) {
+			result.Skipped++
+			continue
+		}
+		if err := c.Download(ctx, walker.Path(), local, opts...); err != nil {
+			return result, err
+		}
+		result.Transferred++
+		result.Bytes += info.Size()
+	}
+	return result, nil
+}
+
+// sameFile 按大小和秒级修改时间判断文件是否一致
+func sameFile(a, b os.FileInfo) bool {
+	return !b.IsDir() && a.Size() == b.Size() && a.ModTime().Unix() == b.ModTime().Unix()
+}
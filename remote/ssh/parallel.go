@@ -0,0 +1,76 @@
+package ssh
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// RunAll 在多台主机上并发执行同一命令，concurrency 为最大并发数（默认 10），结果顺序与 hosts 一致
+func RunAll(ctx context.Context, hosts []Config, cmd string, concurrency int) []*Result {
+	return Parallel(ctx, hosts, concurrency, func(ctx context.Context, c *Client) (*Result, error) {
+		return c.Run(ctx, cmd)
+	})
+}
+
+// RunScriptAll 在多台主机上并发执行同一脚本
+func RunScriptAll(ctx context.Context, hosts []Config, script, interpreter string, concurrency int) []*Result {
+	return Parallel(ctx, hosts, concurrency, func(ctx context.Context, c *Client) (*Result, error) {
+		return c.RunScript(ctx, script, interpreter)
+	})
+}
+
+// Parallel 并发连接多台主机并执行fn，每台主机执行完毕后断开连接
+func Parallel(ctx context.Context, hosts []Config, concurrency int, fn func(ctx context.Context, c *Client) (*Result, error)) []*Result {
+	if concurrency <= 0 {
+		concurrency = 10
+	}
+	results := make([]*Result, len(hosts))
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+
+	for i, host := range hosts {
+		wg.Add(1)
+		go func(i int, host Config) {
+			defer wg.Done()
+			select {
+			case sem <- struct{}{}:
+				defer func() { <-sem }()
+			case <-ctx.Done():
+				results[i] = &Result{Host: host.Host, ExitCode: -1, Err: ctx.Err()}
+				return
+			}
+
+			start := time.Now()
+			client, err := Dial(host)
+			if err != nil {
+				results[i] = &Result{Host: host.Host, ExitCode: -1, Err: err, Duration: time.Since(start)}
+				return
+			}
+			defer client.Close()
+
+			res, err := fn(ctx, client)
+			if res == nil {
+				res = &Result{Host: host.Host, ExitCode: -1}
+			}
+			if err != nil && res.Err == nil {
+				res.Err = err
+			}
+			results[i] = res
+		}(i, host)
+	}
+	wg.Wait()
+	return results
+}
+
+// Summary 汇总执行结果，返回成功和失败的主机列表
+func Summary(results []*Result) (succeeded, failed []string) {
+	for _, r := range results {
+		if r.Success() {
+			succeeded = append(succeeded, r.Host)
+		} else {
+			failed = append(failed, r.Host)
+		}
+	}
+	return succeeded, failed
+}
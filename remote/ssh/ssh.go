@@ -0,0 +1,248 @@
+// Package ssh 提供SSH远程命令执行，支持密码/密钥认证、超时控制、脚本执行和多主机并发执行。
+package ssh
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	gossh "golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/knownhosts"
+)
+
+// Config 配置结构
+type Config struct {
+	Host       string        // 主机地址
+	Port       int           // 端口，默认 22
+	User       string        // 用户名
+	Password   string        // 密码
+	PrivateKey []byte        // 私钥内容，优先于 KeyFile
+	KeyFile    string        // 私钥文件路径
+	Passphrase string        // 私钥密码
+	KnownHosts string        // known_hosts 文件路径，用于校验主机密钥，未设置 InsecureIgnoreHostKey 时必填
+	Timeout    time.Duration // 连接超时时间，默认 10s
+
+	InsecureIgnoreHostKey bool // 不校验主机密钥，存在中间人攻击风险，仅用于测试环境或无法维护 known_hosts 的设备
+}
+
+// Addr 返回 host:port
+func (c Config) Addr() string {
+	port := c.Port
+	if port <= 0 {
+		port = 22
+	}
+	return net.JoinHostPort(c.Host, strconv.Itoa(port))
+}
+
+// Result 命令执行结果
+type Result struct {
+	Host     string
+	Command  string
+	Stdout   string
+	Stderr   string
+	ExitCode int // 远端退出码，连接或超时失败时为 -1
+	Duration time.Duration
+	Err      error // 执行错误，退出码非0不视为错误
+}
+
+// Success 是否执行成功且退出码为0
+func (r *Result) Success() bool {
+	return r.Err == nil && r.ExitCode == 0
+}
+
+// Lines 将标准输出按行拆分，去除首尾空行
+func (r *Result) Lines() []string {
+	out := strings.TrimSpace(r.Stdout)
+	if out == "" {
+		return nil
+	}
+	return strings.Split(out, "\n")
+}
+
+// Client SSH客户端
+type Client struct {
+	config Config
+	client *gossh.Client
+}
+
+// Dial 连接远程主机
+func Dial(config Config) (*Client, error) {
+	if config.Timeout <= 0 {
+		config.Timeout = 10 * time.Second
+	}
+	clientConfig, err := newClientConfig(config)
+	if err != nil {
+		return nil, err
+	}
+	client, err := gossh.Dial("tcp", config.Addr(), clientConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect %s: %w", config.Addr(), err)
+	}
+	return &Client{config: config, client: client}, nil
+}
+
+// newClientConfig 根据配置生成认证方式和主机密钥校验
+func newClientConfig(config Config) (*gossh.ClientConfig, error) {
+	var auths []gossh.AuthMethod
+
+	key := config.PrivateKey
+	if len(key) == 0 && config.KeyFile != "" {
+		data, err := os.ReadFile(config.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read key file: %w", err)
+		}
+		key = data
+	}
+	if len(key) > 0 {
+		var (
+			signer gossh.Signer
+			err    error
+		)
+		if config.Passphrase != "" {
+			signer, err = gossh.ParsePrivateKeyWithPassphrase(key, []byte(config.Passphrase))
+		} else {
+			signer, err = gossh.ParsePrivateKey(key)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse private key: %w", err)
+		}
+		auths = append(auths, gossh.PublicKeys(signer))
+	}
+	if config.Password != "" {
+		auths = append(auths, gossh.Password(config.Password))
+		// 部分设备只开放 keyboard-interactive 认证
+		auths = append(auths, gossh.KeyboardInteractive(func(user, instruction string, questions []string, echos []bool) ([]string, error) {
+			answers := make([]string, len(questions))
+			for i := range answers {
+				answers[i] = config.Password
+			}
+			return answers, nil
+		}))
+	}
+	if len(auths) == 0 {
+		return nil, fmt.Errorf("ssh password or private key must be provided")
+	}
+
+	var hostKeyCallback gossh.HostKeyCallback
+	switch {
+	case config.KnownHosts != "":
+		cb, err := knownhosts.New(config.KnownHosts)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load known hosts: %w", err)
+		}
+		hostKeyCallback = cb
+	case config.InsecureIgnoreHostKey:
+		hostKeyCallback = gossh.InsecureIgnoreHostKey()
+	default:
+		return nil, fmt.Errorf("ssh known hosts must be provided, or set InsecureIgnoreHostKey to skip host key verification")
+	}
+
+	return &gossh.ClientConfig{
+		User:            config.User,
+		Auth:            auths,
+		HostKeyCallback: hostKeyCallback,
+		Timeout:         config.Timeout,
+	}, nil
+}
+
+// Conn 返回底层SSH连接，用于SFTP等子系统
+func (c *Client) Conn() *gossh.Client {
+	return c.client
+}
+
+// Host 返回主机地址
+func (c *Client) Host() string {
+	return c.config.Host
+}
+
+// Close 关闭连接
+func (c *Client) Close() error {
+	return c.client.Close()
+}
+
+// Run 执行命令，ctx 超时或取消时终止远端进程
+func (c *Client) Run(ctx context.Context, cmd string) (*Result, error) {
+	return c.run(ctx, cmd, nil)
+}
+
+// RunScript 通过标准输入将脚本交给解释器执行，interpreter 为空时使用 bash -s
+func (c *Client) RunScript(ctx context.Context, script, interpreter string) (*Result, error) {
+	if interpreter == "" {
+		interpreter = "bash -s"
+	}
+	return c.run(ctx, interpreter, strings.NewReader(script))
+}
+
+// run 在新会话中执行命令并收集输出
+func (c *Client) run(ctx context.Context, cmd string, stdin io.Reader) (*Result, error) {
+	result := &Result{Host: c.config.Host, Command: cmd, ExitCode: -1}
+	start := time.Now()
+	defer func() {
+		result.Duration = time.Since(start)
+	}()
+
+	session, err := c.client.NewSession()
+	if err != nil {
+		result.Err = fmt.Errorf("failed to create session: %w", err)
+		return result, result.Err
+	}
+	defer session.Close()
+
+	var stdout, stderr bytes.Buffer
+	session.Stdout = &stdout
+	session.Stderr = &stderr
+	if stdin != nil {
+		session.Stdin = stdin
+	}
+
+	if err := session.Start(cmd); err != nil {
+		result.Err = fmt.Errorf("failed to start command: %w", err)
+		return result, result.Err
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		done <- session.Wait()
+	}()
+
+	select {
+	case err = <-done:
+	case <-ctx.Done():
+		_ = session.Signal(gossh.SIGKILL)
+		_ = session.Close()
+		<-done
+		err = ctx.Err()
+	}
+
+	result.Stdout = stdout.String()
+	result.Stderr = stderr.String()
+
+	var exitErr *gossh.ExitError
+	switch {
+	case err == nil:
+		result.ExitCode = 0
+	case errors.As(err, &exitErr):
+		result.ExitCode = exitErr.ExitStatus()
+	default:
+		result.Err = fmt.Errorf("command %q on %s: %w", cmd, c.config.Host, err)
+		return result, result.Err
+	}
+	return result, nil
+}
+
+// Run 连接主机执行单条命令后断开
+func Run(ctx context.Context, config Config, cmd string) (*Result, error) {
+	client, err := Dial(config)
+	if err != nil {
+		return &Result{Host: config.Host, Command: cmd, ExitCode: -1, Err: err}, err
+	}
+	defer client.Close()
+	return client.Run(ctx, cmd)
+}
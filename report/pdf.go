@@ -0,0 +1,322 @@
+package report
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+
+	"github.com/jung-kurt/gofpdf"
+)
+
+// PDFOptions PDF 输出选项
+type PDFOptions struct {
+	FontFile    string // UTF-8 TTF 字体文件，包含中文时必须设置，如 NotoSansSC-Regular.ttf
+	Orientation string // P 纵向（默认）/ L 横向
+	ChartHeight float64
+}
+
+const pdfFont = "report"
+
+// 图表配色
+var palette = [][3]int{
+	{79, 129, 189}, {192, 80, 77}, {155, 187, 89}, {128, 100, 162},
+	{75, 172, 198}, {247, 150, 70}, {44, 77, 117}, {119, 44, 42},
+}
+
+// PDF 生成 PDF 报表，表格按页面宽度等分列宽，图表绘制在表格之后
+func (r *Report) PDF(opts PDFOptions) ([]byte, error) {
+	if opts.Orientation == "" {
+		opts.Orientation = "P"
+	}
+	if opts.ChartHeight <= 0 {
+		opts.ChartHeight = 80
+	}
+	pdf := gofpdf.New(opts.Orientation, "mm", "A4", "")
+	family := "Helvetica"
+	if opts.FontFile != "" {
+		pdf.AddUTF8Font(pdfFont, "", opts.FontFile)
+		family = pdfFont
+	}
+	pdf.SetTitle(r.Template.Title, true)
+	pdf.SetAutoPageBreak(true, 15)
+	pdf.AliasNbPages("{nb}")
+	pdf.SetFooterFunc(func() {
+		pdf.SetY(-12)
+		pdf.SetFont(family, "", 8)
+		pdf.SetTextColor(128, 128, 128)
+		pdf.CellFormat(0, 6, fmt.Sprintf("%d / {nb}", pdf.PageNo()), "", 0, "C", false, 0, "")
+	})
+	pdf.AddPage()
+
+	pdf.SetFont(family, "", 18)
+	pdf.SetTextColor(0, 0, 0)
+	pdf.MultiCell(0, 10, r.Template.Title, "", "C", false)
+	pdf.SetFont(family, "", 9)
+	pdf.SetTextColor(100, 100, 100)
+	pdf.CellFormat(0, 6, r.GeneratedAt.Format("2006-01-02 15:04:05"), "", 1, "C", false, 0, "")
+	if r.Template.Description != "" {
+		pdf.MultiCell(0, 5, r.Template.Description, "", "L", false)
+	}
+	pdf.Ln(4)
+
+	for i := range r.Sections {
+		s := &r.Sections[i]
+		pdf.SetFont(family, "", 13)
+		pdf.SetTextColor(0, 0, 0)
+		pdf.MultiCell(0, 8, s.Title, "", "L", false)
+		if s.Text != "" {
+			pdf.SetFont(family, "", 10)
+			pdf.MultiCell(0, 5, s.Text, "", "L", false)
+		}
+		pdf.Ln(2)
+		pdfTable(pdf, family, s)
+		if s.Chart != nil && s.Data != nil && len(s.Data.Rows) > 0 {
+			pdfChart(pdf, family, s, opts.ChartHeight)
+		}
+		pdf.Ln(6)
+	}
+
+	if err := pdf.Error(); err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	if err := pdf.Output(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func pdfTable(pdf *gofpdf.Fpdf, family string, s *SectionData) {
+	cols, idx := s.columns()
+	if len(cols) == 0 {
+		return
+	}
+	pageW, _ := pdf.GetPageSize()
+	left, _, right, _ := pdf.GetMargins()
+	w := (pageW - left - right) / float64(len(cols))
+	const h = 6.0
+
+	header := func() {
+		pdf.SetFont(family, "", 9)
+		pdf.SetFillColor(220, 230, 241)
+		pdf.SetDrawColor(191, 191, 191)
+		pdf.SetTextColor(0, 0, 0)
+		for _, c := range cols {
+			pdf.CellFormat(w, h, fitText(pdf, c.Title, w), "1", 0, "C", true, 0, "")
+		}
+		pdf.Ln(-1)
+	}
+	header()
+	pdf.SetFont(family, "", 8)
+	_, pageH := pdf.GetPageSize()
+	for n, values := range s.Data.Rows {
+		// 换页后重复表头
+		if pdf.GetY()+h > pageH-15 {
+			pdf.AddPage()
+			header()
+			pdf.SetFont(family, "", 8)
+		}
+		pdf.SetFillColor(245, 245, 245)
+		for _, j := range idx {
+			v := values[j]
+			align := "L"
+			if _, ok := v.(string); !ok && v != nil {
+				align = "R"
+			}
+			pdf.CellFormat(w, h, fitText(pdf, formatValue(v), w), "1", 0, align, n%2 == 1, 0, "")
+		}
+		pdf.Ln(-1)
+	}
+	pdf.Ln(3)
+}
+
+// fitText 截断超出单元格宽度的文本
+func fitText(pdf *gofpdf.Fpdf, s string, w float64) string {
+	max := w - 2
+	if pdf.GetStringWidth(s) <= max {
+		return s
+	}
+	r := []rune(s)
+	for len(r) > 0 && pdf.GetStringWidth(string(r)+"...") > max {
+		r = r[:len(r)-1]
+	}
+	return string(r) + "..."
+}
+
+// pdfChart 使用绘图指令绘制简单图表
+func pdfChart(pdf *gofpdf.Fpdf, family string, s *SectionData, height float64) {
+	chart := s.Chart
+	data := s.Data
+	catIdx := data.Index(chart.Category)
+	var series []int
+	for _, f := range chart.Series {
+		if i := data.Index(f); i >= 0 {
+			series = append(series, i)
+		}
+	}
+	if len(series) == 0 {
+		return
+	}
+	pageW, pageH := pdf.GetPageSize()
+	left, _, right, _ := pdf.GetMargins()
+	if pdf.GetY()+height+12 > pageH-15 {
+		pdf.AddPage()
+	}
+	if chart.Title != "" {
+		pdf.SetFont(family, "", 10)
+		pdf.CellFormat(0, 6, chart.Title, "", 1, "C", false, 0, "")
+	}
+	x, y := left+10, pdf.GetY()
+	w, h := pageW-left-right-20, height
+	labels := make([]string, len(data.Rows))
+	for i, row := range data.Rows {
+		if catIdx >= 0 {
+			labels[i] = formatValue(row[catIdx])
+		}
+	}
+
+	pdf.SetFont(family, "", 7)
+	pdf.SetDrawColor(128, 128, 128)
+	pdf.SetLineWidth(0.2)
+	switch chart.Type {
+	case ChartPie:
+		drawPie(pdf, data, series[0], labels, x, y, w, h)
+	default:
+		drawAxisChart(pdf, chart.Type, data, series, labels, x, y, w, h)
+	}
+	// 图例
+	pdf.SetY(y + h + 8)
+	if chart.Type != ChartPie {
+		lx := x
+		for n, i := range series {
+			c := palette[n%len(palette)]
+			pdf.SetFillColor(c[0], c[1], c[2])
+			pdf.Rect(lx, pdf.GetY()+1, 3, 3, "F")
+			pdf.Text(lx+4, pdf.GetY()+3.5, data.Columns[i])
+			lx += pdf.GetStringWidth(data.Columns[i]) + 10
+		}
+		pdf.Ln(6)
+	}
+}
+
+// drawAxisChart 绘制柱状图、条形图和折线图，条形图也按纵向柱绘制以复用坐标轴
+func drawAxisChart(pdf *gofpdf.Fpdf, typ string, data *Dataset, series []int, labels []string, x, y, w, h float64) {
+	maxV, minV := 0.0, 0.0
+	for _, row := range data.Rows {
+		for _, i := range series {
+			v := toFloat(row[i])
+			maxV = math.Max(maxV, v)
+			minV = math.Min(minV, v)
+		}
+	}
+	if maxV == minV {
+		maxV = minV + 1
+	}
+	scale := h / (maxV - minV)
+	zeroY := y + maxV*scale
+
+	// 网格、刻度和坐标轴
+	for t := 0; t <= 4; t++ {
+		v := minV + (maxV-minV)*float64(t)/4
+		ty := zeroY - v*scale
+		pdf.SetDrawColor(220, 220, 220)
+		pdf.Line(x, ty, x+w, ty)
+		pdf.Text(x-pdf.GetStringWidth(formatTick(v))-1, ty+1, formatTick(v))
+	}
+	pdf.SetDrawColor(128, 128, 128)
+	pdf.Line(x, y, x, y+h)
+	pdf.Line(x, zeroY, x+w, zeroY)
+
+	n := len(data.Rows)
+	slot := w / float64(n)
+	// 分类标签过密时间隔显示
+	step := int(math.Ceil(float64(n) * 12 / w))
+	if step < 1 {
+		step = 1
+	}
+	for r, row := range data.Rows {
+		cx := x + slot*float64(r)
+		if r%step == 0 && labels[r] != "" {
+			pdf.Text(cx+slot/2-pdf.GetStringWidth(labels[r])/2, y+h+4, labels[r])
+		}
+		if typ == ChartLine {
+			continue
+		}
+		barW := slot * 0.8 / float64(len(series))
+		for k, i := range series {
+			v := toFloat(row[i])
+			c := palette[k%len(palette)]
+			pdf.SetFillColor(c[0], c[1], c[2])
+			top := math.Min(zeroY, zeroY-v*scale)
+			pdf.Rect(cx+slot*0.1+barW*float64(k), top, barW, math.Abs(v*scale), "F")
+		}
+	}
+	if typ == ChartLine {
+		pdf.SetLineWidth(0.5)
+		for k, i := range series {
+			c := palette[k%len(palette)]
+			pdf.SetDrawColor(c[0], c[1], c[2])
+			for r := 1; r < n; r++ {
+				x1 := x + slot*(float64(r)-0.5)
+				x2 := x + slot*(float64(r)+0.5)
+				pdf.Line(x1, zeroY-toFloat(data.Rows[r-1][i])*scale, x2, zeroY-toFloat(data.Rows[r][i])*scale)
+			}
+		}
+		pdf.SetLineWidth(0.2)
+		pdf.SetDrawColor(128, 128, 128)
+	}
+}
+
+// drawPie 绘制饼图，扇形用多边形近似
+func drawPie(pdf *gofpdf.Fpdf, data *Dataset, series int, labels []string, x, y, w, h float64) {
+	total := 0.0
+	for _, row := range data.Rows {
+		if v := toFloat(row[series]); v > 0 {
+			total += v
+		}
+	}
+	if total == 0 {
+		return
+	}
+	radius := h / 2
+	cx, cy := x+radius, y+radius
+	start := -math.Pi / 2
+	ly := y
+	for r, row := range data.Rows {
+		v := toFloat(row[series])
+		if v <= 0 {
+			continue
+		}
+		angle := v / total * 2 * math.Pi
+		points := []gofpdf.PointType{{X: cx, Y: cy}}
+		steps := int(math.Max(2, angle/(math.Pi/90)))
+		for i := 0; i <= steps; i++ {
+			a := start + angle*float64(i)/float64(steps)
+			points = append(points, gofpdf.PointType{X: cx + radius*math.Cos(a), Y: cy + radius*math.Sin(a)})
+		}
+		c := palette[r%len(palette)]
+		pdf.SetFillColor(c[0], c[1], c[2])
+		pdf.Polygon(points, "F")
+		start += angle
+
+		// 右侧图例
+		lx := cx + radius + 10
+		pdf.Rect(lx, ly, 3, 3, "F")
+		pdf.Text(lx+5, ly+2.5, fmt.Sprintf("%s  %s (%.1f%%)", labels[r], formatTick(v), v/total*100))
+		ly += 5
+	}
+}
+
+func formatTick(v float64) string {
+	switch {
+	case math.Abs(v) >= 1e9:
+		return fmt.Sprintf("%.1fG", v/1e9)
+	case math.Abs(v) >= 1e6:
+		return fmt.Sprintf("%.1fM", v/1e6)
+	case math.Abs(v) >= 1e4:
+		return fmt.Sprintf("%.1fK", v/1e3)
+	case v == math.Trunc(v):
+		return fmt.Sprintf("%.0f", v)
+	}
+	return fmt.Sprintf("%.2f", v)
+}
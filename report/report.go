@@ -0,0 +1,351 @@
+// Package report 根据报表模板（章节、表格、图表）从 ClickHouse/MySQL 查询数据，生成 XLSX 和 PDF 报表，
+// 并支持定时生成和邮件投递。
+package report
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ixxmi/tools/config"
+	"github.com/ixxmi/tools/db/ckgroup"
+	"gorm.io/gorm"
+)
+
+// 图表类型
+const (
+	ChartBar  = "bar"  // 横向条形图
+	ChartCol  = "col"  // 柱状图
+	ChartLine = "line" // 折线图
+	ChartPie  = "pie"  // 饼图，只使用第一个数据系列
+)
+
+// 输出格式
+const (
+	FormatXLSX = "xlsx"
+	FormatPDF  = "pdf"
+)
+
+// Template 报表模板
+type Template struct {
+	Name        string    `json:"name"`
+	Title       string    `json:"title"`
+	Description string    `json:"description"`
+	Sections    []Section `json:"sections"`
+}
+
+// Section 报表章节，XLSX 中每个章节为一个工作表
+type Section struct {
+	Title   string   `json:"title"`
+	Text    string   `json:"text"`    // 说明文字
+	Source  string   `json:"source"`  // 数据源名称，为空时使用第一个注册的数据源
+	Query   string   `json:"query"`   // 查询语句，为空时只输出说明文字
+	Args    []string `json:"args"`    // 查询参数名，按顺序从生成参数中取值替换 ? 占位符
+	Columns []Column `json:"columns"` // 表格列，为空时输出查询结果的全部列
+	Chart   *Chart   `json:"chart"`
+}
+
+// Column 表格列
+type Column struct {
+	Field string  `json:"field"`
+	Title string  `json:"title"` // 为空时使用 Field
+	Width float64 `json:"width"` // XLSX 列宽（字符数），默认按内容估算
+}
+
+// Chart 图表
+type Chart struct {
+	Type     string   `json:"type"`
+	Title    string   `json:"title"`
+	Category string   `json:"category"` // 分类字段（X 轴或饼图标签）
+	Series   []string `json:"series"`   // 数值字段
+}
+
+// LoadTemplate 从 yaml/json/toml 文件加载模板
+func LoadTemplate(filename string) (*Template, error) {
+	m, err := config.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	data, err := json.Marshal(m)
+	if err != nil {
+		return nil, err
+	}
+	tpl := &Template{}
+	if err := json.Unmarshal(data, tpl); err != nil {
+		return nil, fmt.Errorf("decode template %s: %w", filename, err)
+	}
+	return tpl, nil
+}
+
+// Dataset 查询结果
+type Dataset struct {
+	Columns []string
+	Rows    [][]interface{}
+}
+
+// Index 返回字段的列序号，不存在时返回-1
+func (d *Dataset) Index(field string) int {
+	for i, c := range d.Columns {
+		if c == field {
+			return i
+		}
+	}
+	return -1
+}
+
+// DataSource 数据源
+type DataSource interface {
+	Query(ctx context.Context, query string, args ...interface{}) (*Dataset, error)
+}
+
+type clickhouseSource struct {
+	client *ckgroup.ClickHouseClient
+}
+
+// ClickHouse 使用 ClickHouse 客户端作为数据源
+func ClickHouse(client *ckgroup.ClickHouseClient) DataSource {
+	return &clickhouseSource{client: client}
+}
+
+func (s *clickhouseSource) Query(ctx context.Context, query string, args ...interface{}) (*Dataset, error) {
+	rows, err := s.client.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	return ScanRows(rows)
+}
+
+type mysqlSource struct {
+	db *gorm.DB
+}
+
+// MySQL 使用 gorm 连接作为数据源
+func MySQL(db *gorm.DB) DataSource {
+	return &mysqlSource{db: db}
+}
+
+func (s *mysqlSource) Query(ctx context.Context, query string, args ...interface{}) (*Dataset, error) {
+	rows, err := s.db.WithContext(ctx).Raw(query, args...).Rows()
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	return ScanRows(rows)
+}
+
+// ScanRows 读取全部行，数值类型列的文本值转换为数值，[]byte 转换为字符串
+func ScanRows(rows *sql.Rows) (*Dataset, error) {
+	types, err := rows.ColumnTypes()
+	if err != nil {
+		return nil, err
+	}
+	ds := &Dataset{Columns: make([]string, len(types))}
+	numeric := make([]bool, len(types))
+	for i, t := range types {
+		ds.Columns[i] = t.Name()
+		name := strings.ToUpper(t.DatabaseTypeName())
+		numeric[i] = strings.Contains(name, "INT") || strings.Contains(name, "FLOAT") ||
+			strings.Contains(name, "DOUBLE") || strings.Contains(name, "DECIMAL")
+	}
+	for rows.Next() {
+		values := make([]interface{}, len(types))
+		dest := make([]interface{}, len(types))
+		for i := range values {
+			dest[i] = &values[i]
+		}
+		if err := rows.Scan(dest...); err != nil {
+			return nil, err
+		}
+		for i, v := range values {
+			if b, ok := v.([]byte); ok {
+				s := string(b)
+				values[i] = s
+				if numeric[i] {
+					if f, err := strconv.ParseFloat(s, 64); err == nil {
+						values[i] = f
+					}
+				}
+			}
+		}
+		ds.Rows = append(ds.Rows, values)
+	}
+	return ds, rows.Err()
+}
+
+// SectionData 章节及其数据
+type SectionData struct {
+	Section
+	Data *Dataset // 无查询时为nil
+}
+
+// Report 生成的报表
+type Report struct {
+	Template    *Template
+	Params      map[string]interface{}
+	GeneratedAt time.Time
+	Sections    []SectionData
+}
+
+// Generator 报表生成器
+type Generator struct {
+	sources map[string]DataSource
+	first   string
+}
+
+// NewGenerator 创建生成器
+func NewGenerator() *Generator {
+	return &Generator{sources: make(map[string]DataSource)}
+}
+
+// Register 注册数据源，需在 Generate 之前调用
+func (g *Generator) Register(name string, ds DataSource) *Generator {
+	if g.first == "" {
+		g.first = name
+	}
+	g.sources[name] = ds
+	return g
+}
+
+// Generate 执行模板中的查询生成报表，params 为查询参数
+func (g *Generator) Generate(ctx context.Context, tpl *Template, params map[string]interface{}) (*Report, error) {
+	r := &Report{Template: tpl, Params: params, GeneratedAt: time.Now()}
+	for _, s := range tpl.Sections {
+		sd := SectionData{Section: s}
+		if s.Query != "" {
+			name := s.Source
+			if name == "" {
+				name = g.first
+			}
+			ds, ok := g.sources[name]
+			if !ok {
+				return nil, fmt.Errorf("report: data source %q not registered", name)
+			}
+			args := make([]interface{}, len(s.Args))
+			for i, a := range s.Args {
+				v, ok := params[a]
+				if !ok {
+					return nil, fmt.Errorf("report: section %q missing param %q", s.Title, a)
+				}
+				args[i] = v
+			}
+			data, err := ds.Query(ctx, s.Query, args...)
+			if err != nil {
+				return nil, fmt.Errorf("report: query section %q: %w", s.Title, err)
+			}
+			sd.Data = data
+		}
+		r.Sections = append(r.Sections, sd)
+	}
+	return r, nil
+}
+
+// columns 返回章节实际输出的列及其在数据中的序号
+func (s *SectionData) columns() ([]Column, []int) {
+	if s.Data == nil {
+		return nil, nil
+	}
+	cols := s.Columns
+	if len(cols) == 0 {
+		cols = make([]Column, len(s.Data.Columns))
+		for i, c := range s.Data.Columns {
+			cols[i] = Column{Field: c}
+		}
+	}
+	var out []Column
+	var idx []int
+	for _, c := range cols {
+		i := s.Data.Index(c.Field)
+		if i < 0 {
+			continue
+		}
+		if c.Title == "" {
+			c.Title = c.Field
+		}
+		out = append(out, c)
+		idx = append(idx, i)
+	}
+	return out, idx
+}
+
+// Filename 返回报表文件名，如 日报-20240101.xlsx
+func (r *Report) Filename(format string) string {
+	name := r.Template.Name
+	if name == "" {
+		name = r.Template.Title
+	}
+	if name == "" {
+		name = "report"
+	}
+	return fmt.Sprintf("%s-%s.%s", name, r.GeneratedAt.Format("20060102"), format)
+}
+
+// Render 按格式输出报表
+func (r *Report) Render(format string, opts PDFOptions) ([]byte, error) {
+	switch format {
+	case FormatXLSX:
+		return r.XLSX()
+	case FormatPDF:
+		return r.PDF(opts)
+	}
+	return nil, fmt.Errorf("report: unsupported format %s", format)
+}
+
+// formatValue 单元格文本
+func formatValue(v interface{}) string {
+	switch x := v.(type) {
+	case nil:
+		return ""
+	case time.Time:
+		return x.Format("2006-01-02 15:04:05")
+	case float64:
+		return strconv.FormatFloat(x, 'f', -1, 64)
+	case float32:
+		return strconv.FormatFloat(float64(x), 'f', -1, 32)
+	case []byte:
+		return string(x)
+	}
+	return fmt.Sprint(v)
+}
+
+// toFloat 图表数值，无法转换时返回0
+func toFloat(v interface{}) float64 {
+	switch x := v.(type) {
+	case float64:
+		return x
+	case float32:
+		return float64(x)
+	case int:
+		return float64(x)
+	case int8:
+		return float64(x)
+	case int16:
+		return float64(x)
+	case int32:
+		return float64(x)
+	case int64:
+		return float64(x)
+	case uint:
+		return float64(x)
+	case uint8:
+		return float64(x)
+	case uint16:
+		return float64(x)
+	case uint32:
+		return float64(x)
+	case uint64:
+		return float64(x)
+	case string:
+		f, _ := strconv.ParseFloat(x, 64)
+		return f
+	case fmt.Stringer:
+		// decimal.Decimal 等类型
+		f, _ := strconv.ParseFloat(x.String(), 64)
+		return f
+	}
+	return 0
+}
@@ -0,0 +1,204 @@
+package report
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/ixxmi/tools/notify/email"
+	"github.com/robfig/cron/v3"
+)
+
+// Delivery 邮件投递配置
+type Delivery struct {
+	Client  *email.Client
+	To      []string
+	Cc      []string
+	Subject string   // 为空时使用模板标题和生成日期
+	Body    string   // 邮件正文，为空时使用模板描述
+	Formats []string // 附件格式，默认 xlsx
+}
+
+// Send 将报表按格式渲染为附件发送邮件
+func (d *Delivery) Send(ctx context.Context, r *Report, opts PDFOptions) error {
+	formats := d.Formats
+	if len(formats) == 0 {
+		formats = []string{FormatXLSX}
+	}
+	subject := d.Subject
+	if subject == "" {
+		subject = fmt.Sprintf("%s %s", r.Template.Title, r.GeneratedAt.Format("2006-01-02"))
+	}
+	body := d.Body
+	if body == "" {
+		body = r.Template.Description
+	}
+	msg := &email.Message{To: d.To, Cc: d.Cc, Subject: subject, Text: body}
+	for _, format := range formats {
+		data, err := r.Render(format, opts)
+		if err != nil {
+			return err
+		}
+		msg.Attach(r.Filename(format), data)
+	}
+	return d.Client.Send(ctx, msg)
+}
+
+// Job 定时报表任务
+type Job struct {
+	Name      string
+	Spec      string // 标准5段 cron 表达式，如 "0 8 * * *"，支持 @daily、@every 1h 等
+	Template  *Template
+	Params    func(now time.Time) map[string]interface{} // 生成查询参数，如统计时间范围
+	OutputDir string                                     // 报表保存目录，为空时不保存
+	Formats   []string                                   // 保存格式，默认 xlsx
+	Delivery  *Delivery                                  // 为nil时不发送邮件
+
+	schedule cron.Schedule
+}
+
+// Hooks 生成过程回调，均可为nil
+type Hooks struct {
+	Before  func(ctx context.Context, job *Job) error // 返回错误时跳过本次生成
+	After   func(ctx context.Context, job *Job, r *Report)
+	OnError func(job *Job, err error)
+}
+
+// SchedulerOption 调度器选项
+type SchedulerOption func(*Scheduler)
+
+// WithHooks 设置回调
+func WithHooks(hooks Hooks) SchedulerOption {
+	return func(s *Scheduler) {
+		s.hooks = hooks
+	}
+}
+
+// WithPDFOptions 设置 PDF 输出选项
+func WithPDFOptions(opts PDFOptions) SchedulerOption {
+	return func(s *Scheduler) {
+		s.pdf = opts
+	}
+}
+
+// WithLocation 设置 cron 表达式使用的时区，默认本地时区
+func WithLocation(loc *time.Location) SchedulerOption {
+	return func(s *Scheduler) {
+		s.loc = loc
+	}
+}
+
+// Scheduler 定时报表调度器
+type Scheduler struct {
+	gen   *Generator
+	hooks Hooks
+	pdf   PDFOptions
+	loc   *time.Location
+	mu    sync.Mutex
+	jobs  []*Job
+}
+
+// NewScheduler 创建调度器
+func NewScheduler(gen *Generator, opts ...SchedulerOption) *Scheduler {
+	s := &Scheduler{gen: gen, loc: time.Local}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// Add 添加任务，需在 Run 之前调用
+func (s *Scheduler) Add(job *Job) error {
+	sched, err := cron.ParseStandard(job.Spec)
+	if err != nil {
+		return fmt.Errorf("report: invalid spec %q for job %s: %w", job.Spec, job.Name, err)
+	}
+	if job.Template == nil {
+		return fmt.Errorf("report: job %s has no template", job.Name)
+	}
+	job.schedule = sched
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.jobs = append(s.jobs, job)
+	return nil
+}
+
+// Run 按计划执行全部任务，直到ctx取消
+func (s *Scheduler) Run(ctx context.Context) {
+	s.mu.Lock()
+	jobs := append([]*Job{}, s.jobs...)
+	s.mu.Unlock()
+
+	var wg sync.WaitGroup
+	for _, job := range jobs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			s.loop(ctx, job)
+		}()
+	}
+	wg.Wait()
+}
+
+func (s *Scheduler) loop(ctx context.Context, job *Job) {
+	for {
+		now := time.Now().In(s.loc)
+		timer := time.NewTimer(job.schedule.Next(now).Sub(now))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case t := <-timer.C:
+			if err := s.RunJob(ctx, job, t); err != nil && s.hooks.OnError != nil {
+				s.hooks.OnError(job, err)
+			}
+		}
+	}
+}
+
+// RunJob 立即执行一次任务：生成报表、保存文件并发送邮件
+func (s *Scheduler) RunJob(ctx context.Context, job *Job, now time.Time) error {
+	if s.hooks.Before != nil {
+		if err := s.hooks.Before(ctx, job); err != nil {
+			return err
+		}
+	}
+	var params map[string]interface{}
+	if job.Params != nil {
+		params = job.Params(now)
+	}
+	r, err := s.gen.Generate(ctx, job.Template, params)
+	if err != nil {
+		return err
+	}
+	if job.OutputDir != "" {
+		formats := job.Formats
+		if len(formats) == 0 {
+			formats = []string{FormatXLSX}
+		}
+		if err := os.MkdirAll(job.OutputDir, 0o755); err != nil {
+			return err
+		}
+		for _, format := range formats {
+			data, err := r.Render(format, s.pdf)
+			if err != nil {
+				return err
+			}
+			if err := os.WriteFile(filepath.Join(job.OutputDir, r.Filename(format)), data, 0o644); err != nil {
+				return err
+			}
+		}
+	}
+	if job.Delivery != nil {
+		if err := job.Delivery.Send(ctx, r, s.pdf); err != nil {
+			return fmt.Errorf("report: deliver %s: %w", job.Name, err)
+		}
+	}
+	if s.hooks.After != nil {
+		s.hooks.After(ctx, job, r)
+	}
+	return nil
+}
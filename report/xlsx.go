@@ -0,0 +1,219 @@
+package report
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// XLSX 生成 Excel 报表，每个章节一个工作表，图表放在表格右侧
+func (r *Report) XLSX() ([]byte, error) {
+	f := excelize.NewFile()
+	defer f.Close()
+
+	headerStyle, err := f.NewStyle(&excelize.Style{
+		Font:      &excelize.Font{Bold: true},
+		Fill:      excelize.Fill{Type: "pattern", Pattern: 1, Color: []string{"DCE6F1"}},
+		Alignment: &excelize.Alignment{Horizontal: "center", Vertical: "center"},
+		Border: []excelize.Border{
+			{Type: "left", Color: "BFBFBF", Style: 1}, {Type: "right", Color: "BFBFBF", Style: 1},
+			{Type: "top", Color: "BFBFBF", Style: 1}, {Type: "bottom", Color: "BFBFBF", Style: 1},
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	dateStyle, err := f.NewStyle(&excelize.Style{NumFmt: 22})
+	if err != nil {
+		return nil, err
+	}
+
+	used := make(map[string]bool)
+	for i, s := range r.Sections {
+		sheet := sheetName(s.Title, i, used)
+		if i == 0 {
+			if err := f.SetSheetName("Sheet1", sheet); err != nil {
+				return nil, err
+			}
+		} else if _, err := f.NewSheet(sheet); err != nil {
+			return nil, err
+		}
+		if err := r.writeSheet(f, sheet, &s, headerStyle, dateStyle); err != nil {
+			return nil, fmt.Errorf("sheet %s: %w", sheet, err)
+		}
+	}
+	if len(r.Sections) == 0 {
+		f.SetCellValue("Sheet1", "A1", r.Template.Title)
+	}
+	f.SetActiveSheet(0)
+	f.SetDocProps(&excelize.DocProperties{Title: r.Template.Title, Description: r.Template.Description,
+		Created: r.GeneratedAt.Format(time.RFC3339)})
+	buf, err := f.WriteToBuffer()
+	if err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (r *Report) writeSheet(f *excelize.File, sheet string, s *SectionData, headerStyle, dateStyle int) error {
+	row := 1
+	if s.Text != "" {
+		if err := f.SetCellValue(sheet, "A1", s.Text); err != nil {
+			return err
+		}
+		row = 3
+	}
+	cols, idx := s.columns()
+	if len(cols) == 0 {
+		return nil
+	}
+	headerRow := row
+	widths := make([]float64, len(cols))
+	header := make([]interface{}, len(cols))
+	for i, c := range cols {
+		header[i] = c.Title
+		widths[i] = textWidth(c.Title)
+	}
+	cell, _ := excelize.CoordinatesToCellName(1, row)
+	if err := f.SetSheetRow(sheet, cell, &header); err != nil {
+		return err
+	}
+	last, _ := excelize.CoordinatesToCellName(len(cols), row)
+	if err := f.SetCellStyle(sheet, cell, last, headerStyle); err != nil {
+		return err
+	}
+
+	for _, values := range s.Data.Rows {
+		row++
+		out := make([]interface{}, len(cols))
+		for i, j := range idx {
+			out[i] = values[j]
+			if w := textWidth(formatValue(values[j])); w > widths[i] {
+				widths[i] = w
+			}
+		}
+		cell, _ := excelize.CoordinatesToCellName(1, row)
+		if err := f.SetSheetRow(sheet, cell, &out); err != nil {
+			return err
+		}
+		for i, v := range out {
+			if _, ok := v.(time.Time); ok {
+				c, _ := excelize.CoordinatesToCellName(i+1, row)
+				f.SetCellStyle(sheet, c, c, dateStyle)
+			}
+		}
+	}
+	for i, c := range cols {
+		w := c.Width
+		if w <= 0 {
+			w = min(max(widths[i]+2, 8), 60)
+		}
+		name, _ := excelize.ColumnNumberToName(i + 1)
+		if err := f.SetColWidth(sheet, name, name, w); err != nil {
+			return err
+		}
+	}
+	f.SetPanes(sheet, &excelize.Panes{Freeze: true, YSplit: headerRow, TopLeftCell: fmt.Sprintf("A%d", headerRow+1), ActivePane: "bottomLeft"})
+
+	if s.Chart != nil && row > headerRow {
+		return addChart(f, sheet, s.Chart, cols, headerRow, row)
+	}
+	return nil
+}
+
+// addChart 在表格右侧插入图表，数据引用表格中的列
+func addChart(f *excelize.File, sheet string, chart *Chart, cols []Column, headerRow, lastRow int) error {
+	colOf := func(field string) string {
+		for i, c := range cols {
+			if c.Field == field {
+				name, _ := excelize.ColumnNumberToName(i + 1)
+				return name
+			}
+		}
+		return ""
+	}
+	ref := func(col string) string {
+		return fmt.Sprintf("'%s'!$%s$%d:$%s$%d", sheet, col, headerRow+1, col, lastRow)
+	}
+	categories := ""
+	if c := colOf(chart.Category); c != "" {
+		categories = ref(c)
+	}
+	c := &excelize.Chart{Dimension: excelize.ChartDimension{Width: 640, Height: 360}}
+	switch chart.Type {
+	case ChartBar:
+		c.Type = excelize.Bar
+	case ChartLine:
+		c.Type = excelize.Line
+	case ChartPie:
+		c.Type = excelize.Pie
+	default:
+		c.Type = excelize.Col
+	}
+	if chart.Title != "" {
+		c.Title = []excelize.RichTextRun{{Text: chart.Title}}
+	}
+	for _, field := range chart.Series {
+		col := colOf(field)
+		if col == "" {
+			continue
+		}
+		c.Series = append(c.Series, excelize.ChartSeries{
+			Name:       fmt.Sprintf("'%s'!$%s$%d", sheet, col, headerRow),
+			Categories: categories,
+			Values:     ref(col),
+		})
+		if c.Type == excelize.Pie {
+			break
+		}
+	}
+	if len(c.Series) == 0 {
+		return fmt.Errorf("chart has no valid series")
+	}
+	anchor, _ := excelize.ColumnNumberToName(len(cols) + 2)
+	return f.AddChart(sheet, fmt.Sprintf("%s%d", anchor, headerRow), c)
+}
+
+// sheetName 生成合法且不重复的工作表名称，最长31字符
+func sheetName(title string, i int, used map[string]bool) string {
+	name := strings.Map(func(r rune) rune {
+		if strings.ContainsRune(`[]:*?/\`, r) {
+			return '_'
+		}
+		return r
+	}, strings.Trim(title, "'"))
+	if name == "" {
+		name = fmt.Sprintf("Sheet%d", i+1)
+	}
+	name = truncateRunes(name, 31)
+	base := name
+	for n := 2; used[name]; n++ {
+		suffix := fmt.Sprintf("(%d)", n)
+		name = truncateRunes(base, 31-len(suffix)) + suffix
+	}
+	used[name] = true
+	return name
+}
+
+func truncateRunes(s string, n int) string {
+	if utf8.RuneCountInString(s) <= n {
+		return s
+	}
+	return string([]rune(s)[:n])
+}
+
+// textWidth 估算文本显示宽度，中文按2个字符计算
+func textWidth(s string) float64 {
+	w := 0.0
+	for _, r := range s {
+		if r > 0x2E80 {
+			w += 2
+		} else {
+			w++
+		}
+	}
+	return w
+}
@@ -0,0 +1,128 @@
+package snmp
+
+import (
+	"strings"
+	"sync"
+)
+
+// MIB OID与名称的映射表
+type MIB struct {
+	mu    sync.RWMutex
+	names map[string]string // oid -> name
+	oids  map[string]string // name -> oid
+}
+
+// NewMIB 创建映射表，entries 为 OID 到名称的映射
+func NewMIB(entries map[string]string) *MIB {
+	m := &MIB{
+		names: make(map[string]string, len(entries)),
+		oids:  make(map[string]string, len(entries)),
+	}
+	m.RegisterAll(entries)
+	return m
+}
+
+// Register 注册OID名称，OID 前导点可省略
+func (m *MIB) Register(oid, name string) {
+	oid = strings.TrimPrefix(oid, ".")
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.names[oid] = name
+	m.oids[name] = oid
+}
+
+// RegisterAll 批量注册OID名称
+func (m *MIB) RegisterAll(entries map[string]string) {
+	for oid, name := range entries {
+		m.Register(oid, name)
+	}
+}
+
+// Resolve 按最长前缀匹配解析OID，返回名称和剩余的索引部分，未匹配时名称为OID本身
+func (m *MIB) Resolve(oid string) (name, index string) {
+	oid = strings.TrimPrefix(oid, ".")
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	prefix := oid
+	for {
+		if n, ok := m.names[prefix]; ok {
+			return n, strings.TrimPrefix(strings.TrimPrefix(oid, prefix), ".")
+		}
+		i := strings.LastIndexByte(prefix, '.')
+		if i < 0 {
+			return oid, ""
+		}
+		prefix = prefix[:i]
+	}
+}
+
+// Parse 将名称形式的OID（如 ifDescr.1、sysName.0）转换为数字形式，无法识别时原样返回
+func (m *MIB) Parse(s string) string {
+	s = strings.TrimPrefix(s, ".")
+	if s == "" || (s[0] >= '0' && s[0] <= '9') {
+		return "." + s
+	}
+	name, index := s, ""
+	if i := strings.IndexByte(s, '.'); i >= 0 {
+		name, index = s[:i], s[i:]
+	}
+	m.mu.RLock()
+	oid, ok := m.oids[name]
+	m.mu.RUnlock()
+	if !ok {
+		return s
+	}
+	return "." + oid + index
+}
+
+// DefaultMIB 默认映射表，包含 MIB-2 system、interfaces、ifXTable 和 HOST-RESOURCES 常用节点
+var DefaultMIB = NewMIB(map[string]string{
+	// system
+	"1.3.6.1.2.1.1.1": "sysDescr",
+	"1.3.6.1.2.1.1.2": "sysObjectID",
+	"1.3.6.1.2.1.1.3": "sysUpTime",
+	"1.3.6.1.2.1.1.4": "sysContact",
+	"1.3.6.1.2.1.1.5": "sysName",
+	"1.3.6.1.2.1.1.6": "sysLocation",
+	"1.3.6.1.2.1.1.7": "sysServices",
+	// interfaces
+	"1.3.6.1.2.1.2.1":         "ifNumber",
+	"1.3.6.1.2.1.2.2":         "ifTable",
+	"1.3.6.1.2.1.2.2.1.1":     "ifIndex",
+	"1.3.6.1.2.1.2.2.1.2":     "ifDescr",
+	"1.3.6.1.2.1.2.2.1.3":     "ifType",
+	"1.3.6.1.2.1.2.2.1.4":     "ifMtu",
+	"1.3.6.1.2.1.2.2.1.5":     "ifSpeed",
+	"1.3.6.1.2.1.2.2.1.6":     "ifPhysAddress",
+	"1.3.6.1.2.1.2.2.1.7":     "ifAdminStatus",
+	"1.3.6.1.2.1.2.2.1.8":     "ifOperStatus",
+	"1.3.6.1.2.1.2.2.1.9":     "ifLastChange",
+	"1.3.6.1.2.1.2.2.1.10":    "ifInOctets",
+	"1.3.6.1.2.1.2.2.1.11":    "ifInUcastPkts",
+	"1.3.6.1.2.1.2.2.1.13":    "ifInDiscards",
+	"1.3.6.1.2.1.2.2.1.14":    "ifInErrors",
+	"1.3.6.1.2.1.2.2.1.16":    "ifOutOctets",
+	"1.3.6.1.2.1.2.2.1.17":    "ifOutUcastPkts",
+	"1.3.6.1.2.1.2.2.1.19":    "ifOutDiscards",
+	"1.3.6.1.2.1.2.2.1.20":    "ifOutErrors",
+	"1.3.6.1.2.1.31.1.1":      "ifXTable",
+	"1.3.6.1.2.1.31.1.1.1.1":  "ifName",
+	"1.3.6.1.2.1.31.1.1.1.6":  "ifHCInOctets",
+	"1.3.6.1.2.1.31.1.1.1.7":  "ifHCInUcastPkts",
+	"1.3.6.1.2.1.31.1.1.1.10": "ifHCOutOctets",
+	"1.3.6.1.2.1.31.1.1.1.11": "ifHCOutUcastPkts",
+	"1.3.6.1.2.1.31.1.1.1.15": "ifHighSpeed",
+	"1.3.6.1.2.1.31.1.1.1.18": "ifAlias",
+	// HOST-RESOURCES-MIB
+	"1.3.6.1.2.1.25.1.1":     "hrSystemUptime",
+	"1.3.6.1.2.1.25.2.2":     "hrMemorySize",
+	"1.3.6.1.2.1.25.2.3":     "hrStorageTable",
+	"1.3.6.1.2.1.25.2.3.1.2": "hrStorageType",
+	"1.3.6.1.2.1.25.2.3.1.3": "hrStorageDescr",
+	"1.3.6.1.2.1.25.2.3.1.4": "hrStorageAllocationUnits",
+	"1.3.6.1.2.1.25.2.3.1.5": "hrStorageSize",
+	"1.3.6.1.2.1.25.2.3.1.6": "hrStorageUsed",
+	"1.3.6.1.2.1.25.3.3":     "hrProcessorTable",
+	"1.3.6.1.2.1.25.3.3.1.2": "hrProcessorLoad",
+})
@@ -0,0 +1,154 @@
+package snmp
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Device 轮询设备
+type Device struct {
+	Name     string        // 设备名，写入结果的 device 字段，默认使用 Config.Target
+	Config   Config        // 连接配置，Timeout/Retries 控制单次请求的超时和重试
+	OIDs     []string      // 标量OID，使用 Get 采集
+	Tables   []string      // 表格根节点，使用 BulkWalk 采集
+	Interval time.Duration // 轮询间隔，默认 1min
+	Timeout  time.Duration // 单轮采集总超时时间，默认与 Interval 相同
+}
+
+// PollResult 单轮采集结果
+type PollResult struct {
+	Device string
+	Time   time.Time
+	Row    map[string]interface{}              // OIDs 采集结果，见 Row
+	Tables map[string][]map[string]interface{} // 表格根节点到行的映射，见 TableRows
+	Err    error                               // 采集错误，部分表格失败时其余结果仍然有效
+}
+
+// Poller 按设备间隔定时采集
+type Poller struct {
+	mib         *MIB
+	handler     func(*PollResult)
+	concurrency int
+	mu          sync.Mutex
+	devices     []Device
+}
+
+// NewPoller 创建轮询器，concurrency 为同时采集的最大设备数（默认 32），mib 为nil时使用 DefaultMIB
+func NewPoller(concurrency int, mib *MIB, handler func(*PollResult)) *Poller {
+	if concurrency <= 0 {
+		concurrency = 32
+	}
+	if mib == nil {
+		mib = DefaultMIB
+	}
+	return &Poller{mib: mib, handler: handler, concurrency: concurrency}
+}
+
+// Add 添加设备，需在 Run 之前调用
+func (p *Poller) Add(devices ...Device) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.devices = append(p.devices, devices...)
+}
+
+// Run 启动轮询，每台设备启动后立即采集一次，之后按间隔采集，直到ctx取消
+func (p *Poller) Run(ctx context.Context) {
+	p.mu.Lock()
+	devices := append([]Device{}, p.devices...)
+	p.mu.Unlock()
+
+	sem := make(chan struct{}, p.concurrency)
+	var wg sync.WaitGroup
+	for _, device := range devices {
+		wg.Add(1)
+		go func(device Device) {
+			defer wg.Done()
+			p.loop(ctx, device, sem)
+		}(device)
+	}
+	wg.Wait()
+}
+
+// loop 单台设备的轮询循环
+func (p *Poller) loop(ctx context.Context, device Device, sem chan struct{}) {
+	interval := device.Interval
+	if interval <= 0 {
+		interval = time.Minute
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case sem <- struct{}{}:
+			result := p.Poll(ctx, device)
+			<-sem
+			if ctx.Err() != nil {
+				return
+			}
+			p.handler(result)
+		case <-ctx.Done():
+			return
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Poll 对设备执行一轮采集
+func (p *Poller) Poll(ctx context.Context, device Device) *PollResult {
+	name := device.Name
+	if name == "" {
+		name = device.Config.Target
+	}
+	result := &PollResult{Device: name, Time: time.Now(), Tables: make(map[string][]map[string]interface{})}
+
+	timeout := device.Timeout
+	if timeout <= 0 {
+		timeout = device.Interval
+	}
+	if timeout <= 0 {
+		timeout = time.Minute
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	client, err := NewClient(device.Config, p.mib)
+	if err != nil {
+		result.Err = err
+		return result
+	}
+	defer client.Close()
+
+	if len(device.OIDs) > 0 {
+		vars, err := client.Get(ctx, device.OIDs...)
+		if err != nil {
+			result.Err = err
+		} else {
+			result.Row = Row(vars, map[string]interface{}{"device": name, "time": result.Time})
+		}
+	}
+
+	fields := map[string]interface{}{"device": name, "time": result.Time}
+	for _, root := range device.Tables {
+		if ctx.Err() != nil {
+			result.Err = fmt.Errorf("snmp poll %s: %w", name, ctx.Err())
+			break
+		}
+		rows, err := client.Table(ctx, root)
+		if err != nil {
+			if result.Err == nil {
+				result.Err = err
+			}
+			continue
+		}
+		result.Tables[root] = WithFields(rows, fields)
+	}
+	return result
+}
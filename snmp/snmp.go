@@ -0,0 +1,255 @@
+// Package snmp 提供SNMP v2c/v3 采集客户端，支持 Get/Walk/BulkWalk、OID名称映射、类型转换和定时轮询。
+package snmp
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gosnmp/gosnmp"
+)
+
+// SNMP 版本
+const (
+	Version1  = "v1"
+	Version2c = "v2c"
+	Version3  = "v3"
+)
+
+// Config 配置结构
+type Config struct {
+	Target         string        // 设备地址
+	Port           uint16        // 端口，默认 161
+	Version        string        // 版本 v1/v2c/v3，默认 v2c
+	Community      string        // v1/v2c 团体名，默认 public
+	Timeout        time.Duration // 单次请求超时时间，默认 3s
+	Retries        int           // 超时重试次数，默认 2
+	MaxRepetitions uint32        // BulkWalk 每次请求返回的最大条数，默认 20
+	// v3 参数
+	User         string // 用户名
+	AuthProtocol string // 认证协议 MD5/SHA/SHA224/SHA256/SHA384/SHA512，为空时不认证
+	AuthPassword string // 认证密码
+	PrivProtocol string // 加密协议 DES/AES/AES192/AES256/AES192C/AES256C，为空时不加密
+	PrivPassword string // 加密密码
+	ContextName  string // v3 上下文名称
+}
+
+// Client SNMP客户端，非并发安全，多设备并发采集时每个设备使用独立客户端
+type Client struct {
+	config Config
+	snmp   *gosnmp.GoSNMP
+	mib    *MIB
+}
+
+// NewClient 创建SNMP客户端，mib 为nil时使用 DefaultMIB
+func NewClient(config Config, mib *MIB) (*Client, error) {
+	if config.Target == "" {
+		return nil, fmt.Errorf("snmp target must be provided")
+	}
+	if config.Port == 0 {
+		config.Port = 161
+	}
+	if config.Version == "" {
+		config.Version = Version2c
+	}
+	if config.Community == "" {
+		config.Community = "public"
+	}
+	if config.Timeout <= 0 {
+		config.Timeout = 3 * time.Second
+	}
+	if config.Retries == 0 {
+		config.Retries = 2
+	}
+	if config.Retries < 0 {
+		config.Retries = 0
+	}
+	if config.MaxRepetitions == 0 {
+		config.MaxRepetitions = 20
+	}
+	if mib == nil {
+		mib = DefaultMIB
+	}
+
+	s := &gosnmp.GoSNMP{
+		Target:         config.Target,
+		Port:           config.Port,
+		Community:      config.Community,
+		Timeout:        config.Timeout,
+		Retries:        config.Retries,
+		MaxRepetitions: config.MaxRepetitions,
+		MaxOids:        gosnmp.MaxOids,
+	}
+	switch strings.ToLower(config.Version) {
+	case Version1:
+		s.Version = gosnmp.Version1
+	case Version2c:
+		s.Version = gosnmp.Version2c
+	case Version3:
+		if err := setupV3(s, config); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("unsupported snmp version %s", config.Version)
+	}
+
+	if err := s.Connect(); err != nil {
+		return nil, fmt.Errorf("failed to connect %s: %w", config.Target, err)
+	}
+	return &Client{config: config, snmp: s, mib: mib}, nil
+}
+
+// setupV3 设置 v3 安全参数
+func setupV3(s *gosnmp.GoSNMP, config Config) error {
+	if config.User == "" {
+		return fmt.Errorf("snmp v3 user must be provided")
+	}
+	params := &gosnmp.UsmSecurityParameters{
+		UserName:                 config.User,
+		AuthenticationPassphrase: config.AuthPassword,
+		PrivacyPassphrase:        config.PrivPassword,
+	}
+
+	flags := gosnmp.NoAuthNoPriv
+	if config.AuthProtocol != "" {
+		auth, ok := authProtocols[strings.ToUpper(config.AuthProtocol)]
+		if !ok {
+			return fmt.Errorf("unsupported snmp auth protocol %s", config.AuthProtocol)
+		}
+		params.AuthenticationProtocol = auth
+		flags = gosnmp.AuthNoPriv
+	}
+	if config.PrivProtocol != "" {
+		if flags == gosnmp.NoAuthNoPriv {
+			return fmt.Errorf("snmp v3 privacy requires authentication")
+		}
+		priv, ok := privProtocols[strings.ToUpper(config.PrivProtocol)]
+		if !ok {
+			return fmt.Errorf("unsupported snmp priv protocol %s", config.PrivProtocol)
+		}
+		params.PrivacyProtocol = priv
+		flags = gosnmp.AuthPriv
+	}
+
+	s.Version = gosnmp.Version3
+	s.SecurityModel = gosnmp.UserSecurityModel
+	s.MsgFlags = flags
+	s.SecurityParameters = params
+	s.ContextName = config.ContextName
+	return nil
+}
+
+var authProtocols = map[string]gosnmp.SnmpV3AuthProtocol{
+	"MD5":    gosnmp.MD5,
+	"SHA":    gosnmp.SHA,
+	"SHA224": gosnmp.SHA224,
+	"SHA256": gosnmp.SHA256,
+	"SHA384": gosnmp.SHA384,
+	"SHA512": gosnmp.SHA512,
+}
+
+var privProtocols = map[string]gosnmp.SnmpV3PrivProtocol{
+	"DES":     gosnmp.DES,
+	"AES":     gosnmp.AES,
+	"AES192":  gosnmp.AES192,
+	"AES256":  gosnmp.AES256,
+	"AES192C": gosnmp.AES192C,
+	"AES256C": gosnmp.AES256C,
+}
+
+// Target 返回设备地址
+func (c *Client) Target() string {
+	return c.config.Target
+}
+
+// Close 关闭连接
+func (c *Client) Close() error {
+	if c.snmp.Conn == nil {
+		return nil
+	}
+	return c.snmp.Conn.Close()
+}
+
+// Get 获取指定OID的值，OID 可以使用 MIB 中注册的名称，如 sysName.0
+func (c *Client) Get(ctx context.Context, oids ...string) ([]Variable, error) {
+	resolved := make([]string, len(oids))
+	for i, oid := range oids {
+		resolved[i] = c.mib.Parse(oid)
+	}
+
+	vars := make([]Variable, 0, len(oids))
+	c.snmp.Context = ctx
+	// 单次请求的OID数量受设备限制，按 MaxOids 分批
+	for start := 0; start < len(resolved); start += c.snmp.MaxOids {
+		end := start + c.snmp.MaxOids
+		if end > len(resolved) {
+			end = len(resolved)
+		}
+		packet, err := c.snmp.Get(resolved[start:end])
+		if err != nil {
+			return nil, fmt.Errorf("snmp get %s: %w", c.config.Target, err)
+		}
+		if packet.Error != gosnmp.NoError {
+			return nil, fmt.Errorf("snmp get %s: %s (index %d)", c.config.Target, packet.Error, packet.ErrorIndex)
+		}
+		for _, pdu := range packet.Variables {
+			vars = append(vars, c.newVariable(pdu))
+		}
+	}
+	return vars, nil
+}
+
+// Walk 使用 GetNext 遍历子树
+func (c *Client) Walk(ctx context.Context, root string) ([]Variable, error) {
+	c.snmp.Context = ctx
+	pdus, err := c.snmp.WalkAll(c.mib.Parse(root))
+	if err != nil {
+		return nil, fmt.Errorf("snmp walk %s %s: %w", c.config.Target, root, err)
+	}
+	return c.newVariables(pdus), nil
+}
+
+// BulkWalk 使用 GetBulk 遍历子树，v1 设备自动退化为 Walk
+func (c *Client) BulkWalk(ctx context.Context, root string) ([]Variable, error) {
+	if c.snmp.Version == gosnmp.Version1 {
+		return c.Walk(ctx, root)
+	}
+	c.snmp.Context = ctx
+	pdus, err := c.snmp.BulkWalkAll(c.mib.Parse(root))
+	if err != nil {
+		return nil, fmt.Errorf("snmp bulkwalk %s %s: %w", c.config.Target, root, err)
+	}
+	return c.newVariables(pdus), nil
+}
+
+// Table 遍历表格子树并按索引组装成行，列名使用 MIB 名称，每行附带 index 列
+func (c *Client) Table(ctx context.Context, root string) ([]map[string]interface{}, error) {
+	vars, err := c.BulkWalk(ctx, root)
+	if err != nil {
+		return nil, err
+	}
+	return TableRows(vars), nil
+}
+
+// newVariables 批量转换PDU
+func (c *Client) newVariables(pdus []gosnmp.SnmpPDU) []Variable {
+	vars := make([]Variable, 0, len(pdus))
+	for _, pdu := range pdus {
+		vars = append(vars, c.newVariable(pdu))
+	}
+	return vars
+}
+
+// newVariable 转换PDU并解析名称
+func (c *Client) newVariable(pdu gosnmp.SnmpPDU) Variable {
+	oid := strings.TrimPrefix(pdu.Name, ".")
+	name, index := c.mib.Resolve(oid)
+	return Variable{
+		OID:   oid,
+		Name:  name,
+		Index: index,
+		Type:  pdu.Type.String(),
+		Value: convertValue(pdu),
+	}
+}
@@ -0,0 +1,118 @@
+package snmp
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/gosnmp/gosnmp"
+)
+
+// Variable 采集结果
+type Variable struct {
+	OID   string      // 数字形式OID，不含前导点
+	Name  string      // MIB 名称，未注册时为OID
+	Index string      // 名称之后的索引部分，如 ifDescr.3 中的 3
+	Type  string      // SNMP 类型
+	Value interface{} // 转换后的值：int64/uint64/float64/string，无值时为nil
+}
+
+// Key 返回 名称.索引 形式的键
+func (v Variable) Key() string {
+	if v.Index == "" {
+		return v.Name
+	}
+	return v.Name + "." + v.Index
+}
+
+// convertValue 将PDU值转换为Go基础类型
+func convertValue(pdu gosnmp.SnmpPDU) interface{} {
+	switch pdu.Type {
+	case gosnmp.Integer:
+		return gosnmp.ToBigInt(pdu.Value).Int64()
+	case gosnmp.Counter32, gosnmp.Gauge32, gosnmp.TimeTicks, gosnmp.Uinteger32, gosnmp.Counter64:
+		return gosnmp.ToBigInt(pdu.Value).Uint64()
+	case gosnmp.OctetString:
+		b, _ := pdu.Value.([]byte)
+		return octetString(b)
+	case gosnmp.IPAddress:
+		return fmt.Sprint(pdu.Value)
+	case gosnmp.ObjectIdentifier:
+		return strings.TrimPrefix(fmt.Sprint(pdu.Value), ".")
+	case gosnmp.OpaqueFloat:
+		if f, ok := pdu.Value.(float32); ok {
+			return float64(f)
+		}
+	case gosnmp.OpaqueDouble:
+		if f, ok := pdu.Value.(float64); ok {
+			return f
+		}
+	case gosnmp.Null, gosnmp.NoSuchObject, gosnmp.NoSuchInstance, gosnmp.EndOfMibView:
+		return nil
+	}
+	return pdu.Value
+}
+
+// octetString 可打印文本按字符串返回，二进制内容（如MAC地址）转换为冒号分隔的十六进制
+func octetString(b []byte) string {
+	if len(b) == 0 {
+		return ""
+	}
+	s := strings.TrimRight(string(b), "\x00")
+	if utf8.ValidString(s) && strings.IndexFunc(s, func(r rune) bool {
+		return !unicode.IsPrint(r) && !unicode.IsSpace(r)
+	}) < 0 {
+		return s
+	}
+	parts := make([]string, len(b))
+	for i, c := range b {
+		parts[i] = fmt.Sprintf("%02x", c)
+	}
+	return strings.Join(parts, ":")
+}
+
+// Row 将 Get 结果组装为一行，键为 名称.索引（索引为0的标量省略索引），extra 中的字段一并写入，如设备名和采集时间
+func Row(vars []Variable, extra map[string]interface{}) map[string]interface{} {
+	row := make(map[string]interface{}, len(vars)+len(extra))
+	for k, v := range extra {
+		row[k] = v
+	}
+	for _, v := range vars {
+		key := v.Key()
+		if v.Index == "0" {
+			key = v.Name
+		}
+		row[key] = v.Value
+	}
+	return row
+}
+
+// TableRows 将表格遍历结果按索引分组为行，键为列名，index 列为行索引，行顺序与设备返回顺序一致
+func TableRows(vars []Variable) []map[string]interface{} {
+	var rows []map[string]interface{}
+	byIndex := make(map[string]map[string]interface{})
+	for _, v := range vars {
+		if v.Index == "" {
+			continue
+		}
+		row, ok := byIndex[v.Index]
+		if !ok {
+			row = map[string]interface{}{"index": v.Index}
+			byIndex[v.Index] = row
+			rows = append(rows, row)
+		}
+		row[v.Name] = v.Value
+	}
+	return rows
+}
+
+// WithFields 为每行追加相同字段，用于写入 ClickHouse 前补充设备和时间列
+func WithFields(rows []map[string]interface{}, fields map[string]interface{}) []map[string]interface{} {
+	for _, row := range rows {
+		for k, v := range fields {
+			row[k] = v
+		}
+	}
+	return rows
+}
@@ -0,0 +1,95 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"net"
+	"strings"
+
+	goredis "github.com/redis/go-redis/v9"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// maxStatementLen 记录到Span的语句最大长度，避免大批量SQL撑大Span
+const maxStatementLen = 1024
+
+// StartDBSpan 创建数据库调用Span，system 如 clickhouse/mysql/redis
+func StartDBSpan(ctx context.Context, system, operation, statement string) (context.Context, trace.Span) {
+	attrs := []attribute.KeyValue{
+		attribute.String("db.system.name", system),
+		attribute.String("db.operation.name", operation),
+	}
+	if statement != "" {
+		if len(statement) > maxStatementLen {
+			statement = statement[:maxStatementLen]
+		}
+		attrs = append(attrs, attribute.String("db.query.text", statement))
+	}
+	return StartClientSpan(ctx, system+" "+operation, attrs...)
+}
+
+// RedisHook go-redis 追踪钩子，通过 RedisClient.AddHook 注册
+type RedisHook struct{}
+
+// NewRedisHook 创建 Redis 追踪钩子
+func NewRedisHook() RedisHook {
+	return RedisHook{}
+}
+
+// DialHook 实现 goredis.Hook
+func (RedisHook) DialHook(next goredis.DialHook) goredis.DialHook {
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		ctx, span := StartClientSpan(ctx, "redis dial", attribute.String("server.address", addr))
+		defer span.End()
+		conn, err := next(ctx, network, addr)
+		RecordError(span, err)
+		return conn, err
+	}
+}
+
+// ProcessHook 实现 goredis.Hook
+func (RedisHook) ProcessHook(next goredis.ProcessHook) goredis.ProcessHook {
+	return func(ctx context.Context, cmd goredis.Cmder) error {
+		ctx, span := StartDBSpan(ctx, "redis", cmd.Name(), redisStatement(cmd))
+		defer span.End()
+		err := next(ctx, cmd)
+		recordRedisError(span, err)
+		return err
+	}
+}
+
+// ProcessPipelineHook 实现 goredis.Hook
+func (RedisHook) ProcessPipelineHook(next goredis.ProcessPipelineHook) goredis.ProcessPipelineHook {
+	return func(ctx context.Context, cmds []goredis.Cmder) error {
+		names := make([]string, 0, len(cmds))
+		for _, cmd := range cmds {
+			names = append(names, cmd.Name())
+		}
+		ctx, span := StartDBSpan(ctx, "redis", "pipeline", strings.Join(names, " "))
+		span.SetAttributes(attribute.Int("db.operation.batch.size", len(cmds)))
+		defer span.End()
+		err := next(ctx, cmds)
+		recordRedisError(span, err)
+		return err
+	}
+}
+
+// redisStatement 返回命令名和键名，不记录值以免泄露数据
+func redisStatement(cmd goredis.Cmder) string {
+	args := cmd.Args()
+	if len(args) > 1 {
+		if key, ok := args[1].(string); ok {
+			return cmd.Name() + " " + key
+		}
+	}
+	return cmd.Name()
+}
+
+// recordRedisError 记录错误，redis.Nil 表示键不存在，不视为错误
+func recordRedisError(span trace.Span, err error) {
+	if errors.Is(err, goredis.Nil) {
+		return
+	}
+	RecordError(span, err)
+}
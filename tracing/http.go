@@ -0,0 +1,57 @@
+package tracing
+
+import (
+	"fmt"
+	"net/http"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+)
+
+// Transport 为每个HTTP请求创建客户端Span并注入传播头
+type Transport struct {
+	Base http.RoundTripper // 底层传输层，为nil时使用 http.DefaultTransport
+}
+
+// NewTransport 创建追踪传输层，可作为 httpclient.Config.Transport 使用
+func NewTransport(base http.RoundTripper) *Transport {
+	return &Transport{Base: base}
+}
+
+// RoundTrip 实现 http.RoundTripper
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	base := t.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+
+	ctx, span := StartClientSpan(req.Context(), "HTTP "+req.Method,
+		attribute.String("http.request.method", req.Method),
+		attribute.String("url.full", req.URL.Redacted()),
+		attribute.String("server.address", req.URL.Hostname()),
+	)
+	defer span.End()
+
+	// RoundTripper 不应修改原请求，注入传播头前先复制
+	req = req.Clone(ctx)
+	otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(req.Header))
+
+	resp, err := base.RoundTrip(req)
+	if err != nil {
+		RecordError(span, err)
+		return nil, err
+	}
+	span.SetAttributes(attribute.Int("http.response.status_code", resp.StatusCode))
+	if resp.StatusCode >= 500 {
+		span.SetStatus(codes.Error, fmt.Sprintf("http status %d", resp.StatusCode))
+	}
+	return resp, nil
+}
+
+// Extract 从请求头中提取上游传递的链路上下文，用于服务端
+func Extract(req *http.Request) *http.Request {
+	ctx := otel.GetTextMapPropagator().Extract(req.Context(), propagation.HeaderCarrier(req.Header))
+	return req.WithContext(ctx)
+}
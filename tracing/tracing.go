@@ -0,0 +1,209 @@
+// Package tracing 提供OpenTelemetry链路追踪的初始化和常用辅助函数。
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.34.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// 导出协议
+const (
+	ProtocolGRPC = "grpc"
+	ProtocolHTTP = "http"
+)
+
+// 采样策略
+const (
+	SamplerAlways = "always"
+	SamplerNever  = "never"
+	SamplerRatio  = "ratio"
+)
+
+// instrumentationName 本库创建的Tracer名称
+const instrumentationName = "github.com/ixxmi/tools"
+
+// Config 配置结构
+type Config struct {
+	ServiceName    string            // 服务名，必填
+	ServiceVersion string            // 服务版本
+	Environment    string            // 部署环境，如 prod/test
+	Endpoint       string            // OTLP 接收端地址，grpc 默认 localhost:4317，http 默认 localhost:4318
+	Protocol       string            // 导出协议 grpc/http，默认 grpc
+	Insecure       bool              // 不使用TLS
+	Headers        map[string]string // 导出请求附带的请求头，如鉴权令牌
+	Timeout        time.Duration     // 单次导出超时时间，默认 10s
+	Sampler        string            // 采样策略 always/never/ratio，默认 always
+	SampleRatio    float64           // Sampler 为 ratio 时的采样比例 (0,1]
+	Attributes     map[string]string // 额外的资源属性
+	Disabled       bool              // 关闭追踪，仅设置传播器，StartSpan 返回空Span
+}
+
+// ShutdownFunc 刷新并关闭追踪，应在进程退出前调用
+type ShutdownFunc func(ctx context.Context) error
+
+// Init 初始化全局 TracerProvider 和传播器
+func Init(ctx context.Context, config Config) (ShutdownFunc, error) {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
+		propagation.TraceContext{},
+		propagation.Baggage{},
+	))
+	if config.Disabled {
+		return func(context.Context) error { return nil }, nil
+	}
+	if config.ServiceName == "" {
+		return nil, fmt.Errorf("tracing: service name is required")
+	}
+
+	sampler, err := newSampler(config)
+	if err != nil {
+		return nil, err
+	}
+	res, err := newResource(ctx, config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create resource: %w", err)
+	}
+	exporter, err := newExporter(ctx, config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create exporter: %w", err)
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.ParentBased(sampler)),
+	)
+	otel.SetTracerProvider(provider)
+	return provider.Shutdown, nil
+}
+
+// newExporter 根据协议创建OTLP导出器
+func newExporter(ctx context.Context, config Config) (sdktrace.SpanExporter, error) {
+	timeout := config.Timeout
+	if timeout <= 0 {
+		timeout = 10 * time.Second
+	}
+
+	switch strings.ToLower(config.Protocol) {
+	case "", ProtocolGRPC:
+		opts := []otlptracegrpc.Option{otlptracegrpc.WithTimeout(timeout)}
+		if config.Endpoint != "" {
+			opts = append(opts, otlptracegrpc.WithEndpoint(config.Endpoint))
+		}
+		if config.Insecure {
+			opts = append(opts, otlptracegrpc.WithInsecure())
+		}
+		if len(config.Headers) > 0 {
+			opts = append(opts, otlptracegrpc.WithHeaders(config.Headers))
+		}
+		return otlptracegrpc.New(ctx, opts...)
+	case ProtocolHTTP:
+		opts := []otlptracehttp.Option{otlptracehttp.WithTimeout(timeout)}
+		if config.Endpoint != "" {
+			opts = append(opts, otlptracehttp.WithEndpoint(config.Endpoint))
+		}
+		if config.Insecure {
+			opts = append(opts, otlptracehttp.WithInsecure())
+		}
+		if len(config.Headers) > 0 {
+			opts = append(opts, otlptracehttp.WithHeaders(config.Headers))
+		}
+		return otlptracehttp.New(ctx, opts...)
+	}
+	return nil, fmt.Errorf("unsupported protocol %s", config.Protocol)
+}
+
+// newSampler 根据配置创建采样器
+func newSampler(config Config) (sdktrace.Sampler, error) {
+	switch strings.ToLower(config.Sampler) {
+	case "", SamplerAlways:
+		return sdktrace.AlwaysSample(), nil
+	case SamplerNever:
+		return sdktrace.NeverSample(), nil
+	case SamplerRatio:
+		if config.SampleRatio <= 0 || config.SampleRatio > 1 {
+			return nil, fmt.Errorf("invalid sample ratio %v", config.SampleRatio)
+		}
+		return sdktrace.TraceIDRatioBased(config.SampleRatio), nil
+	}
+	return nil, fmt.Errorf("unsupported sampler %s", config.Sampler)
+}
+
+// newResource 创建资源属性，包含服务信息、主机和进程信息
+func newResource(ctx context.Context, config Config) (*resource.Resource, error) {
+	attrs := []attribute.KeyValue{semconv.ServiceName(config.ServiceName)}
+	if config.ServiceVersion != "" {
+		attrs = append(attrs, semconv.ServiceVersion(config.ServiceVersion))
+	}
+	if config.Environment != "" {
+		attrs = append(attrs, semconv.DeploymentEnvironmentName(config.Environment))
+	}
+	for k, v := range config.Attributes {
+		attrs = append(attrs, attribute.String(k, v))
+	}
+	return resource.New(ctx,
+		resource.WithAttributes(attrs...),
+		resource.WithHost(),
+		resource.WithProcessPID(),
+		resource.WithTelemetrySDK(),
+	)
+}
+
+// Tracer 返回本库使用的Tracer
+func Tracer() trace.Tracer {
+	return otel.Tracer(instrumentationName)
+}
+
+// StartSpan 创建子Span，调用方负责调用 span.End()
+func StartSpan(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return Tracer().Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+// StartClientSpan 创建调用外部服务的客户端Span
+func StartClientSpan(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return Tracer().Start(ctx, name, trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(attrs...))
+}
+
+// FromContext 获取ctx中的当前Span，不存在时返回空Span
+func FromContext(ctx context.Context) trace.Span {
+	if ctx == nil {
+		return trace.SpanFromContext(context.Background())
+	}
+	return trace.SpanFromContext(ctx)
+}
+
+// TraceID 获取ctx中的TraceID，不存在时返回空字符串，用于日志关联
+func TraceID(ctx context.Context) string {
+	sc := FromContext(ctx).SpanContext()
+	if !sc.HasTraceID() {
+		return ""
+	}
+	return sc.TraceID().String()
+}
+
+// RecordError 在Span上记录错误并标记状态，err为nil时不做处理
+func RecordError(span trace.Span, err error) {
+	if err == nil {
+		return
+	}
+	span.RecordError(err)
+	span.SetStatus(codes.Error, err.Error())
+}
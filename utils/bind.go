@@ -0,0 +1,523 @@
+package utils
+
+import (
+	"encoding"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+// 反射映射引擎，按 encoding/json 的语义直接在内存中映射 struct/map/slice，
+// 避免 Bind 生成中间 JSON 字符串。遇到自定义序列化（json.Marshaler、time.Time 等）、
+// []byte、非字符串 map 键、带 ,string 选项或嵌入字段的结构体时，对该子树退回 JSON 编解码，
+// 保证结果与 json.Marshal + json.Unmarshal 一致。
+
+var (
+	jsonMarshalerType   = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	jsonUnmarshalerType = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+	textMarshalerType   = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+	jsonNumberType      = reflect.TypeOf(json.Number(""))
+)
+
+const maxBindDepth = 1000
+
+var errBindTooDeep = errors.New("bind: exceeded max depth, possible cycle")
+
+// bindField 结构体字段的 JSON 映射信息
+type bindField struct {
+	name      string
+	index     int
+	omitEmpty bool
+}
+
+// bindStruct 结构体的字段映射，fallback 为true时整体使用 JSON 编解码
+type bindStruct struct {
+	fields   []bindField
+	byName   map[string]int
+	fallback bool
+}
+
+var bindStructCache sync.Map // reflect.Type -> *bindStruct
+
+func structInfo(t reflect.Type) *bindStruct {
+	if v, ok := bindStructCache.Load(t); ok {
+		return v.(*bindStruct)
+	}
+	info := &bindStruct{byName: make(map[string]int)}
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if f.Anonymous {
+			info.fallback = true
+			break
+		}
+		if !f.IsExported() {
+			continue
+		}
+		tag := f.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, opts, _ := strings.Cut(tag, ",")
+		if name == "" {
+			name = f.Name
+		}
+		bf := bindField{name: name, index: i}
+		for _, opt := range strings.Split(opts, ",") {
+			switch opt {
+			case "omitempty":
+				bf.omitEmpty = true
+			case "string":
+				info.fallback = true
+			}
+		}
+		if _, dup := info.byName[name]; dup {
+			// 同名字段按 JSON 规则相互抵消，交给 JSON 处理
+			info.fallback = true
+		}
+		info.byName[name] = len(info.fields)
+		info.fields = append(info.fields, bf)
+	}
+	v, _ := bindStructCache.LoadOrStore(t, info)
+	return v.(*bindStruct)
+}
+
+// lookup 按字段名查找，优先精确匹配，其次不区分大小写
+func (s *bindStruct) lookup(key string) (bindField, bool) {
+	if i, ok := s.byName[key]; ok {
+		return s.fields[i], true
+	}
+	for _, f := range s.fields {
+		if strings.EqualFold(f.name, key) {
+			return f, true
+		}
+	}
+	return bindField{}, false
+}
+
+// customJSON 类型有自定义序列化或特殊编码，需要走 JSON
+func customJSON(t reflect.Type, iface ...reflect.Type) bool {
+	for _, it := range iface {
+		if t.Implements(it) || (t.Kind() != reflect.Ptr && reflect.PointerTo(t).Implements(it)) {
+			return true
+		}
+	}
+	return false
+}
+
+func srcNeedsJSON(t reflect.Type) bool {
+	if t == jsonNumberType || customJSON(t, jsonMarshalerType, textMarshalerType) {
+		return true
+	}
+	switch t.Kind() {
+	case reflect.Slice:
+		return t.Elem().Kind() == reflect.Uint8
+	case reflect.Map:
+		return t.Key().Kind() != reflect.String
+	case reflect.Array, reflect.Complex64, reflect.Complex128, reflect.Chan, reflect.Func, reflect.UnsafePointer:
+		return true
+	case reflect.Struct:
+		return structInfo(t).fallback
+	}
+	return false
+}
+
+func dstNeedsJSON(t reflect.Type) bool {
+	if t == jsonNumberType || customJSON(t, jsonUnmarshalerType, textUnmarshalerType) {
+		return true
+	}
+	switch t.Kind() {
+	case reflect.Slice:
+		return t.Elem().Kind() == reflect.Uint8
+	case reflect.Map:
+		return t.Key().Kind() != reflect.String
+	case reflect.Interface:
+		return t.NumMethod() != 0
+	case reflect.Array, reflect.Complex64, reflect.Complex128, reflect.Chan, reflect.Func, reflect.UnsafePointer:
+		return true
+	case reflect.Struct:
+		return structInfo(t).fallback
+	}
+	return false
+}
+
+// bindJSON 子树退回 JSON 编解码，dst 必须可寻址
+func bindJSON(dst, src reflect.Value) error {
+	var in interface{}
+	if src.IsValid() {
+		in = src.Interface()
+	}
+	data, err := json.Marshal(in)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, dst.Addr().Interface())
+}
+
+// bindValue 将 src 映射到可寻址的 dst
+func bindValue(dst, src reflect.Value, depth int) error {
+	if depth > maxBindDepth {
+		return errBindTooDeep
+	}
+	// 解开接口和指针，nil 对应 JSON null
+	for src.IsValid() && (src.Kind() == reflect.Interface || src.Kind() == reflect.Ptr) {
+		if src.IsNil() {
+			src = reflect.Value{}
+			break
+		}
+		if src.Kind() == reflect.Ptr && srcNeedsJSON(src.Type()) {
+			return bindJSON(dst, src)
+		}
+		src = src.Elem()
+	}
+	if src.IsValid() && srcNeedsJSON(src.Type()) || dstNeedsJSON(dst.Type()) {
+		return bindJSON(dst, src)
+	}
+	if !src.IsValid() {
+		// null 只清空指针、接口、map 和切片，其他类型保持不变
+		switch dst.Kind() {
+		case reflect.Ptr, reflect.Interface, reflect.Map, reflect.Slice:
+			dst.Set(reflect.Zero(dst.Type()))
+		}
+		return nil
+	}
+
+	switch dst.Kind() {
+	case reflect.Ptr:
+		if dst.IsNil() {
+			dst.Set(reflect.New(dst.Type().Elem()))
+		}
+		return bindValue(dst.Elem(), src, depth+1)
+	case reflect.Interface:
+		v, err := toGeneric(src, depth+1)
+		if err != nil {
+			return err
+		}
+		if v == nil {
+			dst.Set(reflect.Zero(dst.Type()))
+		} else {
+			dst.Set(reflect.ValueOf(v))
+		}
+		return nil
+	case reflect.Struct:
+		return bindStructValue(dst, src, depth)
+	case reflect.Map:
+		return bindMapValue(dst, src, depth)
+	case reflect.Slice:
+		return bindSliceValue(dst, src, depth)
+	}
+	return bindScalar(dst, src)
+}
+
+func bindStructValue(dst, src reflect.Value, depth int) error {
+	info := structInfo(dst.Type())
+	switch src.Kind() {
+	case reflect.Map:
+		iter := src.MapRange()
+		for iter.Next() {
+			f, ok := info.lookup(iter.Key().String())
+			if !ok {
+				continue
+			}
+			if err := bindValue(dst.Field(f.index), iter.Value(), depth+1); err != nil {
+				return err
+			}
+		}
+		return nil
+	case reflect.Struct:
+		sinfo := structInfo(src.Type())
+		for _, sf := range sinfo.fields {
+			v := src.Field(sf.index)
+			if sf.omitEmpty && isEmptyValue(v) {
+				continue
+			}
+			f, ok := info.lookup(sf.name)
+			if !ok {
+				continue
+			}
+			if err := bindValue(dst.Field(f.index), v, depth+1); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	return bindTypeError(dst, src)
+}
+
+func bindMapValue(dst, src reflect.Value, depth int) error {
+	if src.Kind() != reflect.Map && src.Kind() != reflect.Struct {
+		return bindTypeError(dst, src)
+	}
+	if dst.IsNil() {
+		dst.Set(reflect.MakeMap(dst.Type()))
+	}
+	keyType, elemType := dst.Type().Key(), dst.Type().Elem()
+	set := func(key string, v reflect.Value) error {
+		elem := reflect.New(elemType).Elem()
+		if err := bindValue(elem, v, depth+1); err != nil {
+			return err
+		}
+		dst.SetMapIndex(reflect.ValueOf(key).Convert(keyType), elem)
+		return nil
+	}
+	if src.Kind() == reflect.Map {
+		iter := src.MapRange()
+		for iter.Next() {
+			if err := set(iter.Key().String(), iter.Value()); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	for _, sf := range structInfo(src.Type()).fields {
+		v := src.Field(sf.index)
+		if sf.omitEmpty && isEmptyValue(v) {
+			continue
+		}
+		if err := set(sf.name, v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func bindSliceValue(dst, src reflect.Value, depth int) error {
+	if src.Kind() != reflect.Slice {
+		return bindTypeError(dst, src)
+	}
+	if src.IsNil() {
+		dst.Set(reflect.Zero(dst.Type()))
+		return nil
+	}
+	n := src.Len()
+	out := reflect.MakeSlice(dst.Type(), n, n)
+	for i := 0; i < n; i++ {
+		if err := bindValue(out.Index(i), src.Index(i), depth+1); err != nil {
+			return err
+		}
+	}
+	dst.Set(out)
+	return nil
+}
+
+// bindScalar 处理数值、字符串和布尔值，数值之间按 JSON 规则转换
+func bindScalar(dst, src reflect.Value) error {
+	switch src.Kind() {
+	case reflect.String:
+		if dst.Kind() != reflect.String {
+			return bindTypeError(dst, src)
+		}
+		s := src.String()
+		if !utf8.ValidString(s) {
+			return bindJSON(dst, src)
+		}
+		dst.SetString(s)
+		return nil
+	case reflect.Bool:
+		if dst.Kind() != reflect.Bool {
+			return bindTypeError(dst, src)
+		}
+		dst.SetBool(src.Bool())
+		return nil
+	case reflect.Float32, reflect.Float64:
+		f := src.Float()
+		if math.IsNaN(f) || math.IsInf(f, 0) {
+			return bindJSON(dst, src)
+		}
+		if dst.Kind() == reflect.Float32 || dst.Kind() == reflect.Float64 {
+			if dst.OverflowFloat(f) {
+				return bindTypeError(dst, src)
+			}
+			dst.SetFloat(f)
+			return nil
+		}
+		if f != math.Trunc(f) {
+			return bindTypeError(dst, src)
+		}
+		return bindInteger(dst, src, f)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		switch dst.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			if dst.OverflowInt(src.Int()) {
+				return bindTypeError(dst, src)
+			}
+			dst.SetInt(src.Int())
+			return nil
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+			if src.Int() < 0 || dst.OverflowUint(uint64(src.Int())) {
+				return bindTypeError(dst, src)
+			}
+			dst.SetUint(uint64(src.Int()))
+			return nil
+		case reflect.Float32, reflect.Float64:
+			dst.SetFloat(float64(src.Int()))
+			return nil
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		switch dst.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			if src.Uint() > math.MaxInt64 || dst.OverflowInt(int64(src.Uint())) {
+				return bindTypeError(dst, src)
+			}
+			dst.SetInt(int64(src.Uint()))
+			return nil
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+			if dst.OverflowUint(src.Uint()) {
+				return bindTypeError(dst, src)
+			}
+			dst.SetUint(src.Uint())
+			return nil
+		case reflect.Float32, reflect.Float64:
+			dst.SetFloat(float64(src.Uint()))
+			return nil
+		}
+	}
+	return bindTypeError(dst, src)
+}
+
+// bindInteger 整数值的浮点数写入整数字段
+func bindInteger(dst, src reflect.Value, f float64) error {
+	switch dst.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if f < math.MinInt64 || f >= math.MaxInt64 || dst.OverflowInt(int64(f)) {
+			return bindTypeError(dst, src)
+		}
+		dst.SetInt(int64(f))
+		return nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		if f < 0 || f >= math.MaxUint64 || dst.OverflowUint(uint64(f)) {
+			return bindTypeError(dst, src)
+		}
+		dst.SetUint(uint64(f))
+		return nil
+	}
+	return bindTypeError(dst, src)
+}
+
+// toGeneric 转换为 JSON 解码到 interface{} 时的结构：map[string]interface{}、[]interface{}、float64、string、bool、nil
+func toGeneric(src reflect.Value, depth int) (interface{}, error) {
+	if depth > maxBindDepth {
+		return nil, errBindTooDeep
+	}
+	for src.IsValid() && (src.Kind() == reflect.Interface || src.Kind() == reflect.Ptr) {
+		if src.IsNil() {
+			return nil, nil
+		}
+		if src.Kind() == reflect.Ptr && srcNeedsJSON(src.Type()) {
+			break
+		}
+		src = src.Elem()
+	}
+	if !src.IsValid() {
+		return nil, nil
+	}
+	if srcNeedsJSON(src.Type()) {
+		var out interface{}
+		data, err := json.Marshal(src.Interface())
+		if err != nil {
+			return nil, err
+		}
+		err = json.Unmarshal(data, &out)
+		return out, err
+	}
+	switch src.Kind() {
+	case reflect.Bool:
+		return src.Bool(), nil
+	case reflect.String:
+		s := src.String()
+		if !utf8.ValidString(s) {
+			// 非法 UTF-8 由 JSON 逐字节替换为 U+FFFD
+			var out string
+			data, _ := json.Marshal(s)
+			err := json.Unmarshal(data, &out)
+			return out, err
+		}
+		return s, nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(src.Int()), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return float64(src.Uint()), nil
+	case reflect.Float32, reflect.Float64:
+		f := src.Float()
+		if math.IsNaN(f) || math.IsInf(f, 0) {
+			return nil, &json.UnsupportedValueError{Value: src, Str: fmt.Sprint(f)}
+		}
+		if src.Kind() == reflect.Float32 {
+			// 与 JSON 一致：float32 按最短表示输出后再解析为 float64
+			return jsonFloat32(f), nil
+		}
+		return f, nil
+	case reflect.Slice:
+		if src.IsNil() {
+			return nil, nil
+		}
+		out := make([]interface{}, src.Len())
+		for i := range out {
+			v, err := toGeneric(src.Index(i), depth+1)
+			if err != nil {
+				return nil, err
+			}
+			out[i] = v
+		}
+		return out, nil
+	case reflect.Map:
+		if src.IsNil() {
+			return nil, nil
+		}
+		out := make(map[string]interface{}, src.Len())
+		iter := src.MapRange()
+		for iter.Next() {
+			v, err := toGeneric(iter.Value(), depth+1)
+			if err != nil {
+				return nil, err
+			}
+			out[iter.Key().String()] = v
+		}
+		return out, nil
+	case reflect.Struct:
+		info := structInfo(src.Type())
+		out := make(map[string]interface{}, len(info.fields))
+		for _, sf := range info.fields {
+			fv := src.Field(sf.index)
+			if sf.omitEmpty && isEmptyValue(fv) {
+				continue
+			}
+			v, err := toGeneric(fv, depth+1)
+			if err != nil {
+				return nil, err
+			}
+			out[sf.name] = v
+		}
+		return out, nil
+	}
+	return nil, &json.UnsupportedTypeError{Type: src.Type()}
+}
+
+func jsonFloat32(f float64) float64 {
+	var out float64
+	data, _ := json.Marshal(float32(f))
+	_ = json.Unmarshal(data, &out)
+	return out
+}
+
+// isEmptyValue 与 encoding/json 的 omitempty 判断一致
+func isEmptyValue(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return v.Len() == 0
+	case reflect.Bool, reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64, reflect.Interface, reflect.Ptr:
+		return v.IsZero()
+	}
+	return false
+}
+
+func bindTypeError(dst, src reflect.Value) error {
+	return fmt.Errorf("bind: cannot map %s into %s", src.Type(), dst.Type())
+}
@@ -0,0 +1,89 @@
+package utils
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type benchItem struct {
+	ID      int64             `json:"id"`
+	Name    string            `json:"name"`
+	Score   float64           `json:"score"`
+	Enabled bool              `json:"enabled"`
+	Tags    []string          `json:"tags"`
+	Attrs   map[string]string `json:"attrs,omitempty"`
+	Created time.Time         `json:"created"`
+}
+
+type benchView struct {
+	ID    int64    `json:"id"`
+	Name  string   `json:"name"`
+	Score float64  `json:"score"`
+	Tags  []string `json:"tags"`
+}
+
+// bindRoundTrip 改为反射映射之前的 Bind 实现
+func bindRoundTrip(data interface{}, ret interface{}) error {
+	b, _ := json.MarshalIndent(data, "", "    ")
+	return json.Unmarshal(b, ret)
+}
+
+func benchData(n int) []benchItem {
+	items := make([]benchItem, n)
+	now := time.Now()
+	for i := range items {
+		items[i] = benchItem{
+			ID:      int64(i),
+			Name:    "item",
+			Score:   float64(i) / 3,
+			Enabled: i%2 == 0,
+			Tags:    []string{"a", "b", "c"},
+			Attrs:   map[string]string{"k": "v"},
+			Created: now,
+		}
+	}
+	return items
+}
+
+func BenchmarkBind(b *testing.B) {
+	data := benchData(1000)
+	var want, got []benchView
+	if err := bindRoundTrip(data, &want); err != nil {
+		b.Fatal(err)
+	}
+	if err := Bind(data, &got); err != nil {
+		b.Fatal(err)
+	}
+	if !reflect.DeepEqual(want, got) {
+		b.Fatal("Bind result differs from the JSON round trip")
+	}
+
+	targets := []struct {
+		name string
+		new  func() interface{}
+	}{
+		{"Struct", func() interface{} { return &[]benchView{} }},
+		{"Maps", func() interface{} { return &[]map[string]interface{}{} }},
+	}
+	impls := []struct {
+		name string
+		bind func(data, ret interface{}) error
+	}{
+		{"RoundTrip", bindRoundTrip},
+		{"Reflect", Bind},
+	}
+	for _, t := range targets {
+		for _, impl := range impls {
+			b.Run(t.name+"/"+impl.name, func(b *testing.B) {
+				b.ReportAllocs()
+				for i := 0; i < b.N; i++ {
+					if err := impl.bind(data, t.new()); err != nil {
+						b.Fatal(err)
+					}
+				}
+			})
+		}
+	}
+}
@@ -0,0 +1,148 @@
+// Package codec 提供 JSON、YAML、TOML 与 map 之间的互相转换，配置处理代码可以不关心文件格式，
+// 统一使用 map[string]interface{} 操作
+package codec
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+// 支持的格式
+const (
+	FormatJSON = "json"
+	FormatYAML = "yaml"
+	FormatTOML = "toml"
+)
+
+// JsonToMap json 字节流转 map，数字保留为 json.Number 避免大整数丢失精度
+func JsonToMap(data []byte) (map[string]interface{}, error) {
+	m := make(map[string]interface{})
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	if err := dec.Decode(&m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// MapToJson map 转缩进格式的 json
+func MapToJson(m map[string]interface{}) ([]byte, error) {
+	return json.MarshalIndent(m, "", "  ")
+}
+
+// YamlToMap yaml 字节流转 map。非字符串键（如 1: a）转为字符串，嵌套结构同样处理
+func YamlToMap(data []byte) (map[string]interface{}, error) {
+	m := make(map[string]interface{})
+	if err := yaml.Unmarshal(data, &m); err != nil {
+		return nil, err
+	}
+	return normalize(m).(map[string]interface{}), nil
+}
+
+// YamlToListMap 顶层为序列的 yaml 转 map 切片
+func YamlToListMap(data []byte) ([]map[string]interface{}, error) {
+	var list []map[string]interface{}
+	if err := yaml.Unmarshal(data, &list); err != nil {
+		return nil, err
+	}
+	for i, m := range list {
+		list[i], _ = normalize(m).(map[string]interface{})
+	}
+	return list, nil
+}
+
+// MapToYaml map 转 yaml，缩进2个空格，键按名称排序
+func MapToYaml(m map[string]interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(m); err != nil {
+		return nil, err
+	}
+	if err := enc.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// TomlToMap toml 字节流转 map，整数为 int64，日期时间为 time.Time 或 toml.Local* 类型
+func TomlToMap(data []byte) (map[string]interface{}, error) {
+	m := make(map[string]interface{})
+	if _, err := toml.Decode(string(data), &m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// MapToToml map 转 toml，值为 nil 的键被忽略
+func MapToToml(m map[string]interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := toml.NewEncoder(&buf)
+	enc.Indent = ""
+	if err := enc.Encode(m); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// ToMap 按格式解析为 map，format 为 json/yaml/yml/toml，可带前导点（如 filepath.Ext 的结果）
+func ToMap(data []byte, format string) (map[string]interface{}, error) {
+	switch normalizeFormat(format) {
+	case FormatJSON:
+		return JsonToMap(data)
+	case FormatYAML:
+		return YamlToMap(data)
+	case FormatTOML:
+		return TomlToMap(data)
+	}
+	return nil, fmt.Errorf("codec: unsupported format %s", format)
+}
+
+// FromMap 按格式编码 map
+func FromMap(m map[string]interface{}, format string) ([]byte, error) {
+	switch normalizeFormat(format) {
+	case FormatJSON:
+		return MapToJson(m)
+	case FormatYAML:
+		return MapToYaml(m)
+	case FormatTOML:
+		return MapToToml(m)
+	}
+	return nil, fmt.Errorf("codec: unsupported format %s", format)
+}
+
+func normalizeFormat(format string) string {
+	f := strings.ToLower(strings.TrimPrefix(format, "."))
+	if f == "yml" {
+		return FormatYAML
+	}
+	return f
+}
+
+// normalize 将 yaml 解析出的 map[interface{}]interface{} 转为 map[string]interface{}
+func normalize(v interface{}) interface{} {
+	switch x := v.(type) {
+	case map[string]interface{}:
+		for k, e := range x {
+			x[k] = normalize(e)
+		}
+		return x
+	case map[interface{}]interface{}:
+		m := make(map[string]interface{}, len(x))
+		for k, e := range x {
+			m[fmt.Sprint(k)] = normalize(e)
+		}
+		return m
+	case []interface{}:
+		for i, e := range x {
+			x[i] = normalize(e)
+		}
+		return x
+	}
+	return v
+}
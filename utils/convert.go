@@ -0,0 +1,417 @@
+package utils
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// 以下为转换函数的 E 版本：转换失败时返回错误而不是打印日志并返回零值，便于区分"值为0"和"转换失败"。
+// 数值转换规则与原函数一致（浮点转整数直接截断），另外会解引用指针并检查目标类型的取值范围。
+
+var (
+	// ErrUnsupportedType 输入类型不支持转换
+	ErrUnsupportedType = errors.New("unsupported type")
+	// ErrOutOfRange 数值超出目标类型范围
+	ErrOutOfRange = errors.New("value out of range")
+)
+
+var (
+	// int 转换，失败返回错误
+	IntE   = InterfaceToIntE
+	Int64E = InterfaceToInt64E
+	Int32E = InterfaceToInt32E
+	// float 转换，失败返回错误
+	Float64E = InterfaceToFloat64E
+	// string 转换，失败返回错误
+	StrE = InterfaceToStrE
+	// bool 转换，失败返回错误
+	BoolE = InterfaceToBoolE
+)
+
+// indirect 解引用指针，nil 返回无效值
+func indirect(x interface{}) reflect.Value {
+	v := reflect.ValueOf(x)
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return reflect.Value{}
+		}
+		v = v.Elem()
+	}
+	return v
+}
+
+func convertError(x interface{}, to string) error {
+	return fmt.Errorf("%w: cannot convert %T to %s", ErrUnsupportedType, x, to)
+}
+
+// InterfaceToInt64E 转换为int64
+func InterfaceToInt64E(x interface{}) (int64, error) {
+	switch st := indirect(x); st.Kind() {
+	case reflect.Uint, reflect.Uintptr, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if st.Uint() > math.MaxInt64 {
+			return 0, fmt.Errorf("%w: %d overflows int64", ErrOutOfRange, st.Uint())
+		}
+		return int64(st.Uint()), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return st.Int(), nil
+	case reflect.Float32, reflect.Float64:
+		f := st.Float()
+		if math.IsNaN(f) || f >= math.MaxInt64 || f < math.MinInt64 {
+			return 0, fmt.Errorf("%w: %v overflows int64", ErrOutOfRange, f)
+		}
+		return int64(f), nil
+	case reflect.String:
+		return strconv.ParseInt(strings.TrimSpace(st.String()), 10, 64)
+	case reflect.Bool:
+		if st.Bool() {
+			return 1, nil
+		}
+		return 0, nil
+	}
+	return 0, convertError(x, "int64")
+}
+
+// InterfaceToIntE 转换为int
+func InterfaceToIntE(x interface{}) (int, error) {
+	v, err := InterfaceToInt64E(x)
+	if err != nil {
+		return 0, err
+	}
+	if v > math.MaxInt || v < math.MinInt {
+		return 0, fmt.Errorf("%w: %d overflows int", ErrOutOfRange, v)
+	}
+	return int(v), nil
+}
+
+// InterfaceToInt32E 转换为int32
+func InterfaceToInt32E(x interface{}) (int32, error) {
+	v, err := InterfaceToInt64E(x)
+	if err != nil {
+		return 0, err
+	}
+	if v > math.MaxInt32 || v < math.MinInt32 {
+		return 0, fmt.Errorf("%w: %d overflows int32", ErrOutOfRange, v)
+	}
+	return int32(v), nil
+}
+
+// ToIntE 转换为int，同 InterfaceToIntE
+func ToIntE(x interface{}) (int, error) {
+	return InterfaceToIntE(x)
+}
+
+// InterfaceToFloat64E 转换为float64
+func InterfaceToFloat64E(x interface{}) (float64, error) {
+	switch st := indirect(x); st.Kind() {
+	case reflect.Uint, reflect.Uintptr, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(st.Uint()), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(st.Int()), nil
+	case reflect.Float32, reflect.Float64:
+		return st.Float(), nil
+	case reflect.String:
+		return strconv.ParseFloat(strings.TrimSpace(st.String()), 64)
+	case reflect.Bool:
+		if st.Bool() {
+			return 1, nil
+		}
+		return 0, nil
+	}
+	return 0, convertError(x, "float64")
+}
+
+// InterfaceToBoolE 转换为bool，字符串按 strconv.ParseBool 解析，数值非0为true
+func InterfaceToBoolE(x interface{}) (bool, error) {
+	switch st := indirect(x); st.Kind() {
+	case reflect.Bool:
+		return st.Bool(), nil
+	case reflect.Uint, reflect.Uintptr, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return st.Uint() != 0, nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return st.Int() != 0, nil
+	case reflect.Float32, reflect.Float64:
+		return st.Float() != 0, nil
+	case reflect.String:
+		return strconv.ParseBool(strings.TrimSpace(st.String()))
+	}
+	return false, convertError(x, "bool")
+}
+
+// InterfaceToStrE 转换为string，支持基本类型、[]byte、error 和 fmt.Stringer
+func InterfaceToStrE(x interface{}) (string, error) {
+	switch v := x.(type) {
+	case []byte:
+		return string(v), nil
+	case error:
+		return v.Error(), nil
+	case fmt.Stringer:
+		return v.String(), nil
+	}
+	switch st := indirect(x); st.Kind() {
+	case reflect.String:
+		return st.String(), nil
+	case reflect.Uint, reflect.Uintptr, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(st.Uint(), 10), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(st.Int(), 10), nil
+	case reflect.Float32:
+		return strconv.FormatFloat(st.Float(), 'g', -1, 32), nil
+	case reflect.Float64:
+		return strconv.FormatFloat(st.Float(), 'g', -1, 64), nil
+	case reflect.Bool:
+		return strconv.FormatBool(st.Bool()), nil
+	}
+	return "", convertError(x, "string")
+}
+
+// ToStringE 转换为string，同 InterfaceToStrE
+func ToStringE(x interface{}) (string, error) {
+	return InterfaceToStrE(x)
+}
+
+// StrToIntE string 转 int
+func StrToIntE(in string) (int, error) {
+	return strconv.Atoi(strings.TrimSpace(in))
+}
+
+// StringToIntE string 转 int
+func StringToIntE(obj string) (int, error) {
+	return StrToIntE(obj)
+}
+
+// StringToInt64E string 转 int64
+func StringToInt64E(obj string) (int64, error) {
+	return strconv.ParseInt(strings.TrimSpace(obj), 10, 64)
+}
+
+// StringToFloat64E string 转 float64
+func StringToFloat64E(obj string) (float64, error) {
+	return strconv.ParseFloat(strings.TrimSpace(obj), 64)
+}
+
+// JsonNumberToInt64E json.Number 转 int64
+func JsonNumberToInt64E(obj json.Number) (int64, error) {
+	return obj.Int64()
+}
+
+// JsonNumberToFloat64E json.Number 转 float64
+func JsonNumberToFloat64E(obj json.Number) (float64, error) {
+	return obj.Float64()
+}
+
+// ToTimeStampE 按 DefaultTimeParser 注册的格式解析时间字符串，返回秒级时间戳
+func ToTimeStampE(in string) (int64, error) {
+	return DefaultTimeParser.ParseUnix(in)
+}
+
+// FromTimeStampE 秒级时间戳（整数、浮点或数字字符串，小数部分为纳秒）按 TimeFormat 格式化
+func FromTimeStampE(in interface{}) (string, error) {
+	var data string
+	switch v := indirect(in); v.Kind() {
+	case reflect.String:
+		data = strings.TrimSpace(v.String())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		data = strconv.FormatInt(v.Int(), 10)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		data = strconv.FormatUint(v.Uint(), 10)
+	case reflect.Float64:
+		data = strconv.FormatFloat(v.Float(), 'f', 5, 64)
+	case reflect.Float32:
+		data = strconv.FormatFloat(v.Float(), 'f', 5, 32)
+	default:
+		return "", convertError(in, "timestamp")
+	}
+	secs, nsecs, _ := strings.Cut(data, ".")
+	sec, err := strconv.ParseInt(secs, 10, 64)
+	if err != nil {
+		return "", err
+	}
+	var nsec int64
+	if nsecs != "" {
+		if nsec, err = strconv.ParseInt(nsecs, 10, 64); err != nil {
+			return "", err
+		}
+	}
+	return time.Unix(sec, nsec).Local().Format(TimeFormat), nil
+}
+
+// InterToSliceStringE []interface{} 或 []string 转 []string，元素必须为字符串
+func InterToSliceStringE(obj interface{}) ([]string, error) {
+	switch v := obj.(type) {
+	case nil:
+		return []string{}, nil
+	case []string:
+		return v, nil
+	case []interface{}:
+		return SliceInterToStringE(v)
+	}
+	return nil, convertError(obj, "[]string")
+}
+
+// SliceInterToStringE []interface{} 转 []string，元素必须为字符串
+func SliceInterToStringE(obj []interface{}) ([]string, error) {
+	sli := make([]string, 0, len(obj))
+	for i, item := range obj {
+		s, ok := item.(string)
+		if !ok {
+			return nil, fmt.Errorf("%w: element %d is %T, not string", ErrUnsupportedType, i, item)
+		}
+		sli = append(sli, s)
+	}
+	return sli, nil
+}
+
+// BJsonToMapE json 字节流转 map
+func BJsonToMapE(bdata []byte) (map[string]interface{}, error) {
+	var jmap map[string]interface{}
+	if err := json.Unmarshal(bdata, &jmap); err != nil {
+		return nil, err
+	}
+	return jmap, nil
+}
+
+// BJsonToListMapE json 数组字节流转 map 切片
+func BJsonToListMapE(bdata []byte) ([]map[string]interface{}, error) {
+	var jmap []map[string]interface{}
+	if err := json.Unmarshal(bdata, &jmap); err != nil {
+		return nil, err
+	}
+	return jmap, nil
+}
+
+// SJsonToMapE json 字符串转 map
+func SJsonToMapE(sdata string) (map[string]interface{}, error) {
+	return BJsonToMapE([]byte(sdata))
+}
+
+// SJsonToListMapE json 数组字符串转 map 切片
+func SJsonToListMapE(sdata string) ([]map[string]interface{}, error) {
+	return BJsonToListMapE([]byte(sdata))
+}
+
+// StructToMapE struct 转 map，键为字段名，只包含导出字段
+func StructToMapE(obj interface{}) (map[string]interface{}, error) {
+	v := indirect(obj)
+	if v.Kind() != reflect.Struct {
+		return nil, convertError(obj, "map")
+	}
+	t := v.Type()
+	data := make(map[string]interface{}, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		if !t.Field(i).IsExported() {
+			continue
+		}
+		data[t.Field(i).Name] = v.Field(i).Interface()
+	}
+	return data, nil
+}
+
+// StringToMapE json 字符串转 map，同 SJsonToMapE
+func StringToMapE(str string) (map[string]interface{}, error) {
+	return SJsonToMapE(str)
+}
+
+// StrToMapE json 字符串转 map，同 SJsonToMapE
+func StrToMapE(str string) (map[string]interface{}, error) {
+	return SJsonToMapE(str)
+}
+
+// MapToStringE 任意值转 json 字符串
+func MapToStringE(m interface{}) (string, error) {
+	data, err := json.Marshal(m)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// MapToStrE map 转 json 字符串
+func MapToStrE(param map[string]interface{}) (string, error) {
+	return MapToStringE(param)
+}
+
+// jsonConvert 经 json 编解码将 obj 转换到 dest，字段名使用 json 标签
+func jsonConvert(obj interface{}, dest interface{}) error {
+	data, err := json.Marshal(obj)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, dest)
+}
+
+// IterToMapE struct 或 map 经 json 编解码转 map，字段名使用 json 标签
+func IterToMapE(obj interface{}) (map[string]interface{}, error) {
+	m := map[string]interface{}{}
+	if err := jsonConvert(obj, &m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// IterToMapsE struct 或 map 的切片经 json 编解码转 map 切片
+func IterToMapsE(obj interface{}) ([]map[string]interface{}, error) {
+	m := []map[string]interface{}{}
+	if err := jsonConvert(obj, &m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// StructToMapMoreE struct 转 map，同 IterToMapE
+func StructToMapMoreE(obj interface{}) (map[string]interface{}, error) {
+	return IterToMapE(obj)
+}
+
+// StructToMapMore1E struct 转值为 map 切片的 map
+func StructToMapMore1E(obj interface{}) (map[string][]map[string]interface{}, error) {
+	m := map[string][]map[string]interface{}{}
+	if err := jsonConvert(obj, &m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// ListStructToListMapE []struct 逐个转 map，出错时返回出错元素的序号
+func ListStructToListMapE(obj []interface{}) ([]map[string]interface{}, error) {
+	list := make([]map[string]interface{}, 0, len(obj))
+	for i, item := range obj {
+		m, err := IterToMapE(item)
+		if err != nil {
+			return nil, fmt.Errorf("element %d: %w", i, err)
+		}
+		list = append(list, m)
+	}
+	return list, nil
+}
+
+// InToMapE []interface{} 转 map 切片，元素必须为 map[string]interface{}
+func InToMapE(b []interface{}) ([]map[string]interface{}, error) {
+	list := make([]map[string]interface{}, 0, len(b))
+	for i, item := range b {
+		m, ok := item.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("%w: element %d is %T, not map[string]interface{}", ErrUnsupportedType, i, item)
+		}
+		list = append(list, m)
+	}
+	return list, nil
+}
+
+// ToSliceE 切片或数组转 []interface{}
+func ToSliceE(arr interface{}) ([]interface{}, error) {
+	v := indirect(arr)
+	if v.Kind() != reflect.Slice && v.Kind() != reflect.Array {
+		return nil, convertError(arr, "[]interface{}")
+	}
+	ret := make([]interface{}, v.Len())
+	for i := range ret {
+		ret[i] = v.Index(i).Interface()
+	}
+	return ret, nil
+}
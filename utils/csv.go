@@ -0,0 +1,187 @@
+package utils
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// csvOptions CSV 读写选项
+type csvOptions struct {
+	delimiter rune
+	infer     bool
+	bom       bool
+}
+
+// CSVOption CSV 读写选项
+type CSVOption func(*csvOptions)
+
+// WithCSVDelimiter 设置分隔符，默认逗号
+func WithCSVDelimiter(d rune) CSVOption {
+	return func(o *csvOptions) {
+		o.delimiter = d
+	}
+}
+
+// WithCSVTypeInference 读取时推断类型：整数转 int64，小数转 float64，true/false 转 bool，空值转 nil
+func WithCSVTypeInference() CSVOption {
+	return func(o *csvOptions) {
+		o.infer = true
+	}
+}
+
+// WithCSVBOM 写入时添加 UTF-8 BOM，便于 Excel 正确识别中文
+func WithCSVBOM() CSVOption {
+	return func(o *csvOptions) {
+		o.bom = true
+	}
+}
+
+func newCSVOptions(opts []CSVOption) *csvOptions {
+	o := &csvOptions{delimiter: ','}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+const utf8BOM = "\ufeff"
+
+// MapsToCSV 将 map 切片写为 CSV，headers 指定列及顺序，为空时使用全部键并按名称排序。
+// nil 写为空字符串，time.Time 按 TimeFormat 格式化，map 和切片写为 JSON
+func MapsToCSV(data []map[string]interface{}, headers []string, w io.Writer, opts ...CSVOption) error {
+	o := newCSVOptions(opts)
+	if len(headers) == 0 {
+		seen := make(map[string]bool)
+		for _, row := range data {
+			for k := range row {
+				if !seen[k] {
+					seen[k] = true
+					headers = append(headers, k)
+				}
+			}
+		}
+		sort.Strings(headers)
+	}
+	if o.bom {
+		if _, err := io.WriteString(w, utf8BOM); err != nil {
+			return err
+		}
+	}
+	cw := csv.NewWriter(w)
+	cw.Comma = o.delimiter
+	if err := cw.Write(headers); err != nil {
+		return err
+	}
+	record := make([]string, len(headers))
+	for _, row := range data {
+		for i, h := range headers {
+			s, err := csvValue(row[h])
+			if err != nil {
+				return fmt.Errorf("column %s: %w", h, err)
+			}
+			record[i] = s
+		}
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+func csvValue(v interface{}) (string, error) {
+	switch x := v.(type) {
+	case nil:
+		return "", nil
+	case string:
+		return x, nil
+	case time.Time:
+		return x.Format(TimeFormat), nil
+	case float64:
+		return strconv.FormatFloat(x, 'f', -1, 64), nil
+	case float32:
+		return strconv.FormatFloat(float64(x), 'f', -1, 32), nil
+	}
+	if s, err := InterfaceToStrE(v); err == nil {
+		return s, nil
+	}
+	b, err := json.Marshal(v)
+	return string(b), err
+}
+
+// CSVToMaps 读取 CSV，第一行为表头，返回 map 切片。默认所有值为字符串，
+// 使用 WithCSVTypeInference 推断数值和布尔类型。自动去除 UTF-8 BOM，列数少于表头的行缺少的列为 nil
+func CSVToMaps(r io.Reader, opts ...CSVOption) ([]map[string]interface{}, error) {
+	o := newCSVOptions(opts)
+	br := bufio.NewReader(r)
+	if b, err := br.Peek(len(utf8BOM)); err == nil && string(b) == utf8BOM {
+		br.Discard(len(utf8BOM))
+	}
+	cr := csv.NewReader(br)
+	cr.Comma = o.delimiter
+	cr.FieldsPerRecord = -1
+	headers, err := cr.Read()
+	if errors.Is(err, io.EOF) {
+		return []map[string]interface{}{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	headers = append([]string(nil), headers...)
+	rows := []map[string]interface{}{}
+	for {
+		record, err := cr.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return rows, err
+		}
+		row := make(map[string]interface{}, len(headers))
+		for i, h := range headers {
+			if i >= len(record) {
+				row[h] = nil
+				continue
+			}
+			if o.infer {
+				row[h] = inferCSVValue(record[i])
+			} else {
+				row[h] = record[i]
+			}
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+func inferCSVValue(s string) interface{} {
+	t := strings.TrimSpace(s)
+	if t == "" {
+		return nil
+	}
+	if i, err := strconv.ParseInt(t, 10, 64); err == nil {
+		// 以0开头的多位数字（如编号、邮编）保留为字符串
+		if len(t) == 1 || t[0] != '0' && !strings.HasPrefix(t, "-0") {
+			return i
+		}
+		return s
+	}
+	if f, err := strconv.ParseFloat(t, 64); err == nil && !strings.ContainsAny(t, "xXpP") && !strings.EqualFold(t, "nan") && !strings.Contains(strings.ToLower(t), "inf") {
+		return f
+	}
+	switch strings.ToLower(t) {
+	case "true":
+		return true
+	case "false":
+		return false
+	}
+	return s
+}
@@ -0,0 +1,119 @@
+package utils
+
+import (
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// 变更类型
+const (
+	ChangeAdded    = "added"
+	ChangeRemoved  = "removed"
+	ChangeModified = "modified"
+)
+
+// Change DeepDiff 返回的一处变更
+type Change struct {
+	Type string      `json:"type"`
+	Path string      `json:"path"` // 可直接用于 GetPath/SetPath，如 servers[0].addr、labels["app.kubernetes.io/name"]，根节点为空
+	From interface{} `json:"from,omitempty"`
+	To   interface{} `json:"to,omitempty"`
+}
+
+// DeepDiff 比较两个结构（map、切片、结构体等）的差异，比较前按 Bind 相同的 JSON 语义归一化：
+// 结构体按 json 标签转为 map，数字统一为 float64，因此 int(1) 与 float64(1) 视为相同。
+// 切片按下标比较，结果按路径排序
+func DeepDiff(a, b interface{}) ([]Change, error) {
+	na, err := toGeneric(reflect.ValueOf(a), 0)
+	if err != nil {
+		return nil, err
+	}
+	nb, err := toGeneric(reflect.ValueOf(b), 0)
+	if err != nil {
+		return nil, err
+	}
+	var changes []Change
+	diffValue("", na, nb, &changes)
+	return changes, nil
+}
+
+// DeepEqual 按 DeepDiff 的归一化规则判断两个结构是否相同，无法归一化时返回 false
+func DeepEqual(a, b interface{}) bool {
+	changes, err := DeepDiff(a, b)
+	return err == nil && len(changes) == 0
+}
+
+func diffValue(path string, a, b interface{}, changes *[]Change) {
+	switch x := a.(type) {
+	case map[string]interface{}:
+		if y, ok := b.(map[string]interface{}); ok {
+			diffMap(path, x, y, changes)
+			return
+		}
+	case []interface{}:
+		if y, ok := b.([]interface{}); ok {
+			diffSlice(path, x, y, changes)
+			return
+		}
+	}
+	if !reflect.DeepEqual(a, b) {
+		*changes = append(*changes, Change{Type: ChangeModified, Path: path, From: a, To: b})
+	}
+}
+
+func diffMap(path string, a, b map[string]interface{}, changes *[]Change) {
+	keys := make([]string, 0, len(a)+len(b))
+	for k := range a {
+		keys = append(keys, k)
+	}
+	for k := range b {
+		if _, ok := a[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		p := joinKeyPath(path, k)
+		va, inA := a[k]
+		vb, inB := b[k]
+		switch {
+		case !inB:
+			*changes = append(*changes, Change{Type: ChangeRemoved, Path: p, From: va})
+		case !inA:
+			*changes = append(*changes, Change{Type: ChangeAdded, Path: p, To: vb})
+		default:
+			diffValue(p, va, vb, changes)
+		}
+	}
+}
+
+func diffSlice(path string, a, b []interface{}, changes *[]Change) {
+	for i := 0; i < max(len(a), len(b)); i++ {
+		p := path + "[" + strconv.Itoa(i) + "]"
+		switch {
+		case i >= len(b):
+			*changes = append(*changes, Change{Type: ChangeRemoved, Path: p, From: a[i]})
+		case i >= len(a):
+			*changes = append(*changes, Change{Type: ChangeAdded, Path: p, To: b[i]})
+		default:
+			diffValue(p, a[i], b[i], changes)
+		}
+	}
+}
+
+// joinKeyPath 拼接 map 键，包含路径分隔符或通配符的键使用引号形式
+func joinKeyPath(path, key string) string {
+	if key == "" || key == "*" || strings.ContainsAny(key, ".[]$'\"") {
+		quote := `"`
+		if strings.Contains(key, `"`) {
+			quote = "'"
+		}
+		return path + "[" + quote + key + quote + "]"
+	}
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
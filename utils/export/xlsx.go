@@ -0,0 +1,369 @@
+// Package export 将 []map[string]interface{} 形式的报表数据导出为 Excel 文件，
+// 支持列顺序、表头本地化、按列格式化和流式写入，适合数十万行的大数据量导出。
+package export
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"sort"
+	"time"
+
+	"github.com/ixxmi/tools/utils"
+	"github.com/xuri/excelize/v2"
+)
+
+// ErrClosed 写入器已关闭
+var ErrClosed = errors.New("export: writer closed")
+
+// Formatter 单元格格式化函数，返回值作为单元格内容写入
+type Formatter func(v interface{}) interface{}
+
+// Column 导出列
+type Column struct {
+	Key    string    // 数据中的键
+	Title  string    // 表头，为空时先经过 Config.Translate 翻译，再回退为 Key
+	Width  float64   // 列宽（字符数），为0时按表头和数据估算
+	Format Formatter // 单元格格式化，为空时使用 Config.Formatters[Key]
+}
+
+// Config 导出配置
+type Config struct {
+	Sheet      string               // 工作表名称，默认 Sheet1
+	Columns    []Column             // 列及顺序，为空时使用第一行数据的全部键并按名称排序
+	Translate  func(string) string  // 表头本地化，参数为列的 Key，如 func(k string) string { return bundle.T(lang, "report."+k) }
+	Formatters map[string]Formatter // 按 Key 指定的格式化函数
+	DateFormat int                  // time.Time 单元格的数字格式，默认22（yyyy-m-d h:mm）
+	NoFreeze   bool                 // 不冻结表头行
+}
+
+// XLSXWriter 流式写入 xlsx，行数据写入临时文件，Close 时输出到 io.Writer
+type XLSXWriter struct {
+	config    Config
+	out       io.Writer
+	file      *excelize.File
+	sw        *excelize.StreamWriter
+	columns   []Column
+	dateStyle int
+	row       int
+	closed    bool
+}
+
+// NewXLSXWriter 创建流式写入器。Config.Columns 为空时列由第一次 Write 的数据决定
+func NewXLSXWriter(w io.Writer, config Config) (*XLSXWriter, error) {
+	if config.Sheet == "" {
+		config.Sheet = "Sheet1"
+	}
+	if config.DateFormat == 0 {
+		config.DateFormat = 22
+	}
+	f := excelize.NewFile()
+	if config.Sheet != "Sheet1" {
+		if err := f.SetSheetName("Sheet1", config.Sheet); err != nil {
+			f.Close()
+			return nil, fmt.Errorf("export: sheet name: %w", err)
+		}
+	}
+	dateStyle, err := f.NewStyle(&excelize.Style{NumFmt: config.DateFormat})
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	sw, err := f.NewStreamWriter(config.Sheet)
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	x := &XLSXWriter{config: config, out: w, file: f, sw: sw, dateStyle: dateStyle}
+	if len(config.Columns) > 0 {
+		if err := x.writeHeader(config.Columns); err != nil {
+			f.Close()
+			return nil, err
+		}
+	}
+	return x, nil
+}
+
+// writeHeader 设置列宽、冻结表头并写入表头行，流式写入要求这些操作在数据行之前完成
+func (x *XLSXWriter) writeHeader(columns []Column) error {
+	x.columns = make([]Column, len(columns))
+	header := make([]interface{}, len(columns))
+	headerStyle, err := x.file.NewStyle(&excelize.Style{
+		Font:      &excelize.Font{Bold: true},
+		Fill:      excelize.Fill{Type: "pattern", Pattern: 1, Color: []string{"DCE6F1"}},
+		Alignment: &excelize.Alignment{Horizontal: "center", Vertical: "center"},
+	})
+	if err != nil {
+		return err
+	}
+	for i, c := range columns {
+		if c.Title == "" && x.config.Translate != nil {
+			c.Title = x.config.Translate(c.Key)
+		}
+		if c.Title == "" {
+			c.Title = c.Key
+		}
+		if c.Format == nil {
+			c.Format = x.config.Formatters[c.Key]
+		}
+		width := c.Width
+		if width <= 0 {
+			width = min(max(textWidth(c.Title)+2, 8), 60)
+		}
+		if err := x.sw.SetColWidth(i+1, i+1, width); err != nil {
+			return err
+		}
+		x.columns[i] = c
+		header[i] = excelize.Cell{StyleID: headerStyle, Value: c.Title}
+	}
+	if !x.config.NoFreeze {
+		if err := x.sw.SetPanes(&excelize.Panes{Freeze: true, YSplit: 1, TopLeftCell: "A2", ActivePane: "bottomLeft"}); err != nil {
+			return err
+		}
+	}
+	x.row = 1
+	return x.sw.SetRow("A1", header)
+}
+
+// Write 写入一行
+func (x *XLSXWriter) Write(row map[string]interface{}) error {
+	if x.closed {
+		return ErrClosed
+	}
+	if x.columns == nil {
+		if err := x.writeHeader(columnsOf(row)); err != nil {
+			return err
+		}
+	}
+	values := make([]interface{}, len(x.columns))
+	for i, c := range x.columns {
+		v := row[c.Key]
+		if c.Format != nil {
+			v = c.Format(v)
+		}
+		v, err := cellValue(v)
+		if err != nil {
+			return fmt.Errorf("export: column %s: %w", c.Key, err)
+		}
+		if _, ok := v.(time.Time); ok {
+			v = excelize.Cell{StyleID: x.dateStyle, Value: v}
+		}
+		values[i] = v
+	}
+	x.row++
+	cell, err := excelize.CoordinatesToCellName(1, x.row)
+	if err != nil {
+		return err
+	}
+	return x.sw.SetRow(cell, values)
+}
+
+// Rows 已写入的数据行数，不含表头
+func (x *XLSXWriter) Rows() int {
+	return max(x.row-1, 0)
+}
+
+// Close 结束写入并输出文件，释放临时文件。重复调用返回 nil
+func (x *XLSXWriter) Close() error {
+	if x.closed {
+		return nil
+	}
+	x.closed = true
+	defer x.file.Close()
+	if x.columns == nil {
+		if err := x.writeHeader(x.config.Columns); err != nil {
+			return err
+		}
+	}
+	if err := x.sw.Flush(); err != nil {
+		return err
+	}
+	_, err := x.file.WriteTo(x.out)
+	return err
+}
+
+// WriteXLSX 将数据一次性导出到 w。未指定列宽时按前1000行内容估算
+func WriteXLSX(w io.Writer, data []map[string]interface{}, config Config) error {
+	if len(config.Columns) == 0 && len(data) > 0 {
+		config.Columns = columnsOf(data[0])
+	}
+	config.Columns = estimateWidths(config, data)
+	x, err := NewXLSXWriter(w, config)
+	if err != nil {
+		return err
+	}
+	for _, row := range data {
+		if err := x.Write(row); err != nil {
+			x.closed = true
+			x.file.Close()
+			return err
+		}
+	}
+	return x.Close()
+}
+
+// SaveXLSX 将数据导出到文件
+func SaveXLSX(filename string, data []map[string]interface{}, config Config) error {
+	f, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	if err := WriteXLSX(f, data, config); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// TimeStamp 将秒级时间戳格式化为 utils.TimeFormat 格式的字符串，无法转换时保持原值
+func TimeStamp() Formatter {
+	return func(v interface{}) interface{} {
+		if v == nil {
+			return nil
+		}
+		s, err := utils.FromTimeStampE(v)
+		if err != nil {
+			return v
+		}
+		return s
+	}
+}
+
+// TimeLayout 将时间戳或时间字符串按 layout 格式化，无法解析时保持原值
+func TimeLayout(layout string) Formatter {
+	return func(v interface{}) interface{} {
+		if v == nil {
+			return nil
+		}
+		t, err := utils.Convert[time.Time](v)
+		if err != nil {
+			return v
+		}
+		return t.Format(layout)
+	}
+}
+
+// Mapping 按字典转换枚举值（如状态码转中文），未命中时保持原值。键按字符串比较
+func Mapping(dict map[string]string) Formatter {
+	return func(v interface{}) interface{} {
+		if v == nil {
+			return nil
+		}
+		s, err := utils.ToStringE(v)
+		if err != nil {
+			return v
+		}
+		if m, ok := dict[s]; ok {
+			return m
+		}
+		return v
+	}
+}
+
+func columnsOf(row map[string]interface{}) []Column {
+	keys := make([]string, 0, len(row))
+	for k := range row {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	columns := make([]Column, len(keys))
+	for i, k := range keys {
+		columns[i] = Column{Key: k}
+	}
+	return columns
+}
+
+// estimateWidths 为未指定宽度的列按数据估算列宽
+func estimateWidths(config Config, data []map[string]interface{}) []Column {
+	const sampleRows = 1000
+	columns := append([]Column(nil), config.Columns...)
+	for i, c := range columns {
+		if c.Width > 0 {
+			continue
+		}
+		title := c.Title
+		if title == "" && config.Translate != nil {
+			title = config.Translate(c.Key)
+		}
+		if title == "" {
+			title = c.Key
+		}
+		format := c.Format
+		if format == nil {
+			format = config.Formatters[c.Key]
+		}
+		width := textWidth(title)
+		for _, row := range data[:min(len(data), sampleRows)] {
+			v := row[c.Key]
+			if format != nil {
+				v = format(v)
+			}
+			if _, ok := v.(time.Time); ok {
+				width = max(width, 16)
+				continue
+			}
+			if v, err := cellValue(v); err == nil && v != nil {
+				width = max(width, textWidth(fmt.Sprint(v)))
+			}
+		}
+		columns[i].Width = min(max(width+2, 8), 60)
+	}
+	return columns
+}
+
+// cellValue 将值转换为 excelize 可直接写入的类型，map、切片和结构体写为 JSON
+func cellValue(v interface{}) (interface{}, error) {
+	switch x := v.(type) {
+	case nil, string, bool, time.Time, time.Duration, []byte,
+		int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, float32, float64:
+		return v, nil
+	case json.Number:
+		if f, err := x.Float64(); err == nil {
+			return f, nil
+		}
+		return x.String(), nil
+	case fmt.Stringer:
+		return x.String(), nil
+	}
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Ptr {
+		if rv.IsNil() {
+			return nil, nil
+		}
+		rv = rv.Elem()
+	}
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return rv.Int(), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return rv.Uint(), nil
+	case reflect.Float32, reflect.Float64:
+		return rv.Float(), nil
+	case reflect.Bool:
+		return rv.Bool(), nil
+	case reflect.String:
+		return rv.String(), nil
+	case reflect.Struct:
+		if t, ok := rv.Interface().(time.Time); ok {
+			return t, nil
+		}
+	}
+	b, err := json.Marshal(rv.Interface())
+	return string(b), err
+}
+
+// textWidth 估算文本显示宽度，中文按2个字符计算
+func textWidth(s string) float64 {
+	w := 0.0
+	for _, r := range s {
+		if r > 0x2E80 {
+			w += 2
+		} else {
+			w++
+		}
+	}
+	return w
+}
@@ -0,0 +1,360 @@
+package utils
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// writeOptions WriteFileAtomic 选项
+type writeOptions struct {
+	backups int
+}
+
+// WriteOption WriteFileAtomic 选项
+type WriteOption func(*writeOptions)
+
+// WithBackups 覆盖前保留 n 个旧版本，命名为 filename.1（最新）到 filename.n，与 logger.LogRotator 一致
+func WithBackups(n int) WriteOption {
+	return func(o *writeOptions) {
+		o.backups = n
+	}
+}
+
+// WriteFileAtomic 先写入同目录下的临时文件并 fsync，再重命名覆盖目标文件，
+// 进程崩溃或断电时目标文件要么是旧内容要么是新内容，不会出现写了一半的文件
+func WriteFileAtomic(filename string, data []byte, perm os.FileMode, opts ...WriteOption) (err error) {
+	o := &writeOptions{}
+	for _, opt := range opts {
+		opt(o)
+	}
+	dir := filepath.Dir(filename)
+	tmp, err := os.CreateTemp(dir, "."+filepath.Base(filename)+".tmp*")
+	if err != nil {
+		return fmt.Errorf("failed to create temp file for %s: %w", filename, err)
+	}
+	defer func() {
+		if err != nil {
+			tmp.Close()
+			os.Remove(tmp.Name())
+		}
+	}()
+	if _, err = tmp.Write(data); err != nil {
+		return fmt.Errorf("failed to write file %s: %w", filename, err)
+	}
+	if err = tmp.Chmod(perm); err != nil {
+		return fmt.Errorf("failed to chmod file %s: %w", filename, err)
+	}
+	if err = tmp.Sync(); err != nil {
+		return fmt.Errorf("failed to sync file %s: %w", filename, err)
+	}
+	if err = tmp.Close(); err != nil {
+		return fmt.Errorf("failed to close file %s: %w", filename, err)
+	}
+	if o.backups > 0 {
+		if err = backupFile(filename, o.backups); err != nil {
+			return fmt.Errorf("failed to backup file %s: %w", filename, err)
+		}
+	}
+	if err = os.Rename(tmp.Name(), filename); err != nil {
+		return fmt.Errorf("failed to rename file %s: %w", filename, err)
+	}
+	// 目录 fsync 失败时文件内容已经落盘，不影响本次写入结果
+	syncDir(dir)
+	return nil
+}
+
+// backupFile 将 filename.1..n-1 依次后移，再把当前文件保存为 filename.1，文件不存在时不做处理
+func backupFile(filename string, n int) error {
+	if _, err := os.Stat(filename); os.IsNotExist(err) {
+		return nil
+	}
+	for i := n - 1; i > 0; i-- {
+		oldPath := fmt.Sprintf("%s.%d", filename, i)
+		if _, err := os.Stat(oldPath); err == nil {
+			if err := os.Rename(oldPath, fmt.Sprintf("%s.%d", filename, i+1)); err != nil {
+				return err
+			}
+		}
+	}
+	// 硬链接保留旧内容，目标文件在重命名前始终存在；不支持硬链接的文件系统退化为复制
+	backup := filename + ".1"
+	os.Remove(backup)
+	if err := os.Link(filename, backup); err == nil {
+		return nil
+	}
+	return CopyFile(filename, backup)
+}
+
+// ErrStopLines ReadLinesFunc 的回调返回此错误时停止读取，ReadLinesFunc 返回 nil
+var ErrStopLines = errors.New("stop reading lines")
+
+// defaultLineBufferSize LineScanner 默认缓冲区大小
+const defaultLineBufferSize = 64 * 1024
+
+// ReadLinesFunc 逐行读取文件并回调，行内容不含行尾的 \n 或 \r\n，最后一行没有换行符时同样回调。
+// 回调返回错误时停止读取并返回该错误（ErrStopLines 除外）
+func ReadLinesFunc(filename string, fn func(line string) error) error {
+	s, err := NewLineScanner(filename, 0)
+	if err != nil {
+		return err
+	}
+	defer s.Close()
+	for s.Scan() {
+		if err := fn(s.Text()); err != nil {
+			if errors.Is(err, ErrStopLines) {
+				return nil
+			}
+			return err
+		}
+	}
+	return s.Err()
+}
+
+// LineScanner 按行迭代读取，不限制单行长度，用法同 bufio.Scanner：
+//
+//	s, err := utils.NewLineScanner("/var/log/app.log", 1<<20)
+//	defer s.Close()
+//	for s.Scan() {
+//		line := s.Text()
+//	}
+//	err = s.Err()
+type LineScanner struct {
+	r      *bufio.Reader
+	closer io.Closer
+	line   []byte
+	n      int
+	err    error
+}
+
+// NewLineScanner 打开文件创建 LineScanner，bufSize 为读缓冲区大小，小于等于0时为64KB
+func NewLineScanner(filename string, bufSize int) (*LineScanner, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open file %s: %w", filename, err)
+	}
+	s := NewLineScannerReader(f, bufSize)
+	s.closer = f
+	return s, nil
+}
+
+// NewLineScannerReader 从 io.Reader 创建 LineScanner
+func NewLineScannerReader(r io.Reader, bufSize int) *LineScanner {
+	if bufSize <= 0 {
+		bufSize = defaultLineBufferSize
+	}
+	return &LineScanner{r: bufio.NewReaderSize(r, bufSize)}
+}
+
+// Scan 读取下一行，读到末尾或出错时返回 false
+func (s *LineScanner) Scan() bool {
+	if s.err != nil {
+		return false
+	}
+	s.line = s.line[:0]
+	for {
+		chunk, err := s.r.ReadSlice('\n')
+		s.line = append(s.line, chunk...)
+		if err == bufio.ErrBufferFull {
+			continue
+		}
+		if err != nil {
+			s.err = err
+			if err != io.EOF || len(s.line) == 0 {
+				return false
+			}
+		}
+		break
+	}
+	s.n++
+	s.line = bytes.TrimSuffix(s.line, []byte("\n"))
+	s.line = bytes.TrimSuffix(s.line, []byte("\r"))
+	return true
+}
+
+// Text 当前行内容
+func (s *LineScanner) Text() string {
+	return string(s.line)
+}
+
+// Bytes 当前行内容，下次 Scan 时会被覆盖
+func (s *LineScanner) Bytes() []byte {
+	return s.line
+}
+
+// Line 当前行号，从1开始
+func (s *LineScanner) Line() int {
+	return s.n
+}
+
+// Err 返回读取过程中的错误，正常读到末尾时为 nil
+func (s *LineScanner) Err() error {
+	if s.err == io.EOF {
+		return nil
+	}
+	return s.err
+}
+
+// Close 关闭由 NewLineScanner 打开的文件
+func (s *LineScanner) Close() error {
+	if s.closer == nil {
+		return nil
+	}
+	return s.closer.Close()
+}
+
+// FileInfo ListFiles 返回的文件信息
+type FileInfo struct {
+	Path    string // 包含 dir 前缀的完整路径
+	Name    string
+	Size    int64
+	Mode    os.FileMode
+	ModTime time.Time
+}
+
+// ListFiles 列出目录下文件名匹配 pattern 的文件（不含目录），pattern 语法同 filepath.Match，
+// 为空时匹配全部。recursive 为 true 时遍历子目录，结果按路径排序
+func ListFiles(dir string, pattern string, recursive bool) ([]FileInfo, error) {
+	if pattern != "" {
+		if _, err := filepath.Match(pattern, ""); err != nil {
+			return nil, fmt.Errorf("invalid pattern %s: %w", pattern, err)
+		}
+	}
+	var files []FileInfo
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if !recursive && path != dir {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if pattern != "" {
+			if ok, _ := filepath.Match(pattern, d.Name()); !ok {
+				return nil
+			}
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		files = append(files, FileInfo{Path: path, Name: d.Name(), Size: info.Size(), Mode: info.Mode(), ModTime: info.ModTime()})
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list files in %s: %w", dir, err)
+	}
+	return files, nil
+}
+
+// DirSize 统计目录下所有普通文件的总大小，不跟随符号链接
+func DirSize(dir string) (int64, error) {
+	var size int64
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.Type().IsRegular() {
+			info, err := d.Info()
+			if err != nil {
+				return err
+			}
+			size += info.Size()
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to get size of %s: %w", dir, err)
+	}
+	return size, nil
+}
+
+// CopyFile 复制文件，保留权限和修改时间，目标目录不存在时自动创建，目标文件已存在时覆盖
+func CopyFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	info, err := in.Stat()
+	if err != nil {
+		return err
+	}
+	if !info.Mode().IsRegular() {
+		return fmt.Errorf("failed to copy %s: not a regular file", src)
+	}
+	if di, err := os.Stat(dst); err == nil && os.SameFile(info, di) {
+		return fmt.Errorf("failed to copy %s: source and destination are the same file", src)
+	}
+	if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+		return err
+	}
+	out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, info.Mode().Perm())
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return fmt.Errorf("failed to copy %s to %s: %w", src, dst, err)
+	}
+	if err := out.Close(); err != nil {
+		return err
+	}
+	// 目标文件已存在时 OpenFile 不会修改权限
+	if err := os.Chmod(dst, info.Mode().Perm()); err != nil {
+		return err
+	}
+	return os.Chtimes(dst, info.ModTime(), info.ModTime())
+}
+
+// CopyDir 递归复制目录，保留文件权限，符号链接按链接本身复制。dst 已存在时合并，同名文件被覆盖
+func CopyDir(src, dst string) error {
+	info, err := os.Stat(src)
+	if err != nil {
+		return err
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("failed to copy %s: not a directory", src)
+	}
+	if rel, err := filepath.Rel(src, dst); err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return fmt.Errorf("failed to copy %s: destination %s is inside the source directory", src, dst)
+	}
+	return filepath.WalkDir(src, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dst, rel)
+		switch {
+		case d.IsDir():
+			info, err := d.Info()
+			if err != nil {
+				return err
+			}
+			return os.MkdirAll(target, info.Mode().Perm())
+		case d.Type()&fs.ModeSymlink != 0:
+			link, err := os.Readlink(path)
+			if err != nil {
+				return err
+			}
+			os.Remove(target)
+			return os.Symlink(link, target)
+		case d.Type().IsRegular():
+			return CopyFile(path, target)
+		}
+		// 设备文件、管道等跳过
+		return nil
+	})
+}
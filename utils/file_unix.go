@@ -0,0 +1,15 @@
+//go:build !windows
+
+package utils
+
+import "os"
+
+// syncDir fsync 目录，确保重命名操作持久化
+func syncDir(dir string) error {
+	d, err := os.Open(dir)
+	if err != nil {
+		return err
+	}
+	defer d.Close()
+	return d.Sync()
+}
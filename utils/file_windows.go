@@ -0,0 +1,6 @@
+package utils
+
+// syncDir Windows 不支持对目录 fsync，重命名由文件系统保证
+func syncDir(dir string) error {
+	return nil
+}
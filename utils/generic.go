@@ -0,0 +1,176 @@
+package utils
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"time"
+)
+
+// Convert 按目标类型T转换，统一替代 InterfaceToXxx 系列函数：
+//
+//	n, err := utils.Convert[int64]("42")
+//	ok, err := utils.Convert[bool]("true")
+//	t, err := utils.Convert[time.Time]("2024-01-02 03:04:05")
+//
+// 基本数值类型、string、bool 使用对应的 E 版本函数并检查取值范围；
+// time.Time 支持秒级时间戳和 DefaultTimeParser 注册的格式；time.Duration 支持 "1m30s" 和纳秒数值；
+// 其他类型（struct、map、切片等）先尝试直接断言和类型转换，最后通过 JSON 映射
+func Convert[T any](in interface{}) (T, error) {
+	var zero T
+	if v, ok := in.(T); ok {
+		return v, nil
+	}
+	var out interface{}
+	var err error
+	switch any(zero).(type) {
+	case int:
+		out, err = InterfaceToIntE(in)
+	case int8:
+		out, err = convertSigned[int8](in, math.MinInt8, math.MaxInt8)
+	case int16:
+		out, err = convertSigned[int16](in, math.MinInt16, math.MaxInt16)
+	case int32:
+		out, err = InterfaceToInt32E(in)
+	case int64:
+		out, err = InterfaceToInt64E(in)
+	case uint:
+		out, err = convertUnsigned[uint](in, math.MaxUint)
+	case uint8:
+		out, err = convertUnsigned[uint8](in, math.MaxUint8)
+	case uint16:
+		out, err = convertUnsigned[uint16](in, math.MaxUint16)
+	case uint32:
+		out, err = convertUnsigned[uint32](in, math.MaxUint32)
+	case uint64:
+		out, err = convertUnsigned[uint64](in, math.MaxUint64)
+	case float32:
+		var f float64
+		if f, err = InterfaceToFloat64E(in); err == nil {
+			out = float32(f)
+		}
+	case float64:
+		out, err = InterfaceToFloat64E(in)
+	case string:
+		out, err = InterfaceToStrE(in)
+	case bool:
+		out, err = InterfaceToBoolE(in)
+	case []byte:
+		var s string
+		if s, err = InterfaceToStrE(in); err == nil {
+			out = []byte(s)
+		}
+	case []string:
+		out, err = InterToSliceStringE(in)
+	case []interface{}:
+		out, err = ToSliceE(in)
+	case time.Time:
+		out, err = toTimeE(in)
+	case time.Duration:
+		out, err = toDurationE(in)
+	default:
+		return convertReflect[T](in)
+	}
+	if err != nil {
+		return zero, err
+	}
+	return out.(T), nil
+}
+
+// convertSigned 转换为有符号整数并检查范围
+func convertSigned[T int8 | int16](in interface{}, min, max int64) (T, error) {
+	v, err := InterfaceToInt64E(in)
+	if err != nil {
+		return 0, err
+	}
+	if v < min || v > max {
+		return 0, fmt.Errorf("%w: %d overflows %T", ErrOutOfRange, v, T(0))
+	}
+	return T(v), nil
+}
+
+// convertUnsigned 转换为无符号整数并检查范围，负数返回错误
+func convertUnsigned[T uint | uint8 | uint16 | uint32 | uint64](in interface{}, max uint64) (T, error) {
+	var v uint64
+	switch st := indirect(in); st.Kind() {
+	case reflect.Uint, reflect.Uintptr, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		v = st.Uint()
+	case reflect.String:
+		var err error
+		if v, err = strconv.ParseUint(st.String(), 10, 64); err != nil {
+			return 0, err
+		}
+	default:
+		i, err := InterfaceToInt64E(in)
+		if err != nil {
+			return 0, err
+		}
+		if i < 0 {
+			return 0, fmt.Errorf("%w: %d overflows %T", ErrOutOfRange, i, T(0))
+		}
+		v = uint64(i)
+	}
+	if v > max {
+		return 0, fmt.Errorf("%w: %d overflows %T", ErrOutOfRange, v, T(0))
+	}
+	return T(v), nil
+}
+
+// toTimeE 数值按秒级时间戳处理，字符串使用 DefaultTimeParser 解析
+func toTimeE(in interface{}) (time.Time, error) {
+	switch st := indirect(in); st.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		sec, err := InterfaceToInt64E(in)
+		if err != nil {
+			return time.Time{}, err
+		}
+		return time.Unix(sec, 0), nil
+	case reflect.Float32, reflect.Float64:
+		sec, frac := math.Modf(st.Float())
+		return time.Unix(int64(sec), int64(frac*1e9)), nil
+	case reflect.String:
+		return DefaultTimeParser.Parse(st.String())
+	}
+	return time.Time{}, convertError(in, "time.Time")
+}
+
+// toDurationE 字符串按 time.ParseDuration 解析，纯数字字符串和数值按纳秒处理
+func toDurationE(in interface{}) (time.Duration, error) {
+	if st := indirect(in); st.Kind() == reflect.String {
+		if n, err := strconv.ParseInt(st.String(), 10, 64); err == nil {
+			return time.Duration(n), nil
+		}
+		return time.ParseDuration(st.String())
+	}
+	n, err := InterfaceToInt64E(in)
+	if err != nil {
+		return 0, err
+	}
+	return time.Duration(n), nil
+}
+
+// convertReflect 处理其他类型：可直接转换的类型使用 reflect 转换，否则通过 JSON 映射
+func convertReflect[T any](in interface{}) (T, error) {
+	var out T
+	if in == nil {
+		return out, convertError(in, fmt.Sprintf("%T", out))
+	}
+	target := reflect.TypeOf(&out).Elem()
+	v := reflect.ValueOf(in)
+	if target.Kind() != reflect.Interface && v.Type().ConvertibleTo(target) && v.Kind() == target.Kind() {
+		reflect.ValueOf(&out).Elem().Set(v.Convert(target))
+		return out, nil
+	}
+	data, err := json.Marshal(in)
+	if err != nil {
+		return out, err
+	}
+	if err := json.Unmarshal(data, &out); err != nil {
+		var zero T
+		return zero, fmt.Errorf("%w: cannot convert %T to %s: %v", ErrUnsupportedType, in, target, err)
+	}
+	return out, nil
+}
@@ -0,0 +1,151 @@
+package utils
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"math/bits"
+	"sync"
+	"time"
+)
+
+// NanoIDAlphabet NanoID 默认字符集，URL 安全
+const NanoIDAlphabet = "_-0123456789abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ"
+
+// uuidV7State 同一毫秒内生成的 UUIDv7 使用递增序号保证有序
+var uuidV7State struct {
+	sync.Mutex
+	ms  int64
+	seq uint16
+}
+
+// NewUUIDv4 生成随机 UUID（RFC 9562 版本4），如 0b9f1c7e-3c3a-4a8f-9d2e-5b6c7d8e9f00
+func NewUUIDv4() string {
+	var u [16]byte
+	rand.Read(u[:])
+	u[6] = u[6]&0x0f | 0x40
+	u[8] = u[8]&0x3f | 0x80
+	return formatUUID(u)
+}
+
+// NewUUIDv7 生成按时间排序的 UUID（RFC 9562 版本7），前48位为毫秒时间戳，
+// 同一进程内严格递增，适合作为 ClickHouse、MySQL 的排序键或主键
+func NewUUIDv7() string {
+	var u [16]byte
+	rand.Read(u[:])
+	ms := time.Now().UnixMilli()
+
+	s := &uuidV7State
+	s.Lock()
+	if ms <= s.ms {
+		// 同一毫秒或时钟回拨：沿用上次的时间戳并递增序号，序号用尽时借用下一毫秒
+		s.seq++
+		if s.seq > 0x0fff {
+			s.ms++
+			s.seq = 0
+		}
+		ms = s.ms
+	} else {
+		s.ms = ms
+		// 序号从随机值的低半区开始，留出递增空间
+		s.seq = uint16(u[6])<<4&0x07f0 | uint16(u[7]>>4)
+	}
+	seq := s.seq
+	s.Unlock()
+
+	u[0] = byte(ms >> 40)
+	u[1] = byte(ms >> 32)
+	u[2] = byte(ms >> 24)
+	u[3] = byte(ms >> 16)
+	u[4] = byte(ms >> 8)
+	u[5] = byte(ms)
+	u[6] = 0x70 | byte(seq>>8)
+	u[7] = byte(seq)
+	u[8] = u[8]&0x3f | 0x80
+	return formatUUID(u)
+}
+
+// UUIDTime 返回 UUIDv7 中的时间戳
+func UUIDTime(uuid string) (time.Time, error) {
+	u, err := parseUUID(uuid)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if u[6]>>4 != 7 {
+		return time.Time{}, fmt.Errorf("uuid %s is not version 7", uuid)
+	}
+	ms := int64(u[0])<<40 | int64(u[1])<<32 | int64(u[2])<<24 | int64(u[3])<<16 | int64(u[4])<<8 | int64(u[5])
+	return time.UnixMilli(ms), nil
+}
+
+// IsUUID 判断是否为 8-4-4-4-12 格式的 UUID，不区分大小写
+func IsUUID(s string) bool {
+	_, err := parseUUID(s)
+	return err == nil
+}
+
+func formatUUID(u [16]byte) string {
+	var buf [36]byte
+	hex.Encode(buf[0:8], u[0:4])
+	buf[8] = '-'
+	hex.Encode(buf[9:13], u[4:6])
+	buf[13] = '-'
+	hex.Encode(buf[14:18], u[6:8])
+	buf[18] = '-'
+	hex.Encode(buf[19:23], u[8:10])
+	buf[23] = '-'
+	hex.Encode(buf[24:], u[10:])
+	return string(buf[:])
+}
+
+func parseUUID(s string) ([16]byte, error) {
+	var u [16]byte
+	if len(s) != 36 || s[8] != '-' || s[13] != '-' || s[18] != '-' || s[23] != '-' {
+		return u, fmt.Errorf("invalid uuid %s", s)
+	}
+	src := s[0:8] + s[9:13] + s[14:18] + s[19:23] + s[24:]
+	if _, err := hex.Decode(u[:], []byte(src)); err != nil {
+		return u, fmt.Errorf("invalid uuid %s", s)
+	}
+	return u, nil
+}
+
+// NewNanoID 使用默认字符集生成21位短 ID，碰撞概率与 UUIDv4 相当
+func NewNanoID() string {
+	id, _ := NanoID(NanoIDAlphabet, 21)
+	return id
+}
+
+// NanoID 使用指定字符集生成 size 位随机 ID，字符集按字节处理（仅支持 ASCII），长度为 2-256 且不能有重复字符
+func NanoID(alphabet string, size int) (string, error) {
+	n := len(alphabet)
+	if n < 2 || n > 256 {
+		return "", fmt.Errorf("nanoid: alphabet length must be between 2 and 256, got %d", n)
+	}
+	var seen [256]bool
+	for i := 0; i < n; i++ {
+		if seen[alphabet[i]] {
+			return "", fmt.Errorf("nanoid: duplicate character %q in alphabet", alphabet[i])
+		}
+		seen[alphabet[i]] = true
+	}
+	if size <= 0 {
+		return "", fmt.Errorf("nanoid: size must be positive, got %d", size)
+	}
+	// 取覆盖字符集的最小二进制掩码，超出字符集的随机值丢弃，避免取模带来的分布偏差
+	mask := byte(1<<bits.Len(uint(n-1)) - 1)
+	step := max(size*2, 16)
+	id := make([]byte, 0, size)
+	buf := make([]byte, step)
+	for {
+		rand.Read(buf)
+		for _, b := range buf {
+			if idx := int(b & mask); idx < n {
+				id = append(id, alphabet[idx])
+				if len(id) == size {
+					return string(id), nil
+				}
+			}
+		}
+	}
+}
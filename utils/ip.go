@@ -0,0 +1,151 @@
+package utils
+
+import (
+	"fmt"
+	"math/big"
+	"net/netip"
+	"strings"
+)
+
+// maxCIDRHosts CIDRHosts 最多枚举的地址数
+const maxCIDRHosts = 1 << 20
+
+// parseAddr 解析 IP，IPv4 映射的 IPv6 地址（::ffff:1.2.3.4）转为 IPv4
+func parseAddr(ip string) (netip.Addr, error) {
+	addr, err := netip.ParseAddr(strings.TrimSpace(ip))
+	if err != nil {
+		return netip.Addr{}, err
+	}
+	return addr.Unmap(), nil
+}
+
+// NormalizeIP 返回 IP 的规范形式：IPv4 为点分十进制，IPv4 映射的 IPv6 地址转为 IPv4，IPv6 为小写压缩形式
+func NormalizeIP(ip string) (string, error) {
+	addr, err := parseAddr(ip)
+	if err != nil {
+		return "", err
+	}
+	return addr.String(), nil
+}
+
+// ExpandIPv6 返回 IPv6 的完整形式，如 2001:0db8:0000:0000:0000:0000:0000:0001
+func ExpandIPv6(ip string) (string, error) {
+	addr, err := netip.ParseAddr(strings.TrimSpace(ip))
+	if err != nil {
+		return "", err
+	}
+	if !addr.Is6() {
+		return "", fmt.Errorf("%s is not an IPv6 address", ip)
+	}
+	return addr.StringExpanded(), nil
+}
+
+// IsIPv4 是否为 IPv4 地址，IPv4 映射的 IPv6 地址也视为 IPv4
+func IsIPv4(ip string) bool {
+	addr, err := parseAddr(ip)
+	return err == nil && addr.Is4()
+}
+
+// IsIPv6 是否为 IPv6 地址
+func IsIPv6(ip string) bool {
+	addr, err := parseAddr(ip)
+	return err == nil && addr.Is6()
+}
+
+// IsPrivateIP 是否为私有地址（RFC 1918 的 10/8、172.16/12、192.168/16 及 RFC 4193 的 fc00::/7），非法地址返回 false
+func IsPrivateIP(ip string) bool {
+	addr, err := parseAddr(ip)
+	return err == nil && addr.IsPrivate()
+}
+
+// IPInCIDR 判断 IP 是否在网段内，IP 或网段非法时返回 false
+func IPInCIDR(ip, cidr string) bool {
+	addr, err := parseAddr(ip)
+	if err != nil {
+		return false
+	}
+	prefix, err := netip.ParsePrefix(strings.TrimSpace(cidr))
+	if err != nil {
+		return false
+	}
+	if prefix.Addr().Is4In6() {
+		// ::ffff:10.0.0.0/104 等价于 10.0.0.0/8
+		if prefix.Bits() < 96 {
+			return false
+		}
+		prefix = netip.PrefixFrom(prefix.Addr().Unmap(), prefix.Bits()-96)
+	}
+	return prefix.Masked().Contains(addr)
+}
+
+// CIDRHosts 枚举网段内的主机地址。IPv4 掩码小于31时不含网络地址和广播地址，
+// 地址数超过 1048576 时返回错误
+func CIDRHosts(cidr string) ([]string, error) {
+	prefix, err := netip.ParsePrefix(strings.TrimSpace(cidr))
+	if err != nil {
+		return nil, err
+	}
+	prefix = prefix.Masked()
+	hostBits := prefix.Addr().BitLen() - prefix.Bits()
+	if hostBits > 20 {
+		return nil, fmt.Errorf("cidr %s is too large to enumerate", cidr)
+	}
+	n := 1 << hostBits
+	hosts := make([]string, 0, n)
+	addr := prefix.Addr()
+	for i := 0; i < n; i++ {
+		hosts = append(hosts, addr.String())
+		addr = addr.Next()
+	}
+	if prefix.Addr().Is4() && hostBits >= 2 {
+		hosts = hosts[1 : len(hosts)-1]
+	}
+	return hosts, nil
+}
+
+// IPRangeToCIDRs 将起止地址（含两端）转换为最少数量的 CIDR 网段
+func IPRangeToCIDRs(start, end string) ([]string, error) {
+	s, err := parseAddr(start)
+	if err != nil {
+		return nil, err
+	}
+	e, err := parseAddr(end)
+	if err != nil {
+		return nil, err
+	}
+	if s.Is4() != e.Is4() {
+		return nil, fmt.Errorf("ip range %s - %s mixes IPv4 and IPv6", start, end)
+	}
+	if e.Less(s) {
+		return nil, fmt.Errorf("ip range %s - %s: start is greater than end", start, end)
+	}
+	bitLen := s.BitLen()
+	cur, last := addrToInt(s), addrToInt(e)
+	one := big.NewInt(1)
+	var cidrs []string
+	for cur.Cmp(last) <= 0 {
+		// 以 cur 为起点、不超过 last 的最大对齐网段
+		size := 0
+		for size < bitLen && cur.Bit(size) == 0 {
+			blockEnd := new(big.Int).Add(cur, new(big.Int).Lsh(one, uint(size+1)))
+			if blockEnd.Sub(blockEnd, one).Cmp(last) > 0 {
+				break
+			}
+			size++
+		}
+		cidrs = append(cidrs, netip.PrefixFrom(intToAddr(cur, bitLen), bitLen-size).String())
+		cur.Add(cur, new(big.Int).Lsh(one, uint(size)))
+	}
+	return cidrs, nil
+}
+
+func addrToInt(addr netip.Addr) *big.Int {
+	return new(big.Int).SetBytes(addr.AsSlice())
+}
+
+func intToAddr(n *big.Int, bitLen int) netip.Addr {
+	b := make([]byte, bitLen/8)
+	n.FillBytes(b)
+	addr, _ := netip.AddrFromSlice(b)
+	return addr
+}
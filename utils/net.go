@@ -0,0 +1,110 @@
+package utils
+
+import (
+	"encoding/hex"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// MAC 地址输出格式
+const (
+	MACColon = "colon" // aa:bb:cc:dd:ee:ff
+	MACDash  = "dash"  // aa-bb-cc-dd-ee-ff
+	MACNoSep = "nosep" // aabbccddeeff
+	MACDot   = "dot"   // aabb.ccdd.eeff（Cisco 格式）
+)
+
+// parseMAC 解析冒号、短横线、点分及无分隔符形式的 MAC 地址，支持48位和 EUI-64
+func parseMAC(mac string) (net.HardwareAddr, error) {
+	mac = strings.TrimSpace(mac)
+	if !strings.ContainsAny(mac, ":-.") {
+		if len(mac) != 12 && len(mac) != 16 {
+			return nil, fmt.Errorf("invalid MAC address %s", mac)
+		}
+		b, err := hex.DecodeString(mac)
+		if err != nil {
+			return nil, fmt.Errorf("invalid MAC address %s", mac)
+		}
+		return net.HardwareAddr(b), nil
+	}
+	hw, err := net.ParseMAC(mac)
+	if err != nil {
+		return nil, err
+	}
+	if len(hw) != 6 && len(hw) != 8 {
+		return nil, fmt.Errorf("invalid MAC address %s", mac)
+	}
+	return hw, nil
+}
+
+// ValidateMAC 判断是否为合法的 MAC 地址，接受 MACColon、MACDash、MACDot、MACNoSep 格式
+func ValidateMAC(mac string) bool {
+	_, err := parseMAC(mac)
+	return err == nil
+}
+
+// NormalizeMAC 将 MAC 地址转为指定格式的小写形式，format 为空时使用 MACColon
+func NormalizeMAC(mac string, format string) (string, error) {
+	hw, err := parseMAC(mac)
+	if err != nil {
+		return "", err
+	}
+	s := hex.EncodeToString(hw)
+	switch format {
+	case "", MACColon:
+		return hw.String(), nil
+	case MACDash:
+		return strings.ReplaceAll(hw.String(), ":", "-"), nil
+	case MACNoSep:
+		return s, nil
+	case MACDot:
+		groups := make([]string, 0, len(s)/4)
+		for i := 0; i < len(s); i += 4 {
+			groups = append(groups, s[i:i+4])
+		}
+		return strings.Join(groups, "."), nil
+	}
+	return "", fmt.Errorf("unsupported MAC format %s", format)
+}
+
+// ParseHostPort 解析 host:port，没有端口时使用 defaultPort。
+// 支持 [::1]:80、[::1] 及不带方括号的 IPv6 地址（视为没有端口）
+func ParseHostPort(addr string, defaultPort int) (host string, port int, err error) {
+	addr = strings.TrimSpace(addr)
+	if addr == "" {
+		return "", 0, fmt.Errorf("empty address")
+	}
+	host, portStr, err := net.SplitHostPort(addr)
+	if err != nil {
+		// 没有端口：host、[::1]、::1
+		host, portStr = strings.TrimSuffix(strings.TrimPrefix(addr, "["), "]"), ""
+		if strings.ContainsAny(host, "[]") || strings.Contains(host, ":") && net.ParseIP(host) == nil {
+			return "", 0, fmt.Errorf("invalid address %s: %w", addr, err)
+		}
+	}
+	if host == "" {
+		return "", 0, fmt.Errorf("invalid address %s: missing host", addr)
+	}
+	if portStr == "" {
+		port = defaultPort
+	} else if port, err = strconv.Atoi(portStr); err != nil {
+		return "", 0, fmt.Errorf("invalid port in address %s", addr)
+	}
+	if port < 1 || port > 65535 {
+		return "", 0, fmt.Errorf("invalid port %d in address %s", port, addr)
+	}
+	return host, port, nil
+}
+
+// IsPortOpen 尝试在 timeout 内建立 TCP 连接，判断端口是否开放
+func IsPortOpen(host string, port int, timeout time.Duration) bool {
+	conn, err := net.DialTimeout("tcp", net.JoinHostPort(host, strconv.Itoa(port)), timeout)
+	if err != nil {
+		return false
+	}
+	conn.Close()
+	return true
+}
@@ -0,0 +1,50 @@
+package utils
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// Paginate 排序并分页，data 为切片或切片指针（通常是 []map[string]interface{}），不修改原切片。
+// page 从1开始，小于1时按1处理；pageSize 小于等于0时返回全部数据；sortKeys 为空时不排序，
+// 排序规则见 SortSlice。pageData 与 data 的切片类型相同，页码超出范围时为空切片
+func Paginate(data interface{}, page, pageSize int, sortKeys []string, reverse bool) (pageData interface{}, total int, err error) {
+	v := reflect.ValueOf(data)
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return nil, 0, fmt.Errorf("paginate: nil input")
+		}
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Slice {
+		return nil, 0, fmt.Errorf("paginate: input must be a slice, got %s", v.Kind())
+	}
+	total = v.Len()
+	rows := reflect.MakeSlice(v.Type(), total, total)
+	reflect.Copy(rows, v)
+	if len(sortKeys) > 0 {
+		if err := SortSlice(rows.Interface(), sortKeys, reverse); err != nil {
+			return nil, total, err
+		}
+	}
+	if pageSize <= 0 {
+		return rows.Interface(), total, nil
+	}
+	if page < 1 {
+		page = 1
+	}
+	start := (page - 1) * pageSize
+	if start >= total {
+		return reflect.MakeSlice(v.Type(), 0, 0).Interface(), total, nil
+	}
+	end := min(start+pageSize, total)
+	return rows.Slice(start, end).Interface(), total, nil
+}
+
+// PageCount 计算总页数
+func PageCount(total, pageSize int) int {
+	if pageSize <= 0 {
+		return 1
+	}
+	return (total + pageSize - 1) / pageSize
+}
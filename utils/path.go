@@ -0,0 +1,374 @@
+package utils
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// pathSegment 路径中的一段，key 和 index 二选一，wildcard 匹配全部元素
+type pathSegment struct {
+	key      string
+	index    int
+	isIndex  bool
+	wildcard bool
+}
+
+// parsePath 解析 "items[2].meta.name"、`a["x.y"]`、"items[*].name"、"data.*.id" 形式的路径，
+// 开头的 "$" 可省略，负数下标从末尾计数
+func parsePath(path string) ([]pathSegment, error) {
+	path = strings.TrimPrefix(strings.TrimPrefix(path, "$"), ".")
+	var segs []pathSegment
+	for i := 0; i < len(path); {
+		switch path[i] {
+		case '.':
+			i++
+			if i == len(path) || path[i] == '.' || path[i] == '[' {
+				return nil, fmt.Errorf("invalid path %q: empty segment at %d", path, i)
+			}
+		case '[':
+			if i+1 < len(path) && (path[i+1] == '"' || path[i+1] == '\'') {
+				// 引号内可以包含 . 和 ]
+				closing := strings.IndexByte(path[i+2:], path[i+1])
+				if closing < 0 || i+3+closing >= len(path) || path[i+3+closing] != ']' {
+					return nil, fmt.Errorf("invalid path %q: unterminated quoted key", path)
+				}
+				segs = append(segs, pathSegment{key: path[i+2 : i+2+closing]})
+				i += closing + 4
+				continue
+			}
+			end := strings.IndexByte(path[i:], ']')
+			if end < 0 {
+				return nil, fmt.Errorf("invalid path %q: missing ]", path)
+			}
+			inner := strings.TrimSpace(path[i+1 : i+end])
+			if inner == "*" {
+				segs = append(segs, pathSegment{wildcard: true})
+			} else {
+				n, err := strconv.Atoi(inner)
+				if err != nil {
+					return nil, fmt.Errorf("invalid path %q: bad index %q", path, inner)
+				}
+				segs = append(segs, pathSegment{index: n, isIndex: true})
+			}
+			i += end + 1
+		default:
+			end := strings.IndexAny(path[i:], ".[")
+			if end < 0 {
+				end = len(path) - i
+			}
+			key := path[i : i+end]
+			if key == "*" {
+				segs = append(segs, pathSegment{wildcard: true})
+			} else {
+				segs = append(segs, pathSegment{key: key})
+			}
+			i += end
+		}
+	}
+	return segs, nil
+}
+
+// GetPath 按路径读取嵌套数据，支持 map、切片和结构体（按 json 标签或字段名）：
+//
+//	name, ok := utils.GetPath(data, "items[2].meta.name")
+//	ids, ok := utils.GetPath(data, "items[*].id") // 通配符返回 []interface{}
+//
+// 路径包含通配符时返回全部匹配值组成的 []interface{}，没有匹配时 ok 为false
+func GetPath(data interface{}, path string) (interface{}, bool) {
+	segs, err := parsePath(path)
+	if err != nil {
+		return nil, false
+	}
+	wildcard := false
+	for _, s := range segs {
+		wildcard = wildcard || s.wildcard
+	}
+	var out []interface{}
+	collectPath(reflect.ValueOf(data), segs, &out)
+	if len(out) == 0 {
+		return nil, false
+	}
+	if wildcard {
+		return out, true
+	}
+	return out[0], true
+}
+
+// GetPathE 同 GetPath，路径格式错误或不存在时返回错误
+func GetPathE(data interface{}, path string) (interface{}, error) {
+	if _, err := parsePath(path); err != nil {
+		return nil, err
+	}
+	v, ok := GetPath(data, path)
+	if !ok {
+		return nil, fmt.Errorf("path %q not found", path)
+	}
+	return v, nil
+}
+
+func unwrapValue(v reflect.Value) reflect.Value {
+	for v.IsValid() && (v.Kind() == reflect.Interface || v.Kind() == reflect.Ptr) {
+		if v.IsNil() {
+			return reflect.Value{}
+		}
+		v = v.Elem()
+	}
+	return v
+}
+
+func collectPath(v reflect.Value, segs []pathSegment, out *[]interface{}) {
+	if len(segs) == 0 {
+		if v.IsValid() && v.CanInterface() {
+			*out = append(*out, v.Interface())
+		} else if !v.IsValid() {
+			*out = append(*out, nil)
+		}
+		return
+	}
+	v = unwrapValue(v)
+	if !v.IsValid() {
+		return
+	}
+	seg, rest := segs[0], segs[1:]
+	switch {
+	case seg.wildcard:
+		switch v.Kind() {
+		case reflect.Slice, reflect.Array:
+			for i := 0; i < v.Len(); i++ {
+				collectPath(v.Index(i), rest, out)
+			}
+		case reflect.Map:
+			keys := v.MapKeys()
+			sortMapKeys(keys)
+			for _, k := range keys {
+				collectPath(v.MapIndex(k), rest, out)
+			}
+		}
+	case seg.isIndex:
+		if v.Kind() != reflect.Slice && v.Kind() != reflect.Array {
+			return
+		}
+		i := seg.index
+		if i < 0 {
+			i += v.Len()
+		}
+		if i >= 0 && i < v.Len() {
+			collectPath(v.Index(i), rest, out)
+		}
+	default:
+		switch v.Kind() {
+		case reflect.Map:
+			if v.Type().Key().Kind() != reflect.String {
+				return
+			}
+			if e := v.MapIndex(reflect.ValueOf(seg.key).Convert(v.Type().Key())); e.IsValid() {
+				collectPath(e, rest, out)
+			}
+		case reflect.Struct:
+			if idx, ok := sortFieldIndex(v.Type(), seg.key); ok {
+				if f, err := v.FieldByIndexErr(idx); err == nil {
+					collectPath(f, rest, out)
+				}
+			}
+		}
+	}
+}
+
+// sortMapKeys 通配符按键排序遍历 map，保证结果顺序稳定
+func sortMapKeys(keys []reflect.Value) {
+	sort.Slice(keys, func(i, j int) bool {
+		return fmt.Sprint(keys[i].Interface()) < fmt.Sprint(keys[j].Interface())
+	})
+}
+
+// SetPath 按路径写入嵌套数据，中间不存在的 map 节点自动创建（下一段为下标时创建 []interface{}），
+// 下标等于切片长度时追加元素，通配符写入全部匹配元素。data 为 map 或指向 map、切片、结构体的指针
+func SetPath(data interface{}, path string, value interface{}) error {
+	segs, err := parsePath(path)
+	if err != nil {
+		return err
+	}
+	if len(segs) == 0 {
+		return fmt.Errorf("empty path")
+	}
+	root := reflect.ValueOf(data)
+	if root.Kind() == reflect.Ptr {
+		if root.IsNil() {
+			return fmt.Errorf("SetPath: nil pointer")
+		}
+		elem := root.Elem()
+		nv, err := setPath(elem, segs, value, path)
+		if err != nil {
+			return err
+		}
+		elem.Set(nv)
+		return nil
+	}
+	if root.Kind() != reflect.Map {
+		return fmt.Errorf("SetPath: data must be a map or pointer, got %T", data)
+	}
+	_, err = setPath(root, segs, value, path)
+	return err
+}
+
+// setPath 在 v 上写入并返回写入后的节点值（切片追加时会返回新切片）
+func setPath(v reflect.Value, segs []pathSegment, value interface{}, path string) (reflect.Value, error) {
+	if len(segs) == 0 {
+		return assignable(value, v.Type(), path)
+	}
+	// interface 节点按实际值处理，指针节点写入指向的值
+	if v.Kind() == reflect.Interface {
+		inner := v.Elem()
+		if !inner.IsValid() {
+			inner = newContainer(segs[0])
+		} else {
+			inner = copyValue(inner)
+		}
+		nv, err := setPath(inner, segs, value, path)
+		if err != nil {
+			return v, err
+		}
+		out := reflect.New(v.Type()).Elem()
+		out.Set(nv)
+		return out, nil
+	}
+	if v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			v = reflect.New(v.Type().Elem())
+		}
+		nv, err := setPath(v.Elem(), segs, value, path)
+		if err != nil {
+			return v, err
+		}
+		v.Elem().Set(nv)
+		return v, nil
+	}
+
+	seg, rest := segs[0], segs[1:]
+	switch v.Kind() {
+	case reflect.Map:
+		if seg.isIndex {
+			return v, fmt.Errorf("path %q: index on map", path)
+		}
+		if v.Type().Key().Kind() != reflect.String {
+			return v, fmt.Errorf("path %q: map key must be string", path)
+		}
+		if v.IsNil() {
+			v = reflect.MakeMap(v.Type())
+		}
+		set := func(k reflect.Value) error {
+			child := reflect.New(v.Type().Elem()).Elem()
+			if e := v.MapIndex(k); e.IsValid() {
+				child.Set(e)
+			}
+			nv, err := setPath(child, rest, value, path)
+			if err != nil {
+				return err
+			}
+			v.SetMapIndex(k, nv)
+			return nil
+		}
+		if seg.wildcard {
+			for _, k := range v.MapKeys() {
+				if err := set(k); err != nil {
+					return v, err
+				}
+			}
+			return v, nil
+		}
+		return v, set(reflect.ValueOf(seg.key).Convert(v.Type().Key()))
+	case reflect.Slice:
+		if seg.wildcard {
+			for i := 0; i < v.Len(); i++ {
+				nv, err := setPath(v.Index(i), rest, value, path)
+				if err != nil {
+					return v, err
+				}
+				v.Index(i).Set(nv)
+			}
+			return v, nil
+		}
+		if !seg.isIndex {
+			return v, fmt.Errorf("path %q: key %q on slice", path, seg.key)
+		}
+		i := seg.index
+		if i < 0 {
+			i += v.Len()
+		}
+		switch {
+		case i >= 0 && i < v.Len():
+			nv, err := setPath(v.Index(i), rest, value, path)
+			if err != nil {
+				return v, err
+			}
+			v.Index(i).Set(nv)
+			return v, nil
+		case i == v.Len():
+			nv, err := setPath(reflect.New(v.Type().Elem()).Elem(), rest, value, path)
+			if err != nil {
+				return v, err
+			}
+			return reflect.Append(v, nv), nil
+		}
+		return v, fmt.Errorf("path %q: index %d out of range", path, seg.index)
+	case reflect.Struct:
+		if seg.isIndex || seg.wildcard {
+			return v, fmt.Errorf("path %q: index on struct", path)
+		}
+		idx, ok := sortFieldIndex(v.Type(), seg.key)
+		if !ok {
+			return v, fmt.Errorf("path %q: field %q not found", path, seg.key)
+		}
+		out := copyValue(v)
+		f, err := out.FieldByIndexErr(idx)
+		if err != nil {
+			return v, err
+		}
+		nv, err := setPath(f, rest, value, path)
+		if err != nil {
+			return v, err
+		}
+		f.Set(nv)
+		return out, nil
+	}
+	return v, fmt.Errorf("path %q: cannot descend into %s", path, v.Type())
+}
+
+// newContainer 为不存在的中间节点创建容器
+func newContainer(seg pathSegment) reflect.Value {
+	if seg.isIndex {
+		return reflect.ValueOf([]interface{}{})
+	}
+	return reflect.ValueOf(map[string]interface{}{})
+}
+
+// copyValue 返回可修改的副本，map 和切片共享底层数据
+func copyValue(v reflect.Value) reflect.Value {
+	out := reflect.New(v.Type()).Elem()
+	out.Set(v)
+	return out
+}
+
+// assignable 将 value 转换为目标类型
+func assignable(value interface{}, t reflect.Type, path string) (reflect.Value, error) {
+	if value == nil {
+		switch t.Kind() {
+		case reflect.Interface, reflect.Ptr, reflect.Map, reflect.Slice:
+			return reflect.Zero(t), nil
+		}
+		return reflect.Value{}, fmt.Errorf("path %q: cannot assign nil to %s", path, t)
+	}
+	v := reflect.ValueOf(value)
+	if v.Type().AssignableTo(t) {
+		out := reflect.New(t).Elem()
+		out.Set(v)
+		return out, nil
+	}
+	if v.Type().ConvertibleTo(t) && isNumberKind(v.Kind()) == isNumberKind(t.Kind()) {
+		return v.Convert(t), nil
+	}
+	return reflect.Value{}, fmt.Errorf("path %q: cannot assign %T to %s", path, value, t)
+}
@@ -0,0 +1,149 @@
+// Package randx 基于 crypto/rand 的随机数工具，既可生成令牌、盐等安全随机数据，也可用于构造测试数据
+package randx
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"math"
+)
+
+// 常用字符集
+const (
+	CharsetDigits   = "0123456789"
+	CharsetLower    = "abcdefghijklmnopqrstuvwxyz"
+	CharsetUpper    = "ABCDEFGHIJKLMNOPQRSTUVWXYZ"
+	CharsetAlpha    = CharsetLower + CharsetUpper
+	CharsetAlphaNum = CharsetDigits + CharsetAlpha
+	CharsetHex      = "0123456789abcdef"
+)
+
+// Bytes 返回 n 个安全随机字节
+func Bytes(n int) []byte {
+	b := make([]byte, n)
+	rand.Read(b)
+	return b
+}
+
+// SecureToken 生成 n 字节随机数的 URL 安全 base64 编码（无填充），用于会话、重置密码等令牌
+func SecureToken(n int) string {
+	return base64.RawURLEncoding.EncodeToString(Bytes(n))
+}
+
+// SecureTokenHex 生成 n 字节随机数的十六进制编码
+func SecureTokenHex(n int) string {
+	return hex.EncodeToString(Bytes(n))
+}
+
+// Uint64 返回均匀分布的随机 uint64
+func Uint64() uint64 {
+	var b [8]byte
+	rand.Read(b[:])
+	return binary.LittleEndian.Uint64(b[:])
+}
+
+// Uint64n 返回 [0, n) 内均匀分布的随机数，n 为0时返回0
+func Uint64n(n uint64) uint64 {
+	if n == 0 {
+		return 0
+	}
+	if n&(n-1) == 0 {
+		return Uint64() & (n - 1)
+	}
+	// 丢弃超出 n 整数倍的部分，避免取模偏差
+	limit := math.MaxUint64 - math.MaxUint64%n
+	for {
+		if v := Uint64(); v < limit {
+			return v % n
+		}
+	}
+}
+
+// Intn 返回 [0, n) 内的随机整数，n 小于等于0时返回0
+func Intn(n int) int {
+	if n <= 0 {
+		return 0
+	}
+	return int(Uint64n(uint64(n)))
+}
+
+// RandomInt 返回 [min, max] 内的随机整数（包含两端），min 大于 max 时交换
+func RandomInt(min, max int) int {
+	if min > max {
+		min, max = max, min
+	}
+	return min + int(Uint64n(uint64(max-min)+1))
+}
+
+// Float64 返回 [0, 1) 内的随机浮点数
+func Float64() float64 {
+	return float64(Uint64()>>11) / (1 << 53)
+}
+
+// RandomString 从字符集中随机选取 n 个字符，charset 为空时使用 CharsetAlphaNum，支持中文等多字节字符
+func RandomString(n int, charset string) string {
+	if n <= 0 {
+		return ""
+	}
+	if charset == "" {
+		charset = CharsetAlphaNum
+	}
+	runes := []rune(charset)
+	out := make([]rune, n)
+	for i := range out {
+		out[i] = runes[Intn(len(runes))]
+	}
+	return string(out)
+}
+
+// Choice 随机返回一个元素，切片为空时返回零值
+func Choice[T any](items []T) T {
+	var zero T
+	if len(items) == 0 {
+		return zero
+	}
+	return items[Intn(len(items))]
+}
+
+// Shuffle 原地打乱切片
+func Shuffle[T any](items []T) {
+	for i := len(items) - 1; i > 0; i-- {
+		j := Intn(i + 1)
+		items[i], items[j] = items[j], items[i]
+	}
+}
+
+// WeightedChoice 按权重随机选取元素，权重为0的元素不会被选中。
+// items 与 weights 长度不同、存在负数或 NaN 权重、权重之和为0时返回错误
+func WeightedChoice[T any](items []T, weights []float64) (T, error) {
+	var zero T
+	if len(items) != len(weights) {
+		return zero, errors.New("randx: items and weights must have the same length")
+	}
+	total := 0.0
+	for _, w := range weights {
+		if w < 0 || math.IsNaN(w) || math.IsInf(w, 0) {
+			return zero, errors.New("randx: weights must be finite and non-negative")
+		}
+		total += w
+	}
+	if total <= 0 {
+		return zero, errors.New("randx: total weight must be positive")
+	}
+	r := Float64() * total
+	for i, w := range weights {
+		if r < w {
+			return items[i], nil
+		}
+		r -= w
+	}
+	// 浮点误差导致未命中时返回最后一个权重非0的元素
+	for i := len(weights) - 1; i >= 0; i-- {
+		if weights[i] > 0 {
+			return items[i], nil
+		}
+	}
+	return zero, nil
+}
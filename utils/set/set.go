@@ -0,0 +1,147 @@
+// Package set 提供基于 map 的泛型集合及切片集合运算，时间复杂度均为 O(n)
+package set
+
+// Set 无序集合
+type Set[T comparable] map[T]struct{}
+
+// New 创建集合
+func New[T comparable](items ...T) Set[T] {
+	s := make(Set[T], len(items))
+	s.Add(items...)
+	return s
+}
+
+// Add 添加元素
+func (s Set[T]) Add(items ...T) {
+	for _, item := range items {
+		s[item] = struct{}{}
+	}
+}
+
+// Remove 删除元素
+func (s Set[T]) Remove(items ...T) {
+	for _, item := range items {
+		delete(s, item)
+	}
+}
+
+// Contains 判断元素是否存在
+func (s Set[T]) Contains(item T) bool {
+	_, ok := s[item]
+	return ok
+}
+
+// Len 元素个数
+func (s Set[T]) Len() int {
+	return len(s)
+}
+
+// Items 返回全部元素，顺序不固定
+func (s Set[T]) Items() []T {
+	items := make([]T, 0, len(s))
+	for item := range s {
+		items = append(items, item)
+	}
+	return items
+}
+
+// Union 并集
+func (s Set[T]) Union(other Set[T]) Set[T] {
+	out := make(Set[T], len(s)+len(other))
+	for item := range s {
+		out[item] = struct{}{}
+	}
+	for item := range other {
+		out[item] = struct{}{}
+	}
+	return out
+}
+
+// Intersect 交集
+func (s Set[T]) Intersect(other Set[T]) Set[T] {
+	small, large := s, other
+	if len(small) > len(large) {
+		small, large = large, small
+	}
+	out := make(Set[T])
+	for item := range small {
+		if large.Contains(item) {
+			out[item] = struct{}{}
+		}
+	}
+	return out
+}
+
+// Difference 差集，属于 s 但不属于 other 的元素
+func (s Set[T]) Difference(other Set[T]) Set[T] {
+	out := make(Set[T])
+	for item := range s {
+		if !other.Contains(item) {
+			out[item] = struct{}{}
+		}
+	}
+	return out
+}
+
+// Union 合并多个切片并去重，按首次出现的顺序返回
+func Union[T comparable](lists ...[]T) []T {
+	seen := make(map[T]struct{})
+	var out []T
+	for _, list := range lists {
+		for _, item := range list {
+			if _, ok := seen[item]; !ok {
+				seen[item] = struct{}{}
+				out = append(out, item)
+			}
+		}
+	}
+	return out
+}
+
+// Intersect 返回 a 中同时存在于 b 的元素，去重并保持 a 中的顺序
+func Intersect[T comparable](a, b []T) []T {
+	in := New(b...)
+	seen := make(map[T]struct{})
+	var out []T
+	for _, item := range a {
+		if _, ok := seen[item]; ok || !in.Contains(item) {
+			continue
+		}
+		seen[item] = struct{}{}
+		out = append(out, item)
+	}
+	return out
+}
+
+// Difference 返回 a 中不存在于 b 的元素，去重并保持 a 中的顺序
+func Difference[T comparable](a, b []T) []T {
+	exclude := New(b...)
+	var out []T
+	for _, item := range a {
+		if !exclude.Contains(item) {
+			exclude[item] = struct{}{}
+			out = append(out, item)
+		}
+	}
+	return out
+}
+
+// Contains 判断切片是否包含元素
+func Contains[T comparable](list []T, item T) bool {
+	for _, v := range list {
+		if v == item {
+			return true
+		}
+	}
+	return false
+}
+
+// Dedup 去重，不保证顺序
+func Dedup[T comparable](list []T) []T {
+	return New(list...).Items()
+}
+
+// DedupStable 去重，保留首次出现的顺序
+func DedupStable[T comparable](list []T) []T {
+	return Union(list)
+}
@@ -0,0 +1,89 @@
+// Package slices 提供常用的泛型切片操作，替代业务代码中围绕 utils.ToSlice、utils.InToMap 的重复实现
+package slices
+
+// Chunk 按 size 切分切片，最后一组可能不足 size；子切片共享原切片的底层数组
+func Chunk[T any](list []T, size int) [][]T {
+	if size <= 0 {
+		return nil
+	}
+	chunks := make([][]T, 0, (len(list)+size-1)/size)
+	for size < len(list) {
+		list, chunks = list[size:], append(chunks, list[:size:size])
+	}
+	if len(list) > 0 {
+		chunks = append(chunks, list)
+	}
+	return chunks
+}
+
+// Filter 返回满足条件的元素
+func Filter[T any](list []T, keep func(T) bool) []T {
+	out := make([]T, 0, len(list))
+	for _, v := range list {
+		if keep(v) {
+			out = append(out, v)
+		}
+	}
+	return out
+}
+
+// Map 逐个转换元素
+func Map[T, R any](list []T, fn func(T) R) []R {
+	out := make([]R, len(list))
+	for i, v := range list {
+		out[i] = fn(v)
+	}
+	return out
+}
+
+// Reduce 从 initial 开始依次累积元素
+func Reduce[T, R any](list []T, initial R, fn func(acc R, v T) R) R {
+	acc := initial
+	for _, v := range list {
+		acc = fn(acc, v)
+	}
+	return acc
+}
+
+// GroupBy 按键分组，组内保持原顺序
+func GroupBy[T any, K comparable](list []T, key func(T) K) map[K][]T {
+	groups := make(map[K][]T)
+	for _, v := range list {
+		k := key(v)
+		groups[k] = append(groups[k], v)
+	}
+	return groups
+}
+
+// Flatten 展开二维切片
+func Flatten[T any](lists [][]T) []T {
+	n := 0
+	for _, l := range lists {
+		n += len(l)
+	}
+	out := make([]T, 0, n)
+	for _, l := range lists {
+		out = append(out, l...)
+	}
+	return out
+}
+
+// IndexOf 返回元素首次出现的下标，不存在返回 -1
+func IndexOf[T comparable](list []T, item T) int {
+	for i, v := range list {
+		if v == item {
+			return i
+		}
+	}
+	return -1
+}
+
+// IndexFunc 返回首个满足条件的元素下标，不存在返回 -1
+func IndexFunc[T any](list []T, match func(T) bool) int {
+	for i, v := range list {
+		if match(v) {
+			return i
+		}
+	}
+	return -1
+}
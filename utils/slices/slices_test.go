@@ -0,0 +1,137 @@
+package slices
+
+import (
+	"reflect"
+	"strconv"
+	"testing"
+)
+
+func TestChunk(t *testing.T) {
+	tests := []struct {
+		name string
+		list []int
+		size int
+		want [][]int
+	}{
+		{"nil input", nil, 2, [][]int{}},
+		{"empty input", []int{}, 2, [][]int{}},
+		{"zero size", []int{1, 2, 3}, 0, nil},
+		{"negative size", []int{1, 2, 3}, -1, nil},
+		{"exact division", []int{1, 2, 3, 4}, 2, [][]int{{1, 2}, {3, 4}}},
+		{"remainder", []int{1, 2, 3, 4, 5}, 2, [][]int{{1, 2}, {3, 4}, {5}}},
+		{"size larger than length", []int{1, 2}, 5, [][]int{{1, 2}}},
+		{"size one", []int{1, 2, 3}, 1, [][]int{{1}, {2}, {3}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Chunk(tt.list, tt.size)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Chunk(%v, %d) = %v, want %v", tt.list, tt.size, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestChunkAppendDoesNotOverwrite(t *testing.T) {
+	list := []int{1, 2, 3, 4}
+	chunks := Chunk(list, 2)
+	_ = append(chunks[0], 99)
+	if !reflect.DeepEqual(chunks[1], []int{3, 4}) {
+		t.Errorf("appending to a chunk changed the next chunk: %v", chunks[1])
+	}
+}
+
+func TestFilter(t *testing.T) {
+	even := func(v int) bool { return v%2 == 0 }
+	tests := []struct {
+		name string
+		list []int
+		want []int
+	}{
+		{"nil input", nil, []int{}},
+		{"none match", []int{1, 3}, []int{}},
+		{"some match", []int{1, 2, 3, 4}, []int{2, 4}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Filter(tt.list, even); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Filter(%v) = %v, want %v", tt.list, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMap(t *testing.T) {
+	tests := []struct {
+		name string
+		list []int
+		want []string
+	}{
+		{"nil input", nil, []string{}},
+		{"values", []int{1, 2, 3}, []string{"1", "2", "3"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Map(tt.list, strconv.Itoa); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Map(%v) = %v, want %v", tt.list, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReduce(t *testing.T) {
+	sum := func(acc, v int) int { return acc + v }
+	tests := []struct {
+		name    string
+		list    []int
+		initial int
+		want    int
+	}{
+		{"nil input", nil, 10, 10},
+		{"values", []int{1, 2, 3}, 0, 6},
+		{"with initial", []int{1, 2, 3}, 4, 10},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Reduce(tt.list, tt.initial, sum); got != tt.want {
+				t.Errorf("Reduce(%v, %d) = %d, want %d", tt.list, tt.initial, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGroupBy(t *testing.T) {
+	parity := func(v int) string {
+		if v%2 == 0 {
+			return "even"
+		}
+		return "odd"
+	}
+	tests := []struct {
+		name string
+		list []int
+		want map[string][]int
+	}{
+		{"nil input", nil, map[string][]int{}},
+		{"keeps order", []int{1, 2, 3, 4, 5}, map[string][]int{"odd": {1, 3, 5}, "even": {2, 4}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := GroupBy(tt.list, parity); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GroupBy(%v) = %v, want %v", tt.list, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFlattenAndIndex(t *testing.T) {
+	if got := Flatten([][]int{{1}, nil, {2, 3}}); !reflect.DeepEqual(got, []int{1, 2, 3}) {
+		t.Errorf("Flatten = %v", got)
+	}
+	if got := IndexOf([]string{"a", "b"}, "b"); got != 1 {
+		t.Errorf("IndexOf = %d, want 1", got)
+	}
+	if got := IndexFunc([]int{1, 2}, func(v int) bool { return v > 5 }); got != -1 {
+		t.Errorf("IndexFunc = %d, want -1", got)
+	}
+}
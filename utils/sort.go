@@ -0,0 +1,219 @@
+package utils
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// SortSlice 按多个键原地排序切片，data 为切片或切片指针，元素可以是 map[string]T、结构体或结构体指针，
+// 结构体按 json 标签或字段名取值。前一个键相等时比较下一个键，排序稳定，元素类型保持不变。
+// 数值类型（包括不同整数、浮点类型之间）按数值比较，字符串按字典序，time.Time 按时间先后，
+// 缺失和 nil 值排在最前，其他类型按 fmt 格式化后的字符串比较。reverse 为true时倒序
+func SortSlice(data interface{}, keys []string, reverse bool) error {
+	v := reflect.ValueOf(data)
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return fmt.Errorf("sort: nil input")
+		}
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Slice {
+		return fmt.Errorf("sort: input must be a slice, got %s", v.Kind())
+	}
+	if len(keys) == 0 {
+		return fmt.Errorf("sort: no sort key")
+	}
+	n := v.Len()
+	// 预先取出排序键，避免比较时重复反射
+	values := make([][]reflect.Value, n)
+	for i := 0; i < n; i++ {
+		values[i] = make([]reflect.Value, len(keys))
+		for k, key := range keys {
+			values[i][k] = sortValue(v.Index(i), key)
+		}
+	}
+	index := make([]int, n)
+	for i := range index {
+		index[i] = i
+	}
+	sort.SliceStable(index, func(a, b int) bool {
+		for k := range keys {
+			c := compareValues(values[index[a]][k], values[index[b]][k])
+			if c != 0 {
+				if reverse {
+					return c > 0
+				}
+				return c < 0
+			}
+		}
+		return false
+	})
+	// 按排序结果重排原切片
+	sorted := reflect.MakeSlice(v.Type(), n, n)
+	for i, j := range index {
+		sorted.Index(i).Set(v.Index(j))
+	}
+	reflect.Copy(v, sorted)
+	return nil
+}
+
+// sortValue 取元素中的排序键，不存在时返回无效值
+func sortValue(elem reflect.Value, key string) reflect.Value {
+	for elem.Kind() == reflect.Ptr || elem.Kind() == reflect.Interface {
+		if elem.IsNil() {
+			return reflect.Value{}
+		}
+		elem = elem.Elem()
+	}
+	var v reflect.Value
+	switch elem.Kind() {
+	case reflect.Map:
+		if elem.Type().Key().Kind() != reflect.String {
+			return reflect.Value{}
+		}
+		v = elem.MapIndex(reflect.ValueOf(key).Convert(elem.Type().Key()))
+	case reflect.Struct:
+		idx, ok := sortFieldIndex(elem.Type(), key)
+		if !ok {
+			return reflect.Value{}
+		}
+		v, _ = elem.FieldByIndexErr(idx)
+	default:
+		return reflect.Value{}
+	}
+	for v.IsValid() && (v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface) {
+		if v.IsNil() {
+			return reflect.Value{}
+		}
+		v = v.Elem()
+	}
+	return v
+}
+
+var sortFieldCache sync.Map // reflect.Type -> map[string][]int
+
+// sortFieldIndex 按 json 标签或字段名查找字段，包括嵌入结构体的字段，名称不区分大小写
+func sortFieldIndex(t reflect.Type, key string) ([]int, bool) {
+	cached, ok := sortFieldCache.Load(t)
+	if !ok {
+		fields := make(map[string][]int)
+		for _, f := range reflect.VisibleFields(t) {
+			if !f.IsExported() || f.Anonymous {
+				continue
+			}
+			name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+			if name == "" || name == "-" {
+				name = f.Name
+			}
+			if _, dup := fields[name]; !dup {
+				fields[name] = f.Index
+			}
+			if _, dup := fields[f.Name]; !dup {
+				fields[f.Name] = f.Index
+			}
+		}
+		cached, _ = sortFieldCache.LoadOrStore(t, fields)
+	}
+	fields := cached.(map[string][]int)
+	if idx, ok := fields[key]; ok {
+		return idx, true
+	}
+	for name, idx := range fields {
+		if strings.EqualFold(name, key) {
+			return idx, true
+		}
+	}
+	return nil, false
+}
+
+// compareValues 比较两个排序键，返回 -1、0、1
+func compareValues(a, b reflect.Value) int {
+	switch {
+	case !a.IsValid() && !b.IsValid():
+		return 0
+	case !a.IsValid():
+		return -1
+	case !b.IsValid():
+		return 1
+	}
+	if isNumberKind(a.Kind()) && isNumberKind(b.Kind()) {
+		return compareNumbers(a, b)
+	}
+	if a.Kind() == reflect.String && b.Kind() == reflect.String {
+		return strings.Compare(a.String(), b.String())
+	}
+	if a.Kind() == reflect.Bool && b.Kind() == reflect.Bool {
+		switch {
+		case a.Bool() == b.Bool():
+			return 0
+		case !a.Bool():
+			return -1
+		}
+		return 1
+	}
+	if a.Type() == timeType && b.Type() == timeType {
+		return a.Interface().(time.Time).Compare(b.Interface().(time.Time))
+	}
+	return strings.Compare(fmt.Sprint(a.Interface()), fmt.Sprint(b.Interface()))
+}
+
+func isNumberKind(k reflect.Kind) bool {
+	switch k {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+	return false
+}
+
+// compareNumbers 同为整数时精确比较，否则按 float64 比较
+func compareNumbers(a, b reflect.Value) int {
+	ai, aInt := intValue(a)
+	bi, bInt := intValue(b)
+	if aInt && bInt {
+		switch {
+		case ai < bi:
+			return -1
+		case ai > bi:
+			return 1
+		}
+		return 0
+	}
+	af, bf := floatValue(a), floatValue(b)
+	switch {
+	case af < bf:
+		return -1
+	case af > bf:
+		return 1
+	}
+	return 0
+}
+
+func intValue(v reflect.Value) (int64, bool) {
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int(), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		if u := v.Uint(); u <= 1<<63-1 {
+			return int64(u), true
+		}
+	}
+	return 0, false
+}
+
+func floatValue(v reflect.Value) float64 {
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(v.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return float64(v.Uint())
+	}
+	return v.Float()
+}
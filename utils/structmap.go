@@ -0,0 +1,173 @@
+package utils
+
+import (
+	"reflect"
+	"strings"
+	"time"
+)
+
+// structMapOptions StructToMapDeep 选项
+type structMapOptions struct {
+	separator  string
+	timeFormat string
+}
+
+// StructMapOption StructToMapDeep 选项
+type StructMapOption func(*structMapOptions)
+
+// WithFlatten 将嵌套结构体和 map 展开为单层，键用 sep 连接，如 "meta.name"
+func WithFlatten(sep string) StructMapOption {
+	return func(o *structMapOptions) {
+		o.separator = sep
+	}
+}
+
+// WithTimeFormat time.Time 按 layout 格式化为字符串，默认保留 time.Time
+func WithTimeFormat(layout string) StructMapOption {
+	return func(o *structMapOptions) {
+		o.timeFormat = layout
+	}
+}
+
+// StructToMapDeep struct 递归转 map，键名取 tagName 指定的标签（如 json、bson、db），标签为空时使用字段名。
+// 支持 "-" 忽略字段和 omitempty（time.Time 零值也视为空）。嵌入结构体的字段提升到上层，与外层字段同名时外层优先；
+// 嵌入字段指定了标签名时作为嵌套对象，带 inline 选项（bson）的结构体字段同样提升。指针自动解引用，
+// 结构体切片和以结构体为值的 map 逐个转换
+func StructToMapDeep(obj interface{}, tagName string, opts ...StructMapOption) map[string]interface{} {
+	o := &structMapOptions{}
+	for _, opt := range opts {
+		opt(o)
+	}
+	v := unwrapValue(reflect.ValueOf(obj))
+	out := make(map[string]interface{})
+	if !v.IsValid() || v.Kind() != reflect.Struct {
+		return out
+	}
+	structFields(v, tagName, o, out, 0)
+	if o.separator != "" {
+		flat := make(map[string]interface{}, len(out))
+		flattenMap("", out, o.separator, flat)
+		return flat
+	}
+	return out
+}
+
+// structFields 将结构体字段写入 out，嵌入结构体的字段在外层字段之后合并
+func structFields(v reflect.Value, tagName string, o *structMapOptions, out map[string]interface{}, depth int) {
+	if depth > maxBindDepth {
+		return
+	}
+	t := v.Type()
+	promoted := make(map[string]interface{})
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() && !f.Anonymous {
+			continue
+		}
+		tag := f.Tag.Get(tagName)
+		if tag == "-" {
+			continue
+		}
+		name, opts, _ := strings.Cut(tag, ",")
+		omitEmpty, inline := false, false
+		for _, opt := range strings.Split(opts, ",") {
+			switch opt {
+			case "omitempty":
+				omitEmpty = true
+			case "inline":
+				inline = true
+			}
+		}
+		fv := v.Field(i)
+		if f.Anonymous && name == "" || inline {
+			if ev := unwrapValue(fv); ev.IsValid() && ev.Kind() == reflect.Struct && ev.Type() != timeType {
+				structFields(ev, tagName, o, promoted, depth+1)
+				continue
+			}
+			if !f.IsExported() {
+				continue
+			}
+		}
+		if omitEmpty && (isEmptyValue(fv) || fv.Type() == timeType && fv.Interface().(time.Time).IsZero()) {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		out[name] = structMapValue(fv, tagName, o, depth+1)
+	}
+	for k, val := range promoted {
+		if _, ok := out[k]; !ok {
+			out[k] = val
+		}
+	}
+}
+
+// structMapValue 转换字段值：结构体转 map，结构体切片和 map 逐个转换，其他值保持原类型
+func structMapValue(v reflect.Value, tagName string, o *structMapOptions, depth int) interface{} {
+	v = unwrapValue(v)
+	if !v.IsValid() {
+		return nil
+	}
+	if v.Type() == timeType {
+		if o.timeFormat != "" {
+			return v.Interface().(time.Time).Format(o.timeFormat)
+		}
+		return v.Interface()
+	}
+	switch v.Kind() {
+	case reflect.Struct:
+		m := make(map[string]interface{})
+		structFields(v, tagName, o, m, depth)
+		return m
+	case reflect.Slice, reflect.Array:
+		if !containsStruct(v.Type().Elem()) {
+			return v.Interface()
+		}
+		if v.Kind() == reflect.Slice && v.IsNil() {
+			return nil
+		}
+		list := make([]interface{}, v.Len())
+		for i := range list {
+			list[i] = structMapValue(v.Index(i), tagName, o, depth+1)
+		}
+		return list
+	case reflect.Map:
+		if !containsStruct(v.Type().Elem()) || v.Type().Key().Kind() != reflect.String {
+			return v.Interface()
+		}
+		if v.IsNil() {
+			return nil
+		}
+		m := make(map[string]interface{}, v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			m[iter.Key().String()] = structMapValue(iter.Value(), tagName, o, depth+1)
+		}
+		return m
+	}
+	return v.Interface()
+}
+
+// containsStruct 元素类型为结构体、结构体指针或 interface{}（运行时可能为结构体）
+func containsStruct(t reflect.Type) bool {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return t.Kind() == reflect.Interface || t.Kind() == reflect.Struct && t != timeType
+}
+
+// flattenMap 展开嵌套的 map[string]interface{}，切片保持不变
+func flattenMap(prefix string, in map[string]interface{}, sep string, out map[string]interface{}) {
+	for k, v := range in {
+		key := k
+		if prefix != "" {
+			key = prefix + sep + k
+		}
+		if m, ok := v.(map[string]interface{}); ok && len(m) > 0 {
+			flattenMap(key, m, sep, out)
+			continue
+		}
+		out[key] = v
+	}
+}
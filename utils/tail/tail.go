@@ -0,0 +1,315 @@
+// Package tail 以 tail -F 的方式跟踪持续增长的文件，按行通过通道输出，
+// 文件被轮转（重命名后新建，如 logger.LogRotator）或截断后自动切换到新文件继续读取
+package tail
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/ixxmi/tools/logger"
+)
+
+// Line 读取到的一行
+type Line struct {
+	Text   string    // 行内容，不含行尾的 \n 或 \r\n
+	Offset int64     // 该行结束后在文件中的偏移，保存后可作为 Config.Offset 断点续读
+	Time   time.Time // 读取时间
+}
+
+// Config 跟踪配置
+type Config struct {
+	Filename     string
+	Offset       int64         // 起始偏移，大于0时从该位置续读；文件已轮转（当前文件小于 Offset）时先读完最新旧文件的剩余部分
+	FromStart    bool          // 从文件开头读取，默认从末尾开始只输出新增内容
+	PollInterval time.Duration // 检查新内容和轮转的间隔，默认250ms；一个间隔内发生多次轮转时中间的文件会被跳过
+	MaxLineSize  int           // 单行最大字节数，超过时拆分为多行，默认1MB
+	BufferSize   int           // 输出通道缓冲区大小，默认100
+	OnError      func(error)   // 读取出错时回调，出错后按 PollInterval 重试
+	// Rotator 文件由 logger.LogRotator 写入时传入其配置（Filename 可省略），续读时按其命名规则查找最新的旧文件，
+	// 支持按时间命名的 app-<时间>.log 和压缩后的 .gz；为 nil 时按序号命名查找 Filename.1
+	Rotator *logger.RotatorConfig
+}
+
+// Follow 开始跟踪文件，ctx 取消后关闭通道。文件不存在时等待其被创建
+func Follow(ctx context.Context, config Config) (<-chan Line, error) {
+	if config.Filename == "" {
+		return nil, errors.New("tail: filename is required")
+	}
+	if config.PollInterval <= 0 {
+		config.PollInterval = 250 * time.Millisecond
+	}
+	if config.MaxLineSize <= 0 {
+		config.MaxLineSize = 1 << 20
+	}
+	if config.BufferSize <= 0 {
+		config.BufferSize = 100
+	}
+	t := &tailer{config: config, out: make(chan Line, config.BufferSize)}
+	go t.run(ctx)
+	return t.out, nil
+}
+
+type tailer struct {
+	config  Config
+	out     chan Line
+	file    *os.File
+	reader  *bufio.Reader
+	offset  int64
+	partial []byte
+	opened  bool // 是否打开过文件，之后打开的都是轮转后的新文件
+}
+
+func (t *tailer) run(ctx context.Context) {
+	defer close(t.out)
+	defer t.close()
+	if err := t.resume(ctx); err != nil {
+		if ctx.Err() != nil {
+			return
+		}
+		t.reportError(err)
+	}
+	ticker := time.NewTicker(t.config.PollInterval)
+	defer ticker.Stop()
+	for {
+		if t.file == nil {
+			if err := t.open(t.config.Filename, t.startOffset()); err != nil && !os.IsNotExist(err) {
+				t.reportError(err)
+			}
+		}
+		if t.file != nil {
+			if !t.drain(ctx) {
+				return
+			}
+			if err := t.checkRotate(ctx); err != nil {
+				if ctx.Err() != nil {
+					return
+				}
+				t.reportError(err)
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// startOffset 打开文件的读取位置，-1 表示文件末尾；只有首次打开且未设置 FromStart 时从末尾开始
+func (t *tailer) startOffset() int64 {
+	if t.config.FromStart || t.opened {
+		return 0
+	}
+	return -1
+}
+
+// resume 按 Config.Offset 续读。当前文件小于 Offset 说明停止期间发生过轮转，先读完最新旧文件中剩余的部分
+func (t *tailer) resume(ctx context.Context) error {
+	if t.config.Offset <= 0 {
+		return nil
+	}
+	t.config.FromStart = true
+	info, err := os.Stat(t.config.Filename)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	if info.Size() >= t.config.Offset {
+		return t.open(t.config.Filename, t.config.Offset)
+	}
+	backup, err := t.latestBackup()
+	if err != nil {
+		return err
+	}
+	if backup != "" {
+		ok, err := t.openBackup(backup, t.config.Offset)
+		if err != nil {
+			return err
+		}
+		if ok {
+			if !t.drain(ctx) {
+				return ctx.Err()
+			}
+			t.flushPartial(ctx)
+			t.close()
+		}
+	}
+	return t.open(t.config.Filename, 0)
+}
+
+// latestBackup 最新的旧文件，不存在时返回空字符串
+func (t *tailer) latestBackup() (string, error) {
+	if t.config.Rotator == nil {
+		backup := t.config.Filename + ".1"
+		if _, err := os.Stat(backup); err != nil {
+			if os.IsNotExist(err) {
+				return "", nil
+			}
+			return "", err
+		}
+		return backup, nil
+	}
+	config := *t.config.Rotator
+	config.Filename = t.config.Filename
+	files, err := logger.RotatedFiles(config)
+	if err != nil || len(files) == 0 {
+		return "", err
+	}
+	return files[0], nil
+}
+
+// openBackup 打开旧文件并定位到 offset，.gz 文件按解压后的偏移定位。文件小于 offset 时返回 false，说明 Offset 已失效
+func (t *tailer) openBackup(name string, offset int64) (bool, error) {
+	if !strings.HasSuffix(name, ".gz") {
+		info, err := os.Stat(name)
+		if err != nil || info.Size() < offset {
+			return false, err
+		}
+		return true, t.open(name, offset)
+	}
+	f, err := os.Open(name)
+	if err != nil {
+		return false, err
+	}
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		f.Close()
+		return false, fmt.Errorf("tail: open %s: %w", name, err)
+	}
+	if _, err := io.CopyN(io.Discard, gz, offset); err != nil {
+		f.Close()
+		if errors.Is(err, io.EOF) {
+			return false, nil
+		}
+		return false, fmt.Errorf("tail: seek %s: %w", name, err)
+	}
+	t.file, t.offset, t.partial, t.opened = f, offset, nil, true
+	if t.reader == nil {
+		t.reader = bufio.NewReaderSize(gz, min(64*1024, t.config.MaxLineSize))
+	} else {
+		t.reader.Reset(gz)
+	}
+	return true, nil
+}
+
+// open 打开文件并定位，offset 为 -1 时定位到末尾
+func (t *tailer) open(name string, offset int64) error {
+	f, err := os.Open(name)
+	if err != nil {
+		return err
+	}
+	whence := io.SeekStart
+	if offset < 0 {
+		offset, whence = 0, io.SeekEnd
+	}
+	pos, err := f.Seek(offset, whence)
+	if err != nil {
+		f.Close()
+		return fmt.Errorf("tail: seek %s: %w", name, err)
+	}
+	t.file, t.offset, t.partial, t.opened = f, pos, nil, true
+	if t.reader == nil {
+		t.reader = bufio.NewReaderSize(f, min(64*1024, t.config.MaxLineSize))
+	} else {
+		t.reader.Reset(f)
+	}
+	return nil
+}
+
+func (t *tailer) close() {
+	if t.file != nil {
+		t.file.Close()
+		t.file = nil
+	}
+}
+
+// drain 读取到当前文件末尾，未以换行结束的内容保留到下次读取。ctx 取消时返回 false
+func (t *tailer) drain(ctx context.Context) bool {
+	for {
+		chunk, err := t.reader.ReadSlice('\n')
+		t.offset += int64(len(chunk))
+		t.partial = append(t.partial, chunk...)
+		switch {
+		case err == nil:
+			if !t.emit(ctx) {
+				return false
+			}
+		case errors.Is(err, bufio.ErrBufferFull):
+			if len(t.partial) >= t.config.MaxLineSize && !t.emit(ctx) {
+				return false
+			}
+		case errors.Is(err, io.EOF):
+			return true
+		default:
+			t.reportError(err)
+			return true
+		}
+	}
+}
+
+// flushPartial 文件轮转后输出旧文件末尾没有换行符的内容
+func (t *tailer) flushPartial(ctx context.Context) bool {
+	if len(t.partial) == 0 {
+		return true
+	}
+	return t.emit(ctx)
+}
+
+func (t *tailer) emit(ctx context.Context) bool {
+	text := bytes.TrimSuffix(t.partial, []byte("\n"))
+	text = bytes.TrimSuffix(text, []byte("\r"))
+	line := Line{Text: string(text), Offset: t.offset, Time: time.Now()}
+	t.partial = t.partial[:0]
+	select {
+	case t.out <- line:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// checkRotate 检查文件是否被轮转或截断
+func (t *tailer) checkRotate(ctx context.Context) error {
+	info, err := os.Stat(t.config.Filename)
+	if err != nil {
+		if os.IsNotExist(err) {
+			// 已重命名但新文件尚未创建，继续读取旧文件
+			return nil
+		}
+		return err
+	}
+	cur, err := t.file.Stat()
+	if err != nil {
+		return err
+	}
+	if !os.SameFile(info, cur) {
+		// 轮转：读完旧文件剩余内容后切换到新文件开头
+		if !t.drain(ctx) || !t.flushPartial(ctx) {
+			return ctx.Err()
+		}
+		t.close()
+		return t.open(t.config.Filename, 0)
+	}
+	if cur.Size() < t.offset {
+		// 截断（copytruncate）：从头读取
+		t.close()
+		return t.open(t.config.Filename, 0)
+	}
+	return nil
+}
+
+func (t *tailer) reportError(err error) {
+	if t.config.OnError != nil {
+		t.config.OnError(err)
+	}
+}
@@ -0,0 +1,109 @@
+package utils
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// HumanBytes 按1024进制格式化字节数，如 512 B、1.5 KB、3.25 GB，最多保留两位小数
+func HumanBytes(n int64) string {
+	const units = "KMGTPE"
+	abs := math.Abs(float64(n))
+	if abs < 1024 {
+		return strconv.FormatInt(n, 10) + " B"
+	}
+	exp := min(int(math.Log(abs)/math.Log(1024)), len(units))
+	v := float64(n) / math.Pow(1024, float64(exp))
+	if math.Abs(v) >= 1023.995 && exp < len(units) {
+		// 四舍五入后进位到下一个单位，避免输出 1024 KB
+		v /= 1024
+		exp++
+	}
+	s := strconv.FormatFloat(v, 'f', 2, 64)
+	s = strings.TrimRight(strings.TrimRight(s, "0"), ".")
+	return s + " " + units[exp-1:exp] + "B"
+}
+
+// TemplateFuncs RenderTemplate 内置的模板函数：
+//
+//	FromTimeStamp  秒级时间戳按 TimeFormat 格式化，{{ FromTimeStamp .time }}
+//	formatTime     时间戳或时间字符串按指定格式输出，{{ formatTime "01-02 15:04" .time }}
+//	HumanBytes     字节数转为可读形式，{{ HumanBytes .size }}
+//	upper/lower    大小写转换
+//	trim           去除首尾空白
+//	default        值为空时使用默认值，{{ .host | default "unknown" }}
+//	join           切片按分隔符拼接，{{ join .tags "," }}
+//	json           输出 JSON
+func TemplateFuncs() template.FuncMap {
+	return template.FuncMap{
+		"FromTimeStamp": func(v interface{}) string {
+			s, err := FromTimeStampE(v)
+			if err != nil {
+				return fmt.Sprint(v)
+			}
+			return s
+		},
+		"formatTime": func(layout string, v interface{}) string {
+			t, err := Convert[time.Time](v)
+			if err != nil {
+				return fmt.Sprint(v)
+			}
+			return t.Format(layout)
+		},
+		"HumanBytes": func(v interface{}) (string, error) {
+			n, err := InterfaceToInt64E(v)
+			if err != nil {
+				return "", err
+			}
+			return HumanBytes(n), nil
+		},
+		"upper": strings.ToUpper,
+		"lower": strings.ToLower,
+		"trim":  strings.TrimSpace,
+		"default": func(def, v interface{}) interface{} {
+			if rv := indirect(v); !rv.IsValid() || isEmptyValue(rv) {
+				return def
+			}
+			return v
+		},
+		"join": func(v interface{}, sep string) string {
+			rv := indirect(v)
+			if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+				return fmt.Sprint(v)
+			}
+			items := make([]string, rv.Len())
+			for i := range items {
+				items[i] = fmt.Sprint(rv.Index(i).Interface())
+			}
+			return strings.Join(items, sep)
+		},
+		"json": func(v interface{}) (string, error) {
+			b, err := json.Marshal(v)
+			return string(b), err
+		},
+	}
+}
+
+// RenderTemplate 使用 text/template 渲染模板，内置函数见 TemplateFuncs
+func RenderTemplate(tpl string, data interface{}) (string, error) {
+	return RenderTemplateFuncs(tpl, data, nil)
+}
+
+// RenderTemplateFuncs 渲染模板，funcs 中的函数与内置函数同名时覆盖内置函数
+func RenderTemplateFuncs(tpl string, data interface{}, funcs template.FuncMap) (string, error) {
+	t, err := template.New("").Funcs(TemplateFuncs()).Funcs(funcs).Parse(tpl)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse template: %w", err)
+	}
+	var sb strings.Builder
+	if err := t.Execute(&sb, data); err != nil {
+		return "", fmt.Errorf("failed to render template: %w", err)
+	}
+	return sb.String(), nil
+}
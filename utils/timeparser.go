@@ -0,0 +1,209 @@
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// LayoutEpoch 数字时间戳格式，只识别10位秒级和13位毫秒级时间戳，也支持 "1700000000.123" 形式的小数秒；
+// 其他位数的数字（如 20240102）不按时间戳解析
+const LayoutEpoch = "epoch"
+
+// ISO8601 常见变体，小数秒在解析时可选
+const (
+	LayoutISO8601      = "2006-01-02T15:04:05"
+	LayoutISO8601Zone  = "2006-01-02T15:04:05-0700"
+	LayoutDateTimeNano = "2006-01-02 15:04:05.999999999"
+)
+
+var epochPattern = regexp.MustCompile(`^(\d{10}(\.\d+)?|\d{13})$`)
+
+// errUnknownTimeFormat 没有匹配的时间格式
+var errUnknownTimeFormat = errors.New("unknown time format")
+
+// timeFormat 已注册的时间格式，pattern 为空时直接尝试按 layout 解析
+type timeFormat struct {
+	layout  string
+	pattern *regexp.Regexp
+}
+
+// TimeParser 可配置的时间解析器，按注册顺序的逆序尝试各格式（后注册的优先），
+// 不带时区的格式使用解析器的时区
+type TimeParser struct {
+	mu      sync.RWMutex
+	formats []timeFormat
+	loc     *time.Location
+}
+
+// TimeParserOption 解析器选项
+type TimeParserOption func(*TimeParser)
+
+// WithTimeLocation 设置解析器时区，默认本地时区
+func WithTimeLocation(loc *time.Location) TimeParserOption {
+	return func(p *TimeParser) {
+		p.loc = loc
+	}
+}
+
+// WithoutDefaultFormats 不注册内置格式，只使用 RegisterFormat 添加的格式
+func WithoutDefaultFormats() TimeParserOption {
+	return func(p *TimeParser) {
+		p.formats = nil
+	}
+}
+
+// DefaultTimeParser ToTimeStamp 等函数使用的默认解析器
+var DefaultTimeParser = NewTimeParser()
+
+// NewTimeParser 创建解析器，内置格式：TimeFormatRexMap 中的格式、ISO8601（可带小数秒和时区）、RFC3339 和数字时间戳
+func NewTimeParser(opts ...TimeParserOption) *TimeParser {
+	p := &TimeParser{loc: time.Local}
+	for pattern, layout := range TimeFormatRexMap {
+		p.formats = append(p.formats, timeFormat{layout: layout, pattern: regexp.MustCompile(pattern)})
+	}
+	for _, layout := range []string{LayoutDateTimeNano, LayoutISO8601Zone, LayoutISO8601, time.RFC3339Nano, LayoutEpoch} {
+		p.formats = append(p.formats, timeFormat{layout: layout})
+	}
+	for _, opt := range opts {
+		opt(p)
+	}
+	return p
+}
+
+// RegisterFormat 注册时间格式（Go layout），已存在时提升为最高优先级
+func (p *TimeParser) RegisterFormat(layout string) {
+	p.register(timeFormat{layout: layout})
+}
+
+// RegisterPattern 注册带正则预匹配的时间格式，只有匹配 pattern 的字符串才按 layout 解析
+func (p *TimeParser) RegisterPattern(pattern, layout string) error {
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return fmt.Errorf("invalid time pattern %q: %w", pattern, err)
+	}
+	p.register(timeFormat{layout: layout, pattern: re})
+	return nil
+}
+
+func (p *TimeParser) register(f timeFormat) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.remove(f.layout)
+	p.formats = append(p.formats, f)
+}
+
+// UnregisterFormat 移除时间格式，包括内置格式（如 LayoutEpoch），返回是否存在
+func (p *TimeParser) UnregisterFormat(layout string) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.remove(layout)
+}
+
+func (p *TimeParser) remove(layout string) bool {
+	for i, f := range p.formats {
+		if f.layout == layout {
+			p.formats = append(p.formats[:i:i], p.formats[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// Formats 按优先级从高到低返回已注册的格式
+func (p *TimeParser) Formats() []string {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	layouts := make([]string, 0, len(p.formats))
+	for i := len(p.formats) - 1; i >= 0; i-- {
+		layouts = append(layouts, p.formats[i].layout)
+	}
+	return layouts
+}
+
+// SetLocation 设置解析器时区
+func (p *TimeParser) SetLocation(loc *time.Location) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.loc = loc
+}
+
+// Location 返回解析器时区
+func (p *TimeParser) Location() *time.Location {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.loc
+}
+
+// Parse 解析时间字符串
+func (p *TimeParser) Parse(in string) (time.Time, error) {
+	in = strings.TrimSpace(in)
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	for i := len(p.formats) - 1; i >= 0; i-- {
+		f := p.formats[i]
+		if f.pattern != nil && !f.pattern.MatchString(in) {
+			continue
+		}
+		var t time.Time
+		var err error
+		if f.layout == LayoutEpoch {
+			if !epochPattern.MatchString(in) {
+				continue
+			}
+			t, err = parseEpoch(in)
+		} else {
+			t, err = time.ParseInLocation(f.layout, in, p.loc)
+		}
+		if err == nil {
+			return t.In(p.loc), nil
+		}
+		if f.pattern != nil {
+			// 正则已匹配但解析失败，说明取值非法
+			return time.Time{}, err
+		}
+	}
+	return time.Time{}, fmt.Errorf("%w: %q", errUnknownTimeFormat, in)
+}
+
+// ParseUnix 解析时间字符串，返回秒级时间戳
+func (p *TimeParser) ParseUnix(in string) (int64, error) {
+	t, err := p.Parse(in)
+	if err != nil {
+		return 0, err
+	}
+	return t.Unix(), nil
+}
+
+// Format 按解析器时区格式化时间，layout 为空时使用 TimeFormat
+func (p *TimeParser) Format(t time.Time, layout string) string {
+	if layout == "" {
+		layout = TimeFormat
+	}
+	return t.In(p.Location()).Format(layout)
+}
+
+// parseEpoch 解析 epochPattern 匹配的时间戳：10位为秒，13位为毫秒
+func parseEpoch(in string) (time.Time, error) {
+	if strings.Contains(in, ".") {
+		f, err := strconv.ParseFloat(in, 64)
+		if err != nil {
+			return time.Time{}, err
+		}
+		sec, frac := math.Modf(f)
+		return time.Unix(int64(sec), int64(math.Round(frac*1e9))), nil
+	}
+	n, err := strconv.ParseInt(in, 10, 64)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if len(in) == 13 {
+		return time.UnixMilli(n), nil
+	}
+	return time.Unix(n, 0), nil
+}
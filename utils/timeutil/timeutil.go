@@ -0,0 +1,119 @@
+// Package timeutil 提供时间窗口计算和分桶工具，用于生成按任意粒度聚合的监控查询时间范围
+package timeutil
+
+import (
+	"time"
+)
+
+// Window 左闭右开的时间窗口 [Start, End)
+type Window struct {
+	Start time.Time
+	End   time.Time
+}
+
+// Duration 窗口长度
+func (w Window) Duration() time.Duration {
+	return w.End.Sub(w.Start)
+}
+
+// Contains 判断时间是否在窗口内
+func (w Window) Contains(t time.Time) bool {
+	return !t.Before(w.Start) && t.Before(w.End)
+}
+
+// String 按 "2006-01-02 15:04:05 ~ 2006-01-02 15:04:05" 格式输出
+func (w Window) String() string {
+	const layout = "2006-01-02 15:04:05"
+	return w.Start.Format(layout) + " ~ " + w.End.Format(layout)
+}
+
+// StartOfDay t 所在日的0点，使用 t 的时区
+func StartOfDay(t time.Time) time.Time {
+	y, m, d := t.Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, t.Location())
+}
+
+// RangeOfDay t 所在的自然日
+func RangeOfDay(t time.Time) Window {
+	start := StartOfDay(t)
+	return Window{Start: start, End: start.AddDate(0, 0, 1)}
+}
+
+// RangeOfWeek t 所在的自然周，周一为一周的第一天
+func RangeOfWeek(t time.Time) Window {
+	start := StartOfDay(t)
+	offset := (int(start.Weekday()) + 6) % 7
+	start = start.AddDate(0, 0, -offset)
+	return Window{Start: start, End: start.AddDate(0, 0, 7)}
+}
+
+// RangeOfMonth t 所在的自然月
+func RangeOfMonth(t time.Time) Window {
+	y, m, _ := t.Date()
+	start := time.Date(y, m, 1, 0, 0, 0, 0, t.Location())
+	return Window{Start: start, End: start.AddDate(0, 1, 0)}
+}
+
+// Yesterday 昨天
+func Yesterday(now time.Time) Window {
+	return RangeOfDay(now.AddDate(0, 0, -1))
+}
+
+// LastHour 当前小时的上一个完整小时
+func LastHour(now time.Time) Window {
+	end := AlignToInterval(now, time.Hour)
+	return Window{Start: end.Add(-time.Hour), End: end}
+}
+
+// SplitRange 将 [start, end) 按 step 切分为连续窗口，最后一个窗口截止到 end。
+// start 不会自动对齐，需要整点分桶时先调用 AlignToInterval
+func SplitRange(start, end time.Time, step time.Duration) []Window {
+	if step <= 0 || !start.Before(end) {
+		return nil
+	}
+	windows := make([]Window, 0, int(end.Sub(start)/step)+1)
+	for s := start; s.Before(end); s = s.Add(step) {
+		e := s.Add(step)
+		if e.After(end) {
+			e = end
+		}
+		windows = append(windows, Window{Start: s, End: e})
+	}
+	return windows
+}
+
+// SplitRangeDays 按自然日切分，跨夏令时也保持每段从0点开始
+func SplitRangeDays(start, end time.Time, days int) []Window {
+	if days <= 0 || !start.Before(end) {
+		return nil
+	}
+	var windows []Window
+	for s := start; s.Before(end); {
+		e := StartOfDay(s).AddDate(0, 0, days)
+		if e.After(end) {
+			e = end
+		}
+		windows = append(windows, Window{Start: s, End: e})
+		s = e
+	}
+	return windows
+}
+
+// AlignToInterval 将时间向下对齐到 interval 的整数倍。interval 能整除一天时按 t 所在时区的0点对齐
+// （如 +08:00 下按5分钟、1小时对齐到本地整点），否则按 Unix 纪元对齐
+func AlignToInterval(t time.Time, interval time.Duration) time.Time {
+	if interval <= 0 {
+		return t
+	}
+	const day = 24 * time.Hour
+	if interval <= day && day%interval == 0 {
+		start := StartOfDay(t)
+		return start.Add(t.Sub(start) / interval * interval)
+	}
+	return time.Unix(0, t.UnixNano()/int64(interval)*int64(interval)).In(t.Location())
+}
+
+// Buckets 生成覆盖 [start, end) 的对齐分桶，首个窗口从 start 对齐后的时间开始
+func Buckets(start, end time.Time, interval time.Duration) []Window {
+	return SplitRange(AlignToInterval(start, interval), end, interval)
+}
@@ -13,7 +13,6 @@ import (
 	"os"
 	"reflect"
 	"regexp"
-	"sort"
 	"strconv"
 	"strings"
 	"time"
@@ -28,7 +27,7 @@ var TimeFormatRexMap = map[string]string{
 	`^\d{4}/\d{2}/\d{2}T\d{2}:\d{2}:\d{2}[+|-]\d{4}$`:    "2006/01/02T15:04:05-0700",
 	`^\d{4}/\d{2}/\d{2}\s\d{2}:\d{2}:\d{2}$`:             "2006/01/02 15:04:05",
 	`^\d{4}/\d{2}/\d{2}T\d{2}:\d{2}:\d{2}$`:              "2006/01/02T15:04:05",
-} // 字符串转时间戳匹配模式
+} // 字符串转时间戳匹配模式，用于初始化 NewTimeParser 的内置格式，新增格式请使用 TimeParser.RegisterPattern
 
 var (
 	// int 强制转换
@@ -65,24 +64,16 @@ func InterfaceToInt64(x interface{}) int64 {
 	return 0
 }
 
-// 根据指定时间格式返回时间戳
+// 根据 DefaultTimeParser 注册的时间格式返回时间戳，无法解析时返回0，没有匹配的格式（包括空字符串）时不输出错误
 func ToTimeStamp(in string) int64 {
-	var timeFormat = ""
-	for r, v := range TimeFormatRexMap {
-		if matched, _ := regexp.Match(r, []byte(in)); matched {
-			timeFormat = v
-			break
+	ret, err := DefaultTimeParser.ParseUnix(in)
+	if err != nil {
+		if !errors.Is(err, errUnknownTimeFormat) {
+			fmt.Println(err)
 		}
-	}
-	if timeFormat == "" {
-
 		return 0
 	}
-	ret, err := time.ParseInLocation(timeFormat, in, time.Local)
-	if err != nil {
-		fmt.Println(err)
-	}
-	return ret.Unix()
+	return ret
 }
 
 func FromTimeStamp(in interface{}) string {
@@ -120,12 +111,16 @@ func Pretty(data interface{}) string {
 	return string(_data)
 }
 
-// data 转换成ret
+// data 转换成ret，按 JSON 规则映射（字段名取 json 标签、数值写入 interface{} 时为 float64），
+// 通过反射直接映射，不生成中间 JSON 字符串
 func Bind(data interface{}, ret interface{}) error {
 	v := reflect.ValueOf(ret)
 	if v.Kind() != reflect.Ptr {
 		return fmt.Errorf("ptr input ret needed as type as input type %s", v.Kind())
 	}
+	if v.IsNil() {
+		return fmt.Errorf("ptr input ret is nil")
+	}
 	havdata := false
 	var bk interface{}
 	if v.Elem().Kind() == reflect.Slice {
@@ -134,10 +129,8 @@ func Bind(data interface{}, ret interface{}) error {
 		v.Elem().Set(t)
 		havdata = true
 	}
-	_data, _ := json.MarshalIndent(data, "", "    ")
-	err := json.Unmarshal(_data, ret)
+	err := bindValue(v.Elem(), reflect.ValueOf(data), 0)
 	if err != nil {
-		fmt.Println(err)
 		if havdata {
 			v.Elem().Set(reflect.ValueOf(bk))
 		}
@@ -536,7 +529,7 @@ func (a SortBy) Less(i, j int) bool {
 	}
 }
 
-// 根据指定字符排序
+// 根据指定字符原地排序，保留元素原有类型，见 SortSlice
 //
 //	m := []map[string]int{
 //		{"k": 2},
@@ -548,24 +541,9 @@ func (a SortBy) Less(i, j int) bool {
 // ture  倒序3, 2, 1
 // fmt.Println(m)
 func SortData(data interface{}, sortkey string, reverse bool) {
-	//func SortData(data interface{}, sortkey string, reverse bool) {
-	var db []map[string]interface{}
-	err := Bind(data, &db)
-	if err != nil {
-		fmt.Println(err)
-		return
-	}
-	stb := SortBy{db, sortkey}
-	if !reverse {
-		sort.Sort(stb)
-	} else {
-		sort.Sort(sort.Reverse(stb))
-	}
-	err = Bind(stb.Data, data)
-	if err != nil {
+	if err := SortSlice(data, []string{sortkey}, reverse); err != nil {
 		fmt.Println(err)
 	}
-
 }
 
 const ( // 格式化基本时间范式 固定时间不可更改
@@ -625,7 +603,7 @@ func FileOpenRead(filename string) (contents []uint8, err error) {
 	//return contents, err
 }
 
-// readlines  读取全部行 返回列表
+// Readlines 读取全部行 返回列表，每行保留换行符。大文件请使用 ReadLinesFunc 或 LineScanner
 func Readlines(filename string) (readlineslist []string, err error) {
 	f, err := os.Open(filename)
 	if err != nil {
@@ -637,10 +615,15 @@ func Readlines(filename string) (readlineslist []string, err error) {
 	rf := bufio.NewReader(f)
 	for {
 		b, err := rf.ReadBytes('\n')
-		readlineslist = append(readlineslist, string(b))
+		if len(b) > 0 {
+			readlineslist = append(readlineslist, string(b))
+		}
 		if err == io.EOF {
 			break
 		}
+		if err != nil {
+			return readlineslist, err
+		}
 	}
 	return readlineslist, nil
 }
@@ -784,12 +767,16 @@ func Formattimezone() string {
 }
 
 // 对两个切片字符串去重并合并
+//
+// Deprecated: 时间复杂度 O(n²)，使用 set.Union
 func SetListStringadd(list1, list2 []string) (listAdd []string) {
 	listAdd = RemoveRepeatedElement(append(list1, list2...))
 	return
 }
 
 // 对两个切片字符串去重并相减
+//
+// Deprecated: 时间复杂度 O(n²)，使用 set.Difference
 func SetListString(list1, list2 []string) []string {
 	obj := RemoveRepeatedElement(list1)
 	list1 = RemoveRepeatedElement(list1)
@@ -810,6 +797,8 @@ func SetListString(list1, list2 []string) []string {
 }
 
 // 去重列表内容
+//
+// Deprecated: 时间复杂度 O(n²)，使用 set.Dedup 或 set.DedupStable
 func RemoveRepeatedElement(arr []string) (newArr []string) {
 	newArr = make([]string, 0)
 	for i := 0; i < len(arr); i++ {
@@ -828,6 +817,8 @@ func RemoveRepeatedElement(arr []string) (newArr []string) {
 }
 
 // 去重列表内容
+//
+// Deprecated: 时间复杂度 O(n²)，使用 set.DedupStable
 func RemoveRepeatedElementList(arr []interface{}) (newArr []interface{}) {
 	newArr = make([]interface{}, 0)
 	for i := 0; i < len(arr); i++ {
@@ -1035,21 +1026,10 @@ func (a SortByEx) Less(i, j int) bool {
 		return fmt.Sprintf("%v", w) < fmt.Sprintf("%v", v)
 	}
 }
+
+// 根据多个键排序，前一个键相等时比较下一个键，见 SortSlice
 func SortDataEx(data interface{}, sortkey []string, reverse bool) {
-	var db []map[string]interface{}
-	err := Bind(data, &db)
-	if err != nil {
-		fmt.Println(3, "sortdata error", err)
-		return
-	}
-	stb := SortByEx{db, sortkey}
-	if !reverse {
-		sort.Sort(stb)
-	} else {
-		sort.Sort(sort.Reverse(stb))
-	}
-	err = Bind(stb.Data, data)
-	if err != nil {
+	if err := SortSlice(data, sortkey, reverse); err != nil {
 		fmt.Println(3, "sortdata error", err)
 	}
 }
@@ -1155,12 +1135,7 @@ func SliceToStr(v interface{}) string {
 }
 
 func CheckIp(ipAddr string) bool {
-	ip := net.ParseIP(ipAddr)
-	if ip == nil {
-		fmt.Println("Invalid IP address")
-		return false
-	}
-	return true
+	return net.ParseIP(ipAddr) != nil
 }
 
 // ReadFile 读取文件内容
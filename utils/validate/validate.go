@@ -0,0 +1,454 @@
+// Package validate 根据结构体的 validate 标签校验字段，返回结构化的字段错误。
+//
+// 标签中多个规则以逗号分隔，如 `validate:"required,min=3,max=32"`，内置规则：
+//
+//	required    非零值，切片、map 长度大于0，指针不为 nil
+//	omitempty   值为空时跳过其余规则
+//	min=N       数值不小于 N；字符串、切片、map 的长度（字符串按字符计）不小于 N
+//	max=N       同 min，不大于 N
+//	len=N       长度等于 N
+//	oneof=a b   值为空格分隔的候选之一
+//	email       邮箱格式，见 utils.VerifyEmailFormat
+//	ip          IPv4 或 IPv6 地址，见 utils.CheckIp
+//	regexp=pat  匹配正则，参数可以包含逗号，因此必须是最后一个规则
+//
+// 嵌套结构体、结构体指针及其切片会递归校验，字段路径使用 json 标签名，如 servers[0].addr。
+package validate
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode/utf8"
+
+	"github.com/ixxmi/tools/utils"
+)
+
+// ErrInvalidValue Struct 的参数不是结构体或结构体指针
+var ErrInvalidValue = errors.New("validate: value must be a struct or a pointer to struct")
+
+// Func 校验函数，v 为解引用后的字段值，param 为规则参数（= 之后的部分），返回 false 表示校验失败
+type Func func(v reflect.Value, param string) bool
+
+// FieldError 单个字段的校验错误
+type FieldError struct {
+	Field string      // 字段路径，如 servers[0].addr
+	Rule  string      // 失败的规则名
+	Param string      // 规则参数
+	Value interface{} // 字段值
+}
+
+func (e *FieldError) Error() string {
+	msg := "failed on " + e.Rule
+	if e.Param != "" {
+		msg += "=" + e.Param
+	}
+	if e.Field == "" {
+		return msg
+	}
+	return e.Field + ": " + msg
+}
+
+// Errors 校验错误列表，按字段顺序排列
+type Errors []*FieldError
+
+func (e Errors) Error() string {
+	msgs := make([]string, len(e))
+	for i, fe := range e {
+		msgs[i] = fe.Error()
+	}
+	return "validate: " + strings.Join(msgs, "; ")
+}
+
+// Fields 返回字段路径到失败规则的映射，便于直接作为接口响应
+func (e Errors) Fields() map[string]string {
+	m := make(map[string]string, len(e))
+	for _, fe := range e {
+		m[fe.Field] = fe.Rule
+	}
+	return m
+}
+
+// Option 校验器选项
+type Option func(*Validator)
+
+// WithTagName 使用其他标签名，默认 validate
+func WithTagName(name string) Option {
+	return func(v *Validator) {
+		v.tagName = name
+	}
+}
+
+// WithStrict 遇到未注册的规则时返回错误。默认忽略未知规则，兼容仅把字段名写在 validate 标签里的旧结构体
+func WithStrict() Option {
+	return func(v *Validator) {
+		v.strict = true
+	}
+}
+
+// Validator 结构体校验器，可并发使用
+type Validator struct {
+	tagName string
+	strict  bool
+	mu      sync.RWMutex
+	rules   map[string]Func
+	fields  sync.Map // reflect.Type -> []fieldRules
+	regexps sync.Map // pattern -> *regexp.Regexp
+}
+
+// rule 解析后的单个规则
+type rule struct {
+	name  string
+	param string
+}
+
+// fieldRules 结构体字段及其规则
+type fieldRules struct {
+	index     int
+	name      string
+	rules     []rule
+	omitempty bool
+}
+
+// New 创建校验器，内置规则已注册
+func New(opts ...Option) *Validator {
+	v := &Validator{tagName: "validate", rules: make(map[string]Func)}
+	for _, opt := range opts {
+		opt(v)
+	}
+	v.rules["required"] = func(f reflect.Value, _ string) bool { return !isEmpty(f) }
+	v.rules["min"] = func(f reflect.Value, p string) bool { return compare(f, p, func(a, b float64) bool { return a >= b }) }
+	v.rules["max"] = func(f reflect.Value, p string) bool { return compare(f, p, func(a, b float64) bool { return a <= b }) }
+	v.rules["len"] = func(f reflect.Value, p string) bool { return compareLen(f, p) }
+	v.rules["oneof"] = oneOf
+	v.rules["email"] = func(f reflect.Value, _ string) bool {
+		return f.Kind() == reflect.String && utils.VerifyEmailFormat(f.String())
+	}
+	v.rules["ip"] = func(f reflect.Value, _ string) bool {
+		return f.Kind() == reflect.String && utils.CheckIp(f.String())
+	}
+	v.rules["regexp"] = v.matchRegexp
+	return v
+}
+
+// Register 注册自定义规则，同名规则会被覆盖
+func (v *Validator) Register(name string, fn Func) error {
+	if name == "" || strings.ContainsAny(name, ",=") || name == "omitempty" {
+		return fmt.Errorf("validate: invalid rule name %q", name)
+	}
+	if fn == nil {
+		return fmt.Errorf("validate: rule %s: nil func", name)
+	}
+	v.mu.Lock()
+	v.rules[name] = fn
+	v.mu.Unlock()
+	return nil
+}
+
+// Struct 校验结构体，校验失败返回 Errors；严格模式下遇到未知规则返回普通 error
+func (v *Validator) Struct(s interface{}) error {
+	rv := reflect.ValueOf(s)
+	for rv.Kind() == reflect.Ptr || rv.Kind() == reflect.Interface {
+		if rv.IsNil() {
+			return ErrInvalidValue
+		}
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		return ErrInvalidValue
+	}
+	var errs Errors
+	if err := v.validateStruct(rv, "", &errs); err != nil {
+		return err
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// Var 按标签语法校验单个值，字段路径为空
+func (v *Validator) Var(value interface{}, tag string) error {
+	f := fieldRules{rules: parseTag(tag)}
+	f.omitempty = stripOmitempty(&f.rules)
+	var errs Errors
+	if err := v.validateField(reflect.ValueOf(value), f, "", &errs); err != nil {
+		return err
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+func (v *Validator) validateStruct(rv reflect.Value, prefix string, errs *Errors) error {
+	for _, f := range v.structFields(rv.Type()) {
+		if err := v.validateField(rv.Field(f.index), f, join(prefix, f.name), errs); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (v *Validator) validateField(fv reflect.Value, f fieldRules, path string, errs *Errors) error {
+	empty := isEmpty(fv)
+	if !(empty && f.omitempty) {
+		elem := fv
+		for elem.IsValid() && (elem.Kind() == reflect.Ptr || elem.Kind() == reflect.Interface) && !elem.IsNil() {
+			elem = elem.Elem()
+		}
+		for _, r := range f.rules {
+			v.mu.RLock()
+			fn, ok := v.rules[r.name]
+			v.mu.RUnlock()
+			if !ok {
+				if v.strict {
+					return fmt.Errorf("validate: %s: unknown rule %s", path, r.name)
+				}
+				continue
+			}
+			// 非 required 规则不校验 nil 指针，需要非空时组合 required 使用
+			if r.name != "required" && (!elem.IsValid() || (elem.Kind() == reflect.Ptr || elem.Kind() == reflect.Interface) && elem.IsNil()) {
+				continue
+			}
+			if !fn(elem, r.param) {
+				var value interface{}
+				if elem.IsValid() && elem.CanInterface() {
+					value = elem.Interface()
+				}
+				*errs = append(*errs, &FieldError{Field: path, Rule: r.name, Param: r.param, Value: value})
+				break
+			}
+		}
+	}
+	return v.dive(fv, path, errs)
+}
+
+// dive 递归校验嵌套的结构体及结构体切片、map
+func (v *Validator) dive(fv reflect.Value, path string, errs *Errors) error {
+	for fv.Kind() == reflect.Ptr || fv.Kind() == reflect.Interface {
+		if fv.IsNil() {
+			return nil
+		}
+		fv = fv.Elem()
+	}
+	switch fv.Kind() {
+	case reflect.Struct:
+		if hasNested(fv.Type()) {
+			return v.validateStruct(fv, path, errs)
+		}
+	case reflect.Slice, reflect.Array:
+		if !hasNested(fv.Type().Elem()) {
+			return nil
+		}
+		for i := 0; i < fv.Len(); i++ {
+			if err := v.dive(fv.Index(i), fmt.Sprintf("%s[%d]", path, i), errs); err != nil {
+				return err
+			}
+		}
+	case reflect.Map:
+		if !hasNested(fv.Type().Elem()) {
+			return nil
+		}
+		iter := fv.MapRange()
+		for iter.Next() {
+			if err := v.dive(iter.Value(), fmt.Sprintf("%s[%v]", path, iter.Key()), errs); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// hasNested 类型（或其元素）是否为可能带有校验规则的结构体
+func hasNested(t reflect.Type) bool {
+	for t.Kind() == reflect.Ptr || t.Kind() == reflect.Slice || t.Kind() == reflect.Array || t.Kind() == reflect.Map {
+		t = t.Elem()
+	}
+	return t.Kind() == reflect.Struct && t.PkgPath() != "time"
+}
+
+// structFields 解析并缓存结构体字段规则，包含没有标签但需要递归校验的字段
+func (v *Validator) structFields(t reflect.Type) []fieldRules {
+	if cached, ok := v.fields.Load(t); ok {
+		return cached.([]fieldRules)
+	}
+	var fields []fieldRules
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		if !sf.IsExported() {
+			continue
+		}
+		tag := sf.Tag.Get(v.tagName)
+		if tag == "-" || tag == "" && !hasNested(sf.Type) {
+			continue
+		}
+		f := fieldRules{index: i, name: fieldName(sf), rules: parseTag(tag)}
+		f.omitempty = stripOmitempty(&f.rules)
+		fields = append(fields, f)
+	}
+	v.fields.Store(t, fields)
+	return fields
+}
+
+// fieldName 字段路径中的名称，没有 json 名称的匿名嵌入字段返回空，其字段提升到外层路径
+func fieldName(sf reflect.StructField) string {
+	if name, _, _ := strings.Cut(sf.Tag.Get("json"), ","); name != "" && name != "-" {
+		return name
+	}
+	if sf.Anonymous {
+		return ""
+	}
+	return sf.Name
+}
+
+func join(prefix, name string) string {
+	if prefix == "" || name == "" {
+		return prefix + name
+	}
+	return prefix + "." + name
+}
+
+// parseTag 解析规则列表，regexp 的参数取到标签末尾
+func parseTag(tag string) []rule {
+	var rules []rule
+	for tag != "" {
+		part := tag
+		if strings.HasPrefix(tag, "regexp=") {
+			tag = ""
+		} else if i := strings.IndexByte(tag, ','); i >= 0 {
+			part, tag = tag[:i], tag[i+1:]
+		} else {
+			tag = ""
+		}
+		name, param, _ := strings.Cut(strings.TrimSpace(part), "=")
+		if name != "" {
+			rules = append(rules, rule{name: name, param: param})
+		}
+	}
+	return rules
+}
+
+func stripOmitempty(rules *[]rule) bool {
+	found := false
+	out := (*rules)[:0]
+	for _, r := range *rules {
+		if r.name == "omitempty" {
+			found = true
+			continue
+		}
+		out = append(out, r)
+	}
+	*rules = out
+	return found
+}
+
+func isEmpty(v reflect.Value) bool {
+	if !v.IsValid() {
+		return true
+	}
+	switch v.Kind() {
+	case reflect.Slice, reflect.Map, reflect.String, reflect.Array:
+		return v.Len() == 0
+	case reflect.Ptr, reflect.Interface:
+		return v.IsNil()
+	}
+	return v.IsZero()
+}
+
+// size 数值返回值本身，字符串、切片、map 返回长度
+func size(v reflect.Value) (float64, bool) {
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(v.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return float64(v.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return v.Float(), true
+	case reflect.String:
+		return float64(utf8.RuneCountInString(v.String())), true
+	case reflect.Slice, reflect.Map, reflect.Array:
+		return float64(v.Len()), true
+	}
+	return 0, false
+}
+
+func compare(v reflect.Value, param string, ok func(a, b float64) bool) bool {
+	limit, err := strconv.ParseFloat(param, 64)
+	if err != nil {
+		return false
+	}
+	n, valid := size(v)
+	return valid && ok(n, limit)
+}
+
+func compareLen(v reflect.Value, param string) bool {
+	n, err := strconv.Atoi(param)
+	if err != nil {
+		return false
+	}
+	switch v.Kind() {
+	case reflect.String:
+		return utf8.RuneCountInString(v.String()) == n
+	case reflect.Slice, reflect.Map, reflect.Array:
+		return v.Len() == n
+	}
+	return false
+}
+
+func oneOf(v reflect.Value, param string) bool {
+	var s string
+	switch v.Kind() {
+	case reflect.String:
+		s = v.String()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		s = strconv.FormatInt(v.Int(), 10)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		s = strconv.FormatUint(v.Uint(), 10)
+	default:
+		return false
+	}
+	for _, c := range strings.Fields(param) {
+		if c == s {
+			return true
+		}
+	}
+	return false
+}
+
+func (v *Validator) matchRegexp(f reflect.Value, pattern string) bool {
+	if f.Kind() != reflect.String {
+		return false
+	}
+	re, ok := v.regexps.Load(pattern)
+	if !ok {
+		compiled, err := regexp.Compile(pattern)
+		if err != nil {
+			return false
+		}
+		re, _ = v.regexps.LoadOrStore(pattern, compiled)
+	}
+	return re.(*regexp.Regexp).MatchString(f.String())
+}
+
+// Default 默认校验器
+var Default = New()
+
+// Register 在默认校验器上注册规则
+func Register(name string, fn Func) error {
+	return Default.Register(name, fn)
+}
+
+// Struct 使用默认校验器校验结构体
+func Struct(s interface{}) error {
+	return Default.Struct(s)
+}
+
+// Var 使用默认校验器校验单个值
+func Var(value interface{}, tag string) error {
+	return Default.Var(value, tag)
+}